@@ -0,0 +1,322 @@
+// Package chaos provides transport decorators that inject faults into an ATP connection. They add latency, reorder
+// frames where the protocol allows it, truncate messages and drop the connection, which helps to harden both the SDK
+// and the engine against real-world pipe failures.
+package chaos
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/clock"
+)
+
+// ErrConnectionDropped is returned by reads and writes after a fault dropped the connection.
+var ErrConnectionDropped = errors.New("connection dropped by fault injection")
+
+// defaultReorderWindow is the time a held back frame waits for a following frame if Config.ReorderWindow is not set.
+const defaultReorderWindow = 10 * time.Millisecond
+
+// Config describes the faults to inject. The zero value injects no faults.
+type Config struct {
+	// Seed initializes the random source deciding when probabilistic faults occur.
+	Seed int64
+	// MinLatency is the minimum delay added to every read and written frame.
+	MinLatency time.Duration
+	// MaxLatency is the maximum delay added to every read and written frame. If it is lower than MinLatency, the
+	// delay is always MinLatency.
+	MaxLatency time.Duration
+	// ReorderProbability is the probability (0-1) that a written frame is held back and sent after the next frame.
+	// Frames are only reordered if both are runtime messages belonging to different runs, since the protocol
+	// requires the messages of a single run and the handshake to arrive in order.
+	ReorderProbability float64
+	// ReorderWindow is the time a held back frame waits for the next frame before it is sent anyway.
+	// Defaults to 10 milliseconds.
+	ReorderWindow time.Duration
+	// TruncateProbability is the probability (0-1) that only part of a written frame is sent, after which the
+	// connection is dropped.
+	TruncateProbability float64
+	// DropProbability is the probability (0-1) that the connection is dropped instead of writing a frame.
+	DropProbability float64
+	// DropAfterFrames drops the connection when the given number of frames has been written. Zero disables it.
+	DropAfterFrames int
+	// DropAfterBytes drops the connection when the given number of bytes has been read. Zero disables it.
+	DropAfterBytes int64
+	// Clock measures the latency and the reorder window. Defaults to the real clock.
+	Clock clock.Clock
+}
+
+// injector decides when faults occur. It is shared between the reading and writing side of a connection.
+type injector struct {
+	config Config
+	lock   sync.Mutex
+	rng    *rand.Rand
+	clock  clock.Clock
+}
+
+func newInjector(config Config) *injector {
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.Real()
+	}
+	return &injector{
+		config: config,
+		rng:    rand.New(rand.NewSource(config.Seed)), //nolint:gosec // Fault injection does not need a secure source.
+		clock:  clk,
+	}
+}
+
+// chance returns true with the given probability.
+func (i *injector) chance(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.rng.Float64() < probability
+}
+
+// intn returns a random number in [0, n).
+func (i *injector) intn(n int) int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.rng.Intn(n)
+}
+
+func (i *injector) delay() {
+	latency := i.config.MinLatency
+	if spread := i.config.MaxLatency - i.config.MinLatency; spread > 0 {
+		i.lock.Lock()
+		latency += time.Duration(i.rng.Int63n(int64(spread) + 1))
+		i.lock.Unlock()
+	}
+	if latency > 0 {
+		<-i.clock.NewTimer(latency).C()
+	}
+}
+
+func (i *injector) reorderWindow() time.Duration {
+	if i.config.ReorderWindow > 0 {
+		return i.config.ReorderWindow
+	}
+	return defaultReorderWindow
+}
+
+// dropper closes the underlying connection once, no matter how many sides observe the fault.
+type dropper struct {
+	closer  io.Closer
+	once    sync.Once
+	dropped bool
+	lock    sync.Mutex
+	err     error
+}
+
+func (d *dropper) drop() error {
+	d.once.Do(func() {
+		d.lock.Lock()
+		d.dropped = true
+		d.lock.Unlock()
+		d.err = d.closer.Close()
+	})
+	return d.err
+}
+
+func (d *dropper) isDropped() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.dropped
+}
+
+// NewWriter wraps the writing side of a connection, such as the standard output of a plugin, and injects the write
+// faults of the config. Each call to Write is treated as one frame, which matches how the CBOR encoder writes
+// messages.
+func NewWriter(w io.WriteCloser, config Config) io.WriteCloser {
+	return newWriter(w, newInjector(config), &dropper{closer: w})
+}
+
+// NewReader wraps the reading side of a connection, such as the standard input of a plugin, and injects the read
+// faults of the config.
+func NewReader(r io.ReadCloser, config Config) io.ReadCloser {
+	return newReader(r, newInjector(config), &dropper{closer: r})
+}
+
+// Wrap wraps both sides of an ATP client channel. A fault on either side drops the whole channel.
+func Wrap(channel atp.ClientChannel, config Config) atp.ClientChannel {
+	i := newInjector(config)
+	d := &dropper{closer: channel}
+	return &chaosChannel{
+		reader:  newReader(channel, i, d),
+		writer:  newWriter(channel, i, d),
+		dropper: d,
+	}
+}
+
+type chaosChannel struct {
+	reader  *reader
+	writer  *writer
+	dropper *dropper
+}
+
+func (c *chaosChannel) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (c *chaosChannel) Write(p []byte) (int, error) {
+	return c.writer.Write(p)
+}
+
+func (c *chaosChannel) Close() error {
+	c.writer.flush()
+	return c.dropper.drop()
+}
+
+type reader struct {
+	source    io.Reader
+	injector  *injector
+	dropper   *dropper
+	bytesRead int64
+}
+
+func newReader(source io.Reader, i *injector, d *dropper) *reader {
+	return &reader{
+		source:   source,
+		injector: i,
+		dropper:  d,
+	}
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.dropper.isDropped() {
+		return 0, ErrConnectionDropped
+	}
+	r.injector.delay()
+	if limit := r.injector.config.DropAfterBytes; limit > 0 {
+		remaining := limit - r.bytesRead
+		if remaining <= 0 {
+			_ = r.dropper.drop()
+			return 0, ErrConnectionDropped
+		}
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := r.source.Read(p)
+	r.bytesRead += int64(n)
+	return n, err
+}
+
+func (r *reader) Close() error {
+	return r.dropper.drop()
+}
+
+type writer struct {
+	target   io.Writer
+	injector *injector
+	dropper  *dropper
+
+	lock      sync.Mutex
+	frames    int
+	held      []byte
+	heldTimer clock.Timer
+}
+
+func newWriter(target io.Writer, i *injector, d *dropper) *writer {
+	return &writer{
+		target:   target,
+		injector: i,
+		dropper:  d,
+	}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.dropper.isDropped() {
+		return 0, ErrConnectionDropped
+	}
+	w.frames++
+	config := w.injector.config
+	if (config.DropAfterFrames > 0 && w.frames > config.DropAfterFrames) || w.injector.chance(config.DropProbability) {
+		w.held = nil
+		_ = w.dropper.drop()
+		return 0, ErrConnectionDropped
+	}
+	w.injector.delay()
+	// The caller may reuse the buffer, so frames that are not written immediately need their own copy.
+	frame := append([]byte(nil), p...)
+
+	if len(frame) > 1 && w.injector.chance(config.TruncateProbability) {
+		if err := w.flushHeld(); err != nil {
+			return 0, err
+		}
+		n, _ := w.target.Write(frame[:1+w.injector.intn(len(frame)-1)])
+		_ = w.dropper.drop()
+		return n, ErrConnectionDropped
+	}
+	if w.held != nil {
+		if canReorder(w.held, frame) {
+			if _, err := w.target.Write(frame); err != nil {
+				return 0, err
+			}
+			return len(p), w.flushHeld()
+		}
+		if err := w.flushHeld(); err != nil {
+			return 0, err
+		}
+	} else if w.injector.chance(config.ReorderProbability) && runID(frame) != "" {
+		w.held = frame
+		w.heldTimer = w.injector.clock.AfterFunc(w.injector.reorderWindow(), w.flush)
+		return len(p), nil
+	}
+	if _, err := w.target.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush sends the held back frame, if any.
+func (w *writer) flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_ = w.flushHeld()
+}
+
+func (w *writer) flushHeld() error {
+	if w.heldTimer != nil {
+		w.heldTimer.Stop()
+		w.heldTimer = nil
+	}
+	if w.held == nil || w.dropper.isDropped() {
+		w.held = nil
+		return nil
+	}
+	frame := w.held
+	w.held = nil
+	_, err := w.target.Write(frame)
+	return err
+}
+
+func (w *writer) Close() error {
+	w.flush()
+	return w.dropper.drop()
+}
+
+// canReorder returns true if the second frame may be delivered before the first one.
+func canReorder(first []byte, second []byte) bool {
+	firstRunID := runID(first)
+	secondRunID := runID(second)
+	return firstRunID != "" && secondRunID != "" && firstRunID != secondRunID
+}
+
+// runID returns the run ID of a frame containing a runtime message, or an empty string for any other frame.
+func runID(frame []byte) string {
+	var message atp.DecodedRuntimeMessage
+	if err := cbor.Unmarshal(frame, &message); err != nil {
+		return ""
+	}
+	return message.RunID
+}
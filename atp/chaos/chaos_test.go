@@ -0,0 +1,284 @@
+package chaos_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.arcalot.io/assert"
+	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/atp/chaos"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// recorder is a WriteCloser that keeps every frame written to it.
+type recorder struct {
+	lock   sync.Mutex
+	frames [][]byte
+	closed bool
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.frames = append(r.frames, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (r *recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *recorder) runIDs(t *testing.T) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := make([]string, len(r.frames))
+	for i, frame := range r.frames {
+		var message atp.DecodedRuntimeMessage
+		assert.NoError(t, cbor.Unmarshal(frame, &message))
+		result[i] = message.RunID
+	}
+	return result
+}
+
+func runtimeFrame(t *testing.T, runID string) []byte {
+	frame, err := cbor.Marshal(atp.RuntimeMessage{
+		MessageID:   atp.MessageTypeSignal,
+		RunID:       runID,
+		MessageData: "test",
+	})
+	assert.NoError(t, err)
+	return frame
+}
+
+func TestWriterNoFaults(t *testing.T) {
+	target := &recorder{}
+	w := chaos.NewWriter(target, chaos.Config{})
+	for _, runID := range []string{"a", "b", "c"} {
+		_, err := w.Write(runtimeFrame(t, runID))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	assert.Equals(t, target.runIDs(t), []string{"a", "b", "c"})
+	assert.Equals(t, target.closed, true)
+}
+
+func TestWriterReorder(t *testing.T) {
+	target := &recorder{}
+	w := chaos.NewWriter(target, chaos.Config{ReorderProbability: 1, ReorderWindow: time.Hour})
+	for _, runID := range []string{"a", "b", "c", "c", "d"} {
+		_, err := w.Write(runtimeFrame(t, runID))
+		assert.NoError(t, err)
+	}
+	// Frames of different runs are swapped, frames of the same run keep their order.
+	assert.Equals(t, target.runIDs(t), []string{"b", "a", "c", "c"})
+	// Closing sends the held back frame.
+	assert.NoError(t, w.Close())
+	assert.Equals(t, target.runIDs(t), []string{"b", "a", "c", "c", "d"})
+}
+
+func TestWriterReorderWindow(t *testing.T) {
+	target := &recorder{}
+	w := chaos.NewWriter(target, chaos.Config{ReorderProbability: 1, ReorderWindow: time.Millisecond})
+	_, err := w.Write(runtimeFrame(t, "a"))
+	assert.NoError(t, err)
+	// A held back frame is sent when no other frame follows within the window.
+	deadline := time.Now().Add(time.Second)
+	for len(target.runIDs(t)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equals(t, target.runIDs(t), []string{"a"})
+}
+
+func TestWriterNoReorderOutsideRuntimeMessages(t *testing.T) {
+	target := &recorder{}
+	w := chaos.NewWriter(target, chaos.Config{ReorderProbability: 1, ReorderWindow: time.Hour})
+	hello, err := cbor.Marshal(atp.HelloMessage{Version: atp.ProtocolVersion})
+	assert.NoError(t, err)
+	_, err = w.Write(hello)
+	assert.NoError(t, err)
+	assert.Equals(t, len(target.frames), 1)
+	assert.Equals(t, target.frames[0], hello)
+}
+
+func TestWriterTruncate(t *testing.T) {
+	target := &recorder{}
+	w := chaos.NewWriter(target, chaos.Config{TruncateProbability: 1})
+	frame := runtimeFrame(t, "a")
+	n, err := w.Write(frame)
+	assert.Equals(t, errors.Is(err, chaos.ErrConnectionDropped), true)
+	assert.Equals(t, n > 0 && n < len(frame), true)
+	assert.Equals(t, target.frames[0], frame[:n])
+	assert.Equals(t, target.closed, true)
+
+	_, err = w.Write(frame)
+	assert.Equals(t, errors.Is(err, chaos.ErrConnectionDropped), true)
+	assert.Equals(t, len(target.frames), 1)
+}
+
+func TestWriterDropAfterFrames(t *testing.T) {
+	target := &recorder{}
+	w := chaos.NewWriter(target, chaos.Config{DropAfterFrames: 2})
+	for i := 0; i < 2; i++ {
+		_, err := w.Write(runtimeFrame(t, "a"))
+		assert.NoError(t, err)
+	}
+	_, err := w.Write(runtimeFrame(t, "a"))
+	assert.Equals(t, errors.Is(err, chaos.ErrConnectionDropped), true)
+	assert.Equals(t, len(target.frames), 2)
+	assert.Equals(t, target.closed, true)
+}
+
+func TestWriterLatency(t *testing.T) {
+	w := chaos.NewWriter(&recorder{}, chaos.Config{MinLatency: 10 * time.Millisecond, MaxLatency: 20 * time.Millisecond})
+	start := time.Now()
+	_, err := w.Write(runtimeFrame(t, "a"))
+	assert.NoError(t, err)
+	assert.Equals(t, time.Since(start) >= 10*time.Millisecond, true)
+}
+
+func TestReaderDropAfterBytes(t *testing.T) {
+	r := chaos.NewReader(io.NopCloser(bytes.NewReader([]byte("0123456789"))), chaos.Config{DropAfterBytes: 4})
+	data, err := io.ReadAll(r)
+	assert.Equals(t, errors.Is(err, chaos.ErrConnectionDropped), true)
+	assert.Equals(t, string(data), "0123")
+}
+
+type testInput struct {
+	Name string `json:"name"`
+}
+
+type testOutput struct {
+	Message string `json:"message"`
+}
+
+var testSchema = schema.NewCallableSchema(
+	schema.NewCallableStep[testInput](
+		"hello",
+		schema.NewScopeSchema(
+			schema.NewStructMappedObjectSchema[testInput](
+				"Input",
+				map[string]*schema.PropertySchema{
+					"name": schema.NewPropertySchema(
+						schema.NewStringSchema(nil, nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+		),
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(
+					schema.NewStructMappedObjectSchema[testOutput](
+						"Output",
+						map[string]*schema.PropertySchema{
+							"message": schema.NewPropertySchema(
+								schema.NewStringSchema(nil, nil, nil),
+								nil,
+								true,
+								nil,
+								nil,
+								nil,
+								nil,
+								nil,
+							),
+						},
+					),
+				),
+				nil,
+				false,
+			),
+		},
+		nil,
+		func(_ context.Context, input testInput) (string, any) {
+			return "success", testOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+		},
+	),
+)
+
+type channel struct {
+	io.Reader
+	io.Writer
+	cancel func()
+}
+
+func (c channel) Close() error {
+	c.cancel()
+	return nil
+}
+
+func TestEndToEndLatencyAndReorder(t *testing.T) {
+	config := chaos.Config{
+		Seed:               1,
+		MaxLatency:         time.Millisecond,
+		ReorderProbability: 0.5,
+		ReorderWindow:      5 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs := atp.RunATPServer(ctx, chaos.NewReader(stdinReader, config), chaos.NewWriter(stdoutWriter, config), testSchema)
+		assert.Equals(t, len(errs), 0)
+	}()
+	cli := atp.NewClientWithLogger(
+		chaos.Wrap(channel{stdoutReader, stdinWriter, cancel}, config),
+		log.NewTestLogger(t),
+	)
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+
+	stepWg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		stepWg.Add(1)
+		go func(i int) {
+			defer stepWg.Done()
+			name := fmt.Sprintf("test-%d", i)
+			result := cli.Execute(schema.Input{RunID: name, ID: "hello", InputData: map[string]any{"name": name}}, nil, nil)
+			assert.NoError(t, result.Error)
+			assert.Equals(t, result.OutputData.(map[any]any)["message"].(string), fmt.Sprintf("Hello, %s!", name))
+		}(i)
+	}
+	stepWg.Wait()
+	assert.NoError(t, cli.Close())
+	wg.Wait()
+}
+
+func TestEndToEndDrop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// The server can send its hello message, but the connection drops before the result is sent.
+		atp.RunATPServer(ctx, stdinReader, chaos.NewWriter(stdoutWriter, chaos.Config{DropAfterFrames: 1}), testSchema)
+	}()
+	cli := atp.NewClientWithLogger(channel{stdoutReader, stdinWriter, cancel}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(schema.Input{RunID: "test", ID: "hello", InputData: map[string]any{"name": "test"}}, nil, nil)
+	assert.Error(t, result.Error)
+	_ = cli.Close()
+	wg.Wait()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/fxamacker/cbor/v2"
 	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/clock"
 	"go.flow.arcalot.io/pluginsdk/schema"
 	"io"
 	"strings"
@@ -25,10 +26,18 @@ type ExecutionResult struct {
 	OutputID   string
 	OutputData any
 	Error      error
+	// DebugLogs holds the debug output the step reported with its result.
+	DebugLogs string
+	// Truncations lists the values of the output data that were truncated, if the output has a truncation policy.
+	Truncations []schema.Truncation
+	// Summaries holds the summaries of the large lists of the output data, if they were requested.
+	Summaries []schema.ListSummary
+	// Metrics holds the timings and payload sizes of the step execution measured by the server, if it sent them.
+	Metrics *schema.StepMetrics
 }
 
 func NewErrorExecutionResult(err error) ExecutionResult {
-	return ExecutionResult{"", nil, err}
+	return ExecutionResult{"", nil, err, "", nil, nil, nil}
 }
 
 // Client is the way to read information from the ATP server and then send a task to it in the form of a step.
@@ -64,6 +73,69 @@ func NewClientWithLogger(
 	channel ClientChannel,
 	logger log.Logger,
 ) Client {
+	return newClient(channel, logger)
+}
+
+// DefaultResumeTimeout is how long the running executions of a resumable client wait for the session to be resumed
+// after the connection failed, unless configured otherwise.
+const DefaultResumeTimeout = 5 * time.Minute
+
+// ackInterval is the number of runtime messages after which a resumable client acknowledges their receipt.
+const ackInterval = 16
+
+// ResumableClient is a client that asks the server to keep its session resumable. If the connection fails, the
+// running executions keep waiting for their results until the session is resumed over a new connection. They fail if
+// the client is closed, or the session is not resumed within the resume timeout.
+type ResumableClient interface {
+	Client
+	// SessionToken returns the token of the session, or an empty string if the server doesn't support resuming
+	// sessions. It is available after ReadSchema.
+	SessionToken() string
+	// Resume continues the session over a new connection after the previous connection failed. The previous
+	// connection is closed. The server sends the messages the client missed, and the running executions receive
+	// their results as usual. If the server no longer knows the session, the running executions fail and an error is
+	// returned.
+	Resume(channel ClientChannel) error
+}
+
+// NewResumableClientWithLogger creates a new ATP client with a logger that keeps its session resumable if the server
+// supports it.
+func NewResumableClientWithLogger(
+	channel ClientChannel,
+	logger log.Logger,
+) ResumableClient {
+	return NewResumableClientWithTimeout(channel, logger, DefaultResumeTimeout)
+}
+
+// NewResumableClientWithTimeout creates a new ATP client like NewResumableClientWithLogger, with a custom time the
+// running executions wait for the session to be resumed after the connection failed.
+func NewResumableClientWithTimeout(
+	channel ClientChannel,
+	logger log.Logger,
+	resumeTimeout time.Duration,
+) ResumableClient {
+	return NewResumableClientWithClock(channel, logger, resumeTimeout, clock.Real())
+}
+
+// NewResumableClientWithClock creates a new ATP client like NewResumableClientWithTimeout, which measures the resume
+// timeout on the given clock. Tests can pass a clock.Fake to expire sessions without waiting.
+func NewResumableClientWithClock(
+	channel ClientChannel,
+	logger log.Logger,
+	resumeTimeout time.Duration,
+	clk clock.Clock,
+) ResumableClient {
+	c := newClient(channel, logger)
+	c.resumable = true
+	c.resumeTimeout = resumeTimeout
+	c.clock = clk
+	return c
+}
+
+func newClient(
+	channel ClientChannel,
+	logger log.Logger,
+) *client {
 	decMode, err := cbor.DecOptions{
 		ExtraReturnErrors: cbor.ExtraDecErrorUnknownField,
 	}.DecMode()
@@ -90,6 +162,20 @@ func NewClientWithLogger(
 		ctx,
 		cancel,
 		sync.WaitGroup{},
+		false,
+		"",
+		sequenceTracker{},
+		0,
+		0,
+		nil,
+		false,
+		0,
+		0,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.Real(),
 	}
 }
 
@@ -121,7 +207,23 @@ type client struct {
 	done                             bool
 	context                          context.Context
 	cancelFunc                       context.CancelFunc
-	wg                               sync.WaitGroup // For the read loop.
+	wg                               sync.WaitGroup   // For the read loop.
+	resumable                        bool             // Whether to ask the server for a resumable session.
+	sessionToken                     string           // Empty if the session is not resumable.
+	received                         sequenceTracker  // Sequence numbers of the received runtime messages.
+	unacknowledged                   int              // Number of messages received since the last ack.
+	lastSent                         uint64           // Sequence number of the last sent runtime message.
+	unconfirmed                      []RuntimeMessage // Sent runtime messages the server hasn't acknowledged yet.
+	disconnected                     bool             // Whether the connection of a resumable session failed.
+	disconnections                   uint64           // Number of times the connection failed.
+	resumeTimeout                    time.Duration    // How long to wait for the session to be resumed.
+	resumeTimer                      clock.Timer      // Fails the running executions if the session isn't resumed.
+	readLoopDone                     chan struct{}    // Closed when the current read loop ends.
+	// engine holds the capabilities announced to the server in the start message, if any.
+	engine *schema.EngineCapabilities
+	// summaries holds the summary options requested from the server in the start message, if any.
+	summaries *schema.SummaryOptions
+	clock     clock.Clock // Measures the resume timeout and the wait for the read loop.
 }
 
 func (c *client) sendCBOR(message any) error {
@@ -130,10 +232,84 @@ func (c *client) sendCBOR(message any) error {
 	return c.encoder.Encode(message)
 }
 
+// sendRuntimeMessage sends a work start or signal message. In a resumable session the message is numbered and kept
+// until the server acknowledges it, so it is sent again after the session is resumed if the connection fails.
+func (c *client) sendRuntimeMessage(message RuntimeMessage) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.sessionToken == "" {
+		return c.encoder.Encode(message)
+	}
+	c.lastSent++
+	message.Sequence = c.lastSent
+	c.unconfirmed = append(c.unconfirmed, message)
+	if err := c.encoder.Encode(message); err != nil {
+		c.logger.Warningf(
+			"Failed to send runtime message %d, sending it again after resuming the session: %v",
+			message.Sequence,
+			err,
+		)
+	}
+	return nil
+}
+
+// CompactSchemaReader is implemented by the clients of this package. Engines that only validate data against the
+// plugin schema can read it with ReadCompactSchema instead of ReadSchema to receive a much smaller schema.
+type CompactSchemaReader interface {
+	// ReadCompactSchema reads the schema from the ATP server like ReadSchema, but asks the server to leave out the
+	// display values and examples. Servers that don't support it send the full schema.
+	ReadCompactSchema() (*schema.SchemaSchema, error)
+}
+
+// EngineAnnouncer is implemented by the clients of this package. Engines announce their capabilities before reading
+// the schema, so the server leaves out the steps and properties they don't meet the EngineRequirements of, and
+// refuses to run them instead of failing in the middle of a workflow.
+type EngineAnnouncer interface {
+	// AnnounceEngine sets the capabilities sent to the server when reading the schema and resuming the session.
+	AnnounceEngine(capabilities schema.EngineCapabilities)
+}
+
+func (c *client) AnnounceEngine(capabilities schema.EngineCapabilities) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.engine = &capabilities
+}
+
+// OutputSummaryRequester is implemented by the clients of this package. Engines request summaries before reading the
+// schema, so the server sends a summary of each large list in the step outputs, and UIs can show previews of outputs
+// without reading all of them.
+type OutputSummaryRequester interface {
+	// RequestOutputSummaries sets the summary options sent to the server when reading the schema and resuming the
+	// session. The summaries are returned in ExecutionResult.Summaries.
+	RequestOutputSummaries(options schema.SummaryOptions)
+}
+
+func (c *client) RequestOutputSummaries(options schema.SummaryOptions) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.summaries = &options
+}
+
 func (c *client) ReadSchema() (*schema.SchemaSchema, error) {
+	return c.readSchema(false)
+}
+
+func (c *client) ReadCompactSchema() (*schema.SchemaSchema, error) {
+	return c.readSchema(true)
+}
+
+func (c *client) readSchema(compact bool) (*schema.SchemaSchema, error) {
 	c.logger.Debugf("Reading plugin schema...")
 
-	if err := c.sendCBOR(nil); err != nil {
+	c.mutex.Lock()
+	engine := c.engine
+	summaries := c.summaries
+	c.mutex.Unlock()
+	var start any
+	if c.resumable || compact || engine != nil || summaries != nil {
+		start = StartMessage{Resumable: c.resumable, CompactSchema: compact, Engine: engine, Summaries: summaries}
+	}
+	if err := c.sendCBOR(start); err != nil {
 		c.logger.Errorf("Failed to encode ATP start output message: %v", err)
 		return nil, fmt.Errorf("failed to encode start output message (%w)", err)
 	}
@@ -153,6 +329,9 @@ func (c *client) ReadSchema() (*schema.SchemaSchema, error) {
 		return nil, err
 	}
 	c.atpVersion = hello.Version
+	c.mutex.Lock()
+	c.sessionToken = hello.SessionToken
+	c.mutex.Unlock()
 
 	unserializedSchema, err := schema.UnserializeSchema(hello.Schema)
 	if err != nil {
@@ -178,14 +357,22 @@ func (c *client) Execute(
 	signalsToStep <-chan schema.Input,
 	signalsFromStep chan<- schema.Input,
 ) ExecutionResult {
-	c.logger.Debugf("Executing plugin step %s/%s...", stepData.RunID, stepData.ID)
+	if len(stepData.Labels) > 0 {
+		c.logger.Debugf("Executing plugin step %s/%s with labels %v...", stepData.RunID, stepData.ID, stepData.Labels)
+	} else {
+		c.logger.Debugf("Executing plugin step %s/%s...", stepData.RunID, stepData.ID)
+	}
 	if len(stepData.RunID) == 0 {
 		return NewErrorExecutionResult(fmt.Errorf("run ID is blank for step %s", stepData.ID))
 	}
+	if err := schema.ValidateLabels(stepData.Labels); err != nil {
+		return NewErrorExecutionResult(fmt.Errorf("invalid labels for step %s (%w)", stepData.RunID, err))
+	}
 	var workStartMsg any
 	workStartMsg = WorkStartMessage{
 		StepID: stepData.ID,
 		Config: stepData.InputData,
+		Labels: stepData.Labels,
 	}
 	cborReader := c.decMode.NewDecoder(c.rawAtpChannels)
 	if c.atpVersion > 1 {
@@ -205,7 +392,13 @@ func (c *client) Execute(
 			return NewErrorExecutionResult(err)
 		}
 	}
-	if err := c.sendCBOR(workStartMsg); err != nil {
+	var err error
+	if runtimeMessage, ok := workStartMsg.(RuntimeMessage); ok {
+		err = c.sendRuntimeMessage(runtimeMessage)
+	} else {
+		err = c.sendCBOR(workStartMsg)
+	}
+	if err != nil {
 		c.logger.Errorf("Step '%s' failed to write start work message: %v", stepData.ID, err)
 		return NewErrorExecutionResult(fmt.Errorf("failed to write work start message (%w)", err))
 	}
@@ -223,19 +416,26 @@ func (c *client) Close() error {
 		return nil
 	}
 	c.done = true
+	disconnected := c.disconnected
+	if c.resumeTimer != nil {
+		c.resumeTimer.Stop()
+	}
 	c.mutex.Unlock()
-	// Now tell the server we're done.
+	if disconnected {
+		// Nobody can deliver the results of the running executions anymore.
+		c.sendErrorToAll(fmt.Errorf("client closed while its session was disconnected"))
+	}
+	// Now tell the server we're done, unless the connection already failed.
 	// Send the client done message
-	if c.atpVersion > 1 {
+	if c.atpVersion > 1 && !disconnected {
 		err := c.sendCBOR(RuntimeMessage{
-			MessageTypeClientDone,
-			"",
-			clientDoneMessage{},
+			MessageID:   MessageTypeClientDone,
+			MessageData: clientDoneMessage{},
 		})
 		if err != nil {
 			// add a timeout to the wait to prevent it from causing a deadlock.
 			// 5 seconds is arbitrary, but gives it enough time to exit.
-			waitedGracefully := waitWithTimeout(time.Second*5, &c.wg)
+			waitedGracefully := waitWithTimeout(c.clock, time.Second*5, &c.wg)
 			if waitedGracefully {
 				return fmt.Errorf("client with step '%s' failed to write client done message with error: %w",
 					c.getRunningStepIDs(), err)
@@ -250,20 +450,22 @@ func (c *client) Close() error {
 }
 
 // waitWithTimeout waits for the provided wait group, aborting the wait if
-// the provided timeout expires.
+// the provided timeout expires on the clock.
 // Returns true if the WaitGroup finished, and false if
 // it reached the end of the timeout.
-func waitWithTimeout(duration time.Duration, wg *sync.WaitGroup) bool {
+func waitWithTimeout(clk clock.Clock, duration time.Duration, wg *sync.WaitGroup) bool {
 	// Run a goroutine to do the waiting
 	doneChannel := make(chan bool, 1)
 	go func() {
 		defer close(doneChannel)
 		wg.Wait()
 	}()
+	timeout := clk.NewTimer(duration)
+	defer timeout.Stop()
 	select {
 	case <-doneChannel:
 		return true
-	case <-time.After(duration):
+	case <-timeout.C():
 		return false
 	}
 }
@@ -315,10 +517,10 @@ func (c *client) executeWriteLoop(
 			c.logger.Errorf("Invalid run ID (%s) or signal ID (%s)", signal.ID, signal.RunID)
 			return
 		}
-		if err := c.sendCBOR(RuntimeMessage{
-			MessageTypeSignal,
-			signal.RunID,
-			SignalMessage{
+		if err := c.sendRuntimeMessage(RuntimeMessage{
+			MessageID: MessageTypeSignal,
+			RunID:     signal.RunID,
+			MessageData: SignalMessage{
 				SignalID: signal.ID,
 				Data:     signal.InputData,
 			}}); err != nil {
@@ -428,6 +630,62 @@ func (c *client) handleErrorMessage(runtimeMessage DecodedRuntimeMessage) bool {
 	return false
 }
 
+// handleAckMessage drops the sent runtime messages the server confirmed receiving.
+func (c *client) handleAckMessage(runtimeMessage DecodedRuntimeMessage) {
+	var ackMessage AckMessage
+	if err := cbor.Unmarshal(runtimeMessage.RawMessageData, &ackMessage); err != nil {
+		c.logger.Errorf("Failed to decode acknowledgement message: %v", err)
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.confirm(ackMessage.LastReceived)
+}
+
+// handleUnsupportedMessage logs that the server didn't understand a message the client sent. The server ignored the
+// message, so the session continues.
+func (c *client) handleUnsupportedMessage(runtimeMessage DecodedRuntimeMessage) {
+	var unsupportedMessage UnsupportedMessage
+	if err := cbor.Unmarshal(runtimeMessage.RawMessageData, &unsupportedMessage); err != nil {
+		c.logger.Errorf("Failed to decode unsupported message reply: %v", err)
+		return
+	}
+	c.logger.Warningf(
+		"Server does not support message type %d sent for run ID '%s', it supports %v",
+		unsupportedMessage.MessageID,
+		runtimeMessage.RunID,
+		unsupportedMessage.Supported,
+	)
+}
+
+// replyUnsupported tells the server that the client doesn't know the type of the message, instead of failing the
+// session, since the server may implement a newer protocol version.
+func (c *client) replyUnsupported(runtimeMessage DecodedRuntimeMessage) {
+	c.logger.Warningf(
+		"Step with run ID '%s' sent unknown message type: %d",
+		runtimeMessage.RunID,
+		runtimeMessage.MessageID,
+	)
+	err := c.sendCBOR(RuntimeMessage{
+		MessageID: MessageTypeUnsupported,
+		RunID:     runtimeMessage.RunID,
+		MessageData: UnsupportedMessage{
+			MessageID: runtimeMessage.MessageID,
+			Supported: clientMessageTypes,
+		},
+	})
+	if err != nil {
+		c.logger.Warningf("Failed to send unsupported message reply: %v", err)
+	}
+}
+
+// confirm drops the sent runtime messages up to the sequence number. The caller must hold the mutex.
+func (c *client) confirm(lastReceived uint64) {
+	for len(c.unconfirmed) > 0 && c.unconfirmed[0].Sequence <= lastReceived {
+		c.unconfirmed = c.unconfirmed[1:]
+	}
+}
+
 func (c *client) hasEntriesRemaining() bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -450,35 +708,27 @@ func (c *client) executeReadLoop(cborReader *cbor.Decoder) {
 		c.wg.Done()
 	}()
 	// Loop and get all messages
-	// The message is generic, so we must find the type and decode the full message next.
-	var runtimeMessage DecodedRuntimeMessage
 	for {
-		if err := cborReader.Decode(&runtimeMessage); err != nil {
-			c.logger.Errorf(
-				"ATP client for steps '%s' failed to read or decode runtime message: %v",
-				c.getRunningStepIDs(),
-				err,
-			)
-			// This is fatal since the entire structure of the runtime message is invalid.
-			c.sendErrorToAll(fmt.Errorf("failed to read or decode runtime message (%w)", err))
+		// The message is generic, so we must find the type and decode the full message next.
+		// The message is reset before decoding, since fields missing in the message would keep their old value.
+		runtimeMessage := getDecodedRuntimeMessage()
+		if err := cborReader.Decode(runtimeMessage); err != nil {
+			c.handleReadError(err)
 			return
 		}
-		switch runtimeMessage.MessageID {
-		case MessageTypeWorkDone:
-			c.handleWorkDoneMessage(runtimeMessage)
-		case MessageTypeSignal:
-			c.handleSignalMessage(runtimeMessage)
-		case MessageTypeError:
-			if c.handleErrorMessage(runtimeMessage) {
-				return // Fatal
-			}
-		default:
-			c.logger.Warningf(
-				"Step with run ID '%s' sent unknown message type: %d",
-				runtimeMessage.RunID,
-				runtimeMessage.MessageID,
-			)
+		if c.isDuplicate(runtimeMessage.Sequence) {
+			// The server sent the message again after resuming the session.
+			putDecodedRuntimeMessage(runtimeMessage)
+			continue
+		}
+		c.acknowledge(
+			runtimeMessage.Sequence,
+			runtimeMessage.MessageID == MessageTypeWorkDone || runtimeMessage.MessageID == MessageTypeError,
+		)
+		if c.dispatchRuntimeMessage(*runtimeMessage) {
+			return // Fatal
 		}
+		putDecodedRuntimeMessage(runtimeMessage)
 		// The non-error exit condition is having no more entries remaining.
 		if !c.hasEntriesRemaining() {
 			return
@@ -486,6 +736,46 @@ func (c *client) executeReadLoop(cborReader *cbor.Decoder) {
 	}
 }
 
+// handleReadError handles a runtime message that could not be read. If the session can be resumed, the steps wait
+// for it, otherwise the error is sent to all of them.
+func (c *client) handleReadError(err error) {
+	if c.disconnect() {
+		c.logger.Warningf(
+			"ATP client for steps '%s' lost its connection, waiting for the session to be resumed: %v",
+			c.getRunningStepIDs(),
+			err,
+		)
+		return
+	}
+	c.logger.Errorf(
+		"ATP client for steps '%s' failed to read or decode runtime message: %v",
+		c.getRunningStepIDs(),
+		err,
+	)
+	// This is fatal since the entire structure of the runtime message is invalid.
+	c.sendErrorToAll(fmt.Errorf("failed to read or decode runtime message (%w)", err))
+}
+
+// dispatchRuntimeMessage passes the runtime message to the handler of its type. It returns true if the message was a
+// fatal error, after which the read loop stops.
+func (c *client) dispatchRuntimeMessage(runtimeMessage DecodedRuntimeMessage) bool {
+	switch runtimeMessage.MessageID {
+	case MessageTypeWorkDone:
+		c.handleWorkDoneMessage(runtimeMessage)
+	case MessageTypeSignal:
+		c.handleSignalMessage(runtimeMessage)
+	case MessageTypeError:
+		return c.handleErrorMessage(runtimeMessage)
+	case MessageTypeAck:
+		c.handleAckMessage(runtimeMessage)
+	case MessageTypeUnsupported:
+		c.handleUnsupportedMessage(runtimeMessage)
+	default:
+		c.replyUnsupported(runtimeMessage)
+	}
+	return false
+}
+
 // executeStep handles the reading of work done, signals, or any other outputs from the plugins.
 // It branches off with different logic for ATP versions 1 and 2.
 func (c *client) getResult(
@@ -536,16 +826,24 @@ func (c *client) prepareResultChannels(
 	}
 	// Run the loop if it isn't running.
 	if !c.readLoopRunning {
-		// Only a single read loop should be running
-		c.wg.Add(1) // Add here, so that it's before the goroutine to prevent race conditions.
-		c.readLoopRunning = true
-		go func() {
-			c.executeReadLoop(cborReader)
-		}()
+		c.startReadLoop(cborReader)
 	}
 	return nil
 }
 
+// startReadLoop starts the read loop. The caller must hold the mutex.
+func (c *client) startReadLoop(cborReader *cbor.Decoder) {
+	// Only a single read loop should be running
+	c.wg.Add(1) // Add here, so that it's before the goroutine to prevent race conditions.
+	c.readLoopRunning = true
+	readLoopDone := make(chan struct{})
+	c.readLoopDone = readLoopDone
+	go func() {
+		defer close(readLoopDone)
+		c.executeReadLoop(cborReader)
+	}()
+}
+
 // getResultV2 communicates with the RuntimeMessage loop to get the ExecutionResult.
 func (c *client) getResultV2(stepData schema.Input) ExecutionResult {
 	c.mutex.Lock()
@@ -586,5 +884,196 @@ func (c *client) processWorkDone(
 		}
 	}
 
-	return ExecutionResult{doneMessage.OutputID, doneMessage.OutputData, nil}
+	for _, truncation := range doneMessage.Truncations {
+		c.logger.Debugf(
+			"Step '%s' output truncated at '%s' from %d.", runID, truncation.Path, truncation.OriginalSize,
+		)
+	}
+
+	return ExecutionResult{
+		doneMessage.OutputID,
+		doneMessage.OutputData,
+		nil,
+		doneMessage.DebugLogs,
+		doneMessage.Truncations,
+		doneMessage.Summaries,
+		doneMessage.Metrics,
+	}
+}
+
+func (c *client) SessionToken() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.sessionToken
+}
+
+// disconnect marks a resumable session as disconnected after its connection failed. It returns false if the session
+// is not resumable or the client is closed, in which case the running executions must fail.
+func (c *client) disconnect() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.sessionToken == "" || c.done {
+		return false
+	}
+	c.disconnected = true
+	c.disconnections++
+	disconnection := c.disconnections
+	if c.resumeTimer != nil {
+		c.resumeTimer.Stop()
+	}
+	c.resumeTimer = c.clock.AfterFunc(c.resumeTimeout, func() {
+		c.resumeTimedOut(disconnection)
+	})
+	return true
+}
+
+// resumeTimedOut fails the running executions if the session is still disconnected since the given disconnection.
+func (c *client) resumeTimedOut(disconnection uint64) {
+	c.mutex.Lock()
+	timedOut := c.disconnected && c.disconnections == disconnection && !c.done
+	c.mutex.Unlock()
+	if timedOut {
+		c.logger.Errorf("ATP session was not resumed within %s", c.resumeTimeout)
+		c.sendErrorToAll(fmt.Errorf("the session was not resumed within %s", c.resumeTimeout))
+	}
+}
+
+// isDuplicate records the sequence number of a received runtime message, and returns true if the message was
+// already received.
+func (c *client) isDuplicate(sequence uint64) bool {
+	if sequence == 0 {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.received.record(sequence)
+}
+
+// acknowledge tells the server which messages it no longer needs to keep for resending. Critical messages, such as
+// outputs, are acknowledged right away, all others once every ackInterval messages.
+func (c *client) acknowledge(sequence uint64, critical bool) {
+	if sequence == 0 {
+		return
+	}
+	c.mutex.Lock()
+	c.unacknowledged++
+	if c.unacknowledged < ackInterval && !critical {
+		c.mutex.Unlock()
+		return
+	}
+	c.unacknowledged = 0
+	lastReceived := c.received.last
+	c.mutex.Unlock()
+	if err := c.sendCBOR(RuntimeMessage{
+		MessageID:   MessageTypeAck,
+		MessageData: AckMessage{LastReceived: lastReceived},
+	}); err != nil {
+		c.logger.Warningf("Failed to acknowledge the runtime messages up to %d: %v", lastReceived, err)
+	}
+}
+
+func (c *client) Resume(channel ClientChannel) error {
+	if err := c.closePreviousConnection(); err != nil {
+		return err
+	}
+	hello, err := c.resumeHandshake(channel)
+	if err != nil {
+		return err
+	}
+	if err := c.replayUnconfirmed(hello); err != nil {
+		return err
+	}
+	c.logger.Debugf("Session resumed.")
+	return nil
+}
+
+// closePreviousConnection closes the connection of a resumable session, and waits for its read loop to end, so that
+// the session can continue on a new connection.
+func (c *client) closePreviousConnection() error {
+	c.mutex.Lock()
+	if c.done {
+		c.mutex.Unlock()
+		return fmt.Errorf("cannot resume a closed client")
+	}
+	if c.sessionToken == "" {
+		c.mutex.Unlock()
+		return fmt.Errorf("the session is not resumable")
+	}
+	previousChannel := c.rawAtpChannels
+	var readLoopDone <-chan struct{}
+	if c.readLoopRunning {
+		readLoopDone = c.readLoopDone
+	}
+	c.mutex.Unlock()
+
+	// Make sure the read loop of the previous connection has ended before reading from the new one.
+	_ = previousChannel.Close()
+	if readLoopDone != nil {
+		<-readLoopDone
+	}
+	return nil
+}
+
+// resumeHandshake switches the client to the new connection, and asks the server to resume the session.
+func (c *client) resumeHandshake(channel ClientChannel) (HelloMessage, error) {
+	c.mutex.Lock()
+	c.rawAtpChannels = channel
+	c.decoder = c.decMode.NewDecoder(channel)
+	c.encoder = cbor.NewEncoder(channel)
+	start := StartMessage{
+		Resumable:    true,
+		SessionToken: c.sessionToken,
+		LastReceived: c.received.last,
+		// The schema in the hello message is not used when resuming.
+		CompactSchema: true,
+		Engine:        c.engine,
+		Summaries:     c.summaries,
+	}
+	c.mutex.Unlock()
+
+	c.logger.Debugf("Resuming session after message %d...", start.LastReceived)
+	var hello HelloMessage
+	if err := c.sendCBOR(start); err != nil {
+		return hello, fmt.Errorf("failed to encode start message for resuming the session (%w)", err)
+	}
+	if err := c.decoder.Decode(&hello); err != nil {
+		return hello, fmt.Errorf("failed to decode hello message while resuming the session (%w)", err)
+	}
+	return hello, nil
+}
+
+// replayUnconfirmed continues the session after the server answered the resume request. It restarts the read loop,
+// and sends the runtime messages the server missed again. If the server could not resume the session, the running
+// executions fail.
+func (c *client) replayUnconfirmed(hello HelloMessage) error {
+	c.mutex.Lock()
+	c.sessionToken = hello.SessionToken
+	c.disconnected = false
+	if c.resumeTimer != nil {
+		c.resumeTimer.Stop()
+	}
+	c.unacknowledged = 0
+	if !hello.Resumed {
+		c.received = sequenceTracker{}
+		c.lastSent = 0
+		c.unconfirmed = nil
+		c.mutex.Unlock()
+		err := fmt.Errorf("the server could not resume the session, the results of the running steps are lost")
+		c.sendErrorToAll(err)
+		return err
+	}
+	// Restart the read loop for the executions that are still waiting for their results. It uses the decoder of the
+	// hello message, since that may already hold the messages the server sent again.
+	if !c.readLoopRunning && len(c.runningStepResultEntries) > 0 {
+		c.startReadLoop(c.decoder)
+	}
+	c.confirm(hello.LastReceived)
+	for _, message := range c.unconfirmed {
+		if err := c.encoder.Encode(message); err != nil {
+			c.mutex.Unlock()
+			return fmt.Errorf("failed to send runtime message %d again after resuming the session (%w)", message.Sequence, err)
+		}
+	}
+	c.mutex.Unlock()
+	return nil
 }
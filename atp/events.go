@@ -0,0 +1,94 @@
+package atp
+
+import (
+	"sync"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// StepEventType identifies a stage in the lifecycle of a running step.
+type StepEventType string
+
+const (
+	// StepEventStarted is the first event of a run. Data holds the step input.
+	StepEventStarted StepEventType = "started"
+	// StepEventSignal is sent for each signal of the run. On the server, these are the signals received by the step,
+	// and on the client the signals emitted by the step. ID holds the signal ID and Data the signal data.
+	StepEventSignal StepEventType = "signal"
+	// StepEventLog is sent for each debug log line the step reported. Data holds the line as a string.
+	StepEventLog StepEventType = "log"
+	// StepEventOutput is sent when the step produced an output. ID holds the output ID and Data the output data.
+	StepEventOutput StepEventType = "output"
+	// StepEventFinished is the last event of a run. Error is set if the step failed without an output.
+	StepEventFinished StepEventType = "finished"
+)
+
+// StepEvent is something that happened to a running step. The events of a single run are delivered in order: the
+// started event comes first, followed by signals and logs, then at most one output, and the finished event comes
+// last. The events of different runs may be interleaved.
+type StepEvent struct {
+	// Type is the stage in the lifecycle of the step.
+	Type StepEventType
+	// RunID identifies the run the event belongs to.
+	RunID string
+	// StepID is the ID of the running step.
+	StepID string
+	// Labels are the labels the step was started with, so the events can be attributed to a tenant in metrics and
+	// audit records.
+	Labels map[string]string
+	// ID is the signal ID for signal events and the output ID for output events.
+	ID string
+	// Data is the payload of the event as described by its type.
+	Data any
+	// Error is set on finished events if the step failed.
+	Error error
+	// Metrics holds the timings and payload sizes of the step execution on output and finished events. If the step
+	// failed, the measurements of the stages it didn't reach are 0.
+	Metrics *schema.StepMetrics
+}
+
+// stepEventEmitter sends the events of the running steps to a channel, keeping the ordering guarantees of StepEvent.
+// A nil emitter discards all events.
+type stepEventEmitter struct {
+	events chan<- StepEvent
+	lock   sync.Mutex
+	active map[string]struct{} // Key: run ID of the steps started and not yet finished.
+}
+
+func newStepEventEmitter(events chan<- StepEvent) *stepEventEmitter {
+	if events == nil {
+		return nil
+	}
+	return &stepEventEmitter{
+		events: events,
+		active: map[string]struct{}{},
+	}
+}
+
+// emit sends the event. Events of runs that are not active, such as signals arriving after the step finished, are
+// dropped. It blocks until the event is received.
+func (e *stepEventEmitter) emit(event StepEvent) {
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, active := e.active[event.RunID]
+	switch event.Type {
+	case StepEventStarted:
+		if active {
+			return
+		}
+		e.active[event.RunID] = struct{}{}
+	case StepEventFinished:
+		if !active {
+			return
+		}
+		delete(e.active, event.RunID)
+	default:
+		if !active {
+			return
+		}
+	}
+	e.events <- event
+}
@@ -0,0 +1,243 @@
+// Package host provides a high level API to run plugins over ATP from Go tools other than the Arcaflow engine. It
+// connects to a plugin, reads its schema, starts steps and delivers the lifecycle of each step as a stream of
+// atp.StepEvent over a channel, validated against the plugin schema.
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// ErrStepFinished is returned when sending a signal to a step that has already finished.
+var ErrStepFinished = errors.New("the step has already finished")
+
+// Plugin is a connection to a plugin. It is safe to start steps from multiple goroutines.
+type Plugin struct {
+	client atp.Client
+	schema *schema.SchemaSchema
+}
+
+// Connect connects to a plugin over the given channel and reads its schema. The logger may be nil.
+func Connect(channel atp.ClientChannel, logger log.Logger) (*Plugin, error) {
+	client := atp.NewClientWithLogger(channel, logger)
+	pluginSchema, err := client.ReadSchema()
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to read plugin schema (%w)", err)
+	}
+	return &Plugin{
+		client: client,
+		schema: pluginSchema,
+	}, nil
+}
+
+// Schema returns the schema of the plugin.
+func (p *Plugin) Schema() *schema.SchemaSchema {
+	return p.schema
+}
+
+// StartStep validates the serialized input against the input schema of the step and starts running it. The run ID
+// must be unique among the steps running on the plugin.
+func (p *Plugin) StartStep(runID string, stepID string, input any) (*Step, error) {
+	stepSchema, ok := p.schema.Steps()[stepID]
+	if !ok {
+		return nil, fmt.Errorf("step %s not found in the plugin schema", stepID)
+	}
+	if _, err := stepSchema.Input().Unserialize(input); err != nil {
+		return nil, fmt.Errorf("invalid input for step %s (%w)", stepID, err)
+	}
+	s := &Step{
+		runID:   runID,
+		schema:  stepSchema,
+		stepID:  stepID,
+		signals: make(chan schema.Input),
+		// The started event is buffered so the step starts running without waiting for the events to be consumed.
+		events: make(chan atp.StepEvent, 1),
+		done:   make(chan struct{}),
+	}
+	emittedSignals := make(chan schema.Input)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		s.forwardSignals(emittedSignals)
+	}()
+	s.events <- atp.StepEvent{Type: atp.StepEventStarted, RunID: runID, StepID: stepID, Data: input}
+	go func() {
+		result := p.client.Execute(
+			schema.Input{RunID: runID, ID: stepID, InputData: input},
+			s.signals,
+			emittedSignals,
+		)
+		// The signal channel is closed before the result is returned, but the signals still have to reach the events
+		// channel before the output.
+		<-forwarded
+		s.finish(result)
+	}()
+	return s, nil
+}
+
+// Close tells the plugin that no more steps will be started and closes the connection. Running steps fail.
+func (p *Plugin) Close() error {
+	return p.client.Close()
+}
+
+// Step is a running step of a plugin. Either the events of the step must be consumed, or Await must be called,
+// otherwise the plugin connection stalls on the next event. Await must not be called from multiple goroutines.
+type Step struct {
+	runID  string
+	stepID string
+	schema schema.Step
+
+	signalsLock sync.RWMutex
+	signals     chan schema.Input
+	events      chan atp.StepEvent
+	done        chan struct{}
+	// output is the output event Await received before the finished event.
+	output *atp.StepEvent
+}
+
+// RunID returns the run ID of the step.
+func (s *Step) RunID() string {
+	return s.runID
+}
+
+// SendSignal validates the serialized signal data against the signal handler of the step and sends it to the plugin.
+// It blocks until the signal is sent, and returns ErrStepFinished if the step finishes first.
+func (s *Step) SendSignal(signalID string, data any) error {
+	signalSchema, ok := s.schema.SignalHandlers()[signalID]
+	if !ok {
+		return fmt.Errorf("step %s has no signal handler %s", s.schema.ID(), signalID)
+	}
+	if _, err := signalSchema.DataSchema().Unserialize(data); err != nil {
+		return fmt.Errorf("invalid data for signal %s (%w)", signalID, err)
+	}
+	s.signalsLock.RLock()
+	defer s.signalsLock.RUnlock()
+	// The signal channel is closed once the step is done, so this must be checked before selecting the send.
+	select {
+	case <-s.done:
+		return ErrStepFinished
+	default:
+	}
+	select {
+	case <-s.done:
+		return ErrStepFinished
+	case s.signals <- schema.Input{RunID: s.runID, ID: signalID, InputData: data}:
+		return nil
+	}
+}
+
+// Events returns the channel delivering the lifecycle events of the step in the order described on atp.StepEvent.
+// The signal and output data is unserialized. Signals emitted by the step that don't match the plugin schema are
+// delivered as received, with the Error field set. The channel is closed after the finished event.
+func (s *Step) Events() <-chan atp.StepEvent {
+	return s.events
+}
+
+// Await waits for the step to finish and returns its output ID and unserialized output data. The other events are
+// discarded. If the context is cancelled first, the step keeps running and its result can be awaited again.
+func (s *Step) Await(ctx context.Context) (string, any, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		case event, ok := <-s.events:
+			if !ok {
+				return "", nil, ErrStepFinished
+			}
+			switch event.Type {
+			case atp.StepEventOutput:
+				s.output = &event
+			case atp.StepEventFinished:
+				if event.Error != nil {
+					return "", nil, event.Error
+				}
+				return s.output.ID, s.output.Data, nil
+			}
+		}
+	}
+}
+
+func (s *Step) forwardSignals(emittedSignals <-chan schema.Input) {
+	for signal := range emittedSignals {
+		s.events <- s.signalEvent(signal)
+	}
+}
+
+func (s *Step) signalEvent(signal schema.Input) atp.StepEvent {
+	event := atp.StepEvent{
+		Type:   atp.StepEventSignal,
+		RunID:  s.runID,
+		StepID: s.stepID,
+		ID:     signal.ID,
+		Data:   signal.InputData,
+	}
+	signalSchema, ok := s.schema.SignalEmitters()[signal.ID]
+	if !ok {
+		event.Error = fmt.Errorf("step %s emitted undeclared signal %s", s.stepID, signal.ID)
+		return event
+	}
+	data, err := signalSchema.DataSchema().Unserialize(signal.InputData)
+	if err != nil {
+		event.Error = fmt.Errorf("step %s emitted invalid data for signal %s (%w)", s.stepID, signal.ID, err)
+		return event
+	}
+	event.Data = data
+	return event
+}
+
+func (s *Step) finish(result atp.ExecutionResult) {
+	close(s.done)
+	// Wait for the signals being sent, then stop the signal loop of the client.
+	s.signalsLock.Lock()
+	close(s.signals)
+	s.signalsLock.Unlock()
+
+	for _, line := range strings.Split(result.DebugLogs, "\n") {
+		if strings.TrimSpace(line) != "" {
+			s.events <- atp.StepEvent{Type: atp.StepEventLog, RunID: s.runID, StepID: s.stepID, Data: line}
+		}
+	}
+	finished := atp.StepEvent{Type: atp.StepEventFinished, RunID: s.runID, StepID: s.stepID}
+	output, err := s.outputEvent(result)
+	if err != nil {
+		finished.Error = err
+	} else {
+		s.events <- output
+	}
+	s.events <- finished
+	close(s.events)
+}
+
+func (s *Step) outputEvent(result atp.ExecutionResult) (atp.StepEvent, error) {
+	if result.Error != nil {
+		return atp.StepEvent{}, result.Error
+	}
+	outputSchema, ok := s.schema.Outputs()[result.OutputID]
+	if !ok {
+		return atp.StepEvent{}, fmt.Errorf("step %s returned undeclared output %s", s.stepID, result.OutputID)
+	}
+	data, err := outputSchema.Unserialize(result.OutputData)
+	if err != nil {
+		return atp.StepEvent{}, fmt.Errorf(
+			"step %s returned invalid data for output %s (%w)",
+			s.stepID,
+			result.OutputID,
+			err,
+		)
+	}
+	return atp.StepEvent{
+		Type:   atp.StepEventOutput,
+		RunID:  s.runID,
+		StepID: s.stepID,
+		ID:     result.OutputID,
+		Data:   data,
+	}, nil
+}
@@ -0,0 +1,252 @@
+package host_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.arcalot.io/assert"
+	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/atp/host"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type greetInput struct {
+	Name string `json:"name"`
+}
+
+type greetOutput struct {
+	Message string `json:"message"`
+}
+
+type greetState struct {
+	names chan string
+}
+
+var progressSchema = schema.NewScopeSchema(
+	schema.NewObjectSchema("Progress", map[string]*schema.PropertySchema{
+		"stage": schema.NewPropertySchema(
+			schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}),
+)
+
+var greetInputSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[greetInput]("Input", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}),
+)
+
+var greetOutputs = map[string]*schema.StepOutputSchema{
+	"success": schema.NewStepOutputSchema(
+		schema.NewScopeSchema(
+			schema.NewStructMappedObjectSchema[greetOutput]("Output", map[string]*schema.PropertySchema{
+				"message": schema.NewPropertySchema(
+					schema.NewStringSchema(nil, nil, nil),
+					nil,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			}),
+		),
+		nil,
+		false,
+	),
+}
+
+var renameSignal = schema.NewCallableSignal(
+	"rename",
+	greetInputSchema,
+	nil,
+	func(_ context.Context, state *greetState, input greetInput) {
+		state.names <- input.Name
+	},
+)
+
+var greetSchema = schema.NewCallableSchema(
+	schema.NewCallableStepWithSignals[*greetState, greetInput](
+		"greet",
+		greetInputSchema,
+		greetOutputs,
+		map[string]schema.CallableSignal{"rename": renameSignal},
+		nil,
+		nil,
+		func() *greetState {
+			return &greetState{names: make(chan string, 1)}
+		},
+		func(ctx context.Context, state *greetState, input greetInput) (string, any) {
+			select {
+			case name := <-state.names:
+				return "success", greetOutput{Message: fmt.Sprintf("Hello, %s (formerly %s)!", name, input.Name)}
+			case <-ctx.Done():
+				return "success", greetOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+			}
+		},
+	),
+)
+
+type channel struct {
+	io.Reader
+	io.Writer
+	cancel func()
+}
+
+func (c channel) Close() error {
+	c.cancel()
+	return nil
+}
+
+func TestPlugin(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, greetSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	plugin := assert.NoErrorR[*host.Plugin](t)(host.Connect(
+		channel{stdoutReader, stdinWriter, cancel},
+		log.NewTestLogger(t),
+	))
+	assert.Equals(t, len(plugin.Schema().Steps()), 1)
+
+	_, err := plugin.StartStep("run-1", "nonexistent", map[string]any{"name": "Arca"})
+	assert.Error(t, err)
+	_, err = plugin.StartStep("run-1", "greet", map[string]any{"name": ""})
+	assert.Error(t, err)
+
+	step := assert.NoErrorR[*host.Step](t)(plugin.StartStep("run-1", "greet", map[string]any{"name": "Arca"}))
+	assert.Equals(t, step.RunID(), "run-1")
+	assert.Error(t, step.SendSignal("nonexistent", map[string]any{"name": "Lot"}))
+	assert.Error(t, step.SendSignal("rename", map[string]any{}))
+	assert.NoError(t, step.SendSignal("rename", map[string]any{"name": "Lot"}))
+	outputID, outputData, err := step.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, "success")
+	assert.Equals(t, outputData.(map[string]any), map[string]any{"message": "Hello, Lot (formerly Arca)!"})
+	assert.Equals(t, step.SendSignal("rename", map[string]any{"name": "Lot"}), host.ErrStepFinished)
+
+	assert.NoError(t, plugin.Close())
+	wg.Wait()
+}
+
+func TestPlugin_Events(t *testing.T) {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	pluginSchema := schema.NewCallableSchema(
+		schema.NewCallableStepWithSignals[any, greetInput](
+			"greet",
+			greetInputSchema,
+			greetOutputs,
+			nil,
+			map[string]*schema.SignalSchema{
+				"progress": schema.NewSignalSchema("progress", progressSchema, nil),
+			},
+			nil,
+			nil,
+			nil,
+		),
+	)
+	serializedSchema := assert.NoErrorR[any](t)(pluginSchema.SelfSerialize())
+
+	// The SDK server cannot emit signals, so this test uses a minimal server instead.
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		decoder := cbor.NewDecoder(stdinReader)
+		encoder := cbor.NewEncoder(stdoutWriter)
+		var start atp.StartMessage
+		assert.NoError(t, decoder.Decode(&start))
+		assert.NoError(t, encoder.Encode(atp.HelloMessage{Version: atp.ProtocolVersion, Schema: serializedSchema}))
+		var workStart atp.DecodedRuntimeMessage
+		assert.NoError(t, decoder.Decode(&workStart))
+		assert.Equals(t, workStart.MessageID, atp.MessageTypeWorkStart)
+		for _, data := range []map[string]any{{"stage": "started"}, {"stage": ""}} {
+			assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+				MessageID:   atp.MessageTypeSignal,
+				RunID:       workStart.RunID,
+				MessageData: atp.SignalMessage{SignalID: "progress", Data: data},
+			}))
+		}
+		assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+			MessageID: atp.MessageTypeWorkDone,
+			RunID:     workStart.RunID,
+			MessageData: atp.WorkDoneMessage{
+				StepID:     "greet",
+				OutputID:   "success",
+				OutputData: map[string]any{"message": "Hello, Arca!"},
+			},
+		}))
+		var clientDone atp.DecodedRuntimeMessage
+		assert.NoError(t, decoder.Decode(&clientDone))
+		assert.Equals(t, clientDone.MessageID, atp.MessageTypeClientDone)
+	}()
+
+	plugin := assert.NoErrorR[*host.Plugin](t)(host.Connect(
+		channel{stdoutReader, stdinWriter, func() {}},
+		log.NewTestLogger(t),
+	))
+	step := assert.NoErrorR[*host.Step](t)(plugin.StartStep("run-1", "greet", map[string]any{"name": "Arca"}))
+	var events []atp.StepEvent
+	for event := range step.Events() {
+		events = append(events, event)
+	}
+	assert.Equals(t, len(events), 5)
+	assert.Equals(t, events[0], atp.StepEvent{
+		Type:   atp.StepEventStarted,
+		RunID:  "run-1",
+		StepID: "greet",
+		Data:   map[string]any{"name": "Arca"},
+	})
+	assert.Equals(t, events[1], atp.StepEvent{
+		Type:   atp.StepEventSignal,
+		RunID:  "run-1",
+		StepID: "greet",
+		ID:     "progress",
+		Data:   map[string]any{"stage": "started"},
+	})
+	// Signals that don't match the schema are delivered with an error.
+	assert.Equals(t, events[2].Type, atp.StepEventSignal)
+	assert.Error(t, events[2].Error)
+	assert.Equals(t, events[3], atp.StepEvent{
+		Type:   atp.StepEventOutput,
+		RunID:  "run-1",
+		StepID: "greet",
+		ID:     "success",
+		Data:   map[string]any{"message": "Hello, Arca!"},
+	})
+	assert.Equals(t, events[4], atp.StepEvent{Type: atp.StepEventFinished, RunID: "run-1", StepID: "greet"})
+	_, _, err := step.Await(context.Background())
+	assert.Equals(t, err, host.ErrStepFinished)
+
+	assert.NoError(t, plugin.Close())
+	<-serverDone
+}
@@ -0,0 +1,348 @@
+package atp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrJobQueueClosed is returned when submitting a job to a closed job queue.
+var ErrJobQueueClosed = errors.New("the job queue is closed")
+
+// ErrJobExists is returned when submitting a job with the run ID of a job already in the queue.
+var ErrJobExists = errors.New("a job with this run ID already exists")
+
+// JobStatus describes how far a job in a JobQueue got.
+type JobStatus string
+
+const (
+	// JobQueued is the status of a job waiting for a worker.
+	JobQueued JobStatus = "queued"
+	// JobRunning is the status of a job whose step is running.
+	JobRunning JobStatus = "running"
+	// JobFinished is the status of a job whose step returned an output.
+	JobFinished JobStatus = "finished"
+	// JobFailed is the status of a job whose step failed without an output.
+	JobFailed JobStatus = "failed"
+)
+
+// Job is a step request stored in a JobQueue.
+type Job struct {
+	RunID  string `cbor:"run_id"`
+	StepID string `cbor:"step_id"`
+	// Input is the serialized step input.
+	Input  any       `cbor:"input"`
+	Status JobStatus `cbor:"status"`
+	// OutputID and OutputData hold the serialized output of finished jobs.
+	OutputID   string `cbor:"output_id,omitempty"`
+	OutputData any    `cbor:"output_data,omitempty"`
+	// Error holds the reason failed jobs failed.
+	Error string `cbor:"error,omitempty"`
+	// Sequence orders the jobs by submission.
+	Sequence uint64 `cbor:"sequence"`
+}
+
+// JobQueue runs step requests in the background and stores them, along with their results, in a directory. This
+// turns a long-lived plugin service into a small durable worker: the jobs survive process restarts, and their status
+// can be queried until they are removed. Jobs that were queued or running when the process stopped are run again when
+// the queue is opened the next time, so steps run through a job queue should be safe to repeat.
+type JobQueue struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	dir          string
+	pluginSchema *schema.CallableSchema
+
+	lock         sync.Mutex
+	changed      *sync.Cond
+	jobs         map[string]*Job
+	done         map[string]chan struct{}
+	pending      []string
+	lastSequence uint64
+	closed       bool
+	workers      sync.WaitGroup
+}
+
+// OpenJobQueue loads the jobs stored in the directory, creating it if needed, and starts the given number of workers
+// to run the jobs that haven't finished yet. The steps run with the given context.
+func OpenJobQueue(
+	ctx context.Context,
+	dir string,
+	pluginSchema *schema.CallableSchema,
+	workers int,
+) (*JobQueue, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("a job queue needs at least one worker (got %d)", workers)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create job queue directory %s (%w)", dir, err)
+	}
+	jobs, err := loadJobs(dir)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	q := &JobQueue{
+		ctx:          ctx,
+		cancel:       cancel,
+		dir:          dir,
+		pluginSchema: pluginSchema,
+		jobs:         make(map[string]*Job, len(jobs)),
+		done:         make(map[string]chan struct{}, len(jobs)),
+	}
+	q.changed = sync.NewCond(&q.lock)
+	for _, job := range jobs {
+		q.jobs[job.RunID] = job
+		q.done[job.RunID] = make(chan struct{})
+		q.lastSequence = max(q.lastSequence, job.Sequence)
+		switch job.Status {
+		case JobQueued, JobRunning:
+			job.Status = JobQueued
+			q.pending = append(q.pending, job.RunID)
+		default:
+			close(q.done[job.RunID])
+		}
+	}
+	for i := 0; i < workers; i++ {
+		q.workers.Add(1)
+		go q.work()
+	}
+	return q, nil
+}
+
+// Submit validates the input against the step schema, stores the job and queues it. The run ID must be unique among
+// the jobs in the queue, otherwise ErrJobExists is returned.
+func (q *JobQueue) Submit(runID string, stepID string, input any) error {
+	step, ok := q.pluginSchema.StepsValue[stepID]
+	if !ok {
+		return fmt.Errorf("step %s not found in the plugin schema", stepID)
+	}
+	if _, err := step.Input().Unserialize(input); err != nil {
+		return fmt.Errorf("invalid input for step %s (%w)", stepID, err)
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return ErrJobQueueClosed
+	}
+	if _, ok := q.jobs[runID]; ok {
+		return fmt.Errorf("cannot submit run ID %s (%w)", runID, ErrJobExists)
+	}
+	job := &Job{
+		RunID:    runID,
+		StepID:   stepID,
+		Input:    input,
+		Status:   JobQueued,
+		Sequence: q.lastSequence + 1,
+	}
+	if err := q.save(job); err != nil {
+		return err
+	}
+	q.lastSequence = job.Sequence
+	q.jobs[runID] = job
+	q.done[runID] = make(chan struct{})
+	q.pending = append(q.pending, runID)
+	q.changed.Signal()
+	return nil
+}
+
+// Job returns a copy of the job with the given run ID. The second return value is false if there is no such job.
+func (q *JobQueue) Job(runID string) (Job, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	job, ok := q.jobs[runID]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Jobs returns a copy of all jobs in the order they were submitted.
+func (q *JobQueue) Jobs() []Job {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	result := make([]Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		result = append(result, *job)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Sequence < result[j].Sequence
+	})
+	return result
+}
+
+// Wait waits for the job with the given run ID to finish or fail and returns it.
+func (q *JobQueue) Wait(ctx context.Context, runID string) (Job, error) {
+	q.lock.Lock()
+	done, ok := q.done[runID]
+	q.lock.Unlock()
+	if !ok {
+		return Job{}, fmt.Errorf("no job with run ID %s", runID)
+	}
+	select {
+	case <-ctx.Done():
+		return Job{}, ctx.Err()
+	case <-done:
+		job, _ := q.Job(runID)
+		return job, nil
+	}
+}
+
+// Remove deletes a finished or failed job from the queue.
+func (q *JobQueue) Remove(runID string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	job, ok := q.jobs[runID]
+	if !ok {
+		return fmt.Errorf("no job with run ID %s", runID)
+	}
+	if job.Status != JobFinished && job.Status != JobFailed {
+		return fmt.Errorf("job %s is still %s", runID, job.Status)
+	}
+	if err := os.Remove(q.jobPath(runID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove job %s (%w)", runID, err)
+	}
+	delete(q.jobs, runID)
+	delete(q.done, runID)
+	return nil
+}
+
+// Close stops the queue. The context of the running steps is cancelled and the workers are waited for. The results
+// of the steps that were running are not recorded, so these jobs run again when the queue is opened the next time,
+// just like the jobs still queued. Waiting for these jobs only ends with the context of Wait.
+func (q *JobQueue) Close() {
+	q.lock.Lock()
+	q.closed = true
+	q.changed.Broadcast()
+	q.lock.Unlock()
+	q.cancel()
+	q.workers.Wait()
+}
+
+func (q *JobQueue) work() {
+	defer q.workers.Done()
+	for {
+		q.lock.Lock()
+		for !q.closed && len(q.pending) == 0 {
+			q.changed.Wait()
+		}
+		if q.closed {
+			q.lock.Unlock()
+			return
+		}
+		job := q.jobs[q.pending[0]]
+		q.pending = q.pending[1:]
+		job.Status = JobRunning
+		// A failure to record the running status is harmless, the job is still queued on disk.
+		_ = q.save(job)
+		runID, stepID, input := job.RunID, job.StepID, job.Input
+		q.lock.Unlock()
+
+		outputID, outputData, err := q.callStep(runID, stepID, input)
+
+		q.lock.Lock()
+		if q.closed {
+			q.lock.Unlock()
+			return
+		}
+		if err != nil {
+			job.Status = JobFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = JobFinished
+			job.OutputID = outputID
+			job.OutputData = outputData
+		}
+		if err := q.save(job); err != nil {
+			job.Status = JobFailed
+			job.OutputID = ""
+			job.OutputData = nil
+			job.Error = err.Error()
+		}
+		close(q.done[runID])
+		q.lock.Unlock()
+	}
+}
+
+func (q *JobQueue) callStep(runID string, stepID string, input any) (outputID string, outputData any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while running step with Run ID '%s': (%v)", runID, r)
+		}
+	}()
+	return q.pluginSchema.CallStep(q.ctx, runID, stepID, input)
+}
+
+// save writes the job to a temporary file and moves it in place, so a crash never leaves a partially written job.
+func (q *JobQueue) save(job *Job) error {
+	data, err := cbor.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s (%w)", job.RunID, err)
+	}
+	file, err := os.CreateTemp(q.dir, "job-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to store job %s (%w)", job.RunID, err)
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), q.jobPath(job.RunID))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to store job %s (%w)", job.RunID, err)
+	}
+	return nil
+}
+
+// jobPath returns the file of the job. The file is named after the hash of the run ID, since the run ID may contain
+// any character and may be longer than the 255 bytes file systems allow for a file name.
+func (q *JobQueue) jobPath(runID string) string {
+	hash := sha256.Sum256([]byte(runID))
+	return filepath.Join(q.dir, hex.EncodeToString(hash[:])+".job")
+}
+
+func loadJobs(dir string) ([]*Job, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job queue directory %s (%w)", dir, err)
+	}
+	var jobs []*Job
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			// Left behind by a crash while storing a job.
+			_ = os.Remove(path)
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".job") {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job file %s (%w)", path, err)
+		}
+		job := &Job{}
+		if err := cbor.Unmarshal(data, job); err != nil {
+			return nil, fmt.Errorf("failed to decode job file %s (%w)", path, err)
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Sequence < jobs[j].Sequence
+	})
+	return jobs, nil
+}
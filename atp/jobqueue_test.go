@@ -0,0 +1,150 @@
+package atp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestJobQueue(t *testing.T) {
+	queue := assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(context.Background(), t.TempDir(), helloWorldSchema, 2))
+	defer queue.Close()
+
+	assert.Error(t, queue.Submit("run-1", "nonexistent", map[string]any{"name": "Arca Lot"}))
+	assert.Error(t, queue.Submit("run-1", "hello-world", map[string]any{}))
+	assert.NoError(t, queue.Submit("run-1", "hello-world", map[string]any{"name": "Arca Lot"}))
+	assert.Error(t, queue.Submit("run-1", "hello-world", map[string]any{"name": "Arca Lot"}))
+
+	job, err := queue.Wait(context.Background(), "run-1")
+	assert.NoError(t, err)
+	assert.Equals(t, job.Status, atp.JobFinished)
+	assert.Equals(t, job.OutputID, "success")
+	assert.Equals(t, job.OutputData.(map[string]any)["message"].(string), "Hello, Arca Lot!")
+	assert.Equals(t, len(queue.Jobs()), 1)
+
+	assert.NoError(t, queue.Remove("run-1"))
+	_, ok := queue.Job("run-1")
+	assert.Equals(t, ok, false)
+	_, err = queue.Wait(context.Background(), "run-1")
+	assert.Error(t, err)
+}
+
+func TestJobQueue_Panicking(t *testing.T) {
+	queue := assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(
+		context.Background(),
+		t.TempDir(),
+		panickingHelloWorldSchema,
+		1,
+	))
+	defer queue.Close()
+
+	assert.NoError(t, queue.Submit("run-1", "hello-world", map[string]any{"name": "Arca Lot"}))
+	job, err := queue.Wait(context.Background(), "run-1")
+	assert.NoError(t, err)
+	assert.Equals(t, job.Status, atp.JobFailed)
+	assert.Contains(t, job.Error, "abcde")
+}
+
+func TestJobQueue_Restart(t *testing.T) {
+	// Jobs that didn't finish before the queue was closed run again after it is opened again.
+	dir := t.TempDir()
+	started := make(chan struct{})
+	blockingSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			helloWorldSchema.StepsValue["hello-world"].Outputs(),
+			nil,
+			func(ctx context.Context, input helloWorldInput) (string, any) {
+				close(started)
+				<-ctx.Done()
+				return helloWorldStepHandler(ctx, nil, input)
+			},
+		),
+	)
+	queue := assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(context.Background(), dir, blockingSchema, 1))
+	assert.NoError(t, queue.Submit("run-1", "hello-world", map[string]any{"name": "Arca"}))
+	assert.NoError(t, queue.Submit("run-2", "hello-world", map[string]any{"name": "Lot"}))
+	<-started
+	job, _ := queue.Job("run-1")
+	assert.Equals(t, job.Status, atp.JobRunning)
+	job, _ = queue.Job("run-2")
+	assert.Equals(t, job.Status, atp.JobQueued)
+	queue.Close()
+	assert.Equals(t, queue.Submit("run-3", "hello-world", map[string]any{"name": "Arca"}), atp.ErrJobQueueClosed)
+
+	queue = assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(context.Background(), dir, helloWorldSchema, 1))
+	defer queue.Close()
+	for _, runID := range []string{"run-1", "run-2"} {
+		job, err := queue.Wait(context.Background(), runID)
+		assert.NoError(t, err)
+		assert.Equals(t, job.Status, atp.JobFinished)
+	}
+	jobs := queue.Jobs()
+	assert.Equals(t, len(jobs), 2)
+	assert.Equals(t, jobs[0].RunID, "run-1")
+	assert.Equals(t, jobs[1].RunID, "run-2")
+	assert.Equals(t, jobs[1].OutputData.(map[string]any)["message"].(string), "Hello, Lot!")
+}
+
+func TestJobQueue_LongRunID(t *testing.T) {
+	// Run IDs longer than the file name limit of the file system are stored under their hash.
+	dir := t.TempDir()
+	runID := strings.Repeat("run-", 100)
+	queue := assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(context.Background(), dir, helloWorldSchema, 1))
+	assert.NoError(t, queue.Submit(runID, "hello-world", map[string]any{"name": "Arca Lot"}))
+	_, err := queue.Wait(context.Background(), runID)
+	assert.NoError(t, err)
+	queue.Close()
+
+	queue = assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(context.Background(), dir, helloWorldSchema, 1))
+	defer queue.Close()
+	job, ok := queue.Job(runID)
+	assert.Equals(t, ok, true)
+	assert.Equals(t, job.Status, atp.JobFinished)
+	assert.NoError(t, queue.Remove(runID))
+}
+
+func TestJobQueue_Server(t *testing.T) {
+	queue := assert.NoErrorR[*atp.JobQueue](t)(atp.OpenJobQueue(context.Background(), t.TempDir(), helloWorldSchema, 1))
+	defer queue.Close()
+	// The job was run before the plugin restarted.
+	assert.NoError(t, queue.Submit("first", "hello-world", map[string]any{"name": "Arca"}))
+	_, err := queue.Wait(context.Background(), "first")
+	assert.NoError(t, err)
+	sessions := atp.NewServerSessions(context.Background()).WithJobQueue(queue)
+
+	connection := newPipeConnection()
+	serverErrors := connection.serve(helloWorldSchema, sessions)
+	cli := atp.NewClientWithLogger(connection, log.NewTestLogger(t))
+	_, err = cli.ReadSchema()
+	assert.NoError(t, err)
+
+	// The client receives the stored result instead of running the step again.
+	result := cli.Execute(
+		schema.Input{RunID: "first", ID: "hello-world", InputData: map[string]any{"name": "Lot"}},
+		nil,
+		nil,
+	)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputData.(map[any]any)["message"].(string), "Hello, Arca!")
+
+	result = cli.Execute(
+		schema.Input{RunID: "second", ID: "hello-world", InputData: map[string]any{"name": "Lot"}},
+		nil,
+		nil,
+	)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputData.(map[any]any)["message"].(string), "Hello, Lot!")
+	job, ok := queue.Job("second")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, job.Status, atp.JobFinished)
+
+	assert.NoError(t, cli.Close())
+	assert.Equals(t, len(<-serverErrors), 0)
+}
@@ -0,0 +1,299 @@
+package loadtest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// maxGeneratorDepth limits how deep the generator descends into nested objects. Beyond this depth only required
+// properties and the minimum number of list and map items are generated, so recursive schemas terminate.
+const maxGeneratorDepth = 8
+
+// maxGeneratedItems caps the length of generated strings, lists and maps when the schema has no upper bound.
+const maxGeneratedItems = 8
+
+// maxGenerateAttempts is the number of times Generate retries when the generated data fails validation.
+const maxGenerateAttempts = 100
+
+// intBounds reads the limits of an integer schema. schema.Int cannot be used in a type assertion because it embeds a
+// type constraint.
+type intBounds interface {
+	Min() *int64
+	Max() *int64
+}
+
+const generatedStringCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Generator creates random serialized data matching a schema. The same seed produces the same sequence of values.
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	rng *rand.Rand
+}
+
+// NewGenerator creates a new random data generator with the specified seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rng: rand.New(rand.NewSource(seed)), //nolint:gosec // Load test data does not need a secure random source.
+	}
+}
+
+// Generate creates random serialized data for the specified type. The generated data is validated against the type
+// and regenerated if it does not pass, for example because of interdependent properties. An error is returned if no
+// valid data could be generated.
+func (g *Generator) Generate(t schema.Type) (any, error) {
+	var lastErr error
+	for i := 0; i < maxGenerateAttempts; i++ {
+		data, err := g.generate(t, 0)
+		if err != nil {
+			return nil, err
+		}
+		if _, lastErr = t.Unserialize(data); lastErr == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to generate valid data after %d attempts (%w)", maxGenerateAttempts, lastErr)
+}
+
+func (g *Generator) generate(t schema.Type, depth int) (any, error) {
+	switch t.TypeID() {
+	case schema.TypeIDString:
+		return g.generateString(t.(schema.String))
+	case schema.TypeIDInt:
+		i := t.(intBounds)
+		return g.generateInt(i.Min(), i.Max()), nil
+	case schema.TypeIDFloat:
+		return g.generateFloat(t.(schema.Float)), nil
+	case schema.TypeIDBool:
+		return g.rng.Intn(2) == 1, nil
+	case schema.TypeIDStringEnum, schema.TypeIDIntEnum:
+		return g.generateEnum(t)
+	case schema.TypeIDList:
+		return g.generateList(t.(schema.UntypedList), depth)
+	case schema.TypeIDMap:
+		return g.generateMap(t.(schema.UntypedMap), depth)
+	case schema.TypeIDTuple:
+		return g.generateTuple(t.(*schema.TupleSchema), depth)
+	case schema.TypeIDScope:
+		return g.generateObject(t.(schema.Scope).RootObject(), depth)
+	case schema.TypeIDRef:
+		return g.generateObject(t.(schema.Ref).GetObject(), depth)
+	case schema.TypeIDObject:
+		return g.generateObject(t.(schema.Object), depth)
+	case schema.TypeIDOneOfString:
+		return generateOneOf(g, t.(*schema.OneOfSchema[string]), depth)
+	case schema.TypeIDOneOfInt:
+		return generateOneOf(g, t.(*schema.OneOfSchema[int64]), depth)
+	case schema.TypeIDAny:
+		return g.randomString(1, maxGeneratedItems), nil
+	default:
+		return nil, fmt.Errorf("cannot generate data for type %s", t.TypeID())
+	}
+}
+
+func (g *Generator) generateString(s schema.String) (any, error) {
+	if s.Pattern() != nil {
+		return nil, fmt.Errorf("cannot generate strings matching the pattern %s", s.Pattern().String())
+	}
+	minLength, maxLength := g.bounds(s.Min(), s.Max())
+	return g.randomString(minLength, maxLength), nil
+}
+
+func (g *Generator) randomString(minLength int64, maxLength int64) string {
+	length := minLength + g.rng.Int63n(maxLength-minLength+1)
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = generatedStringCharacters[g.rng.Intn(len(generatedStringCharacters))]
+	}
+	return string(result)
+}
+
+func (g *Generator) generateInt(minValue *int64, maxValue *int64) int64 {
+	lower := int64(math.MinInt32)
+	upper := int64(math.MaxInt32)
+	if minValue != nil {
+		lower = *minValue
+		if maxValue == nil {
+			upper = lower + math.MaxInt32
+		}
+	}
+	if maxValue != nil {
+		upper = *maxValue
+		if minValue == nil {
+			lower = upper - math.MaxInt32
+		}
+	}
+	if upper <= lower {
+		return lower
+	}
+	return lower + g.rng.Int63n(upper-lower+1)
+}
+
+func (g *Generator) generateFloat(f schema.Float) float64 {
+	lower := -float64(math.MaxInt32)
+	upper := float64(math.MaxInt32)
+	if f.Min() != nil {
+		lower = *f.Min()
+		if f.Max() == nil {
+			upper = lower + math.MaxInt32
+		}
+	}
+	if f.Max() != nil {
+		upper = *f.Max()
+		if f.Min() == nil {
+			lower = upper - math.MaxInt32
+		}
+	}
+	return lower + g.rng.Float64()*(upper-lower)
+}
+
+func (g *Generator) generateEnum(t schema.Type) (any, error) {
+	// The enum schemas are generic over the value type, so the valid values are read through reflection.
+	validValues := reflect.ValueOf(t).MethodByName("ValidValues")
+	if !validValues.IsValid() {
+		return nil, fmt.Errorf("cannot read the valid values of enum %T", t)
+	}
+	values := validValues.Call(nil)[0]
+	if values.Len() == 0 {
+		return nil, fmt.Errorf("enum %T has no valid values", t)
+	}
+	keys := values.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+	})
+	key := keys[g.rng.Intn(len(keys))]
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	return key.Int(), nil
+}
+
+func (g *Generator) generateList(l schema.UntypedList, depth int) (any, error) {
+	length := g.collectionLength(l.Min(), l.Max(), depth)
+	result := make([]any, length)
+	for i := range result {
+		item, err := g.generate(l.Items(), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate list item %d (%w)", i, err)
+		}
+		result[i] = item
+	}
+	return result, nil
+}
+
+func (g *Generator) generateTuple(t *schema.TupleSchema, depth int) (any, error) {
+	result := make([]any, len(t.Items()))
+	for i, itemType := range t.Items() {
+		item, err := g.generate(itemType, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate tuple item %d (%w)", i, err)
+		}
+		result[i] = item
+	}
+	return result, nil
+}
+
+func (g *Generator) generateMap(m schema.UntypedMap, depth int) (any, error) {
+	length := g.collectionLength(m.Min(), m.Max(), depth)
+	result := make(map[any]any, length)
+	// Random keys may collide, so keep generating until enough distinct keys were found or the attempts ran out.
+	for attempt := 0; int64(len(result)) < length && attempt < maxGenerateAttempts; attempt++ {
+		key, err := g.generate(m.Keys(), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate map key (%w)", err)
+		}
+		if _, exists := result[key]; exists {
+			continue
+		}
+		value, err := g.generate(m.Values(), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate map value for key %v (%w)", key, err)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (g *Generator) generateObject(o schema.Object, depth int) (map[string]any, error) {
+	properties := o.Properties()
+	propertyIDs := make([]string, 0, len(properties))
+	for propertyID := range properties {
+		propertyIDs = append(propertyIDs, propertyID)
+	}
+	sort.Strings(propertyIDs)
+	result := make(map[string]any, len(properties))
+	for _, propertyID := range propertyIDs {
+		property := properties[propertyID]
+		if property.Disabled {
+			continue
+		}
+		if !property.Required() && (depth >= maxGeneratorDepth || g.rng.Intn(2) == 0) {
+			continue
+		}
+		value, err := g.generate(property.Type(), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate property %s of object %s (%w)", propertyID, o.ID(), err)
+		}
+		result[propertyID] = value
+	}
+	return result, nil
+}
+
+func generateOneOf[KeyType int64 | string](g *Generator, o *schema.OneOfSchema[KeyType], depth int) (any, error) {
+	types := o.Types()
+	if len(types) == 0 {
+		return nil, fmt.Errorf("one-of type has no variants")
+	}
+	keys := make([]KeyType, 0, len(types))
+	for key := range types {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	key := keys[g.rng.Intn(len(keys))]
+	data, err := g.generateObject(types[key], depth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate one-of variant %v (%w)", key, err)
+	}
+	switch {
+	case o.Untagged:
+		return data, nil
+	case o.ValueFieldName() != "":
+		return map[string]any{
+			o.DiscriminatorFieldName(): key,
+			o.ValueFieldName():         data,
+		}, nil
+	default:
+		data[o.DiscriminatorFieldName()] = key
+		return data, nil
+	}
+}
+
+// bounds returns the length range for strings, lists and maps, capping unbounded lengths.
+func (g *Generator) bounds(minValue *int64, maxValue *int64) (int64, int64) {
+	lower := int64(0)
+	if minValue != nil {
+		lower = *minValue
+	}
+	upper := lower + maxGeneratedItems
+	if maxValue != nil && *maxValue < upper {
+		upper = *maxValue
+	}
+	if upper < lower {
+		upper = lower
+	}
+	return lower, upper
+}
+
+func (g *Generator) collectionLength(minValue *int64, maxValue *int64, depth int) int64 {
+	lower, upper := g.bounds(minValue, maxValue)
+	if depth >= maxGeneratorDepth {
+		return lower
+	}
+	return lower + g.rng.Int63n(upper-lower+1)
+}
@@ -0,0 +1,139 @@
+package loadtest
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// histogramBaseBucket is the upper bound of the first histogram bucket. Each following bucket doubles the bound.
+const histogramBaseBucket = 10 * time.Microsecond
+
+// histogramBuckets is the number of bounded buckets. The last bound is a little over 5 minutes, slower samples fall
+// into an overflow bucket.
+const histogramBuckets = 25
+
+// Bucket is a single bucket of a latency histogram.
+type Bucket struct {
+	// UpperBound is the inclusive upper bound of the latencies counted in this bucket. The overflow bucket has a
+	// bound of math.MaxInt64.
+	UpperBound time.Duration
+	// Count is the number of latencies recorded in this bucket.
+	Count uint64
+}
+
+// Histogram records latencies in exponentially growing buckets. It is safe for concurrent use.
+type Histogram struct {
+	lock   sync.Mutex
+	counts [histogramBuckets + 1]uint64
+	count  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram creates an empty latency histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// Record adds a single latency to the histogram.
+func (h *Histogram) Record(latency time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.counts[bucketIndex(latency)]++
+	if h.count == 0 || latency < h.min {
+		h.min = latency
+	}
+	if latency > h.max {
+		h.max = latency
+	}
+	h.count++
+	h.sum += latency
+}
+
+// Count returns the number of recorded latencies.
+func (h *Histogram) Count() uint64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.count
+}
+
+// Min returns the lowest recorded latency, or 0 if nothing was recorded.
+func (h *Histogram) Min() time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.min
+}
+
+// Max returns the highest recorded latency.
+func (h *Histogram) Max() time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.max
+}
+
+// Mean returns the average recorded latency, or 0 if nothing was recorded.
+func (h *Histogram) Mean() time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.count)
+}
+
+// Percentile returns an estimate of the latency below which the given percentage (0-100) of the recorded latencies
+// fall. The estimate is the upper bound of the bucket containing the percentile, capped at the highest recorded
+// latency.
+func (h *Histogram) Percentile(percentile float64) time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(percentile / 100 * float64(h.count)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen uint64
+	for i, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			return min(bucketUpperBound(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// Buckets returns the non-empty buckets of the histogram in ascending order.
+func (h *Histogram) Buckets() []Bucket {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	var result []Bucket
+	for i, count := range h.counts {
+		if count > 0 {
+			result = append(result, Bucket{
+				UpperBound: bucketUpperBound(i),
+				Count:      count,
+			})
+		}
+	}
+	return result
+}
+
+func bucketIndex(latency time.Duration) int {
+	for i := 0; i < histogramBuckets; i++ {
+		if latency <= bucketUpperBound(i) {
+			return i
+		}
+	}
+	return histogramBuckets
+}
+
+func bucketUpperBound(index int) time.Duration {
+	if index >= histogramBuckets {
+		return math.MaxInt64
+	}
+	return histogramBaseBucket << index
+}
@@ -0,0 +1,229 @@
+// Package loadtest drives a plugin over ATP with randomly generated, schema-valid input and collects latency
+// histograms. This lets maintainers benchmark the overhead of a plugin and the SDK end to end.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Config describes the load to put on the plugin.
+type Config struct {
+	// StepID is the step to execute. It may be left empty if the plugin has exactly one step.
+	StepID string
+	// Concurrency is the number of executions running at the same time. Defaults to 1.
+	Concurrency int
+	// Rate is the maximum number of executions started per second across all workers. Zero means no limit. The rate
+	// cannot exceed one execution per nanosecond.
+	Rate float64
+	// Requests is the total number of executions. If zero, executions are started until Duration has passed.
+	Requests int
+	// Duration limits the time during which new executions are started. If zero, Requests must be set.
+	Duration time.Duration
+	// Seed initializes the random input generators so runs are reproducible.
+	Seed int64
+}
+
+func (c Config) validate() error {
+	if c.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency: %d", c.Concurrency)
+	}
+	if c.Rate < 0 || c.Rate > float64(time.Second) || math.IsNaN(c.Rate) {
+		return fmt.Errorf("invalid rate: %f", c.Rate)
+	}
+	if c.Requests < 0 {
+		return fmt.Errorf("invalid number of requests: %d", c.Requests)
+	}
+	if c.Duration < 0 {
+		return fmt.Errorf("invalid duration: %s", c.Duration)
+	}
+	if c.Requests == 0 && c.Duration == 0 {
+		return fmt.Errorf("either the number of requests or the duration must be set")
+	}
+	return nil
+}
+
+// Result holds the measurements of a load test run.
+type Result struct {
+	// Requests is the number of executions that were started.
+	Requests int
+	// Errors is the number of executions that failed, either because no input could be generated or because the
+	// execution returned an error.
+	Errors int
+	// Outputs counts the output IDs of the successful executions.
+	Outputs map[string]int
+	// Latency is the histogram of the execution latencies, from sending the input to receiving the output.
+	Latency *Histogram
+	// Elapsed is the wall clock time of the entire run.
+	Elapsed time.Duration
+}
+
+// Throughput returns the number of executions completed successfully per second.
+func (r *Result) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests-r.Errors) / r.Elapsed.Seconds()
+}
+
+// Run reads the schema from the client and executes the configured step with random input until the number of
+// requests or the duration in the config is reached, or the context is cancelled. Executions that are already
+// running when the run stops are waited for. The client must not have read the schema yet and is not closed.
+func Run(ctx context.Context, client atp.Client, config Config) (*Result, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	pluginSchema, err := client.ReadSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin schema (%w)", err)
+	}
+	stepID, step, err := selectStep(pluginSchema, config.StepID)
+	if err != nil {
+		return nil, err
+	}
+	// Fail early if the input schema contains something the generator cannot handle.
+	if _, err := NewGenerator(config.Seed).Generate(step.Input()); err != nil {
+		return nil, fmt.Errorf("cannot generate input for step %s (%w)", stepID, err)
+	}
+
+	if config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Duration)
+		defer cancel()
+	}
+	var ticks <-chan time.Time
+	if config.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / config.Rate))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	concurrency := config.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	r := &runner{
+		client:  client,
+		config:  config,
+		stepID:  stepID,
+		input:   step.Input(),
+		ticks:   ticks,
+		outputs: map[string]int{},
+		latency: NewHistogram(),
+	}
+	start := time.Now()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			r.work(ctx, NewGenerator(config.Seed+int64(worker)))
+		}(i)
+	}
+	wg.Wait()
+	return &Result{
+		Requests: int(r.started.Load()),
+		Errors:   r.errors,
+		Outputs:  r.outputs,
+		Latency:  r.latency,
+		Elapsed:  time.Since(start),
+	}, nil
+}
+
+func selectStep(pluginSchema *schema.SchemaSchema, stepID string) (string, schema.Step, error) {
+	steps := pluginSchema.Steps()
+	if stepID != "" {
+		step, ok := steps[stepID]
+		if !ok {
+			return "", nil, fmt.Errorf("step %s not found in the plugin schema", stepID)
+		}
+		return stepID, step, nil
+	}
+	if len(steps) != 1 {
+		stepIDs := make([]string, 0, len(steps))
+		for id := range steps {
+			stepIDs = append(stepIDs, id)
+		}
+		sort.Strings(stepIDs)
+		return "", nil, fmt.Errorf("the plugin has %d steps (%v), please specify the step ID", len(steps), stepIDs)
+	}
+	for id, step := range steps {
+		return id, step, nil
+	}
+	panic("unreachable")
+}
+
+type runner struct {
+	client  atp.Client
+	config  Config
+	stepID  string
+	input   schema.Scope
+	ticks   <-chan time.Time
+	started atomic.Int64
+
+	lock    sync.Mutex
+	errors  int
+	outputs map[string]int
+	latency *Histogram
+}
+
+func (r *runner) work(ctx context.Context, generator *Generator) {
+	for {
+		if r.ticks != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.ticks:
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+		requestNumber := r.started.Add(1)
+		if r.config.Requests > 0 && requestNumber > int64(r.config.Requests) {
+			r.started.Add(-1)
+			return
+		}
+		r.execute(requestNumber, generator)
+	}
+}
+
+func (r *runner) execute(requestNumber int64, generator *Generator) {
+	inputData, err := generator.Generate(r.input)
+	if err != nil {
+		r.recordError()
+		return
+	}
+	start := time.Now()
+	result := r.client.Execute(
+		schema.Input{
+			RunID:     fmt.Sprintf("loadtest-%d", requestNumber),
+			ID:        r.stepID,
+			InputData: inputData,
+		},
+		nil,
+		nil,
+	)
+	latency := time.Since(start)
+	if result.Error != nil {
+		r.recordError()
+		return
+	}
+	r.latency.Record(latency)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.outputs[result.OutputID]++
+}
+
+func (r *runner) recordError() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.errors++
+}
@@ -0,0 +1,239 @@
+package loadtest_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/atp/loadtest"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type loadTestInput struct {
+	Name   string           `json:"name"`
+	Count  int64            `json:"count"`
+	Ratio  *float64         `json:"ratio"`
+	Color  string           `json:"color"`
+	Tags   []string         `json:"tags"`
+	Labels map[string]int64 `json:"labels"`
+}
+
+type loadTestOutput struct {
+	Message string `json:"message"`
+}
+
+func newLoadTestProperty(t schema.Type, required bool) *schema.PropertySchema {
+	return schema.NewPropertySchema(t, nil, required, nil, nil, nil, nil, nil)
+}
+
+var loadTestInputSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[loadTestInput](
+		"Input",
+		map[string]*schema.PropertySchema{
+			"name": newLoadTestProperty(
+				schema.NewStringSchema(schema.IntPointer(1), schema.IntPointer(16), nil),
+				true,
+			),
+			"count": newLoadTestProperty(
+				schema.NewIntSchema(schema.IntPointer(1), schema.IntPointer(10), nil),
+				true,
+			),
+			"ratio": newLoadTestProperty(
+				schema.NewFloatSchema(schema.PointerTo(0.0), schema.PointerTo(1.0), nil),
+				false,
+			),
+			"color": newLoadTestProperty(
+				schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+					"red":   schema.NewDisplayValue(schema.PointerTo("Red"), nil, nil),
+					"green": schema.NewDisplayValue(schema.PointerTo("Green"), nil, nil),
+				}),
+				true,
+			),
+			"tags": newLoadTestProperty(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), schema.IntPointer(1), schema.IntPointer(3)),
+				true,
+			),
+			"labels": newLoadTestProperty(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil),
+				false,
+			),
+		},
+	),
+)
+
+var loadTestSchema = schema.NewCallableSchema(
+	schema.NewCallableStep[loadTestInput](
+		"greet",
+		loadTestInputSchema,
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(
+					schema.NewStructMappedObjectSchema[loadTestOutput](
+						"Output",
+						map[string]*schema.PropertySchema{
+							"message": newLoadTestProperty(schema.NewStringSchema(nil, nil, nil), true),
+						},
+					),
+				),
+				nil,
+				false,
+			),
+		},
+		nil,
+		func(_ context.Context, input loadTestInput) (string, any) {
+			return "success", loadTestOutput{
+				Message: fmt.Sprintf("Hello, %s (%d)!", input.Name, input.Count),
+			}
+		},
+	),
+)
+
+type channel struct {
+	io.Reader
+	io.Writer
+	cancel func()
+}
+
+func (c channel) Close() error {
+	c.cancel()
+	return nil
+}
+
+func runWithServer(t *testing.T, config loadtest.Config) (*loadtest.Result, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, loadTestSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+	cli := atp.NewClientWithLogger(channel{stdoutReader, stdinWriter, cancel}, log.NewTestLogger(t))
+	result, err := loadtest.Run(context.Background(), cli, config)
+	assert.NoError(t, cli.Close())
+	wg.Wait()
+	return result, err
+}
+
+func TestRunRequests(t *testing.T) {
+	result, err := runWithServer(t, loadtest.Config{
+		Concurrency: 4,
+		Requests:    20,
+		Seed:        1,
+	})
+	assert.NoError(t, err)
+	assert.Equals(t, result.Requests, 20)
+	assert.Equals(t, result.Errors, 0)
+	assert.Equals(t, result.Outputs, map[string]int{"success": 20})
+	assert.Equals(t, result.Latency.Count(), uint64(20))
+	assert.Equals(t, result.Latency.Min() <= result.Latency.Percentile(50), true)
+	assert.Equals(t, result.Latency.Percentile(100), result.Latency.Max())
+	assert.Equals(t, result.Throughput() > 0, true)
+}
+
+func TestResultThroughput(t *testing.T) {
+	result := &loadtest.Result{Requests: 10, Errors: 4, Elapsed: 2 * time.Second}
+	assert.Equals(t, result.Throughput(), 3.0)
+}
+
+func TestRunRateAndDuration(t *testing.T) {
+	result, err := runWithServer(t, loadtest.Config{
+		StepID:      "greet",
+		Concurrency: 2,
+		Rate:        100,
+		Duration:    100 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	assert.Equals(t, result.Errors, 0)
+	// The rate allows at most 10 executions in 100 milliseconds.
+	assert.Equals(t, result.Requests > 0, true)
+	assert.Equals(t, result.Requests <= 10, true)
+	assert.Equals(t, uint64(result.Outputs["success"]), result.Latency.Count())
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	_, err := loadtest.Run(context.Background(), nil, loadtest.Config{})
+	assert.Error(t, err)
+	_, err = loadtest.Run(context.Background(), nil, loadtest.Config{Requests: 1, Concurrency: -1})
+	assert.Error(t, err)
+	_, err = loadtest.Run(context.Background(), nil, loadtest.Config{Requests: 1, Rate: 2e9})
+	assert.Error(t, err)
+}
+
+func TestRunUnknownStep(t *testing.T) {
+	_, err := runWithServer(t, loadtest.Config{StepID: "nonexistent", Requests: 1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nonexistent")
+}
+
+func TestGenerator(t *testing.T) {
+	oneOf := schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{
+			"a": schema.NewObjectSchema("A", map[string]*schema.PropertySchema{
+				"x": newLoadTestProperty(schema.NewIntSchema(nil, nil, nil), true),
+			}),
+			"b": schema.NewObjectSchema("B", map[string]*schema.PropertySchema{
+				"y": newLoadTestProperty(schema.NewBoolSchema(), true),
+			}),
+		},
+		"_type",
+		false,
+	)
+	types := []schema.Type{
+		loadTestInputSchema,
+		oneOf,
+		schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{
+			1: schema.NewDisplayValue(schema.PointerTo("One"), nil, nil),
+		}, nil),
+		schema.NewListSchema(schema.NewAnySchema(), schema.IntPointer(2), nil),
+	}
+	for _, typeToGenerate := range types {
+		generator := loadtest.NewGenerator(42)
+		for i := 0; i < 20; i++ {
+			data, err := generator.Generate(typeToGenerate)
+			assert.NoError(t, err)
+			_, err = typeToGenerate.Unserialize(data)
+			assert.NoError(t, err)
+		}
+	}
+
+	// The same seed produces the same data.
+	first := assert.NoErrorR[any](t)(loadtest.NewGenerator(7).Generate(loadTestInputSchema))
+	second := assert.NoErrorR[any](t)(loadtest.NewGenerator(7).Generate(loadTestInputSchema))
+	assert.Equals(t, first, second)
+}
+
+func TestGeneratorUnsupported(t *testing.T) {
+	_, err := loadtest.NewGenerator(1).Generate(schema.NewStringSchema(nil, nil, regexp.MustCompile("^a+$")))
+	assert.Error(t, err)
+}
+
+func TestHistogram(t *testing.T) {
+	h := loadtest.NewHistogram()
+	assert.Equals(t, h.Percentile(99), time.Duration(0))
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equals(t, h.Count(), uint64(100))
+	assert.Equals(t, h.Min(), time.Millisecond)
+	assert.Equals(t, h.Max(), 100*time.Millisecond)
+	assert.Equals(t, h.Mean(), 50500*time.Microsecond)
+	// 50ms falls into the bucket bounded by 10µs * 2^13 = 81.92ms.
+	assert.Equals(t, h.Percentile(50), 81920*time.Microsecond)
+	assert.Equals(t, h.Percentile(100), 100*time.Millisecond)
+
+	var total uint64
+	for _, bucket := range h.Buckets() {
+		total += bucket.Count
+	}
+	assert.Equals(t, total, uint64(100))
+}
@@ -0,0 +1,41 @@
+package atp
+
+import (
+	"sync"
+)
+
+// maxPooledMessageSize is the capacity above which message buffers are not returned to the pool, so a single large
+// message doesn't keep its memory alive for the rest of the session.
+const maxPooledMessageSize = 64 * 1024
+
+// runtimeMessagePool holds the runtime messages the read loops decode into. The decoder appends the data of the
+// message to its existing buffer, so a reused message avoids allocating the data of every message.
+var runtimeMessagePool = sync.Pool{
+	New: func() any {
+		return &DecodedRuntimeMessage{}
+	},
+}
+
+// getDecodedRuntimeMessage returns an empty runtime message from the pool. The message must be passed to
+// putDecodedRuntimeMessage once its data has been unmarshalled, and its data must not be kept.
+func getDecodedRuntimeMessage() *DecodedRuntimeMessage {
+	message := runtimeMessagePool.Get().(*DecodedRuntimeMessage)
+	// Fields missing in the next message must not keep their old value.
+	*message = DecodedRuntimeMessage{RawMessageData: message.RawMessageData[:0]}
+	return message
+}
+
+// putDecodedRuntimeMessage returns the message to the pool.
+func putDecodedRuntimeMessage(message *DecodedRuntimeMessage) {
+	if cap(message.RawMessageData) > maxPooledMessageSize {
+		message.RawMessageData = nil
+	}
+	runtimeMessagePool.Put(message)
+}
+
+// sendResultPool holds the channels encodeWithTimeout receives the result of the write on.
+var sendResultPool = sync.Pool{
+	New: func() any {
+		return make(chan error, 1)
+	},
+}
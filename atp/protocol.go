@@ -11,11 +11,43 @@ const ProtocolVersion int64 = 3
 type HelloMessage struct {
 	Version int64 `cbor:"version"`
 	Schema  any   `cbor:"schema"`
+	// SessionToken identifies the session for resumption. It is only sent if the client asked for a resumable
+	// session and the server supports it.
+	SessionToken string `cbor:"session_token,omitempty"`
+	// Resumed indicates that the session given in the start message was resumed.
+	Resumed bool `cbor:"resumed,omitempty"`
+	// LastReceived is the sequence number up to which the server received all runtime messages of the client in the
+	// resumed session. The client sends all later messages again.
+	LastReceived uint64 `cbor:"last_received,omitempty"`
+}
+
+// StartMessage is the first message the client sends. Clients that don't support session resumption send an empty
+// message instead.
+type StartMessage struct {
+	// Resumable asks the server to keep the session alive if the connection fails, so it can be resumed.
+	Resumable bool `cbor:"resumable,omitempty"`
+	// SessionToken is the token of the session to resume. It is empty when starting a new session.
+	SessionToken string `cbor:"session_token,omitempty"`
+	// LastReceived is the sequence number up to which the client received all runtime messages in the session to
+	// resume. The server sends all later messages again.
+	LastReceived uint64 `cbor:"last_received,omitempty"`
+	// CompactSchema asks the server to leave the documentation out of the schema in the hello message, as described by
+	// schema.CompactSerializeOptions. Servers that don't support it send the full schema.
+	CompactSchema bool `cbor:"compact_schema,omitempty"`
+	// Engine announces the capabilities of the engine. The server leaves the steps and properties the engine doesn't
+	// meet the requirements of out of the schema, and refuses to run them. Servers that don't support it offer all
+	// steps and properties.
+	Engine *schema.EngineCapabilities `cbor:"engine,omitempty"`
+	// Summaries asks the server to send a summary of each large list in the step outputs along with the output, as
+	// described by schema.SummaryOptions. Servers that don't support it send the outputs only.
+	Summaries *schema.SummaryOptions `cbor:"summaries,omitempty"`
 }
 
 type WorkStartMessage struct {
 	StepID string `cbor:"id"`
 	Config any    `cbor:"config"`
+	// Labels identify who the step runs for, such as the tenant or namespace of the workflow owner.
+	Labels map[string]string `cbor:"labels,omitempty"`
 }
 
 // All messages that can be contained in a RuntimeMessage struct.
@@ -25,18 +57,51 @@ const (
 	MessageTypeSignal     uint32 = 3
 	MessageTypeClientDone uint32 = 4
 	MessageTypeError      uint32 = 5
+	MessageTypeAck        uint32 = 6
+	// MessageTypeUnsupported is the reply to a runtime message of a type the receiver doesn't know.
+	MessageTypeUnsupported uint32 = 7
 )
 
+// serverMessageTypes lists the runtime message types the server handles.
+var serverMessageTypes = []uint32{
+	MessageTypeWorkStart,
+	MessageTypeSignal,
+	MessageTypeClientDone,
+	MessageTypeAck,
+	MessageTypeUnsupported,
+}
+
+// clientMessageTypes lists the runtime message types the client handles.
+var clientMessageTypes = []uint32{
+	MessageTypeWorkDone,
+	MessageTypeSignal,
+	MessageTypeError,
+	MessageTypeAck,
+	MessageTypeUnsupported,
+}
+
 type RuntimeMessage struct {
 	MessageID   uint32 `cbor:"id"`
 	RunID       string `cbor:"run_id"`
 	MessageData any    `cbor:"data"`
+	// Sequence numbers the work start, signal, work done and error messages each side sends in a resumable session,
+	// starting at 1. The sender keeps each numbered message until the receiver acknowledges it, so it can be sent
+	// again after the session is resumed. It is 0 for all other messages and outside resumable sessions.
+	Sequence uint64 `cbor:"seq,omitempty"`
 }
 
 type DecodedRuntimeMessage struct {
 	MessageID      uint32          `cbor:"id"`
 	RunID          string          `cbor:"run_id"`
 	RawMessageData cbor.RawMessage `cbor:"data"`
+	Sequence       uint64          `cbor:"seq,omitempty"`
+}
+
+// AckMessage confirms the receipt of the runtime messages of a resumable session, so the other side no longer needs to
+// keep them for resending.
+type AckMessage struct {
+	// LastReceived is the sequence number up to which all runtime messages were received.
+	LastReceived uint64 `cbor:"last_received"`
 }
 
 type WorkDoneMessage struct {
@@ -44,6 +109,25 @@ type WorkDoneMessage struct {
 	OutputID   string `cbor:"output_id"`
 	OutputData any    `cbor:"output_data"`
 	DebugLogs  string `cbor:"debug_logs"`
+	// Truncations lists the values of the output data that were truncated according to the truncation policy of the
+	// output.
+	Truncations []schema.Truncation `cbor:"truncations,omitempty"`
+	// Summaries holds the summaries of the large lists of the output data, if the client requested them. The lists
+	// are summarized before they are truncated.
+	Summaries []schema.ListSummary `cbor:"summaries,omitempty"`
+	// Metrics holds the timings and payload sizes of the step execution, as described by schema.DescribeStepMetrics.
+	// Servers that don't support it leave it empty.
+	Metrics *schema.StepMetrics `cbor:"metrics,omitempty"`
+}
+
+// UnsupportedMessage is the reply to a runtime message of a type the receiver doesn't know, for example because the
+// sender implements a newer version of the protocol. The receiver ignores the message and the session continues, so
+// the sender can fall back to what the receiver supports.
+type UnsupportedMessage struct {
+	// MessageID is the type of the message that was not understood.
+	MessageID uint32 `cbor:"message_id"`
+	// Supported lists the runtime message types the receiver handles.
+	Supported []uint32 `cbor:"supported"`
 }
 
 type SignalMessage struct {
@@ -9,6 +9,7 @@ import (
 	"go.flow.arcalot.io/pluginsdk/atp"
 	"go.flow.arcalot.io/pluginsdk/schema"
 	"io"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -197,6 +198,540 @@ func TestProtocol_Client_Execute(t *testing.T) {
 	wg.Wait()
 }
 
+func TestProtocol_Server_Events(t *testing.T) {
+	// The server reports the lifecycle of the steps it runs in order.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	events := make(chan atp.StepEvent, 10)
+	serverDone := make(chan struct{})
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServerWithEvents(
+			ctx,
+			stdinReader,
+			stdoutWriter,
+			helloWorldSchema,
+			events,
+		)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, nil, nil)
+	assert.NoError(t, result.Error)
+	assert.NoError(t, cli.Close())
+	<-serverDone
+	close(events)
+
+	var received []atp.StepEvent
+	for event := range events {
+		received = append(received, event)
+	}
+	assert.Equals(t, len(received), 3)
+	assert.Equals(t, received[0].Type, atp.StepEventStarted)
+	assert.Equals(t, received[0].RunID, t.Name())
+	assert.Equals(t, received[0].StepID, "hello-world")
+	assert.Equals(t, received[1].Type, atp.StepEventOutput)
+	assert.Equals(t, received[1].ID, "success")
+	assert.Equals(t, received[2], atp.StepEvent{
+		Type:    atp.StepEventFinished,
+		RunID:   t.Name(),
+		StepID:  "hello-world",
+		Metrics: result.Metrics,
+	})
+	// The metrics are measured without any help from the step and sent to the client.
+	assert.NotNil(t, result.Metrics)
+	assert.Equals(t, *received[1].Metrics, *result.Metrics)
+	assert.Equals(t, result.Metrics.InputBytes > 0, true)
+	assert.Equals(t, result.Metrics.OutputBytes > 0, true)
+}
+
+func TestProtocol_Client_Execute_Labels(t *testing.T) {
+	// The labels of the invocation reach the step and the step events.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	events := make(chan atp.StepEvent, 10)
+	serverDone := make(chan struct{})
+	labelsSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			helloWorldSchema.StepsValue["hello-world"].Outputs(),
+			nil,
+			func(ctx context.Context, input helloWorldInput) (string, any) {
+				return "success", helloWorldOutput{
+					Message: fmt.Sprintf("Hello, %s from %s!", input.Name, schema.LabelsFromContext(ctx)["tenant"]),
+				}
+			},
+		),
+	)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServerWithEvents(ctx, stdinReader, stdoutWriter, labelsSchema, events)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	labels := map[string]string{"tenant": "arcalot"}
+	result := cli.Execute(
+		schema.Input{
+			RunID:     "invalid",
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+			Labels:    map[string]string{"": "arcalot"},
+		}, nil, nil)
+	assert.Error(t, result.Error)
+	result = cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+			Labels:    labels,
+		}, nil, nil)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputData.(map[any]any)["message"].(string), "Hello, Arca Lot from arcalot!")
+	assert.NoError(t, cli.Close())
+	<-serverDone
+	close(events)
+
+	received := 0
+	for event := range events {
+		assert.Equals(t, event.Labels, labels)
+		received++
+	}
+	assert.Equals(t, received, 3)
+}
+
+func TestProtocol_Client_Execute_Cleanup(t *testing.T) {
+	// The cleanup report of the step reaches the client in the debug logs.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan struct{})
+	cleanupSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			helloWorldSchema.StepsValue["hello-world"].Outputs(),
+			nil,
+			func(ctx context.Context, input helloWorldInput) (string, any) {
+				schema.OnCleanup(ctx, func(ctx context.Context) error {
+					return nil
+				})
+				return "success", helloWorldOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+			},
+		),
+	)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, cleanupSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, nil, nil)
+	assert.NoError(t, result.Error)
+	assert.Contains(t, result.DebugLogs, "Cleanup 1 of 1 succeeded.")
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Client_Execute_Truncation(t *testing.T) {
+	// Oversized outputs are truncated according to the policy of the output and the client learns about it.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan struct{})
+	outputSchema := *helloWorldSchema.StepsValue["hello-world"].Outputs()["success"]
+	truncatingSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			map[string]*schema.StepOutputSchema{
+				"success": outputSchema.Truncate(schema.TruncationPolicy{MaxStringLength: schema.PointerTo(int64(5))}),
+			},
+			nil,
+			func(ctx context.Context, input helloWorldInput) (string, any) {
+				return "success", helloWorldOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+			},
+		),
+	)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, truncatingSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, nil, nil)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputData.(map[any]any)["message"].(string), "Hello")
+	assert.Equals(t, result.Truncations, []schema.Truncation{
+		{Path: "/message", Truncated: true, OriginalSize: 16},
+	})
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Client_Execute_Summaries(t *testing.T) {
+	// Engines that request summaries receive one for each large list of the output.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan struct{})
+	listOutput := schema.NewScopeSchema(schema.NewObjectSchema("Output", map[string]*schema.PropertySchema{
+		"sizes": schema.NewPropertySchema(
+			schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}))
+	listSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"list",
+			helloWorldInputSchema,
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(listOutput, nil, false),
+			},
+			nil,
+			func(ctx context.Context, input helloWorldInput) (string, any) {
+				return "success", map[string]any{"sizes": []int64{1, 2, 3, 4, 5, 6}}
+			},
+		),
+	)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, listSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	cli.(atp.OutputSummaryRequester).RequestOutputSummaries(schema.SummaryOptions{MinItems: 5, FirstItems: 2})
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "list",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, nil, nil)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, len(result.OutputData.(map[any]any)["sizes"].([]any)), 6)
+	assert.Equals(t, len(result.Summaries), 1)
+	summary := result.Summaries[0]
+	assert.Equals(t, summary.Path, "/sizes")
+	assert.Equals(t, summary.Count, int64(6))
+	assert.Equals(t, len(summary.First), 2)
+	assert.Equals(t, summary.Numeric[""], schema.NumericSummary{Count: 6, Min: 1, Max: 6, Mean: 3.5})
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Server_UnsupportedMessage(t *testing.T) {
+	// Unknown message types are answered with the supported types, and the session continues.
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan []*atp.ServerError, 1)
+	go func() {
+		serverDone <- atp.RunATPServer(context.Background(), stdinReader, stdoutWriter, helloWorldSchema)
+	}()
+	encoder := cbor.NewEncoder(stdinWriter)
+	decoder := cbor.NewDecoder(stdoutReader)
+	assert.NoError(t, encoder.Encode(atp.StartMessage{}))
+	var hello atp.HelloMessage
+	assert.NoError(t, decoder.Decode(&hello))
+
+	assert.NoError(t, encoder.Encode(atp.RuntimeMessage{MessageID: 99, RunID: t.Name(), MessageData: "future"}))
+	var reply atp.DecodedRuntimeMessage
+	assert.NoError(t, decoder.Decode(&reply))
+	assert.Equals(t, reply.MessageID, atp.MessageTypeUnsupported)
+	assert.Equals(t, reply.RunID, t.Name())
+	var unsupported atp.UnsupportedMessage
+	assert.NoError(t, cbor.Unmarshal(reply.RawMessageData, &unsupported))
+	assert.Equals(t, unsupported.MessageID, uint32(99))
+	assert.Equals(t, slices.Contains(unsupported.Supported, atp.MessageTypeWorkStart), true)
+
+	assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+		MessageID:   atp.MessageTypeWorkStart,
+		RunID:       t.Name(),
+		MessageData: atp.WorkStartMessage{StepID: "hello-world", Config: map[string]any{"name": "Arca Lot"}},
+	}))
+	var done atp.DecodedRuntimeMessage
+	assert.NoError(t, decoder.Decode(&done))
+	assert.Equals(t, done.MessageID, atp.MessageTypeWorkDone)
+	assert.NoError(t, encoder.Encode(atp.RuntimeMessage{MessageID: atp.MessageTypeClientDone}))
+	assert.Equals(t, len(<-serverDone), 0)
+}
+
+func TestProtocol_Client_UnsupportedMessage(t *testing.T) {
+	// The client answers unknown message types from a newer server and keeps waiting for the result.
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serializedSchema := assert.NoErrorR[any](t)(helloWorldSchema.SelfSerialize())
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		decoder := cbor.NewDecoder(stdinReader)
+		encoder := cbor.NewEncoder(stdoutWriter)
+		var start any
+		assert.NoError(t, decoder.Decode(&start))
+		assert.NoError(t, encoder.Encode(atp.HelloMessage{Version: atp.ProtocolVersion, Schema: serializedSchema}))
+		var workStart atp.DecodedRuntimeMessage
+		assert.NoError(t, decoder.Decode(&workStart))
+		assert.NoError(t, encoder.Encode(atp.RuntimeMessage{MessageID: 99, RunID: workStart.RunID}))
+		var reply atp.DecodedRuntimeMessage
+		assert.NoError(t, decoder.Decode(&reply))
+		assert.Equals(t, reply.MessageID, atp.MessageTypeUnsupported)
+		var unsupported atp.UnsupportedMessage
+		assert.NoError(t, cbor.Unmarshal(reply.RawMessageData, &unsupported))
+		assert.Equals(t, unsupported.MessageID, uint32(99))
+		assert.Equals(t, slices.Contains(unsupported.Supported, atp.MessageTypeWorkDone), true)
+		assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+			MessageID: atp.MessageTypeWorkDone,
+			RunID:     workStart.RunID,
+			MessageData: atp.WorkDoneMessage{
+				StepID:     "hello-world",
+				OutputID:   "success",
+				OutputData: map[string]any{"message": "Hello, Arca Lot!"},
+			},
+		}))
+		var clientDone atp.DecodedRuntimeMessage
+		assert.NoError(t, decoder.Decode(&clientDone))
+		assert.Equals(t, clientDone.MessageID, atp.MessageTypeClientDone)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader: stdoutReader,
+		Writer: stdinWriter,
+		cancel: func() {},
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(
+		schema.Input{RunID: t.Name(), ID: "hello-world", InputData: map[string]any{"name": "Arca Lot"}},
+		nil,
+		nil,
+	)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputID, "success")
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Client_ReadCompactSchema(t *testing.T) {
+	// The client asks for the schema without documentation and can still execute steps with it.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan struct{})
+	documentedSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			helloWorldSchema.StepsValue["hello-world"].Outputs(),
+			schema.NewDisplayValue(schema.PointerTo("Hello world"), schema.PointerTo("Greets the world."), nil),
+			func(ctx context.Context, input helloWorldInput) (string, any) {
+				return "success", helloWorldOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+			},
+		),
+	)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, documentedSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	pluginSchema, err := cli.(atp.CompactSchemaReader).ReadCompactSchema()
+	assert.NoError(t, err)
+	assert.Nil(t, pluginSchema.StepsValue["hello-world"].DisplayValue)
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, nil, nil)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputID, "success")
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Client_AnnounceEngine(t *testing.T) {
+	// Steps the engine doesn't meet the requirements of are not offered, and refused if they are executed anyway.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan struct{})
+	modernStep := schema.NewCallableStep[helloWorldInput](
+		"modern-hello-world",
+		helloWorldInputSchema,
+		helloWorldSchema.StepsValue["hello-world"].Outputs(),
+		nil,
+		func(ctx context.Context, input helloWorldInput) (string, any) {
+			return "success", helloWorldOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+		},
+	)
+	modernStep.(*schema.CallableStepSchema[any, helloWorldInput]).RequireEngine(schema.EngineRequirements{
+		Features: []string{"modern"},
+	})
+	gatedSchema := schema.NewCallableSchema(helloWorldSchema.StepsValue["hello-world"], modernStep)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, gatedSchema)
+		assert.Equals(t, len(errors), 1)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	cli.(atp.EngineAnnouncer).AnnounceEngine(schema.EngineCapabilities{Features: []string{"classic"}})
+	pluginSchema, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	_, offered := pluginSchema.StepsValue["modern-hello-world"]
+	assert.Equals(t, offered, false)
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "modern-hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, nil, nil)
+	assert.Error(t, result.Error)
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Client_Execute_TwoPhase(t *testing.T) {
+	// The plan of a two-phase step reaches the client, which approves it with the confirm signal.
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan struct{})
+	twoPhaseSchema := schema.NewCallableSchema(
+		schema.NewTwoPhaseStep(
+			"hello-world",
+			helloWorldInputSchema,
+			helloWorldInputSchema,
+			helloWorldSchema.StepsValue["hello-world"].Outputs(),
+			nil,
+			func(_ context.Context, input helloWorldInput) (helloWorldInput, error) {
+				return input, nil
+			},
+			func(_ context.Context, _ helloWorldInput, plan helloWorldInput) (string, any) {
+				return "success", helloWorldOutput{Message: fmt.Sprintf("Hello, %s!", plan.Name)}
+			},
+		),
+	)
+
+	go func() {
+		defer close(serverDone)
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, twoPhaseSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	toStepChan := make(chan schema.Input, 1)
+	fromStepChan := make(chan schema.Input, 1)
+	go func() {
+		plan := <-fromStepChan
+		assert.Equals(t, plan.ID, schema.TwoPhasePlanSignalID)
+		assert.Equals(t, plan.InputData.(map[any]any)["name"].(string), "Arca Lot")
+		toStepChan <- schema.Input{
+			RunID:     t.Name(),
+			ID:        schema.TwoPhaseConfirmSignalID,
+			InputData: map[string]any{"approve": true},
+		}
+	}()
+	result := cli.Execute(
+		schema.Input{
+			RunID:     t.Name(),
+			ID:        "hello-world",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}, toStepChan, fromStepChan)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputID, "success")
+	assert.Equals(t, result.OutputData.(map[any]any)["message"].(string), "Hello, Arca Lot!")
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
 func TestProtocol_Client_Execute_With_Signals(t *testing.T) {
 	testExecuteWithChannels(true, t)
 }
@@ -964,3 +1499,84 @@ func newATPServer(
 		cbor.NewEncoder(channel),
 	}
 }
+
+func BenchmarkProtocol_Signal_Throughput(b *testing.B) {
+	received := make(chan struct{}, 128)
+	signal := schema.NewCallableSignal(
+		"hello-world-signal",
+		helloWorldInputSchema,
+		nil,
+		func(_ context.Context, _ any, _ helloWorldInput) {
+			received <- struct{}{}
+		},
+	)
+	signalSchema := schema.NewCallableSchema(
+		schema.NewCallableStepWithSignals[any, helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			map[string]*schema.StepOutputSchema{
+				"success": helloWorldSchema.StepsValue["hello-world"].Outputs()["success"],
+			},
+			map[string]schema.CallableSignal{
+				"hello-world-signal": signal,
+			},
+			nil,
+			nil,
+			// The signal handlers get the data of the initializer.
+			func() any { return b.N },
+			func(ctx context.Context, data any, input helloWorldInput) (string, any) {
+				for i := 0; i < b.N; i++ {
+					<-received
+				}
+				return helloWorldStepHandler(ctx, data, input)
+			},
+		),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serverDone := make(chan []*atp.ServerError, 1)
+	go func() {
+		serverDone <- atp.RunATPServer(ctx, stdinReader, stdoutWriter, signalSchema)
+	}()
+	cli := atp.NewClientWithLogger(channel{
+		Reader:  stdoutReader,
+		Writer:  stdinWriter,
+		Context: nil,
+		cancel:  cancel,
+	}, log.NewLogger(log.LevelError, log.NewNOOPLogger()))
+	if _, err := cli.ReadSchema(); err != nil {
+		b.Fatal(err)
+	}
+	toStepChan := make(chan schema.Input)
+	resultChan := make(chan atp.ExecutionResult, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	go func() {
+		resultChan <- cli.Execute(
+			schema.Input{RunID: "bench", ID: "hello-world", InputData: map[string]any{"name": "Arca Lot"}},
+			toStepChan,
+			nil,
+		)
+	}()
+	for i := 0; i < b.N; i++ {
+		toStepChan <- schema.Input{
+			RunID:     "bench",
+			ID:        "hello-world-signal",
+			InputData: map[string]any{"name": "Arca Lot"},
+		}
+	}
+	close(toStepChan)
+	result := <-resultChan
+	b.StopTimer()
+	if result.Error != nil {
+		b.Fatal(result.Error)
+	}
+	if err := cli.Close(); err != nil {
+		b.Fatal(err)
+	}
+	if errs := <-serverDone; len(errs) != 0 {
+		b.Fatal(errs)
+	}
+}
@@ -1,9 +1,12 @@
 package atp
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/fxamacker/cbor/v2"
+	"go.flow.arcalot.io/pluginsdk/clock"
 	"go.flow.arcalot.io/pluginsdk/schema"
 	"io"
 	"os"
@@ -17,8 +20,34 @@ func RunATPServer(
 	stdin io.ReadCloser,
 	stdout io.WriteCloser,
 	pluginSchema *schema.CallableSchema,
+) []*ServerError {
+	return runATPServer(ctx, stdin, stdout, pluginSchema, nil, nil)
+}
+
+// RunATPServerWithEvents runs an ATP server like RunATPServer, and sends the lifecycle events of the steps it runs to
+// the given channel. The channel must be read until the server returns, since the server waits for each event to be
+// received.
+func RunATPServerWithEvents(
+	ctx context.Context,
+	stdin io.ReadCloser,
+	stdout io.WriteCloser,
+	pluginSchema *schema.CallableSchema,
+	events chan<- StepEvent,
+) []*ServerError {
+	return runATPServer(ctx, stdin, stdout, pluginSchema, nil, newStepEventEmitter(events))
+}
+
+func runATPServer(
+	ctx context.Context,
+	stdin io.ReadCloser,
+	stdout io.WriteCloser,
+	pluginSchema *schema.CallableSchema,
+	sessions *ServerSessions,
+	events *stepEventEmitter,
 ) []*ServerError {
 	session := initializeATPServerSession(ctx, stdin, stdout, pluginSchema)
+	session.sessions = sessions
+	session.events = events
 	session.wg.Add(1)
 
 	// Run needs to be run in its own goroutine to allow for the closure handling to happen simultaneously.
@@ -30,6 +59,9 @@ func RunATPServer(
 
 	// Ensure that the session is done.
 	session.wg.Wait()
+	if session.resumable != nil {
+		workError = append(workError, session.resumable.takeErrors()...)
+	}
 	return workError
 }
 
@@ -39,11 +71,35 @@ type atpServerSession struct {
 	stdinCloser    io.ReadCloser
 	cborStdin      *cbor.Decoder
 	cborStdout     *cbor.Encoder
-	runningSteps   map[string]string // Maps run ID to step ID
+	runningSteps   map[string]runningStep // Key: run ID
 	workDone       chan ServerError
 	runDoneChannel chan bool
 	pluginSchema   *schema.CallableSchema
 	encoderMutex   sync.Mutex
+
+	// sessions holds the resumable sessions if the server supports resumption.
+	sessions *ServerSessions
+	// resumable is the session this connection is attached to, if the client asked for a resumable session.
+	resumable *resumableSession
+	// connection identifies this connection in the resumable session.
+	connection uint64
+	// stepCtx and stepsWg are used for running steps and signal handlers. They belong to the resumable session if
+	// there is one, since the steps outlive the connection.
+	stepCtx          context.Context
+	stepsWg          *sync.WaitGroup
+	runningStepsLock *sync.Mutex
+	// events receives the lifecycle events of the steps, if requested.
+	events *stepEventEmitter
+	// engine holds the capabilities the client announced in the start message, if any.
+	engine *schema.EngineCapabilities
+	// summaries holds the summary options the client requested in the start message, if any.
+	summaries *schema.SummaryOptions
+}
+
+// runningStep is a step started on the server, which signals can be sent to.
+type runningStep struct {
+	stepID string
+	labels map[string]string
 }
 
 type ServerError struct {
@@ -69,39 +125,74 @@ func initializeATPServerSession(
 	cborStdout := cbor.NewEncoder(stdout)
 	runDoneChannel := make(chan bool, 3) // Buffer to prevent it from hanging if something unexpected happens.
 
+	wg := &sync.WaitGroup{}
 	return &atpServerSession{
-		ctx:            ctx,
-		cborStdin:      cborStdin,
-		stdinCloser:    stdin,
-		cborStdout:     cborStdout,
-		workDone:       workDone,
-		runDoneChannel: runDoneChannel,
-		pluginSchema:   pluginSchema,
-		wg:             &sync.WaitGroup{},
-		runningSteps:   make(map[string]string),
+		ctx:              ctx,
+		cborStdin:        cborStdin,
+		stdinCloser:      stdin,
+		cborStdout:       cborStdout,
+		workDone:         workDone,
+		runDoneChannel:   runDoneChannel,
+		pluginSchema:     pluginSchema,
+		wg:               wg,
+		runningSteps:     make(map[string]runningStep),
+		stepCtx:          ctx,
+		stepsWg:          wg,
+		runningStepsLock: &sync.Mutex{},
 	}
 }
 
 func (s *atpServerSession) sendRuntimeMessage(msgID uint32, runID string, message any) error {
+	if s.resumable != nil {
+		s.resumable.send(msgID, runID, message)
+		return nil
+	}
 	s.encoderMutex.Lock()
-	doneChannel := make(chan error, 1)
+	defer s.encoderMutex.Unlock()
+	return encodeWithTimeout(clock.FromContext(s.ctx), s.cborStdout, RuntimeMessage{
+		MessageID:   msgID,
+		RunID:       runID,
+		MessageData: message,
+	})
+}
+
+// encodeWithTimeout writes the runtime message, giving up if the peer doesn't read it within a minute on the clock.
+func encodeWithTimeout(clk clock.Clock, encoder *cbor.Encoder, message RuntimeMessage) error {
+	doneChannel := sendResultPool.Get().(chan error)
 	go func() {
-		defer close(doneChannel)
-		doneChannel <- s.cborStdout.Encode(RuntimeMessage{
-			MessageID:   msgID,
-			RunID:       runID,
-			MessageData: message,
-		})
+		doneChannel <- encoder.Encode(message)
 	}()
-	defer s.encoderMutex.Unlock()
+	timeout := clk.NewTimer(time.Second * 60)
+	defer timeout.Stop()
 	select {
 	case err := <-doneChannel:
+		// The channel is only reused if the write finished, otherwise the write may still send on it.
+		sendResultPool.Put(doneChannel)
 		return err
-	case <-time.After(time.Second * 60):
-		return fmt.Errorf("send timeout exceeded while sending message ID %q for run id %q", msgID, runID)
+	case <-timeout.C():
+		return fmt.Errorf(
+			"send timeout exceeded while sending message ID %q for run id %q",
+			message.MessageID,
+			message.RunID,
+		)
 	}
 }
 
+// reportError reports an error of a step or signal handler. In a resumable session the error is sent directly, since
+// the step may outlive the connection that started it.
+func (s *atpServerSession) reportError(serverError ServerError) {
+	if s.resumable == nil || serverError.ServerFatal {
+		s.workDone <- serverError
+		return
+	}
+	s.resumable.recordError(serverError)
+	s.resumable.send(MessageTypeError, serverError.RunID, ErrorMessage{
+		Error:       serverError.Err.Error(),
+		StepFatal:   serverError.StepFatal,
+		ServerFatal: serverError.ServerFatal,
+	})
+}
+
 func (s *atpServerSession) handleClosure() []*ServerError {
 	// Wait for work done or context complete.
 	var errors []*ServerError
@@ -150,12 +241,18 @@ closeLoop:
 }
 
 func (s *atpServerSession) runATPReadLoop() {
-	// The message is generic, so we must find the type and decode the full message next.
-	var runtimeMessage DecodedRuntimeMessage
 	for {
+		// The message is generic, so we must find the type and decode the full message next.
+		// The message is reset before decoding, since fields missing in the message would keep their old value.
+		runtimeMessage := getDecodedRuntimeMessage()
 		// First, decode the message
 		// Note: This blocks. To abort early, close stdin.
-		if err := s.cborStdin.Decode(&runtimeMessage); err != nil {
+		if err := s.cborStdin.Decode(runtimeMessage); err != nil {
+			if s.resumable != nil {
+				// The connection failed, but the steps keep running until the client resumes the session.
+				s.resumable.detach(s.connection)
+				return
+			}
 			// Failed to decode. If it's done, that's okay. If not, there's a problem.
 			done := false
 			select {
@@ -173,7 +270,13 @@ func (s *atpServerSession) runATPReadLoop() {
 			} // If done, it didn't get the work done message, which is not ideal.
 			return
 		}
-		done := s.onRuntimeMessageReceived(&runtimeMessage)
+		if s.resumable != nil && runtimeMessage.Sequence > 0 && !s.resumable.receive(runtimeMessage.Sequence) {
+			// The client sent the message again after resuming the session, but it was already handled.
+			putDecodedRuntimeMessage(runtimeMessage)
+			continue
+		}
+		done := s.onRuntimeMessageReceived(runtimeMessage)
+		putDecodedRuntimeMessage(runtimeMessage)
 		if done {
 			return
 		}
@@ -183,58 +286,116 @@ func (s *atpServerSession) runATPReadLoop() {
 // onRuntimeMessageReceived handles the runtime message by determining what type it is, and executing the proper path.
 // Returns true if termination should be terminated, which should correspond to only client done or fatal server errors.
 func (s *atpServerSession) onRuntimeMessageReceived(message *DecodedRuntimeMessage) bool {
-	runID := message.RunID
 	switch message.MessageID {
 	case MessageTypeWorkStart:
-		var workStartMsg WorkStartMessage
-		if err := cbor.Unmarshal(message.RawMessageData, &workStartMsg); err != nil {
-			s.workDone <- ServerError{
-				RunID:       runID,
-				Err:         fmt.Errorf("failed to decode work start message: %w", err),
-				StepFatal:   true,
-				ServerFatal: false,
-			}
-			return false
-		}
-		s.handleWorkStartMessage(runID, workStartMsg)
-		return false
+		s.onWorkStartMessage(message)
 	case MessageTypeSignal:
-		var signalMessage SignalMessage
-		if err := cbor.Unmarshal(message.RawMessageData, &signalMessage); err != nil {
-			s.workDone <- ServerError{
-				RunID:       runID,
-				Err:         fmt.Errorf("failed to decode signal message: %w", err),
-				StepFatal:   false,
-				ServerFatal: false,
-			}
-			return false
-		}
-		s.handleSignalMessage(runID, signalMessage)
-
-		return false
+		s.onSignalMessage(message)
+	case MessageTypeAck:
+		s.onAckMessage(message)
 	case MessageTypeClientDone:
-		// It's now safe to close the channel
-		err := s.stdinCloser.Close()
-		if err != nil {
-			s.workDone <- ServerError{
-				// this error does not apply to a specific run id
-				RunID:       "",
-				Err:         fmt.Errorf("error while closing stdin on client done: %w", err),
-				StepFatal:   true,
-				ServerFatal: true,
-			}
-		}
+		s.onClientDoneMessage()
 		return true // Client done, so terminate loop
+	case MessageTypeUnsupported:
+		s.onUnsupportedMessage(message)
 	default:
+		s.onUnknownMessage(message)
+	}
+	return false
+}
+
+func (s *atpServerSession) onWorkStartMessage(message *DecodedRuntimeMessage) {
+	var workStartMsg WorkStartMessage
+	if err := cbor.Unmarshal(message.RawMessageData, &workStartMsg); err != nil {
+		s.workDone <- ServerError{
+			RunID:       message.RunID,
+			Err:         fmt.Errorf("failed to decode work start message: %w", err),
+			StepFatal:   true,
+			ServerFatal: false,
+		}
+		return
+	}
+	s.handleWorkStartMessage(message.RunID, workStartMsg)
+}
+
+func (s *atpServerSession) onSignalMessage(message *DecodedRuntimeMessage) {
+	var signalMessage SignalMessage
+	if err := cbor.Unmarshal(message.RawMessageData, &signalMessage); err != nil {
+		s.workDone <- ServerError{
+			RunID:       message.RunID,
+			Err:         fmt.Errorf("failed to decode signal message: %w", err),
+			StepFatal:   false,
+			ServerFatal: false,
+		}
+		return
+	}
+	s.handleSignalMessage(message.RunID, signalMessage)
+}
+
+// onAckMessage confirms the messages the client received, so a resumable session doesn't replay them.
+func (s *atpServerSession) onAckMessage(message *DecodedRuntimeMessage) {
+	var ackMessage AckMessage
+	if err := cbor.Unmarshal(message.RawMessageData, &ackMessage); err != nil {
+		s.workDone <- ServerError{
+			RunID:       "",
+			Err:         fmt.Errorf("failed to decode acknowledgement message: %w", err),
+			StepFatal:   false,
+			ServerFatal: false,
+		}
+		return
+	}
+	if s.resumable != nil {
+		s.resumable.confirm(ackMessage.LastReceived)
+	}
+}
+
+// onClientDoneMessage ends the session, since the client will not send any more messages.
+func (s *atpServerSession) onClientDoneMessage() {
+	if s.resumable != nil {
+		s.sessions.remove(s.resumable)
+	}
+	// It's now safe to close the channel
+	err := s.stdinCloser.Close()
+	if err != nil {
 		s.workDone <- ServerError{
 			// this error does not apply to a specific run id
-			RunID: "",
-			Err: fmt.Errorf("unknown message ID received: %d. This is a sign of incompatible server and client versions",
-				message.MessageID),
+			RunID:       "",
+			Err:         fmt.Errorf("error while closing stdin on client done: %w", err),
+			StepFatal:   true,
+			ServerFatal: true,
+		}
+	}
+}
+
+func (s *atpServerSession) onUnsupportedMessage(message *DecodedRuntimeMessage) {
+	var unsupportedMessage UnsupportedMessage
+	if err := cbor.Unmarshal(message.RawMessageData, &unsupportedMessage); err != nil {
+		s.workDone <- ServerError{
+			RunID:       message.RunID,
+			Err:         fmt.Errorf("failed to decode unsupported message reply: %w", err),
 			StepFatal:   false,
 			ServerFatal: false,
 		}
-		return false
+		return
+	}
+	_, _ = fmt.Fprintf(
+		os.Stderr,
+		"client does not support message type %d for run ID %q, it supports %v\n",
+		unsupportedMessage.MessageID,
+		message.RunID,
+		unsupportedMessage.Supported,
+	)
+}
+
+// onUnknownMessage replies to a message type the server doesn't know. The client may implement a newer protocol
+// version, so it is told what the server supports instead of failing the session.
+func (s *atpServerSession) onUnknownMessage(message *DecodedRuntimeMessage) {
+	err := s.sendRuntimeMessage(MessageTypeUnsupported, message.RunID, UnsupportedMessage{
+		MessageID: message.MessageID,
+		Supported: serverMessageTypes,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error while sending unsupported message reply: %s\n", err)
 	}
 }
 
@@ -249,11 +410,44 @@ func (s *atpServerSession) handleWorkStartMessage(runID string, workStartMsg Wor
 		}
 		return
 	}
-	s.runningSteps[runID] = workStartMsg.StepID
-	s.wg.Add(1) // Wait until the step is done
+	if err := schema.ValidateLabels(workStartMsg.Labels); err != nil {
+		s.workDone <- ServerError{
+			RunID:       runID,
+			Err:         fmt.Errorf("invalid labels in work start message (%w)", err),
+			StepFatal:   true,
+			ServerFatal: false,
+		}
+		return
+	}
+	if s.engine != nil {
+		if err := s.pluginSchema.CheckEngine(workStartMsg.StepID, workStartMsg.Config, *s.engine); err != nil {
+			s.workDone <- ServerError{
+				RunID:       runID,
+				Err:         fmt.Errorf("refusing to run step (%w)", err),
+				StepFatal:   true,
+				ServerFatal: false,
+			}
+			return
+		}
+	}
+	s.runningStepsLock.Lock()
+	s.runningSteps[runID] = runningStep{workStartMsg.StepID, workStartMsg.Labels}
+	s.runningStepsLock.Unlock()
+	s.events.emit(StepEvent{
+		Type:   StepEventStarted,
+		RunID:  runID,
+		StepID: workStartMsg.StepID,
+		Labels: workStartMsg.Labels,
+		Data:   workStartMsg.Config,
+	})
+	s.stepsWg.Add(1) // Wait until the step is done
 	go func() {
-		s.runStep(runID, workStartMsg)
-		s.wg.Done()
+		if s.sessions != nil && s.sessions.jobs != nil {
+			s.runQueuedStep(s.sessions.jobs, runID, workStartMsg)
+		} else {
+			s.runStep(runID, workStartMsg)
+		}
+		s.stepsWg.Done()
 	}()
 }
 
@@ -267,7 +461,9 @@ func (s *atpServerSession) handleSignalMessage(runID string, signalMessage Signa
 		}
 		return
 	}
-	stepID, found := s.runningSteps[runID]
+	s.runningStepsLock.Lock()
+	step, found := s.runningSteps[runID]
+	s.runningStepsLock.Unlock()
 	if !found {
 		s.workDone <- ServerError{
 			RunID:       runID,
@@ -277,27 +473,51 @@ func (s *atpServerSession) handleSignalMessage(runID string, signalMessage Signa
 		}
 		return
 	}
-	s.wg.Add(1) // Wait until the signal handler is done
+	s.events.emit(StepEvent{
+		Type:   StepEventSignal,
+		RunID:  runID,
+		StepID: step.stepID,
+		Labels: step.labels,
+		ID:     signalMessage.SignalID,
+		Data:   signalMessage.Data,
+	})
+	s.stepsWg.Add(1) // Wait until the signal handler is done
 	go func() {
 		if err := s.pluginSchema.CallSignal(
-			s.ctx,
+			schema.ContextWithLabels(s.stepCtx, step.labels),
 			runID,
-			stepID,
+			step.stepID,
 			signalMessage.SignalID,
 			signalMessage.Data,
 		); err != nil {
-			s.workDone <- ServerError{
+			s.reportError(ServerError{
 				RunID: runID,
 				Err: fmt.Errorf("failed while running signal ID %s: %w",
 					signalMessage.SignalID, err),
 				StepFatal:   false,
 				ServerFatal: false,
-			}
+			})
 		}
-		s.wg.Done()
+		s.stepsWg.Done()
 	}()
 }
 
+// sendStepSignal serializes a signal emitted by a running step and sends it to the client.
+func (s *atpServerSession) sendStepSignal(runID string, stepID string, signalID string, data any) error {
+	signal, ok := s.pluginSchema.StepsValue[stepID].SignalEmitters()[signalID]
+	if !ok {
+		return fmt.Errorf("step %s does not declare the emitted signal %s", stepID, signalID)
+	}
+	serializedData, err := signal.DataSchema().Serialize(data)
+	if err != nil {
+		return fmt.Errorf("invalid data for signal %s (%w)", signalID, err)
+	}
+	return s.sendRuntimeMessage(MessageTypeSignal, runID, SignalMessage{
+		SignalID: signalID,
+		Data:     serializedData,
+	})
+}
+
 func (s *atpServerSession) run() {
 	defer func() {
 		s.runDoneChannel <- true
@@ -306,6 +526,10 @@ func (s *atpServerSession) run() {
 	}()
 
 	err := s.sendInitialMessagesToClient()
+	if err != nil && s.resumable != nil {
+		// The connection failed while sending the hello message. The client can try to resume again.
+		return
+	}
 	if err != nil {
 		s.workDone <- ServerError{
 			RunID:       "",
@@ -325,33 +549,113 @@ func (s *atpServerSession) runStep(runID string, req WorkStartMessage) {
 	defer func() {
 		// Handle and properly report panics
 		if r := recover(); r != nil {
-			s.workDone <- ServerError{
-				RunID:       runID,
-				Err:         fmt.Errorf("panic while running step with Run ID '%s': (%v)", runID, r),
-				StepFatal:   true,
-				ServerFatal: false,
-			}
+			s.failStep(runID, req, fmt.Errorf("panic while running step with Run ID '%s': (%v)", runID, r), nil)
 		}
 	}()
-	outputID, outputData, err := s.pluginSchema.CallStep(s.ctx, runID, req.StepID, req.Config)
+	debugLogs := &bytes.Buffer{}
+	metrics := &schema.StepMetrics{}
+	ctx := schema.ContextWithDebugLog(schema.ContextWithLabels(s.stepCtx, req.Labels), debugLogs)
+	ctx = schema.ContextWithStepMetrics(ctx, metrics)
+	ctx = schema.ContextWithSignalEmitter(ctx, func(signalID string, data any) error {
+		return s.sendStepSignal(runID, req.StepID, signalID, data)
+	})
+	outputID, outputData, err := s.pluginSchema.CallStep(
+		ctx,
+		runID,
+		req.StepID,
+		req.Config,
+	)
 	if err != nil {
-		s.workDone <- ServerError{
-			RunID:       runID,
-			Err:         fmt.Errorf("error calling step (%w)", err),
-			StepFatal:   true,
-			ServerFatal: false,
-		}
+		s.failStep(runID, req, fmt.Errorf("error calling step (%w)", err), metrics)
 		return
 	}
+	s.finishStep(runID, req, outputID, outputData, debugLogs.String(), metrics)
+}
+
+// runQueuedStep runs the step through the job queue of the server sessions, and reports the result of the job. If the
+// queue already has a job with the run ID, for example because the plugin was restarted while the step was running,
+// the result of that job is reported instead of running the step again.
+func (s *atpServerSession) runQueuedStep(queue *JobQueue, runID string, req WorkStartMessage) {
+	if err := queue.Submit(runID, req.StepID, req.Config); err != nil && !errors.Is(err, ErrJobExists) {
+		s.failStep(runID, req, fmt.Errorf("error queueing step (%w)", err), nil)
+		return
+	}
+	job, err := queue.Wait(s.stepCtx, runID)
+	switch {
+	case err != nil:
+		s.failStep(runID, req, fmt.Errorf("error waiting for the queued step (%w)", err), nil)
+	case job.StepID != req.StepID:
+		s.failStep(runID, req, fmt.Errorf("run ID '%s' is already used by a job for step %s", runID, job.StepID), nil)
+	case job.Status == JobFailed:
+		s.failStep(runID, req, fmt.Errorf("error calling step (%s)", job.Error), nil)
+	default:
+		s.finishStep(runID, req, job.OutputID, job.OutputData, "", nil)
+	}
+}
+
+// failStep reports a step that ended without an output.
+func (s *atpServerSession) failStep(runID string, req WorkStartMessage, err error, metrics *schema.StepMetrics) {
+	s.events.emit(StepEvent{
+		Type:    StepEventFinished,
+		RunID:   runID,
+		StepID:  req.StepID,
+		Labels:  req.Labels,
+		Error:   err,
+		Metrics: metrics,
+	})
+	s.reportError(ServerError{
+		RunID:       runID,
+		Err:         err,
+		StepFatal:   true,
+		ServerFatal: false,
+	})
+}
+
+// finishStep sends the serialized output of a step to the client.
+func (s *atpServerSession) finishStep(
+	runID string,
+	req WorkStartMessage,
+	outputID string,
+	outputData any,
+	debugLogs string,
+	metrics *schema.StepMetrics,
+) {
+	var summaries []schema.ListSummary
+	if s.summaries != nil {
+		summaries = s.summaries.Summarize(outputData)
+	}
+	var truncations []schema.Truncation
+	if policy := s.pluginSchema.StepsValue[req.StepID].Outputs()[outputID].Truncation(); policy != nil {
+		outputData, truncations = policy.Apply(outputData)
+	}
+	s.events.emit(StepEvent{
+		Type:    StepEventOutput,
+		RunID:   runID,
+		StepID:  req.StepID,
+		Labels:  req.Labels,
+		ID:      outputID,
+		Data:    outputData,
+		Metrics: metrics,
+	})
+	s.events.emit(StepEvent{
+		Type:    StepEventFinished,
+		RunID:   runID,
+		StepID:  req.StepID,
+		Labels:  req.Labels,
+		Metrics: metrics,
+	})
 	// Lastly, send the work done message.
-	err = s.sendRuntimeMessage(
+	err := s.sendRuntimeMessage(
 		MessageTypeWorkDone,
 		runID,
 		WorkDoneMessage{
 			req.StepID,
 			outputID,
 			outputData,
-			"",
+			debugLogs,
+			truncations,
+			summaries,
+			metrics,
 		},
 	)
 	if err != nil {
@@ -361,23 +665,42 @@ func (s *atpServerSession) runStep(runID string, req WorkStartMessage) {
 }
 
 func (s *atpServerSession) sendInitialMessagesToClient() error {
-	// Start by serializing the schema, since the protocol requires sending the schema on the hello message.
-	serializedSchema, err := s.pluginSchema.SelfSerialize()
+	// First, the start message, which is empty unless the client supports session resumption or a compact schema.
+	var start StartMessage
+	err := s.cborStdin.Decode(&start)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to CBOR-decode start output message (%w)", err)
 	}
 
-	// First, the start message, which is just an empty message.
-	var empty any
-	err = s.cborStdin.Decode(&empty)
+	// The protocol requires sending the schema on the hello message.
+	options := schema.SerializeOptions{}
+	if start.CompactSchema {
+		options = schema.CompactSerializeOptions()
+	}
+	s.engine = start.Engine
+	s.summaries = start.Summaries
+	options.Engine = start.Engine
+	serializedSchema, err := s.pluginSchema.SelfSerializeWithOptions(options)
 	if err != nil {
-		return fmt.Errorf("failed to CBOR-decode start output message (%w)", err)
+		return err
 	}
 
 	// Next, send the hello message, which includes the version and schema.
-	err = s.cborStdout.Encode(HelloMessage{ProtocolVersion, serializedSchema})
+	hello := HelloMessage{Version: ProtocolVersion, Schema: serializedSchema}
+	if s.sessions != nil && start.Resumable {
+		session, resumed := s.sessions.open(start.SessionToken, start.LastReceived)
+		s.useSession(session)
+		hello.SessionToken = session.token
+		hello.Resumed = resumed
+		hello.LastReceived = session.lastReceived()
+	}
+	err = s.cborStdout.Encode(hello)
 	if err != nil {
 		return fmt.Errorf("failed to CBOR-encode schema (%w)", err)
 	}
+	if s.resumable != nil {
+		// Send the messages the client missed, and the following messages over this connection.
+		s.connection = s.resumable.attach(s.cborStdout, s.stdinCloser, start.LastReceived)
+	}
 	return nil
 }
@@ -0,0 +1,422 @@
+package atp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/fxamacker/cbor/v2"
+	"go.flow.arcalot.io/pluginsdk/clock"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultSessionTimeout is how long a session is kept after its connection failed, unless configured otherwise.
+const DefaultSessionTimeout = 5 * time.Minute
+
+// DefaultMaxUnconfirmedMessages is the number of messages kept for resending per session, unless configured otherwise.
+const DefaultMaxUnconfirmedMessages = 1000
+
+// ServerSessions keeps the resumable ATP sessions of a plugin alive between connections. A plugin that accepts
+// connections, for example over a socket, passes the same ServerSessions to RunResumableATPServer for every
+// connection. If the connection of a client fails, its steps keep running, and the client can resume the session over
+// a new connection to receive the messages it missed. Sessions that are not resumed within the session timeout are
+// removed, and their steps are cancelled.
+type ServerSessions struct {
+	ctx            context.Context
+	timeout        time.Duration
+	maxUnconfirmed int
+	events         *stepEventEmitter
+	clock          clock.Clock
+	random         io.Reader
+	jobs           *JobQueue
+	lock           sync.Mutex
+	sessions       map[string]*resumableSession
+}
+
+// NewServerSessions creates an empty session store. The steps of resumable sessions run with the given context
+// instead of the context of the connection that started them.
+func NewServerSessions(ctx context.Context) *ServerSessions {
+	return &ServerSessions{
+		ctx:            ctx,
+		timeout:        DefaultSessionTimeout,
+		maxUnconfirmed: DefaultMaxUnconfirmedMessages,
+		clock:          clock.Real(),
+		random:         rand.Reader,
+		sessions:       map[string]*resumableSession{},
+	}
+}
+
+// WithTimeout is a builder-pattern way of setting how long a session is kept after its connection failed.
+func (s *ServerSessions) WithTimeout(timeout time.Duration) *ServerSessions {
+	s.timeout = timeout
+	return s
+}
+
+// WithMaxUnconfirmedMessages is a builder-pattern way of limiting the number of messages the client hasn't confirmed
+// yet that are kept for resending. If a client falls further behind, the oldest messages are dropped, and the session
+// can no longer be resumed.
+func (s *ServerSessions) WithMaxUnconfirmedMessages(maxUnconfirmed int) *ServerSessions {
+	s.maxUnconfirmed = maxUnconfirmed
+	return s
+}
+
+// WithEvents is a builder-pattern way of sending the lifecycle events of the steps of all sessions to the given
+// channel. The channel must be read while steps are running, since the steps wait for each event to be received.
+func (s *ServerSessions) WithEvents(events chan<- StepEvent) *ServerSessions {
+	s.events = newStepEventEmitter(events)
+	return s
+}
+
+// WithClock is a builder-pattern way of expiring sessions and timing out sends according to the clock, such as a
+// clock.Fake in tests.
+func (s *ServerSessions) WithClock(c clock.Clock) *ServerSessions {
+	s.clock = c
+	return s
+}
+
+// WithRandom is a builder-pattern way of generating the session tokens from the given source instead of crypto/rand.
+// Tests can use a fixed source to get predictable tokens.
+func (s *ServerSessions) WithRandom(random io.Reader) *ServerSessions {
+	s.random = random
+	return s
+}
+
+// WithJobQueue is a builder-pattern way of running the steps of all connections through the given job queue, so that
+// they survive restarts of the plugin. A client starting a step with the run ID of a job already in the queue receives
+// the result of that job instead of running the step again. The jobs are kept until they are removed from the queue.
+// Steps run by the queue use the context of the queue, and don't receive labels, signals, or debug logs.
+func (s *ServerSessions) WithJobQueue(queue *JobQueue) *ServerSessions {
+	s.jobs = queue
+	return s
+}
+
+// RunResumableATPServer runs an ATP server on a single connection like RunATPServer, but offers clients to keep the
+// session resumable. It returns when the connection ends. If the client finished the session, the errors of the
+// whole session are returned. If the connection failed, the steps keep running, and only the errors that occurred so
+// far are returned.
+func RunResumableATPServer(
+	ctx context.Context,
+	stdin io.ReadCloser,
+	stdout io.WriteCloser,
+	pluginSchema *schema.CallableSchema,
+	sessions *ServerSessions,
+) []*ServerError {
+	return runATPServer(ctx, stdin, stdout, pluginSchema, sessions, sessions.events)
+}
+
+// open returns the session with the given token, or a new session if the token is unknown, or the session can no
+// longer send all messages after lastReceived. The second return value is true if an existing session was found.
+func (s *ServerSessions) open(token string, lastReceived uint64) (*resumableSession, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if session, ok := s.sessions[token]; ok && token != "" {
+		if session.canResume(lastReceived) {
+			return session, true
+		}
+		// The steps can no longer report their results, so they are stopped.
+		delete(s.sessions, token)
+		session.close()
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	session := &resumableSession{
+		token:          newSessionToken(s.random),
+		ctx:            ctx,
+		cancel:         cancel,
+		sessions:       s,
+		maxUnconfirmed: s.maxUnconfirmed,
+		runningSteps:   map[string]runningStep{},
+	}
+	session.changed = sync.NewCond(&session.lock)
+	s.sessions[session.token] = session
+	return session, false
+}
+
+// remove ends the session after the client finished it.
+func (s *ServerSessions) remove(session *resumableSession) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.sessions, session.token)
+	session.close()
+}
+
+// expire removes the session if it is still detached from the given connection.
+func (s *ServerSessions) expire(session *resumableSession, connection uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	session.lock.Lock()
+	expired := !session.attached && session.connection == connection
+	session.lock.Unlock()
+	if expired && s.sessions[session.token] == session {
+		delete(s.sessions, session.token)
+		session.close()
+	}
+}
+
+func newSessionToken(random io.Reader) string {
+	token := make([]byte, 16)
+	if _, err := io.ReadFull(random, token); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(token)
+}
+
+// useSession makes the connection run its steps as part of the resumable session.
+func (s *atpServerSession) useSession(session *resumableSession) {
+	s.resumable = session
+	s.stepCtx = session.ctx
+	s.stepsWg = &session.stepsWg
+	s.runningSteps = session.runningSteps
+	s.runningStepsLock = &session.runningStepsLock
+}
+
+// resumableSession holds the state of a session that outlives its connections. Every runtime message sent to the
+// client is numbered and kept until the client confirms receiving it, either by acknowledging it or by resuming the
+// session. In turn, the numbered messages of the client are acknowledged. The messages are written by a separate
+// goroutine per connection, so a slow client never blocks the steps or the read loop.
+type resumableSession struct {
+	token    string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	sessions *ServerSessions
+
+	lock    sync.Mutex
+	changed *sync.Cond
+	// lastSequence is the sequence number of the last message sent.
+	lastSequence   uint64
+	unconfirmed    []RuntimeMessage
+	maxUnconfirmed int
+	// dropped is the sequence number of the last message that was dropped before the client confirmed it.
+	dropped uint64
+	// received tracks the numbered messages received from the client, so messages sent again are only handled once.
+	received sequenceTracker
+	// connection identifies the last attached connection, so a failing old connection doesn't detach a newer one.
+	connection uint64
+	attached   bool
+	closer     io.Closer
+	expiry     clock.Timer
+	closed     bool
+	errors     []*ServerError
+
+	stepsWg          sync.WaitGroup
+	runningSteps     map[string]runningStep
+	runningStepsLock sync.Mutex
+}
+
+// canResume returns true if all messages after lastReceived are still available.
+func (r *resumableSession) canResume(lastReceived uint64) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return !r.closed && lastReceived >= r.dropped
+}
+
+// attach starts sending the messages after lastReceived to the new connection, followed by all further messages. A
+// previous connection is closed, so its read loop ends.
+func (r *resumableSession) attach(encoder *cbor.Encoder, closer io.Closer, lastReceived uint64) uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.closer != nil {
+		_ = r.closer.Close()
+	}
+	if r.expiry != nil {
+		r.expiry.Stop()
+		r.expiry = nil
+	}
+	r.connection++
+	r.attached = true
+	r.closer = closer
+	r.confirmLocked(lastReceived)
+	r.changed.Broadcast()
+	go r.write(r.connection, encoder, closer, lastReceived, r.received.last)
+	return r.connection
+}
+
+// lastReceived returns the sequence number up to which all numbered messages of the client were received.
+func (r *resumableSession) lastReceived() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.received.last
+}
+
+// receive records a numbered message of the client, so the writer acknowledges it. It returns false if the message
+// was already received.
+func (r *resumableSession) receive(sequence uint64) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.received.record(sequence) {
+		return false
+	}
+	r.changed.Broadcast()
+	return true
+}
+
+// write sends the messages after the sent sequence number to the connection until it is detached, and acknowledges
+// the messages of the client received after the acked sequence number.
+func (r *resumableSession) write(
+	connection uint64,
+	encoder *cbor.Encoder,
+	closer io.Closer,
+	sent uint64,
+	acked uint64,
+) {
+	for {
+		r.lock.Lock()
+		for r.attached && r.connection == connection && r.dropped <= sent && r.lastSequence <= sent &&
+			r.received.last <= acked {
+			r.changed.Wait()
+		}
+		if !r.attached || r.connection != connection {
+			r.lock.Unlock()
+			return
+		}
+		if r.dropped > sent {
+			// The client can never receive the dropped messages, so the connection is useless.
+			r.detachLocked()
+			r.lock.Unlock()
+			_ = closer.Close()
+			return
+		}
+		next := sort.Search(len(r.unconfirmed), func(i int) bool {
+			return r.unconfirmed[i].Sequence > sent
+		})
+		pending := slices.Clone(r.unconfirmed[next:])
+		received := r.received.last
+		r.lock.Unlock()
+		for _, message := range pending {
+			if err := encodeWithTimeout(r.sessions.clock, encoder, message); err != nil {
+				_ = closer.Close()
+				r.detach(connection)
+				return
+			}
+			sent = message.Sequence
+		}
+		if received > acked {
+			if err := encodeWithTimeout(r.sessions.clock, encoder, RuntimeMessage{
+				MessageID:   MessageTypeAck,
+				MessageData: AckMessage{LastReceived: received},
+			}); err != nil {
+				_ = closer.Close()
+				r.detach(connection)
+				return
+			}
+			acked = received
+		}
+	}
+}
+
+// detach stops sending messages to the connection if it is still attached. The session is removed if it isn't
+// resumed in time.
+func (r *resumableSession) detach(connection uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.attached && r.connection == connection {
+		r.detachLocked()
+	}
+}
+
+func (r *resumableSession) detachLocked() {
+	r.attached = false
+	r.closer = nil
+	r.changed.Broadcast()
+	if r.closed {
+		return
+	}
+	connection := r.connection
+	r.expiry = r.sessions.clock.AfterFunc(r.sessions.timeout, func() {
+		r.sessions.expire(r, connection)
+	})
+}
+
+// close stops sending messages and cancels the steps of the session.
+func (r *resumableSession) close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.closed = true
+	r.attached = false
+	if r.expiry != nil {
+		r.expiry.Stop()
+		r.expiry = nil
+	}
+	r.changed.Broadcast()
+	r.cancel()
+}
+
+// confirm drops the messages the client acknowledged.
+func (r *resumableSession) confirm(lastReceived uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.confirmLocked(lastReceived)
+}
+
+func (r *resumableSession) confirmLocked(lastReceived uint64) {
+	for len(r.unconfirmed) > 0 && r.unconfirmed[0].Sequence <= lastReceived {
+		r.unconfirmed = r.unconfirmed[1:]
+	}
+}
+
+// send numbers and keeps the message, and hands it to the writer of the attached connection, if any. If the client
+// doesn't confirm the messages, the oldest are dropped once there are more than the session can keep.
+func (r *resumableSession) send(msgID uint32, runID string, data any) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.closed {
+		return
+	}
+	r.lastSequence++
+	r.unconfirmed = append(r.unconfirmed, RuntimeMessage{
+		MessageID:   msgID,
+		RunID:       runID,
+		MessageData: data,
+		Sequence:    r.lastSequence,
+	})
+	if len(r.unconfirmed) > r.maxUnconfirmed {
+		r.dropped = r.unconfirmed[0].Sequence
+		r.unconfirmed = r.unconfirmed[1:]
+	}
+	r.changed.Broadcast()
+}
+
+func (r *resumableSession) recordError(serverError ServerError) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.errors = append(r.errors, &serverError)
+}
+
+// takeErrors returns the step errors recorded since the last call.
+func (r *resumableSession) takeErrors() []*ServerError {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := r.errors
+	r.errors = nil
+	return result
+}
+
+// sequenceTracker records the sequence numbers of the runtime messages received in a resumable session. Messages may
+// arrive out of order, for example when the transport reorders the messages of different runs, so the sequence
+// numbers received after a gap are kept until the gap is filled.
+type sequenceTracker struct {
+	// last is the sequence number up to which all messages were received.
+	last  uint64
+	ahead map[uint64]struct{}
+}
+
+// record returns true if the message with the sequence number was already received.
+func (t *sequenceTracker) record(sequence uint64) bool {
+	if _, ok := t.ahead[sequence]; ok || sequence <= t.last {
+		return true
+	}
+	if t.ahead == nil {
+		t.ahead = map[uint64]struct{}{}
+	}
+	t.ahead[sequence] = struct{}{}
+	for {
+		if _, ok := t.ahead[t.last+1]; !ok {
+			return false
+		}
+		delete(t.ahead, t.last+1)
+		t.last++
+	}
+}
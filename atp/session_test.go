@@ -0,0 +1,418 @@
+package atp_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"go.arcalot.io/assert"
+	"go.arcalot.io/log/v2"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/clock"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingSteps lets the test decide when the steps of the blocking schema finish.
+type blockingSteps struct {
+	lock    sync.Mutex
+	started map[string]chan struct{}
+	release map[string]chan struct{}
+}
+
+func newBlockingSteps(names ...string) *blockingSteps {
+	b := &blockingSteps{
+		started: map[string]chan struct{}{},
+		release: map[string]chan struct{}{},
+	}
+	for _, name := range names {
+		b.started[name] = make(chan struct{})
+		b.release[name] = make(chan struct{})
+	}
+	return b
+}
+
+func (b *blockingSteps) schema() *schema.CallableSchema {
+	return schema.NewCallableSchema(
+		schema.NewCallableStep[helloWorldInput](
+			"hello-world",
+			helloWorldInputSchema,
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(
+					schema.NewScopeSchema(
+						schema.NewStructMappedObjectSchema[helloWorldOutput](
+							"Output",
+							map[string]*schema.PropertySchema{
+								"message": schema.NewPropertySchema(
+									schema.NewStringSchema(nil, nil, nil),
+									nil,
+									true,
+									nil,
+									nil,
+									nil,
+									nil,
+									nil,
+								),
+							},
+						),
+					),
+					nil,
+					false,
+				),
+			},
+			nil,
+			func(_ context.Context, input helloWorldInput) (string, any) {
+				b.lock.Lock()
+				started := b.started[input.Name]
+				release := b.release[input.Name]
+				b.lock.Unlock()
+				close(started)
+				<-release
+				return "success", helloWorldOutput{Message: fmt.Sprintf("Hello, %s!", input.Name)}
+			},
+		),
+	)
+}
+
+// pipeConnection is a connection between a client and a server made of two pipes. Closing the client channel closes
+// the whole connection.
+type pipeConnection struct {
+	stdinReader  *io.PipeReader
+	stdinWriter  *io.PipeWriter
+	stdoutReader *io.PipeReader
+	stdoutWriter *io.PipeWriter
+}
+
+func newPipeConnection() *pipeConnection {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	return &pipeConnection{stdinReader, stdinWriter, stdoutReader, stdoutWriter}
+}
+
+func (p *pipeConnection) Read(data []byte) (int, error) {
+	return p.stdoutReader.Read(data)
+}
+
+func (p *pipeConnection) Write(data []byte) (int, error) {
+	return p.stdinWriter.Write(data)
+}
+
+func (p *pipeConnection) Close() error {
+	p.fail(io.ErrClosedPipe)
+	return nil
+}
+
+// fail breaks the connection in both directions.
+func (p *pipeConnection) fail(err error) {
+	_ = p.stdinReader.CloseWithError(err)
+	_ = p.stdinWriter.CloseWithError(err)
+	_ = p.stdoutReader.CloseWithError(err)
+	_ = p.stdoutWriter.CloseWithError(err)
+}
+
+// serve runs a resumable server on the connection and returns a channel receiving its errors.
+func (p *pipeConnection) serve(
+	pluginSchema *schema.CallableSchema,
+	sessions *atp.ServerSessions,
+) <-chan []*atp.ServerError {
+	result := make(chan []*atp.ServerError, 1)
+	go func() {
+		result <- atp.RunResumableATPServer(context.Background(), p.stdinReader, p.stdoutWriter, pluginSchema, sessions)
+	}()
+	return result
+}
+
+func executeAsync(cli atp.Client, name string) <-chan atp.ExecutionResult {
+	result := make(chan atp.ExecutionResult, 1)
+	go func() {
+		result <- cli.Execute(
+			schema.Input{RunID: name, ID: "hello-world", InputData: map[string]any{"name": name}},
+			nil,
+			nil,
+		)
+	}()
+	return result
+}
+
+func TestProtocol_Session_Resume(t *testing.T) {
+	steps := newBlockingSteps("first", "second")
+	pluginSchema := steps.schema()
+	sessions := atp.NewServerSessions(context.Background())
+
+	connection := newPipeConnection()
+	serverErrors := connection.serve(pluginSchema, sessions)
+	cli := atp.NewResumableClientWithLogger(connection, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	assert.Equals(t, cli.SessionToken() != "", true)
+
+	first := executeAsync(cli, "first")
+	second := executeAsync(cli, "second")
+	<-steps.started["first"]
+	<-steps.started["second"]
+
+	// The connection fails while both steps are running, and the first step finishes while the session is
+	// disconnected.
+	connection.fail(fmt.Errorf("connection reset"))
+	assert.Equals(t, len(<-serverErrors), 0)
+	close(steps.release["first"])
+
+	connection = newPipeConnection()
+	serverErrors = connection.serve(pluginSchema, sessions)
+	assert.NoError(t, cli.Resume(connection))
+	close(steps.release["second"])
+
+	for name, result := range map[string]<-chan atp.ExecutionResult{"first": first, "second": second} {
+		executionResult := <-result
+		assert.NoError(t, executionResult.Error)
+		assert.Equals(t, executionResult.OutputID, "success")
+		assert.Equals(
+			t,
+			executionResult.OutputData.(map[any]any)["message"].(string),
+			fmt.Sprintf("Hello, %s!", name),
+		)
+	}
+	assert.NoError(t, cli.Close())
+	assert.Equals(t, len(<-serverErrors), 0)
+}
+
+func TestProtocol_Session_Resume_Unknown(t *testing.T) {
+	steps := newBlockingSteps("first")
+	pluginSchema := steps.schema()
+	defer close(steps.release["first"])
+
+	connection := newPipeConnection()
+	connection.serve(pluginSchema, atp.NewServerSessions(context.Background()))
+	cli := atp.NewResumableClientWithLogger(connection, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	first := executeAsync(cli, "first")
+	<-steps.started["first"]
+	connection.fail(fmt.Errorf("connection reset"))
+
+	// The new plugin process doesn't know the session, so the result of the running step is lost.
+	connection = newPipeConnection()
+	serverErrors := connection.serve(pluginSchema, atp.NewServerSessions(context.Background()))
+	assert.Error(t, cli.Resume(connection))
+	assert.Error(t, (<-first).Error)
+	assert.NoError(t, cli.Close())
+	assert.Equals(t, len(<-serverErrors), 0)
+}
+
+func TestProtocol_Session_Not_Supported(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errors := atp.RunATPServer(ctx, stdinReader, stdoutWriter, helloWorldSchema)
+		assert.Equals(t, len(errors), 0)
+	}()
+
+	cli := atp.NewResumableClientWithLogger(channel{
+		Reader: stdoutReader,
+		Writer: stdinWriter,
+		cancel: cancel,
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	assert.Equals(t, cli.SessionToken(), "")
+	result := cli.Execute(
+		schema.Input{RunID: t.Name(), ID: "hello-world", InputData: map[string]any{"name": "Arca Lot"}},
+		nil,
+		nil,
+	)
+	assert.NoError(t, result.Error)
+	assert.Error(t, cli.Resume(channel{Reader: stdoutReader, Writer: stdinWriter, cancel: cancel}))
+	assert.NoError(t, cli.Close())
+	wg.Wait()
+}
+
+func TestProtocol_Session_Resume_Timeout(t *testing.T) {
+	steps := newBlockingSteps("first")
+	pluginSchema := steps.schema()
+	defer close(steps.release["first"])
+
+	connection := newPipeConnection()
+	connection.serve(pluginSchema, atp.NewServerSessions(context.Background()))
+	clientClock := clock.NewFake(time.Now())
+	cli := atp.NewResumableClientWithClock(connection, log.NewTestLogger(t), time.Minute, clientClock)
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	first := executeAsync(cli, "first")
+	<-steps.started["first"]
+
+	// The session is never resumed, so the execution fails once the resume timeout has passed.
+	connection.fail(fmt.Errorf("connection reset"))
+	clientClock.WaitForTimers(1)
+	clientClock.Advance(time.Minute)
+	assert.Error(t, (<-first).Error)
+	assert.NoError(t, cli.Close())
+}
+
+func TestProtocol_Session_Expired(t *testing.T) {
+	steps := newBlockingSteps("first")
+	pluginSchema := steps.schema()
+	sessionClock := clock.NewFake(time.Now())
+	sessions := atp.NewServerSessions(context.Background()).WithTimeout(time.Minute).WithClock(sessionClock)
+	defer close(steps.release["first"])
+
+	connection := newPipeConnection()
+	serverErrors := connection.serve(pluginSchema, sessions)
+	cli := atp.NewResumableClientWithLogger(connection, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	first := executeAsync(cli, "first")
+	<-steps.started["first"]
+	connection.fail(fmt.Errorf("connection reset"))
+	assert.Equals(t, len(<-serverErrors), 0)
+
+	// The server removes the session before the client tries to resume it.
+	sessionClock.WaitForTimers(1)
+	sessionClock.Advance(time.Minute)
+	connection = newPipeConnection()
+	serverErrors = connection.serve(pluginSchema, sessions)
+	assert.Error(t, cli.Resume(connection))
+	assert.Error(t, (<-first).Error)
+	assert.NoError(t, cli.Close())
+	assert.Equals(t, len(<-serverErrors), 0)
+}
+
+func TestProtocol_Session_Unconfirmed_Limit(t *testing.T) {
+	steps := newBlockingSteps("first", "second")
+	pluginSchema := steps.schema()
+	sessions := atp.NewServerSessions(context.Background()).WithMaxUnconfirmedMessages(1)
+
+	connection := newPipeConnection()
+	serverErrors := connection.serve(pluginSchema, sessions)
+	cli := atp.NewResumableClientWithLogger(connection, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	first := executeAsync(cli, "first")
+	second := executeAsync(cli, "second")
+	<-steps.started["first"]
+	<-steps.started["second"]
+	connection.fail(fmt.Errorf("connection reset"))
+	assert.Equals(t, len(<-serverErrors), 0)
+
+	// Both steps finish while the session is disconnected, but the server can only keep one of the results.
+	close(steps.release["first"])
+	close(steps.release["second"])
+	time.Sleep(100 * time.Millisecond)
+	connection = newPipeConnection()
+	serverErrors = connection.serve(pluginSchema, sessions)
+	assert.Error(t, cli.Resume(connection))
+	assert.Error(t, (<-first).Error)
+	assert.Error(t, (<-second).Error)
+	assert.NoError(t, cli.Close())
+	assert.Equals(t, len(<-serverErrors), 0)
+}
+
+func receiveAck(t *testing.T, received <-chan atp.DecodedRuntimeMessage) uint64 {
+	ack := <-received
+	assert.Equals(t, ack.MessageID, atp.MessageTypeAck)
+	var ackMessage atp.AckMessage
+	assert.NoError(t, cbor.Unmarshal(ack.RawMessageData, &ackMessage))
+	return ackMessage.LastReceived
+}
+
+// TestProtocol_Session_Acknowledge checks that the client acknowledges the runtime messages it received, even if they
+// arrive out of order or more than once.
+func TestProtocol_Session_Acknowledge(t *testing.T) {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	serializedSchema := assert.NoErrorR[any](t)(helloWorldSchema.SelfSerialize())
+	received := make(chan atp.DecodedRuntimeMessage)
+	go func() {
+		defer close(received)
+		decoder := cbor.NewDecoder(stdinReader)
+		var start atp.StartMessage
+		if err := decoder.Decode(&start); err != nil {
+			return
+		}
+		for {
+			var message atp.DecodedRuntimeMessage
+			if err := decoder.Decode(&message); err != nil {
+				return
+			}
+			received <- message
+		}
+	}()
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		encoder := cbor.NewEncoder(stdoutWriter)
+		assert.NoError(t, encoder.Encode(atp.HelloMessage{
+			Version:      atp.ProtocolVersion,
+			Schema:       serializedSchema,
+			SessionToken: "test",
+		}))
+		workStart := <-received
+		assert.Equals(t, workStart.MessageID, atp.MessageTypeWorkStart)
+		assert.Equals(t, workStart.Sequence, uint64(1))
+		assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+			MessageID:   atp.MessageTypeAck,
+			MessageData: atp.AckMessage{LastReceived: 1},
+		}))
+		for _, sequence := range []uint64{2, 1, 1, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16} {
+			assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+				MessageID:   atp.MessageTypeSignal,
+				RunID:       workStart.RunID,
+				MessageData: atp.SignalMessage{SignalID: "progress"},
+				Sequence:    sequence,
+			}))
+		}
+		assert.Equals(t, receiveAck(t, received), uint64(16))
+		assert.NoError(t, encoder.Encode(atp.RuntimeMessage{
+			MessageID: atp.MessageTypeWorkDone,
+			RunID:     workStart.RunID,
+			MessageData: atp.WorkDoneMessage{
+				StepID:     "hello-world",
+				OutputID:   "success",
+				OutputData: map[string]any{"message": "Hello, Arca Lot!"},
+			},
+			Sequence: 17,
+		}))
+		// Outputs are acknowledged right away.
+		assert.Equals(t, receiveAck(t, received), uint64(17))
+		assert.Equals(t, (<-received).MessageID, atp.MessageTypeClientDone)
+	}()
+
+	cli := atp.NewResumableClientWithLogger(channel{
+		Reader: stdoutReader,
+		Writer: stdinWriter,
+		cancel: func() {},
+	}, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	result := cli.Execute(
+		schema.Input{RunID: t.Name(), ID: "hello-world", InputData: map[string]any{"name": "Arca Lot"}},
+		nil,
+		nil,
+	)
+	assert.NoError(t, result.Error)
+	assert.Equals(t, result.OutputID, "success")
+	assert.NoError(t, cli.Close())
+	<-serverDone
+}
+
+func TestProtocol_Session_Random_Token(t *testing.T) {
+	steps := newBlockingSteps("first")
+	pluginSchema := steps.schema()
+	random := bytes.NewReader(bytes.Repeat([]byte{0xab}, 16))
+	sessions := atp.NewServerSessions(context.Background()).WithRandom(random)
+
+	connection := newPipeConnection()
+	serverErrors := connection.serve(pluginSchema, sessions)
+	cli := atp.NewResumableClientWithLogger(connection, log.NewTestLogger(t))
+	_, err := cli.ReadSchema()
+	assert.NoError(t, err)
+	assert.Equals(t, cli.SessionToken(), "abababababababababababababababab")
+	assert.NoError(t, cli.Close())
+	assert.Equals(t, len(<-serverErrors), 0)
+}
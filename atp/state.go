@@ -0,0 +1,87 @@
+package atp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// NewFileStateBackend creates a state backend for schema.NewStateStore that stores each key in a file in the
+// directory, creating it if needed. The state survives plugin restarts, so a plugin service can pass state between
+// steps that run in different processes.
+func NewFileStateBackend(dir string) (schema.StateBackend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory %s (%w)", dir, err)
+	}
+	return &fileStateBackend{dir: dir}, nil
+}
+
+type fileStateBackend struct {
+	lock sync.Mutex
+	dir  string
+}
+
+func (f *fileStateBackend) LoadState(key string) (any, bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	data, err := os.ReadFile(f.statePath(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read state key %s (%w)", key, err)
+	}
+	var serialized any
+	if err := cbor.Unmarshal(data, &serialized); err != nil {
+		return nil, false, fmt.Errorf("failed to decode state key %s (%w)", key, err)
+	}
+	return serialized, true, nil
+}
+
+// StoreState writes the value to a temporary file and moves it in place, so a crash never leaves a partially written
+// value.
+func (f *fileStateBackend) StoreState(key string, serialized any) error {
+	data, err := cbor.Marshal(serialized)
+	if err != nil {
+		return fmt.Errorf("failed to encode state key %s (%w)", key, err)
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	file, err := os.CreateTemp(f.dir, "state-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to store state key %s (%w)", key, err)
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), f.statePath(key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to store state key %s (%w)", key, err)
+	}
+	return nil
+}
+
+func (f *fileStateBackend) DeleteState(key string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := os.Remove(f.statePath(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete state key %s (%w)", key, err)
+	}
+	return nil
+}
+
+// statePath returns the file of the key. The key is hex-encoded since it may contain any character.
+func (f *fileStateBackend) statePath(key string) string {
+	return filepath.Join(f.dir, hex.EncodeToString([]byte(key))+".state")
+}
@@ -0,0 +1,33 @@
+package atp_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/atp"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestFileStateBackend(t *testing.T) {
+	dir := t.TempDir()
+	keys := map[string]schema.Type{
+		"greeting": helloWorldInputSchema,
+	}
+	backend := assert.NoErrorR[schema.StateBackend](t)(atp.NewFileStateBackend(dir))
+	store := schema.NewStateStore(keys, backend)
+	assert.NoError(t, store.Set("greeting", helloWorldInput{Name: "Arca Lot"}))
+
+	// A new store on the same directory sees the stored state.
+	backend = assert.NoErrorR[schema.StateBackend](t)(atp.NewFileStateBackend(dir))
+	store = schema.NewStateStore(keys, backend)
+	greeting, found, err := schema.GetState[helloWorldInput](store, "greeting")
+	assert.NoError(t, err)
+	assert.Equals(t, found, true)
+	assert.Equals(t, greeting.Name, "Arca Lot")
+
+	assert.NoError(t, store.Delete("greeting"))
+	assert.NoError(t, store.Delete("greeting"))
+	_, found, err = store.Get("greeting")
+	assert.NoError(t, err)
+	assert.Equals(t, found, false)
+}
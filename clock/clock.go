@@ -0,0 +1,82 @@
+// Package clock provides the time source used by the SDK for timeouts, retries, session expiry and durations. Code
+// under test can pass a Fake clock instead of the real one and advance it explicitly, so tests of timeout and retry
+// behavior neither sleep nor depend on the speed of the machine.
+//
+// Components that are configured with builders, such as the ATP server sessions, accept a clock directly. Code that
+// runs as part of a step, such as the HTTP clients and the rate limiters of a step, takes the clock from the context
+// of the step, see NewContext.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and creates timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a timer that sends the current time on its channel after the duration.
+	NewTimer(d time.Duration) Timer
+	// AfterFunc calls the function after the duration. The returned timer has no channel.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event scheduled on a clock.
+type Timer interface {
+	// C returns the channel the time is sent on when the timer fires, or nil for timers created by AfterFunc.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer already fired or was stopped.
+	Stop() bool
+}
+
+// Since returns the time elapsed on the clock since t.
+func Since(c Clock, t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Real returns the clock of the system.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{timer: time.AfterFunc(d, f)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the clock.
+func NewContext(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the clock of the context, or the real clock if the context carries none.
+func FromContext(ctx context.Context) Clock {
+	if c, ok := ctx.Value(contextKey{}).(Clock); ok {
+		return c
+	}
+	return Real()
+}
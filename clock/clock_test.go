@@ -0,0 +1,64 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeAdvance(t *testing.T) {
+	c := clock.NewFake(start)
+	timer := c.NewTimer(time.Second)
+	var calls []string
+	c.AfterFunc(2*time.Second, func() { calls = append(calls, "second") })
+	c.AfterFunc(time.Second, func() { calls = append(calls, "first") })
+	assert.Equals(t, c.PendingTimers(), 3)
+
+	c.Advance(500 * time.Millisecond)
+	assert.Equals(t, c.Now(), start.Add(500*time.Millisecond))
+	assert.Equals(t, len(calls), 0)
+	select {
+	case <-timer.C():
+		t.Fatalf("the timer fired early")
+	default:
+	}
+
+	c.Advance(2 * time.Second)
+	assert.Equals(t, calls, []string{"first", "second"})
+	assert.Equals(t, <-timer.C(), start.Add(2500*time.Millisecond))
+	assert.Equals(t, c.PendingTimers(), 0)
+	assert.Equals(t, clock.Since(c, start), 2500*time.Millisecond)
+}
+
+func TestFakeStop(t *testing.T) {
+	c := clock.NewFake(start)
+	called := false
+	timer := c.AfterFunc(time.Second, func() { called = true })
+	assert.Equals(t, timer.Stop(), true)
+	assert.Equals(t, timer.Stop(), false)
+	c.Advance(time.Minute)
+	assert.Equals(t, called, false)
+}
+
+func TestFakeWaitForTimers(t *testing.T) {
+	c := clock.NewFake(start)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-c.NewTimer(time.Hour).C()
+	}()
+	c.WaitForTimers(1)
+	c.Advance(time.Hour)
+	<-done
+}
+
+func TestContext(t *testing.T) {
+	assert.Equals(t, clock.FromContext(context.Background()), clock.Real())
+	c := clock.NewFake(start)
+	assert.Equals(t, clock.FromContext(clock.NewContext(context.Background(), c)), clock.Clock(c))
+}
@@ -0,0 +1,119 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a clock that only moves when Advance is called. Timers that become due fire during Advance, in the order
+// of their due time, and functions scheduled with AfterFunc are called synchronously, so they have finished when
+// Advance returns. A Fake clock is safe for concurrent use.
+type Fake struct {
+	lock    sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+}
+
+// NewFake creates a fake clock showing the given time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.changed = sync.NewCond(&f.lock)
+	return f
+}
+
+// Now returns the time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.now
+}
+
+// NewTimer creates a timer that fires once the clock is advanced by the duration.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.schedule(d, make(chan time.Time, 1), nil)
+}
+
+// AfterFunc schedules the function to be called once the clock is advanced by the duration.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.schedule(d, nil, fn)
+}
+
+func (f *Fake) schedule(d time.Duration, c chan time.Time, fn func()) *fakeTimer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	t := &fakeTimer{clock: f, at: f.now.Add(d), c: c, fn: fn}
+	f.timers = append(f.timers, t)
+	f.changed.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by the duration and fires the timers that became due.
+func (f *Fake) Advance(d time.Duration) {
+	f.lock.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+	var due []*fakeTimer
+	pending := f.timers[:0]
+	for _, t := range f.timers {
+		if t.at.After(now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	f.timers = pending
+	f.changed.Broadcast()
+	f.lock.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, t := range due {
+		if t.fn != nil {
+			t.fn()
+		} else {
+			t.c <- now
+		}
+	}
+}
+
+// PendingTimers returns the number of timers that have not fired or been stopped.
+func (f *Fake) PendingTimers() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.timers)
+}
+
+// WaitForTimers blocks until at least the given number of timers are pending. Tests call it before Advance to make
+// sure the code under test, which may run in another goroutine, has started waiting.
+func (f *Fake) WaitForTimers(count int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for len(f.timers) < count {
+		f.changed.Wait()
+	}
+}
+
+type fakeTimer struct {
+	clock *Fake
+	at    time.Time
+	c     chan time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	for i, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			t.clock.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Package httpclient creates HTTP clients from a configuration described by a schema. Plugins calling HTTP APIs can
+// add the configuration to their step input with NewConfigProperty, so all of them accept the same timeout, TLS, proxy
+// and retry settings. The clients log each request to the debug log of the step, wait for the rate limiter of the
+// step before each request, and retry failed requests. They wait between retries according to the clock of the context
+// of the request, see clock.NewContext.
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/tlsconfig"
+)
+
+// Config configures an HTTP client. The zero value creates a client without timeouts that does not retry.
+type Config struct {
+	// Timeout limits the time a request may take, including retries and reading the response body. Zero means no
+	// limit.
+	Timeout time.Duration `json:"timeout"`
+	// ConnectTimeout limits the time establishing a connection may take. Zero means no limit.
+	ConnectTimeout time.Duration `json:"connect_timeout"`
+	// TLS configures the TLS connections. If nil, the system CA certificates are used.
+	TLS *tlsconfig.Config `json:"tls"`
+	// Proxy is the URL of the proxy to send the requests through. If nil, the proxy is taken from the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy *string `json:"proxy"`
+	// Retries configures how failed requests are retried. If nil, they are not retried.
+	Retries *RetryConfig `json:"retries"`
+}
+
+// RetryConfig configures how an HTTP client retries requests that failed with a connection error or with the status
+// codes 429, 502, 503 or 504. Requests with a body are only retried if the body can be read again, which is the case
+// for the bodies created by http.NewRequest from bytes or strings.
+type RetryConfig struct {
+	// MaxAttempts is the number of times a request is sent at most, including the first attempt.
+	MaxAttempts int64 `json:"max_attempts"`
+	// Backoff is the time to wait before the first retry. It doubles with each retry.
+	Backoff time.Duration `json:"backoff"`
+	// MaxBackoff limits the time to wait before a retry. Zero means no limit.
+	MaxBackoff time.Duration `json:"max_backoff"`
+}
+
+// New creates an HTTP client from the configuration. Warnings about the configuration are written to the debug log
+// of the step in the context.
+func New(ctx context.Context, config Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.ConnectTimeout > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   config.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	if config.Proxy != nil {
+		proxyURL, err := url.Parse(*config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL (%w)", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.Build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &http.Client{
+		Transport: &instrumentedTransport{
+			base:    transport,
+			retries: config.Retries,
+		},
+		Timeout: config.Timeout,
+	}, nil
+}
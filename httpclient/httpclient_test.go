@@ -0,0 +1,112 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/httpclient"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/tlsconfig"
+)
+
+func TestConfigSchemaDefaults(t *testing.T) {
+	config := assert.NoErrorR[any](t)(httpclient.NewConfigSchema().Unserialize(map[string]any{
+		"retries": map[string]any{},
+	})).(*httpclient.Config)
+	assert.Equals(t, config.Timeout, 30*time.Second)
+	assert.Equals(t, config.ConnectTimeout, 10*time.Second)
+	assert.Equals(t, config.Retries.MaxAttempts, int64(3))
+	assert.Equals(t, config.Retries.Backoff, time.Second)
+	assert.Nil(t, config.TLS)
+
+	_, err := httpclient.NewConfigSchema().Unserialize(map[string]any{
+		"tls": map[string]any{"client_cert": "cert"},
+	})
+	assert.Error(t, err)
+}
+
+func TestConfigPropertySelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+			"http": httpclient.NewConfigProperty(),
+		}),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	assert.NoError(t, unserialized.(*schema.ScopeSchema).Validate(map[string]any{
+		"http": map[string]any{"timeout": int64(time.Second)},
+	}))
+}
+
+func TestClientRetries(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := assert.NoErrorR[*http.Client](t)(httpclient.New(context.Background(), httpclient.Config{
+		Retries: &httpclient.RetryConfig{MaxAttempts: 3, Backoff: time.Millisecond},
+	}))
+	resp := assert.NoErrorR[*http.Response](t)(client.Post(server.URL, "text/plain", strings.NewReader("body")))
+	assert.NoError(t, resp.Body.Close())
+	assert.Equals(t, resp.StatusCode, http.StatusOK)
+	assert.Equals(t, requests.Load(), int64(3))
+}
+
+func TestClientWithoutRetries(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := assert.NoErrorR[*http.Client](t)(httpclient.New(context.Background(), httpclient.Config{}))
+	resp := assert.NoErrorR[*http.Response](t)(client.Get(server.URL))
+	assert.NoError(t, resp.Body.Close())
+	assert.Equals(t, resp.StatusCode, http.StatusServiceUnavailable)
+	assert.Equals(t, requests.Load(), int64(1))
+}
+
+func TestClientDebugLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	log := &bytes.Buffer{}
+	ctx := schema.ContextWithDebugLog(context.Background(), log)
+	req := assert.NoErrorR[*http.Request](t)(http.NewRequestWithContext(
+		ctx, http.MethodGet, server.URL+"/items?token=secret", nil,
+	))
+	client := assert.NoErrorR[*http.Client](t)(httpclient.New(context.Background(), httpclient.Config{}))
+	resp := assert.NoErrorR[*http.Response](t)(client.Do(req))
+	assert.NoError(t, resp.Body.Close())
+	assert.Contains(t, log.String(), "HTTP GET "+server.URL+"/items returned 204")
+	assert.Equals(t, strings.Contains(log.String(), "secret"), false)
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := httpclient.New(context.Background(), httpclient.Config{Proxy: schema.PointerTo("://invalid")})
+	assert.Error(t, err)
+	_, err = httpclient.New(context.Background(), httpclient.Config{
+		TLS: &tlsconfig.Config{CACert: schema.PointerTo("not a certificate")},
+	})
+	assert.Error(t, err)
+	_, err = httpclient.New(context.Background(), httpclient.Config{
+		TLS: &tlsconfig.Config{ClientCert: schema.PointerTo("cert")},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/tlsconfig"
+)
+
+// NewConfigSchema creates the object schema of Config. It unserializes to a *Config.
+func NewConfigSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[*Config](
+		"HTTPClientConfig",
+		map[string]*schema.PropertySchema{
+			"timeout": durationProperty(
+				"Timeout",
+				"Time limit for a request, including retries and reading the response. Zero means no limit.",
+				"30000000000",
+			),
+			"connect_timeout": durationProperty(
+				"Connect timeout",
+				"Time limit for establishing a connection. Zero means no limit.",
+				"10000000000",
+			),
+			"tls": tlsconfig.NewProperty(),
+			"proxy": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+				schema.NewDisplayValue(
+					schema.PointerTo("Proxy"),
+					schema.PointerTo("URL of the proxy to send the requests through. If not set, the proxy is "+
+						"taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"http://proxy.example.com:3128\""},
+			),
+			"retries": schema.NewPropertySchema(
+				newRetryConfigSchema(),
+				schema.NewDisplayValue(
+					schema.PointerTo("Retries"),
+					schema.PointerTo("How failed requests are retried. If not set, they are not retried."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	)
+}
+
+// NewConfigProperty creates an optional input property holding the HTTP client configuration. Map it to a *Config
+// field of the input struct.
+func NewConfigProperty() *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		NewConfigSchema(),
+		schema.NewDisplayValue(
+			schema.PointerTo("HTTP client"),
+			schema.PointerTo("Settings of the HTTP client the step uses."),
+			nil,
+		),
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func newRetryConfigSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[*RetryConfig](
+		"HTTPClientRetryConfig",
+		map[string]*schema.PropertySchema{
+			"max_attempts": schema.NewPropertySchema(
+				schema.NewIntSchema(schema.IntPointer(1), nil, nil),
+				schema.NewDisplayValue(
+					schema.PointerTo("Maximum attempts"),
+					schema.PointerTo("Number of times a request is sent at most, including the first attempt."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				schema.PointerTo("3"),
+				nil,
+			),
+			"backoff": durationProperty(
+				"Backoff",
+				"Time to wait before the first retry. It doubles with each retry.",
+				"1000000000",
+			),
+			"max_backoff": durationProperty(
+				"Maximum backoff",
+				"Time limit for waiting before a retry. Zero means no limit.",
+				"30000000000",
+			),
+		},
+	)
+}
+
+func durationProperty(name string, description string, defaultValue string) *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewIntSchema(schema.IntPointer(0), nil, schema.UnitDurationNanoseconds),
+		schema.NewDisplayValue(&name, &description, nil),
+		false,
+		nil,
+		nil,
+		nil,
+		&defaultValue,
+		nil,
+	)
+}
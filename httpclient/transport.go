@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/clock"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// instrumentedTransport waits for the rate limiter of the step, logs the requests to the debug log of the step and
+// retries failed requests.
+type instrumentedTransport struct {
+	base    http.RoundTripper
+	retries *RetryConfig
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	clk := clock.FromContext(ctx)
+	backoff := time.Duration(0)
+	maxAttempts := int64(1)
+	if t.retries != nil {
+		backoff = t.retries.Backoff
+		maxAttempts = t.retries.MaxAttempts
+	}
+	for attempt := int64(1); ; attempt++ {
+		if err := schema.WaitRateLimit(ctx); err != nil {
+			return nil, err
+		}
+		start := clk.Now()
+		resp, err := t.base.RoundTrip(req)
+		logRequest(req, resp, err, attempt, clock.Since(clk, start))
+		if attempt >= maxAttempts || !isRetryable(resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body, so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		timer := clk.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C():
+		}
+		backoff *= 2
+		if t.retries.MaxBackoff > 0 && backoff > t.retries.MaxBackoff {
+			backoff = t.retries.MaxBackoff
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewind returns a copy of the request with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// logRequest writes the request to the debug log of the step. The query is left out since it may hold credentials.
+func logRequest(req *http.Request, resp *http.Response, err error, attempt int64, duration time.Duration) {
+	target := *req.URL
+	target.RawQuery = ""
+	if err != nil {
+		schema.DebugLogf(
+			req.Context(), "HTTP %s %s failed after %s (attempt %d): %v",
+			req.Method, target.Redacted(), duration, attempt, err,
+		)
+		return
+	}
+	schema.DebugLogf(
+		req.Context(), "HTTP %s %s returned %d after %s (attempt %d)",
+		req.Method, target.Redacted(), resp.StatusCode, duration, attempt,
+	)
+}
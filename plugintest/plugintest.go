@@ -0,0 +1,314 @@
+// Package plugintest provides assertions for testing plugins. Failures are reported as a list of the differing values,
+// annotated with their paths and formatted according to the schema, instead of a dump of both values.
+package plugintest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Difference is a value that differs between the expected and the actual data.
+type Difference struct {
+	// Path is the JSON pointer of the value in the serialized data, such as "/items/0/name".
+	Path string
+	// Expected is the formatted expected value, or "unset" if the value should not be set.
+	Expected string
+	// Actual is the formatted actual value, or "unset" if the value is not set.
+	Actual string
+}
+
+// String describes the difference, for example `/timeout: expected 60000000000 (1m), got 120000000000 (2m)`.
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", path, d.Expected, d.Actual)
+}
+
+// AssertOutputEqual fails the test if a step did not return the expected output. The data may be given in serialized
+// or unserialized form, and serialized numbers with units may be given as strings, such as "5m". On failure, the
+// differing values are listed with their paths. Numbers with units are shown with their formatted value, and the values
+// of sensitive properties are redacted.
+func AssertOutputEqual(
+	t testing.TB,
+	step schema.Step,
+	expectedOutputID string,
+	expectedData any,
+	actualOutputID string,
+	actualData any,
+) {
+	t.Helper()
+	output, ok := step.Outputs()[expectedOutputID]
+	if !ok {
+		t.Fatalf("step %s has no output named %s", step.ID(), expectedOutputID)
+		return
+	}
+	if actualOutputID != expectedOutputID {
+		actual := any("unknown output")
+		if actualOutput, ok := step.Outputs()[actualOutputID]; ok {
+			actual = schema.Redact(actualOutput.Schema(), actualData)
+		}
+		t.Fatalf(
+			"expected step %s to return output %s, got output %s\ndata: %v",
+			step.ID(),
+			expectedOutputID,
+			actualOutputID,
+			actual,
+		)
+		return
+	}
+	subject := fmt.Sprintf("output %s of step %s", expectedOutputID, step.ID())
+	assertEqual(t, subject, output.Schema(), expectedData, actualData)
+}
+
+// AssertEqual fails the test if the actual data differs from the expected data of the type. The data is compared the
+// same way as by AssertOutputEqual.
+func AssertEqual(t testing.TB, typ schema.Type, expected any, actual any) {
+	t.Helper()
+	assertEqual(t, "data", typ, expected, actual)
+}
+
+func assertEqual(t testing.TB, subject string, typ schema.Type, expected any, actual any) {
+	t.Helper()
+	differences, err := Diff(typ, expected, actual)
+	if err != nil {
+		t.Fatalf("failed to compare the %s (%v)", subject, err)
+		return
+	}
+	if len(differences) == 0 {
+		return
+	}
+	lines := make([]string, len(differences))
+	for i, difference := range differences {
+		lines[i] = "  " + difference.String()
+	}
+	t.Fatalf("the %s differs from the expected value:\n%s", subject, strings.Join(lines, "\n"))
+}
+
+// Diff compares the expected and actual data of the type and returns the values that differ, ordered by path. Both
+// values are serialized first, so they may be given in either form.
+func Diff(typ schema.Type, expected any, actual any) ([]Difference, error) {
+	serializedExpected, err := serialize(typ, expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the expected value (%w)", err)
+	}
+	serializedActual, err := serialize(typ, actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the actual value (%w)", err)
+	}
+	d := &differ{}
+	d.diff(typ, false, "", serializedExpected, serializedActual)
+	return d.differences, nil
+}
+
+// serialize serializes the data of the type. Data that is partially serialized, such as a map with numbers given as
+// strings with units, is unserialized first.
+func serialize(typ schema.Type, data any) (any, error) {
+	if data == nil {
+		return nil, nil
+	}
+	serialized, err := typ.Serialize(data)
+	if err == nil {
+		return serialized, nil
+	}
+	unserialized, unserializeErr := typ.Unserialize(data)
+	if unserializeErr != nil {
+		return nil, err
+	}
+	return typ.Serialize(unserialized)
+}
+
+type differ struct {
+	differences []Difference
+}
+
+// diff compares the serialized values at the path. The type is nil if the schema does not describe the value, in
+// which case the values are compared structurally.
+func (d *differ) diff(typ schema.Type, sensitive bool, path string, expected any, actual any) {
+	if expected == nil || actual == nil {
+		if expected != nil || actual != nil {
+			d.add(typ, sensitive, path, expected, actual)
+		}
+		return
+	}
+	typ, ok := d.selectVariant(typ, sensitive, path, expected, actual)
+	if !ok {
+		return
+	}
+	expectedValue := reflect.ValueOf(expected)
+	actualValue := reflect.ValueOf(actual)
+	switch {
+	case expectedValue.Kind() == reflect.Map && actualValue.Kind() == reflect.Map:
+		d.diffMaps(typ, sensitive, path, expectedValue, actualValue)
+	case expectedValue.Kind() == reflect.Slice && actualValue.Kind() == reflect.Slice:
+		d.diffSlices(typ, sensitive, path, expectedValue, actualValue)
+	default:
+		if !scalarsEqual(typ, expected, actual) {
+			d.add(typ, sensitive, path, expected, actual)
+		}
+	}
+}
+
+// selectVariant returns the variant type of a one-of for the serialized values. If the values are of different
+// variants, it records the difference in the discriminator and returns false.
+func (d *differ) selectVariant(
+	typ schema.Type,
+	sensitive bool,
+	path string,
+	expected any,
+	actual any,
+) (schema.Type, bool) {
+	if typ == nil || (typ.TypeID() != schema.TypeIDOneOfString && typ.TypeID() != schema.TypeIDOneOfInt) {
+		return typ, true
+	}
+	oneOf, ok := typ.(interface{ DiscriminatorFieldName() string })
+	expectedMap, expectedIsMap := expected.(map[string]any)
+	actualMap, actualIsMap := actual.(map[string]any)
+	if !ok || !expectedIsMap || !actualIsMap {
+		return nil, true
+	}
+	expectedDiscriminator := expectedMap[oneOf.DiscriminatorFieldName()]
+	actualDiscriminator := actualMap[oneOf.DiscriminatorFieldName()]
+	if fmt.Sprint(expectedDiscriminator) != fmt.Sprint(actualDiscriminator) {
+		d.add(nil, sensitive, childPath(path, oneOf.DiscriminatorFieldName()), expectedDiscriminator, actualDiscriminator)
+		return nil, false
+	}
+	variant, err := schema.TypeAtPath(typ, childPath("", fmt.Sprint(expectedDiscriminator)))
+	if err != nil {
+		return nil, true
+	}
+	return variant, true
+}
+
+func (d *differ) diffMaps(typ schema.Type, sensitive bool, path string, expected reflect.Value, actual reflect.Value) {
+	expectedEntries := mapEntries(expected)
+	actualEntries := mapEntries(actual)
+	keys := make([]string, 0, len(expectedEntries)+len(actualEntries))
+	for key := range expectedEntries {
+		keys = append(keys, key)
+	}
+	for key := range actualEntries {
+		if _, ok := expectedEntries[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keySensitive := sensitive
+		if object, ok := typ.(interface {
+			Properties() map[string]*schema.PropertySchema
+		}); ok {
+			if property, isProperty := object.Properties()[key]; isProperty && property.Sensitive() {
+				keySensitive = true
+			}
+		}
+		d.diff(childType(typ, key), keySensitive, childPath(path, key), expectedEntries[key], actualEntries[key])
+	}
+}
+
+func (d *differ) diffSlices(
+	typ schema.Type,
+	sensitive bool,
+	path string,
+	expected reflect.Value,
+	actual reflect.Value,
+) {
+	length := expected.Len()
+	if actual.Len() > length {
+		length = actual.Len()
+	}
+	for i := 0; i < length; i++ {
+		var expectedItem, actualItem any
+		if i < expected.Len() {
+			expectedItem = expected.Index(i).Interface()
+		}
+		if i < actual.Len() {
+			actualItem = actual.Index(i).Interface()
+		}
+		segment := fmt.Sprintf("%d", i)
+		d.diff(childType(typ, segment), sensitive, childPath(path, segment), expectedItem, actualItem)
+	}
+}
+
+func (d *differ) add(typ schema.Type, sensitive bool, path string, expected any, actual any) {
+	d.differences = append(d.differences, Difference{
+		Path:     path,
+		Expected: formatValue(typ, sensitive, expected),
+		Actual:   formatValue(typ, sensitive, actual),
+	})
+}
+
+// scalarsEqual compares two serialized scalar values. Values of a known type are unserialized first, so for example
+// an int and an int64, or 60000000000 and "1m" for a duration, are equal.
+func scalarsEqual(typ schema.Type, expected any, actual any) bool {
+	if typ != nil {
+		unserializedExpected, expectedErr := typ.Unserialize(expected)
+		unserializedActual, actualErr := typ.Unserialize(actual)
+		if expectedErr == nil && actualErr == nil {
+			return reflect.DeepEqual(unserializedExpected, unserializedActual)
+		}
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+// formatValue formats a serialized value for the failure message.
+func formatValue(typ schema.Type, sensitive bool, value any) string {
+	switch {
+	case value == nil:
+		return "unset"
+	case sensitive:
+		return schema.RedactedPlaceholder
+	case typ != nil:
+		value = schema.Redact(typ, value)
+	}
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	withUnits, ok := typ.(interface {
+		Units() *schema.UnitsDefinition
+	})
+	if !ok || withUnits.Units() == nil {
+		return fmt.Sprintf("%v", value)
+	}
+	switch number := value.(type) {
+	case int64:
+		return fmt.Sprintf("%d (%s)", number, withUnits.Units().FormatShortInt(number))
+	case float64:
+		return fmt.Sprintf("%v (%s)", number, withUnits.Units().FormatShortFloat(number))
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// childType returns the type of the value at the segment below the type, or nil if it is not known.
+func childType(typ schema.Type, segment string) schema.Type {
+	if typ == nil {
+		return nil
+	}
+	child, err := schema.TypeAtPath(typ, childPath("", segment))
+	if err != nil {
+		return nil
+	}
+	return child
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func childPath(path string, segment string) string {
+	return path + "/" + jsonPointerEscaper.Replace(segment)
+}
+
+func mapEntries(m reflect.Value) map[string]any {
+	result := make(map[string]any, m.Len())
+	iterator := m.MapRange()
+	for iterator.Next() {
+		result[fmt.Sprint(iterator.Key().Interface())] = iterator.Value().Interface()
+	}
+	return result
+}
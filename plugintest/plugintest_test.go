@@ -0,0 +1,162 @@
+package plugintest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/plugintest"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type testOutput struct {
+	Name    string        `json:"name"`
+	Timeout time.Duration `json:"timeout"`
+	Token   string        `json:"token"`
+	Tags    []string      `json:"tags"`
+}
+
+func testOutputScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(schema.NewStructMappedObjectSchema[testOutput](
+		"output",
+		map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"timeout": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, schema.UnitDurationNanoseconds),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"token": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil).
+				MarkSensitive(),
+			"tags": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	))
+}
+
+func TestDiff(t *testing.T) {
+	scope := testOutputScope()
+	actual := testOutput{Name: "a", Timeout: 2 * time.Minute, Token: "secret", Tags: []string{"x", "y"}}
+
+	differences := assert.NoErrorR[[]plugintest.Difference](t)(plugintest.Diff(scope, map[string]any{
+		"name":    "a",
+		"timeout": "2m",
+		"token":   "secret",
+		"tags":    []any{"x", "y"},
+	}, actual))
+	assert.Equals(t, len(differences), 0)
+
+	differences = assert.NoErrorR[[]plugintest.Difference](t)(plugintest.Diff(scope, testOutput{
+		Name:    "b",
+		Timeout: time.Minute,
+		Token:   "other",
+		Tags:    []string{"x"},
+	}, actual))
+	assert.Equals(t, differences, []plugintest.Difference{
+		{Path: "/name", Expected: `"b"`, Actual: `"a"`},
+		{Path: "/tags/1", Expected: "unset", Actual: `"y"`},
+		{Path: "/timeout", Expected: "60000000000 (1m)", Actual: "120000000000 (2m)"},
+		{Path: "/token", Expected: schema.RedactedPlaceholder, Actual: schema.RedactedPlaceholder},
+	})
+	assert.Equals(t, differences[2].String(), "/timeout: expected 60000000000 (1m), got 120000000000 (2m)")
+}
+
+func TestDiffOneOf(t *testing.T) {
+	oneOf := schema.NewOneOfStringSchema[any](map[string]schema.Object{
+		"a": schema.NewObjectSchema("a", map[string]*schema.PropertySchema{
+			"value": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		}),
+		"b": schema.NewObjectSchema("b", map[string]*schema.PropertySchema{
+			"value": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, schema.UnitBytes),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+	}, "type", false)
+
+	differences := assert.NoErrorR[[]plugintest.Difference](t)(plugintest.Diff(
+		oneOf,
+		map[string]any{"type": "b", "value": "2kB"},
+		map[string]any{"type": "b", "value": 1024},
+	))
+	assert.Equals(t, differences, []plugintest.Difference{
+		{Path: "/value", Expected: "2048 (2kB)", Actual: "1024 (1kB)"},
+	})
+
+	differences = assert.NoErrorR[[]plugintest.Difference](t)(plugintest.Diff(
+		oneOf,
+		map[string]any{"type": "a", "value": "x"},
+		map[string]any{"type": "b", "value": 1024},
+	))
+	assert.Equals(t, differences, []plugintest.Difference{
+		{Path: "/type", Expected: `"a"`, Actual: `"b"`},
+	})
+}
+
+// recordingTB records the failure instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	failure string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failure = fmt.Sprintf(format, args...)
+}
+
+func TestAssertOutputEqual(t *testing.T) {
+	outputs := map[string]*schema.StepOutputSchema{
+		"success": schema.NewStepOutputSchema(testOutputScope(), nil, false),
+	}
+	step := schema.NewCallableStep[testOutput](
+		"test",
+		testOutputScope(),
+		outputs,
+		nil,
+		func(_ context.Context, input testOutput) (string, any) {
+			return "success", input
+		},
+	)
+	input := testOutput{Name: "a", Timeout: time.Second, Token: "secret", Tags: []string{}}
+	outputID, outputData, err := step.Call(context.Background(), "run", input)
+	assert.NoError(t, err)
+
+	plugintest.AssertOutputEqual(t, step, "success", input, outputID, outputData)
+
+	recorder := &recordingTB{}
+	expected := input
+	expected.Timeout = time.Minute
+	plugintest.AssertOutputEqual(recorder, step, "success", expected, outputID, outputData)
+	assert.Equals(
+		t,
+		recorder.failure,
+		"the output success of step test differs from the expected value:\n"+
+			"  /timeout: expected 60000000000 (1m), got 1000000000 (1s)",
+	)
+
+	recorder = &recordingTB{}
+	plugintest.AssertOutputEqual(recorder, step, "error", nil, outputID, outputData)
+	assert.Contains(t, recorder.failure, "has no output named error")
+}
@@ -0,0 +1,255 @@
+package plugintest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultScenarioTimeout is how long a scenario may run if it doesn't set a timeout.
+const DefaultScenarioTimeout = time.Minute
+
+// Scenario describes a step execution and its expected result, so plugins can be tested without writing Go. Scenarios
+// are usually written in YAML files and run with RunScenarioFiles:
+//
+//	name: greets the user
+//	step: hello
+//	input:
+//	  name: Arca
+//	signals:
+//	  - id: pause
+//	    after: 100ms
+//	expected_output_id: success
+//	matchers:
+//	  - path: /message
+//	    matches: "^Hello"
+type Scenario struct {
+	// Name is the name of the scenario, used as the name of the subtest.
+	Name string `json:"name"`
+	// Step is the ID of the step to run.
+	Step string `json:"step"`
+	// Input is the serialized input of the step.
+	Input any `json:"input"`
+	// Signals are sent to the step while it runs.
+	Signals []*ScenarioSignal `json:"signals"`
+	// ExpectedOutputID is the ID of the output the step must return.
+	ExpectedOutputID string `json:"expected_output_id"`
+	// ExpectedOutput is the data the step must return, compared like AssertOutputEqual. If it is not set, only the
+	// matchers are checked.
+	ExpectedOutput any `json:"expected_output"`
+	// Matchers check individual values of the output.
+	Matchers []*Matcher `json:"matchers"`
+	// Timeout is how long the step may run. It defaults to DefaultScenarioTimeout.
+	Timeout *time.Duration `json:"timeout"`
+}
+
+// ScenarioSignal is a signal sent to the step of a scenario.
+type ScenarioSignal struct {
+	// ID is the ID of the signal handler.
+	ID string `json:"id"`
+	// After is how long after the start of the step the signal is sent.
+	After time.Duration `json:"after"`
+	// Data is the serialized data of the signal.
+	Data any `json:"data"`
+}
+
+// Matcher checks the value at a path of the output. All checks that are set must pass.
+type Matcher struct {
+	// Path is the path of the value within the output, as accepted by schema.ValueAtPath.
+	Path string `json:"path"`
+	// Present checks whether the value is set.
+	Present *bool `json:"present"`
+	// Equals is the expected value, compared like AssertEqual.
+	Equals any `json:"equals"`
+	// Contains is a string the formatted value must contain.
+	Contains *string `json:"contains"`
+	// Matches is a regular expression the formatted value must match.
+	Matches *regexp.Regexp `json:"matches"`
+}
+
+// ParseScenario parses a scenario from YAML. Durations may be given with units, such as 1m30s. Keys that appear more
+// than once in a mapping are rejected, since it is unclear which value the author meant.
+func ParseScenario(data []byte) (*Scenario, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario (%w)", err)
+	}
+	result, err := schema.UnserializeYAMLWithOptions(scenarioSchema, node, schema.DecodeOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario (%w)", err)
+	}
+	return result.(*Scenario), nil
+}
+
+// LoadScenario reads and parses a scenario file. If the scenario has no name, the name of the file is used.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Reading the scenario files of the test is the purpose.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario %s (%w)", path, err)
+	}
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scenario %s (%w)", path, err)
+	}
+	if scenario.Name == "" {
+		scenario.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return scenario, nil
+}
+
+// RunScenarioFiles runs the scenarios in the files matching the glob pattern, such as testdata/*.yaml, each as a
+// subtest named after the scenario.
+func RunScenarioFiles(t *testing.T, plugin *schema.CallableSchema, pattern string) {
+	t.Helper()
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatalf("invalid scenario pattern %s (%v)", pattern, err)
+		return
+	}
+	if len(files) == 0 {
+		t.Fatalf("no scenario files match %s", pattern)
+		return
+	}
+	for _, file := range files {
+		scenario, err := LoadScenario(file)
+		if err != nil {
+			t.Fatalf("%v", err)
+			return
+		}
+		t.Run(scenario.Name, func(t *testing.T) {
+			RunScenario(t, plugin, scenario)
+		})
+	}
+}
+
+var scenarioRuns atomic.Int64
+
+// RunScenario runs the step of the scenario, sends its signals, and fails the test if the step doesn't return the
+// expected output.
+func RunScenario(t testing.TB, plugin *schema.CallableSchema, scenario *Scenario) {
+	t.Helper()
+	step, ok := plugin.StepsValue[scenario.Step]
+	if !ok {
+		t.Fatalf("the scenario runs unknown step %s", scenario.Step)
+		return
+	}
+	for _, signal := range scenario.Signals {
+		if _, ok := step.SignalHandlers()[signal.ID]; !ok {
+			t.Fatalf("the scenario sends unknown signal %s to step %s", signal.ID, scenario.Step)
+			return
+		}
+	}
+	timeout := DefaultScenarioTimeout
+	if scenario.Timeout != nil {
+		timeout = *scenario.Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	runID := fmt.Sprintf("scenario-%d", scenarioRuns.Add(1))
+
+	done := make(chan struct{})
+	signalErrors := make(chan error, 1)
+	go func() {
+		signalErrors <- sendSignals(ctx, plugin, runID, scenario, done)
+	}()
+	outputID, outputData, err := plugin.CallStep(ctx, runID, scenario.Step, scenario.Input)
+	close(done)
+	signalErr := <-signalErrors
+	if err != nil {
+		t.Fatalf("step %s failed (%v)", scenario.Step, err)
+		return
+	}
+	if signalErr != nil {
+		t.Fatalf("%v", signalErr)
+		return
+	}
+	if scenario.ExpectedOutput != nil {
+		AssertOutputEqual(t, step, scenario.ExpectedOutputID, scenario.ExpectedOutput, outputID, outputData)
+	} else if outputID != scenario.ExpectedOutputID {
+		t.Fatalf("expected step %s to return output %s, got output %s", step.ID(), scenario.ExpectedOutputID, outputID)
+		return
+	}
+	outputSchema := step.Outputs()[outputID].Schema()
+	for _, matcher := range scenario.Matchers {
+		if err := matcher.check(outputSchema, outputData); err != nil {
+			t.Fatalf("output %s of step %s does not match (%v)", outputID, step.ID(), err)
+			return
+		}
+	}
+}
+
+// sendSignals sends the signals of the scenario in the order of their delays. It fails if the step finishes before
+// all signals are sent.
+func sendSignals(
+	ctx context.Context,
+	plugin *schema.CallableSchema,
+	runID string,
+	scenario *Scenario,
+	done <-chan struct{},
+) error {
+	signals := append([]*ScenarioSignal(nil), scenario.Signals...)
+	sort.SliceStable(signals, func(i, j int) bool { return signals[i].After < signals[j].After })
+	start := time.Now()
+	for _, signal := range signals {
+		timer := time.NewTimer(time.Until(start.Add(signal.After)))
+		select {
+		case <-done:
+			timer.Stop()
+			return fmt.Errorf("step %s finished before signal %s was sent", scenario.Step, signal.ID)
+		case <-timer.C:
+		}
+		if err := plugin.CallSignal(ctx, runID, scenario.Step, signal.ID, signal.Data); err != nil {
+			return fmt.Errorf("failed to send signal %s to step %s (%w)", signal.ID, scenario.Step, err)
+		}
+	}
+	return nil
+}
+
+func (m *Matcher) check(outputSchema schema.Type, outputData any) error {
+	value, err := schema.ValueAtPath(outputSchema, outputData, m.Path)
+	if err != nil {
+		return err
+	}
+	if m.Present != nil && *m.Present != (value != nil) {
+		if *m.Present {
+			return fmt.Errorf("%s is not set", m.Path)
+		}
+		return fmt.Errorf("%s is set to %v", m.Path, value)
+	}
+	if m.Equals != nil {
+		valueType, err := schema.TypeAtPath(outputSchema, m.Path)
+		if err != nil {
+			return err
+		}
+		differences, err := Diff(valueType, m.Equals, value)
+		if err != nil {
+			return err
+		}
+		if len(differences) > 0 {
+			lines := make([]string, len(differences))
+			for i, difference := range differences {
+				difference.Path = m.Path + difference.Path
+				lines[i] = difference.String()
+			}
+			return fmt.Errorf("%s", strings.Join(lines, "; "))
+		}
+	}
+	formatted := fmt.Sprint(value)
+	if m.Contains != nil && !strings.Contains(formatted, *m.Contains) {
+		return fmt.Errorf("%s is %q, which does not contain %q", m.Path, formatted, *m.Contains)
+	}
+	if m.Matches != nil && !m.Matches.MatchString(formatted) {
+		return fmt.Errorf("%s is %q, which does not match %s", m.Path, formatted, m.Matches)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package plugintest
+
+import (
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// scenarioSchema describes the scenario files, so they are validated and their durations parsed like plugin inputs.
+var scenarioSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[*Scenario](
+		"Scenario",
+		map[string]*schema.PropertySchema{
+			"name": scenarioProperty(
+				schema.NewStringSchema(nil, nil, nil),
+				"Name",
+				"Name of the scenario. Defaults to the name of the file.",
+				false,
+			),
+			"step": scenarioProperty(
+				schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+				"Step",
+				"ID of the step to run.",
+				true,
+			),
+			"input": scenarioProperty(
+				schema.NewAnySchema(),
+				"Input",
+				"Serialized input of the step.",
+				true,
+			),
+			"signals": scenarioProperty(
+				schema.NewListSchema(schema.NewRefSchema("ScenarioSignal", nil), nil, nil),
+				"Signals",
+				"Signals sent to the step while it runs.",
+				false,
+			),
+			"expected_output_id": scenarioProperty(
+				schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+				"Expected output ID",
+				"ID of the output the step must return.",
+				true,
+			),
+			"expected_output": scenarioProperty(
+				schema.NewAnySchema(),
+				"Expected output",
+				"Data the step must return. Numbers with units may be given with their units, such as 5m.",
+				false,
+			),
+			"matchers": scenarioProperty(
+				schema.NewListSchema(schema.NewRefSchema("Matcher", nil), nil, nil),
+				"Matchers",
+				"Checks of individual values of the output.",
+				false,
+			),
+			"timeout": scenarioProperty(
+				schema.NewIntSchema(schema.PointerTo(int64(1)), nil, schema.UnitDurationNanoseconds),
+				"Timeout",
+				"How long the step may run.",
+				false,
+			),
+		},
+	),
+	schema.NewStructMappedObjectSchema[*ScenarioSignal](
+		"ScenarioSignal",
+		map[string]*schema.PropertySchema{
+			"id": scenarioProperty(
+				schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+				"ID",
+				"ID of the signal handler.",
+				true,
+			),
+			"after": scenarioProperty(
+				schema.NewIntSchema(schema.PointerTo(int64(0)), nil, schema.UnitDurationNanoseconds),
+				"After",
+				"How long after the start of the step the signal is sent.",
+				false,
+			),
+			"data": scenarioProperty(
+				schema.NewAnySchema(),
+				"Data",
+				"Serialized data of the signal.",
+				false,
+			),
+		},
+	),
+	schema.NewStructMappedObjectSchema[*Matcher](
+		"Matcher",
+		map[string]*schema.PropertySchema{
+			"path": scenarioProperty(
+				schema.NewStringSchema(nil, nil, nil),
+				"Path",
+				"Path of the value within the output, such as /items/0/name.",
+				true,
+			),
+			"present": scenarioProperty(
+				schema.NewBoolSchema(),
+				"Present",
+				"Whether the value must be set.",
+				false,
+			),
+			"equals": scenarioProperty(
+				schema.NewAnySchema(),
+				"Equals",
+				"Expected value.",
+				false,
+			),
+			"contains": scenarioProperty(
+				schema.NewStringSchema(nil, nil, nil),
+				"Contains",
+				"String the formatted value must contain.",
+				false,
+			),
+			"matches": scenarioProperty(
+				schema.NewPatternSchema(),
+				"Matches",
+				"Regular expression the formatted value must match.",
+				false,
+			),
+		},
+	),
+)
+
+func scenarioProperty(t schema.Type, name string, description string, required bool) *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		t,
+		schema.NewDisplayValue(schema.PointerTo(name), schema.PointerTo(description), nil),
+		required,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
@@ -0,0 +1,138 @@
+package plugintest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/plugintest"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type waitInput struct {
+	Name string        `json:"name"`
+	Wait time.Duration `json:"wait"`
+}
+
+type waitOutput struct {
+	Message   string        `json:"message"`
+	StoppedBy *string       `json:"stopped_by,omitempty"`
+	Waited    time.Duration `json:"waited"`
+}
+
+type stopSignal struct {
+	Reason string `json:"reason"`
+}
+
+type waitState struct {
+	stop chan string
+}
+
+func waitPlugin() *schema.CallableSchema {
+	duration := func() *schema.PropertySchema {
+		return schema.NewPropertySchema(
+			schema.NewIntSchema(nil, nil, schema.UnitDurationNanoseconds), nil, false, nil, nil, nil, nil, nil,
+		)
+	}
+	text := func(required bool) *schema.PropertySchema {
+		return schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, required, nil, nil, nil, nil, nil)
+	}
+	return schema.NewCallableSchema(schema.NewCallableStepWithSignals[*waitState, waitInput](
+		"wait",
+		schema.NewScopeSchema(schema.NewStructMappedObjectSchema[waitInput](
+			"input",
+			map[string]*schema.PropertySchema{"name": text(true), "wait": duration()},
+		)),
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(schema.NewScopeSchema(schema.NewStructMappedObjectSchema[waitOutput](
+				"output",
+				map[string]*schema.PropertySchema{"message": text(true), "stopped_by": text(false), "waited": duration()},
+			)), nil, false),
+		},
+		map[string]schema.CallableSignal{
+			"stop": schema.NewCallableSignal(
+				"stop",
+				schema.NewScopeSchema(schema.NewStructMappedObjectSchema[stopSignal](
+					"stop",
+					map[string]*schema.PropertySchema{"reason": text(true)},
+				)),
+				nil,
+				func(_ context.Context, state *waitState, signal stopSignal) {
+					state.stop <- signal.Reason
+				},
+			),
+		},
+		nil,
+		nil,
+		func() *waitState {
+			return &waitState{stop: make(chan string, 1)}
+		},
+		func(ctx context.Context, state *waitState, input waitInput) (string, any) {
+			output := waitOutput{Message: "Hello " + input.Name + "!"}
+			if input.Wait == 0 {
+				return "success", output
+			}
+			start := time.Now()
+			select {
+			case reason := <-state.stop:
+				output.StoppedBy = &reason
+			case <-time.After(input.Wait):
+			case <-ctx.Done():
+			}
+			output.Waited = time.Since(start)
+			return "success", output
+		},
+	))
+}
+
+func TestRunScenarioFiles(t *testing.T) {
+	plugintest.RunScenarioFiles(t, waitPlugin(), "testdata/scenarios/*.yaml")
+}
+
+func TestLoadScenario(t *testing.T) {
+	scenario := assert.NoErrorR[*plugintest.Scenario](t)(plugintest.LoadScenario("testdata/scenarios/stop.yaml"))
+	assert.Equals(t, scenario.Name, "stop")
+	assert.Equals(t, scenario.Signals[0].After, 10*time.Millisecond)
+	assert.Equals(t, *scenario.Timeout, 10*time.Second)
+
+	_, err := plugintest.ParseScenario([]byte("step: wait\nexpected_output_id: success\n"))
+	assert.Error(t, err)
+	_, err = plugintest.ParseScenario([]byte("step: wait\ninput: {}\nexpected_output_id: success\ntimeout: soon\n"))
+	assert.Error(t, err)
+	_, err = plugintest.ParseScenario([]byte("step: wait\nstep: stop\ninput: {}\nexpected_output_id: success\n"))
+	assert.Error(t, err)
+}
+
+func TestRunScenarioFailures(t *testing.T) {
+	plugin := waitPlugin()
+	for name, tc := range map[string]struct {
+		scenario string
+		failure  string
+	}{
+		"output": {
+			"step: wait\ninput: {name: Arca}\nexpected_output_id: success\nexpected_output: {message: Hi}\n",
+			"the output success of step wait differs from the expected value:\n" +
+				`  /message: expected "Hi", got "Hello Arca!"`,
+		},
+		"matcher": {
+			"step: wait\ninput: {name: Arca}\nexpected_output_id: success\nmatchers: [{path: /message, contains: Bye}]\n",
+			`output success of step wait does not match (/message is "Hello Arca!", which does not contain "Bye")`,
+		},
+		"late signal": {
+			"step: wait\ninput: {name: Arca}\nexpected_output_id: success\nsignals: [{id: stop, after: 1m}]\n",
+			"step wait finished before signal stop was sent",
+		},
+		"unknown signal": {
+			"step: wait\ninput: {name: Arca}\nexpected_output_id: success\nsignals: [{id: pause}]\n",
+			"the scenario sends unknown signal pause to step wait",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			scenario := assert.NoErrorR[*plugintest.Scenario](t)(plugintest.ParseScenario([]byte(tc.scenario)))
+			recorder := &recordingTB{}
+			plugintest.RunScenario(recorder, plugin, scenario)
+			assert.Equals(t, recorder.failure, tc.failure)
+		})
+	}
+}
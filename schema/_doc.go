@@ -1,2 +1,8 @@
 // Package schema contains the Arcaflow schema system.
+//
+// Schemas are built with the constructors and builder methods, and then linked by applying their scope. After that,
+// Unserialize, Serialize, Validate and the other methods of the Type interface may be called on the same schema from
+// any number of goroutines. The caches the types fill on first use are built behind atomic pointers or sync.Once, so
+// they don't need locking by the caller. Builder methods and ApplyNamespace modify the schema and must not be called
+// while it is in use.
 package schema
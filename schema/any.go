@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -20,6 +22,11 @@ func (a *AnySchema) ReflectedType() reflect.Type {
 	return reflect.TypeOf(&defaultValue).Elem()
 }
 
+func (a *AnySchema) ApplyDefaults(serialized any) any {
+	// Any types may hold maps and lists, which must be copied so the result does not share them with the input.
+	return copySerializedValue(serialized)
+}
+
 func (a *AnySchema) Unserialize(data any) (any, error) {
 	return a.checkAndConvert(data)
 }
@@ -252,6 +259,9 @@ func (a *AnySchema) checkAndConvert(data any) (any, error) {
 	case reflect.Float64:
 		return asFloat(data)
 	case reflect.String:
+		if number, ok := data.(json.Number); ok {
+			return jsonNumberToAny(number)
+		}
 		return data.(string), nil
 	case reflect.Bool:
 		return asBool(data)
@@ -267,12 +277,20 @@ func (a *AnySchema) checkAndConvert(data any) (any, error) {
 		return result, nil
 	case reflect.Map:
 		result := make(map[any]any, t.Len())
-		for _, k := range t.MapKeys() {
+		for iter := t.MapRange(); iter.Next(); {
+			k := iter.Key()
 			key, err := a.checkAndConvert(k.Interface())
 			if err != nil {
 				return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("{%v}", k))
 			}
-			v := t.MapIndex(k)
+			if floatKey, ok := key.(float64); ok && math.IsNaN(floatKey) {
+				// NaN keys are never equal to each other, so they cannot be looked up or serialized reliably.
+				return nil, &ConstraintError{
+					Message: "NaN is not a valid map key",
+					Path:    []string{fmt.Sprintf("{%v}", k)},
+				}
+			}
+			v := iter.Value()
 			value, err := a.checkAndConvert(v.Interface())
 			if err != nil {
 				return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%v]", key))
@@ -286,3 +304,21 @@ func (a *AnySchema) checkAndConvert(data any) (any, error) {
 		}
 	}
 }
+
+// jsonNumberToAny converts a number decoded by a json.Decoder with UseNumber to an int64 if it is an integer, and to a
+// float64 otherwise.
+func jsonNumberToAny(v json.Number) (any, error) {
+	if i, err := v.Int64(); err == nil {
+		return i, nil
+	}
+	f, err := v.Float64()
+	if err != nil {
+		return nil, &ConstraintError{
+			Message:    fmt.Sprintf("Invalid number %q (%v)", v.String(), err),
+			Constraint: ConstraintDataType,
+			Expected:   "number",
+			Actual:     "json.Number",
+		}
+	}
+	return f, nil
+}
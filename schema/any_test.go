@@ -1,7 +1,9 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"go.arcalot.io/assert"
+	"math"
 	"testing"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
@@ -288,3 +290,21 @@ func TestAnyValidateCompatibilityMaps(t *testing.T) {
 	assert.Contains(t, err.Error(), "string")
 	assert.Contains(t, err.Error(), "int64")
 }
+
+func TestAnyNaNKey(t *testing.T) {
+	s := schema.NewAnySchema()
+	_, err := s.Unserialize(map[any]any{math.NaN(): 1})
+	assert.Error(t, err)
+	assert.Error(t, s.Validate(map[any]any{math.NaN(): 1}))
+	_, err = s.Serialize(map[any]any{math.NaN(): 1})
+	assert.Error(t, err)
+}
+
+func TestAnyJSONNumber(t *testing.T) {
+	anySchema := schema.NewAnySchema()
+	assert.Equals(t, assert.NoErrorR[any](t)(anySchema.Unserialize(json.Number("9007199254740993"))),
+		any(int64(9007199254740993)))
+	assert.Equals(t, assert.NoErrorR[any](t)(anySchema.Unserialize([]any{json.Number("1.5")})), any([]any{1.5}))
+	_, err := anySchema.Unserialize(json.Number("1e400"))
+	assert.Error(t, err)
+}
@@ -29,7 +29,12 @@ func (b BoolSchema) Unserialize(data any) (any, error) {
 		case 0:
 			return false, nil
 		default:
-			return false, fmt.Errorf("'%d' is not a valid boolean value", data)
+			return false, &ConstraintError{
+				Message:    fmt.Sprintf("'%d' is not a valid boolean value", data),
+				Constraint: ConstraintDataType,
+				Expected:   "bool",
+				Actual:     fmt.Sprintf("%T", data),
+			}
 		}
 	}
 	switch v := data.(type) {
@@ -61,7 +66,12 @@ func (b BoolSchema) Unserialize(data any) (any, error) {
 	case uint8:
 		return intConverter(int64(v))
 	}
-	return false, fmt.Errorf("'%v' is not a valid boolean value", data)
+	return false, &ConstraintError{
+		Message:    fmt.Sprintf("'%v' is not a valid boolean value", data),
+		Constraint: ConstraintDataType,
+		Expected:   "bool",
+		Actual:     fmt.Sprintf("%T", data),
+	}
 }
 
 func (b BoolSchema) UnserializeType(data any) (bool, error) {
@@ -82,7 +92,10 @@ func (b BoolSchema) ValidateCompatibility(typeOrData any) error {
 
 	if schemaType.TypeID() != TypeIDBool {
 		return &ConstraintError{
-			Message: fmt.Sprintf("unsupported data type for 'bool' type: %T", schemaType),
+			Message:    fmt.Sprintf("unsupported data type for 'bool' type: %T", schemaType),
+			Constraint: ConstraintDataType,
+			Expected:   "bool",
+			Actual:     fmt.Sprintf("%T", schemaType),
 		}
 	}
 	// No need to do further verification because booleans don't have any other fields.
@@ -90,12 +103,12 @@ func (b BoolSchema) ValidateCompatibility(typeOrData any) error {
 }
 
 func (b BoolSchema) Validate(data any) error {
-	_, err := b.Serialize(data)
+	_, err := asBool(data)
 	return err
 }
 
-func (b BoolSchema) ValidateType(data bool) error {
-	return b.Validate(data)
+func (b BoolSchema) ValidateType(_ bool) error {
+	return nil
 }
 
 func (b BoolSchema) Serialize(d any) (any, error) {
@@ -108,9 +121,12 @@ func asBool(d any) (bool, error) {
 		var i bool
 		intType := reflect.TypeOf(i)
 		dValue := reflect.ValueOf(d)
-		if !dValue.CanConvert(intType) {
+		if !dValue.IsValid() || !dValue.CanConvert(intType) {
 			return false, &ConstraintError{
-				Message: fmt.Sprintf("%T is not a valid data type for a bool schema.", d),
+				Message:    fmt.Sprintf("%T is not a valid data type for a bool schema.", d),
+				Constraint: ConstraintDataType,
+				Expected:   "bool",
+				Actual:     fmt.Sprintf("%T", d),
 			}
 		}
 		data = dValue.Convert(intType).Bool()
@@ -219,3 +219,19 @@ func TestBoolSchema_ValidateCompatibility(t *testing.T) {
 	assert.Error(t, s1.ValidateCompatibility([]string{}))
 	assert.Error(t, s1.ValidateCompatibility(map[string]any{}))
 }
+
+func TestBoolNilValue(t *testing.T) {
+	assert.Error(t, schema.NewBoolSchema().Validate(nil))
+	_, err := schema.NewBoolSchema().Serialize(nil)
+	assert.Error(t, err)
+}
+
+func TestBoolAllocations(t *testing.T) {
+	s := schema.NewBoolSchema()
+	var data any = true
+	assert.Equals(t, testing.AllocsPerRun(100, func() {
+		_, _ = s.Serialize(data)
+		_, _ = s.Unserialize(data)
+		_ = s.Validate(data)
+	}), float64(0))
+}
@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SerializeCanonical serializes the data with the type and encodes the result with MarshalCanonical, so the same
+// data always produces the same bytes. Use it to hash step inputs for caching or to write golden files.
+func SerializeCanonical(t Type, data any) ([]byte, error) {
+	serialized, err := t.Serialize(data)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalCanonical(serialized)
+}
+
+// MarshalCanonical encodes serialized data as JSON in a canonical form: map keys are sorted by their string form,
+// integers are written in decimal, floats with an integral value are written as integers, and all other floats in
+// their shortest representation. No whitespace is added and HTML characters are not escaped. NaN and infinite floats
+// are rejected, since JSON cannot represent them.
+func MarshalCanonical(serialized any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := writeCanonical(buf, reflect.ValueOf(serialized)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	if ordered, ok := v.Interface().(orderedMap); ok {
+		return writeCanonicalMap(buf, ordered.entries())
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeCanonical(buf, v.Elem())
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return writeCanonicalFloat(buf, v.Float())
+	case reflect.String:
+		return writeCanonicalString(buf, v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, v.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		buf.WriteByte(']')
+	case reflect.Map:
+		entries, _ := mapEntriesOf(v.Interface())
+		return writeCanonicalMap(buf, entries)
+	default:
+		return fmt.Errorf("cannot encode %s canonically, only serialized data is supported", v.Type())
+	}
+	return nil
+}
+
+func writeCanonicalMap(buf *bytes.Buffer, entries []mapEntry) error {
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		key := entry.key
+		for key.Kind() == reflect.Interface && !key.IsNil() {
+			key = key.Elem()
+		}
+		switch key.Kind() {
+		case reflect.String:
+			keys[i] = key.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			keys[i] = strconv.FormatInt(key.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			keys[i] = strconv.FormatUint(key.Uint(), 10)
+		default:
+			return fmt.Errorf("cannot encode map key of type %s canonically", key.Type())
+		}
+	}
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return keys[order[i]] < keys[order[j]]
+	})
+	buf.WriteByte('{')
+	for i, index := range order {
+		if i > 0 {
+			if keys[index] == keys[order[i-1]] {
+				return fmt.Errorf("duplicate map key %q in canonical form", keys[index])
+			}
+			buf.WriteByte(',')
+		}
+		if err := writeCanonicalString(buf, keys[index]); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := writeCanonical(buf, entries[index].value); err != nil {
+			return fmt.Errorf("[%s]: %w", keys[index], err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeCanonicalFloat(buf *bytes.Buffer, f float64) error {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return fmt.Errorf("cannot encode %v canonically", f)
+	case f == math.Trunc(f) && math.Abs(f) < 1e21:
+		if f == 0 {
+			// Turns negative zero into 0.
+			f = 0
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+	default:
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return err
+	}
+	// Encode terminates the value with a newline.
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
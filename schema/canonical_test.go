@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"math"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	encoded := assert.NoErrorR[[]byte](t)(schema.MarshalCanonical(map[any]any{
+		"b":    []any{int64(1), 2.0, 2.5, math.Copysign(0, -1), 1e21, true, nil},
+		"a":    map[int64]any{10: "<x>", 9: "y"},
+		"c":    "\"quoted\"",
+		"list": []string(nil),
+	}))
+	assert.Equals(
+		t,
+		string(encoded),
+		`{"a":{"10":"<x>","9":"y"},"b":[1,2,2.5,0,1e+21,true,null],"c":"\"quoted\"","list":null}`,
+	)
+
+	ordered := schema.NewOrderedMap[any, any]()
+	ordered.Set("b", int64(1))
+	ordered.Set("a", int64(2))
+	encoded = assert.NoErrorR[[]byte](t)(schema.MarshalCanonical(ordered))
+	assert.Equals(t, string(encoded), `{"a":2,"b":1}`)
+
+	assert.ErrorR(t)(schema.MarshalCanonical(math.NaN()))
+	assert.ErrorR(t)(schema.MarshalCanonical(map[any]any{int64(1): "x", "1": "y"}))
+	assert.ErrorR(t)(schema.MarshalCanonical(struct{}{}))
+}
+
+func TestSerializeCanonical(t *testing.T) {
+	objectType := schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"age": schema.NewPropertySchema(
+			schema.NewIntSchema(nil, nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	})
+	// The same data encodes the same way every time, regardless of the map iteration order.
+	for i := 0; i < 10; i++ {
+		encoded := assert.NoErrorR[[]byte](t)(schema.SerializeCanonical(
+			objectType,
+			map[string]any{"name": "Arca Lot", "age": int64(3)},
+		))
+		assert.Equals(t, string(encoded), `{"age":3,"name":"Arca Lot"}`)
+	}
+	assert.ErrorR(t)(schema.SerializeCanonical(objectType, map[string]any{"name": "Arca Lot"}))
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// schemaEncMode encodes schemas with the deterministic core encoding of RFC 8949, so the same schema always results
+// in the same bytes.
+var schemaEncMode = func() cbor.EncMode {
+	encMode, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(BadArgumentError{Message: fmt.Sprintf("failed to create CBOR encoder (%v)", err)})
+	}
+	return encMode
+}()
+
+// MarshalCBOR encodes the self-serialized scope as deterministic CBOR. Unlike JSON, CBOR keeps integers and floats
+// apart, so limits and units survive the round trip unchanged.
+func (s *ScopeSchema) MarshalCBOR() ([]byte, error) {
+	return marshalSchemaCBOR(scopeScopeSchema, s)
+}
+
+// UnmarshalCBOR decodes a scope encoded with MarshalCBOR.
+func (s *ScopeSchema) UnmarshalCBOR(data []byte) error {
+	result, err := unmarshalSchemaCBOR(scopeScopeSchema, data)
+	if err != nil {
+		return err
+	}
+	*s = *result.(*ScopeSchema)
+	return nil
+}
+
+// MarshalCBOR encodes the self-serialized step as deterministic CBOR.
+func (s *StepSchema) MarshalCBOR() ([]byte, error) {
+	return marshalSchemaCBOR(stepScopeSchema, s)
+}
+
+// UnmarshalCBOR decodes a step encoded with MarshalCBOR. Like UnserializeSchema, it resolves the units of the step
+// against the DefaultUnitsRegistry.
+func (s *StepSchema) UnmarshalCBOR(data []byte) error {
+	result, err := unmarshalSchemaCBOR(stepScopeSchema, data)
+	if err != nil {
+		return err
+	}
+	step := result.(*StepSchema)
+	steps := SchemaSchema{StepsValue: map[string]*StepSchema{step.IDValue: step}}
+	steps.applyNamespace()
+	steps.resolveUnits(DefaultUnitsRegistry)
+	*s = *step
+	return nil
+}
+
+// MarshalCBOR encodes the self-serialized function as deterministic CBOR.
+func (f FunctionSchema) MarshalCBOR() ([]byte, error) {
+	return marshalSchemaCBOR(functionScopeSchema, &f)
+}
+
+// UnmarshalCBOR decodes a function encoded with MarshalCBOR.
+func (f *FunctionSchema) UnmarshalCBOR(data []byte) error {
+	result, err := unmarshalSchemaCBOR(functionScopeSchema, data)
+	if err != nil {
+		return err
+	}
+	*f = *result.(*FunctionSchema)
+	return nil
+}
+
+func marshalSchemaCBOR(describedBy *ScopeSchema, s any) ([]byte, error) {
+	serialized, err := describedBy.Serialize(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize %s schema (%w)", describedBy.RootValue, err)
+	}
+	encoded, err := schemaEncMode.Marshal(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s schema (%w)", describedBy.RootValue, err)
+	}
+	return encoded, nil
+}
+
+func unmarshalSchemaCBOR(describedBy *ScopeSchema, data []byte) (any, error) {
+	var decoded any
+	if err := cbor.Unmarshal(data, &decoded); err != nil {
+		return nil, &UnserializationError{
+			Message: fmt.Sprintf("failed to decode %s schema", describedBy.RootValue),
+			Cause:   err,
+		}
+	}
+	result, err := describedBy.Unserialize(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unserialize %s schema (%w)", describedBy.RootValue, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestScopeSchemaCBOR(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+		"count": schema.NewPropertySchema(
+			schema.NewIntSchema(schema.PointerTo(int64(1)), schema.PointerTo(int64(1)<<60+1), schema.UnitBytes),
+			nil, true, nil, nil, nil, nil, nil,
+		),
+		"ratio": schema.NewPropertySchema(
+			schema.NewFloatSchema(schema.PointerTo(1.0), schema.PointerTo(2.5), nil),
+			nil, false, nil, nil, nil, nil, nil,
+		),
+	}))
+	encoded := assert.NoErrorR[[]byte](t)(cbor.Marshal(scope))
+	// The encoding is deterministic.
+	assert.Equals(t, assert.NoErrorR[[]byte](t)(cbor.Marshal(scope)), encoded)
+
+	decoded := &schema.ScopeSchema{}
+	assert.NoError(t, cbor.Unmarshal(encoded, decoded))
+	properties := decoded.Properties()
+	count := properties["count"].Type().(*schema.IntSchema)
+	assert.Equals(t, *count.Max(), int64(1)<<60+1)
+	ratio := properties["ratio"].Type().(*schema.FloatSchema)
+	assert.Equals(t, *ratio.Min(), 1.0)
+	assert.Equals(t, assert.NoErrorR[[]byte](t)(cbor.Marshal(decoded)), encoded)
+}
+
+func TestStepSchemaCBOR(t *testing.T) {
+	step := engineTestSchema().StepsValue["basic"].ToStepSchema()
+	encoded := assert.NoErrorR[[]byte](t)(cbor.Marshal(step))
+
+	decoded := &schema.StepSchema{}
+	assert.NoError(t, cbor.Unmarshal(encoded, decoded))
+	assert.Equals(t, decoded.ID(), "basic")
+	assert.Equals(t, assert.NoErrorR[[]byte](t)(cbor.Marshal(decoded)), encoded)
+	_, err := decoded.Input().Unserialize(map[string]any{"name": "Arca", "color": "red"})
+	assert.NoError(t, err)
+
+	assert.Error(t, cbor.Unmarshal([]byte{0xa1, 0x61, 0x78, 0x01}, &schema.StepSchema{}))
+}
+
+func TestFunctionSchemaCBOR(t *testing.T) {
+	function := assert.NoErrorR[schema.CallableFunction](t)(schema.NewCallableFunction(
+		"greet",
+		[]schema.Type{schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil)},
+		schema.NewStringSchema(nil, nil, nil),
+		false,
+		schema.NewDisplayValue(schema.PointerTo("Greet"), nil, nil),
+		func(name string, times int64) string {
+			return name
+		},
+	))
+	functionSchema := assert.NoErrorR[*schema.FunctionSchema](t)(
+		function.(*schema.CallableFunctionSchema).ToFunctionSchema(),
+	)
+	encoded := assert.NoErrorR[[]byte](t)(cbor.Marshal(functionSchema))
+
+	decoded := schema.FunctionSchema{}
+	assert.NoError(t, cbor.Unmarshal(encoded, &decoded))
+	assert.Equals(t, decoded.String(), "greet(string, integer) string")
+	assert.Equals(t, *decoded.Display().Name(), "Greet")
+	assert.Equals(t, assert.NoErrorR[[]byte](t)(cbor.Marshal(decoded)), encoded)
+}
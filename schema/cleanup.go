@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type cleanupContextKey struct{}
+
+type debugLogContextKey struct{}
+
+// cleanupRegistry holds the cleanup functions registered by a running step.
+type cleanupRegistry struct {
+	lock     sync.Mutex
+	cleanups []func(context.Context) error
+}
+
+// OnCleanup registers a function that runs when the step the context belongs to finishes, regardless of whether it
+// returned an output, failed, was cancelled or panicked. Use it to release resources the step created, such as
+// cloud resources. The functions run in reverse registration order with a context that is not cancelled along with
+// the step, and the result of each is reported in the debug log of the step. It panics if the context does not
+// belong to a running step.
+func OnCleanup(ctx context.Context, cleanup func(context.Context) error) {
+	registry, ok := ctx.Value(cleanupContextKey{}).(*cleanupRegistry)
+	if !ok {
+		panic(BadArgumentError{
+			Message: "OnCleanup called with a context that does not belong to a running step",
+		})
+	}
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.cleanups = append(registry.cleanups, cleanup)
+}
+
+// contextWithCleanups returns a copy of the context that step handlers can register cleanup functions on.
+func contextWithCleanups(ctx context.Context) (context.Context, *cleanupRegistry) {
+	registry := &cleanupRegistry{}
+	return context.WithValue(ctx, cleanupContextKey{}, registry), registry
+}
+
+// run calls the registered cleanup functions in reverse order and reports their results in the debug log. Panics in
+// cleanup functions are recovered and reported like errors, so the remaining functions still run.
+func (r *cleanupRegistry) run(ctx context.Context) {
+	r.lock.Lock()
+	cleanups := r.cleanups
+	r.cleanups = nil
+	r.lock.Unlock()
+	if len(cleanups) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	DebugLogf(ctx, "Running %d cleanup functions...", len(cleanups))
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := callCleanup(ctx, cleanups[i]); err != nil {
+			DebugLogf(ctx, "Cleanup %d of %d failed: %v", i+1, len(cleanups), err)
+		} else {
+			DebugLogf(ctx, "Cleanup %d of %d succeeded.", i+1, len(cleanups))
+		}
+	}
+}
+
+func callCleanup(ctx context.Context, cleanup func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return cleanup(ctx)
+}
+
+// debugLog serializes the writes of concurrent goroutines to the debug log writer.
+type debugLog struct {
+	lock   sync.Mutex
+	writer io.Writer
+}
+
+// ContextWithDebugLog returns a copy of the context that collects the debug log lines of a step, such as its cleanup
+// report, into the writer. The ATP server sends these lines to the client along with the step output.
+func ContextWithDebugLog(ctx context.Context, writer io.Writer) context.Context {
+	return context.WithValue(ctx, debugLogContextKey{}, &debugLog{writer: writer})
+}
+
+// DebugLogf writes a line to the debug log of the step the context belongs to. If the context has no debug log, the
+// line is discarded.
+func DebugLogf(ctx context.Context, format string, args ...any) {
+	log, ok := ctx.Value(debugLogContextKey{}).(*debugLog)
+	if !ok {
+		return
+	}
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	_, _ = fmt.Fprintf(log.writer, format+"\n", args...)
+}
@@ -0,0 +1,90 @@
+package schema_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func cleanupTestStep(handler func(ctx context.Context, input stepTestInputData) (string, any)) schema.CallableStep {
+	return schema.NewCallableStep(
+		"hello",
+		testStepSchema.Input().(*schema.ScopeSchema),
+		testStepSchema.Outputs(),
+		nil,
+		handler,
+	)
+}
+
+func TestOnCleanup(t *testing.T) {
+	testData := map[string]struct {
+		handler  func(ctx context.Context, input stepTestInputData) (string, any)
+		expected []string
+	}{
+		"success": {
+			func(ctx context.Context, input stepTestInputData) (string, any) {
+				schema.OnCleanup(ctx, func(ctx context.Context) error { return nil })
+				schema.OnCleanup(ctx, func(ctx context.Context) error { return fmt.Errorf("resource gone") })
+				return stepTestHandler(ctx, input)
+			},
+			[]string{
+				"Running 2 cleanup functions...",
+				"Cleanup 2 of 2 failed: resource gone",
+				"Cleanup 1 of 2 succeeded.",
+			},
+		},
+		"panic": {
+			func(ctx context.Context, input stepTestInputData) (string, any) {
+				schema.OnCleanup(ctx, func(ctx context.Context) error { panic("cleanup panic") })
+				panic("step panic")
+			},
+			[]string{
+				"Running 1 cleanup functions...",
+				"Cleanup 1 of 1 failed: panic: cleanup panic",
+			},
+		},
+		"cancelled": {
+			func(ctx context.Context, input stepTestInputData) (string, any) {
+				schema.OnCleanup(ctx, func(ctx context.Context) error { return ctx.Err() })
+				<-ctx.Done()
+				return "error", stepTestErrorOutput{"cancelled"}
+			},
+			[]string{
+				"Running 1 cleanup functions...",
+				"Cleanup 1 of 1 succeeded.",
+			},
+		},
+	}
+	for name, testCase := range testData {
+		tc := testCase
+		t.Run(name, func(t *testing.T) {
+			logs := &bytes.Buffer{}
+			ctx, cancel := context.WithCancel(schema.ContextWithDebugLog(context.Background(), logs))
+			cancel()
+			step := cleanupTestStep(tc.handler)
+			func() {
+				defer func() {
+					_ = recover()
+				}()
+				_, _, _ = step.Call(ctx, t.Name(), stepTestInputData{Name: "Arca Lot"})
+			}()
+			assert.Equals(t, strings.Split(strings.TrimSpace(logs.String()), "\n"), tc.expected)
+		})
+	}
+}
+
+func TestOnCleanupOutsideStep(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.OnCleanup(context.Background(), func(ctx context.Context) error { return nil })
+	})
+}
+
+func TestDebugLogfWithoutLog(t *testing.T) {
+	// Without a debug log the lines are discarded.
+	schema.DebugLogf(context.Background(), "test %d", 1)
+}
@@ -0,0 +1,180 @@
+package schema
+
+import (
+	"reflect"
+)
+
+// CloneValue returns a deep copy of an unserialized value of the type, such as a step input, so the copy can be
+// handed to a handler or changed without affecting the original. The schema guides the copy: struct fields of the
+// properties, list items, map values, tuple items and one-of variants are copied according to their types, and the
+// copy keeps the Go types of the original, including pointers and ordered maps. Strings and other scalars are
+// immutable and are not copied. Values of any types, struct fields that are not properties, and values that don't
+// match the schema are copied by reflection. Unexported struct fields are copied shallowly.
+func CloneValue(t Type, v any) any {
+	if v == nil {
+		return nil
+	}
+	return cloneValue(t, reflect.ValueOf(v)).Interface()
+}
+
+// anySchemaForClone is the type values without a schema are cloned with.
+var anySchemaForClone = NewAnySchema()
+
+func cloneValue(t Type, v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if ordered, ok := v.Interface().(orderedMap); ok {
+			return cloneOrderedMap(t, v, ordered)
+		}
+		result := reflect.New(v.Type().Elem())
+		result.Elem().Set(cloneValue(t, v.Elem()))
+		return result
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.New(v.Type()).Elem()
+		result.Set(cloneValue(t, v.Elem()))
+		return result
+	}
+	if objectSchema, ok := objectSchemaOf(t); ok {
+		return cloneObject(objectSchema, v)
+	}
+	switch typed := t.(type) {
+	case untypedListSchema:
+		if v.Kind() == reflect.Slice {
+			return cloneSlice(v, func(int) Type { return typed.itemType() })
+		}
+	case untypedMapSchema:
+		if v.Kind() == reflect.Map {
+			return cloneMap(v, typed.valueType())
+		}
+	case *TupleSchema:
+		if v.Kind() == reflect.Slice && v.Len() == len(typed.ItemsValue) {
+			return cloneSlice(v, func(i int) Type { return typed.ItemsValue[i] })
+		}
+	case variantSelector:
+		if _, selectedType, _, err := typed.selectUnserializedVariant(v.Interface()); err == nil {
+			return cloneValue(selectedType, v)
+		}
+	}
+	return cloneReflected(v)
+}
+
+func cloneObject(o *ObjectSchema, v reflect.Value) reflect.Value {
+	if o.ValuePropertyValue != "" {
+		// Wrappers of non-object one-of variants unserialize to the value of their property.
+		return cloneValue(o.PropertiesValue[o.ValuePropertyValue].TypeValue, v)
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key, value := iter.Key(), iter.Value()
+			if property, ok := o.PropertiesValue[key.String()]; key.Kind() == reflect.String && ok {
+				result.SetMapIndex(key, cloneValue(property.TypeValue, value))
+			} else {
+				result.SetMapIndex(key, cloneReflected(value))
+			}
+		}
+		return result
+	case reflect.Struct:
+		propertyFields := map[int]bool{}
+		for _, field := range o.fieldCache {
+			if len(field.Index) == 1 {
+				propertyFields[field.Index[0]] = true
+			}
+		}
+		result := reflect.New(v.Type()).Elem()
+		result.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() && !propertyFields[i] {
+				result.Field(i).Set(cloneReflected(v.Field(i)))
+			}
+		}
+		// Fields promoted from embedded structs are copied again with their types, after the embedded struct.
+		for propertyID, field := range o.fieldCache {
+			resultField, err := result.FieldByIndexErr(field.Index)
+			if err != nil {
+				continue
+			}
+			resultField.Set(cloneValue(o.PropertiesValue[propertyID].TypeValue, resultField))
+		}
+		return result
+	default:
+		return cloneReflected(v)
+	}
+}
+
+func cloneSlice(v reflect.Value, itemType func(i int) Type) reflect.Value {
+	if v.IsNil() {
+		return v
+	}
+	result := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		result.Index(i).Set(cloneValue(itemType(i), v.Index(i)))
+	}
+	return result
+}
+
+func cloneMap(v reflect.Value, valueType Type) reflect.Value {
+	if v.IsNil() {
+		return v
+	}
+	result := reflect.MakeMapWithSize(v.Type(), v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		result.SetMapIndex(iter.Key(), cloneValue(valueType, iter.Value()))
+	}
+	return result
+}
+
+func cloneOrderedMap(t Type, v reflect.Value, ordered orderedMap) reflect.Value {
+	var valueType Type = anySchemaForClone
+	if mapSchema, ok := t.(untypedMapSchema); ok {
+		valueType = mapSchema.valueType()
+	}
+	result := reflect.New(v.Type().Elem())
+	resultMap := result.Interface().(orderedMap)
+	for _, entry := range ordered.entries() {
+		resultMap.set(entry.key.Interface(), cloneValue(valueType, entry.value).Interface())
+	}
+	return result
+}
+
+// cloneReflected deep-copies the value without a schema.
+func cloneReflected(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() || !v.CanInterface() {
+			return v
+		}
+		return cloneValue(anySchemaForClone, v)
+	case reflect.Slice:
+		return cloneSlice(v, func(int) Type { return anySchemaForClone })
+	case reflect.Map:
+		return cloneMap(v, anySchemaForClone)
+	case reflect.Array:
+		result := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			result.Index(i).Set(cloneReflected(v.Index(i)))
+		}
+		return result
+	case reflect.Struct:
+		result := reflect.New(v.Type()).Elem()
+		result.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				result.Field(i).Set(cloneReflected(v.Field(i)))
+			}
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,109 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type cloneTestInput struct {
+	Name   string            `json:"name"`
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels"`
+	Child  *cloneTestChild   `json:"child"`
+	Source cloneTestSource   `json:"source"`
+	Extra  []int             `json:"-"`
+}
+
+type cloneTestChild struct {
+	Sizes []int64 `json:"sizes"`
+}
+
+type cloneTestSource interface{}
+
+type cloneTestGit struct {
+	Kind string   `json:"kind"`
+	Refs []string `json:"refs"`
+}
+
+func newCloneTestSchema() *schema.ScopeSchema {
+	stringType := schema.NewStringSchema(nil, nil, nil)
+	optional := func(t schema.Type) *schema.PropertySchema {
+		return schema.NewPropertySchema(t, nil, false, nil, nil, nil, nil, nil)
+	}
+	return schema.NewScopeSchema(
+		schema.NewStructMappedObjectSchema[cloneTestInput]("input", map[string]*schema.PropertySchema{
+			"name":   optional(stringType),
+			"tags":   optional(schema.NewListSchema(stringType, nil, nil)),
+			"labels": optional(schema.NewMapSchema(stringType, stringType, nil, nil)),
+			"child":  optional(schema.NewRefSchema("child", nil)),
+			"source": optional(schema.NewOneOfStringSchema[cloneTestSource](
+				map[string]schema.Object{
+					"git": schema.NewRefSchema("git", nil),
+				},
+				"kind",
+				true,
+			)),
+		}),
+		schema.NewStructMappedObjectSchema[*cloneTestChild]("child", map[string]*schema.PropertySchema{
+			"sizes": optional(schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil)),
+		}),
+		schema.NewStructMappedObjectSchema[cloneTestGit]("git", map[string]*schema.PropertySchema{
+			"kind": optional(stringType),
+			"refs": optional(schema.NewListSchema(stringType, nil, nil)),
+		}),
+	)
+}
+
+func TestCloneValueStruct(t *testing.T) {
+	original := cloneTestInput{
+		Name:   "test",
+		Tags:   []string{"a", "b"},
+		Labels: map[string]string{"app": "test"},
+		Child:  &cloneTestChild{Sizes: []int64{1, 2}},
+		Source: cloneTestGit{Kind: "git", Refs: []string{"main"}},
+		Extra:  []int{1},
+	}
+	clone := schema.CloneValue(newCloneTestSchema(), original).(cloneTestInput)
+	assert.Equals(t, clone, original)
+
+	clone.Tags[0] = "changed"
+	clone.Labels["app"] = "changed"
+	clone.Child.Sizes[0] = 42
+	clone.Source.(cloneTestGit).Refs[0] = "changed"
+	clone.Extra[0] = 42
+	assert.Equals(t, original.Tags[0], "a")
+	assert.Equals(t, original.Labels["app"], "test")
+	assert.Equals(t, original.Child.Sizes[0], int64(1))
+	assert.Equals(t, original.Source.(cloneTestGit).Refs[0], "main")
+	assert.Equals(t, original.Extra[0], 1)
+}
+
+func TestCloneValueUntyped(t *testing.T) {
+	original := map[string]any{
+		"name": "test",
+		"tags": []any{"a", map[string]any{"b": "c"}},
+	}
+	clone := schema.CloneValue(schema.NewAnySchema(), original).(map[string]any)
+	assert.Equals(t, clone, original)
+	clone["tags"].([]any)[1].(map[string]any)["b"] = "changed"
+	assert.Equals(t, original["tags"].([]any)[1].(map[string]any)["b"], "c")
+
+	assert.Nil(t, schema.CloneValue(schema.NewAnySchema(), nil))
+}
+
+func TestCloneValueOrderedMap(t *testing.T) {
+	stringType := schema.NewStringSchema(nil, nil, nil)
+	mapType := schema.NewOrderedMapSchema(stringType, schema.NewListSchema(stringType, nil, nil), nil, nil)
+	original := schema.NewOrderedMap[any, any]()
+	original.Set("b", []any{"1"})
+	original.Set("a", []any{"2"})
+
+	clone := schema.CloneValue(mapType, original).(*schema.OrderedMap[any, any])
+	assert.Equals(t, clone.Keys(), []any{"b", "a"})
+	value, _ := clone.Get("b")
+	value.([]any)[0] = "changed"
+	value, _ = original.Get("b")
+	assert.Equals(t, value.([]any)[0], any("1"))
+}
@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// CoercionPolicy controls which implicit conversions between data types are allowed when unserializing. By default,
+// Unserialize is lenient: it accepts "5" for an integer or "yes" for a boolean, since data from config files and
+// environment variables is often typed loosely. Data sources with reliable types can disallow the conversions to catch
+// mistakes, such as a number sent for a string field.
+//
+// The policy applies to the values the user provided. Default values and map keys are always converted, since JSON
+// keys are always strings and defaults are set by the plugin.
+type CoercionPolicy struct {
+	// StringToNumber converts numeric strings, and strings with units for types that have units, to integers and
+	// floats.
+	StringToNumber bool
+	// NumberToString converts integers and floats to strings.
+	NumberToString bool
+	// StringToBool converts strings such as "yes", "on" or "1" to booleans.
+	StringToBool bool
+	// NumberToBool converts the numbers 0 and 1 to booleans.
+	NumberToBool bool
+	// BoolToNumber converts booleans to the numbers 0 and 1.
+	BoolToNumber bool
+	// FloatToInt converts floats holding whole numbers, such as 3.0, to integers. Numbers decoded by encoding/json
+	// without UseNumber are floats, so data decoded that way needs it.
+	FloatToInt bool
+}
+
+// DefaultCoercionPolicy returns the policy Unserialize follows, which allows all conversions.
+func DefaultCoercionPolicy() CoercionPolicy {
+	return CoercionPolicy{
+		StringToNumber: true,
+		NumberToString: true,
+		StringToBool:   true,
+		NumberToBool:   true,
+		BoolToNumber:   true,
+		FloatToInt:     true,
+	}
+}
+
+// StrictCoercionPolicy returns a policy that allows no conversions: each value has to be of the type of its field.
+func StrictCoercionPolicy() CoercionPolicy {
+	return CoercionPolicy{}
+}
+
+// UnserializeWithCoercion unserializes the data like UnserializeAll, but only performs the conversions between data
+// types the policy allows. Values needing other conversions are reported as ConstraintErrors.
+func UnserializeWithCoercion(t Type, data any, policy CoercionPolicy) (any, error) {
+	result, errs := errorCollector{coercion: &policy}.collect(t, data)
+	if len(errs) > 0 {
+		return nil, &ConstraintErrors{Errors: errs}
+	}
+	return result, nil
+}
+
+// check returns an error if unserializing the data with the type needs a conversion the policy doesn't allow.
+func (p CoercionPolicy) check(t Type, data any) *ConstraintError {
+	from := coercionKindOf(data)
+	var to string
+	var allowed bool
+	switch t.TypeID() {
+	case TypeIDInt, TypeIDIntEnum:
+		to = "integer"
+		allowed = from == "integer" || from == "" ||
+			(from == "string" && p.StringToNumber) ||
+			(from == "bool" && p.BoolToNumber) ||
+			(from == "float" && p.FloatToInt)
+	case TypeIDFloat:
+		to = "float"
+		allowed = from == "integer" || from == "float" || from == "" ||
+			(from == "string" && p.StringToNumber) ||
+			(from == "bool" && p.BoolToNumber)
+	case TypeIDString, TypeIDStringEnum:
+		to = "string"
+		allowed = (from != "integer" && from != "float") || p.NumberToString
+	case TypeIDBool:
+		to = "bool"
+		allowed = from == "bool" || from == "" ||
+			(from == "string" && p.StringToBool) ||
+			((from == "integer" || from == "float") && p.NumberToBool)
+	default:
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	return &ConstraintError{
+		Message:    fmt.Sprintf("Converting %s data to %s is not allowed by the coercion policy", from, to),
+		Constraint: ConstraintDataType,
+		Expected:   to,
+		Actual:     from,
+	}
+}
+
+// coercionKindOf returns the kind of data the coercion policy distinguishes, or an empty string for other data, which
+// the types reject by themselves.
+func coercionKindOf(data any) string {
+	if number, ok := data.(json.Number); ok {
+		if _, err := number.Int64(); err == nil {
+			return "integer"
+		}
+		return "float"
+	}
+	switch reflect.ValueOf(data).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	default:
+		return ""
+	}
+}
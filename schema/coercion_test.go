@@ -0,0 +1,83 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestUnserializeWithCoercion(t *testing.T) {
+	intType := schema.NewIntSchema(nil, nil, nil)
+	stringType := schema.NewStringSchema(nil, nil, nil)
+	boolType := schema.NewBoolSchema()
+
+	for name, tc := range map[string]struct {
+		t    schema.Type
+		data any
+	}{
+		"string-to-int":  {intType, "5"},
+		"float-to-int":   {intType, 3.0},
+		"int-to-string":  {stringType, 5},
+		"string-to-bool": {boolType, "yes"},
+		"int-to-bool":    {boolType, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.NoErrorR[any](t)(schema.UnserializeWithCoercion(tc.t, tc.data, schema.DefaultCoercionPolicy()))
+			_, err := schema.UnserializeWithCoercion(tc.t, tc.data, schema.StrictCoercionPolicy())
+			assert.Error(t, err)
+		})
+	}
+
+	assert.Equals(
+		t,
+		assert.NoErrorR[any](t)(schema.UnserializeWithCoercion(intType, 5, schema.StrictCoercionPolicy())),
+		any(int64(5)),
+	)
+	policy := schema.StrictCoercionPolicy()
+	policy.StringToNumber = true
+	assert.Equals(
+		t,
+		assert.NoErrorR[any](t)(schema.UnserializeWithCoercion(intType, "5", policy)),
+		any(int64(5)),
+	)
+	_, err := schema.UnserializeWithCoercion(boolType, "yes", policy)
+	assert.Error(t, err)
+}
+
+func TestUnserializeWithCoercionObject(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"timeout": schema.NewPropertySchema(
+			schema.NewIntSchema(nil, nil, schema.UnitDurationNanoseconds),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			schema.PointerTo(`"1s"`),
+			nil,
+		),
+	}))
+
+	// The default is given as a string with units, but the policy only applies to the data of the user.
+	result := assert.NoErrorR[any](t)(schema.UnserializeWithCoercion(
+		scope,
+		map[string]any{"name": "test"},
+		schema.StrictCoercionPolicy(),
+	))
+	assert.Equals(t, result.(map[string]any)["timeout"], any(int64(1000000000)))
+
+	_, err := schema.UnserializeWithCoercion(scope, map[string]any{"name": 5}, schema.StrictCoercionPolicy())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "name")
+
+	// The policy of the scope applies to Unserialize.
+	_, err = scope.Unserialize(map[string]any{"name": 5})
+	assert.NoError(t, err)
+	scope.WithCoercionPolicy(schema.StrictCoercionPolicy())
+	_, err = scope.Unserialize(map[string]any{"name": 5})
+	assert.Error(t, err)
+	_, err = scope.Unserialize(map[string]any{"name": "test", "timeout": "2m"})
+	assert.Error(t, err)
+}
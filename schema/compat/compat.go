@@ -0,0 +1,300 @@
+// Package compat classifies the differences between two versions of a plugin schema as breaking or non-breaking, so
+// release pipelines can fail when a new plugin version would break existing workflows.
+//
+// Data flowing into the plugin, such as step inputs and received signals, may be loosened but not tightened: a new
+// optional property is fine, a new required property is not. Data flowing out of the plugin, such as step outputs and
+// emitted signals, may be tightened but not loosened: removing an output property breaks workflows using it, while
+// making an optional output property required doesn't. Changes the checker doesn't know are considered breaking.
+//
+// BuildMatrix compares a set of plugin schemas with each other instead, and tells which step outputs can be passed as
+// which step inputs.
+package compat
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Direction describes whether data flows into or out of the plugin.
+type Direction string
+
+const (
+	// DirectionInput is data the plugin receives, such as step inputs.
+	DirectionInput Direction = "input"
+	// DirectionOutput is data the plugin produces, such as step outputs.
+	DirectionOutput Direction = "output"
+)
+
+// Finding is a change between two schema versions, classified as breaking or not.
+type Finding struct {
+	Change   schema.Change
+	Breaking bool
+	// Reason explains the classification.
+	Reason string
+}
+
+func (f Finding) String() string {
+	if f.Breaking {
+		return fmt.Sprintf("breaking: %s (%s)", f.Change.String(), f.Reason)
+	}
+	return fmt.Sprintf("compatible: %s (%s)", f.Change.String(), f.Reason)
+}
+
+// Report holds the classified changes between two schema versions, ordered by path.
+type Report struct {
+	Findings []Finding
+}
+
+// Breaking returns the breaking findings of the report.
+func (r Report) Breaking() []Finding {
+	var result []Finding
+	for _, finding := range r.Findings {
+		if finding.Breaking {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
+// Err returns an error listing the breaking changes, or nil if there are none.
+func (r Report) Err() error {
+	breaking := r.Breaking()
+	if len(breaking) == 0 {
+		return nil
+	}
+	lines := make([]string, len(breaking))
+	for i, finding := range breaking {
+		lines[i] = finding.String()
+	}
+	return fmt.Errorf("%d breaking schema changes:\n%s", len(breaking), strings.Join(lines, "\n"))
+}
+
+func (r Report) String() string {
+	lines := make([]string, len(r.Findings))
+	for i, finding := range r.Findings {
+		lines[i] = finding.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check compares two versions of a plugin schema, such as the SchemaSchema of the last release and the current
+// CallableSchema. Removing steps, outputs or signals is breaking, adding them is not.
+func Check(previous, current interface{ SelfSerialize() (any, error) }) (Report, error) {
+	changes, err := schema.DiffSchemas(previous, current)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{}
+	for _, change := range changes {
+		breaking, reason := classifySchemaChange(change)
+		report.Findings = append(report.Findings, Finding{change, breaking, reason})
+	}
+	return report, nil
+}
+
+// CheckType compares two versions of a single type, such as the input scope of a step, in which data flows in the
+// given direction.
+func CheckType(previous, current schema.Type, direction Direction) Report {
+	report := Report{}
+	for _, change := range schema.Diff(previous, current) {
+		breaking, reason := classifyChange(change, direction)
+		report.Findings = append(report.Findings, Finding{change, breaking, reason})
+	}
+	return report
+}
+
+// classifySchemaChange classifies a change of a plugin schema, whose paths start with steps and the step ID.
+func classifySchemaChange(change schema.Change) (bool, string) {
+	path := change.Path
+	if len(path) < 2 || path[0] != "steps" {
+		return true, "unknown plugin schema change"
+	}
+	// Workflows refer to steps, outputs and signals, so adding them is compatible, while removing them is not.
+	if len(path) == 2 {
+		return classifyEntry(change, DirectionInput, "step")
+	}
+	switch path[2] {
+	case "input":
+		return classifyChange(change, DirectionInput)
+	case "signal_handlers":
+		if len(path) == 4 {
+			return classifyEntry(change, DirectionInput, "signal handler")
+		}
+		return classifyChange(change, DirectionInput)
+	case "outputs":
+		if len(path) == 4 {
+			return classifyEntry(change, DirectionInput, "output")
+		}
+		if path[4] == "truncation" {
+			return classifyTruncation(change)
+		}
+		return classifyChange(change, DirectionOutput)
+	case "signal_emitters":
+		if len(path) == 4 {
+			return classifyEntry(change, DirectionInput, "emitted signal")
+		}
+		return classifyChange(change, DirectionOutput)
+	case "display", "deprecated":
+		return false, "documentation change"
+	case "scheduling":
+		// Engines may ignore scheduling hints, so they don't change which workflows are valid.
+		return false, "scheduling hint change"
+	case "engine_requirements":
+		return classifyEngineRequirements(change)
+	default:
+		return true, "unknown step change"
+	}
+}
+
+// classifyChange classifies a change of a type in which data flows in the given direction.
+func classifyChange(change schema.Change, direction Direction) (bool, string) {
+	path := change.Path
+	last := ""
+	if len(path) > 0 {
+		last = path[len(path)-1]
+	}
+	if isEngineRequirementsPath(path) {
+		return classifyEngineRequirements(change)
+	}
+	switch change.Kind {
+	case schema.ChangeDisplay:
+		return false, "display change"
+	case schema.ChangeTypeChanged:
+		return true, "type changed"
+	case schema.ChangeAdded, schema.ChangeRemoved:
+		if len(path) < 2 {
+			return true, "unknown change"
+		}
+		switch path[len(path)-2] {
+		case "properties":
+			return classifyProperty(change, direction)
+		case "values":
+			return classifyEntry(change, direction, "enum value")
+		case "value_aliases":
+			return classifyEntry(change, direction, "enum value alias")
+		case "types":
+			return classifyEntry(change, direction, "one-of type")
+		case "objects", "deprecated_values":
+			// Objects are checked where they are used.
+			return false, "definition change"
+		}
+		return true, "unknown change"
+	}
+	switch last {
+	case "required", "unique_items":
+		return classifyTightening(direction, change.New == true, fmt.Sprintf("%s changed", last))
+	case "min":
+		return classifyTightening(direction, isTighterBound(change.Old, change.New, 1), "lower bound changed")
+	case "max":
+		return classifyTightening(direction, isTighterBound(change.Old, change.New, -1), "upper bound changed")
+	case "pattern":
+		if change.Old != nil && change.New != nil {
+			return true, "pattern changed"
+		}
+		return classifyTightening(direction, change.New != nil, "pattern changed")
+	case "default", "examples", "display", "sensitive", "deprecated":
+		return false, fmt.Sprintf("%s changed", last)
+	default:
+		return true, fmt.Sprintf("%s changed", last)
+	}
+}
+
+// classifyTruncation classifies a change of the truncation policy of an output. Workflows may rely on values that a
+// new or lower limit would cut off.
+func classifyTruncation(change schema.Change) (bool, string) {
+	switch {
+	case change.New == nil:
+		return false, "output truncation limit removed"
+	case change.Old == nil:
+		return true, "output truncation limit added"
+	case isTighterBound(change.Old, change.New, -1):
+		return true, "output truncation limit lowered"
+	default:
+		return false, "output truncation limit raised"
+	}
+}
+
+// classifyEngineRequirements classifies a change of the engine requirements of a step or property. Engines that could
+// use the item before may no longer be offered it, unless the requirements were removed.
+func classifyEngineRequirements(change schema.Change) (bool, string) {
+	if change.Kind == schema.ChangeConstraint && change.Path[len(change.Path)-1] == "engine_requirements" &&
+		change.New == nil {
+		return false, "engine requirements removed"
+	}
+	return true, "engine requirements changed, which may exclude engines"
+}
+
+// isEngineRequirementsPath returns true if the path points into the engine requirements of a property.
+func isEngineRequirementsPath(path []string) bool {
+	for i := 2; i < len(path); i++ {
+		if path[i] == "engine_requirements" && path[i-2] == "properties" {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyProperty classifies an added or removed object property.
+func classifyProperty(change schema.Change, direction Direction) (bool, string) {
+	switch {
+	case change.Kind == schema.ChangeRemoved && direction == DirectionInput:
+		return true, "removed input property, existing workflows may still set it"
+	case change.Kind == schema.ChangeRemoved:
+		return true, "removed output property, existing workflows may use it"
+	case direction == DirectionInput && isRequired(change.New):
+		return true, "new required input property"
+	default:
+		return false, "new optional or output property"
+	}
+}
+
+// classifyEntry classifies an added or removed entry, such as an enum value, that adds accepted data if it is added.
+func classifyEntry(change schema.Change, direction Direction, name string) (bool, string) {
+	added := change.Kind == schema.ChangeAdded
+	return classifyTightening(direction, !added, fmt.Sprintf("%s %s", name, change.Kind))
+}
+
+// classifyTightening classifies a change that tightens or loosens what data is valid.
+func classifyTightening(direction Direction, tighter bool, reason string) (bool, string) {
+	if tighter == (direction == DirectionInput) {
+		return true, reason + ", which restricts " + string(direction)
+	}
+	return false, reason
+}
+
+func isRequired(property any) bool {
+	serialized, ok := property.(map[string]any)
+	return ok && serialized["required"] == true
+}
+
+// isTighterBound returns true if the new bound is stricter than the old one. The sign is 1 for lower bounds and -1
+// for upper bounds. A missing bound is the loosest.
+func isTighterBound(previous, current any, sign float64) bool {
+	oldValue, oldOk := toFloat(previous)
+	newValue, newOk := toFloat(current)
+	switch {
+	case !newOk:
+		return false
+	case !oldOk:
+		return true
+	default:
+		return sign*newValue > sign*oldValue
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,165 @@
+package compat_test
+
+import (
+	"context"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/schema/compat"
+)
+
+type compatTestData struct {
+	Name  string  `json:"name"`
+	Email *string `json:"email,omitempty"`
+}
+
+func compatTestObject(withEmail bool, emailRequired bool, nameMin int) *schema.ScopeSchema {
+	properties := map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(schema.IntPointer(nameMin), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}
+	if withEmail {
+		properties["email"] = schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			emailRequired,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+	return schema.NewScopeSchema(schema.NewStructMappedObjectSchema[compatTestData]("data", properties))
+}
+
+func compatTestSchema(input *schema.ScopeSchema, output *schema.ScopeSchema) *schema.CallableSchema {
+	return schema.NewCallableSchema(
+		schema.NewCallableStep(
+			"greet",
+			input,
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(output, nil, false),
+			},
+			nil,
+			func(_ context.Context, input compatTestData) (string, any) {
+				return "success", input
+			},
+		),
+	)
+}
+
+func TestCheckCompatible(t *testing.T) {
+	previous := compatTestSchema(compatTestObject(false, false, 1), compatTestObject(false, false, 1))
+	// A new optional input property, a looser input constraint, and a new output property are fine.
+	current := compatTestSchema(compatTestObject(true, false, 0), compatTestObject(true, false, 1))
+	report := assert.NoErrorR[compat.Report](t)(compat.Check(previous, current))
+	assert.Equals(t, len(report.Findings), 3)
+	assert.Equals(t, len(report.Breaking()), 0)
+	assert.NoError(t, report.Err())
+}
+
+func TestCheckBreaking(t *testing.T) {
+	previous := compatTestSchema(compatTestObject(false, false, 1), compatTestObject(true, false, 1))
+	// A new required input property, a stricter input constraint, and a removed output property break workflows.
+	current := compatTestSchema(compatTestObject(true, true, 2), compatTestObject(false, false, 1))
+	report := assert.NoErrorR[compat.Report](t)(compat.Check(previous, current))
+	assert.Equals(t, len(report.Breaking()), 3)
+	err := report.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "steps.greet.input.objects.data.properties.email added")
+	assert.Contains(t, err.Error(), "steps.greet.input.objects.data.properties.name.type.min changed from 1 to 2")
+	assert.Contains(t, err.Error(), "steps.greet.outputs.success.schema.objects.data.properties.email removed")
+
+	// Removing a step is breaking.
+	report = assert.NoErrorR[compat.Report](t)(compat.Check(previous, schema.NewCallableSchema()))
+	assert.Equals(t, len(report.Breaking()), 1)
+}
+
+func TestCheckType(t *testing.T) {
+	optional := compatTestObject(true, false, 1)
+	required := compatTestObject(true, true, 1)
+	assert.Equals(t, len(compat.CheckType(optional, required, compat.DirectionInput).Breaking()), 1)
+	assert.Equals(t, len(compat.CheckType(optional, required, compat.DirectionOutput).Breaking()), 0)
+	assert.Equals(t, len(compat.CheckType(required, optional, compat.DirectionOutput).Breaking()), 1)
+
+	display := schema.NewDisplayValue(schema.PointerTo("Value"), nil, nil)
+	values := schema.NewStringEnumSchema(map[string]*schema.DisplayValue{"a": display})
+	moreValues := schema.NewStringEnumSchema(map[string]*schema.DisplayValue{"a": display, "b": display})
+	assert.Equals(t, len(compat.CheckType(values, moreValues, compat.DirectionInput).Breaking()), 0)
+	assert.Equals(t, len(compat.CheckType(values, moreValues, compat.DirectionOutput).Breaking()), 1)
+
+	report := compat.CheckType(schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil),
+		compat.DirectionInput)
+	assert.Equals(t, len(report.Breaking()), 1)
+}
+
+func TestCheckTruncation(t *testing.T) {
+	truncated := func(maxListItems *int64) *schema.CallableSchema {
+		s := compatTestSchema(compatTestObject(false, false, 1), compatTestObject(false, false, 1))
+		if maxListItems != nil {
+			s.StepsValue["greet"].Outputs()["success"].Truncate(schema.TruncationPolicy{MaxListItems: maxListItems})
+		}
+		return s
+	}
+	for name, testCase := range map[string]struct {
+		previous *int64
+		current  *int64
+		breaking int
+	}{
+		"added":   {nil, schema.PointerTo(int64(10)), 1},
+		"removed": {schema.PointerTo(int64(10)), nil, 0},
+		"lowered": {schema.PointerTo(int64(10)), schema.PointerTo(int64(5)), 1},
+		"raised":  {schema.PointerTo(int64(5)), schema.PointerTo(int64(10)), 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			report := assert.NoErrorR[compat.Report](t)(compat.Check(
+				truncated(testCase.previous),
+				truncated(testCase.current),
+			))
+			assert.Equals(t, len(report.Findings), 1)
+			assert.Equals(t, len(report.Breaking()), testCase.breaking)
+		})
+	}
+}
+
+func TestCheckEngineRequirements(t *testing.T) {
+	required := func(stepVersion *string, propertyVersion *string) *schema.CallableSchema {
+		input := compatTestObject(false, false, 1)
+		s := compatTestSchema(input, compatTestObject(false, false, 1))
+		if stepVersion != nil {
+			step := s.StepsValue["greet"].(*schema.CallableStepSchema[any, compatTestData])
+			step.RequireEngine(schema.EngineRequirements{MinVersion: stepVersion})
+		}
+		if propertyVersion != nil {
+			input.Properties()["name"].RequireEngine(schema.EngineRequirements{MinVersion: propertyVersion})
+		}
+		return s
+	}
+	for name, testCase := range map[string]struct {
+		previous *schema.CallableSchema
+		current  *schema.CallableSchema
+		breaking int
+	}{
+		"step added":       {required(nil, nil), required(schema.PointerTo("1.0.0"), nil), 1},
+		"step removed":     {required(schema.PointerTo("1.0.0"), nil), required(nil, nil), 0},
+		"step changed":     {required(schema.PointerTo("1.0.0"), nil), required(schema.PointerTo("2.0.0"), nil), 1},
+		"property added":   {required(nil, nil), required(nil, schema.PointerTo("1.0.0")), 1},
+		"property removed": {required(nil, schema.PointerTo("1.0.0")), required(nil, nil), 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			report := assert.NoErrorR[compat.Report](t)(compat.Check(testCase.previous, testCase.current))
+			assert.Equals(t, len(report.Findings), 1)
+			assert.Equals(t, len(report.Breaking()), testCase.breaking)
+		})
+	}
+}
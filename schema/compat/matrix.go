@@ -0,0 +1,129 @@
+package compat
+
+import (
+	"fmt"
+	"sort"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// StepRef identifies a step of one of the plugins of a matrix.
+type StepRef struct {
+	Plugin string `json:"plugin"`
+	Step   string `json:"step"`
+}
+
+func (s StepRef) String() string {
+	return s.Plugin + "/" + s.Step
+}
+
+// OutputRef identifies an output of a step of one of the plugins of a matrix.
+type OutputRef struct {
+	StepRef
+	Output string `json:"output"`
+	// Error is set if the output is an error output.
+	Error bool `json:"error"`
+}
+
+func (o OutputRef) String() string {
+	return o.StepRef.String() + "/" + o.Output
+}
+
+// MatrixEntry tells whether the data of a step output can be passed as the input of a step.
+type MatrixEntry struct {
+	Output     OutputRef `json:"output"`
+	Input      StepRef   `json:"input"`
+	Compatible bool      `json:"compatible"`
+	// Reason explains why the output is incompatible. It is empty for compatible entries.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Matrix holds the compatibility of every step output with every step input of a set of plugins, ordered by output and
+// then by input. It is meant to be encoded as JSON, for example to suggest which steps a workflow builder may connect.
+type Matrix struct {
+	Entries []MatrixEntry `json:"entries"`
+}
+
+// Compatible returns the entries whose output can be passed as the input.
+func (m Matrix) Compatible() []MatrixEntry {
+	var result []MatrixEntry
+	for _, entry := range m.Entries {
+		if entry.Compatible {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// InputsFor returns the step inputs the given output can be passed to.
+func (m Matrix) InputsFor(output OutputRef) []StepRef {
+	var result []StepRef
+	for _, entry := range m.Entries {
+		if entry.Compatible && entry.Output.StepRef == output.StepRef && entry.Output.Output == output.Output {
+			result = append(result, entry.Input)
+		}
+	}
+	return result
+}
+
+// BuildMatrix computes which step outputs are compatible with which step inputs across the given plugin schemas, keyed
+// by plugin name. An output is compatible with an input if its properties are accepted by the properties of the input,
+// and every required input property is provided. The root objects don't need to share an ID, since workflows pass the
+// properties and not the object, but nested objects are checked like in ObjectSchema.ValidateCompatibility.
+//
+// Steps are compared to themselves as well, since a workflow may pass the output of one invocation of a step to
+// another.
+func BuildMatrix(plugins map[string]interface{ SelfSerialize() (any, error) }) (Matrix, error) {
+	type output struct {
+		ref   OutputRef
+		scope schema.Scope
+	}
+	type input struct {
+		ref   StepRef
+		scope schema.Scope
+	}
+	var outputs []output
+	var inputs []input
+	for plugin, pluginSchema := range plugins {
+		// The schemas are serialized and read back, so callable schemas and schemas received from plugins are
+		// treated the same.
+		serialized, err := pluginSchema.SelfSerialize()
+		if err != nil {
+			return Matrix{}, fmt.Errorf("failed to serialize the schema of plugin %s (%w)", plugin, err)
+		}
+		unserialized, err := schema.UnserializeSchema(serialized)
+		if err != nil {
+			return Matrix{}, fmt.Errorf("failed to read the schema of plugin %s (%w)", plugin, err)
+		}
+		for stepID, step := range unserialized.Steps() {
+			stepRef := StepRef{plugin, stepID}
+			inputs = append(inputs, input{stepRef, step.Input()})
+			for outputID, stepOutput := range step.Outputs() {
+				outputs = append(outputs, output{OutputRef{stepRef, outputID, stepOutput.Error()}, stepOutput.Schema()})
+			}
+		}
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].ref.String() < outputs[j].ref.String()
+	})
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].ref.String() < inputs[j].ref.String()
+	})
+
+	matrix := Matrix{Entries: make([]MatrixEntry, 0, len(outputs)*len(inputs))}
+	for _, o := range outputs {
+		properties := make(map[string]any, len(o.scope.RootObject().Properties()))
+		for propertyID, property := range o.scope.RootObject().Properties() {
+			properties[propertyID] = property
+		}
+		for _, i := range inputs {
+			entry := MatrixEntry{Output: o.ref, Input: i.ref, Compatible: true}
+			if err := i.scope.RootObject().ValidateCompatibility(properties); err != nil {
+				entry.Compatible = false
+				entry.Reason = err.Error()
+			}
+			matrix.Entries = append(matrix.Entries, entry)
+		}
+	}
+	return matrix, nil
+}
@@ -0,0 +1,59 @@
+package compat_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/schema/compat"
+)
+
+func matrixTestCountScope(id string) *schema.ScopeSchema {
+	return schema.NewScopeSchema(schema.NewObjectSchema(id, map[string]*schema.PropertySchema{
+		"count": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	}))
+}
+
+func TestBuildMatrix(t *testing.T) {
+	counter := schema.NewSchema(map[string]*schema.StepSchema{
+		"count": schema.NewStepSchema(
+			"count",
+			matrixTestCountScope("count_input"),
+			map[string]*schema.StepOutputSchema{
+				"done":  schema.NewStepOutputSchema(matrixTestCountScope("count_output"), nil, false),
+				"error": schema.NewStepOutputSchema(compatTestObject(false, false, 0), nil, true),
+			},
+			nil,
+			nil,
+			nil,
+		),
+	})
+	matrix := assert.NoErrorR[compat.Matrix](t)(compat.BuildMatrix(
+		map[string]interface{ SelfSerialize() (any, error) }{
+			"greeter": compatTestSchema(compatTestObject(true, false, 1), compatTestObject(true, true, 1)),
+			"counter": counter,
+		},
+	))
+	assert.Equals(t, len(matrix.Entries), 6)
+	assert.Equals(t, len(matrix.Compatible()), 3)
+
+	countDone := compat.OutputRef{StepRef: compat.StepRef{Plugin: "counter", Step: "count"}, Output: "done"}
+	countError := compat.OutputRef{StepRef: countDone.StepRef, Output: "error", Error: true}
+	greetSuccess := compat.OutputRef{StepRef: compat.StepRef{Plugin: "greeter", Step: "greet"}, Output: "success"}
+	// The root object IDs differ, but the properties match.
+	assert.Equals(t, matrix.InputsFor(countDone), []compat.StepRef{countDone.StepRef})
+	// The error output provides the name the greeter requires.
+	assert.Equals(t, matrix.InputsFor(countError), []compat.StepRef{greetSuccess.StepRef})
+	assert.Equals(t, matrix.InputsFor(greetSuccess), []compat.StepRef{greetSuccess.StepRef})
+
+	// The entries are ordered by output, then by input.
+	assert.Equals(t, matrix.Entries[0].Output, countDone)
+	assert.Equals(t, matrix.Entries[1].Input, greetSuccess.StepRef)
+	assert.Equals(t, matrix.Entries[1].Compatible, false)
+	assert.Contains(t, matrix.Entries[1].Reason, "name")
+
+	encoded := assert.NoErrorR[[]byte](t)(json.Marshal(matrix))
+	assert.Contains(t, string(encoded), `{"output":{"plugin":"counter","step":"count","output":"done","error":false},`+
+		`"input":{"plugin":"counter","step":"count"},"compatible":true}`)
+}
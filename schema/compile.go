@@ -0,0 +1,265 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// compilable is implemented by the types that can precompute the reflection work and lookups of their conversions.
+type compilable interface {
+	compile()
+}
+
+// Compile is a builder-pattern way of precompiling the conversions of the object and all objects and enums it
+// contains. Struct-mapped objects resolve their fields, setters and pointer handling once instead of on every
+// Unserialize, Serialize and Validate call, and enums build lookup tables for their values and aliases. The results are
+// identical to the uncompiled conversions.
+//
+// Compile must be called while the schema is built, before it is used from multiple goroutines. The properties and
+// enum values of the compiled types must not be changed afterwards.
+func (o *ObjectSchema) Compile() *ObjectSchema {
+	compileTypes(o)
+	return o
+}
+
+// Compile is a builder-pattern way of precompiling the conversions of all objects and enums of the scope, see
+// ObjectSchema.Compile.
+func (s *ScopeSchema) Compile() *ScopeSchema {
+	compileTypes(s)
+	return s
+}
+
+// Compile is a builder-pattern way of precompiling the conversions of the inputs, outputs and signals of all steps,
+// see ObjectSchema.Compile.
+func (s *CallableSchema) Compile() *CallableSchema {
+	for _, step := range s.StepsValue {
+		compileTypes(step.Input())
+		for _, output := range step.Outputs() {
+			compileTypes(output.Schema())
+		}
+		for _, signal := range step.SignalHandlers() {
+			compileTypes(signal.DataSchema())
+		}
+		for _, signal := range step.SignalEmitters() {
+			compileTypes(signal.DataSchema())
+		}
+	}
+	return s
+}
+
+func compileTypes(root Type) {
+	if root == nil {
+		return
+	}
+	_ = Walk(root, func(_ []string, t Type) error {
+		if c, ok := t.(compilable); ok {
+			c.compile()
+		}
+		return nil
+	})
+}
+
+// compiledStruct holds the field information of a struct-mapped object, resolved once by Compile.
+type compiledStruct struct {
+	// structType is the struct the object is mapped to, without the pointer.
+	structType reflect.Type
+	// pointer is set if the object unserializes to a pointer to the struct.
+	pointer bool
+	// fields holds the fields in the order of their property IDs.
+	fields []compiledField
+}
+
+type compiledField struct {
+	propertyID string
+	property   *PropertySchema
+	index      []int
+	// embedded is set if the field is promoted from an embedded struct, whose pointers may need allocating.
+	embedded  bool
+	fieldType reflect.Type
+	// elemType is set for pointer fields. Values that are not pointers are stored in a new pointer of this type.
+	elemType reflect.Type
+	// deref is set if the field is a pointer, but the property converts the value it points to.
+	deref bool
+	// emptyValue is the zero value of the property type, compared against when empty means default.
+	emptyValue any
+}
+
+func (o *ObjectSchema) compile() {
+	if o.fieldCache == nil || o.compiled.fields != nil {
+		return
+	}
+	o.checkNotLocked("compile")
+	structType := reflect.TypeOf(o.defaultValue)
+	c := &compiledStruct{
+		structType: structType,
+		pointer:    structType.Kind() == reflect.Pointer,
+		fields:     make([]compiledField, 0, len(o.fieldCache)),
+	}
+	if c.pointer {
+		c.structType = structType.Elem()
+	}
+	propertyIDs := make([]string, 0, len(o.fieldCache))
+	for propertyID := range o.fieldCache {
+		propertyIDs = append(propertyIDs, propertyID)
+	}
+	sort.Strings(propertyIDs)
+	for _, propertyID := range propertyIDs {
+		structField := o.fieldCache[propertyID]
+		property := o.PropertiesValue[propertyID]
+		field := compiledField{
+			propertyID: propertyID,
+			property:   property,
+			index:      structField.Index,
+			embedded:   len(structField.Index) > 1,
+			fieldType:  structField.Type,
+		}
+		propertyType := property.ReflectedType()
+		if structField.Type.Kind() == reflect.Pointer {
+			field.elemType = structField.Type.Elem()
+			field.deref = propertyType.Kind() != reflect.Pointer
+		}
+		if property.emptyIsDefault {
+			field.emptyValue = reflect.Zero(propertyType).Interface()
+		}
+		c.fields = append(c.fields, field)
+	}
+	o.compiled.fields = c
+}
+
+// unserialize creates the struct from the unserialized property values. It behaves like
+// ObjectSchema.unserializeToStruct, but converts values only if their type differs from the field type.
+func (c *compiledStruct) unserialize(rawData map[string]any) (result any, err error) {
+	value := reflect.New(c.structType)
+	elem := value.Elem()
+	var propertyID string
+	defer func() {
+		if e := recover(); e != nil {
+			cause, ok := e.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", e)
+			}
+			result = nil
+			err = &ConstraintError{
+				Message: "Field cannot be set",
+				Path:    []string{propertyID},
+				Cause:   cause,
+			}
+		}
+	}()
+	for i := range c.fields {
+		field := &c.fields[i]
+		data, isSet := rawData[field.propertyID]
+		if !isSet {
+			continue
+		}
+		propertyID = field.propertyID
+		target := field.target(elem)
+		v := reflect.ValueOf(data)
+		if field.elemType != nil && v.Kind() != reflect.Pointer {
+			pointer := reflect.New(field.elemType)
+			pointer.Elem().Set(convertValueTo(v, field.elemType))
+			target.Set(pointer)
+		} else {
+			target.Set(convertValueTo(v, field.fieldType))
+		}
+	}
+	if c.pointer {
+		return value.Interface(), nil
+	}
+	return elem.Interface(), nil
+}
+
+// rawData reads the set property values from the struct, like ObjectSchema.structRawData. If serialize is set, the
+// values are serialized, and empty values are skipped like in ObjectSchema.extractPropertyValue.
+func (c *compiledStruct) rawData(v reflect.Value, serialize bool) (map[string]any, error) {
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	rawData := make(map[string]any, len(c.fields))
+	for i := range c.fields {
+		field := &c.fields[i]
+		var val reflect.Value
+		if field.embedded {
+			var err error
+			if val, err = v.FieldByIndexErr(field.index); err != nil {
+				// A nil embedded pointer means none of its promoted fields are set.
+				continue
+			}
+		} else {
+			val = v.Field(field.index[0])
+		}
+		if val.Kind() == reflect.Pointer {
+			if val.IsNil() {
+				continue
+			}
+			if field.deref {
+				val = val.Elem()
+			}
+		}
+		value := val.Interface()
+		if value == nil {
+			continue
+		}
+		if !serialize {
+			if field.property.emptyIsDefault &&
+				reflect.DeepEqual(reflect.ValueOf(field.emptyValue).Convert(val.Type()).Interface(), value) {
+				continue
+			}
+			rawData[field.propertyID] = value
+			continue
+		}
+		if field.property.emptyIsDefault && val.IsZero() {
+			continue
+		}
+		serialized, err := field.property.Serialize(value)
+		if err != nil {
+			return nil, ConstraintErrorAddPathSegment(err, field.propertyID)
+		}
+		rawData[field.propertyID] = serialized
+	}
+	return rawData, nil
+}
+
+// target returns the field of the struct, allocating the embedded pointers on the way.
+func (f *compiledField) target(elem reflect.Value) reflect.Value {
+	if f.embedded {
+		return fieldByIndexAllocating(elem, f.index)
+	}
+	return elem.Field(f.index[0])
+}
+
+// convertValueTo returns the value as the type, skipping the conversion if it already has the type.
+func convertValueTo(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.IsValid() && v.Type() == t {
+		return v
+	}
+	return convertValue(v, t)
+}
+
+// compiledEnum holds the lookup tables of an enum, built by Compile.
+type compiledEnum[T enumValue] struct {
+	// values maps each valid value to the copy stored in the schema.
+	values map[T]T
+	// aliases maps each alias to the valid value it stands for.
+	aliases map[string]T
+}
+
+func (e *EnumSchema[S, T]) compile() {
+	if e.compiled != nil {
+		return
+	}
+	c := &compiledEnum[T]{
+		values:  make(map[T]T, len(e.ValidValuesMap)),
+		aliases: map[string]T{},
+	}
+	for value := range e.ValidValuesMap {
+		c.values[value] = value
+	}
+	for value, aliases := range e.ValueAliasesMap {
+		for _, alias := range aliases {
+			c.aliases[alias] = value
+		}
+	}
+	e.compiled = c
+}
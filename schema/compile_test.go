@@ -0,0 +1,146 @@
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type CompileTestBase struct {
+	ID int64 `json:"id"`
+}
+
+type compileTestStruct struct {
+	*CompileTestBase `json:",inline"`
+	Name             string            `json:"name"`
+	Nickname         *string           `json:"nickname"`
+	Color            string            `json:"color"`
+	Count            int64             `json:"count"`
+	Tags             []string          `json:"tags"`
+	Labels           map[string]string `json:"labels"`
+	Child            *compileTestChild `json:"child"`
+}
+
+type compileTestChild struct {
+	Value string `json:"value"`
+}
+
+func newCompileTestProperty(t schema.Type) *schema.PropertySchema {
+	return schema.NewPropertySchema(t, nil, false, nil, nil, nil, nil, nil)
+}
+
+func newCompileTestSchema() *schema.ObjectSchema {
+	child := schema.NewStructMappedObjectSchema[*compileTestChild]("child", map[string]*schema.PropertySchema{
+		"value": newCompileTestProperty(schema.NewStringSchema(nil, nil, nil)),
+	})
+	return schema.NewStructMappedObjectSchema[compileTestStruct]("test", map[string]*schema.PropertySchema{
+		"id":       newCompileTestProperty(schema.NewIntSchema(nil, nil, nil)),
+		"name":     newCompileTestProperty(schema.NewStringSchema(nil, nil, nil)),
+		"nickname": newCompileTestProperty(schema.NewStringSchema(nil, nil, nil)),
+		"color": newCompileTestProperty(schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+			"red":   nil,
+			"green": nil,
+		}).AliasValue("red", "crimson")),
+		"count": newCompileTestProperty(schema.NewIntSchema(nil, nil, nil)).TreatEmptyAsDefaultValue(),
+		"tags":  newCompileTestProperty(schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil)),
+		"labels": newCompileTestProperty(schema.NewMapSchema(
+			schema.NewStringSchema(nil, nil, nil),
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			nil,
+		)),
+		"child": newCompileTestProperty(child),
+	})
+}
+
+func newCompileTestData() map[string]any {
+	return map[string]any{
+		"id":       1,
+		"name":     "Arca",
+		"nickname": "arcalot",
+		"color":    "crimson",
+		"tags":     []any{"a", "b"},
+		"labels":   map[string]any{"team": "flow"},
+		"child":    map[string]any{"value": "test"},
+	}
+}
+
+func TestObjectCompile(t *testing.T) {
+	plain := newCompileTestSchema()
+	compiled := newCompileTestSchema().Compile()
+
+	expected := assert.NoErrorR[any](t)(plain.Unserialize(newCompileTestData()))
+	result := assert.NoErrorR[any](t)(compiled.Unserialize(newCompileTestData()))
+	assert.Equals(t, result, expected)
+	unserialized := result.(compileTestStruct)
+	assert.Equals(t, unserialized.ID, int64(1))
+	assert.Equals(t, *unserialized.Nickname, "arcalot")
+	assert.Equals(t, unserialized.Color, "red")
+	assert.Equals(t, unserialized.Child.Value, "test")
+
+	expectedSerialized := assert.NoErrorR[any](t)(plain.Serialize(expected))
+	serialized := assert.NoErrorR[any](t)(compiled.Serialize(result))
+	assert.Equals(t, serialized, expectedSerialized)
+	assert.NoError(t, compiled.Validate(result))
+
+	// Without the embedded struct, its fields are not set.
+	unserialized.CompileTestBase = nil
+	serialized = assert.NoErrorR[any](t)(compiled.Serialize(unserialized))
+	_, ok := serialized.(map[string]any)["id"]
+	assert.Equals(t, ok, false)
+}
+
+func TestObjectCompileErrors(t *testing.T) {
+	plain := newCompileTestSchema()
+	compiled := newCompileTestSchema().Compile()
+
+	invalid := newCompileTestData()
+	invalid["color"] = "blue"
+	_, expectedErr := plain.Unserialize(invalid)
+	_, err := compiled.Unserialize(invalid)
+	assert.Error(t, err)
+	assert.Equals(t, err.Error(), expectedErr.Error())
+
+	invalidStruct := compileTestStruct{Color: "blue"}
+	_, expectedErr = plain.Serialize(invalidStruct)
+	_, err = compiled.Serialize(invalidStruct)
+	assert.Error(t, err)
+	assert.Equals(t, err.Error(), expectedErr.Error())
+	assert.Error(t, compiled.Validate(invalidStruct))
+}
+
+func TestScopeCompile(t *testing.T) {
+	scope := schema.NewScopeSchema(newCompileTestSchema()).Compile()
+	result := assert.NoErrorR[any](t)(scope.Unserialize(newCompileTestData()))
+	assert.Equals(t, result.(compileTestStruct).Child.Value, "test")
+}
+
+func BenchmarkObjectCompile(b *testing.B) {
+	for _, compile := range []bool{false, true} {
+		s := newCompileTestSchema()
+		if compile {
+			s = s.Compile()
+		}
+		data := newCompileTestData()
+		unserialized, err := s.Unserialize(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("unserialize/compiled=%t", compile), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Unserialize(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("serialize/compiled=%t", compile), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Serialize(unserialized); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
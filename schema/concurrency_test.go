@@ -0,0 +1,254 @@
+package schema_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// The tests in this file use the same schemas from many goroutines. They are meant to be run with -race.
+
+const concurrencyTestGoroutines = 8
+const concurrencyTestIterations = 50
+
+type concurrencyTestRoot struct {
+	Name    string                `json:"name"`
+	Count   int64                 `json:"count"`
+	Ratio   float64               `json:"ratio"`
+	Enabled bool                  `json:"enabled"`
+	Color   string                `json:"color"`
+	Tags    []string              `json:"tags"`
+	Labels  map[string]string     `json:"labels"`
+	Child   *concurrencyTestChild `json:"child"`
+	Choice  any                   `json:"choice"`
+	Extra   any                   `json:"extra"`
+	Retries int64                 `json:"retries"`
+}
+
+type concurrencyTestChild struct {
+	Value string `json:"value"`
+	Size  int64  `json:"size"`
+}
+
+func concurrencyTestProperty(t schema.Type, required bool) *schema.PropertySchema {
+	return schema.NewPropertySchema(t, nil, required, nil, nil, nil, nil, nil)
+}
+
+func newConcurrencyTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewStructMappedObjectSchema[concurrencyTestRoot]("root", map[string]*schema.PropertySchema{
+			"name":    concurrencyTestProperty(schema.NewStringSchema(schema.IntPointer(1), nil, nil), true),
+			"count":   concurrencyTestProperty(schema.NewIntSchema(schema.IntPointer(0), nil, nil), false),
+			"ratio":   concurrencyTestProperty(schema.NewFloatSchema(nil, nil, nil), false),
+			"enabled": concurrencyTestProperty(schema.NewBoolSchema(), false),
+			"color": concurrencyTestProperty(
+				schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+					"red":   {NameValue: schema.PointerTo("Red")},
+					"green": {NameValue: schema.PointerTo("Green")},
+				}).
+					AliasValue("red", "crimson"),
+				false,
+			),
+			"tags": concurrencyTestProperty(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, schema.IntPointer(100)),
+				false,
+			),
+			"labels": concurrencyTestProperty(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewStringSchema(nil, nil, nil), nil, nil),
+				false,
+			),
+			"child": concurrencyTestProperty(schema.NewRefSchema("child", nil), false),
+			"choice": concurrencyTestProperty(
+				schema.NewOneOfStringSchema[any](
+					map[string]schema.Object{
+						"first":  schema.NewRefSchema("first", nil),
+						"second": schema.NewRefSchema("second", nil),
+					},
+					"_type",
+					false,
+				),
+				false,
+			),
+			"extra": concurrencyTestProperty(schema.NewAnySchema(), false),
+			"retries": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, schema.PointerTo("3"), nil,
+			),
+		}),
+		schema.NewStructMappedObjectSchema[*concurrencyTestChild]("child", map[string]*schema.PropertySchema{
+			"value": concurrencyTestProperty(schema.NewStringSchema(nil, nil, nil), true),
+			"size":  concurrencyTestProperty(schema.NewIntSchema(nil, nil, schema.UnitBytes), false),
+		}),
+		schema.NewObjectSchema("first", map[string]*schema.PropertySchema{
+			"a": concurrencyTestProperty(schema.NewStringSchema(nil, nil, nil), true),
+		}),
+		schema.NewObjectSchema("second", map[string]*schema.PropertySchema{
+			"b": concurrencyTestProperty(schema.NewIntSchema(nil, nil, nil), true),
+		}),
+	)
+}
+
+func newConcurrencyTestData(i int) map[string]any {
+	choice := map[string]any{"_type": "first", "a": fmt.Sprintf("choice %d", i)}
+	if i%2 == 1 {
+		choice = map[string]any{"_type": "second", "b": i}
+	}
+	return map[string]any{
+		"name":    fmt.Sprintf("name %d", i),
+		"count":   i,
+		"ratio":   float64(i) / 3,
+		"enabled": i%2 == 0,
+		"color":   "crimson",
+		"tags":    []any{"a", "b", fmt.Sprintf("%d", i)},
+		"labels":  map[string]any{"index": fmt.Sprintf("%d", i)},
+		"child":   map[string]any{"value": "test", "size": "1kB"},
+		"choice":  choice,
+		"extra":   map[string]any{"nested": []any{"x", fmt.Sprintf("%d", i)}},
+	}
+}
+
+// runConcurrently calls the function with each iteration from several goroutines at once.
+func runConcurrently(t *testing.T, f func(i int) error) {
+	t.Helper()
+	wg := &sync.WaitGroup{}
+	for g := 0; g < concurrencyTestGoroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < concurrencyTestIterations; i++ {
+				if err := f(g*concurrencyTestIterations + i); err != nil {
+					t.Errorf("goroutine %d failed in iteration %d (%v)", g, i, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func testScopeConcurrently(t *testing.T, scope *schema.ScopeSchema) {
+	runConcurrently(t, func(i int) error {
+		data := newConcurrencyTestData(i)
+		unserialized, err := scope.Unserialize(data)
+		if err != nil {
+			return err
+		}
+		root := unserialized.(concurrencyTestRoot)
+		if root.Color != "red" || root.Child.Size != 1024 || root.Count != int64(i) || root.Retries != 3 {
+			return fmt.Errorf("unexpected unserialized data: %v", root)
+		}
+		if err := scope.Validate(unserialized); err != nil {
+			return err
+		}
+		serialized, err := scope.Serialize(unserialized)
+		if err != nil {
+			return err
+		}
+		if err := scope.ValidateCompatibility(serialized); err != nil {
+			return err
+		}
+		if _, err := scope.SelfSerialize(); err != nil {
+			return err
+		}
+		invalid := newConcurrencyTestData(i)
+		invalid["name"] = ""
+		if _, err := scope.Unserialize(invalid); err == nil {
+			return fmt.Errorf("invalid data unserialized without an error")
+		}
+		return nil
+	})
+}
+
+func TestConcurrentScope(t *testing.T) {
+	testScopeConcurrently(t, newConcurrencyTestScope())
+}
+
+func TestConcurrentCompiledScope(t *testing.T) {
+	testScopeConcurrently(t, newConcurrencyTestScope().Compile())
+}
+
+func TestConcurrentUnserializedScope(t *testing.T) {
+	// Scopes received from a plugin are map-based and resolve their references after unserialization.
+	serialized := assert.NoErrorR[any](t)(newConcurrencyTestScope().SelfSerialize())
+	scope := assert.NoErrorR[*schema.ScopeSchema](t)(schema.UnserializeScope(serialized))
+	scope.ApplySelf()
+	runConcurrently(t, func(i int) error {
+		unserialized, err := scope.Unserialize(newConcurrencyTestData(i))
+		if err != nil {
+			return err
+		}
+		if retries := unserialized.(map[string]any)["retries"]; retries != int64(3) {
+			return fmt.Errorf("unexpected retries: %v", retries)
+		}
+		if err := scope.Validate(unserialized); err != nil {
+			return err
+		}
+		_, err = scope.Serialize(unserialized)
+		return err
+	})
+}
+
+func TestConcurrentTypeCompatibility(t *testing.T) {
+	scope := newConcurrencyTestScope()
+	other := newConcurrencyTestScope()
+	runConcurrently(t, func(_ int) error {
+		if err := scope.ValidateCompatibility(other); err != nil {
+			return err
+		}
+		return scope.ValidateCompatibilityParallel(other, 2)
+	})
+}
+
+func TestConcurrentCallableSchema(t *testing.T) {
+	callable := schema.NewCallableSchema(
+		schema.NewCallableStep[concurrencyTestRoot](
+			"step",
+			newConcurrencyTestScope(),
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(newConcurrencyTestScope(), nil, false),
+			},
+			nil,
+			func(_ context.Context, input concurrencyTestRoot) (string, any) {
+				return "success", input
+			},
+		),
+	)
+	runConcurrently(t, func(i int) error {
+		outputID, output, err := callable.CallStep(context.Background(), fmt.Sprintf("run-%d", i), "step",
+			newConcurrencyTestData(i))
+		if err != nil {
+			return err
+		}
+		if outputID != "success" || output.(map[string]any)["name"] != fmt.Sprintf("name %d", i) {
+			return fmt.Errorf("unexpected output %s: %v", outputID, output)
+		}
+		_, err = callable.SelfSerialize()
+		return err
+	})
+}
+
+func TestConcurrentUnits(t *testing.T) {
+	units := schema.NewUnits(
+		schema.NewUnit("byte", "bytes", "B", "B"),
+		map[int64]*schema.UnitDefinition{
+			1024: schema.NewUnit("kibibyte", "kibibytes", "KiB", "KiB"),
+		},
+	)
+	intSchema := schema.NewIntSchema(nil, nil, units)
+	runConcurrently(t, func(i int) error {
+		value, err := intSchema.Unserialize("2KiB")
+		if err != nil {
+			return err
+		}
+		if value != int64(2048) {
+			return fmt.Errorf("unexpected value: %v", value)
+		}
+		if formatted := units.FormatShortInt(int64(i)); formatted == "" {
+			return fmt.Errorf("empty formatted value for %d", i)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,12 @@
+package schema
+
+// Deprecated marks a property, step, or enum value as no longer recommended for use. It is included in the schema, so
+// the engine can warn workflow authors before the item is removed.
+type Deprecated struct {
+	// Since holds the version in which the item was deprecated, if known.
+	Since *string `json:"since"`
+	// Message explains why the item is deprecated and what to do instead.
+	Message string `json:"message"`
+	// ReplacedBy holds the ID of the item that should be used instead, if any.
+	ReplacedBy *string `json:"replaced_by"`
+}
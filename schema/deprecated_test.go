@@ -0,0 +1,77 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestDeprecatedSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema(
+			"input",
+			map[string]*schema.PropertySchema{
+				"fruit": schema.NewPropertySchema(
+					schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+						"apple":  {NameValue: schema.PointerTo("Apple")},
+						"banana": {NameValue: schema.PointerTo("Banana")},
+					}).DeprecateValue("banana", schema.Deprecated{Message: "Bananas are out of season."}),
+					nil,
+					false,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				).Deprecate(schema.Deprecated{
+					Since:      schema.PointerTo("1.2.0"),
+					Message:    "Use fruits instead.",
+					ReplacedBy: schema.PointerTo("fruits"),
+				}),
+			},
+		),
+	)
+	callableSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[map[string]any](
+			"eat",
+			scope,
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(
+					schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+					nil,
+					false,
+				),
+			},
+			nil,
+			func(_ context.Context, _ map[string]any) (string, any) {
+				return "success", map[string]any{}
+			},
+		).(*schema.CallableStepSchema[any, map[string]any]).Deprecate(
+			schema.Deprecated{Message: "Use the cook step instead."},
+		),
+	)
+
+	serialized := assert.NoErrorR[any](t)(callableSchema.SelfSerialize())
+	unserialized := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(serialized))
+
+	step := unserialized.StepsValue["eat"]
+	assert.Equals(t, step.Deprecated().Message, "Use the cook step instead.")
+
+	property := step.Input().Properties()["fruit"]
+	assert.Equals(t, *property.Deprecated().Since, "1.2.0")
+	assert.Equals(t, property.Deprecated().Message, "Use fruits instead.")
+	assert.Equals(t, *property.Deprecated().ReplacedBy, "fruits")
+
+	enum := property.Type().(*schema.StringEnumSchema)
+	assert.Equals(t, enum.DeprecatedValues()["banana"].Message, "Bananas are out of season.")
+	assert.Nil(t, enum.DeprecatedValues()["apple"])
+}
+
+func TestDeprecateInvalidEnumValue(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{1: nil}, nil).
+			DeprecateValue(2, schema.Deprecated{Message: "test"})
+	})
+}
@@ -0,0 +1,179 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes what kind of difference a Change is.
+type ChangeKind string
+
+const (
+	// ChangeAdded is a named entry, such as a property, object, enum value or one-of type, that only exists in the
+	// new schema.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is a named entry that only exists in the old schema.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeTypeChanged is a type that was replaced by a type with a different type ID.
+	ChangeTypeChanged ChangeKind = "type_changed"
+	// ChangeConstraint is a changed setting of a type or property, such as a minimum or whether it is required.
+	ChangeConstraint ChangeKind = "constraint_changed"
+	// ChangeDisplay is a changed display value. It does not affect which data the schema accepts.
+	ChangeDisplay ChangeKind = "display_changed"
+	// ChangeValue is a value that differs between two values compared with DiffValues.
+	ChangeValue ChangeKind = "value_changed"
+)
+
+// Change is a single difference between two schemas.
+type Change struct {
+	Kind ChangeKind
+	// Path is the location of the change in the serialized schema, such as objects, the object ID, properties and
+	// the property ID.
+	Path []string
+	// Old is the serialized value in the old schema, or nil for added entries.
+	Old any
+	// New is the serialized value in the new schema, or nil for removed entries.
+	New any
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s added", strings.Join(c.Path, "."))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s removed", strings.Join(c.Path, "."))
+	default:
+		return fmt.Sprintf("%s changed from %v to %v", strings.Join(c.Path, "."), c.Old, c.New)
+	}
+}
+
+// Diff compares two schemas structurally and returns their differences, ordered by path. Plugin maintainers can use
+// it to check that a new version of a schema stays backwards compatible before releasing it. The comparison works on
+// the serialized definitions, so it also works on schemas unserialized from another plugin version. It panics with a
+// BadArgumentError if a schema cannot be serialized.
+func Diff(a, b Type) []Change {
+	var changes []Change
+	diffSerialized(nil, serializeForDiff(a), serializeForDiff(b), false, &changes)
+	return changes
+}
+
+// DiffSchemas compares two plugin schemas, such as a CallableSchema and a SchemaSchema unserialized from an older
+// plugin version, like Diff.
+func DiffSchemas(a, b interface{ SelfSerialize() (any, error) }) ([]Change, error) {
+	serializedA, err := a.SelfSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize old plugin schema for comparison (%w)", err)
+	}
+	serializedB, err := b.SelfSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize new plugin schema for comparison (%w)", err)
+	}
+	var changes []Change
+	diffSerialized(nil, serializedA, serializedB, false, &changes)
+	return changes, nil
+}
+
+// Equals returns true if the two schemas accept and produce the same data. Unlike Diff, it ignores display values.
+func Equals(a, b Type) bool {
+	for _, change := range Diff(a, b) {
+		if change.Kind != ChangeDisplay {
+			return false
+		}
+	}
+	return true
+}
+
+func serializeForDiff(t Type) any {
+	serialized, err := valueType.Serialize(t)
+	if err != nil {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot serialize %s schema for comparison (%v)", t.TypeID(), err),
+		})
+	}
+	return serialized
+}
+
+// diffSerialized compares two serialized schema fragments. The self-schema serializes structs, such as types and
+// properties, to map[string]any, and Go maps, such as the properties of an object, to map[any]any. The entries of the
+// latter are named, so they are reported as added or removed, while the fields of the former are settings.
+func diffSerialized(path []string, a, b any, display bool, changes *[]Change) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	kind := ChangeConstraint
+	if display {
+		kind = ChangeDisplay
+	}
+	switch aValue := a.(type) {
+	case map[string]any:
+		bValue, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		if aValue["type_id"] != bValue["type_id"] {
+			*changes = append(*changes, Change{ChangeTypeChanged, path, aValue["type_id"], bValue["type_id"]})
+			return
+		}
+		for _, key := range sortedDiffKeys(aValue, bValue) {
+			diffSerialized(appendPath(path, key), aValue[key], bValue[key], display || key == "display", changes)
+		}
+		return
+	case map[any]any:
+		bValue, ok := b.(map[any]any)
+		if !ok {
+			break
+		}
+		// The values of enums only hold their display values.
+		entryDisplay := display || (len(path) > 0 && path[len(path)-1] == "values")
+		for _, key := range sortedDiffKeys(aValue, bValue) {
+			aEntry, aOk := aValue[key]
+			bEntry, bOk := bValue[key]
+			entryPath := appendPath(path, fmt.Sprintf("%v", key))
+			switch {
+			case !aOk:
+				*changes = append(*changes, Change{ChangeAdded, entryPath, nil, bEntry})
+			case !bOk:
+				*changes = append(*changes, Change{ChangeRemoved, entryPath, aEntry, nil})
+			default:
+				diffSerialized(entryPath, aEntry, bEntry, entryDisplay, changes)
+			}
+		}
+		return
+	case []any:
+		// Lists of types, such as the items of tuples, are compared item by item.
+		bValue, ok := b.([]any)
+		if !ok || len(aValue) != len(bValue) {
+			break
+		}
+		for i := range aValue {
+			diffSerialized(appendPath(path, fmt.Sprintf("[%d]", i)), aValue[i], bValue[i], display, changes)
+		}
+		return
+	}
+	*changes = append(*changes, Change{kind, path, a, b})
+}
+
+// sortedDiffKeys returns the union of the keys of two maps, sorted by their string form.
+func sortedDiffKeys[K comparable](a, b map[K]any) []K {
+	keys := make([]K, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j])
+	})
+	return keys
+}
+
+func appendPath(path []string, segment string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, segment)
+}
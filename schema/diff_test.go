@@ -0,0 +1,210 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func diffTestScope(
+	nameMin int,
+	nameDescription string,
+	extra map[string]*schema.PropertySchema,
+	ageType schema.Type,
+) *schema.ScopeSchema {
+	properties := map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(schema.IntPointer(nameMin), nil, nil),
+			schema.NewDisplayValue(schema.PointerTo("Name"), schema.PointerTo(nameDescription), nil),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"age": schema.NewPropertySchema(ageType, nil, false, nil, nil, nil, nil, nil),
+	}
+	for id, property := range extra {
+		properties[id] = property
+	}
+	return schema.NewScopeSchema(schema.NewObjectSchema("person", properties))
+}
+
+func TestDiff(t *testing.T) {
+	old := diffTestScope(1, "The name.", nil, schema.NewIntSchema(nil, nil, nil))
+	assert.Equals(t, len(schema.Diff(old, diffTestScope(1, "The name.", nil, schema.NewIntSchema(nil, nil, nil)))), 0)
+	assert.Equals(t, schema.Equals(old, diffTestScope(1, "The name.", nil, schema.NewIntSchema(nil, nil, nil))), true)
+
+	changes := schema.Diff(
+		old,
+		diffTestScope(
+			2,
+			"The full name.",
+			map[string]*schema.PropertySchema{
+				"email": schema.NewPropertySchema(
+					schema.NewStringSchema(nil, nil, nil),
+					nil,
+					false,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+			schema.NewStringSchema(nil, nil, nil),
+		),
+	)
+	assert.Equals(t, len(changes), 4)
+	assert.Equals(t, changes[0].Kind, schema.ChangeTypeChanged)
+	assert.Equals(t, changes[0].Path, []string{"objects", "person", "properties", "age", "type"})
+	assert.Equals(t, changes[0].Old, any("integer"))
+	assert.Equals(t, changes[0].New, any("string"))
+	assert.Equals(t, changes[1].Kind, schema.ChangeAdded)
+	assert.Equals(t, changes[1].Path, []string{"objects", "person", "properties", "email"})
+	assert.Equals(t, changes[2].Kind, schema.ChangeDisplay)
+	assert.Equals(t, changes[2].Path, []string{"objects", "person", "properties", "name", "display", "description"})
+	assert.Equals(t, changes[3].Kind, schema.ChangeConstraint)
+	assert.Equals(t, changes[3].Path, []string{"objects", "person", "properties", "name", "type", "min"})
+	assert.Equals(t, changes[3].String(), "objects.person.properties.name.type.min changed from 1 to 2")
+
+	// Display changes alone keep the schemas equal.
+	assert.Equals(t, schema.Equals(old, diffTestScope(1, "Another.", nil, schema.NewIntSchema(nil, nil, nil))), true)
+	assert.Equals(t, schema.Equals(old, diffTestScope(2, "The name.", nil, schema.NewIntSchema(nil, nil, nil))), false)
+
+	removed := schema.Diff(diffTestScope(1, "The name.", nil, schema.NewIntSchema(nil, nil, nil)), schema.NewScopeSchema(
+		schema.NewObjectSchema("person", map[string]*schema.PropertySchema{}),
+	))
+	assert.Equals(t, len(removed), 2)
+	assert.Equals(t, removed[0].Kind, schema.ChangeRemoved)
+	assert.Equals(t, removed[0].String(), "objects.person.properties.age removed")
+}
+
+func TestDiffEnum(t *testing.T) {
+	old := schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+		"a": schema.NewDisplayValue(schema.PointerTo("A"), nil, nil),
+	})
+	changes := schema.Diff(old, schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+		"a": schema.NewDisplayValue(schema.PointerTo("First"), nil, nil),
+		"b": schema.NewDisplayValue(schema.PointerTo("B"), nil, nil),
+	}))
+	assert.Equals(t, len(changes), 2)
+	assert.Equals(t, changes[0].Kind, schema.ChangeDisplay)
+	assert.Equals(t, changes[0].Path, []string{"values", "a", "name"})
+	assert.Equals(t, changes[1].Kind, schema.ChangeAdded)
+	assert.Equals(t, changes[1].Path, []string{"values", "b"})
+}
+
+func TestDiffUnserialized(t *testing.T) {
+	// Schemas unserialized from another plugin version compare equal to the original.
+	original := diffTestScope(1, "The name.", nil, schema.NewIntSchema(nil, nil, nil))
+	serialized := assert.NoErrorR[any](t)(original.SelfSerialize())
+	unserialized := assert.NoErrorR[*schema.ScopeSchema](t)(schema.UnserializeScope(serialized))
+	assert.Equals(t, len(schema.Diff(original, unserialized)), 0)
+}
+
+type valueDiffTestServer struct {
+	Host     string            `json:"host"`
+	Port     *int64            `json:"port"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Password string            `json:"password"`
+}
+
+var valueDiffTestSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[valueDiffTestServer](
+		"server",
+		map[string]*schema.PropertySchema{
+			"host": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"port": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+			"tags": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"labels": schema.NewPropertySchema(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewStringSchema(nil, nil, nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"password": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			).MarkSensitive(),
+		},
+	),
+)
+
+func TestDiffValues(t *testing.T) {
+	old := valueDiffTestServer{
+		Host:     "localhost",
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"app": "web", "tier/name": "front"},
+		Password: "secret",
+	}
+	assert.Equals(t, len(schema.DiffValues(valueDiffTestSchema, old, old)), 0)
+
+	changes := schema.DiffValues(valueDiffTestSchema, old, valueDiffTestServer{
+		Host:     "example.com",
+		Port:     schema.PointerTo(int64(8080)),
+		Tags:     []string{"a"},
+		Labels:   map[string]string{"app": "web", "tier/name": "back"},
+		Password: "changed",
+	})
+	assert.Equals(t, changes, []schema.ValueChange{
+		{Kind: schema.ChangeValue, Path: "/host", Old: "localhost", New: "example.com"},
+		{Kind: schema.ChangeValue, Path: "/labels/tier~1name", Old: "front", New: "back"},
+		{Kind: schema.ChangeValue, Path: "/password", Old: schema.RedactedPlaceholder, New: schema.RedactedPlaceholder},
+		{Kind: schema.ChangeAdded, Path: "/port", New: int64(8080)},
+		{Kind: schema.ChangeRemoved, Path: "/tags/1", Old: "b"},
+	})
+	assert.Equals(t, changes[0].String(), "/host changed from localhost to example.com")
+	assert.Equals(t, changes[3].String(), "/port added: 8080")
+
+	// Values that don't match the schema are compared as a whole.
+	changes = schema.DiffValues(valueDiffTestSchema, old, "invalid")
+	assert.Equals(t, len(changes), 1)
+	assert.Equals(t, changes[0].Kind, schema.ChangeValue)
+	assert.Equals(t, changes[0].Path, "")
+	assert.Equals(t, changes[0].New, any("invalid"))
+}
+
+func TestDiffValuesOneOf(t *testing.T) {
+	oneOf := schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{
+			"a": schema.NewObjectSchema("a", map[string]*schema.PropertySchema{
+				"value": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			}),
+			"b": schema.NewObjectSchema("b", map[string]*schema.PropertySchema{
+				"value": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			}),
+		},
+		"kind",
+		false,
+	)
+	a1 := assert.NoErrorR[any](t)(oneOf.Unserialize(map[string]any{"kind": "a", "value": "1"}))
+	a2 := assert.NoErrorR[any](t)(oneOf.Unserialize(map[string]any{"kind": "a", "value": "2"}))
+	b1 := assert.NoErrorR[any](t)(oneOf.Unserialize(map[string]any{"kind": "b", "value": "1"}))
+
+	assert.Equals(t, schema.DiffValues(oneOf, a1, a2), []schema.ValueChange{
+		{Kind: schema.ChangeValue, Path: "/value", Old: "1", New: "2"},
+	})
+	assert.Equals(t, schema.DiffValues(oneOf, a1, b1), []schema.ValueChange{
+		{
+			Kind: schema.ChangeValue,
+			Old:  map[string]any{"kind": "a", "value": "1"},
+			New:  map[string]any{"kind": "b", "value": "1"},
+		},
+	})
+}
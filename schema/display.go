@@ -16,11 +16,18 @@ func NewDisplayValue(name *string, description *string, icon *string) *DisplayVa
 	}
 }
 
+// DisplayFormatVersion is the version of the serialized form of displays this SDK understands. The version is only
+// serialized if it is above 1, so readers that reject unknown fields can read the displays of this SDK. Readers ignore
+// the fields they don't know, so they can read the displays of newer versions without the new fields.
+const DisplayFormatVersion int64 = 1
+
 // DisplayValue holds the data related to displaying fields.
 type DisplayValue struct {
 	NameValue        *string `json:"name"`
 	DescriptionValue *string `json:"description"`
 	IconValue        *string `json:"icon"`
+	// VersionValue is the version of the serialized form. Nil means version 1.
+	VersionValue *int64 `json:"version,omitempty"`
 }
 
 func (d DisplayValue) Name() *string {
@@ -34,3 +41,12 @@ func (d DisplayValue) Description() *string {
 func (d DisplayValue) Icon() *string {
 	return d.IconValue
 }
+
+// Version returns the version of the serialized form of the display. A version above DisplayFormatVersion means the
+// display was written by a newer SDK and may have held fields that were ignored.
+func (d DisplayValue) Version() int64 {
+	if d.VersionValue == nil {
+		return 1
+	}
+	return *d.VersionValue
+}
@@ -0,0 +1,286 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DumpFormat is the output format of Dump.
+type DumpFormat string
+
+const (
+	// DumpFormatYAML renders the value as YAML. The discriminators of one-of types are annotated with a comment.
+	DumpFormatYAML DumpFormat = "yaml"
+	// DumpFormatJSON renders the value as JSON. Since JSON has no comments, the discriminators of one-of types are
+	// annotated with an additional "//" entry in the object.
+	DumpFormatJSON DumpFormat = "json"
+)
+
+// DumpOptions configures Dump. The zero value renders YAML indented by 2 spaces.
+type DumpOptions struct {
+	// Format is the output format, DumpFormatYAML if empty.
+	Format DumpFormat
+	// Indent is the number of spaces nested values are indented with, 2 if zero.
+	Indent int
+}
+
+// dumpCommentKey is the key of the entries annotating JSON objects.
+const dumpCommentKey = "//"
+
+// Dump renders a value of the type as indented text for logs and error outputs. The value may be serialized or
+// unserialized, and it doesn't need to be valid. Sensitive properties are masked like Redact does, the discriminators
+// of one-of types are annotated with the ID of the selected variant, and the properties of objects are sorted with the
+// discriminator first, so dumps of equal values are equal.
+func Dump(t Type, v any, options DumpOptions) (string, error) {
+	indent := options.Indent
+	if indent == 0 {
+		indent = 2
+	}
+	node, err := dumpNode(t, Redact(t, v))
+	if err != nil {
+		return "", err
+	}
+	switch options.Format {
+	case DumpFormatYAML, "":
+		buf := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(buf)
+		encoder.SetIndent(indent)
+		if err := encoder.Encode(node); err != nil {
+			return "", fmt.Errorf("failed to dump value as YAML (%w)", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("failed to dump value as YAML (%w)", err)
+		}
+		return buf.String(), nil
+	case DumpFormatJSON:
+		buf := &bytes.Buffer{}
+		if err := writeDumpJSON(buf, node, strings.Repeat(" ", indent), 0); err != nil {
+			return "", err
+		}
+		buf.WriteByte('\n')
+		return buf.String(), nil
+	default:
+		return "", BadArgumentError{Message: fmt.Sprintf("unsupported dump format: %s", options.Format)}
+	}
+}
+
+// dumpNode converts the redacted value to a YAML node. The type is only used to order and annotate the entries, so
+// values that don't match it are rendered as they are.
+func dumpNode(t Type, value any) (*yaml.Node, error) {
+	if value == nil {
+		return encodeDumpNode(nil)
+	}
+	if objectSchema, ok := objectSchemaOf(t); ok {
+		return dumpObject(objectSchema, value)
+	}
+	switch typed := t.(type) {
+	case untypedListSchema:
+		return dumpList(value, func(int) Type { return typed.itemType() })
+	case untypedMapSchema:
+		return dumpMap(value, typed.valueType())
+	case *TupleSchema:
+		return dumpList(value, func(i int) Type {
+			if i < len(typed.ItemsValue) {
+				return typed.ItemsValue[i]
+			}
+			return nil
+		})
+	case variantSelector:
+		return dumpOneOf(typed, value)
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice:
+		if _, isBytes := value.([]byte); !isBytes {
+			return dumpList(value, func(int) Type { return nil })
+		}
+	case reflect.Map:
+		return dumpMap(value, nil)
+	}
+	if _, ok := value.(orderedMap); ok {
+		return dumpMap(value, nil)
+	}
+	return encodeDumpNode(value)
+}
+
+func dumpObject(o *ObjectSchema, value any) (*yaml.Node, error) {
+	entries, ok := mapEntriesOf(value)
+	if !ok {
+		return encodeDumpNode(value)
+	}
+	sortMapEntries(entries)
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, entry := range entries {
+		key := fmt.Sprintf("%v", entry.key.Interface())
+		var propertyType Type
+		if property, ok := o.PropertiesValue[key]; ok {
+			propertyType = property.Type()
+		}
+		if err := appendDumpEntry(node, key, propertyType, entry.value.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func dumpMap(value any, valueType Type) (*yaml.Node, error) {
+	entries, ok := mapEntriesOf(value)
+	if !ok {
+		return encodeDumpNode(value)
+	}
+	if _, ordered := value.(orderedMap); !ordered {
+		sortMapEntries(entries)
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, entry := range entries {
+		if err := appendDumpEntry(node, entry.key.Interface(), valueType, entry.value.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func appendDumpEntry(node *yaml.Node, key any, valueType Type, value any) error {
+	keyNode, err := encodeDumpNode(key)
+	if err != nil {
+		return err
+	}
+	valueNode, err := dumpNode(valueType, value)
+	if err != nil {
+		return fmt.Errorf("failed to dump %v (%w)", key, err)
+	}
+	node.Content = append(node.Content, keyNode, valueNode)
+	return nil
+}
+
+func dumpList(value any, itemType func(i int) Type) (*yaml.Node, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return encodeDumpNode(value)
+	}
+	node := &yaml.Node{Kind: yaml.SequenceNode}
+	for i := 0; i < v.Len(); i++ {
+		itemNode, err := dumpNode(itemType(i), v.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump item %d (%w)", i, err)
+		}
+		node.Content = append(node.Content, itemNode)
+	}
+	return node, nil
+}
+
+// dumpOneOf renders the selected variant and moves its discriminator to the front, annotated with the variant ID.
+func dumpOneOf(o variantSelector, value any) (*yaml.Node, error) {
+	_, selectedType, _, err := o.selectVariant(value)
+	if err != nil {
+		return dumpNode(nil, value)
+	}
+	var node *yaml.Node
+	if objectSchema, ok := objectSchemaOf(selectedType); ok {
+		node, err = dumpObject(objectSchema, value)
+	} else {
+		node, err = dumpNode(nil, value)
+	}
+	if err != nil || node.Kind != yaml.MappingNode {
+		return node, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != o.DiscriminatorFieldName() {
+			continue
+		}
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		valueNode.LineComment = "variant " + selectedType.ID()
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		node.Content = append([]*yaml.Node{keyNode, valueNode}, node.Content...)
+		break
+	}
+	return node, nil
+}
+
+func encodeDumpNode(value any) (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to dump %T (%w)", value, err)
+	}
+	return node, nil
+}
+
+// writeDumpJSON writes the YAML node as JSON, with the comments of the entries as "//" entries. Unlike json.Marshal,
+// it keeps the order of the entries and doesn't escape HTML characters, such as those of RedactedPlaceholder.
+func writeDumpJSON(buf *bytes.Buffer, node *yaml.Node, indent string, depth int) error {
+	writeItem := func(i int) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+		buf.WriteString(strings.Repeat(indent, depth+1))
+	}
+	closeContainer := func(length int, closing byte) {
+		if length > 0 {
+			buf.WriteByte('\n')
+			buf.WriteString(strings.Repeat(indent, depth))
+		}
+		buf.WriteByte(closing)
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		count := 0
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			if valueNode.LineComment != "" {
+				writeItem(count)
+				count++
+				writeDumpJSONString(buf, dumpCommentKey)
+				buf.WriteString(": ")
+				writeDumpJSONString(buf, keyNode.Value+": "+valueNode.LineComment)
+			}
+			writeItem(count)
+			count++
+			writeDumpJSONString(buf, keyNode.Value)
+			buf.WriteString(": ")
+			if err := writeDumpJSON(buf, valueNode, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		closeContainer(count, '}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			writeItem(i)
+			if err := writeDumpJSON(buf, item, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		closeContainer(len(node.Content), ']')
+	default:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("failed to dump value as JSON (%w)", err)
+		}
+		if err := writeDumpJSONScalar(buf, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeDumpJSONString(buf *bytes.Buffer, value string) {
+	// Strings always encode.
+	_ = writeDumpJSONScalar(buf, value)
+}
+
+func writeDumpJSONScalar(buf *bytes.Buffer, value any) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to dump %T as JSON (%w)", value, err)
+	}
+	// Encode terminates the value with a newline.
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
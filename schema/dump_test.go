@@ -0,0 +1,107 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type dumpTestConfig struct {
+	Name   string `json:"name"`
+	Source any    `json:"source"`
+}
+
+type dumpTestGit struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+type dumpTestFile struct {
+	Path string `json:"path"`
+}
+
+var dumpTestSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[dumpTestConfig]("config", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"source": schema.NewPropertySchema(
+			schema.NewOneOfStringSchema[any](
+				map[string]schema.Object{
+					"git":  schema.NewRefSchema("git", nil),
+					"file": schema.NewRefSchema("file", nil),
+				},
+				"type",
+				false,
+			),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}),
+	schema.NewStructMappedObjectSchema[dumpTestGit]("git", map[string]*schema.PropertySchema{
+		"url": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"token": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+		).MarkSensitive(),
+	}),
+	schema.NewStructMappedObjectSchema[dumpTestFile]("file", map[string]*schema.PropertySchema{
+		"path": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	}),
+)
+
+func TestDump(t *testing.T) {
+	value := dumpTestConfig{
+		Name:   "checkout",
+		Source: dumpTestGit{URL: "https://example.com/repo.git", Token: "secret"},
+	}
+	assert.Equals(
+		t,
+		assert.NoErrorR[string](t)(schema.Dump(dumpTestSchema, value, schema.DumpOptions{})),
+		`name: checkout
+source:
+  type: git # variant git
+  token: <redacted>
+  url: https://example.com/repo.git
+`,
+	)
+	assert.Equals(
+		t,
+		assert.NoErrorR[string](t)(schema.Dump(dumpTestSchema, value, schema.DumpOptions{
+			Format: schema.DumpFormatJSON,
+			Indent: 4,
+		})),
+		`{
+    "name": "checkout",
+    "source": {
+        "//": "type: variant git",
+        "type": "git",
+        "token": "<redacted>",
+        "url": "https://example.com/repo.git"
+    }
+}
+`,
+	)
+}
+
+func TestDumpSerialized(t *testing.T) {
+	// Serialized and invalid data is dumped as well, so it can be included in error outputs.
+	dump := assert.NoErrorR[string](t)(schema.Dump(dumpTestSchema, map[string]any{
+		"name":   "checkout",
+		"source": map[string]any{"type": "git", "token": "secret", "extra": []any{1, "2"}},
+	}, schema.DumpOptions{}))
+	assert.Equals(t, dump, `name: checkout
+source:
+  type: git # variant git
+  extra:
+    - 1
+    - "2"
+  token: <redacted>
+`)
+
+	_, err := schema.Dump(dumpTestSchema, nil, schema.DumpOptions{Format: "xml"})
+	assert.Error(t, err)
+}
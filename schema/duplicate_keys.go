@@ -0,0 +1,45 @@
+package schema
+
+// DuplicateKeyPolicy determines how a key that appears more than once in the same JSON object or YAML mapping is
+// handled when a document is decoded.
+type DuplicateKeyPolicy string
+
+const (
+	// DuplicateKeysReject fails decoding with a ConstraintError pointing to the repeated key. This is the default.
+	DuplicateKeysReject DuplicateKeyPolicy = "reject"
+	// DuplicateKeysLastWins keeps the last value of the key, like encoding/json does, and reports each repeated key to
+	// the Warn function of the options.
+	DuplicateKeysLastWins DuplicateKeyPolicy = "last_wins"
+)
+
+// DecodeOptions configures how UnserializeJSON and UnserializeYAMLWithOptions decode documents before unserializing
+// them. The zero value rejects duplicate keys.
+type DecodeOptions struct {
+	// DuplicateKeys is the handling of repeated keys. Empty means DuplicateKeysReject.
+	DuplicateKeys DuplicateKeyPolicy
+	// Warn is called with an error describing each repeated key kept with DuplicateKeysLastWins, for example to log
+	// it. It may be nil.
+	Warn func(err *ConstraintError)
+}
+
+// duplicateKey handles a repeated key according to the options. It returns the error if the key is rejected.
+func (o DecodeOptions) duplicateKey(err *ConstraintError) error {
+	if o.DuplicateKeys != DuplicateKeysLastWins {
+		return err
+	}
+	if o.Warn != nil {
+		o.Warn(err)
+	}
+	return nil
+}
+
+// UnserializeJSON decodes the JSON data and unserializes the result with the type, handling duplicate keys as the
+// options say. The data is otherwise decoded like json.Unmarshal decodes into an any, except that numbers are kept as
+// json.Number, so large integers are not rounded.
+func UnserializeJSON(t Type, data []byte, options DecodeOptions) (any, error) {
+	decoded, err := decodeJSON(data, options)
+	if err != nil {
+		return nil, err
+	}
+	return t.Unserialize(decoded)
+}
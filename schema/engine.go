@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EngineRequirements tags a step or property with what the engine running it has to support. Engines that announce
+// their capabilities, for example in the ATP start message, are not offered the steps and properties they can't run,
+// and are refused if they try to use them anyway, instead of failing in the middle of a workflow.
+type EngineRequirements struct {
+	// MinVersion holds the earliest engine version that supports the item, for example "1.4.0".
+	MinVersion *string `json:"min_version"`
+	// Features lists the engine features the item needs. All of them have to be supported.
+	Features []string `json:"features"`
+}
+
+// EngineCapabilities describes what the engine connected to the plugin supports.
+type EngineCapabilities struct {
+	// Version holds the version of the engine. Engines that don't announce it are assumed to be too old for all items
+	// with a minimum version.
+	Version *string `json:"version"`
+	// Features lists the features the engine supports.
+	Features []string `json:"features"`
+}
+
+// Supports returns an error explaining what the engine lacks if it doesn't meet the requirements. Nil requirements
+// are always met.
+func (c EngineCapabilities) Supports(requirements *EngineRequirements) error {
+	if requirements == nil {
+		return nil
+	}
+	if requirements.MinVersion != nil {
+		if c.Version == nil {
+			return fmt.Errorf("engine version %s or later required, but the engine did not announce its version",
+				*requirements.MinVersion)
+		}
+		if compareEngineVersions(*c.Version, *requirements.MinVersion) < 0 {
+			return fmt.Errorf("engine version %s or later required, but the engine is version %s",
+				*requirements.MinVersion, *c.Version)
+		}
+	}
+	var missing []string
+	for _, feature := range requirements.Features {
+		if !slices.Contains(c.Features, feature) {
+			missing = append(missing, feature)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("engine features required, but not supported by the engine: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// compareEngineVersions compares two dot-separated versions part by part, numerically where both parts are numbers.
+// A leading "v" is ignored, and a pre-release such as "1.4.0-rc1" is lower than the release itself.
+func compareEngineVersions(a string, b string) int {
+	a, aPreRelease, aHasPreRelease := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	b, bPreRelease, bHasPreRelease := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		aNumber, aErr := strconv.ParseUint(aPart, 10, 64)
+		bNumber, bErr := strconv.ParseUint(bPart, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil && aNumber != bNumber:
+			if aNumber < bNumber {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && aPart != bPart:
+			return strings.Compare(aPart, bPart)
+		}
+	}
+	switch {
+	case aHasPreRelease && !bHasPreRelease:
+		return -1
+	case !aHasPreRelease && bHasPreRelease:
+		return 1
+	default:
+		return strings.Compare(aPreRelease, bPreRelease)
+	}
+}
+
+// CheckEngine returns an error if the engine can't run the step with the given serialized input: if it doesn't meet
+// the requirements of the step, or if the input sets a property of the root input object, directly or by an alias,
+// whose requirements the engine doesn't meet.
+func (s CallableSchema) CheckEngine(stepID string, data any, capabilities EngineCapabilities) error {
+	step, ok := s.StepsValue[stepID]
+	if !ok {
+		return NoSuchStepError{Step: stepID}
+	}
+	if err := capabilities.Supports(step.ToStepSchema().EngineRequirementsValue); err != nil {
+		return fmt.Errorf("step %s is not supported by the engine (%w)", stepID, err)
+	}
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Map {
+		return nil
+	}
+	set := map[string]bool{}
+	for _, key := range value.MapKeys() {
+		set[fmt.Sprintf("%v", key.Interface())] = true
+	}
+	for propertyID, property := range step.Input().Properties() {
+		if !set[propertyID] && !slices.ContainsFunc(property.AliasesValue, func(alias string) bool { return set[alias] }) {
+			continue
+		}
+		if err := capabilities.Supports(property.EngineRequirements()); err != nil {
+			return fmt.Errorf("property %s of step %s is not supported by the engine (%w)", propertyID, stepID, err)
+		}
+	}
+	return nil
+}
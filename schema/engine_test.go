@@ -0,0 +1,114 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestEngineCapabilitiesSupports(t *testing.T) {
+	engine := schema.EngineCapabilities{Version: schema.PointerTo("v1.10.0"), Features: []string{"a", "b"}}
+	assert.NoError(t, engine.Supports(nil))
+	assert.NoError(t, engine.Supports(&schema.EngineRequirements{Features: []string{"b"}}))
+	assert.Error(t, engine.Supports(&schema.EngineRequirements{Features: []string{"a", "c"}}))
+	assert.Error(t, schema.EngineCapabilities{}.Supports(&schema.EngineRequirements{MinVersion: schema.PointerTo("1")}))
+
+	for minVersion, supported := range map[string]bool{
+		"1.9.0":       true,
+		"1.10":        true,
+		"1.10.0":      true,
+		"1.10.0-rc1":  true,
+		"1.10.1":      false,
+		"1.11.0-rc1":  false,
+		"2":           false,
+		"v0.99.99999": true,
+	} {
+		t.Run(minVersion, func(t *testing.T) {
+			err := engine.Supports(&schema.EngineRequirements{MinVersion: &minVersion})
+			assert.Equals(t, err == nil, supported)
+		})
+	}
+	preRelease := schema.EngineCapabilities{Version: schema.PointerTo("1.10.0-rc2")}
+	assert.Error(t, preRelease.Supports(&schema.EngineRequirements{MinVersion: schema.PointerTo("1.10.0")}))
+	assert.NoError(t, preRelease.Supports(&schema.EngineRequirements{MinVersion: schema.PointerTo("1.10.0-rc1")}))
+}
+
+type engineTestInput struct {
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+	Shade *string `json:"shade,omitempty"`
+}
+
+func engineTestSchema() *schema.CallableSchema {
+	optionalString := func() *schema.PropertySchema {
+		return schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil)
+	}
+	handler := func(_ context.Context, _ engineTestInput) (string, any) {
+		return "success", map[string]any{}
+	}
+	input := schema.NewScopeSchema(schema.NewStructMappedObjectSchema[engineTestInput](
+		"input",
+		map[string]*schema.PropertySchema{
+			"name":  schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"color": optionalString().RequireEngine(schema.EngineRequirements{Features: []string{"colors"}}),
+			"shade": optionalString().RequireIfNotValue("color", "red"),
+		},
+	))
+	outputs := func() map[string]*schema.StepOutputSchema {
+		return map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+				nil,
+				false,
+			),
+		}
+	}
+	modern := schema.NewCallableStep[engineTestInput]("modern", input, outputs(), nil, handler)
+	modern.(*schema.CallableStepSchema[any, engineTestInput]).
+		RequireEngine(schema.EngineRequirements{MinVersion: schema.PointerTo("2.0.0")})
+	return schema.NewCallableSchema(
+		schema.NewCallableStep[engineTestInput]("basic", input, outputs(), nil, handler),
+		modern,
+	)
+}
+
+func TestCallableSchemaCheckEngine(t *testing.T) {
+	callable := engineTestSchema()
+	oldEngine := schema.EngineCapabilities{Version: schema.PointerTo("1.0.0")}
+	newEngine := schema.EngineCapabilities{Version: schema.PointerTo("2.0.0"), Features: []string{"colors"}}
+
+	assert.NoError(t, callable.CheckEngine("basic", map[string]any{"name": "a"}, oldEngine))
+	assert.Error(t, callable.CheckEngine("basic", map[any]any{"name": "a", "color": "red"}, oldEngine))
+	assert.Error(t, callable.CheckEngine("modern", map[string]any{"name": "a"}, oldEngine))
+	assert.Error(t, callable.CheckEngine("nonexistent", map[string]any{}, newEngine))
+	assert.NoError(t, callable.CheckEngine("modern", map[string]any{"name": "a", "color": "red"}, newEngine))
+}
+
+func TestSelfSerializeWithOptionsEngine(t *testing.T) {
+	callable := engineTestSchema()
+
+	// The requirements are part of the schema.
+	full := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(
+		assert.NoErrorR[any](t)(callable.SelfSerialize()),
+	))
+	assert.Equals(t, *full.StepsValue["modern"].EngineRequirements().MinVersion, "2.0.0")
+	assert.Equals(t, full.StepsValue["basic"].InputValue.Properties()["color"].EngineRequirements().Features,
+		[]string{"colors"})
+
+	serialized := assert.NoErrorR[any](t)(callable.SelfSerializeWithOptions(schema.SerializeOptions{
+		Engine: &schema.EngineCapabilities{Version: schema.PointerTo("1.5.0")},
+	}))
+	offered := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(serialized))
+	assert.Equals(t, len(offered.StepsValue), 1)
+	properties := offered.StepsValue["basic"].InputValue.Properties()
+	_, hasColor := properties["color"]
+	assert.Equals(t, hasColor, false)
+	// The color is never red if it can't be set, so the shade is always required.
+	assert.Equals(t, properties["shade"].Required(), true)
+	assert.Equals(t, len(properties["shade"].RequiredIfConditions()), 0)
+
+	// The schema of the plugin is left as is.
+	assert.Equals(t, len(callable.StepsValue["basic"].Input().Properties()), 3)
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -23,13 +24,80 @@ type Enum[T enumValue] interface {
 
 type EnumSchema[S serializedEnumValue, T enumValue] struct {
 	ScalarType
-	ValidValuesMap map[T]*DisplayValue `json:"values"`
+	ValidValuesMap      map[T]*DisplayValue `json:"values"`
+	DeprecatedValuesMap map[T]*Deprecated   `json:"deprecated_values,omitempty"`
+	ValueAliasesMap     map[T][]string      `json:"value_aliases,omitempty"`
+
+	compiled *compiledEnum[T]
 }
 
 func (e EnumSchema[S, T]) ValidValues() map[T]*DisplayValue {
 	return e.ValidValuesMap
 }
 
+// DeprecatedValues returns the deprecation information of the valid values that should no longer be used.
+func (e EnumSchema[S, T]) DeprecatedValues() map[T]*Deprecated {
+	return e.DeprecatedValuesMap
+}
+
+// ValueAliases returns the alternative spellings of the valid values that are accepted when unserializing.
+func (e EnumSchema[S, T]) ValueAliases() map[T][]string {
+	return e.ValueAliasesMap
+}
+
+func (e *EnumSchema[S, T]) aliasValue(value T, aliases []string) {
+	if _, ok := e.ValidValuesMap[value]; !ok {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot add aliases to %v, it is not a valid value of the enum", value),
+		})
+	}
+	for _, alias := range aliases {
+		for validValue := range e.ValidValuesMap {
+			if fmt.Sprintf("%v", validValue) == alias {
+				panic(BadArgumentError{
+					Message: fmt.Sprintf("alias %q of %v conflicts with the valid value %v", alias, value, validValue),
+				})
+			}
+		}
+		if existing, ok := e.resolveAlias(alias); ok {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf("alias %q of %v is already used for %v", alias, value, existing),
+			})
+		}
+		if e.ValueAliasesMap == nil {
+			e.ValueAliasesMap = map[T][]string{}
+		}
+		e.ValueAliasesMap[value] = append(e.ValueAliasesMap[value], alias)
+	}
+}
+
+// resolveAlias returns the valid value the alias stands for.
+func (e EnumSchema[S, T]) resolveAlias(alias string) (T, bool) {
+	if e.compiled != nil {
+		value, ok := e.compiled.aliases[alias]
+		return value, ok
+	}
+	for value, aliases := range e.ValueAliasesMap {
+		if slices.Contains(aliases, alias) {
+			return value, true
+		}
+	}
+	var defaultValue T
+	return defaultValue, false
+}
+
+func (e *EnumSchema[S, T]) deprecateValue(value T, deprecated Deprecated) {
+	if _, ok := e.ValidValuesMap[value]; !ok {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot deprecate %v, it is not a valid value of the enum", value),
+		})
+	}
+	if e.DeprecatedValuesMap == nil {
+		e.DeprecatedValuesMap = map[T]*Deprecated{}
+	}
+	e.DeprecatedValuesMap[value] = &deprecated
+}
+
 func (e EnumSchema[S, T]) ReflectedType() reflect.Type {
 	var defaultValue T
 	return reflect.TypeOf(defaultValue)
@@ -37,16 +105,15 @@ func (e EnumSchema[S, T]) ReflectedType() reflect.Type {
 
 func (e EnumSchema[S, T]) ValidateCompatibility(typeOrData any) error {
 	// Check if it's a schema type. If it is, verify it. If not, verify it as data.
-	value := reflect.ValueOf(typeOrData)
-	if reflect.Indirect(value).Kind() != reflect.Struct {
-		return e.Validate(typeOrData) // Validate as data
+	if alias, ok := typeOrData.(string); ok {
+		if _, isAlias := e.resolveAlias(alias); isAlias {
+			return nil
+		}
 	}
-	enumField := reflect.Indirect(value).FieldByName("EnumSchema")
-	if !enumField.IsValid() {
+	validValuesMapField, isSchema := enumValidValuesField(typeOrData)
+	if !isSchema {
 		return e.Validate(typeOrData) // Validate as data
 	}
-
-	validValuesMapField := enumField.FieldByName("ValidValuesMap")
 	if !validValuesMapField.IsValid() {
 		return fmt.Errorf("failed to get values map in enum %T", e)
 	}
@@ -97,6 +164,20 @@ func (e EnumSchema[S, T]) ValidateCompatibility(typeOrData any) error {
 	return nil
 }
 
+// enumValidValuesField returns the ValidValuesMap field of the enum schema to compare with, or false if typeOrData is
+// not an enum schema and should be validated as data.
+func enumValidValuesField(typeOrData any) (reflect.Value, bool) {
+	value := reflect.Indirect(reflect.ValueOf(typeOrData))
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	enumField := value.FieldByName("EnumSchema")
+	if !enumField.IsValid() {
+		return reflect.Value{}, false
+	}
+	return enumField.FieldByName("ValidValuesMap"), true
+}
+
 func (e EnumSchema[S, T]) Validate(d any) error {
 	_, data, err := e.asType(d)
 	if err != nil {
@@ -114,9 +195,21 @@ func (e EnumSchema[S, T]) Serialize(d any) (any, error) {
 }
 
 func (e EnumSchema[S, T]) ValidateType(data T) error {
+	_, err := e.canonicalValue(data)
+	return err
+}
+
+// canonicalValue returns the value as stored in the schema, or an error if the value is not valid. Returning the
+// schema's own copy allows large inputs with many repeated enum values to share a single instance of each value.
+func (e EnumSchema[S, T]) canonicalValue(data T) (T, error) {
+	if e.compiled != nil {
+		if validValue, ok := e.compiled.values[data]; ok {
+			return validValue, nil
+		}
+	}
 	for validValue := range e.ValidValuesMap {
 		if validValue == data {
-			return nil
+			return validValue, nil
 		}
 	}
 	validValues := make([]string, len(e.ValidValuesMap))
@@ -128,12 +221,15 @@ func (e EnumSchema[S, T]) ValidateType(data T) error {
 	sort.SliceStable(validValues, func(i, j int) bool {
 		return validValues[i] < validValues[j]
 	})
-	return &ConstraintError{
+	return data, &ConstraintError{
 		Message: fmt.Sprintf(
 			"'%v' is not a valid value, must be one of: '%s'",
 			data,
 			strings.Join(validValues, "', '"),
 		),
+		Constraint: ConstraintEnum,
+		Expected:   validValues,
+		Actual:     describeValue(data),
 	}
 }
 
@@ -148,7 +244,7 @@ func (e EnumSchema[S, T]) asType(d any) (S, T, error) {
 	var unserializedDefaultValue T
 	unserializedType := reflect.TypeOf(unserializedDefaultValue)
 
-	if !dValue.CanConvert(serializedType) {
+	if !dValue.IsValid() || !dValue.CanConvert(serializedType) {
 		return serializedDefaultValue, unserializedDefaultValue, &ConstraintError{
 			Message: fmt.Sprintf("%T is not a valid data type for an %T schema.", d, serializedDefaultValue),
 		}
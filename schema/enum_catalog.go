@@ -0,0 +1,253 @@
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+)
+
+// SeverityCatalogURI is the URI of the built-in catalog of the severity levels of RFC 5424, from emergency to debug.
+const SeverityCatalogURI = "urn:arcalot:catalog:severity:v1"
+
+// EnumCatalog is a centrally published vocabulary of enum values, such as severity levels or cloud regions, that
+// plugins share so they accept and produce the same values. A catalog is identified by its URI, which names the
+// catalog and its version and doesn't need to be resolvable. Catalogs are published as JSON documents described by
+// DescribeEnumCatalog and parsed with ParseEnumCatalog.
+type EnumCatalog struct {
+	URI    string                   `json:"uri"`
+	Values map[string]*DisplayValue `json:"values"`
+}
+
+// ParseEnumCatalog parses a published catalog document.
+func ParseEnumCatalog(data []byte) (*EnumCatalog, error) {
+	catalog, err := UnserializeJSONStrict(enumCatalogSchema, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enum catalog (%w)", err)
+	}
+	return catalog.(*EnumCatalog), nil
+}
+
+// EnumCatalogRegistry holds catalogs by their URI. The catalogs are snapshots, so schemas built from them validate
+// data offline, without fetching the published catalog.
+//
+// A registry may have a parent, such as the DefaultEnumCatalogRegistry, which it falls back to. Catalogs registered in
+// a registry shadow those of its parents with the same URI, for example to use a newer snapshot than the one embedded
+// in the SDK. A registry is safe for concurrent use.
+type EnumCatalogRegistry struct {
+	lock    sync.RWMutex
+	parent  *EnumCatalogRegistry
+	entries map[string]*EnumCatalog
+}
+
+// NewEnumCatalogRegistry creates an empty registry that falls back to the parent registry, if any.
+func NewEnumCatalogRegistry(parent *EnumCatalogRegistry) *EnumCatalogRegistry {
+	return &EnumCatalogRegistry{
+		parent:  parent,
+		entries: map[string]*EnumCatalog{},
+	}
+}
+
+//go:embed catalogs/*.json
+var enumCatalogSnapshots embed.FS
+
+// DefaultEnumCatalogRegistry is the process-wide registry. It holds the snapshots of the catalogs embedded in the SDK,
+// such as the one of SeverityCatalogURI.
+var DefaultEnumCatalogRegistry = newDefaultEnumCatalogRegistry()
+
+func newDefaultEnumCatalogRegistry() *EnumCatalogRegistry {
+	registry := NewEnumCatalogRegistry(nil)
+	files, err := fs.Glob(enumCatalogSnapshots, "catalogs/*.json")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		data, err := enumCatalogSnapshots.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+		catalog, err := ParseEnumCatalog(data)
+		if err != nil {
+			panic(fmt.Errorf("embedded catalog %s: %w", file, err))
+		}
+		if err := registry.Register(catalog); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// RegisterEnumCatalog registers the catalog in the DefaultEnumCatalogRegistry.
+func RegisterEnumCatalog(catalog *EnumCatalog) error {
+	return DefaultEnumCatalogRegistry.Register(catalog)
+}
+
+// NewStringEnumSchemaFromCatalog creates a new enum of the string values of the catalog registered in the
+// DefaultEnumCatalogRegistry under the URI, see EnumCatalogRegistry.StringEnumSchema.
+func NewStringEnumSchemaFromCatalog(uri string) *StringEnumSchema {
+	return DefaultEnumCatalogRegistry.StringEnumSchema(uri)
+}
+
+// Register adds the catalog under its URI. It fails if the catalog has no URI or values, or if the URI is already
+// taken in this registry.
+func (r *EnumCatalogRegistry) Register(catalog *EnumCatalog) error {
+	if catalog == nil || catalog.URI == "" || len(catalog.Values) == 0 {
+		return BadArgumentError{Message: "enum catalogs must be registered with a URI and at least one value"}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, taken := r.entries[catalog.URI]; taken {
+		return BadArgumentError{Message: fmt.Sprintf("enum catalog %s is already registered", catalog.URI)}
+	}
+	r.entries[catalog.URI] = catalog
+	return nil
+}
+
+// Get returns the catalog registered under the URI in the registry or its parents.
+func (r *EnumCatalogRegistry) Get(uri string) (*EnumCatalog, bool) {
+	r.lock.RLock()
+	catalog, ok := r.entries[uri]
+	r.lock.RUnlock()
+	if !ok && r.parent != nil {
+		return r.parent.Get(uri)
+	}
+	return catalog, ok
+}
+
+// List returns the catalogs of the registry and its parents ordered by URI, for example to document them.
+func (r *EnumCatalogRegistry) List() []*EnumCatalog {
+	entries := map[string]*EnumCatalog{}
+	for registry := r; registry != nil; registry = registry.parent {
+		registry.lock.RLock()
+		for uri, catalog := range registry.entries {
+			if _, ok := entries[uri]; !ok {
+				entries[uri] = catalog
+			}
+		}
+		registry.lock.RUnlock()
+	}
+	result := make([]*EnumCatalog, 0, len(entries))
+	for _, catalog := range entries {
+		result = append(result, catalog)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].URI < result[j].URI })
+	return result
+}
+
+// StringEnumSchema creates a new enum of the values of the catalog registered under the URI. The values are copied,
+// so the enum doesn't change if a newer snapshot is registered later, and the URI is kept in the schema, so engines
+// and other plugins can tell which vocabulary the values come from. It panics with a BadArgumentError if no catalog
+// is registered under the URI.
+func (r *EnumCatalogRegistry) StringEnumSchema(uri string) *StringEnumSchema {
+	catalog, ok := r.Get(uri)
+	if !ok {
+		panic(BadArgumentError{Message: fmt.Sprintf("no enum catalog is registered as %s", uri)})
+	}
+	values := make(map[string]*DisplayValue, len(catalog.Values))
+	for value, display := range catalog.Values {
+		values[value] = display
+	}
+	enum := NewStringEnumSchema(values)
+	enum.CatalogValue = &uri
+	return enum
+}
+
+// Check checks that the string enums in the type that refer to a registered catalog, such as those of a schema
+// unserialized from another plugin, only accept values of the catalog. Enums referring to catalogs that are not
+// registered are not checked. The violations are returned as a ConstraintErrors of ConstraintEnum errors.
+func (r *EnumCatalogRegistry) Check(t Type) error {
+	var errs []*ConstraintError
+	_ = Walk(t, func(path []string, t Type) error {
+		enum, ok := t.(*StringEnumSchema)
+		if !ok || enum.CatalogValue == nil {
+			return nil
+		}
+		catalog, ok := r.Get(*enum.CatalogValue)
+		if !ok {
+			return nil
+		}
+		values := make([]string, 0, len(enum.ValidValuesMap))
+		for value := range enum.ValidValuesMap {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			if _, ok := catalog.Values[value]; !ok {
+				errs = append(errs, &ConstraintError{
+					Message:    fmt.Sprintf("value %q is not in the enum catalog %s", value, catalog.URI),
+					Path:       append([]string(nil), path...),
+					Constraint: ConstraintEnum,
+					Expected:   catalog.URI,
+				})
+			}
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return &ConstraintErrors{Errors: errs}
+	}
+	return nil
+}
+
+var enumCatalogSchema = NewScopeSchema(
+	NewStructMappedObjectSchema[*EnumCatalog](
+		"EnumCatalog",
+		map[string]*PropertySchema{
+			"uri": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(PointerTo("URI"), PointerTo("URI identifying the catalog and its version."), nil),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"values": NewPropertySchema(
+				NewMapSchema(NewStringSchema(nil, nil, nil), NewRefSchema("CatalogValueDisplay", nil), IntPointer(1), nil),
+				NewDisplayValue(
+					PointerTo("Values"),
+					PointerTo("The values of the catalog with their display values."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+	NewStructMappedObjectSchema[*DisplayValue](
+		"CatalogValueDisplay",
+		map[string]*PropertySchema{
+			"name": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(PointerTo("Name"), PointerTo("Short name of the value."), nil),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"description": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(PointerTo("Description"), PointerTo("Description of the value."), nil),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+)
+
+// DescribeEnumCatalog returns a scope that describes a published EnumCatalog document.
+func DescribeEnumCatalog() *ScopeSchema {
+	return enumCatalogSchema
+}
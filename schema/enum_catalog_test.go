@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestStringEnumSchemaFromCatalog(t *testing.T) {
+	severity := schema.NewStringEnumSchemaFromCatalog(schema.SeverityCatalogURI)
+	assert.Equals(t, *severity.Catalog(), schema.SeverityCatalogURI)
+	assert.Equals(t, len(severity.ValidValues()), 8)
+	assert.NoErrorR[any](t)(severity.Unserialize("warning"))
+	_, err := severity.Unserialize("fatal")
+	assert.Error(t, err)
+
+	// The catalog URI is kept when the schema is exchanged.
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("event", map[string]*schema.PropertySchema{
+		"severity": schema.NewPropertySchema(severity, nil, true, nil, nil, nil, nil, nil),
+	}))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[*schema.ScopeSchema](t)(schema.UnserializeScope(serialized))
+	property := unserialized.RootObject().Properties()["severity"]
+	assert.Equals(t, *property.Type().(*schema.StringEnumSchema).Catalog(), schema.SeverityCatalogURI)
+	assert.NoError(t, schema.DefaultEnumCatalogRegistry.Check(unserialized))
+
+	assert.PanicsContains(t, func() {
+		schema.NewStringEnumSchemaFromCatalog("urn:example:missing")
+	}, "no enum catalog is registered as urn:example:missing")
+}
+
+func TestEnumCatalogRegistry(t *testing.T) {
+	catalog := assert.NoErrorR[*schema.EnumCatalog](t)(schema.ParseEnumCatalog([]byte(`{
+		"uri": "urn:example:regions:v1",
+		"values": {"eu-west": {"name": "EU West"}, "us-east": {}}
+	}`)))
+	_, err := schema.ParseEnumCatalog([]byte(`{"uri": "urn:example:empty:v1", "values": {}}`))
+	assert.Error(t, err)
+
+	registry := schema.NewEnumCatalogRegistry(schema.DefaultEnumCatalogRegistry)
+	assert.NoError(t, registry.Register(catalog))
+	assert.Error(t, registry.Register(catalog))
+	list := registry.List()
+	assert.Equals(t, len(list), 2)
+	assert.Equals(t, list[0].URI, "urn:arcalot:catalog:severity:v1")
+	assert.Equals(t, list[1].URI, "urn:example:regions:v1")
+
+	// A newer snapshot of a catalog shadows the one of the parent.
+	assert.NoError(t, registry.Register(&schema.EnumCatalog{
+		URI:    schema.SeverityCatalogURI,
+		Values: map[string]*schema.DisplayValue{"error": nil, "info": nil},
+	}))
+	assert.Equals(t, len(registry.StringEnumSchema(schema.SeverityCatalogURI).ValidValues()), 2)
+
+	// Enums with values that are not in their catalog are reported.
+	regions := registry.StringEnumSchema("urn:example:regions:v1")
+	regions.ValidValuesMap["mars-north"] = nil
+	err = registry.Check(schema.NewListSchema(regions, nil, nil))
+	assert.Error(t, err)
+	var constraintErrors *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &constraintErrors), true)
+	assert.Equals(t, len(constraintErrors.Errors), 1)
+	assert.Equals(t, constraintErrors.Errors[0].Constraint, schema.ConstraintEnum)
+	assert.Contains(t, constraintErrors.Errors[0].Message, `"mars-north"`)
+}
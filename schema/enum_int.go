@@ -12,6 +12,19 @@ func NewIntEnumSchema(validValues map[int64]*DisplayValue, units *UnitsDefinitio
 	}
 }
 
+// DeprecateValue is a builder-pattern way of marking one of the valid values as deprecated.
+func (i *IntEnumSchema) DeprecateValue(value int64, deprecated Deprecated) *IntEnumSchema {
+	i.deprecateValue(value, deprecated)
+	return i
+}
+
+// AliasValue is a builder-pattern way of adding alternative names for one of the valid values. The aliases are
+// accepted as strings when unserializing and are replaced with the valid value.
+func (i *IntEnumSchema) AliasValue(value int64, aliases ...string) *IntEnumSchema {
+	i.aliasValue(value, aliases)
+	return i
+}
+
 // IntEnum is an enum type with integer values.
 type IntEnum interface {
 	Enum[int64]
@@ -33,6 +46,11 @@ func (i IntEnumSchema) Units() *UnitsDefinition {
 }
 
 func (i IntEnumSchema) Unserialize(data any) (any, error) {
+	if alias, ok := data.(string); ok {
+		if value, isAlias := i.resolveAlias(alias); isAlias {
+			return value, nil
+		}
+	}
 	typedData, err := intInputMapper(data, i.Units())
 	if err != nil {
 		return 0, &ConstraintError{
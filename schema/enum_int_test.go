@@ -265,3 +265,28 @@ func TestIntEnumSchemaCompatibilityValidation(t *testing.T) {
 	assert.Error(t, s1.ValidateCompatibility(S1))
 	assert.Error(t, S1.ValidateCompatibility(s1))
 }
+
+func TestIntEnumNilValue(t *testing.T) {
+	s := schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{1: nil}, nil)
+	assert.Error(t, s.Validate(nil))
+	assert.Error(t, s.ValidateCompatibility(nil))
+	_, err := s.Serialize(nil)
+	assert.Error(t, err)
+}
+
+func TestIntEnumAliases(t *testing.T) {
+	s := schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{
+		1: {NameValue: schema.PointerTo("Low")},
+		3: {NameValue: schema.PointerTo("High")},
+	}, nil).AliasValue(1, "low").AliasValue(3, "high")
+	assert.Equals(t, s.ValueAliases(), map[int64][]string{1: {"low"}, 3: {"high"}})
+	assert.Equals(t, assert.NoErrorR[int64](t)(s.UnserializeType("high")), int64(3))
+	assert.Equals(t, assert.NoErrorR[int64](t)(s.UnserializeType("1")), int64(1))
+	assert.Equals(t, assert.NoErrorR[int64](t)(s.UnserializeType(3)), int64(3))
+	_, err := s.Unserialize("medium")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		s.AliasValue(1, "3")
+	})
+}
@@ -6,7 +6,7 @@ import "fmt"
 func NewStringEnumSchema(validValues map[string]*DisplayValue) *StringEnumSchema {
 	return &StringEnumSchema{
 		TypedStringEnumSchema[string]{
-			EnumSchema[string, string]{
+			EnumSchema: EnumSchema[string, string]{
 				ValidValuesMap: validValues,
 			},
 		},
@@ -17,12 +17,38 @@ func NewStringEnumSchema(validValues map[string]*DisplayValue) *StringEnumSchema
 // Useful for external APIs that are being mapped to a schema that use string enums.
 func NewTypedStringEnumSchema[T ~string](validValues map[T]*DisplayValue) *TypedStringEnumSchema[T] {
 	return &TypedStringEnumSchema[T]{
-		EnumSchema[string, T]{
+		EnumSchema: EnumSchema[string, T]{
 			ValidValuesMap: validValues,
 		},
 	}
 }
 
+// DeprecateValue is a builder-pattern way of marking one of the valid values as deprecated.
+func (s *StringEnumSchema) DeprecateValue(value string, deprecated Deprecated) *StringEnumSchema {
+	s.deprecateValue(value, deprecated)
+	return s
+}
+
+// DeprecateValue is a builder-pattern way of marking one of the valid values as deprecated.
+func (s *TypedStringEnumSchema[T]) DeprecateValue(value T, deprecated Deprecated) *TypedStringEnumSchema[T] {
+	s.deprecateValue(value, deprecated)
+	return s
+}
+
+// AliasValue is a builder-pattern way of adding alternative spellings for one of the valid values. The aliases are
+// accepted when unserializing and are replaced with the valid value.
+func (s *StringEnumSchema) AliasValue(value string, aliases ...string) *StringEnumSchema {
+	s.aliasValue(value, aliases)
+	return s
+}
+
+// AliasValue is a builder-pattern way of adding alternative spellings for one of the valid values. The aliases are
+// accepted when unserializing and are replaced with the valid value.
+func (s *TypedStringEnumSchema[T]) AliasValue(value T, aliases ...string) *TypedStringEnumSchema[T] {
+	s.aliasValue(value, aliases)
+	return s
+}
+
 // StringEnum is an enum type with string values.
 type StringEnum interface {
 	Enum[string]
@@ -37,6 +63,13 @@ type StringEnumSchema struct {
 // element for golang enums that have an underlying string type.
 type TypedStringEnumSchema[T ~string] struct {
 	EnumSchema[string, T] `json:",inline"`
+	// CatalogValue is the URI of the enum catalog the values were taken from, see NewStringEnumSchemaFromCatalog.
+	CatalogValue *string `json:"catalog,omitempty"`
+}
+
+// Catalog returns the URI of the enum catalog the values were taken from, or nil if they were not taken from one.
+func (s TypedStringEnumSchema[T]) Catalog() *string {
+	return s.CatalogValue
 }
 
 func (s TypedStringEnumSchema[T]) TypeID() TypeID {
@@ -51,7 +84,10 @@ func (s TypedStringEnumSchema[T]) Unserialize(data any) (any, error) {
 			Message: fmt.Sprintf("'%v' (type %T) is not a valid type for a '%T' enum", data, data, typedData),
 		}
 	}
-	return typedData, s.Validate(typedData)
+	if value, isAlias := s.resolveAlias(strData); isAlias {
+		return value, nil
+	}
+	return s.canonicalValue(typedData)
 }
 
 func (s TypedStringEnumSchema[T]) UnserializeType(data any) (string, error) {
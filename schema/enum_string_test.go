@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go.arcalot.io/assert"
 	"testing"
+	"unsafe"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
 )
@@ -180,3 +181,89 @@ func TestStringEnumSchemaCompatibilityValidation(t *testing.T) {
 	assert.NoError(t, s1.ValidateCompatibility(s1Typed))
 	assert.NoError(t, s1Typed.ValidateCompatibility(s1))
 }
+
+func TestStringEnumUnserializeReturnsSchemaValue(t *testing.T) {
+	s := schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+		"small": {NameValue: schema.PointerTo("Small")},
+	})
+	var schemaValue string
+	for value := range s.ValidValues() {
+		schemaValue = value
+	}
+	input := string([]byte("small"))
+	result := assert.NoErrorR[string](t)(s.UnserializeType(input))
+	assert.Equals(t, result, "small")
+	assert.Equals(t, unsafe.StringData(result), unsafe.StringData(schemaValue))
+}
+
+func TestStringEnumNilValue(t *testing.T) {
+	s := schema.NewStringEnumSchema(map[string]*schema.DisplayValue{"a": nil})
+	assert.Error(t, s.Validate(nil))
+	assert.Error(t, s.ValidateCompatibility(nil))
+	_, err := s.Serialize(nil)
+	assert.Error(t, err)
+}
+
+func TestStringEnumAliases(t *testing.T) {
+	s := schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+		"small": schema.NewDisplayValue(schema.PointerTo("Small"), schema.PointerTo("A small size."), nil),
+		"large": schema.NewDisplayValue(schema.PointerTo("Large"), nil, nil),
+	}).AliasValue("small", "s", "sm").AliasValue("large", "l")
+	assert.Equals(t, s.ValueAliases(), map[string][]string{"small": {"s", "sm"}, "large": {"l"}})
+
+	assert.Equals(t, assert.NoErrorR[string](t)(s.UnserializeType("sm")), "small")
+	assert.Equals(t, assert.NoErrorR[string](t)(s.UnserializeType("l")), "large")
+	assert.Equals(t, assert.NoErrorR[string](t)(s.UnserializeType("large")), "large")
+	assert.NoError(t, s.ValidateCompatibility("s"))
+	// Aliases are only accepted as input, the unserialized value must be one of the valid values.
+	assert.Error(t, s.Validate("s"))
+	_, err := s.Unserialize("m")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		s.AliasValue("medium", "m")
+	})
+	assert.Panics(t, func() {
+		s.AliasValue("large", "small")
+	})
+	assert.Panics(t, func() {
+		s.AliasValue("large", "s")
+	})
+}
+
+func TestTypedStringEnumAliases(t *testing.T) {
+	type size string
+	s := schema.NewTypedStringEnumSchema[size](map[size]*schema.DisplayValue{
+		"small": {NameValue: schema.PointerTo("Small")},
+	}).AliasValue("small", "s")
+	result := assert.NoErrorR[any](t)(s.Unserialize("s"))
+	assert.Equals(t, result.(size), size("small"))
+}
+
+func TestStringEnumAliasesSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema(
+			"root",
+			map[string]*schema.PropertySchema{
+				"size": schema.NewPropertySchema(
+					schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+						"small": {NameValue: schema.PointerTo("Small")},
+					}).AliasValue("small", "s"),
+					nil,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+		),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	data := assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"size": "s"}))
+	assert.Equals(t, data.(map[string]any)["size"].(string), "small")
+}
@@ -1,27 +1,106 @@
 package schema
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 )
 
+// Constraint identifies the kind of schema rule that was violated.
+type Constraint string
+
+const (
+	// ConstraintDataType indicates that the data had the wrong type for the schema.
+	ConstraintDataType Constraint = "type"
+	// ConstraintMin indicates that the value, length, or item count was below the minimum.
+	ConstraintMin Constraint = "min"
+	// ConstraintMax indicates that the value, length, or item count was above the maximum.
+	ConstraintMax Constraint = "max"
+	// ConstraintPattern indicates that a string did not match the required pattern.
+	ConstraintPattern Constraint = "pattern"
+	// ConstraintRequired indicates that a required field was not set.
+	ConstraintRequired Constraint = "required"
+	// ConstraintConflicts indicates that two conflicting fields were set.
+	ConstraintConflicts Constraint = "conflicts"
+	// ConstraintUnknownField indicates that a field not present in the object schema was set.
+	ConstraintUnknownField Constraint = "unknown_field"
+	// ConstraintEnum indicates that the value was not one of the valid enum values.
+	ConstraintEnum Constraint = "enum"
+	// ConstraintDiscriminator indicates that the discriminator of a one-of type was missing or invalid.
+	ConstraintDiscriminator Constraint = "discriminator"
+	// ConstraintUnique indicates that a list item duplicated an earlier item.
+	ConstraintUnique Constraint = "unique"
+	// ConstraintCustom indicates that a validator registered on an object with WithValidator failed.
+	ConstraintCustom Constraint = "custom"
+	// ConstraintLimit indicates that the data exceeded an UnserializeLimits limit, such as the nesting depth.
+	ConstraintLimit Constraint = "limit"
+	// ConstraintDuplicateKey indicates that a key appeared more than once in a JSON object or YAML mapping, see
+	// DecodeOptions.
+	ConstraintDuplicateKey Constraint = "duplicate_key"
+	// ConstraintTemplate indicates that a template string had a malformed placeholder or one referring to an unknown
+	// variable, see TemplateStringSchema.
+	ConstraintTemplate Constraint = "template"
+)
+
+// ErrorCode is a stable, machine-readable identifier of the kind of an error, so that consumers can decide whether to
+// retry or how to report an error without matching its message. Codes are never renamed once published.
+type ErrorCode string
+
+const (
+	// ErrorCodeConstraint is the code of a ConstraintError, data that violates a rule of the schema.
+	ErrorCodeConstraint ErrorCode = "constraint_violation"
+	// ErrorCodeUnserialization is the code of an UnserializationError, data that could not be decoded at all.
+	ErrorCodeUnserialization ErrorCode = "unserialization_failed"
+	// ErrorCodeSchemaBuild is the code of a SchemaBuildError, an invalid schema or an invalid argument to the SDK.
+	ErrorCodeSchemaBuild ErrorCode = "schema_build_failed"
+	// ErrorCodeFunctionCall is the code of a FunctionCallError caused by an invalid call or an unexpected behavior of
+	// the function.
+	ErrorCodeFunctionCall ErrorCode = "function_call_failed"
+	// ErrorCodeFunctionReported is the code of a FunctionCallError reported by the function itself, either by
+	// returning an error or by panicking.
+	ErrorCodeFunctionReported ErrorCode = "function_reported_error"
+)
+
+// CodedError is implemented by the errors of the SDK that carry an ErrorCode. Use errors.As to find it in a chain of
+// wrapped errors, or ErrorCodeOf to get just the code.
+type CodedError interface {
+	error
+	// Code returns the stable code of the error.
+	Code() ErrorCode
+	// Pointer returns the location the error relates to as a JSON pointer (RFC 6901), or an empty string if the error
+	// doesn't relate to a specific location.
+	Pointer() string
+}
+
+// ErrorCodeOf returns the code of the first CodedError in the chain of the error, or an empty code if there is none.
+func ErrorCodeOf(err error) ErrorCode {
+	var coded CodedError
+	if errors.As(err, &coded) {
+		return coded.Code()
+	}
+	return ""
+}
+
 // ConstraintError indicates that the passed data violated one or more constraints defined in the schema.
 // The message holds the exact path of the problematic field, as well as a message explaining the error.
 // If this error is not easily understood, please open an issue on the Arcaflow plugin SDK.
+//
+// For machine consumption, Constraint, Expected, and Actual describe the failed rule, and Pointer returns the location
+// of the field in the data. These may be empty if the error does not relate to a specific rule. Strings and enum
+// values are described by their type and length in Actual instead of being stored, since they may be sensitive.
 type ConstraintError struct {
-	Message string
-	Path    []string
-	Cause   error
+	Message    string
+	Path       []string
+	Cause      error
+	Constraint Constraint
+	Expected   any
+	Actual     any
 }
 
 // Error returns the error message.
 func (c *ConstraintError) Error() string {
-	pathDescriptor := ""
-	if len(c.Path) > 0 {
-		pathDescriptor = " for '" + strings.Join(c.Path, "' -> '") + "'"
-	}
-	result := fmt.Sprintf("Validation failed%s: %s", pathDescriptor, c.Message)
+	result := fmt.Sprintf("Validation failed%s: %s", describeErrorPath(c.Path), c.Message)
 	if c.Cause != nil {
 		result += " (" + c.Cause.Error() + ")"
 	}
@@ -39,8 +118,149 @@ func (c *ConstraintError) Unwrap() error {
 	return c.Cause
 }
 
-// ConstraintErrorAddPathSegment adds a path segment if a ConstraintError is found.
+// Pointer returns the path of the problematic field as a JSON pointer (RFC 6901), for example /items/0/name. Segments
+// that don't refer to a location in the data, such as the selected one-of variant, are omitted.
+func (c *ConstraintError) Pointer() string {
+	return pathPointer(c.Path)
+}
+
+// FieldPath returns the path of the problematic field in the dotted notation used in configuration files, for example
+// steps[3].config.timeout. Like for Pointer, segments that don't refer to a location in the data are omitted.
+func (c *ConstraintError) FieldPath() string {
+	return pathBreadcrumb(c.Path)
+}
+
+// Code returns ErrorCodeConstraint. Constraint tells which rule was violated.
+func (c *ConstraintError) Code() ErrorCode {
+	return ErrorCodeConstraint
+}
+
+// pathBreadcrumb returns the error path in dotted notation, see ConstraintError.FieldPath.
+func pathBreadcrumb(path []string) string {
+	var result strings.Builder
+	for _, segment := range path {
+		switch {
+		case strings.HasPrefix(segment, "{oneof[") && strings.HasSuffix(segment, "]}"):
+			continue
+		case strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]"):
+			result.WriteString(segment)
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			result.WriteString("[" + segment[1:len(segment)-1] + "]")
+		default:
+			if result.Len() > 0 {
+				result.WriteString(".")
+			}
+			result.WriteString(segment)
+		}
+	}
+	return result.String()
+}
+
+// pathPointer returns the error path as a JSON pointer, see ConstraintError.Pointer.
+func pathPointer(path []string) string {
+	var result strings.Builder
+	for _, segment := range path {
+		switch {
+		case strings.HasPrefix(segment, "{oneof[") && strings.HasSuffix(segment, "]}"):
+			continue
+		case strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]"),
+			strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			segment = segment[1 : len(segment)-1]
+		}
+		result.WriteString("/")
+		result.WriteString(jsonPointerEscaper.Replace(segment))
+	}
+	return result.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// MarshalJSON encodes the error in a structured form so that the engine and user interfaces can locate the failed
+// field without parsing the message.
+func (c *ConstraintError) MarshalJSON() ([]byte, error) {
+	encoded := struct {
+		Message    string     `json:"message"`
+		Path       string     `json:"path"`
+		Constraint Constraint `json:"constraint,omitempty"`
+		Expected   any        `json:"expected,omitempty"`
+		Actual     any        `json:"actual,omitempty"`
+		Cause      string     `json:"cause,omitempty"`
+	}{
+		Message:    c.Message,
+		Path:       c.Pointer(),
+		Constraint: c.Constraint,
+		Expected:   c.Expected,
+		Actual:     c.Actual,
+	}
+	if c.Cause != nil {
+		encoded.Cause = c.Cause.Error()
+	}
+	return json.Marshal(encoded)
+}
+
+// describeValue returns a description of the value for ConstraintError.Actual that doesn't contain the value itself,
+// since it may be sensitive.
+func describeValue(value any) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("string of length %d", len(s))
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// describeErrorPath returns the part of an error message naming the path, or an empty string if there is no path.
+func describeErrorPath(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	return " for '" + strings.Join(path, "' -> '") + "'"
+}
+
+// UnserializationError indicates that data could not be decoded at all, such as malformed JSON, YAML, or CBOR, so
+// no schema rules could be checked. Path holds the location of the problem in the data, if known.
+type UnserializationError struct {
+	Message string
+	Path    []string
+	Cause   error
+}
+
+// Error returns the error message.
+func (u *UnserializationError) Error() string {
+	result := fmt.Sprintf("Unserialization failed%s: %s", describeErrorPath(u.Path), u.Message)
+	if u.Cause != nil {
+		result += " (" + u.Cause.Error() + ")"
+	}
+	return result
+}
+
+// Unwrap returns the underlying error if any.
+func (u *UnserializationError) Unwrap() error {
+	return u.Cause
+}
+
+// FieldPath returns the location of the problem in the data in dotted notation, see ConstraintError.FieldPath.
+func (u *UnserializationError) FieldPath() string {
+	return pathBreadcrumb(u.Path)
+}
+
+// Code returns ErrorCodeUnserialization.
+func (u *UnserializationError) Code() ErrorCode {
+	return ErrorCodeUnserialization
+}
+
+// Pointer returns the location of the problem in the data as a JSON pointer.
+func (u *UnserializationError) Pointer() string {
+	return pathPointer(u.Path)
+}
+
+// ConstraintErrorAddPathSegment adds a path segment if a ConstraintError is found. If the error is a
+// ConstraintErrors, the segment is added to each of its errors.
 func ConstraintErrorAddPathSegment(err error, pathSegment string) error {
+	if all, ok := err.(*ConstraintErrors); ok {
+		for _, c := range all.Errors {
+			c.AddPathSegment(pathSegment)
+		}
+		return all
+	}
 	var c *ConstraintError
 	if errors.As(err, &c) {
 		return c.AddPathSegment(pathSegment)
@@ -48,6 +268,33 @@ func ConstraintErrorAddPathSegment(err error, pathSegment string) error {
 	return err
 }
 
+// ConstraintErrors holds all constraint violations found in a single input. It is returned by UnserializeAll and
+// ValidateAll so that all problems can be reported at once instead of one at a time.
+type ConstraintErrors struct {
+	Errors []*ConstraintError
+}
+
+// Error returns the error message listing all violations.
+func (c *ConstraintErrors) Error() string {
+	if len(c.Errors) == 1 {
+		return c.Errors[0].Error()
+	}
+	messages := make([]string, len(c.Errors))
+	for i, err := range c.Errors {
+		messages[i] = "- " + err.Error()
+	}
+	return fmt.Sprintf("%d validation errors:\n%s", len(c.Errors), strings.Join(messages, "\n"))
+}
+
+// Unwrap returns the individual constraint errors.
+func (c *ConstraintErrors) Unwrap() []error {
+	result := make([]error, len(c.Errors))
+	for i, err := range c.Errors {
+		result[i] = err
+	}
+	return result
+}
+
 // NoSuchStepError indicates that the given step is not supported by the plugin.
 type NoSuchStepError struct {
 	Step string
@@ -60,12 +307,17 @@ func (n NoSuchStepError) Error() string {
 
 // BadArgumentError indicates that an invalid configuration was passed to a schema component. The message will
 // explain what exactly the problem is, but may not be able to locate the exact error as the schema may be manually
-// built.
+// built. Path holds the location in the schema, such as the object ID and the property ID, if known.
 type BadArgumentError struct {
 	Message string
 	Cause   error
+	Path    []string
 }
 
+// SchemaBuildError is the name of BadArgumentError in the error hierarchy. Schemas that are built incorrectly panic
+// with it, and functions of the SDK return it for invalid arguments.
+type SchemaBuildError = BadArgumentError
+
 // Error returns the error message.
 func (b BadArgumentError) Error() string {
 	result := b.Message
@@ -80,6 +332,16 @@ func (b BadArgumentError) Unwrap() error {
 	return b.Cause
 }
 
+// Code returns ErrorCodeSchemaBuild.
+func (b BadArgumentError) Code() ErrorCode {
+	return ErrorCodeSchemaBuild
+}
+
+// Pointer returns the location in the schema as a JSON pointer, such as /object/property.
+func (b BadArgumentError) Pointer() string {
+	return pathPointer(b.Path)
+}
+
 // UnitParseError indicates that it failed to parse a UnitDefinition string.
 type UnitParseError struct {
 	Message string
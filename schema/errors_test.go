@@ -1,9 +1,11 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go.arcalot.io/assert"
+	"regexp"
 	"testing"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
@@ -76,6 +78,108 @@ func TestConstraintErrorMessage(t *testing.T) {
 	)
 }
 
+func TestConstraintErrorPointer(t *testing.T) {
+	assert.Equals(t, (&schema.ConstraintError{}).Pointer(), "")
+	assert.Equals(
+		t,
+		(&schema.ConstraintError{Path: []string{"items", "[0]", "{oneof[a]}", "labels", "[a/b~c]"}}).Pointer(),
+		"/items/0/labels/a~1b~0c",
+	)
+}
+
+func TestConstraintErrorFieldPath(t *testing.T) {
+	assert.Equals(t, (&schema.ConstraintError{}).FieldPath(), "")
+	assert.Equals(
+		t,
+		(&schema.ConstraintError{Path: []string{"steps", "[3]", "{oneof[a]}", "config", "{bad key}"}}).FieldPath(),
+		"steps[3].config[bad key]",
+	)
+}
+
+func TestNestedErrorsKeepFullPath(t *testing.T) {
+	config := schema.NewObjectSchema("config", map[string]*schema.PropertySchema{
+		"timeout": schema.NewPropertySchema(
+			schema.NewStringSchema(schema.IntPointer(1), nil, nil), nil, true, nil, nil, nil, nil, nil,
+		),
+	})
+	step := schema.NewObjectSchema("step", map[string]*schema.PropertySchema{
+		"type": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"config": schema.NewPropertySchema(
+			schema.NewRefSchema("config", nil), nil, true, nil, nil, nil, nil, nil,
+		),
+	})
+	stepOneOf := schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{"step": schema.NewRefSchema("step", nil)}, "type", true,
+	)
+	root := schema.NewScopeSchema(
+		schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+			"steps": schema.NewPropertySchema(
+				schema.NewListSchema(stepOneOf, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			),
+			"named": schema.NewPropertySchema(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), stepOneOf, nil, nil),
+				nil, false, nil, nil, nil, nil, nil,
+			),
+		}),
+		config,
+		step,
+	)
+	invalidStep := map[string]any{"type": "step", "config": map[string]any{"timeout": ""}}
+	validStep := map[string]any{"type": "step", "config": map[string]any{"timeout": "1s"}}
+
+	for name, data := range map[string]map[string]any{
+		"steps[1].config.timeout": {"steps": []any{validStep, invalidStep}},
+		"named[b].config.timeout": {"named": map[string]any{"a": validStep, "b": invalidStep}},
+	} {
+		var constraintErr *schema.ConstraintError
+		_, err := root.Unserialize(data)
+		assert.Equals(t, errors.As(err, &constraintErr), true)
+		assert.Equals(t, constraintErr.FieldPath(), name)
+		// Validating the data reports the same path instead of nesting the messages of the inner errors.
+		err = root.Validate(data)
+		assert.Equals(t, errors.As(err, &constraintErr), true)
+		assert.Equals(t, constraintErr.FieldPath(), name)
+		assert.Equals(t, constraintErr.Constraint, schema.ConstraintMin)
+	}
+}
+
+func TestConstraintErrorStructured(t *testing.T) {
+	s := schema.NewListSchema(
+		schema.NewStringSchema(nil, schema.IntPointer(3), nil),
+		nil,
+		nil,
+	)
+	_, err := s.Unserialize([]any{"a", "abcd"})
+	var c *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &c), true)
+	assert.Equals(t, c.Constraint, schema.ConstraintMax)
+	assert.Equals(t, c.Expected, any(int64(3)))
+	assert.Equals(t, c.Actual, any(int64(4)))
+	assert.Equals(t, c.Pointer(), "/1")
+
+	encoded := assert.NoErrorR[[]byte](t)(json.Marshal(c))
+	assert.Equals(
+		t,
+		string(encoded),
+		`{"message":"String must be at most 3 characters, 4 given","path":"/1","constraint":"max","expected":3,"actual":4}`,
+	)
+}
+
+func TestConstraintErrorActualDescribesStrings(t *testing.T) {
+	pattern := regexp.MustCompile("^[a-z]+$")
+	_, err := schema.NewStringSchema(nil, nil, pattern).Unserialize("s3cr3t")
+	var c *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &c), true)
+	assert.Equals(t, c.Constraint, schema.ConstraintPattern)
+	assert.Equals(t, c.Actual, any("string of length 6"))
+
+	_, err = schema.NewBoolSchema().Unserialize("maybe")
+	assert.Equals(t, errors.As(err, &c), true)
+	assert.Equals(t, c.Constraint, schema.ConstraintDataType)
+	assert.Equals(t, c.Expected, any("bool"))
+	assert.Equals(t, c.Actual, any("string"))
+}
+
 func TestNoSuchStepErrorMessage(t *testing.T) {
 	assert.Equals(t, (&schema.NoSuchStepError{Step: "test"}).Error(), "No such step: test")
 }
@@ -123,3 +227,48 @@ func TestUnitParseErrorUnwrap(t *testing.T) {
 		t.Fatal("Unwrap doesn't work properly.")
 	}
 }
+
+func TestErrorCodes(t *testing.T) {
+	// Unserialization errors point to the malformed data.
+	_, err := schema.UnserializeJSONStrict(schema.NewAnySchema(), []byte(`{"a": [1, }`))
+	var unserializationErr *schema.UnserializationError
+	assert.Equals(t, errors.As(err, &unserializationErr), true)
+	assert.Equals(t, unserializationErr.Pointer(), "/a/1")
+	assert.Equals(t, unserializationErr.FieldPath(), "a.1")
+	assert.Equals(t, schema.ErrorCodeOf(err), schema.ErrorCodeUnserialization)
+
+	// Constraint errors are found in wrapped and aggregated errors.
+	_, err = schema.NewStringSchema(schema.IntPointer(2), nil, nil).Unserialize("a")
+	assert.Equals(t, schema.ErrorCodeOf(fmt.Errorf("wrapped (%w)", err)), schema.ErrorCodeConstraint)
+	all := &schema.ConstraintErrors{Errors: []*schema.ConstraintError{{Message: "test", Path: []string{"a"}}}}
+	var coded schema.CodedError
+	assert.Equals(t, errors.As(all, &coded), true)
+	assert.Equals(t, coded.Code(), schema.ErrorCodeConstraint)
+	assert.Equals(t, coded.Pointer(), "/a")
+
+	// Schema build errors locate the problem in the schema.
+	var buildErr schema.SchemaBuildError
+	assert.Equals(t, errors.As(catchPanic(func() {
+		schema.NewObjectSchema("obj", map[string]*schema.PropertySchema{
+			"a": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			),
+		}).CollectUnknownFields("a")
+	}), &buildErr), true)
+	assert.Equals(t, buildErr.Code(), schema.ErrorCodeSchemaBuild)
+	assert.Equals(t, buildErr.Pointer(), "/obj/a")
+
+	// Function call errors tell errors reported by the function from failed calls.
+	assert.Equals(t, schema.ErrorCodeOf(schema.NewFunctionCallError(err, true)), schema.ErrorCodeFunctionReported)
+	assert.Equals(t, schema.ErrorCodeOf(schema.NewFunctionCallError(err, false)), schema.ErrorCodeFunctionCall)
+
+	assert.Equals(t, schema.ErrorCodeOf(fmt.Errorf("test")), schema.ErrorCode(""))
+}
+
+func catchPanic(f func()) (err error) {
+	defer func() {
+		err = recover().(error)
+	}()
+	f()
+	return nil
+}
@@ -0,0 +1,380 @@
+package schema
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// exampleMaxDepth limits how deep recursive schemas are generated. Optional properties below it are left out.
+const exampleMaxDepth = 8
+
+// GenerateExample returns a minimal valid serialized instance of the type, for documentation or as a starting point
+// for a user. Objects only contain the properties they require, set to their default or first example if they have
+// one. Otherwise, the value is the smallest the type accepts: the lowest enum value, the first one-of variant, and
+// strings, lists and maps of the minimum length. Strings with a pattern are generated from the pattern and may not fit
+// the length limits if the pattern makes that unlikely.
+func GenerateExample(t Type) any {
+	g := &exampleGenerator{}
+	return g.generate(t, 0)
+}
+
+// GenerateRandomExample returns a random valid serialized instance of the type, such as for a fuzzing corpus. It
+// includes optional properties at random, and picks random enum values, one-of variants, numbers and lengths within
+// the limits of the type. Numbers are often the limits themselves or, for types with units, multiples of a unit, and
+// strings may contain non-ASCII characters. The same random source generates the same instance.
+func GenerateRandomExample(t Type, random *rand.Rand) any {
+	g := &exampleGenerator{random: random}
+	return g.generate(t, 0)
+}
+
+// exampleGenerator generates minimal instances if random is nil and random instances otherwise.
+type exampleGenerator struct {
+	random *rand.Rand
+}
+
+func (g *exampleGenerator) generate(t Type, depth int) any {
+	switch typed := t.(type) {
+	case Scope:
+		return g.generateObject(typed.RootObject(), depth)
+	case *RefSchema:
+		return g.generate(typed.GetObject(), depth)
+	case *ObjectSchema:
+		return g.generateObject(typed, depth)
+	case *StringSchema:
+		if typed.PatternValue != nil {
+			return g.generatePatternString(typed.PatternValue, typed.MinValue, typed.MaxValue)
+		}
+		return g.generateString(typed.MinValue, typed.MaxValue)
+	case *PatternSchema:
+		return ".*"
+	case *IntSchema:
+		return g.generateInt(typed.MinValue, typed.MaxValue, typed.UnitsValue)
+	case *FloatSchema:
+		return g.generateFloat(typed.MinValue, typed.MaxValue)
+	case *BoolSchema:
+		return g.random != nil && g.random.Intn(2) == 1
+	case *StringEnumSchema:
+		return pickEnumValue(g, typed.ValidValuesMap)
+	case *IntEnumSchema:
+		return pickEnumValue(g, typed.ValidValuesMap)
+	case untypedListSchema:
+		return g.generateList(typed, depth)
+	case untypedMapSchema:
+		return g.generateMap(typed, depth)
+	case *TupleSchema:
+		result := make([]any, len(typed.ItemsValue))
+		for i, item := range typed.ItemsValue {
+			result[i] = g.generate(item, depth+1)
+		}
+		return result
+	case *OneOfSchema[string]:
+		return generateOneOf(g, typed, depth)
+	case *OneOfSchema[int64]:
+		return generateOneOf(g, typed, depth)
+	case *AnySchema:
+		return map[string]any{}
+	default:
+		if object, ok := objectSchemaOf(t); ok {
+			return g.generateObject(object, depth)
+		}
+		panic(BadArgumentError{Message: fmt.Sprintf("cannot generate an example for %s types", t.TypeID())})
+	}
+}
+
+func (g *exampleGenerator) generateObject(o *ObjectSchema, depth int) any {
+	included := g.includedProperties(o, depth)
+	result := make(map[string]any, len(included))
+	for _, propertyID := range sortedKeys(included) {
+		property := o.PropertiesValue[propertyID]
+		if value, ok := exampleFromProperty(property, g.random == nil); ok {
+			result[propertyID] = value
+			continue
+		}
+		result[propertyID] = g.generate(property.TypeValue, depth+1)
+	}
+	if o.ValuePropertyValue != "" {
+		// Value variants of one-of types are serialized as the wrapped value.
+		return result[o.ValuePropertyValue]
+	}
+	return result
+}
+
+// includedProperties returns the properties to set: the required ones, the ones their conditions require, and, for
+// random instances, some of the optional ones.
+func (g *exampleGenerator) includedProperties(o *ObjectSchema, depth int) map[string]bool {
+	included := map[string]bool{}
+	// The properties are sorted, so the same random source includes the same properties.
+	for _, propertyID := range sortedKeys(o.PropertiesValue) {
+		property := o.PropertiesValue[propertyID]
+		optional := g.random != nil && depth < exampleMaxDepth && g.random.Intn(2) == 1
+		if property.RequiredValue || optional {
+			included[propertyID] = true
+		}
+	}
+	// Adding a property can make others required, so this repeats until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for propertyID, property := range o.PropertiesValue {
+			if included[propertyID] || !requiredByIncluded(property, included) {
+				continue
+			}
+			included[propertyID] = true
+			changed = true
+		}
+	}
+	for propertyID := range included {
+		for _, conflict := range o.PropertiesValue[propertyID].ConflictsValue {
+			if !o.PropertiesValue[conflict].RequiredValue {
+				delete(included, conflict)
+			}
+		}
+	}
+	return included
+}
+
+func requiredByIncluded(property *PropertySchema, included map[string]bool) bool {
+	for _, other := range property.RequiredIfValue {
+		if included[other] {
+			return true
+		}
+	}
+	for _, other := range property.RequiredIfNotValue {
+		if !included[other] {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleFromProperty returns the default or an example of the property if it is valid for its type. Minimal
+// instances prefer the default, random instances the examples.
+func exampleFromProperty(property *PropertySchema, preferDefault bool) (any, bool) {
+	candidates := property.ExamplesValue
+	if property.DefaultValue != nil {
+		if preferDefault {
+			candidates = append([]string{*property.DefaultValue}, candidates...)
+		} else {
+			candidates = append(candidates, *property.DefaultValue)
+		}
+	}
+	for _, candidate := range candidates {
+		var decoded any
+		if err := jsonUnmarshal(candidate, &decoded, property.TypeValue.TypeID()); err != nil {
+			continue
+		}
+		unserialized, err := property.TypeValue.Unserialize(decoded)
+		if err != nil {
+			continue
+		}
+		if serialized, err := property.TypeValue.Serialize(unserialized); err == nil {
+			return serialized, true
+		}
+	}
+	return nil, false
+}
+
+// exampleNonASCIIRunes are mixed into random strings. The string length limits count bytes, so they are only used if
+// they fit.
+var exampleNonASCIIRunes = []rune{'é', 'ß', 'Ж', '中', '€', '😀'}
+
+func (g *exampleGenerator) generateString(minLength, maxLength *int64) string {
+	length := g.length(minLength, maxLength, 0)
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	var result strings.Builder
+	for int64(result.Len()) < length {
+		switch {
+		case g.random == nil:
+			result.WriteByte('a')
+		case g.random.Intn(8) == 0:
+			r := exampleNonASCIIRunes[g.random.Intn(len(exampleNonASCIIRunes))]
+			if int64(result.Len()+utf8.RuneLen(r)) <= length {
+				result.WriteRune(r)
+			}
+		default:
+			result.WriteByte(letters[g.random.Intn(len(letters))])
+		}
+	}
+	return result.String()
+}
+
+func (g *exampleGenerator) generateInt(minValue, maxValue *int64, units *UnitsDefinition) int64 {
+	if g.random != nil {
+		if boundary, ok := pickBoundary(g, minValue, maxValue); ok {
+			return boundary
+		}
+		if units != nil && len(units.MultipliersValue) > 0 && g.random.Intn(3) == 0 {
+			multipliers := sortedKeys(units.MultipliersValue)
+			value := multipliers[g.random.Intn(len(multipliers))] * (1 + g.random.Int63n(10))
+			if (minValue == nil || value >= *minValue) && (maxValue == nil || value <= *maxValue) {
+				return value
+			}
+		}
+	}
+	switch {
+	case minValue != nil && maxValue != nil:
+		if g.random == nil || *maxValue-*minValue <= 0 {
+			return *minValue
+		}
+		return *minValue + g.random.Int63n(*maxValue-*minValue+1)
+	case minValue != nil:
+		return *minValue + g.randomInt63n(100)
+	case maxValue != nil:
+		return min(0, *maxValue) - g.randomInt63n(100)
+	default:
+		return g.randomInt63n(100)
+	}
+}
+
+func (g *exampleGenerator) generateFloat(minValue, maxValue *float64) float64 {
+	random := 0.0
+	if g.random != nil {
+		if boundary, ok := pickBoundary(g, minValue, maxValue); ok {
+			return boundary
+		}
+		random = g.random.Float64()
+	}
+	switch {
+	case minValue != nil && maxValue != nil:
+		return *minValue + random*(*maxValue-*minValue)
+	case minValue != nil:
+		return *minValue + random*100
+	case maxValue != nil:
+		return min(0, *maxValue) - random*100
+	default:
+		return random * 100
+	}
+}
+
+func (g *exampleGenerator) generateList(l untypedListSchema, depth int) []any {
+	result := make([]any, g.length(l.Min(), l.Max(), depth))
+	for i := range result {
+		result[i] = g.generate(l.itemType(), depth+1)
+	}
+	return result
+}
+
+// generateMap generates a map of the chosen length. Keys that are generated twice are skipped, so the map may be
+// shorter if the key type allows few values.
+func (g *exampleGenerator) generateMap(m untypedMapSchema, depth int) map[any]any {
+	length := g.length(m.Min(), m.Max(), depth)
+	result := make(map[any]any, length)
+	for i := int64(0); i < length; i++ {
+		key := g.generate(m.keyType(), depth+1)
+		if g.random == nil {
+			key = minimalMapKey(m.keyType(), key, i)
+		}
+		result[key] = g.generate(m.valueType(), depth+1)
+	}
+	return result
+}
+
+// minimalMapKey derives the i-th distinct key from the minimal key of the map.
+func minimalMapKey(keyType Type, key any, i int64) any {
+	switch typed := keyType.(type) {
+	case *StringEnumSchema:
+		return nthEnumValue(typed.ValidValuesMap, i)
+	case *IntEnumSchema:
+		return nthEnumValue(typed.ValidValuesMap, i)
+	}
+	switch typedKey := key.(type) {
+	case string:
+		if i == 0 {
+			return typedKey
+		}
+		return fmt.Sprintf("%s%d", typedKey, i)
+	case int64:
+		return typedKey + i
+	default:
+		return key
+	}
+}
+
+// length returns the minimum length, or a random length within the limits for random instances. Below the maximum
+// depth, random collections may be empty if the type allows it, so recursive schemas end.
+func (g *exampleGenerator) length(minLength, maxLength *int64, depth int) int64 {
+	length := int64(0)
+	if minLength != nil {
+		length = *minLength
+	}
+	if g.random == nil || depth >= exampleMaxDepth {
+		return length
+	}
+	upper := length + 3
+	if maxLength != nil && *maxLength < upper {
+		upper = *maxLength
+	}
+	if upper <= length {
+		return length
+	}
+	return length + g.random.Int63n(upper-length+1)
+}
+
+// pickBoundary returns one of the limits of a random number some of the time, since values at the limits are the
+// most likely to be handled wrong.
+func pickBoundary[T int64 | float64](g *exampleGenerator, minValue, maxValue *T) (T, bool) {
+	switch g.random.Intn(8) {
+	case 0:
+		if minValue != nil {
+			return *minValue, true
+		}
+	case 1:
+		if maxValue != nil {
+			return *maxValue, true
+		}
+	}
+	return 0, false
+}
+
+func (g *exampleGenerator) randomInt63n(n int64) int64 {
+	if g.random == nil {
+		return 0
+	}
+	return g.random.Int63n(n)
+}
+
+func pickEnumValue[T int64 | string](g *exampleGenerator, values map[T]*DisplayValue) T {
+	if g.random == nil {
+		return nthEnumValue(values, 0)
+	}
+	return nthEnumValue(values, g.random.Int63n(int64(len(values))))
+}
+
+// nthEnumValue returns the i-th lowest enum value, wrapping around if there are fewer values.
+func nthEnumValue[T int64 | string](values map[T]*DisplayValue, i int64) T {
+	keys := sortedKeys(values)
+	return keys[i%int64(len(keys))]
+}
+
+func generateOneOf[KeyType int64 | string](g *exampleGenerator, o *OneOfSchema[KeyType], depth int) any {
+	keys := sortedKeys(o.TypesValue)
+	key := keys[0]
+	switch {
+	case g.random != nil:
+		key = keys[g.random.Intn(len(keys))]
+	case o.DefaultTypeValue != nil:
+		key = *o.DefaultTypeValue
+	}
+	selectedType := o.TypesValue[key]
+	variant := g.generate(selectedType, depth+1)
+	variantData, ok := variant.(map[string]any)
+	if !ok {
+		// Value variants generate the wrapped value.
+		variantData = map[string]any{OneOfValueFieldName: variant}
+	}
+	delete(variantData, o.DiscriminatorFieldNameValue)
+	return o.serializedVariant(key, selectedType, variantData)
+}
+
+func sortedKeys[K int64 | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
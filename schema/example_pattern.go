@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// patternAttempts is the number of random strings generated for a pattern before giving up on finding one that also
+// fits the length limits.
+const patternAttempts = 20
+
+// patternMaxRepeat limits how often unbounded repetitions such as x* are repeated in random strings.
+const patternMaxRepeat = 4
+
+// generatePatternString generates a string matching the pattern and the length limits. Minimal strings take the first
+// alternative, the lowest character and the minimum number of repetitions. If no string fits the length limits, the
+// last one generated is returned.
+func (g *exampleGenerator) generatePatternString(pattern *regexp.Regexp, minLength, maxLength *int64) string {
+	parsed, err := syntax.Parse(pattern.String(), syntax.Perl)
+	if err != nil {
+		// Patterns are compiled, so they always parse.
+		panic(BadArgumentError{Message: "failed to parse pattern " + pattern.String()})
+	}
+	parsed = parsed.Simplify()
+	attempts := 1
+	if g.random != nil {
+		attempts = patternAttempts
+	}
+	result := ""
+	for i := 0; i < attempts; i++ {
+		var builder strings.Builder
+		g.generateRegexp(&builder, parsed)
+		result = builder.String()
+		length := int64(len(result))
+		if (minLength == nil || length >= *minLength) && (maxLength == nil || length <= *maxLength) &&
+			pattern.MatchString(result) {
+			break
+		}
+	}
+	return result
+}
+
+func (g *exampleGenerator) generateRegexp(builder *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && g.random != nil && g.random.Intn(2) == 1 {
+				r = unicode.SimpleFold(r)
+			}
+			builder.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		builder.WriteRune(g.pickRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		builder.WriteRune(g.pickRune([]rune{' ', '~'}))
+	case syntax.OpCapture:
+		g.generateRegexp(builder, re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		g.generateRepeat(builder, re)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.generateRegexp(builder, sub)
+		}
+	case syntax.OpAlternate:
+		sub := re.Sub[0]
+		if g.random != nil {
+			sub = re.Sub[g.random.Intn(len(re.Sub))]
+		}
+		g.generateRegexp(builder, sub)
+	default:
+		// Anchors, word boundaries and empty matches do not produce characters.
+	}
+}
+
+func (g *exampleGenerator) generateRepeat(builder *strings.Builder, re *syntax.Regexp) {
+	minRepeat, maxRepeat := 0, 1
+	switch re.Op {
+	case syntax.OpStar:
+		maxRepeat = patternMaxRepeat
+	case syntax.OpPlus:
+		minRepeat, maxRepeat = 1, patternMaxRepeat
+	case syntax.OpRepeat:
+		minRepeat, maxRepeat = re.Min, re.Max
+		if maxRepeat < 0 {
+			maxRepeat = minRepeat + patternMaxRepeat
+		}
+	}
+	count := minRepeat
+	if g.random != nil && maxRepeat > minRepeat {
+		count += g.random.Intn(maxRepeat - minRepeat + 1)
+	}
+	for i := 0; i < count; i++ {
+		g.generateRegexp(builder, re.Sub[0])
+	}
+}
+
+// pickRune picks a rune from the ranges, which hold pairs of the lowest and highest rune of each range. Random runes
+// are taken from the printable ASCII part of the ranges most of the time, so the strings stay readable.
+func (g *exampleGenerator) pickRune(ranges []rune) rune {
+	if g.random == nil {
+		return ranges[0]
+	}
+	if g.random.Intn(4) != 0 {
+		var printable []rune
+		for i := 0; i < len(ranges); i += 2 {
+			low, high := max(ranges[i], ' '), min(ranges[i+1], '~')
+			if low <= high {
+				printable = append(printable, low, high)
+			}
+		}
+		if len(printable) > 0 {
+			ranges = printable
+		}
+	}
+	i := 2 * g.random.Intn(len(ranges)/2)
+	r := ranges[i] + rune(g.random.Int63n(int64(ranges[i+1]-ranges[i])+1))
+	if !utf8.ValidRune(r) {
+		return ranges[i]
+	}
+	return r
+}
@@ -0,0 +1,166 @@
+package schema_test
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func exampleTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewObjectSchema("config", map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(3), schema.IntPointer(10), nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"version": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, regexp.MustCompile(`^\d+\.\d+$`)),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{`"1.0"`},
+			),
+			"replicas": schema.NewPropertySchema(
+				schema.NewIntSchema(schema.IntPointer(1), schema.IntPointer(5), nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				schema.PointerTo("2"),
+				nil,
+			),
+			"mode": schema.NewPropertySchema(
+				schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+					"fast": {NameValue: schema.PointerTo("Fast")},
+					"safe": {NameValue: schema.PointerTo("Safe")},
+				}),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"servers": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewRefSchema("server", nil), schema.IntPointer(1), schema.IntPointer(3)),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"labels": schema.NewPropertySchema(
+				schema.NewMapSchema(
+					schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+					schema.NewFloatSchema(schema.PointerTo(0.5), nil, nil),
+					schema.IntPointer(2),
+					nil,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"token": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil),
+				nil,
+				false,
+				[]string{"labels"},
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"target": schema.NewPropertySchema(
+				schema.NewOneOfStringSchema[any](map[string]schema.Object{
+					"server": schema.NewRefSchema("server", nil),
+					"name":   schema.NewOneOfValueVariant("name", schema.NewStringSchema(nil, nil, nil)),
+				}, "kind", false),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"position": schema.NewPropertySchema(
+				schema.NewTupleSchema(schema.NewIntSchema(nil, schema.IntPointer(-3), nil), schema.NewBoolSchema()),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		schema.NewObjectSchema("server", map[string]*schema.PropertySchema{
+			"host": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"backup": schema.NewPropertySchema(
+				schema.NewRefSchema("server", nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+	)
+}
+
+func TestGenerateExample(t *testing.T) {
+	scope := exampleTestScope()
+	example := schema.GenerateExample(scope)
+	assert.Equals(t, example, any(map[string]any{
+		"name":    "aaa",
+		"version": "1.0",
+		"mode":    "fast",
+		"servers": []any{map[string]any{"host": ""}},
+	}))
+	assert.NoErrorR[any](t)(scope.Unserialize(example))
+
+	assert.Equals(t, schema.GenerateExample(scope.Properties()["labels"].Type()), any(map[any]any{
+		"a":  0.5,
+		"a1": 0.5,
+	}))
+	assert.Equals(t, schema.GenerateExample(scope.Properties()["target"].Type()), any(map[string]any{
+		"kind":  "name",
+		"value": "",
+	}))
+}
+
+func TestGenerateRandomExample(t *testing.T) {
+	scope := exampleTestScope()
+	random := rand.New(rand.NewSource(1)) //nolint:gosec // Examples do not need a secure source.
+	for i := 0; i < 100; i++ {
+		example := schema.GenerateRandomExample(scope, random)
+		assert.NoErrorR[any](t)(scope.Unserialize(example))
+	}
+	assert.Equals(
+		t,
+		schema.GenerateRandomExample(scope, rand.New(rand.NewSource(2))), //nolint:gosec // Not security relevant.
+		schema.GenerateRandomExample(scope, rand.New(rand.NewSource(2))), //nolint:gosec // Not security relevant.
+	)
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -58,7 +59,11 @@ func (f FloatSchema) Unserialize(data any) (any, error) {
 	if err != nil {
 		return 0, err
 	}
-	return unserialized, f.Validate(unserialized)
+	if _, ok := data.(float64); ok {
+		// Returning the data as is avoids boxing the number into a new interface value.
+		return data, f.ValidateType(unserialized)
+	}
+	return unserialized, f.ValidateType(unserialized)
 }
 
 func (f FloatSchema) UnserializeType(data any) (float64, error) {
@@ -101,30 +106,43 @@ func (f FloatSchema) ValidateCompatibility(typeOrData any) error {
 }
 
 func (f FloatSchema) Validate(d any) error {
-	_, err := f.Serialize(d)
-	return err
+	data, err := asFloat(d)
+	if err != nil {
+		return err
+	}
+	return f.ValidateType(data)
 }
 
 func (f FloatSchema) ValidateType(data float64) error {
-	return f.Validate(data)
+	if f.MinValue != nil && data < *f.MinValue {
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must be at least %f", *f.MinValue),
+			Constraint: ConstraintMin,
+			Expected:   *f.MinValue,
+			Actual:     data,
+		}
+	}
+	if f.MaxValue != nil && data > *f.MaxValue {
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must be at most %f", *f.MaxValue),
+			Constraint: ConstraintMax,
+			Expected:   *f.MaxValue,
+			Actual:     data,
+		}
+	}
+	return nil
 }
 
 func (f FloatSchema) Serialize(d any) (any, error) {
+	if data, ok := d.(float64); ok {
+		// Returning the data as is avoids boxing the number into a new interface value.
+		return d, f.ValidateType(data)
+	}
 	data, err := asFloat(d)
 	if err != nil {
 		return data, err
 	}
-	if f.MinValue != nil && data < *f.MinValue {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("Must be at least %f", *f.MinValue),
-		}
-	}
-	if f.MaxValue != nil && data > *f.MaxValue {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("Must be at most %f", *f.MaxValue),
-		}
-	}
-	return data, nil
+	return data, f.ValidateType(data)
 }
 
 func asFloat(d any) (float64, error) {
@@ -133,9 +151,12 @@ func asFloat(d any) (float64, error) {
 		var i float64
 		intType := reflect.TypeOf(i)
 		dValue := reflect.ValueOf(d)
-		if !dValue.CanConvert(intType) {
+		if !dValue.IsValid() || !dValue.CanConvert(intType) {
 			return 0, &ConstraintError{
-				Message: fmt.Sprintf("%T is not a valid data type for a float schema.", d),
+				Message:    fmt.Sprintf("%T is not a valid data type for a float schema.", d),
+				Constraint: ConstraintDataType,
+				Expected:   "float",
+				Actual:     fmt.Sprintf("%T", d),
 			}
 		}
 		data = dValue.Convert(intType).Float()
@@ -154,6 +175,8 @@ func floatInputMapper(data any, u *UnitsDefinition) (float64, error) {
 			return (*u).ParseFloat(v)
 		}
 		return strconv.ParseFloat(v, 64)
+	case json.Number:
+		return v.Float64()
 	case int64:
 		return float64(v), nil
 	case uint64:
@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"go.arcalot.io/assert"
 	"testing"
 
@@ -314,3 +315,26 @@ func TestFloatCompatibilityValidation(t *testing.T) {
 	assert.Error(t, s1.ValidateCompatibility(schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{}, nil)))
 
 }
+
+func TestFloatNilValue(t *testing.T) {
+	assert.Error(t, schema.NewFloatSchema(nil, nil, nil).Validate(nil))
+	_, err := schema.NewFloatSchema(nil, nil, nil).Serialize(nil)
+	assert.Error(t, err)
+}
+
+func TestFloatJSONNumber(t *testing.T) {
+	floatSchema := schema.NewFloatSchema(nil, nil, nil)
+	assert.Equals(t, assert.NoErrorR[float64](t)(floatSchema.UnserializeType(json.Number("1.5e3"))), 1500.0)
+	_, err := floatSchema.UnserializeType(json.Number("1e400"))
+	assert.Error(t, err)
+}
+
+func TestFloatAllocations(t *testing.T) {
+	s := schema.NewFloatSchema(schema.PointerTo(0.0), nil, nil)
+	var data any = 1234.5678
+	assert.Equals(t, testing.AllocsPerRun(100, func() {
+		_, _ = s.Serialize(data)
+		_, _ = s.Unserialize(data)
+		_ = s.Validate(data)
+	}), float64(0))
+}
@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FrozenSchema is an immutable view of a plugin schema. It holds its own copy of the schema, so later changes to the
+// schema it was frozen from do not affect it, and it can be shared between goroutines without locking. Deriving a new
+// view with WithStep or WithoutStep copies only the step map; the unchanged steps are shared with the original view.
+//
+// The steps returned by the view are shared and must not be modified. Use Thaw to get a copy that can be changed.
+type FrozenSchema struct {
+	steps map[string]*StepSchema
+	pool  *SchemaPool
+}
+
+// SchemaPool deduplicates the scopes of frozen schemas. Scopes with the same content, such as the inputs of the same
+// step in different versions of a plugin, are stored once and shared by all schemas frozen with the pool. The pool
+// keeps every scope it has seen for its lifetime. A pool is safe for concurrent use.
+type SchemaPool struct {
+	lock   sync.Mutex
+	scopes map[string]*ScopeSchema
+}
+
+// NewSchemaPool creates an empty pool to freeze schemas with.
+func NewSchemaPool() *SchemaPool {
+	return &SchemaPool{
+		scopes: map[string]*ScopeSchema{},
+	}
+}
+
+// Freeze creates an immutable view of the schema, such as a CallableSchema or a SchemaSchema. Identical scopes within
+// the schema are shared. Use a SchemaPool to share them across schemas.
+func Freeze(s interface{ SelfSerialize() (any, error) }) (*FrozenSchema, error) {
+	return NewSchemaPool().Freeze(s)
+}
+
+// Freeze creates an immutable view of the schema, sharing its scopes with the other schemas frozen with the pool.
+func (p *SchemaPool) Freeze(s interface{ SelfSerialize() (any, error) }) (*FrozenSchema, error) {
+	serialized, err := s.SelfSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize schema for freezing (%w)", err)
+	}
+	unserialized, err := UnserializeSchema(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy schema for freezing (%w)", err)
+	}
+	for _, step := range unserialized.StepsValue {
+		if err := p.share(step); err != nil {
+			return nil, err
+		}
+	}
+	return &FrozenSchema{unserialized.StepsValue, p}, nil
+}
+
+// share replaces the scopes of the step with the identical ones in the pool, adding the ones that are not in it yet.
+func (p *SchemaPool) share(step *StepSchema) error {
+	var err error
+	if step.InputValue, err = p.scope(step.InputValue); err != nil {
+		return err
+	}
+	for _, output := range step.OutputsValue {
+		if output.SchemaValue, err = p.scope(output.SchemaValue); err != nil {
+			return err
+		}
+	}
+	for _, signals := range []map[string]*SignalSchema{step.SignalHandlersValue, step.SignalEmittersValue} {
+		for _, signal := range signals {
+			if signal.DataSchemaValue, err = p.scope(signal.DataSchemaValue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *SchemaPool) scope(scope Scope) (Scope, error) {
+	scopeSchema, ok := scope.(*ScopeSchema)
+	if !ok {
+		return scope, nil
+	}
+	hash, err := Hash(scopeSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash scope for sharing (%w)", err)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if existing, ok := p.scopes[hash]; ok {
+		return existing, nil
+	}
+	if err := prepareForSharing(scopeSchema); err != nil {
+		return nil, err
+	}
+	scopeSchema.Lock()
+	p.scopes[hash] = scopeSchema
+	return scopeSchema, nil
+}
+
+// prepareForSharing fills the caches the types would otherwise fill on first use, so the first values read by the
+// users of the shared scope don't pay for building them.
+func prepareForSharing(scope *ScopeSchema) error {
+	return Walk(scope, func(_ []string, t Type) error {
+		if withUnits, ok := t.(interface{ Units() *UnitsDefinition }); ok && withUnits.Units() != nil {
+			withUnits.Units().getCache()
+		}
+		return nil
+	})
+}
+
+// Steps returns the steps of the schema. The map is a copy, but the steps are shared and must not be modified.
+func (f *FrozenSchema) Steps() map[string]Step {
+	result := make(map[string]Step, len(f.steps))
+	for id, step := range f.steps {
+		result[id] = step
+	}
+	return result
+}
+
+// Step returns the step with the given ID, if the schema has it. The step is shared and must not be modified.
+func (f *FrozenSchema) Step(id string) (Step, bool) {
+	step, ok := f.steps[id]
+	return step, ok
+}
+
+// SelfSerialize serializes the schema.
+func (f *FrozenSchema) SelfSerialize() (any, error) {
+	return (&SchemaSchema{f.steps}).SelfSerialize()
+}
+
+// WithStep returns a new view with the step added, or replaced if the schema already has a step with the same ID. The
+// step is copied, and the other steps are shared with this view.
+func (f *FrozenSchema) WithStep(step Step) (*FrozenSchema, error) {
+	stepSchema, ok := step.(*StepSchema)
+	if !ok {
+		callable, isCallable := step.(CallableStep)
+		if !isCallable {
+			return nil, BadArgumentError{Message: fmt.Sprintf("unsupported step type for freezing: %T", step)}
+		}
+		stepSchema = callable.ToStepSchema()
+	}
+	frozen, err := f.pool.Freeze(&SchemaSchema{map[string]*StepSchema{step.ID(): stepSchema}})
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[string]*StepSchema, len(f.steps)+1)
+	for id, existing := range f.steps {
+		steps[id] = existing
+	}
+	steps[step.ID()] = frozen.steps[step.ID()]
+	return &FrozenSchema{steps, f.pool}, nil
+}
+
+// WithoutStep returns a new view without the step with the given ID. The other steps are shared with this view.
+func (f *FrozenSchema) WithoutStep(id string) *FrozenSchema {
+	steps := make(map[string]*StepSchema, len(f.steps))
+	for stepID, step := range f.steps {
+		if stepID != id {
+			steps[stepID] = step
+		}
+	}
+	return &FrozenSchema{steps, f.pool}
+}
+
+// Thaw returns a copy of the schema that can be modified without affecting the view.
+func (f *FrozenSchema) Thaw() (*SchemaSchema, error) {
+	serialized, err := f.SelfSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize frozen schema (%w)", err)
+	}
+	return UnserializeSchema(serialized)
+}
@@ -0,0 +1,104 @@
+package schema_test
+
+import (
+	"sync"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestFreeze(t *testing.T) {
+	callable := engineTestSchema()
+	frozen := assert.NoErrorR[*schema.FrozenSchema](t)(schema.Freeze(callable))
+	assert.Equals(
+		t,
+		assert.NoErrorR[string](t)(schema.HashSchema(frozen)),
+		assert.NoErrorR[string](t)(schema.HashSchema(callable)),
+	)
+
+	// Both steps have the same input, which is stored once.
+	basic, _ := frozen.Step("basic")
+	modern, _ := frozen.Step("modern")
+	assert.Equals(t, basic.Input() == modern.Input(), true)
+
+	// Changes to the original schema do not affect the view.
+	callable.StepsValue["basic"].Input().Properties()["name"].RequiredValue = false
+	assert.Equals(t, basic.Input().Properties()["name"].Required(), true)
+}
+
+func TestSchemaPoolSharesScopes(t *testing.T) {
+	pool := schema.NewSchemaPool()
+	first := assert.NoErrorR[*schema.FrozenSchema](t)(pool.Freeze(engineTestSchema()))
+	second := assert.NoErrorR[*schema.FrozenSchema](t)(pool.Freeze(schemaTestSchema))
+	third := assert.NoErrorR[*schema.FrozenSchema](t)(pool.Freeze(engineTestSchema()))
+
+	firstStep, _ := first.Step("basic")
+	thirdStep, _ := third.Step("basic")
+	assert.Equals(t, firstStep.Input() == thirdStep.Input(), true)
+	assert.Equals(t, firstStep.Outputs()["success"].Schema() == thirdStep.Outputs()["success"].Schema(), true)
+	secondStep, _ := second.Step("hello")
+	assert.Equals(t, firstStep.Input() == secondStep.Input(), false)
+}
+
+func TestFrozenSchemaDerive(t *testing.T) {
+	frozen := assert.NoErrorR[*schema.FrozenSchema](t)(schema.Freeze(engineTestSchema()))
+	basic, _ := frozen.Step("basic")
+
+	derived := assert.NoErrorR[*schema.FrozenSchema](t)(frozen.WithStep(testStepSchema))
+	assert.Equals(t, len(derived.Steps()), 3)
+	assert.Equals(t, len(frozen.Steps()), 2)
+	derivedBasic, _ := derived.Step("basic")
+	assert.Equals(t, derivedBasic == basic, true)
+	_, ok := frozen.Step("hello")
+	assert.Equals(t, ok, false)
+
+	removed := derived.WithoutStep("modern")
+	assert.Equals(t, len(removed.Steps()), 2)
+	_, ok = removed.Step("modern")
+	assert.Equals(t, ok, false)
+	_, ok = derived.Step("modern")
+	assert.Equals(t, ok, true)
+
+	thawed := assert.NoErrorR[*schema.SchemaSchema](t)(frozen.Thaw())
+	delete(thawed.StepsValue, "basic")
+	assert.Equals(t, len(frozen.Steps()), 2)
+}
+
+func TestFrozenSchemaConcurrentUse(t *testing.T) {
+	step := schema.NewStepSchema(
+		"wait",
+		schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+			"timeout": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, schema.UnitDurationNanoseconds),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		})),
+		map[string]*schema.StepOutputSchema{},
+		nil,
+		nil,
+		nil,
+	)
+	frozen := assert.NoErrorR[*schema.FrozenSchema](t)(schema.Freeze(
+		schema.NewSchema(map[string]*schema.StepSchema{"wait": step}).(*schema.SchemaSchema),
+	))
+	wait, _ := frozen.Step("wait")
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := wait.Input().Unserialize(map[string]any{"timeout": "1m30s"})
+			assert.NoError(t, err)
+			assert.Equals(t, result.(map[string]any)["timeout"], any(int64(90000000000)))
+		}()
+	}
+	wg.Wait()
+}
@@ -40,6 +40,24 @@ func (e *FunctionCallError) Error() string {
 	return e.SourceError.Error()
 }
 
+// Unwrap returns the error of the function or the call.
+func (e *FunctionCallError) Unwrap() error {
+	return e.SourceError
+}
+
+// Code returns ErrorCodeFunctionReported for errors reported by the function and ErrorCodeFunctionCall for all others.
+func (e *FunctionCallError) Code() ErrorCode {
+	if e.IsFunctionReportedError {
+		return ErrorCodeFunctionReported
+	}
+	return ErrorCodeFunctionCall
+}
+
+// Pointer returns an empty string, since the call as a whole failed.
+func (e *FunctionCallError) Pointer() string {
+	return ""
+}
+
 const errorType = "error"
 
 // NewCallableFunction creates a CallableFunction schema type for the strictly typed function.
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// FuzzUnserialize is a fuzz target for unserializing untrusted data with the type. It decodes the data as CBOR, the
+// encoding step inputs arrive in over ATP, and unserializes it. Rejecting the data is fine, but data the type accepts
+// must survive a round trip: it has to validate, serialize, and unserialize again. FuzzUnserialize returns an error if
+// the round trip fails. Panics are not recovered, so the fuzzing engine reports them with their stack trace.
+//
+// Plugins can fuzz their input schema with a fuzz test seeded from FuzzCorpus:
+//
+//	func FuzzInput(f *testing.F) {
+//		for _, seed := range schema.FuzzCorpus(inputSchema, 20, 1) {
+//			f.Add(seed)
+//		}
+//		f.Fuzz(func(t *testing.T, data []byte) {
+//			if err := schema.FuzzUnserialize(inputSchema, data); err != nil {
+//				t.Fatal(err)
+//			}
+//		})
+//	}
+func FuzzUnserialize(t Type, data []byte) error {
+	var decoded any
+	if err := cbor.Unmarshal(data, &decoded); err != nil {
+		// Invalid CBOR never reaches the schema.
+		return nil //nolint:nilerr // Not a failure of the schema.
+	}
+	unserialized, err := t.Unserialize(decoded)
+	if err != nil {
+		return nil //nolint:nilerr // Rejecting the data is the expected outcome for most inputs.
+	}
+	if err := t.Validate(unserialized); err != nil {
+		return fmt.Errorf("accepted data fails validation after unserialization (%w)", err)
+	}
+	serialized, err := t.Serialize(unserialized)
+	if err != nil {
+		return fmt.Errorf("accepted data fails to serialize (%w)", err)
+	}
+	if _, err := t.Unserialize(serialized); err != nil {
+		return fmt.Errorf("serialized data fails to unserialize again (%w)", err)
+	}
+	return nil
+}
+
+// FuzzCorpus returns seeds for fuzzing the type with FuzzUnserialize: the minimal example of the type followed by the
+// given number of random examples generated from the seed, all encoded as deterministic CBOR.
+func FuzzCorpus(t Type, count int, seed int64) [][]byte {
+	encoder, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(BadArgumentError{Message: fmt.Sprintf("failed to create CBOR encoder (%v)", err)})
+	}
+	random := rand.New(rand.NewSource(seed)) //nolint:gosec // Fuzzing seeds do not need a secure source.
+	examples := []any{GenerateExample(t)}
+	for i := 0; i < count; i++ {
+		examples = append(examples, GenerateRandomExample(t, random))
+	}
+	corpus := make([][]byte, 0, len(examples))
+	for _, example := range examples {
+		encoded, err := encoder.Marshal(example)
+		if err != nil {
+			panic(BadArgumentError{Message: fmt.Sprintf("failed to encode fuzzing seed (%v)", err)})
+		}
+		corpus = append(corpus, encoded)
+	}
+	return corpus
+}
@@ -0,0 +1,34 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func FuzzUnserialize(f *testing.F) {
+	scope := exampleTestScope()
+	for _, seed := range schema.FuzzCorpus(scope, 20, 1) {
+		f.Add(seed)
+	}
+	f.Add([]byte{0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := schema.FuzzUnserialize(scope, data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestFuzzCorpus(t *testing.T) {
+	scope := exampleTestScope()
+	corpus := schema.FuzzCorpus(scope, 5, 1)
+	assert.Equals(t, len(corpus), 6)
+	for _, seed := range corpus {
+		var decoded any
+		assert.NoError(t, cbor.Unmarshal(seed, &decoded))
+		assert.NoErrorR[any](t)(scope.Unserialize(decoded))
+	}
+	assert.Equals(t, schema.FuzzCorpus(scope, 5, 2), schema.FuzzCorpus(scope, 5, 2))
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Hash returns a stable content hash of a type, such as a scope used as a step input. Two types have the same hash if
+// their serialized definitions are the same, regardless of map iteration order or the process that built them, so the
+// hash can be used as a cache key or to detect changes between plugin versions. Display values are part of the
+// definition, so changing a description changes the hash.
+func Hash(t Type) (string, error) {
+	serialized, err := valueType.Serialize(t)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize %s schema for hashing (%w)", t.TypeID(), err)
+	}
+	return hashSerialized(serialized)
+}
+
+// HashSchema returns a stable content hash of a whole plugin schema, such as a CallableSchema or a SchemaSchema, with
+// the same guarantees as Hash. A plugin schema and the schema unserialized from its serialized form have the same hash.
+func HashSchema(s interface{ SelfSerialize() (any, error) }) (string, error) {
+	serialized, err := s.SelfSerialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize plugin schema for hashing (%w)", err)
+	}
+	return hashSerialized(serialized)
+}
+
+func hashSerialized(serialized any) (string, error) {
+	canonical, err := MarshalCanonical(serialized)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schema for hashing (%w)", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,49 @@
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func hashTestScope(maxLength int) *schema.ScopeSchema {
+	properties := map[string]*schema.PropertySchema{}
+	for i := 0; i < 20; i++ {
+		properties[fmt.Sprintf("field%d", i)] = schema.NewPropertySchema(
+			schema.NewStringSchema(nil, schema.IntPointer(maxLength), nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+	}
+	return schema.NewScopeSchema(schema.NewObjectSchema("input", properties))
+}
+
+func TestHash(t *testing.T) {
+	hash := assert.NoErrorR[string](t)(schema.Hash(hashTestScope(10)))
+	assert.Equals(t, len(hash), 64)
+	for i := 0; i < 10; i++ {
+		assert.Equals(t, assert.NoErrorR[string](t)(schema.Hash(hashTestScope(10))), hash)
+	}
+	assert.Equals(t, assert.NoErrorR[string](t)(schema.Hash(hashTestScope(11))) != hash, true)
+
+	stringHash := assert.NoErrorR[string](t)(schema.Hash(schema.NewStringSchema(nil, nil, nil)))
+	intHash := assert.NoErrorR[string](t)(schema.Hash(schema.NewIntSchema(nil, nil, nil)))
+	assert.Equals(t, stringHash != intHash, true)
+}
+
+func TestHashSchema(t *testing.T) {
+	pluginSchema := schema.NewCallableSchema(testStepSchema)
+	hash := assert.NoErrorR[string](t)(schema.HashSchema(pluginSchema))
+
+	// The hash survives a round trip through the serialized form.
+	serialized := assert.NoErrorR[any](t)(pluginSchema.SelfSerialize())
+	unserialized := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(serialized))
+	assert.Equals(t, assert.NoErrorR[string](t)(schema.HashSchema(unserialized)), hash)
+}
@@ -6,4 +6,7 @@ type Input struct {
 	ID string
 	// The data being input into the step/signal/other
 	InputData any
+	// Labels identify who the step runs for, such as the tenant or namespace of the workflow owner. They are only
+	// used when starting steps, and are available to the step and its signal handlers through LabelsFromContext.
+	Labels map[string]string
 }
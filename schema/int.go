@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
@@ -31,6 +33,16 @@ type IntSchema struct {
 	MinValue   *int64           `json:"min"`
 	MaxValue   *int64           `json:"max"`
 	UnitsValue *UnitsDefinition `json:"units"`
+	// SerializeUnitsValue serializes values as strings with units instead of numbers in the base unit.
+	SerializeUnitsValue bool `json:"serialize_units,omitempty"`
+}
+
+// SerializeWithUnits is a builder-pattern way of serializing values as strings with units, such as "1m30s", instead
+// of numbers in the base unit. Unserialize accepts both forms. Negative values and schemas without units are
+// serialized as numbers.
+func (i *IntSchema) SerializeWithUnits() *IntSchema {
+	i.SerializeUnitsValue = true
+	return i
 }
 
 func (i IntSchema) ReflectedType() reflect.Type {
@@ -58,7 +70,11 @@ func (i IntSchema) Unserialize(data any) (any, error) {
 	if err != nil {
 		return 0, err
 	}
-	return unserialized, i.Validate(unserialized)
+	if _, ok := data.(int64); ok {
+		// Returning the data as is avoids boxing the number into a new interface value.
+		return data, i.ValidateType(unserialized)
+	}
+	return unserialized, i.ValidateType(unserialized)
 }
 
 func (i IntSchema) Serialize(d any) (any, error) {
@@ -66,28 +82,38 @@ func (i IntSchema) Serialize(d any) (any, error) {
 	if err != nil {
 		return data, err
 	}
-	if i.MinValue != nil && data < *i.MinValue {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("Must be at least %d", *i.MinValue),
-		}
+	if err := i.ValidateType(data); err != nil {
+		return data, err
 	}
-	if i.MaxValue != nil && data > *i.MaxValue {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("Must be at most %d", *i.MaxValue),
-		}
+	if i.SerializeUnitsValue && i.UnitsValue != nil && data >= 0 {
+		return i.UnitsValue.FormatShortInt(data), nil
+	}
+	if _, ok := d.(int64); ok {
+		// Returning the data as is avoids boxing the number into a new interface value.
+		return d, nil
 	}
 	return data, nil
 }
 
+// SerializeInt validates and returns a number without converting it to and from an interface value, which avoids
+// the allocations of Serialize for plugins serializing many values. Unlike Serialize, it doesn't format the number
+// with units if SerializeWithUnits is set.
+func (i IntSchema) SerializeInt(data int64) (int64, error) {
+	return data, i.ValidateType(data)
+}
+
 func asInt(d any) (int64, error) {
 	data, ok := d.(int64)
 	if !ok {
 		var i int64
 		intType := reflect.TypeOf(i)
 		dValue := reflect.ValueOf(d)
-		if !dValue.CanConvert(intType) {
+		if !dValue.IsValid() || !dValue.CanConvert(intType) {
 			return 0, &ConstraintError{
-				Message: fmt.Sprintf("%T is not a valid data type for an int schema.", d),
+				Message:    fmt.Sprintf("%T is not a valid data type for an int schema.", d),
+				Constraint: ConstraintDataType,
+				Expected:   "int",
+				Actual:     fmt.Sprintf("%T", d),
 			}
 		}
 		data = dValue.Convert(intType).Int()
@@ -132,8 +158,11 @@ func (i IntSchema) ValidateCompatibility(typeOrData any) error {
 }
 
 func (i IntSchema) Validate(d any) error {
-	_, err := i.Serialize(d)
-	return err
+	data, err := asInt(d)
+	if err != nil {
+		return err
+	}
+	return i.ValidateType(data)
 }
 
 func (i IntSchema) UnserializeType(data any) (int64, error) {
@@ -145,7 +174,23 @@ func (i IntSchema) UnserializeType(data any) (int64, error) {
 }
 
 func (i IntSchema) ValidateType(data int64) error {
-	return i.Validate(data)
+	if i.MinValue != nil && data < *i.MinValue {
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must be at least %d", *i.MinValue),
+			Constraint: ConstraintMin,
+			Expected:   *i.MinValue,
+			Actual:     data,
+		}
+	}
+	if i.MaxValue != nil && data > *i.MaxValue {
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must be at most %d", *i.MaxValue),
+			Constraint: ConstraintMax,
+			Expected:   *i.MaxValue,
+			Actual:     data,
+		}
+	}
+	return nil
 }
 
 func (i IntSchema) SerializeType(data int64) (any, error) {
@@ -160,6 +205,8 @@ func intInputMapper(data any, u *UnitsDefinition) (int64, error) {
 			return (*u).ParseInt(v)
 		}
 		return strconv.ParseInt(v, 10, 64)
+	case json.Number:
+		return jsonNumberToInt(v)
 	case int64:
 		return v, nil
 	case uint64:
@@ -170,6 +217,9 @@ func intInputMapper(data any, u *UnitsDefinition) (int64, error) {
 	case int:
 		return int64(v), nil
 	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return 0, fmt.Errorf("number is too large for an int64: %d", v)
+		}
 		return int64(v), nil
 	case int32:
 		return int64(v), nil
@@ -184,17 +234,9 @@ func intInputMapper(data any, u *UnitsDefinition) (int64, error) {
 	case uint8:
 		return int64(v), nil
 	case float64:
-		i := int64(v)
-		if v != float64(i) {
-			return 0, fmt.Errorf("float64 number %f cannot be converted to an int64", v)
-		}
-		return i, nil
+		return floatToInt(v, "float64")
 	case float32:
-		i := int64(v)
-		if v != float32(i) {
-			return 0, fmt.Errorf("float32 number %f cannot be converted to an int64", v)
-		}
-		return i, nil
+		return floatToInt(float64(v), "float32")
 	case bool:
 		if v {
 			return 1, nil
@@ -204,3 +246,34 @@ func intInputMapper(data any, u *UnitsDefinition) (int64, error) {
 		return 0, fmt.Errorf("%T cannot be converted to an int64", data)
 	}
 }
+
+// floatToInt converts a whole number float to an int64. Converting floats outside the int64 range is undefined in Go,
+// so the range is checked first.
+func floatToInt(v float64, typeName string) (int64, error) {
+	if math.IsNaN(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, fmt.Errorf("%s number %f is out of the int64 range", typeName, v)
+	}
+	i := int64(v)
+	if v != float64(i) {
+		return 0, fmt.Errorf("%s number %f cannot be converted to an int64", typeName, v)
+	}
+	return i, nil
+}
+
+// jsonNumberToInt converts a number decoded by a json.Decoder with UseNumber. Unlike a float64, it holds integers of
+// any size exactly, so large int64 values don't lose precision.
+func jsonNumberToInt(v json.Number) (int64, error) {
+	i, err := v.Int64()
+	if err == nil {
+		return i, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("number %s is out of the int64 range", v)
+	}
+	// Whole numbers may also be written as floats, such as 1e3 or 2.0.
+	f, err := v.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q (%w)", v.String(), err)
+	}
+	return floatToInt(f, "JSON")
+}
@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"go.arcalot.io/assert"
 	"math"
 	"testing"
@@ -277,3 +278,97 @@ func TestIntCompatibilityValidation(t *testing.T) {
 	assert.Error(t, s1.ValidateCompatibility([]string{}))
 	assert.Error(t, s1.ValidateCompatibility(map[string]any{}))
 }
+
+func TestIntNilValue(t *testing.T) {
+	assert.Error(t, schema.NewIntSchema(nil, nil, nil).Validate(nil))
+	_, err := schema.NewIntSchema(nil, nil, nil).Serialize(nil)
+	assert.Error(t, err)
+}
+
+func TestIntJSONNumber(t *testing.T) {
+	intSchema := schema.NewIntSchema(nil, nil, nil)
+	// 2^53 + 1 can't be represented as a float64, so it only survives as a json.Number.
+	assert.Equals(t, assert.NoErrorR[int64](t)(intSchema.UnserializeType(json.Number("9007199254740993"))),
+		int64(9007199254740993))
+	assert.Equals(t, assert.NoErrorR[int64](t)(intSchema.UnserializeType(json.Number("-9223372036854775808"))),
+		int64(math.MinInt64))
+	assert.Equals(t, assert.NoErrorR[int64](t)(intSchema.UnserializeType(json.Number("1e3"))), int64(1000))
+	for _, number := range []json.Number{"9223372036854775808", "1.5", "1e19", "abc"} {
+		_, err := intSchema.UnserializeType(number)
+		assert.Error(t, err)
+	}
+	enumSchema := schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{3: nil}, nil)
+	assert.Equals(t, assert.NoErrorR[any](t)(enumSchema.Unserialize(json.Number("3"))), any(int64(3)))
+}
+
+func TestIntFloatOverflow(t *testing.T) {
+	intSchema := schema.NewIntSchema(nil, nil, nil)
+	for _, value := range []any{
+		float64(math.MaxInt64),
+		math.Ldexp(1, 64),
+		-math.Ldexp(1, 64),
+		math.Inf(1),
+		math.NaN(),
+		float32(math.MaxFloat32),
+		uint(math.MaxUint64),
+	} {
+		_, err := intSchema.UnserializeType(value)
+		assert.Error(t, err)
+	}
+	assert.Equals(t, assert.NoErrorR[int64](t)(intSchema.UnserializeType(float64(math.MinInt64))),
+		int64(math.MinInt64))
+	assert.Equals(t, assert.NoErrorR[int64](t)(intSchema.UnserializeType(math.Ldexp(1, 62))), int64(1)<<62)
+}
+
+func TestIntUnits(t *testing.T) {
+	durationType := schema.NewIntSchema(nil, nil, schema.UnitDurationNanoseconds)
+	assert.Equals(t, assert.NoErrorR[any](t)(durationType.Unserialize("200ms")), any(int64(200000000)))
+	assert.Equals(t, assert.NoErrorR[any](t)(durationType.Unserialize("1m30s")), any(int64(90000000000)))
+	assert.Equals(t, assert.NoErrorR[any](t)(durationType.Serialize(int64(90000000000))), any(int64(90000000000)))
+	_, err := durationType.Unserialize("5GB")
+	assert.Error(t, err)
+
+	bytesType := schema.NewIntSchema(nil, nil, schema.UnitBytes).SerializeWithUnits()
+	assert.Equals(t, assert.NoErrorR[any](t)(bytesType.Unserialize("5GB")), any(int64(5368709120)))
+	assert.Equals(t, assert.NoErrorR[any](t)(bytesType.Serialize(int64(5368709120))), any("5GB"))
+	assert.Equals(t, assert.NoErrorR[any](t)(bytesType.Serialize(int64(10240))), any("10kB"))
+	assert.Equals(t, assert.NoErrorR[any](t)(bytesType.Serialize(int64(0))), any("0B"))
+	assert.Equals(t, assert.NoErrorR[any](t)(bytesType.Serialize(int64(-1))), any(int64(-1)))
+
+	for _, value := range []int64{0, 1, 999, 1025, 90000000000, 3 * 1073741824} {
+		serialized := assert.NoErrorR[any](t)(bytesType.Serialize(value))
+		assert.Equals(t, assert.NoErrorR[any](t)(bytesType.Unserialize(serialized)), any(value))
+	}
+}
+
+func TestIntSerializeWithUnitsSelfSerialize(t *testing.T) {
+	newScope := func(sizeType *schema.IntSchema) *schema.ScopeSchema {
+		return schema.NewScopeSchema(schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"size": schema.NewPropertySchema(sizeType, nil, true, nil, nil, nil, nil, nil),
+		}))
+	}
+	serialized := assert.NoErrorR[any](t)(newScope(schema.NewIntSchema(nil, nil, schema.UnitBytes)).SelfSerialize())
+	assertNoSerializedKeys(t, serialized, "serialize_units")
+
+	serialized = assert.NoErrorR[any](t)(
+		newScope(schema.NewIntSchema(nil, nil, schema.UnitBytes).SerializeWithUnits()).SelfSerialize(),
+	)
+	unserialized := assert.NoErrorR[*schema.ScopeSchema](t)(schema.UnserializeScope(serialized))
+	sizeType := unserialized.Objects()["test"].Properties()["size"].Type()
+	assert.Equals(t, assert.NoErrorR[any](t)(sizeType.Serialize(int64(5368709120))), any("5GB"))
+}
+
+func TestIntAllocations(t *testing.T) {
+	s := schema.NewIntSchema(schema.IntPointer(0), nil, nil)
+	var data any = int64(123456789)
+	assert.Equals(t, testing.AllocsPerRun(100, func() {
+		_, _ = s.Serialize(data)
+		_, _ = s.Unserialize(data)
+		_ = s.Validate(data)
+		_, _ = s.SerializeInt(123456789)
+	}), float64(0))
+
+	assert.Equals(t, assert.NoErrorR[int64](t)(s.SerializeInt(123456789)), int64(123456789))
+	_, err := s.SerializeInt(-1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// UnserializeJSONStrict decodes the JSON data and unserializes the result with the type. Unlike json.Unmarshal, which
+// silently keeps the last value of a key that appears more than once in an object, it rejects duplicate keys with a
+// ConstraintError pointing to the key, so an input that says two different things is never half-applied. Otherwise
+// the data is decoded like json.Unmarshal decodes into an any, except that numbers are kept as json.Number, so large
+// integers are not rounded. See UnserializeJSON to keep the last value instead.
+func UnserializeJSONStrict(t Type, data []byte) (any, error) {
+	return UnserializeJSON(t, data, DecodeOptions{DuplicateKeys: DuplicateKeysReject})
+}
+
+// jsonStrictFrame is an object or array decodeJSON is in the middle of decoding.
+type jsonStrictFrame struct {
+	// object is the decoded object, or nil if the frame is an array.
+	object map[string]any
+	array  []any
+	// key is the key of the object value being decoded. It is only valid if hasKey is true.
+	key    string
+	hasKey bool
+}
+
+// segment returns the path segment of the value currently being decoded in the frame.
+func (f *jsonStrictFrame) segment() string {
+	if f.object != nil {
+		return f.key
+	}
+	return strconv.Itoa(len(f.array))
+}
+
+// decodeJSON decodes the JSON data token by token, handling duplicate object keys as the options say. It keeps the
+// objects and arrays it is in the middle of on a stack instead of recursing, so deeply nested data can't exhaust the
+// stack. Numbers are decoded as json.Number, as a float64 can't hold integers above 2^53 exactly.
+func decodeJSON(data []byte, options DecodeOptions) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var stack []*jsonStrictFrame
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, &UnserializationError{Message: "failed to decode JSON", Path: jsonStrictPath(stack), Cause: err}
+		}
+		var value any
+		switch token {
+		case json.Delim('{'):
+			stack = append(stack, &jsonStrictFrame{object: map[string]any{}})
+			continue
+		case json.Delim('['):
+			stack = append(stack, &jsonStrictFrame{array: []any{}})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top.object != nil {
+				value = top.object
+			} else {
+				value = top.array
+			}
+		default:
+			if len(stack) > 0 {
+				if top := stack[len(stack)-1]; top.object != nil && !top.hasKey {
+					// The decoder only returns strings in the place of keys.
+					key := token.(string)
+					if _, ok := top.object[key]; ok {
+						if err := options.duplicateKey(duplicateJSONKeyError(stack, key)); err != nil {
+							return nil, err
+						}
+					}
+					top.key = key
+					top.hasKey = true
+					continue
+				}
+			}
+			value = token
+		}
+		if len(stack) == 0 {
+			if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+				return nil, &UnserializationError{Message: "failed to decode JSON, unexpected data after the top-level value"}
+			}
+			return value, nil
+		}
+		parent := stack[len(stack)-1]
+		if parent.object != nil {
+			parent.object[parent.key] = value
+			parent.hasKey = false
+		} else {
+			parent.array = append(parent.array, value)
+		}
+	}
+}
+
+// jsonStrictPath returns the path of the value currently being decoded.
+func jsonStrictPath(stack []*jsonStrictFrame) []string {
+	path := make([]string, 0, len(stack))
+	for _, frame := range stack {
+		if frame.object == nil || frame.hasKey {
+			path = append(path, frame.segment())
+		}
+	}
+	return path
+}
+
+func duplicateJSONKeyError(stack []*jsonStrictFrame, key string) *ConstraintError {
+	return &ConstraintError{
+		Message:    fmt.Sprintf("Duplicate key '%s' in JSON object", key),
+		Path:       append(jsonStrictPath(stack[:len(stack)-1]), key),
+		Constraint: ConstraintDuplicateKey,
+		Actual:     key,
+	}
+}
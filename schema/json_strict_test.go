@@ -0,0 +1,95 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestUnserializeJSONStrict(t *testing.T) {
+	listSchema := schema.NewListSchema(schema.NewMapSchema(
+		schema.NewStringSchema(nil, nil, nil),
+		schema.NewAnySchema(),
+		nil,
+		nil,
+	), nil, nil)
+	result := assert.NoErrorR[any](t)(schema.UnserializeJSONStrict(
+		listSchema,
+		[]byte(`[{"a": 1, "b": [true, "c"]}, {"a": {"a": 2}}]`),
+	))
+	assert.Equals(t, result, any([]map[string]any{
+		{"a": int64(1), "b": []any{true, "c"}},
+		{"a": map[any]any{"a": int64(2)}},
+	}))
+
+	for name, testCase := range map[string]struct {
+		data string
+		path []string
+	}{
+		"top-level": {`[{"a": 1, "a": 2}]`, []string{"0", "a"}},
+		"nested":    {`[{}, {"a": {"b": [], "c": {"d": 1, "d": 1}}}]`, []string{"1", "a", "c", "d"}},
+		"after-map": {`[{"a": {"b": 1}, "a": {}}]`, []string{"0", "a"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := schema.UnserializeJSONStrict(listSchema, []byte(testCase.data))
+			var constraintErr *schema.ConstraintError
+			assert.Equals(t, errors.As(err, &constraintErr), true)
+			assert.Equals(t, constraintErr.Constraint, schema.ConstraintDuplicateKey)
+			assert.Equals(t, constraintErr.Path, testCase.path)
+		})
+	}
+}
+
+func TestUnserializeJSONStrictInvalid(t *testing.T) {
+	for _, data := range []string{``, `[`, `{"a": }`, `[1] [2]`, `{"a": 1]`} {
+		_, err := schema.UnserializeJSONStrict(schema.NewAnySchema(), []byte(data))
+		assert.Error(t, err)
+	}
+	_, err := schema.UnserializeJSONStrict(schema.NewIntSchema(nil, nil, nil), []byte(`"one"`))
+	assert.Error(t, err)
+
+	// Nesting is decoded without recursion, up to the depth limit of the JSON decoder.
+	deep := strings.Repeat("[", 5000) + strings.Repeat("]", 5000)
+	assert.NoErrorR[any](t)(schema.UnserializeJSONStrict(schema.NewAnySchema(), []byte(deep)))
+}
+
+func TestUnserializeJSONLastWins(t *testing.T) {
+	var warnings []*schema.ConstraintError
+	result := assert.NoErrorR[any](t)(schema.UnserializeJSON(
+		schema.NewAnySchema(),
+		[]byte(`{"a": {"b": 1, "b": 2}, "a": {"c": 3}}`),
+		schema.DecodeOptions{
+			DuplicateKeys: schema.DuplicateKeysLastWins,
+			Warn: func(err *schema.ConstraintError) {
+				warnings = append(warnings, err)
+			},
+		},
+	))
+	assert.Equals(t, result, any(map[any]any{"a": map[any]any{"c": int64(3)}}))
+	assert.Equals(t, len(warnings), 2)
+	assert.Equals(t, warnings[0].Path, []string{"a", "b"})
+	assert.Equals(t, warnings[1].Path, []string{"a"})
+	assert.Equals(t, warnings[1].Constraint, schema.ConstraintDuplicateKey)
+
+	// The zero options reject duplicates.
+	_, err := schema.UnserializeJSON(schema.NewAnySchema(), []byte(`{"a": 1, "a": 1}`), schema.DecodeOptions{})
+	assert.Error(t, err)
+}
+
+func TestUnserializeJSONStrictLargeInt(t *testing.T) {
+	// 9007199254740993 is 2^53+1, which a float64 rounds to 9007199254740992.
+	result := assert.NoErrorR[any](t)(schema.UnserializeJSONStrict(
+		schema.NewIntSchema(nil, nil, nil),
+		[]byte(`9007199254740993`),
+	))
+	assert.Equals(t, result, any(int64(9007199254740993)))
+
+	result = assert.NoErrorR[any](t)(schema.UnserializeJSONStrict(
+		schema.NewAnySchema(),
+		[]byte(`{"a": [9007199254740993, 1.5]}`),
+	))
+	assert.Equals(t, result, any(map[any]any{"a": []any{int64(9007199254740993), 1.5}}))
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+)
+
+type labelsContextKey struct{}
+
+// ContextWithLabels returns a copy of the context carrying the labels of a step invocation, so shared plugin services
+// can segregate their logs and usage per workflow owner.
+func ContextWithLabels(ctx context.Context, labels map[string]string) context.Context {
+	if len(labels) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, labelsContextKey{}, labels)
+}
+
+// LabelsFromContext returns the labels of the step invocation the context belongs to, or nil if it has none.
+func LabelsFromContext(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(labelsContextKey{}).(map[string]string)
+	return labels
+}
+
+// ValidateLabels checks that the label keys are not empty.
+func ValidateLabels(labels map[string]string) error {
+	for key := range labels {
+		if key == "" {
+			return &ConstraintError{
+				Message:    fmt.Sprintf("Label keys must not be empty (value: %q)", labels[key]),
+				Path:       []string{"labels"},
+				Constraint: ConstraintMin,
+				Expected:   int64(1),
+				Actual:     int64(0),
+			}
+		}
+	}
+	return nil
+}
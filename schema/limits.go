@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// UnserializeLimits limits the size of the data UnserializeWithLimits accepts, so untrusted input with deeply nested or
+// enormous structures is rejected with a ConstraintError before it can exhaust the stack or memory of the plugin. Zero
+// values mean no limit.
+type UnserializeLimits struct {
+	// MaxDepth is the number of lists and maps that may be nested in each other.
+	MaxDepth int64
+	// MaxLength is the number of items a single list or map may have.
+	MaxLength int64
+	// MaxNodes is the number of values the data may consist of in total, counting every list, map, key and scalar.
+	MaxNodes int64
+}
+
+// DefaultUnserializeLimits returns the limits the callable schema applies to step inputs and signal data unless
+// overridden with WithUnserializeLimits. They are far above what legitimate inputs need.
+func DefaultUnserializeLimits() UnserializeLimits {
+	return UnserializeLimits{
+		MaxDepth:  128,
+		MaxLength: 1_000_000,
+		MaxNodes:  10_000_000,
+	}
+}
+
+// UnserializeWithLimits checks the serialized data against the limits and unserializes it with the type if it is
+// within them.
+func UnserializeWithLimits(t Type, data any, limits UnserializeLimits) (any, error) {
+	if err := limits.Check(data); err != nil {
+		return nil, err
+	}
+	return t.Unserialize(data)
+}
+
+// Check returns a ConstraintError if the serialized data exceeds the limits. It walks the data without recursion, so
+// it is safe to call on arbitrarily deep data.
+func (l UnserializeLimits) Check(data any) error {
+	return l.check(data, nil)
+}
+
+// check checks the data against the limits, yielding with the yielder if set.
+func (l UnserializeLimits) check(data any, y *yielder) error {
+	stack := []*limitsNode{{value: reflect.ValueOf(data)}}
+	nodes := int64(0)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		value := current.value
+		for value.IsValid() && (value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer) && !value.IsNil() {
+			value = value.Elem()
+		}
+		if y.point() != nil {
+			return y.err
+		}
+		nodes++
+		if l.MaxNodes > 0 && nodes > l.MaxNodes {
+			return current.limitError("The data consists of more than %d values", l.MaxNodes, nodes)
+		}
+		if !isLimitsContainer(value) {
+			continue
+		}
+		if depth := current.depth + 1; l.MaxDepth > 0 && depth > l.MaxDepth {
+			return current.limitError("The data is nested deeper than %d levels", l.MaxDepth, depth)
+		}
+		if length := int64(value.Len()); l.MaxLength > 0 && length > l.MaxLength {
+			return current.limitError("The data contains more than %d items", l.MaxLength, length)
+		}
+		if value.Kind() == reflect.Map {
+			iterator := value.MapRange()
+			for iterator.Next() {
+				// Keys are scalars, so they only count as nodes.
+				nodes++
+				stack = append(stack, current.child(iterator.Value(), fmt.Sprintf("%v", iterator.Key().Interface())))
+			}
+			continue
+		}
+		for i := value.Len() - 1; i >= 0; i-- {
+			stack = append(stack, current.child(value.Index(i), strconv.Itoa(i)))
+		}
+	}
+	return nil
+}
+
+// isLimitsContainer returns true for the lists and maps whose depth and length are limited. Byte slices are binary
+// values, not lists.
+func isLimitsContainer(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Map, reflect.Array:
+		return true
+	case reflect.Slice:
+		return value.Type().Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// limitsNode is a value in the data Check walks. It links to its parent, so the path is only built for errors.
+type limitsNode struct {
+	value   reflect.Value
+	parent  *limitsNode
+	segment string
+	depth   int64
+}
+
+func (n *limitsNode) child(value reflect.Value, segment string) *limitsNode {
+	return &limitsNode{value, n, segment, n.depth + 1}
+}
+
+func (n *limitsNode) limitError(format string, limit int64, actual int64) *ConstraintError {
+	path := make([]string, n.depth)
+	for current := n; current.parent != nil; current = current.parent {
+		path[current.depth-1] = current.segment
+	}
+	return &ConstraintError{
+		Message:    fmt.Sprintf(format, limit),
+		Path:       path,
+		Constraint: ConstraintLimit,
+		Expected:   limit,
+		Actual:     actual,
+	}
+}
@@ -0,0 +1,105 @@
+package schema_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func nestedLists(depth int) any {
+	var data any = "leaf"
+	for i := 0; i < depth; i++ {
+		data = []any{data}
+	}
+	return data
+}
+
+func TestUnserializeLimitsCheck(t *testing.T) {
+	limits := schema.UnserializeLimits{MaxDepth: 3, MaxLength: 3, MaxNodes: 10}
+	assert.NoError(t, limits.Check(nil))
+	assert.NoError(t, limits.Check(nestedLists(3)))
+	assert.NoError(t, limits.Check(map[string]any{"a": []any{1, 2, 3}, "b": map[any]any{"c": "d"}}))
+
+	for name, testCase := range map[string]struct {
+		data any
+		path []string
+	}{
+		"depth":  {nestedLists(4), []string{"0", "0", "0"}},
+		"list":   {map[string]any{"a": []string{"1", "2", "3", "4"}}, []string{"a"}},
+		"map":    {map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}, []string{}},
+		"nodes":  {[]any{[]any{1, 2, 3}, []any{4, 5, 6}, []any{7, 8, 9}}, []string{"2", "0"}},
+		"unlist": {map[string]any{"a": map[string]any{"b": []any{1, 2, 3, 4}}}, []string{"a", "b"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := limits.Check(testCase.data)
+			var constraintErr *schema.ConstraintError
+			assert.Equals(t, errors.As(err, &constraintErr), true)
+			assert.Equals(t, constraintErr.Constraint, schema.ConstraintLimit)
+			assert.Equals(t, constraintErr.Path, testCase.path)
+		})
+	}
+
+	// Byte slices are single values and zero limits are no limits.
+	assert.NoError(t, limits.Check([]byte("0123456789abcdef")))
+	assert.NoError(t, schema.UnserializeLimits{}.Check(nestedLists(10000)))
+}
+
+func TestUnserializeWithLimits(t *testing.T) {
+	listSchema := schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil)
+	_, err := schema.UnserializeWithLimits(listSchema, []any{1, 2, 3}, schema.UnserializeLimits{MaxLength: 2})
+	assert.Error(t, err)
+	result := assert.NoErrorR[any](t)(schema.UnserializeWithLimits(
+		listSchema,
+		[]any{1, 2, 3},
+		schema.DefaultUnserializeLimits(),
+	))
+	assert.Equals(t, result, any([]int64{1, 2, 3}))
+}
+
+func TestCallableSchemaUnserializeLimits(t *testing.T) {
+	type input struct {
+		Items []any `json:"items"`
+	}
+	callable := schema.NewCallableSchema(schema.NewCallableStep[input](
+		"step",
+		schema.NewScopeSchema(schema.NewStructMappedObjectSchema[input]("input", map[string]*schema.PropertySchema{
+			"items": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewAnySchema(), nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		})),
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+				nil,
+				false,
+			),
+		},
+		nil,
+		func(_ context.Context, _ input) (string, any) {
+			return "success", map[string]any{}
+		},
+	))
+	deep := map[string]any{"items": []any{nestedLists(200)}}
+	_, _, err := callable.CallStep(context.Background(), "run-1", "step", deep)
+	var invalidInput schema.InvalidInputError
+	assert.Equals(t, errors.As(err, &invalidInput), true)
+
+	callable.WithUnserializeLimits(schema.UnserializeLimits{MaxLength: 1})
+	_, _, err = callable.CallStep(context.Background(), "run-2", "step", map[string]any{"items": []any{1, 2}})
+	assert.Error(t, err)
+	outputID := assert.NoErrorR[string](t)(func() (string, error) {
+		outputID, _, err := callable.CallStep(context.Background(), "run-3", "step", map[string]any{"items": []any{1}})
+		return outputID, err
+	}())
+	assert.Equals(t, outputID, "success")
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // List holds the schema definition for lists.
@@ -22,13 +23,22 @@ type TypedList[UnserializedType any, ItemType TypedType[UnserializedType]] inter
 // UntypedList specifies a list that has no specific type.
 type UntypedList = List[Type]
 
+// untypedListSchema is satisfied by every list schema regardless of its item type parameter.
+type untypedListSchema interface {
+	Type
+	itemType() Type
+	Min() *int64
+	Max() *int64
+	duplicateItems(items reflect.Value, sink *constraintSink) error
+}
+
 // NewListSchema creates a new list schema from the specified values.
 func NewListSchema(items Type, min *int64, max *int64) *ListSchema {
 	return &ListSchema{
 		AbstractListSchema[Type]{
-			items,
-			min,
-			max,
+			ItemsValue: items,
+			MinValue:   min,
+			MaxValue:   max,
 		},
 	}
 }
@@ -41,9 +51,9 @@ func NewTypedListSchema[UnserializedType any](
 ) *TypedListSchema[UnserializedType, TypedType[UnserializedType]] {
 	return &TypedListSchema[UnserializedType, TypedType[UnserializedType]]{
 		AbstractListSchema[TypedType[UnserializedType]]{
-			items,
-			min,
-			max,
+			ItemsValue: items,
+			MinValue:   min,
+			MaxValue:   max,
 		},
 	}
 }
@@ -53,16 +63,42 @@ type ListSchema struct {
 	AbstractListSchema[Type] `json:",inline"`
 }
 
+// UniqueItems is a builder-pattern way of rejecting lists with duplicate items. If a key path is given, only the
+// value at that property path of each item is compared, such as the name of each object. Items that don't have a
+// value at the path are not compared.
+func (l *ListSchema) UniqueItems(keyPath ...string) *ListSchema {
+	l.UniqueItemsValue = true
+	l.UniqueKeyValue = keyPath
+	return l
+}
+
 // TypedListSchema is the typed variant of the list.
 type TypedListSchema[UnserializedType any, ItemType TypedType[UnserializedType]] struct {
 	AbstractListSchema[ItemType] `json:",inline"`
 }
 
+// UniqueItems is a builder-pattern way of rejecting lists with duplicate items. If a key path is given, only the
+// value at that property path of each item is compared. Items that don't have a value at the path are not compared.
+func (t *TypedListSchema[UnserializedType, ItemType]) UniqueItems(
+	keyPath ...string,
+) *TypedListSchema[UnserializedType, ItemType] {
+	t.UniqueItemsValue = true
+	t.UniqueKeyValue = keyPath
+	return t
+}
+
 // AbstractListSchema is a root type for both the untyped and the typed lists.
 type AbstractListSchema[ItemType Type] struct {
 	ItemsValue ItemType `json:"items"`
 	MinValue   *int64   `json:"min"`
 	MaxValue   *int64   `json:"max"`
+	// UniqueItemsValue rejects lists with duplicate items.
+	UniqueItemsValue bool `json:"unique_items,omitempty"`
+	// UniqueKeyValue is the property path of the item value compared for uniqueness. Empty means the whole item.
+	UniqueKeyValue []string `json:"unique_key"`
+
+	// parallelWorkers is the number of workers unserializing the items of large lists, or 0 for sequential.
+	parallelWorkers int
 }
 
 func (l AbstractListSchema[ItemType]) TypeID() TypeID {
@@ -81,14 +117,135 @@ func (l AbstractListSchema[ItemType]) Max() *int64 {
 	return l.MaxValue
 }
 
+// UniqueItemsEnabled returns true if lists with duplicate items are rejected.
+func (l AbstractListSchema[ItemType]) UniqueItemsEnabled() bool {
+	return l.UniqueItemsValue
+}
+
+// UniqueKey returns the property path of the item value compared for uniqueness, or nil if whole items are compared.
+func (l AbstractListSchema[ItemType]) UniqueKey() []string {
+	return l.UniqueKeyValue
+}
+
+// duplicateItems reports every unserialized item that duplicates an earlier item to the sink, if the list requires
+// unique items. Items are compared in their serialized form, so values unserializing to the same result are
+// duplicates regardless of how they were written.
+func (l AbstractListSchema[ItemType]) duplicateItems(items reflect.Value, sink *constraintSink) error {
+	if !l.UniqueItemsValue {
+		return nil
+	}
+	seen := make(map[string]int, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		serialized, err := l.ItemsValue.Serialize(items.Index(i).Interface())
+		if err != nil {
+			// Invalid items are reported by the item validation.
+			continue
+		}
+		key, ok := valueAtPath(serialized, l.UniqueKeyValue)
+		if !ok {
+			continue
+		}
+		identity := fmt.Sprintf("%#v", key)
+		first, duplicate := seen[identity]
+		if !duplicate {
+			seen[identity] = i
+			continue
+		}
+		if err := sink.report(l.duplicateItemError(i, first, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkItemCount reports the item count of a list or map to the sink if it is outside the bounds.
+func checkItemCount(minValue *int64, maxValue *int64, count int, sink *constraintSink) error {
+	if minValue != nil && *minValue > int64(count) {
+		if err := sink.report(&ConstraintError{
+			Message:    fmt.Sprintf("Must have at least %d items, %d given", *minValue, count),
+			Constraint: ConstraintMin,
+			Expected:   *minValue,
+			Actual:     int64(count),
+		}); err != nil {
+			return err
+		}
+	}
+	if maxValue != nil && *maxValue < int64(count) {
+		return sink.report(&ConstraintError{
+			Message:    fmt.Sprintf("Must have at most %d items, %d given", *maxValue, count),
+			Constraint: ConstraintMax,
+			Expected:   *maxValue,
+			Actual:     int64(count),
+		})
+	}
+	return nil
+}
+
+// duplicateItemError describes the item at index i duplicating the key of the item at index first.
+func (l AbstractListSchema[ItemType]) duplicateItemError(i int, first int, key any) *ConstraintError {
+	message := fmt.Sprintf("Item %d duplicates item %d", i, first)
+	if len(l.UniqueKeyValue) > 0 {
+		message = fmt.Sprintf(
+			"Item %d duplicates the %s of item %d",
+			i,
+			strings.Join(l.UniqueKeyValue, "."),
+			first,
+		)
+	}
+	return &ConstraintError{
+		Message:    message,
+		Path:       append([]string{fmt.Sprintf("[%d]", i)}, l.UniqueKeyValue...),
+		Constraint: ConstraintUnique,
+		Expected:   fmt.Sprintf("[%d]", first),
+		Actual:     describeValue(key),
+	}
+}
+
+// valueAtPath returns the value at the property path of serialized data.
+func valueAtPath(data any, path []string) (any, bool) {
+	for _, segment := range path {
+		v := reflect.ValueOf(data)
+		if v.Kind() != reflect.Map {
+			return nil, false
+		}
+		key := reflect.ValueOf(segment)
+		if !key.Type().AssignableTo(v.Type().Key()) {
+			return nil, false
+		}
+		value := v.MapIndex(key)
+		if !value.IsValid() || value.Interface() == nil {
+			return nil, false
+		}
+		data = value.Interface()
+	}
+	return data, true
+}
+
+// itemType returns the item type without the generic type parameter.
+func (l AbstractListSchema[ItemType]) itemType() Type {
+	return l.ItemsValue
+}
+
 func (l AbstractListSchema[ItemType]) ApplyNamespace(objects map[string]*ObjectSchema, namespace string) {
-	l.ItemsValue.ApplyNamespace(objects, namespace)
+	applyNamespace(l.ItemsValue, objects, namespace)
 }
 
 func (l AbstractListSchema[ItemType]) ValidateReferences() error {
 	return l.ItemsValue.ValidateReferences()
 }
 
+func (l AbstractListSchema[ItemType]) ApplyDefaults(serialized any) any {
+	v := reflect.ValueOf(serialized)
+	if v.Kind() != reflect.Slice {
+		return serialized
+	}
+	result := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		result[i] = ApplyDefaults(l.ItemsValue, v.Index(i).Interface())
+	}
+	return result
+}
+
 func (l AbstractListSchema[ItemType]) ReflectedType() reflect.Type {
 	elementType := l.ItemsValue.ReflectedType()
 	return reflect.SliceOf(elementType)
@@ -98,33 +255,56 @@ func (l AbstractListSchema[ItemType]) Unserialize(data any) (any, error) {
 	v := reflect.ValueOf(data)
 	switch v.Kind() {
 	case reflect.Slice:
-		if l.MinValue != nil && *l.MinValue > int64(v.Len()) {
-			return nil, &ConstraintError{
-				Message: fmt.Sprintf("Must have at least %d items, %d given", *l.MinValue, v.Len()),
-			}
-		}
-		if l.MaxValue != nil && *l.MaxValue < int64(v.Len()) {
-			return nil, &ConstraintError{
-				Message: fmt.Sprintf("Must have at most %d items, %d given", *l.MaxValue, v.Len()),
-			}
+		if err := checkItemCount(l.MinValue, l.MaxValue, v.Len(), nil); err != nil {
+			return nil, err
 		}
 
 		result := reflect.MakeSlice(reflect.SliceOf(l.ItemsValue.ReflectedType()), v.Len(), v.Len())
-		for i := 0; i < v.Len(); i++ {
-			unserializedV, err := l.ItemsValue.Unserialize(v.Index(i).Interface())
-			if err != nil {
-				return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+		if l.parallelWorkers > 0 && v.Len() >= ParallelMinItems {
+			if err := l.unserializeParallel(v, result); err != nil {
+				return nil, err
+			}
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				unserializedV, err := l.ItemsValue.Unserialize(v.Index(i).Interface())
+				if err != nil {
+					return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+				}
+				result.Index(i).Set(reflect.ValueOf(unserializedV))
 			}
-			result.Index(i).Set(reflect.ValueOf(unserializedV))
+		}
+		if err := l.duplicateItems(result, nil); err != nil {
+			return nil, err
 		}
 		return result.Interface(), nil
 	default:
 		return nil, &ConstraintError{
-			Message: fmt.Sprintf("Must be a slice, %T given", data),
+			Message:    fmt.Sprintf("Must be a slice, %T given", data),
+			Constraint: ConstraintDataType,
+			Expected:   "list",
+			Actual:     fmt.Sprintf("%T", data),
 		}
 	}
 }
 
+// unserializeParallel unserializes the items of the slice into the result on the workers of the list.
+func (l AbstractListSchema[ItemType]) unserializeParallel(v reflect.Value, result reflect.Value) error {
+	items, err := unserializeParallel(v.Len(), l.parallelWorkers, func(i int) (any, error) {
+		unserializedV, err := l.ItemsValue.Unserialize(v.Index(i).Interface())
+		if err != nil {
+			return nil, itemError(err, fmt.Sprintf("[%d]", i))
+		}
+		return unserializedV, nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		result.Index(i).Set(reflect.ValueOf(item))
+	}
+	return nil
+}
+
 func (l AbstractListSchema[ItemType]) ValidateCompatibility(typeOrData any) error {
 	// Check if it's a schema.Type. If it is, verify it. If not, verify it as data.
 	value := reflect.ValueOf(typeOrData)
@@ -183,18 +363,14 @@ func (l AbstractListSchema[ItemType]) Validate(data any) error {
 	v := reflect.ValueOf(data)
 	if v.Kind() != reflect.Slice {
 		return &ConstraintError{
-			Message: fmt.Sprintf("%T is not a valid data type for a slice schema.", data),
-		}
-	}
-	if l.MinValue != nil && *l.MinValue > int64(v.Len()) {
-		return &ConstraintError{
-			Message: fmt.Sprintf("Must have at least %d items, %d given", *l.MinValue, v.Len()),
+			Message:    fmt.Sprintf("%T is not a valid data type for a slice schema.", data),
+			Constraint: ConstraintDataType,
+			Expected:   "list",
+			Actual:     fmt.Sprintf("%T", data),
 		}
 	}
-	if l.MaxValue != nil && *l.MaxValue < int64(v.Len()) {
-		return &ConstraintError{
-			Message: fmt.Sprintf("Must have at most %d items, %d given", *l.MaxValue, v.Len()),
-		}
+	if err := checkItemCount(l.MinValue, l.MaxValue, v.Len(), nil); err != nil {
+		return err
 	}
 
 	for i := 0; i < v.Len(); i++ {
@@ -202,6 +378,9 @@ func (l AbstractListSchema[ItemType]) Validate(data any) error {
 			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
 		}
 	}
+	if err := l.duplicateItems(v, nil); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"fmt"
+)
+
+// ListItems produces the items of a list one at a time by calling yield for each item, and stops early if yield
+// returns false. It has the shape of the iterators of the iter package, so item sources can be written as generators
+// without holding all items in memory.
+type ListItems func(yield func(item any) bool)
+
+// ChannelItems returns the items received from the channel until it is closed. If the consumer stops early, the
+// remaining items are not read, so the producer should also watch a context that is cancelled when the consumer fails.
+func ChannelItems[T any](items <-chan T) ListItems {
+	return func(yield func(item any) bool) {
+		for item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// StreamSerialize serializes the unserialized items one at a time and passes each serialized item to emit, so steps
+// producing millions of records don't need the whole list in memory. The items are checked like Serialize checks a
+// list, except that lists with too few items are only detected once all items are emitted. For lists with unique
+// items, the identity of every item is kept to detect duplicates. It stops at the first invalid item or emit error.
+func (l AbstractListSchema[ItemType]) StreamSerialize(items ListItems, emit func(serialized any) error) error {
+	return l.stream(items, emit, func(item any) (any, any, error) {
+		serialized, err := l.ItemsValue.Serialize(item)
+		return serialized, serialized, err
+	})
+}
+
+// StreamUnserialize unserializes the serialized items one at a time, such as records read from a stream decoder, and
+// passes each unserialized item to emit. The items are checked like StreamSerialize checks them.
+func (l AbstractListSchema[ItemType]) StreamUnserialize(items ListItems, emit func(unserialized any) error) error {
+	return l.stream(items, emit, func(item any) (any, any, error) {
+		unserialized, err := l.ItemsValue.Unserialize(item)
+		if err != nil || !l.UniqueItemsValue {
+			return unserialized, nil, err
+		}
+		// Duplicates are detected in serialized form, like for lists unserialized at once.
+		serialized, err := l.ItemsValue.Serialize(unserialized)
+		return unserialized, serialized, err
+	})
+}
+
+// stream converts the items with the function, which returns the converted item and its serialized form, and checks
+// the list constraints on the way.
+func (l AbstractListSchema[ItemType]) stream(
+	items ListItems,
+	emit func(item any) error,
+	convert func(item any) (any, any, error),
+) error {
+	var err error
+	count := 0
+	seen := map[string]int{}
+	items(func(item any) bool {
+		if l.MaxValue != nil && *l.MaxValue <= int64(count) {
+			err = &ConstraintError{
+				Message:    fmt.Sprintf("Must have at most %d items, more given", *l.MaxValue),
+				Constraint: ConstraintMax,
+				Expected:   *l.MaxValue,
+				Actual:     int64(count + 1),
+			}
+			return false
+		}
+		converted, serialized, convertErr := convert(item)
+		if convertErr != nil {
+			err = ConstraintErrorAddPathSegment(convertErr, fmt.Sprintf("[%d]", count))
+			return false
+		}
+		if l.UniqueItemsValue {
+			if key, ok := valueAtPath(serialized, l.UniqueKeyValue); ok {
+				identity := fmt.Sprintf("%#v", key)
+				if first, duplicate := seen[identity]; duplicate {
+					err = l.duplicateItemError(count, first, key)
+					return false
+				}
+				seen[identity] = count
+			}
+		}
+		if emitErr := emit(converted); emitErr != nil {
+			err = fmt.Errorf("failed to emit item %d (%w)", count, emitErr)
+			return false
+		}
+		count++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if l.MinValue != nil && *l.MinValue > int64(count) {
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must have at least %d items, %d given", *l.MinValue, count),
+			Constraint: ConstraintMin,
+			Expected:   *l.MinValue,
+			Actual:     int64(count),
+		}
+	}
+	return nil
+}
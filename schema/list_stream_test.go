@@ -0,0 +1,100 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func countTo(n int) schema.ListItems {
+	return func(yield func(item any) bool) {
+		for i := 1; i <= n; i++ {
+			if !yield(int64(i)) {
+				return
+			}
+		}
+	}
+}
+
+func TestListStreamSerialize(t *testing.T) {
+	list := schema.NewListSchema(
+		schema.NewIntSchema(nil, nil, nil),
+		schema.PointerTo(int64(2)),
+		schema.PointerTo(int64(4)),
+	)
+	var emitted []any
+	emit := func(serialized any) error {
+		emitted = append(emitted, serialized)
+		return nil
+	}
+	assert.NoError(t, list.StreamSerialize(countTo(3), emit))
+	assert.Equals(t, emitted, []any{int64(1), int64(2), int64(3)})
+
+	var err *schema.ConstraintError
+	assert.Equals(t, errors.As(list.StreamSerialize(countTo(1), emit), &err), true)
+	assert.Equals(t, err.Constraint, schema.ConstraintMin)
+
+	// The producer stops once the list is too long.
+	produced := 0
+	tooMany := func(yield func(item any) bool) {
+		for i := 0; i < 1000; i++ {
+			produced++
+			if !yield(int64(i)) {
+				return
+			}
+		}
+	}
+	assert.Equals(t, errors.As(list.StreamSerialize(tooMany, emit), &err), true)
+	assert.Equals(t, err.Constraint, schema.ConstraintMax)
+	assert.Equals(t, produced, 5)
+
+	emitErr := errors.New("disk full")
+	assert.Equals(t, errors.Is(list.StreamSerialize(countTo(3), func(any) error { return emitErr }), emitErr), true)
+}
+
+func TestListStreamUnserialize(t *testing.T) {
+	type record struct {
+		Name string `json:"name"`
+	}
+	list := schema.NewListSchema(
+		schema.NewStructMappedObjectSchema[record]("record", map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(1), nil, nil), nil, true, nil, nil, nil, nil, nil,
+			),
+		}),
+		nil,
+		nil,
+	).UniqueItems("name")
+
+	decoder := json.NewDecoder(strings.NewReader(`{"name": "a"} {"name": "b"} {"name": "a"}`))
+	records := func(yield func(item any) bool) {
+		for decoder.More() {
+			var item any
+			if decoder.Decode(&item) != nil || !yield(item) {
+				return
+			}
+		}
+	}
+	var names []string
+	err := list.StreamUnserialize(records, func(unserialized any) error {
+		names = append(names, unserialized.(record).Name)
+		return nil
+	})
+	assert.Equals(t, names, []string{"a", "b"})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintUnique)
+	assert.Equals(t, constraintErr.Path, []string{"[2]", "name"})
+
+	items := make(chan map[string]any, 2)
+	items <- map[string]any{"name": "a"}
+	items <- map[string]any{"name": ""}
+	close(items)
+	err = list.StreamUnserialize(schema.ChannelItems(items), func(any) error { return nil })
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path[0], "[1]")
+}
@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"errors"
 	"go.arcalot.io/assert"
 	"testing"
 
@@ -202,3 +203,98 @@ func TestList_UnSerialize_Reversible(t *testing.T) {
 	// test reversiblity
 	assert.Equals(t, unserialized2, unserialized)
 }
+
+func TestListNilItem(t *testing.T) {
+	s := schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil)
+	err := s.Validate([]any{nil})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[0]")
+	_, err = s.Serialize([]any{nil})
+	assert.Error(t, err)
+}
+
+func TestListUniqueItems(t *testing.T) {
+	listType := schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil)
+	assert.NoErrorR[any](t)(listType.Unserialize([]any{1, 1}))
+	listType.UniqueItems()
+	assert.Equals(t, listType.UniqueItemsEnabled(), true)
+	assert.NoErrorR[any](t)(listType.Unserialize([]any{1, 2}))
+	// Items are compared after unserialization.
+	_, err := listType.Unserialize([]any{1, "2", 2})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintUnique)
+	assert.Equals(t, constraintErr.Path, []string{"[2]"})
+	assert.Error(t, listType.Validate([]int64{3, 3}))
+	_, err = listType.Serialize([]int64{3, 3})
+	assert.Error(t, err)
+
+	_, err = schema.UnserializeAll(listType, []any{1, 1, 2, 2})
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 2)
+	assert.Error(t, schema.ValidateAll(listType, []int64{3, 3}))
+}
+
+type uniqueItemsTestStruct struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
+func TestListUniqueItemsKey(t *testing.T) {
+	listType := schema.NewTypedListSchema[uniqueItemsTestStruct](
+		schema.NewTypedObject[uniqueItemsTestStruct]("item", map[string]*schema.PropertySchema{
+			"name":  schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"value": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		}),
+		nil,
+		nil,
+	).UniqueItems("name")
+	assert.Equals(t, listType.UniqueKey(), []string{"name"})
+	result := assert.NoErrorR[[]uniqueItemsTestStruct](t)(listType.UnserializeType([]any{
+		map[string]any{"name": "a", "value": 1},
+		map[string]any{"name": "b", "value": 1},
+	}))
+	assert.Equals(t, len(result), 2)
+
+	_, err := listType.UnserializeType([]any{
+		map[string]any{"name": "a", "value": 1},
+		map[string]any{"name": "a", "value": 2},
+	})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path, []string{"[1]", "name"})
+	assert.Contains(t, constraintErr.Error(), "duplicates the name of item 0")
+}
+
+func TestListUniqueItemsSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"items": schema.NewPropertySchema(
+				schema.NewListSchema(
+					schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewAnySchema(), nil, nil),
+					nil,
+					nil,
+				).UniqueItems("name"),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	_, err := unserializedScope.Unserialize(map[string]any{
+		"items": []any{map[string]any{"name": "a"}, map[string]any{"name": "a", "value": 1}},
+	})
+	assert.Error(t, err)
+	assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{
+		"items": []any{map[string]any{"name": "a"}, map[string]any{"value": 1}, map[string]any{"value": 1}},
+	}))
+}
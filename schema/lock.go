@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+)
+
+// Lock is a builder-pattern way of marking the scope as complete once it is built. Unlike the Freeze function, which
+// returns an immutable copy of a schema, it marks the scope itself, together with the objects, properties and
+// references in it. After that, the following panic with a BadArgumentError naming the object, instead of silently
+// changing a schema other goroutines may be using:
+//
+//   - Applying a namespace to the scope again, for example with ApplySelf.
+//   - Applying a namespace to one of its objects that links a reference to a different object. Objects shared with
+//     scopes built later are applied again when those scopes are built, which is allowed as long as every reference
+//     still resolves to the object it points to.
+//   - The builder methods of the scope, its objects and their properties, such as Compile or TreatEmptyAsDefaultValue.
+//
+// The exported fields of the schemas are not guarded and must not be changed either. Lock panics if the scope has
+// references that are not linked yet.
+func (s *ScopeSchema) Lock() *ScopeSchema {
+	if err := s.ValidateReferences(); err != nil {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot lock scope with root %s before its references are linked", s.RootValue),
+			Cause:   err,
+		})
+	}
+	s.locked = true
+	for _, object := range s.ObjectsValue {
+		_ = Walk(object, func(_ []string, t Type) error {
+			switch typed := t.(type) {
+			case *ObjectSchema:
+				typed.markLocked()
+			case *RefSchema:
+				typed.locked = true
+				// Objects of the scope are locked by the outer loop, and objects of other scopes are left alone.
+				return SkipChildren
+			case lookupInitializer:
+				// The lookup is created now, so applying the namespace again doesn't write to the one-of.
+				typed.initLookup()
+			}
+			return nil
+		})
+	}
+	return s
+}
+
+// Locked returns true if Lock was called on the scope.
+func (s *ScopeSchema) Locked() bool {
+	return s.locked
+}
+
+// Lock is a builder-pattern way of locking the input, output and signal scopes of all steps once the plugin schema is
+// built, see ScopeSchema.Lock.
+func (s *CallableSchema) Lock() *CallableSchema {
+	for _, step := range s.StepsValue {
+		lockScope(step.Input())
+		for _, output := range step.Outputs() {
+			lockScope(output.Schema())
+		}
+		for _, signal := range step.SignalHandlers() {
+			lockScope(signal.DataSchema())
+		}
+		for _, signal := range step.SignalEmitters() {
+			lockScope(signal.DataSchema())
+		}
+	}
+	return s
+}
+
+func lockScope(scope Scope) {
+	if scopeSchema, ok := scope.(*ScopeSchema); ok && !scopeSchema.locked {
+		scopeSchema.Lock()
+	}
+}
+
+func (s *ScopeSchema) checkNotLocked(change string) {
+	if s.locked {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot %s on scope with root %s, the scope is locked", change, s.RootValue),
+		})
+	}
+}
+
+func (o *ObjectSchema) markLocked() {
+	o.locked = true
+	for _, property := range o.PropertiesValue {
+		property.locked = true
+	}
+}
+
+func (o *ObjectSchema) checkNotLocked(change string) {
+	if o.locked {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot %s on object %s, it is part of a locked scope", change, o.IDValue),
+		})
+	}
+}
+
+func (p *PropertySchema) checkNotLocked(change string) {
+	if p.locked {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("cannot %s on a property of type %s, it is part of a locked scope", change, p.TypeID()),
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type lockTestRoot struct {
+	Name  string        `json:"name"`
+	Child *lockTestItem `json:"child"`
+}
+
+type lockTestItem struct {
+	Size int64 `json:"size"`
+}
+
+func newLockTestChild() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[*lockTestItem]("child", map[string]*schema.PropertySchema{
+		"size": schema.NewPropertySchema(
+			schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, schema.PointerTo("1"), nil,
+		),
+	})
+}
+
+func newLockTestRoot() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[lockTestRoot]("root", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"child": schema.NewPropertySchema(
+			schema.NewRefSchema("child", nil), nil, false, nil, nil, nil, nil, nil,
+		),
+	})
+}
+
+func TestScopeLock(t *testing.T) {
+	root := newLockTestRoot()
+	child := newLockTestChild()
+	scope := schema.NewScopeSchema(root, child).Lock()
+	assert.Equals(t, scope.Locked(), true)
+
+	result := assert.NoErrorR[any](t)(scope.Unserialize(map[string]any{"name": "test", "child": map[string]any{}}))
+	assert.Equals(t, result.(lockTestRoot).Child.Size, int64(1))
+
+	assert.PanicsContains(t, scope.ApplySelf, "cannot apply a namespace on scope with root root")
+	assert.PanicsContains(t, func() {
+		scope.WithCoercionPolicy(schema.StrictCoercionPolicy())
+	}, "locked")
+	assert.PanicsContains(t, func() {
+		root.IgnoreUnknownFields()
+	}, "cannot ignore unknown fields on object root")
+	assert.PanicsContains(t, func() {
+		child.PropertiesValue["size"].TreatEmptyAsDefaultValue()
+	}, "cannot treat empty values as the default on a property of type integer")
+	assert.PanicsContains(t, func() {
+		scope.Compile()
+	}, "cannot compile on object")
+
+	// The scope still works after the rejected changes.
+	assert.NoErrorR[any](t)(scope.Unserialize(map[string]any{"name": "test"}))
+}
+
+func TestScopeLockSharedObjects(t *testing.T) {
+	root := newLockTestRoot()
+	child := newLockTestChild()
+	schema.NewScopeSchema(root, child).Lock()
+
+	// A locked object can be shared with scopes built later, as long as its references are not relinked.
+	other := schema.NewScopeSchema(root, child)
+	assert.NoErrorR[any](t)(other.Unserialize(map[string]any{"name": "test"}))
+
+	assert.PanicsContains(t, func() {
+		schema.NewScopeSchema(root, newLockTestChild())
+	}, "cannot link reference to object child")
+}
+
+func TestScopeLockUnlinked(t *testing.T) {
+	serialized := assert.NoErrorR[any](t)(schema.NewScopeSchema(newLockTestRoot(), newLockTestChild()).SelfSerialize())
+	unserialized := assert.NoErrorR[*schema.ScopeSchema](t)(schema.UnserializeScope(serialized))
+	assert.PanicsContains(t, func() {
+		unserialized.Lock()
+	}, "before its references are linked")
+
+	unserialized.ApplySelf()
+	unserialized.Lock()
+	assert.NoErrorR[any](t)(unserialized.Unserialize(map[string]any{"name": "test"}))
+}
+
+func TestCallableSchemaLock(t *testing.T) {
+	input := schema.NewScopeSchema(newLockTestRoot(), newLockTestChild())
+	output := schema.NewScopeSchema(newLockTestRoot(), newLockTestChild())
+	callable := schema.NewCallableSchema(
+		schema.NewCallableStep[lockTestRoot](
+			"step",
+			input,
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(output, nil, false),
+			},
+			nil,
+			func(_ context.Context, input lockTestRoot) (string, any) {
+				return "success", input
+			},
+		),
+	).Lock()
+	assert.Equals(t, input.Locked(), true)
+	assert.Equals(t, output.Locked(), true)
+
+	outputID, _, err := callable.CallStep(context.Background(), "run", "step", map[string]any{"name": "test"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, "success")
+}
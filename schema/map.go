@@ -3,6 +3,7 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 )
 
 // Map holds the schema definition for key-value associations. This dataclass only has the ability to hold the
@@ -19,6 +20,16 @@ type Map[KeyType Type, ValueType Type] interface {
 // UntypedMap is a map schema without specific underlying types.
 type UntypedMap = Map[Type, Type]
 
+// untypedMapSchema is satisfied by every map schema regardless of its key and value type parameters.
+type untypedMapSchema interface {
+	Type
+	keyType() Type
+	valueType() Type
+	Min() *int64
+	Max() *int64
+	newUnserializedMap(size int) reflect.Value
+}
+
 // TypedMap is a map schema that can be unserialized in its underlying components.
 type TypedMap[KeyType comparable, ValueType any] interface {
 	TypedType[map[KeyType]ValueType]
@@ -27,6 +38,25 @@ type TypedMap[KeyType comparable, ValueType any] interface {
 
 // NewMapSchema creates a new map schema.
 func NewMapSchema(keys Type, values Type, min *int64, max *int64) *MapSchema[Type, Type] {
+	validateMapKeyType(keys)
+	return &MapSchema[Type, Type]{
+		KeysValue:   keys,
+		ValuesValue: values,
+		MinValue:    min,
+		MaxValue:    max,
+	}
+}
+
+// NewOrderedMapSchema creates a new map schema like NewMapSchema that unserializes into an *OrderedMap[any, any]
+// instead of a Go map. Ordered serialized data, such as an OrderedMap read from YAML, keeps its order, while the keys
+// of Go maps are sorted. The map is serialized into an *OrderedMap[any, any] in the same order.
+func NewOrderedMapSchema(keys Type, values Type, min *int64, max *int64) *MapSchema[Type, Type] {
+	m := NewMapSchema(keys, values, min, max)
+	m.orderedType = reflect.TypeOf(&OrderedMap[any, any]{})
+	return m
+}
+
+func validateMapKeyType(keys Type) {
 	switch keys.TypeID() {
 	case TypeIDString:
 	case TypeIDInt:
@@ -34,16 +64,12 @@ func NewMapSchema(keys Type, values Type, min *int64, max *int64) *MapSchema[Typ
 	case TypeIDIntEnum:
 	default:
 		panic(BadArgumentError{
-			Message: fmt.Sprintf("Invalid type ID for map: %s, expected one of: string, int", keys.TypeID()),
+			Message: fmt.Sprintf(
+				"Invalid type ID for map: %s, expected one of: string, int, enum_string, enum_integer",
+				keys.TypeID(),
+			),
 		})
 	}
-
-	return &MapSchema[Type, Type]{
-		keys,
-		values,
-		min,
-		max,
-	}
 }
 
 // MapSchema is the implementation of tye map types.
@@ -52,6 +78,49 @@ type MapSchema[K Type, V Type] struct {
 	ValuesValue V      `json:"values"`
 	MinValue    *int64 `json:"min"`
 	MaxValue    *int64 `json:"max"`
+	// StringKeysValue serializes integer keys as strings, for JSON consumers that only accept string object keys.
+	StringKeysValue bool `json:"string_keys,omitempty"`
+
+	// parallelWorkers is the number of workers unserializing the entries of large maps, or 0 for sequential.
+	parallelWorkers int
+
+	// orderedType is the *OrderedMap type the map unserializes into, or nil for a Go map.
+	orderedType reflect.Type
+}
+
+// SerializeKeysAsStrings is a builder-pattern way of serializing the integer keys of the map as decimal strings, such
+// as "42", for JSON consumers that cannot handle non-string object keys. Unserializing accepts both integers and
+// strings. The choice is recorded in the schema, so the engine and other consumers know the keys are strings. It
+// panics if the keys are not integers or integer enums.
+func (m *MapSchema[K, V]) SerializeKeysAsStrings() *MapSchema[K, V] {
+	validateStringKeysType(m.KeysValue)
+	m.StringKeysValue = true
+	return m
+}
+
+// SerializeKeysAsStrings is a builder-pattern way of serializing the integer keys of the map as strings, see
+// MapSchema.SerializeKeysAsStrings.
+func (m *TypedMapSchema[KeyType, ValueType]) SerializeKeysAsStrings() *TypedMapSchema[KeyType, ValueType] {
+	m.MapSchema.SerializeKeysAsStrings()
+	return m
+}
+
+func validateStringKeysType(keys Type) {
+	switch keys.TypeID() {
+	case TypeIDInt, TypeIDIntEnum:
+	default:
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"Only integer keys can be serialized as strings, the keys of this map are %s",
+				keys.TypeID(),
+			),
+		})
+	}
+}
+
+// StringKeys returns whether the integer keys of the map are serialized as strings.
+func (m MapSchema[K, V]) StringKeys() bool {
+	return m.StringKeysValue
 }
 
 func (m MapSchema[K, V]) TypeID() TypeID {
@@ -59,6 +128,9 @@ func (m MapSchema[K, V]) TypeID() TypeID {
 }
 
 func (m MapSchema[K, V]) ReflectedType() reflect.Type {
+	if m.orderedType != nil {
+		return m.orderedType
+	}
 	reflectedKey := m.KeysValue.ReflectedType()
 	reflectedValue := m.ValuesValue.ReflectedType()
 	return reflect.MapOf(reflectedKey, reflectedValue)
@@ -80,9 +152,94 @@ func (m MapSchema[K, V]) Max() *int64 {
 	return m.MaxValue
 }
 
+// entries returns the entries of a Go map or an OrderedMap. If the schema is ordered, the keys of Go maps are sorted so
+// the result is deterministic. The second return value is false if the data is not a map.
+func (m MapSchema[K, V]) entries(data any) ([]mapEntry, bool) {
+	entries, ok := mapEntriesOf(data)
+	if ok && m.orderedType != nil {
+		if _, isOrdered := data.(orderedMap); !isOrdered {
+			sortMapEntries(entries)
+		}
+	}
+	return entries, ok
+}
+
+// newUnserializedMap returns an empty Go map or OrderedMap to unserialize into. Entries are added with
+// setUnserializedEntry.
+func (m MapSchema[K, V]) newUnserializedMap(size int) reflect.Value {
+	if m.orderedType != nil {
+		return reflect.New(m.orderedType.Elem())
+	}
+	return reflect.MakeMapWithSize(m.ReflectedType(), size)
+}
+
+func setUnserializedEntry(result reflect.Value, key any, value any) {
+	if ordered, ok := result.Interface().(orderedMap); ok {
+		ordered.set(key, value)
+		return
+	}
+	result.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+}
+
+// plainMapKey returns the key of a Go map with a named string or integer key type, such as an enum type, as a plain
+// string or int64, so the key schema can unserialize it.
+func plainMapKey(key reflect.Value) any {
+	for key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+	switch key.Kind() {
+	case reflect.String:
+		return key.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return key.Int()
+	default:
+		return key.Interface()
+	}
+}
+
+// convertValue converts the value to the type like reflect.Value.Convert. Maps are also converted if their keys or
+// values need a conversion, so an unserialized map[string]T can be stored in a struct field of a map type with a named
+// key type, such as map[EnumType]T. It panics if the value cannot be converted.
+func convertValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Type().ConvertibleTo(t) || v.Kind() != reflect.Map || t.Kind() != reflect.Map {
+		return v.Convert(t)
+	}
+	if v.IsNil() {
+		return reflect.Zero(t)
+	}
+	result := reflect.MakeMapWithSize(t, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		key := iter.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		value := iter.Value()
+		if value.Kind() == reflect.Interface && !value.IsNil() && t.Elem().Kind() != reflect.Interface {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Interface && value.IsNil() {
+			value = reflect.Zero(t.Elem())
+		} else {
+			value = convertValue(value, t.Elem())
+		}
+		result.SetMapIndex(convertValue(key, t.Key()), value)
+	}
+	return result
+}
+
+// keyType returns the key type without the generic type parameter.
+func (m MapSchema[K, V]) keyType() Type {
+	return m.KeysValue
+}
+
+// valueType returns the value type without the generic type parameter.
+func (m MapSchema[K, V]) valueType() Type {
+	return m.ValuesValue
+}
+
 func (m MapSchema[K, V]) ApplyNamespace(objects map[string]*ObjectSchema, namespace string) {
 	m.KeysValue.ApplyNamespace(objects, namespace)
-	m.ValuesValue.ApplyNamespace(objects, namespace)
+	applyNamespace(m.ValuesValue, objects, namespace)
 }
 
 func (m MapSchema[K, V]) ValidateReferences() error {
@@ -93,43 +250,77 @@ func (m MapSchema[K, V]) ValidateReferences() error {
 	return m.ValuesValue.ValidateReferences()
 }
 
-func (m MapSchema[K, V]) Unserialize(data any) (any, error) {
-	v := reflect.ValueOf(data)
+func (m MapSchema[K, V]) ApplyDefaults(serialized any) any {
+	v := reflect.ValueOf(serialized)
 	if v.Kind() != reflect.Map {
-		return nil, &ConstraintError{
-			Message: fmt.Sprintf("Must be a map, %T given", data),
-		}
+		return serialized
 	}
-
-	if m.MinValue != nil && *m.MinValue > int64(v.Len()) {
-		return nil, &ConstraintError{
-			Message: fmt.Sprintf("Must have at least %d items, %d given", *m.MinValue, v.Len()),
+	result := reflect.MakeMapWithSize(v.Type(), v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		key := iter.Key()
+		value := reflect.ValueOf(ApplyDefaults(m.ValuesValue, iter.Value().Interface()))
+		if !value.IsValid() || !value.Type().AssignableTo(v.Type().Elem()) {
+			value = iter.Value()
 		}
+		result.SetMapIndex(key, value)
 	}
-	if m.MaxValue != nil && *m.MaxValue < int64(v.Len()) {
+	return result.Interface()
+}
+
+func (m MapSchema[K, V]) Unserialize(data any) (any, error) {
+	entries, ok := m.entries(data)
+	if !ok {
 		return nil, &ConstraintError{
-			Message: fmt.Sprintf("Must have at most %d items, %d given", *m.MaxValue, v.Len()),
+			Message:    fmt.Sprintf("Must be a map, %T given", data),
+			Constraint: ConstraintDataType,
+			Expected:   "map",
+			Actual:     fmt.Sprintf("%T", data),
 		}
 	}
 
-	t := m.ReflectedType()
-	result := reflect.MakeMapWithSize(t, v.Len())
-	for _, k := range v.MapKeys() {
-		val := v.MapIndex(k)
+	if err := checkItemCount(m.MinValue, m.MaxValue, len(entries), nil); err != nil {
+		return nil, err
+	}
 
-		unserializedKey, err := m.KeysValue.Unserialize(k.Interface())
+	result := m.newUnserializedMap(len(entries))
+	if m.parallelWorkers > 0 && len(entries) >= ParallelMinItems {
+		unserializedEntries, err := unserializeParallel(len(entries), m.parallelWorkers, func(i int) (any, error) {
+			return m.unserializeEntry(entries[i], itemError)
+		})
 		if err != nil {
-			return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("{%v}", k.Interface()))
+			return nil, err
+		}
+		for _, entry := range unserializedEntries {
+			pair := entry.([2]any)
+			setUnserializedEntry(result, pair[0], pair[1])
 		}
-		unserializedValue, err := m.ValuesValue.Unserialize(val.Interface())
+		return result.Interface(), nil
+	}
+	for _, entry := range entries {
+		pair, err := m.unserializeEntry(entry, ConstraintErrorAddPathSegment)
 		if err != nil {
-			return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%v]", k.Interface()))
+			return nil, err
 		}
-		result.SetMapIndex(reflect.ValueOf(unserializedKey), reflect.ValueOf(unserializedValue))
+		setUnserializedEntry(result, pair[0], pair[1])
 	}
 	return result.Interface(), nil
 }
 
+// unserializeEntry returns the unserialized key and value of the entry. The addPath function labels the errors with
+// the key of the entry.
+func (m MapSchema[K, V]) unserializeEntry(entry mapEntry, addPath func(error, string) error) ([2]any, error) {
+	k := entry.key
+	unserializedKey, err := m.KeysValue.Unserialize(plainMapKey(k))
+	if err != nil {
+		return [2]any{}, addPath(err, fmt.Sprintf("{%v}", k.Interface()))
+	}
+	unserializedValue, err := m.ValuesValue.Unserialize(entry.value.Interface())
+	if err != nil {
+		return [2]any{}, addPath(err, fmt.Sprintf("[%v]", k.Interface()))
+	}
+	return [2]any{unserializedKey, unserializedValue}, nil
+}
+
 func (m MapSchema[K, V]) validateSchemaCompatibility(schemaType Type) error {
 	if schemaType.TypeID() != TypeIDMap {
 		return &ConstraintError{
@@ -190,28 +381,35 @@ func (m MapSchema[K, V]) ValidateCompatibility(typeOrData any) error {
 		return m.validateSchemaCompatibility(schemaType)
 	}
 	// It's not a schema type, so now check if it's an actual map
-	v := reflect.ValueOf(typeOrData)
-	if v.Kind() != reflect.Map {
+	entries, ok := m.entries(typeOrData)
+	if !ok {
 		return &ConstraintError{
 			Message: fmt.Sprintf("Must be a map or map schema, %T given", typeOrData),
 		}
 	}
-	if m.MinValue != nil && *m.MinValue > int64(v.Len()) {
+	if m.MinValue != nil && *m.MinValue > int64(len(entries)) {
 		return &ConstraintError{
-			Message: fmt.Sprintf("Must have at least %d items, %d given", *m.MinValue, v.Len()),
+			Message:    fmt.Sprintf("Must have at least %d items, %d given", *m.MinValue, len(entries)),
+			Constraint: ConstraintMin,
+			Expected:   *m.MinValue,
+			Actual:     int64(len(entries)),
 		}
 	}
-	if m.MaxValue != nil && *m.MaxValue < int64(v.Len()) {
+	if m.MaxValue != nil && *m.MaxValue < int64(len(entries)) {
 		return &ConstraintError{
-			Message: fmt.Sprintf("Must have at most %d items, %d given", *m.MaxValue, v.Len()),
+			Message:    fmt.Sprintf("Must have at most %d items, %d given", *m.MaxValue, len(entries)),
+			Constraint: ConstraintMax,
+			Expected:   *m.MaxValue,
+			Actual:     int64(len(entries)),
 		}
 	}
 
-	for _, k := range v.MapKeys() {
+	for _, entry := range entries {
+		k := entry.key
 		if err := m.KeysValue.ValidateCompatibility(k.Interface()); err != nil {
 			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("{%v}", k))
 		}
-		if err := m.ValuesValue.ValidateCompatibility(v.MapIndex(k).Interface()); err != nil {
+		if err := m.ValuesValue.ValidateCompatibility(entry.value.Interface()); err != nil {
 			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%v]", k))
 		}
 	}
@@ -219,29 +417,26 @@ func (m MapSchema[K, V]) ValidateCompatibility(typeOrData any) error {
 }
 
 func (m MapSchema[K, V]) Validate(data any) error {
-	v := reflect.ValueOf(data)
-	if v.Kind() != reflect.Map {
+	entries, ok := m.entries(data)
+	if !ok {
 		return &ConstraintError{
-			Message: fmt.Sprintf("Must be a map, %T given", data),
+			Message:    fmt.Sprintf("Must be a map, %T given", data),
+			Constraint: ConstraintDataType,
+			Expected:   "map",
+			Actual:     fmt.Sprintf("%T", data),
 		}
 	}
 
-	if m.MinValue != nil && *m.MinValue > int64(v.Len()) {
-		return &ConstraintError{
-			Message: fmt.Sprintf("Must have at least %d items, %d given", *m.MinValue, v.Len()),
-		}
-	}
-	if m.MaxValue != nil && *m.MaxValue < int64(v.Len()) {
-		return &ConstraintError{
-			Message: fmt.Sprintf("Must have at most %d items, %d given", *m.MaxValue, v.Len()),
-		}
+	if err := checkItemCount(m.MinValue, m.MaxValue, len(entries), nil); err != nil {
+		return err
 	}
 
-	for _, k := range v.MapKeys() {
+	for _, entry := range entries {
+		k := entry.key
 		if err := m.KeysValue.Validate(k.Interface()); err != nil {
 			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("{%v}", k))
 		}
-		if err := m.ValuesValue.Validate(v.MapIndex(k).Interface()); err != nil {
+		if err := m.ValuesValue.Validate(entry.value.Interface()); err != nil {
 			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%v]", k))
 		}
 	}
@@ -253,18 +448,33 @@ func (m MapSchema[K, V]) Serialize(data any) (any, error) {
 		return nil, err
 	}
 
-	v := reflect.ValueOf(data)
-	result := make(map[any]any, v.Len())
-	for _, k := range v.MapKeys() {
+	entries, _ := m.entries(data)
+	result := make(map[any]any, len(entries))
+	var ordered *OrderedMap[any, any]
+	if m.orderedType != nil {
+		ordered = NewOrderedMap[any, any]()
+	}
+	for _, entry := range entries {
+		k := entry.key
 		serializedKey, err := m.KeysValue.Serialize(k.Interface())
 		if err != nil {
 			return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("{%v}", k))
 		}
-		serializedValue, err := m.ValuesValue.Serialize(v.MapIndex(k).Interface())
+		if intKey, isInt := serializedKey.(int64); isInt && m.StringKeysValue {
+			serializedKey = strconv.FormatInt(intKey, 10)
+		}
+		serializedValue, err := m.ValuesValue.Serialize(entry.value.Interface())
 		if err != nil {
 			return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%v]", k))
 		}
-		result[serializedKey] = serializedValue
+		if ordered != nil {
+			ordered.Set(serializedKey, serializedValue)
+		} else {
+			result[serializedKey] = serializedValue
+		}
+	}
+	if ordered != nil {
+		return ordered, nil
 	}
 	return result, nil
 }
@@ -276,23 +486,13 @@ func NewTypedMapSchema[KeyType comparable, ValueType any](
 	min *int64,
 	max *int64,
 ) *TypedMapSchema[KeyType, ValueType] {
-	switch keys.TypeID() {
-	case TypeIDString:
-	case TypeIDInt:
-	case TypeIDStringEnum:
-	case TypeIDIntEnum:
-	default:
-		panic(BadArgumentError{
-			Message: fmt.Sprintf("Invalid type ID for map: %s, expected one of: string, int", keys.TypeID()),
-		})
-	}
-
+	validateMapKeyType(keys)
 	return &TypedMapSchema[KeyType, ValueType]{
 		MapSchema[TypedType[KeyType], TypedType[ValueType]]{
-			keys,
-			values,
-			min,
-			max,
+			KeysValue:   keys,
+			ValuesValue: values,
+			MinValue:    min,
+			MaxValue:    max,
 		},
 	}
 }
@@ -316,3 +516,47 @@ func (m TypedMapSchema[KeyType, ValueType]) ValidateType(data map[KeyType]ValueT
 func (m TypedMapSchema[KeyType, ValueType]) SerializeType(data map[KeyType]ValueType) (any, error) {
 	return m.Serialize(data)
 }
+
+// NewTypedOrderedMapSchema creates a new map schema like NewTypedMapSchema that unserializes into an
+// *OrderedMap[KeyType, ValueType] instead of a Go map. See NewOrderedMapSchema for how the order is kept.
+func NewTypedOrderedMapSchema[KeyType comparable, ValueType any](
+	keys TypedType[KeyType],
+	values TypedType[ValueType],
+	min *int64,
+	max *int64,
+) *TypedOrderedMapSchema[KeyType, ValueType] {
+	validateMapKeyType(keys)
+
+	return &TypedOrderedMapSchema[KeyType, ValueType]{
+		MapSchema[TypedType[KeyType], TypedType[ValueType]]{
+			KeysValue:   keys,
+			ValuesValue: values,
+			MinValue:    min,
+			MaxValue:    max,
+			orderedType: reflect.TypeOf(&OrderedMap[KeyType, ValueType]{}),
+		},
+	}
+}
+
+// TypedOrderedMapSchema is the typed variant of the ordered map schema.
+type TypedOrderedMapSchema[KeyType comparable, ValueType any] struct {
+	MapSchema[TypedType[KeyType], TypedType[ValueType]]
+}
+
+func (m TypedOrderedMapSchema[KeyType, ValueType]) UnserializeType(
+	data any,
+) (result *OrderedMap[KeyType, ValueType], err error) {
+	unserialized, err := m.Unserialize(data)
+	if err != nil {
+		return result, err
+	}
+	return unserialized.(*OrderedMap[KeyType, ValueType]), nil
+}
+
+func (m TypedOrderedMapSchema[KeyType, ValueType]) ValidateType(data *OrderedMap[KeyType, ValueType]) error {
+	return m.Validate(data)
+}
+
+func (m TypedOrderedMapSchema[KeyType, ValueType]) SerializeType(data *OrderedMap[KeyType, ValueType]) (any, error) {
+	return m.Serialize(data)
+}
@@ -1,8 +1,11 @@
 package schema_test
 
 import (
+	"errors"
 	"go.arcalot.io/assert"
 	"go.flow.arcalot.io/pluginsdk/schema/testdata"
+	"math"
+	"regexp"
 	"testing"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
@@ -415,3 +418,235 @@ func TestMap_AliasedTypes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equals[map[any]any](t, serializedOutput.(map[any]any), serializedInput)
 }
+
+func TestMapNilValue(t *testing.T) {
+	s := schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil)
+	err := s.Validate(map[string]any{"a": nil})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[a]")
+	_, err = s.Serialize(map[string]any{"a": nil})
+	assert.Error(t, err)
+	assert.Error(t, s.Validate(map[any]any{nil: int64(1)}))
+}
+
+func TestMapNaNKey(t *testing.T) {
+	s := schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil)
+	// The key is converted to a string, so it can be looked up after unserialization.
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[any]any{math.NaN(): 1}))
+	assert.Equals(t, unserialized.(map[string]int64)["NaN"], int64(1))
+	result := s.ApplyDefaults(map[any]any{math.NaN(): 1})
+	assert.Equals(t, len(result.(map[any]any)), 1)
+}
+
+type constrainedKeysTestStruct struct {
+	Destinations map[string]string `json:"destinations"`
+	Limits       map[string]int64  `json:"limits"`
+}
+
+func newConstrainedKeysTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewStructMappedObjectSchema[constrainedKeysTestStruct]("test", map[string]*schema.PropertySchema{
+			"destinations": schema.NewPropertySchema(
+				schema.NewMapSchema(
+					schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+						"info":  schema.NewDisplayValue(schema.PointerTo("Info"), nil, nil),
+						"error": schema.NewDisplayValue(schema.PointerTo("Error"), nil, nil),
+					}),
+					schema.NewStringSchema(nil, nil, nil),
+					nil,
+					nil,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"limits": schema.NewPropertySchema(
+				schema.NewMapSchema(
+					schema.NewStringSchema(nil, nil, regexp.MustCompile("^[a-z]+_max$")),
+					schema.NewIntSchema(nil, nil, nil),
+					nil,
+					nil,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+	)
+}
+
+func TestMapConstrainedKeys(t *testing.T) {
+	scope := newConstrainedKeysTestScope()
+	result := assert.NoErrorR[any](t)(scope.Unserialize(map[string]any{
+		"destinations": map[string]any{"info": "stdout", "error": "stderr"},
+		"limits":       map[string]any{"memory_max": 1},
+	}))
+	assert.Equals(t, result.(constrainedKeysTestStruct).Destinations["error"], "stderr")
+
+	_, err := scope.Unserialize(map[string]any{"destinations": map[string]any{"trace": "stdout"}})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path, []string{"destinations", "{trace}"})
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintEnum)
+
+	_, err = scope.Unserialize(map[string]any{"limits": map[string]any{"memory": 1}})
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path, []string{"limits", "{memory}"})
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintPattern)
+
+	_, err = schema.UnserializeAll(scope, map[string]any{
+		"destinations": map[string]any{"trace": "stdout"},
+		"limits":       map[string]any{"memory": 1},
+	})
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 2)
+
+	assert.Panics(t, func() {
+		schema.NewMapSchema(schema.NewBoolSchema(), schema.NewStringSchema(nil, nil, nil), nil, nil)
+	})
+}
+
+func TestMapConstrainedKeysSelfSerialize(t *testing.T) {
+	serialized := assert.NoErrorR[any](t)(newConstrainedKeysTestScope().SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	_, err := unserializedScope.Unserialize(map[string]any{"destinations": map[string]any{"trace": "stdout"}})
+	assert.Error(t, err)
+	_, err = unserializedScope.Unserialize(map[string]any{"limits": map[string]any{"memory": 1}})
+	assert.Error(t, err)
+	assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{
+		"destinations": map[string]any{"info": "stdout"},
+		"limits":       map[string]any{"memory_max": 1},
+	}))
+}
+
+type namedIntKey int32
+
+type namedKeysTestStruct struct {
+	Levels  map[TypedStringEnumTestType]string `json:"levels"`
+	Retries map[namedIntKey]int64              `json:"retries"`
+}
+
+func TestMapNamedKeyTypes(t *testing.T) {
+	// Maps with named key types are serialized and unserialized against untyped key schemas.
+	object := schema.NewStructMappedObjectSchema[namedKeysTestStruct]("test", map[string]*schema.PropertySchema{
+		"levels": schema.NewPropertySchema(
+			schema.NewMapSchema(
+				schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+					string(testA): nil,
+					string(testB): nil,
+				}),
+				schema.NewStringSchema(nil, nil, nil),
+				nil,
+				nil,
+			),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"retries": schema.NewPropertySchema(
+			schema.NewMapSchema(schema.NewIntSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	})
+	data := namedKeysTestStruct{
+		Levels:  map[TypedStringEnumTestType]string{testA: "info"},
+		Retries: map[namedIntKey]int64{5: 3},
+	}
+	serialized := assert.NoErrorR[any](t)(object.Serialize(data))
+	assert.Equals(t, serialized.(map[string]any)["levels"].(map[any]any), map[any]any{"testA": "info"})
+	assert.Equals(t, serialized.(map[string]any)["retries"].(map[any]any), map[any]any{int64(5): int64(3)})
+
+	// Keys read from JSON or YAML arrive as strings.
+	unserialized := assert.NoErrorR[any](t)(object.Unserialize(map[string]any{
+		"levels":  map[string]any{"testA": "info"},
+		"retries": map[string]any{"5": 3},
+	}))
+	assert.Equals(t, unserialized.(namedKeysTestStruct), data)
+
+	mapType := schema.NewMapSchema(schema.NewIntSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil)
+	unserializedMap := assert.NoErrorR[any](t)(mapType.Unserialize(map[namedIntKey]int64{5: 3}))
+	assert.Equals(t, unserializedMap.(map[int64]int64), map[int64]int64{5: 3})
+}
+
+func TestMapStringKeys(t *testing.T) {
+	m := schema.NewMapSchema(
+		schema.NewIntSchema(nil, nil, nil),
+		schema.NewStringSchema(nil, nil, nil),
+		nil,
+		nil,
+	).SerializeKeysAsStrings()
+	assert.Equals(t, m.StringKeys(), true)
+
+	// Both integer and string keys are accepted.
+	unserialized := assert.NoErrorR[any](t)(m.Unserialize(map[any]any{int64(1): "a", "2": "b"}))
+	assert.Equals(t, unserialized.(map[int64]string), map[int64]string{1: "a", 2: "b"})
+	_, err := m.Unserialize(map[string]any{"two": "b"})
+	assert.Error(t, err)
+
+	serialized := assert.NoErrorR[any](t)(m.Serialize(unserialized))
+	assert.Equals(t, serialized.(map[any]any), map[any]any{"1": "a", "2": "b"})
+
+	typed := schema.NewTypedMapSchema[int64, string](
+		schema.NewIntSchema(nil, nil, nil),
+		schema.NewStringSchema(nil, nil, nil),
+		nil,
+		nil,
+	).SerializeKeysAsStrings()
+	serialized = assert.NoErrorR[any](t)(typed.SerializeType(map[int64]string{-3: "c"}))
+	assert.Equals(t, serialized.(map[any]any), map[any]any{"-3": "c"})
+
+	assert.Panics(t, func() {
+		schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewStringSchema(nil, nil, nil), nil, nil).
+			SerializeKeysAsStrings()
+	})
+}
+
+func TestMapStringKeysSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"ports": schema.NewPropertySchema(
+			schema.NewMapSchema(
+				schema.NewIntSchema(nil, nil, nil),
+				schema.NewStringSchema(nil, nil, nil),
+				nil,
+				nil,
+			).SerializeKeysAsStrings(),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	ports := unserializedScope.Objects()["test"].Properties()["ports"].Type().(*schema.MapSchema[schema.Type, schema.Type])
+	assert.Equals(t, ports.StringKeys(), true)
+	data := assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"ports": map[string]any{"80": "http"}}))
+	serializedData := assert.NoErrorR[any](t)(unserializedScope.Serialize(data))
+	assert.Equals(t, serializedData.(map[string]any)["ports"].(map[any]any), map[any]any{"80": "http"})
+}
@@ -2,9 +2,14 @@ package schema
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // Object holds the definition for objects comprised of defined fields.
@@ -34,15 +39,59 @@ func NewUnenforcedIDObjectSchema(id string, properties map[string]*PropertySchem
 }
 
 func newObjectSchema(id string, properties map[string]*PropertySchema, unenforcedIDMatch bool) *ObjectSchema {
+	aliases := buildObjectAliases(id, properties)
 	var anyValue any
-	return &ObjectSchema{
-		id,
-		properties,
-		unenforcedIDMatch,
-		extractObjectDefaultValues(properties),
-		nil,
-		reflect.TypeOf(anyValue),
-		nil,
+	o := &ObjectSchema{
+		IDValue:           id,
+		PropertiesValue:   properties,
+		IDUnenforcedValue: unenforcedIDMatch,
+
+		defaultValues: extractObjectDefaultValues(properties),
+
+		defaultValueType: reflect.TypeOf(anyValue),
+
+		aliases: aliases,
+	}
+	o.decodeConditions()
+	return o
+}
+
+// UnknownFieldPolicy determines how an object schema handles keys in the input that don't belong to any property.
+type UnknownFieldPolicy string
+
+const (
+	// UnknownFieldsReject fails unserialization when an unknown key is found. This is the default.
+	UnknownFieldsReject UnknownFieldPolicy = "reject"
+	// UnknownFieldsIgnore drops unknown keys from the input.
+	UnknownFieldsIgnore UnknownFieldPolicy = "ignore"
+	// UnknownFieldsCollect moves unknown keys into a designated map[string]any property.
+	UnknownFieldsCollect UnknownFieldPolicy = "collect"
+)
+
+// KeyMatching determines how input keys are matched to property IDs when unserializing an object.
+type KeyMatching string
+
+const (
+	// KeyMatchingStrict only accepts keys identical to the property ID. This is the default.
+	KeyMatchingStrict KeyMatching = "strict"
+	// KeyMatchingCaseInsensitive accepts keys that only differ from the property ID in case, such as "UserName" for
+	// "username".
+	KeyMatchingCaseInsensitive KeyMatching = "case_insensitive"
+	// KeyMatchingNamingInsensitive accepts keys that only differ from the property ID in case, underscores, and dashes,
+	// so "userName", "user_name", and "user-name" all match each other.
+	KeyMatchingNamingInsensitive KeyMatching = "naming_insensitive"
+)
+
+var namingSeparatorRemover = strings.NewReplacer("_", "", "-", "")
+
+func (k KeyMatching) normalize(key string) string {
+	switch k {
+	case KeyMatchingCaseInsensitive:
+		return strings.ToLower(key)
+	case KeyMatchingNamingInsensitive:
+		return strings.ToLower(namingSeparatorRemover.Replace(key))
+	default:
+		return key
 	}
 }
 
@@ -51,24 +100,284 @@ type ObjectSchema struct {
 	IDValue           string                     `json:"id"`
 	PropertiesValue   map[string]*PropertySchema `json:"properties"`
 	IDUnenforcedValue bool                       `json:"id_unenforced"`
+	// UnknownFieldsValue is the policy for input keys that don't belong to any property. Empty means reject.
+	UnknownFieldsValue UnknownFieldPolicy `json:"unknown_fields,omitempty"`
+	// CatchAllPropertyValue is the property receiving the unknown keys if UnknownFieldsValue is collect.
+	CatchAllPropertyValue string `json:"catch_all_property,omitempty"`
+	// KeyMatchingValue determines how input keys are matched to property IDs. Empty means strict.
+	KeyMatchingValue KeyMatching `json:"key_matching,omitempty"`
+	// ValuePropertyValue is set on wrappers of non-object one-of variants. The object unserializes to the value of
+	// this property instead of a map.
+	ValuePropertyValue string `json:"value_property,omitempty"`
+	// CapturePatternValue is the pattern string inputs are parsed with. The named groups of the pattern set the
+	// properties of the same name.
+	CapturePatternValue *regexp.Regexp `json:"capture_pattern,omitempty"`
 
 	defaultValues map[string]any // Key: Object field name, value: The default value
 
 	defaultValue     any
 	defaultValueType reflect.Type
 	fieldCache       map[string]reflect.StructField
+
+	aliases        map[string]string // Key: property alias, value: property ID
+	normalizedKeys map[string]string // Key: normalized property ID, value: property ID
+	validators     []func(any) error
+	conditions     map[string][]decodedCondition // Key: property ID, value: decoded RequiredIfConditionsValue
+	compiled       compiledObject
+	locked         bool
+}
+
+// compiledObject holds the structures derived from the properties of an object to speed up converting its values.
+// Each of them is only set if enabled: the decode plan by UseDecodePlan, the property index by UsePropertyIndex, the
+// struct fields by Compile, and the scope cache by applying the namespace of the scope the object is in.
+type compiledObject struct {
+	plan   *decodePlan
+	index  *propertyIndex
+	fields *compiledStruct
+	// scope is shared with the other objects of the scope the object was last applied in.
+	scope *scopeTypeCache
+}
+
+// WithValidator registers a function that checks invariants spanning multiple fields, such as one timestamp being
+// after another. The validator runs after all properties have been unserialized, as well as on Validate. The type
+// parameter must match the type the object unserializes to. If the validator returns a ConstraintError, it is passed
+// through as-is so the validator can point to the offending field.
+func WithValidator[T any](o *ObjectSchema, validator func(obj T) error) *ObjectSchema {
+	o.checkNotLocked("add a validator")
+	expectedType := reflect.TypeOf((*T)(nil)).Elem()
+	if expectedType != o.ReflectedType() {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"validator type %s does not match the type %s of object %s",
+				expectedType.String(),
+				o.ReflectedType().String(),
+				o.IDValue,
+			),
+		})
+	}
+	o.validators = append(o.validators, func(obj any) error {
+		return validator(obj.(T))
+	})
+	return o
+}
+
+func (o *ObjectSchema) runValidators(data any) error {
+	for _, validator := range o.validators {
+		if err := validator(data); err != nil {
+			var c *ConstraintError
+			if errors.As(err, &c) {
+				return err
+			}
+			return &ConstraintError{
+				Message:    "Object validation failed",
+				Cause:      err,
+				Constraint: ConstraintCustom,
+			}
+		}
+	}
+	return nil
+}
+
+// IgnoreUnknownFields is a builder-pattern way of dropping input keys that don't belong to any property instead of
+// rejecting them. This is useful for wrapping third-party tools whose configuration format may gain new fields.
+func (o *ObjectSchema) IgnoreUnknownFields() *ObjectSchema {
+	o.checkNotLocked("ignore unknown fields")
+	o.UnknownFieldsValue = UnknownFieldsIgnore
+	o.CatchAllPropertyValue = ""
+	return o
+}
+
+// CollectUnknownFields is a builder-pattern way of moving input keys that don't belong to any property into the
+// specified property, which must be a map with string keys. Keys explicitly set in the catch-all property take
+// precedence over unknown keys with the same name.
+func (o *ObjectSchema) CollectUnknownFields(propertyID string) *ObjectSchema {
+	o.checkNotLocked("collect unknown fields")
+	property, ok := o.PropertiesValue[propertyID]
+	if !ok {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("catch-all property %s does not exist on object %s", propertyID, o.IDValue),
+			Path:    []string{o.IDValue, propertyID},
+		})
+	}
+	if property.TypeID() != TypeIDMap || property.ReflectedType().Key().Kind() != reflect.String {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"catch-all property %s on object %s must be a map with string keys, %s given",
+				propertyID,
+				o.IDValue,
+				property.TypeID(),
+			),
+			Path: []string{o.IDValue, propertyID},
+		})
+	}
+	o.UnknownFieldsValue = UnknownFieldsCollect
+	o.CatchAllPropertyValue = propertyID
+	return o
+}
+
+// MatchKeys is a builder-pattern way of accepting input keys that don't exactly match a property ID, which eases
+// ingesting payloads produced by systems with different naming conventions. Setting the same property via two different
+// keys fails unserialization. It panics if two properties can no longer be told apart with the given matching.
+func (o *ObjectSchema) MatchKeys(matching KeyMatching) *ObjectSchema {
+	o.checkNotLocked("change the key matching")
+	o.KeyMatchingValue = matching
+	o.buildNormalizedKeys()
+	return o
+}
+
+// buildNormalizedKeys builds the lookup of normalized keys for the key matching of the object.
+func (o *ObjectSchema) buildNormalizedKeys() {
+	matching := o.KeyMatching()
+	o.normalizedKeys = nil
+	if matching == KeyMatchingStrict {
+		return
+	}
+	normalizedKeys := make(map[string]string, len(o.PropertiesValue))
+	for propertyID, property := range o.PropertiesValue {
+		for _, key := range append([]string{propertyID}, property.AliasesValue...) {
+			normalizedKey := matching.normalize(key)
+			if existing, ok := normalizedKeys[normalizedKey]; ok && existing != propertyID {
+				panic(BadArgumentError{
+					Message: fmt.Sprintf(
+						"properties %s and %s on object %s cannot be told apart with %s key matching",
+						existing,
+						propertyID,
+						o.IDValue,
+						matching,
+					),
+				})
+			}
+			normalizedKeys[normalizedKey] = propertyID
+		}
+	}
+	o.normalizedKeys = normalizedKeys
+}
+
+// WithCapturePattern is a builder-pattern way of accepting a string in place of the object. The string must match
+// the pattern, and each named group that participates in the match sets the property of the same name. The
+// captured values are then unserialized like any other input, so a pattern such as
+// `^(?P<host>[^:]+):(?P<port>\d+)$` can fill in a string host and an integer port from "localhost:8080". It panics if
+// the pattern has no named groups or a group doesn't match a property.
+func (o *ObjectSchema) WithCapturePattern(pattern *regexp.Regexp) *ObjectSchema {
+	o.checkNotLocked("set a capture pattern")
+	hasNamedGroups := false
+	for _, name := range pattern.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		hasNamedGroups = true
+		if _, ok := o.PropertiesValue[name]; !ok {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf(
+					"capture group %s of pattern %s does not match a property on object %s",
+					name,
+					pattern.String(),
+					o.IDValue,
+				),
+			})
+		}
+	}
+	if !hasNamedGroups {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("capture pattern %s for object %s has no named groups", pattern.String(), o.IDValue),
+		})
+	}
+	o.CapturePatternValue = pattern
+	return o
+}
+
+// CapturePattern returns the pattern string inputs are parsed with, or nil if the object only accepts maps.
+func (o *ObjectSchema) CapturePattern() *regexp.Regexp {
+	return o.CapturePatternValue
+}
+
+// captureToMap parses the string input with the capture pattern into the raw property values.
+func (o *ObjectSchema) captureToMap(data string) (map[string]any, error) {
+	pattern := o.CapturePatternValue
+	match := pattern.FindStringSubmatchIndex(data)
+	if match == nil {
+		return nil, &ConstraintError{
+			Message:    fmt.Sprintf("String '%s' must match the pattern '%s'", data, pattern.String()),
+			Constraint: ConstraintPattern,
+			Expected:   pattern.String(),
+			Actual:     describeValue(data),
+		}
+	}
+	result := map[string]any{}
+	for i, name := range pattern.SubexpNames() {
+		// Groups that don't participate in the match are left unset, so the property defaults apply.
+		if name == "" || match[2*i] < 0 {
+			continue
+		}
+		result[name] = data[match[2*i]:match[2*i+1]]
+	}
+	return result, nil
+}
+
+// KeyMatching returns how input keys are matched to property IDs.
+func (o *ObjectSchema) KeyMatching() KeyMatching {
+	if o.KeyMatchingValue == "" {
+		return KeyMatchingStrict
+	}
+	return o.KeyMatchingValue
+}
+
+// resolveKey returns the property ID the input key belongs to, if any.
+func (o *ObjectSchema) resolveKey(key string) (string, bool) {
+	if _, ok := o.PropertiesValue[key]; ok {
+		return key, true
+	}
+	if propertyID, ok := o.aliases[key]; ok {
+		return propertyID, true
+	}
+	if o.normalizedKeys == nil {
+		return "", false
+	}
+	propertyID, ok := o.normalizedKeys[o.KeyMatchingValue.normalize(key)]
+	return propertyID, ok
+}
+
+// duplicateKeyError returns an error for a key matching a property that is already set by a different key.
+func (o *ObjectSchema) duplicateKeyError(key string, propertyID string) *ConstraintError {
+	return &ConstraintError{
+		Message:    fmt.Sprintf("Key '%s' sets the property '%s', which is already set", key, propertyID),
+		Path:       []string{propertyID},
+		Constraint: ConstraintConflicts,
+		Actual:     key,
+	}
+}
+
+// UnknownFields returns the policy for input keys that don't belong to any property.
+func (o *ObjectSchema) UnknownFields() UnknownFieldPolicy {
+	if o.UnknownFieldsValue == "" {
+		return UnknownFieldsReject
+	}
+	return o.UnknownFieldsValue
+}
+
+// CatchAllProperty returns the property receiving the input keys that don't belong to any property, or an empty
+// string if unknown keys are not collected.
+func (o *ObjectSchema) CatchAllProperty() string {
+	return o.CatchAllPropertyValue
 }
 
 func (o *ObjectSchema) ReflectedType() reflect.Type {
+	if o.ValuePropertyValue != "" {
+		return o.PropertiesValue[o.ValuePropertyValue].ReflectedType()
+	}
 	if o.fieldCache != nil {
 		return o.defaultValueType
 	}
 	return reflect.TypeOf(map[string]any{})
 }
 
+// GetDefaults returns the parsed default values of the properties. The returned map is shared and must not be
+// modified.
 func (o *ObjectSchema) GetDefaults() map[string]any {
 	if o.defaultValues == nil {
-		o.defaultValues = extractObjectDefaultValues(o.PropertiesValue)
+		// The object was created without a constructor and no namespace was applied. Storing the defaults here would
+		// race with concurrent readers, so they are parsed on every call.
+		return extractObjectDefaultValues(o.PropertiesValue)
 	}
 	return o.defaultValues
 }
@@ -81,6 +390,75 @@ func (o *ObjectSchema) ApplyNamespace(objects map[string]*ObjectSchema, namespac
 	for _, property := range o.PropertiesValue {
 		property.ApplyNamespace(objects, namespace)
 	}
+	if o.locked {
+		// The references of locked objects only check that they still resolve to the same objects.
+		return
+	}
+	// The conditions may not have been decoded yet if they depend on references.
+	o.decodeConditions()
+	if o.aliases == nil {
+		// The object was created without a constructor, for example by unserializing a schema.
+		o.aliases = buildObjectAliases(o.IDValue, o.PropertiesValue)
+		o.buildNormalizedKeys()
+	}
+	if o.defaultValues == nil {
+		o.defaultValues = extractObjectDefaultValues(o.PropertiesValue)
+	}
+}
+
+func (o *ObjectSchema) ApplyDefaults(serialized any) any {
+	v := reflect.ValueOf(serialized)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return serialized
+	}
+	data := make(map[string]any, v.Len())
+	for _, key := range v.MapKeys() {
+		data[key.String()] = v.MapIndex(key).Interface()
+	}
+	return o.ApplyObjectDefaults(data)
+}
+
+// ApplyObjectDefaults returns a copy of the serialized object with the defaults of all unset properties filled in
+// recursively, producing the effective configuration the plugin will receive. Keys that don't belong to any property
+// are copied as-is.
+func (o *ObjectSchema) ApplyObjectDefaults(serialized map[string]any) map[string]any {
+	result := make(map[string]any, len(o.PropertiesValue))
+	for key, value := range serialized {
+		if property, ok := o.PropertiesValue[key]; ok {
+			result[key] = property.ApplyDefaults(value)
+		} else {
+			result[key] = copySerializedValue(value)
+		}
+	}
+	for propertyID, property := range o.PropertiesValue {
+		if _, isSet := result[propertyID]; isSet {
+			continue
+		}
+		if defaultValue, ok := o.GetDefaults()[propertyID]; ok {
+			result[propertyID] = property.ApplyDefaults(typedDefaultValue(property, defaultValue))
+		} else if o.fieldCache != nil && o.propertyTypeInfo(property).subObject != nil {
+			// Struct-mapped objects also fill in the defaults of non-pointer sub-objects, see
+			// applySubObjectDefaultValues.
+			if subObject, ok := property.ApplyDefaults(map[string]any{}).(map[string]any); ok && len(subObject) > 0 {
+				result[propertyID] = subObject
+			}
+		}
+	}
+	return result
+}
+
+// typedDefaultValue returns the default value decoded from JSON in the serialized form of the property type, for
+// example as an int64 instead of a float64. If the default does not match the type, it is returned as decoded.
+func typedDefaultValue(property *PropertySchema, defaultValue any) any {
+	unserialized, err := property.TypeValue.Unserialize(defaultValue)
+	if err != nil {
+		return copySerializedValue(defaultValue)
+	}
+	serialized, err := property.TypeValue.Serialize(unserialized)
+	if err != nil {
+		return copySerializedValue(defaultValue)
+	}
+	return serialized
 }
 
 func (o *ObjectSchema) ValidateReferences() error {
@@ -108,12 +486,21 @@ func (o *ObjectSchema) Properties() map[string]*PropertySchema {
 func (o *ObjectSchema) Unserialize(data any) (result any, err error) {
 	v := reflect.ValueOf(data)
 	var rawData map[string]any
-	if v.Kind() != reflect.Map {
+	if v.Kind() == reflect.String && o.CapturePatternValue != nil {
+		var captured map[string]any
+		captured, err = o.captureToMap(v.String())
+		if err == nil {
+			rawData, err = o.convertData(reflect.ValueOf(captured))
+		}
+	} else if v.Kind() != reflect.Map {
 		if len(o.Properties()) == 1 {
 			rawData, err = o.unserializeInlinedDataToMap(data)
 		} else {
 			return nil, &ConstraintError{
-				Message: fmt.Sprintf("Must be a map to convert to object, %T given", data),
+				Message:    fmt.Sprintf("Must be a map to convert to object, %T given", data),
+				Constraint: ConstraintDataType,
+				Expected:   "object",
+				Actual:     fmt.Sprintf("%T", data),
 			}
 		}
 	} else {
@@ -125,11 +512,30 @@ func (o *ObjectSchema) Unserialize(data any) (result any, err error) {
 	if err := o.validateFieldInterdependencies(rawData); err != nil {
 		return nil, err
 	}
+	return o.buildResult(rawData)
+}
 
-	if o.fieldCache != nil {
-		return o.unserializeToStruct(rawData)
+// buildResult creates the unserialized object from the unserialized property values and runs the validators.
+func (o *ObjectSchema) buildResult(rawData map[string]any) (result any, err error) {
+	if o.compiled.fields != nil {
+		result, err = o.compiled.fields.unserialize(rawData)
+		if err != nil {
+			return nil, err
+		}
+	} else if o.fieldCache != nil {
+		result, err = o.unserializeToStruct(rawData)
+		if err != nil {
+			return nil, err
+		}
+	} else if o.ValuePropertyValue != "" {
+		result = rawData[o.ValuePropertyValue]
+	} else {
+		result = rawData
 	}
-	return rawData, nil
+	if err := o.runValidators(result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (o *ObjectSchema) unserializeInlinedDataToMap(data any) (map[string]any, error) {
@@ -161,9 +567,14 @@ func (o *ObjectSchema) unserializeToStruct(rawData map[string]any) (any, error)
 		reflectedValue = reflect.New(reflectType.Elem())
 	}
 	for key, value := range rawData {
+		structField, ok := o.fieldCache[key]
+		if !ok {
+			// The property is mapped to an unexported field.
+			continue
+		}
 		val := value
 		elem := reflectedValue.Elem()
-		field := elem.FieldByIndex(o.fieldCache[key].Index)
+		field := fieldByIndexAllocating(elem, structField.Index)
 		f := field
 		v := reflect.ValueOf(val)
 		var recoveredError error
@@ -180,17 +591,17 @@ func (o *ObjectSchema) unserializeToStruct(rawData map[string]any) (any, error)
 			}()
 			if field.Kind() == reflect.Pointer && v.Kind() != reflect.Pointer {
 				f = reflect.New(f.Type().Elem())
-				f.Elem().Set(v.Convert(f.Elem().Type()))
+				f.Elem().Set(convertValue(v, f.Elem().Type()))
 				field.Set(f)
 			} else {
-				f.Set(v.Convert(f.Type()))
+				f.Set(convertValue(v, f.Type()))
 			}
 		}()
 		if recoveredError != nil {
 			return nil, &ConstraintError{
-				"Field cannot be set",
-				[]string{key},
-				recoveredError,
+				Message: "Field cannot be set",
+				Path:    []string{key},
+				Cause:   recoveredError,
 			}
 		}
 	}
@@ -209,7 +620,7 @@ func (o *ObjectSchema) serializeMap(data map[string]any) (any, error) {
 		return nil, err
 	}
 
-	rawSerializedData := map[string]any{}
+	rawSerializedData := make(map[string]any, len(data))
 	for k, v := range data {
 		property, ok := o.PropertiesValue[k]
 		if !ok {
@@ -221,7 +632,7 @@ func (o *ObjectSchema) serializeMap(data map[string]any) (any, error) {
 		}
 		rawSerializedData[k] = serializedValue
 	}
-	return rawSerializedData, nil
+	return o.flattenUnknownFields(rawSerializedData), nil
 }
 
 func (o *ObjectSchema) serializeStruct(data any) (any, error) {
@@ -238,13 +649,20 @@ func (o *ObjectSchema) serializeStruct(data any) (any, error) {
 			Message: fmt.Sprintf("Nil value passed instead of %T", o.defaultValue),
 		}
 	}
-	for propertyID, property := range o.PropertiesValue {
-		a, err := o.extractPropertyValue(propertyID, v, property)
-		if err != nil {
+	if o.compiled.fields != nil {
+		var err error
+		if rawData, err = o.compiled.fields.rawData(v, true); err != nil {
 			return nil, err
 		}
-		if a != nil {
-			rawData[propertyID] = *a
+	} else {
+		for propertyID, property := range o.PropertiesValue {
+			a, err := o.extractPropertyValue(propertyID, v, property)
+			if err != nil {
+				return nil, err
+			}
+			if a != nil {
+				rawData[propertyID] = *a
+			}
 		}
 	}
 
@@ -252,7 +670,39 @@ func (o *ObjectSchema) serializeStruct(data any) (any, error) {
 		return nil, err
 	}
 
-	return rawData, nil
+	return o.flattenUnknownFields(rawData), nil
+}
+
+// flattenUnknownFields moves the collected unknown fields out of the catch-all property of the serialized data, so
+// that serializing restores the keys of the input. Keys that would be read back as a property stay in the catch-all
+// property, since explicitly set keys take precedence when unserializing.
+func (o *ObjectSchema) flattenUnknownFields(serialized map[string]any) map[string]any {
+	if o.UnknownFields() != UnknownFieldsCollect {
+		return serialized
+	}
+	catchAll := reflect.ValueOf(serialized[o.CatchAllPropertyValue])
+	if catchAll.Kind() != reflect.Map || catchAll.Len() == 0 {
+		return serialized
+	}
+	remaining := make(map[any]any, catchAll.Len())
+	for iter := catchAll.MapRange(); iter.Next(); {
+		key, ok := iter.Key().Interface().(string)
+		if !ok {
+			remaining[iter.Key().Interface()] = iter.Value().Interface()
+			continue
+		}
+		if _, isProperty := o.resolveKey(key); isProperty {
+			remaining[key] = iter.Value().Interface()
+			continue
+		}
+		serialized[key] = iter.Value().Interface()
+	}
+	if len(remaining) == 0 {
+		delete(serialized, o.CatchAllPropertyValue)
+	} else {
+		serialized[o.CatchAllPropertyValue] = remaining
+	}
+	return serialized
 }
 
 func (o *ObjectSchema) extractPropertyValue(propertyID string, v reflect.Value, property *PropertySchema) (*any, error) {
@@ -262,12 +712,10 @@ func (o *ObjectSchema) extractPropertyValue(propertyID string, v reflect.Value,
 	}
 	value := valPtr.Interface()
 
-	if property.emptyIsDefault {
-		// Handle the case where the empty value corresponds to the default value.
-		defaultValue := reflect.New(property.ReflectedType()).Elem().Convert(valPtr.Type()).Interface()
-		if defaultValue == value {
-			return nil, nil
-		}
+	// Handle the case where the empty value corresponds to the default value. IsZero also covers types that are not
+	// comparable, such as slices.
+	if property.emptyIsDefault && valPtr.IsZero() {
+		return nil, nil
 	}
 
 	serializedData, err := property.Serialize(value)
@@ -278,18 +726,23 @@ func (o *ObjectSchema) extractPropertyValue(propertyID string, v reflect.Value,
 }
 
 func (o *ObjectSchema) getFieldReflection(propertyID string, v reflect.Value, property *PropertySchema) *reflect.Value {
-	field := o.fieldCache[propertyID]
-	var val reflect.Value
+	field, ok := o.fieldCache[propertyID]
+	if !ok {
+		return nil
+	}
 	if v.Kind() == reflect.Pointer {
-		val = v.Elem().FieldByName(field.Name)
-	} else {
-		val = v.FieldByName(field.Name)
+		v = v.Elem()
+	}
+	val, err := v.FieldByIndexErr(field.Index)
+	if err != nil {
+		// A nil embedded pointer means none of its promoted fields are set.
+		return nil
 	}
 	if val.Kind() == reflect.Pointer {
 		if val.IsNil() {
 			return nil
 		}
-		if property.ReflectedType().Kind() != reflect.Pointer {
+		if o.propertyTypeInfo(property).reflectedType.Kind() != reflect.Pointer {
 			val = val.Elem()
 		}
 	}
@@ -300,6 +753,9 @@ func (o *ObjectSchema) getFieldReflection(propertyID string, v reflect.Value, pr
 }
 
 func (o *ObjectSchema) Serialize(data any) (any, error) {
+	if o.ValuePropertyValue != "" {
+		return o.serializeMap(map[string]any{o.ValuePropertyValue: data})
+	}
 	if o.fieldCache != nil {
 		return o.serializeStruct(data)
 	}
@@ -352,8 +808,22 @@ func (o *ObjectSchema) validateMapTypesCompatibility(data map[string]any) error
 }
 
 func (o *ObjectSchema) validateStruct(data any) error {
+	rawData, err := o.structRawData(data)
+	if err != nil {
+		return err
+	}
+	for propertyID, value := range rawData {
+		if err := o.PropertiesValue[propertyID].Validate(value); err != nil {
+			return ConstraintErrorAddPathSegment(err, propertyID)
+		}
+	}
+	return o.validateFieldInterdependencies(rawData)
+}
+
+// structRawData reads the set property values from the struct the object is mapped to.
+func (o *ObjectSchema) structRawData(data any) (map[string]any, error) {
 	if reflect.TypeOf(data) != o.ReflectedType() {
-		return &ConstraintError{
+		return nil, &ConstraintError{
 			Message: fmt.Sprintf("%T is not a valid data type, expected %s.", data, o.ReflectedType().String()),
 		}
 	}
@@ -362,10 +832,13 @@ func (o *ObjectSchema) validateStruct(data any) error {
 
 	v := reflect.ValueOf(data)
 	if v.Kind() == reflect.Pointer && v.IsNil() {
-		return &ConstraintError{
+		return nil, &ConstraintError{
 			Message: fmt.Sprintf("Nil value passed instead of %T", o.defaultValue),
 		}
 	}
+	if o.compiled.fields != nil {
+		return o.compiled.fields.rawData(v, false)
+	}
 	for propertyID, property := range o.PropertiesValue {
 		valPtr := o.getFieldReflection(propertyID, v, property)
 		if valPtr == nil {
@@ -374,22 +847,30 @@ func (o *ObjectSchema) validateStruct(data any) error {
 		value := valPtr.Interface()
 		if property.emptyIsDefault {
 			// Handle the case where the empty value corresponds to the default value.
-			defaultValue := reflect.New(property.ReflectedType()).Elem().Convert(valPtr.Type()).Interface()
+			defaultValue := reflect.New(o.propertyTypeInfo(property).reflectedType).Elem().Convert(valPtr.Type()).Interface()
 			if reflect.DeepEqual(defaultValue, value) {
 				continue
 			}
 		}
-		if err := property.Validate(value); err != nil {
-			return ConstraintErrorAddPathSegment(err, propertyID)
-		}
 		rawData[propertyID] = value
 	}
-
-	return o.validateFieldInterdependencies(rawData)
+	return rawData, nil
 }
 
 func (o *ObjectSchema) validateSchemaCompatibility(schemaType Object) error {
 	fieldData := map[string]any{}
+	if err := o.validateSchemaID(schemaType); err != nil {
+		return err
+	}
+	// Copy all properties to the variable for validating later.
+	for key, value := range schemaType.Properties() {
+		fieldData[key] = value
+	}
+	// Now validate object fields
+	return o.validateMapTypesCompatibility(fieldData)
+}
+
+func (o *ObjectSchema) validateSchemaID(schemaType Object) error {
 	// Validate IDs if both schemas require it to be enforced.
 	if !schemaType.IDUnenforced() && !o.IDUnenforced() && schemaType.ID() != o.ID() {
 		return &ConstraintError{
@@ -397,12 +878,109 @@ func (o *ObjectSchema) validateSchemaCompatibility(schemaType Object) error {
 				o.ID(), schemaType.ID()),
 		}
 	}
-	// Copy all properties to the variable for validating later.
-	for key, value := range schemaType.Properties() {
-		fieldData[key] = value
+	return nil
+}
+
+// validateSchemaCompatibilityParallel checks the compatibility with the other schema in a bounded worker pool. Each
+// pair of objects referenced by the same property is checked as a separate unit, so the objects of a scope are checked
+// concurrently. If there are several incompatibilities, the one with the lowest path is returned, so the result does
+// not depend on scheduling.
+func (o *ObjectSchema) validateSchemaCompatibilityParallel(schemaType Object, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+	v := &parallelCompatibilityValidator{
+		workers: make(chan struct{}, workers),
+		wg:      &sync.WaitGroup{},
+	}
+	v.start(o, schemaType, nil)
+	v.wg.Wait()
+	if len(v.errs) == 0 {
+		return nil
 	}
-	// Now validate object fields
-	return o.validateMapTypesCompatibility(fieldData)
+	sort.Slice(v.errs, func(i, j int) bool {
+		return slices.Compare(v.errs[i].path, v.errs[j].path) < 0
+	})
+	return v.errs[0].err
+}
+
+// parallelCompatibilityValidator holds the state of a single validateSchemaCompatibilityParallel call.
+type parallelCompatibilityValidator struct {
+	workers chan struct{}
+	wg      *sync.WaitGroup
+	lock    sync.Mutex
+	errs    []pathError
+}
+
+type pathError struct {
+	path []string
+	err  error
+}
+
+func (v *parallelCompatibilityValidator) start(o *ObjectSchema, schemaType Object, path []string) {
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+		v.workers <- struct{}{}
+		defer func() {
+			<-v.workers
+		}()
+		v.validate(o, schemaType, path)
+	}()
+}
+
+// validate checks the properties of a single pair of objects. Properties referencing another object on both sides
+// are checked as a new unit instead of descending into them.
+func (v *parallelCompatibilityValidator) validate(o *ObjectSchema, schemaType Object, path []string) {
+	if err := o.validateSchemaID(schemaType); err != nil {
+		v.addError(path, err)
+		return
+	}
+	properties := schemaType.Properties()
+	for k, property := range o.PropertiesValue {
+		if property.Required() && properties[k] == nil {
+			v.addError(path, &ConstraintError{
+				Message: fmt.Sprintf("error while validating fields of objects %s, could not find required field %s", o.ReflectedType().String(), k),
+			})
+		}
+	}
+	for propertyID, otherProperty := range properties {
+		propertyPath := append(slices.Clip(path), propertyID)
+		property, ok := o.PropertiesValue[propertyID]
+		if !ok {
+			v.addError(propertyPath, o.invalidKeyError(propertyID))
+			continue
+		}
+		if object, otherObject, ok := referencedObjects(property, otherProperty); ok {
+			v.start(object, otherObject, propertyPath)
+			continue
+		}
+		if err := property.ValidateCompatibility(otherProperty); err != nil {
+			v.addError(propertyPath, err)
+		}
+	}
+}
+
+// referencedObjects returns the objects if both properties are references, which is checked the same way as the
+// referenced objects.
+func referencedObjects(property *PropertySchema, otherProperty *PropertySchema) (*ObjectSchema, Object, bool) {
+	ref, isRef := property.TypeValue.(*RefSchema)
+	otherRef, otherIsRef := otherProperty.TypeValue.(*RefSchema)
+	if !isRef || !otherIsRef || ref.referencedObjectCache == nil || otherRef.referencedObjectCache == nil {
+		return nil, nil, false
+	}
+	object, ok := ref.referencedObjectCache.(*ObjectSchema)
+	return object, otherRef.referencedObjectCache, ok
+}
+
+// addError records the error of the unit at the given path, with the path added to the error.
+func (v *parallelCompatibilityValidator) addError(path []string, err error) {
+	for i := len(path) - 1; i >= 0; i-- {
+		err = ConstraintErrorAddPathSegment(err, path[i])
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.errs = append(v.errs, pathError{path, err})
 }
 
 func (o *ObjectSchema) validateRawCompatibility(typeOrData any) error {
@@ -437,8 +1015,17 @@ func (o *ObjectSchema) ValidateCompatibility(typeOrData any) error {
 }
 
 func (o *ObjectSchema) Validate(data any) error {
+	if o.ValuePropertyValue != "" {
+		if err := o.validateMap(map[string]any{o.ValuePropertyValue: data}); err != nil {
+			return err
+		}
+		return o.runValidators(data)
+	}
 	if o.fieldCache != nil {
-		return o.validateStruct(data)
+		if err := o.validateStruct(data); err != nil {
+			return err
+		}
+		return o.runValidators(data)
 	}
 	d, ok := data.(map[string]any)
 	if !ok {
@@ -446,21 +1033,15 @@ func (o *ObjectSchema) Validate(data any) error {
 			Message: fmt.Sprintf("%T is not a valid data type for an object schema", d),
 		}
 	}
-	return o.validateMap(d)
+	if err := o.validateMap(d); err != nil {
+		return err
+	}
+	return o.runValidators(d)
 }
 
 func (o *ObjectSchema) applySubObjectDefaultValues(propertyID string, property *PropertySchema, rawData map[string]any) {
-	reflectedType := property.ReflectedType()
-	if reflectedType.Kind() == reflect.Pointer {
-		return
-	}
-	var subObject Object
-	switch property.TypeID() {
-	case TypeIDRef:
-		subObject = property.Type().(Ref).GetObject()
-	case TypeIDObject:
-		subObject = property.Type().(Object)
-	default:
+	subObject := o.propertyTypeInfo(property).subObject
+	if subObject == nil {
 		return
 	}
 	data := map[string]any{}
@@ -480,21 +1061,43 @@ func (o *ObjectSchema) applySubObjectDefaultValues(propertyID string, property *
 }
 
 func (o *ObjectSchema) convertData(v reflect.Value) (map[string]any, error) {
-	rawData := make(map[string]any, v.Len())
-	for _, key := range v.MapKeys() {
-		stringKey, ok := key.Interface().(string)
-		if !ok {
-			return nil, o.invalidKeyError(key.Interface())
+	rawData, err := o.copyRawData(v, nil)
+	if err != nil {
+		return nil, err
+	}
+	if o.compiled.plan != nil {
+		return o.runDecodePlan(rawData)
+	}
+	o.fillDefaults(rawData)
+	for propertyID, property := range o.PropertiesValue {
+		if d, ok := rawData[propertyID]; ok {
+			unserializedData, err := property.Unserialize(d)
+			if err != nil {
+				return nil, ConstraintErrorAddPathSegment(err, propertyID)
+			}
+			rawData[propertyID] = unserializedData
 		}
-		if _, ok := o.PropertiesValue[stringKey]; !ok {
-			return nil, o.invalidKeyError(stringKey)
+	}
+
+	return rawData, nil
+}
+
+// fillDefaults sets the serialized default values of all unset properties in the raw data.
+func (o *ObjectSchema) fillDefaults(rawData map[string]any) {
+	defaults := o.GetDefaults()
+	if index := o.getPropertyIndex(); index != nil && o.fieldCache == nil {
+		// Only the properties with a default value can be filled in.
+		for _, propertyID := range index.defaulted {
+			if _, isSet := rawData[propertyID]; !isSet {
+				rawData[propertyID] = defaults[propertyID]
+			}
 		}
-		rawData[stringKey] = v.MapIndex(key).Interface()
+		return
 	}
 	for propertyID := range o.PropertiesValue {
 		_, isSet := rawData[propertyID]
 		if !isSet {
-			if defaultValue, ok := o.GetDefaults()[propertyID]; ok {
+			if defaultValue, ok := defaults[propertyID]; ok {
 				rawData[propertyID] = defaultValue
 			}
 			if o.fieldCache != nil {
@@ -502,35 +1105,129 @@ func (o *ObjectSchema) convertData(v reflect.Value) (map[string]any, error) {
 			}
 		}
 	}
-	for propertyID, property := range o.PropertiesValue {
-		if d, ok := rawData[propertyID]; ok {
-			unserializedData, err := property.Unserialize(d)
-			if err != nil {
-				return nil, ConstraintErrorAddPathSegment(err, propertyID)
+}
+
+// copyRawData copies the input map into a string-keyed map. The keys are always the property IDs owned by the schema
+// rather than the keys of the input, so unserializing a large list of objects does not retain a separate copy of each
+// property name for every item. Invalid and duplicate keys are reported to the sink, in key order if it collects them.
+func (o *ObjectSchema) copyRawData(v reflect.Value, sink *constraintSink) (map[string]any, error) {
+	rawData := make(map[string]any, v.Len())
+	if data, ok := v.Interface().(map[string]any); ok {
+		for propertyID := range o.PropertiesValue {
+			if value, isSet := data[propertyID]; isSet {
+				rawData[propertyID] = value
 			}
-			rawData[propertyID] = unserializedData
+		}
+		if len(rawData) == len(data) {
+			return rawData, nil
+		}
+		// There are keys that don't belong to any property, fall back to the slow path to find them.
+		clear(rawData)
+	}
+	entries := make([]mapEntry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		entries = append(entries, mapEntry{iter.Key(), iter.Value()})
+	}
+	if sink != nil {
+		sortMapEntries(entries)
+	}
+	var unknownFields map[string]any
+	for _, entry := range entries {
+		stringKey, ok := entry.key.Interface().(string)
+		if !ok {
+			if err := sink.report(o.invalidKeyError(entry.key.Interface())); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		propertyID, ok := o.resolveKey(stringKey)
+		if !ok {
+			switch o.UnknownFields() {
+			case UnknownFieldsIgnore:
+			case UnknownFieldsCollect:
+				if unknownFields == nil {
+					unknownFields = map[string]any{}
+				}
+				unknownFields[stringKey] = entry.value.Interface()
+			default:
+				if err := sink.report(o.invalidKeyError(stringKey)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if _, isSet := rawData[propertyID]; isSet {
+			if err := sink.report(o.duplicateKeyError(stringKey, propertyID)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rawData[propertyID] = entry.value.Interface()
+	}
+	if err := o.mergeUnknownFields(rawData, unknownFields); err != nil {
+		if err := sink.report(err); err != nil {
+			return nil, err
 		}
 	}
-
 	return rawData, nil
 }
 
-func (o *ObjectSchema) validateFieldInterdependencies(rawData map[string]any) error {
-	for propertyID, property := range o.PropertiesValue {
-		if _, isSet := rawData[propertyID]; isSet {
-			if err := o.validatePropertyInterdependenciesIfSet(rawData, propertyID, property); err != nil {
-				return err
+// mergeUnknownFields adds the collected unknown fields to the catch-all property without modifying the input data.
+func (o *ObjectSchema) mergeUnknownFields(rawData map[string]any, unknownFields map[string]any) error {
+	if len(unknownFields) == 0 {
+		return nil
+	}
+	merged := make(map[string]any, len(unknownFields))
+	for k, v := range unknownFields {
+		merged[k] = v
+	}
+	if explicit, isSet := rawData[o.CatchAllPropertyValue]; isSet {
+		explicitValue := reflect.ValueOf(explicit)
+		if explicitValue.Kind() != reflect.Map {
+			return &ConstraintError{
+				Message:    fmt.Sprintf("Must be a map, %T given", explicit),
+				Path:       []string{o.CatchAllPropertyValue},
+				Constraint: ConstraintDataType,
+				Expected:   "map",
+				Actual:     fmt.Sprintf("%T", explicit),
 			}
-		} else {
-			err := o.validatePropertyInterdependenciesIfUnset(rawData, propertyID, property)
-			if err != nil {
+		}
+		for _, key := range explicitValue.MapKeys() {
+			merged[fmt.Sprintf("%v", key.Interface())] = explicitValue.MapIndex(key).Interface()
+		}
+	}
+	rawData[o.CatchAllPropertyValue] = merged
+	return nil
+}
+
+func (o *ObjectSchema) validateFieldInterdependencies(rawData map[string]any) error {
+	if index := o.getPropertyIndex(); index != nil {
+		for _, entry := range index.ruled {
+			if err := o.validatePropertyInterdependencies(rawData, entry.id, entry.property); err != nil {
 				return err
 			}
 		}
+		return nil
+	}
+	for propertyID, property := range o.PropertiesValue {
+		if err := o.validatePropertyInterdependencies(rawData, propertyID, property); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func (o *ObjectSchema) validatePropertyInterdependencies(
+	rawData map[string]any,
+	propertyID string,
+	property *PropertySchema,
+) error {
+	if _, isSet := rawData[propertyID]; isSet {
+		return o.validatePropertyInterdependenciesIfSet(rawData, propertyID, property)
+	}
+	return o.validatePropertyInterdependenciesIfUnset(rawData, propertyID, property)
+}
+
 func (o *ObjectSchema) validatePropertyInterdependenciesIfUnset(
 	rawData map[string]any,
 	propertyID string,
@@ -538,8 +1235,9 @@ func (o *ObjectSchema) validatePropertyInterdependenciesIfUnset(
 ) error {
 	if property.Required() {
 		return &ConstraintError{
-			Message: "This field is required",
-			Path:    []string{propertyID},
+			Message:    "This field is required",
+			Path:       []string{propertyID},
+			Constraint: ConstraintRequired,
 		}
 	}
 	for _, requiredIf := range property.RequiredIf() {
@@ -549,10 +1247,14 @@ func (o *ObjectSchema) validatePropertyInterdependenciesIfUnset(
 					"This field is required because '%s' is set",
 					requiredIf,
 				),
-				Path: []string{propertyID},
+				Path:       []string{propertyID},
+				Constraint: ConstraintRequired,
 			}
 		}
 	}
+	if err := o.validateRequiredIfConditions(rawData, propertyID, property); err != nil {
+		return err
+	}
 	if len(property.RequiredIfNot()) > 0 {
 		foundSet := false
 		for _, requiredIfNot := range property.RequiredIfNot() {
@@ -568,7 +1270,8 @@ func (o *ObjectSchema) validatePropertyInterdependenciesIfUnset(
 						"This field is required because '%s' is not set",
 						property.RequiredIfNot()[0],
 					),
-					Path: []string{propertyID},
+					Path:       []string{propertyID},
+					Constraint: ConstraintRequired,
 				}
 			}
 			return &ConstraintError{
@@ -576,13 +1279,138 @@ func (o *ObjectSchema) validatePropertyInterdependenciesIfUnset(
 					"This field is required because none of '%s' are set",
 					strings.Join(property.RequiredIfNot(), "', '"),
 				),
-				Path: []string{propertyID},
+				Path:       []string{propertyID},
+				Constraint: ConstraintRequired,
 			}
 		}
 	}
 	return nil
 }
 
+// validateRequiredIfConditions returns an error for the unset property if one of its value-based conditions applies.
+func (o *ObjectSchema) validateRequiredIfConditions(
+	rawData map[string]any,
+	propertyID string,
+	property *PropertySchema,
+) error {
+	for _, condition := range o.propertyConditions(propertyID, property) {
+		if !o.propertyConditionApplies(rawData, condition) {
+			continue
+		}
+		verb := "is set to"
+		if condition.Negate {
+			verb = "is not set to"
+		}
+		return &ConstraintError{
+			Message: fmt.Sprintf(
+				"This field is required because '%s' %s %s",
+				condition.PropertyID,
+				verb,
+				strings.Join(condition.Values, " or "),
+			),
+			Path:       []string{propertyID},
+			Constraint: ConstraintRequired,
+		}
+	}
+	return nil
+}
+
+// decodedCondition is a PropertyCondition with its values unserialized with the type of the referenced property.
+type decodedCondition struct {
+	PropertyCondition
+	expected []any
+}
+
+// decodeConditions unserializes the values of the value-based conditions of all properties, so they are not decoded
+// on every validation. It panics if a condition references a property that doesn't exist. If a referenced property
+// contains references that are not linked yet, decoding is left to ApplyNamespace.
+func (o *ObjectSchema) decodeConditions() {
+	conditions := map[string][]decodedCondition{}
+	for propertyID, property := range o.PropertiesValue {
+		if len(property.RequiredIfConditionsValue) == 0 {
+			continue
+		}
+		for _, condition := range property.RequiredIfConditionsValue {
+			otherProperty, ok := o.PropertiesValue[condition.PropertyID]
+			if !ok {
+				panic(BadArgumentError{
+					Message: fmt.Sprintf(
+						"condition of property %s on object %s references the nonexistent property %s",
+						propertyID,
+						o.IDValue,
+						condition.PropertyID,
+					),
+					Path: []string{o.IDValue, propertyID},
+				})
+			}
+			if otherProperty.ValidateReferences() != nil {
+				o.conditions = nil
+				return
+			}
+		}
+		conditions[propertyID] = o.decodePropertyConditions(property)
+	}
+	o.conditions = conditions
+}
+
+func (o *ObjectSchema) decodePropertyConditions(property *PropertySchema) []decodedCondition {
+	result := make([]decodedCondition, len(property.RequiredIfConditionsValue))
+	for i, condition := range property.RequiredIfConditionsValue {
+		otherType := o.PropertiesValue[condition.PropertyID].Type()
+		expected := make([]any, 0, len(condition.Values))
+		for _, encodedValue := range condition.Values {
+			var decodedValue any
+			if err := json.Unmarshal([]byte(encodedValue), &decodedValue); err != nil {
+				continue
+			}
+			if value, err := otherType.Unserialize(decodedValue); err == nil {
+				expected = append(expected, value)
+			}
+		}
+		result[i] = decodedCondition{condition, expected}
+	}
+	return result
+}
+
+// propertyConditions returns the decoded conditions of the property. They are only decoded here if the object was
+// created without a constructor and no namespace has been applied yet.
+func (o *ObjectSchema) propertyConditions(propertyID string, property *PropertySchema) []decodedCondition {
+	if o.conditions != nil {
+		return o.conditions[propertyID]
+	}
+	if len(property.RequiredIfConditionsValue) == 0 {
+		return nil
+	}
+	return o.decodePropertyConditions(property)
+}
+
+// propertyConditionApplies returns true if the condition is triggered by the data. The data may hold either serialized
+// or unserialized values, so the value is unserialized with the referenced property's type before comparing.
+func (o *ObjectSchema) propertyConditionApplies(rawData map[string]any, condition decodedCondition) bool {
+	matches := false
+	otherProperty, hasProperty := o.PropertiesValue[condition.PropertyID]
+	value, isSet := rawData[condition.PropertyID]
+	if hasProperty && isSet {
+		otherType := otherProperty.Type()
+		actual, err := otherType.Unserialize(value)
+		if err != nil {
+			serialized, serializeErr := otherType.Serialize(value)
+			if serializeErr == nil {
+				actual, err = otherType.Unserialize(serialized)
+			}
+		}
+		if err == nil {
+			for _, expected := range condition.expected {
+				if reflect.DeepEqual(actual, expected) {
+					matches = true
+					break
+				}
+			}
+		}
+	}
+	return matches != condition.Negate
+}
+
 func (o *ObjectSchema) validatePropertyInterdependenciesIfSet(
 	rawData map[string]any,
 	propertyID string,
@@ -595,7 +1423,9 @@ func (o *ObjectSchema) validatePropertyInterdependenciesIfSet(
 					"Field conflicts '%s', set one of the two, not both",
 					conflict,
 				),
-				Path: []string{propertyID},
+				Path:       []string{propertyID},
+				Constraint: ConstraintConflicts,
+				Expected:   conflict,
 			}
 		}
 	}
@@ -603,11 +1433,14 @@ func (o *ObjectSchema) validatePropertyInterdependenciesIfSet(
 }
 
 func (o *ObjectSchema) invalidKeyError(value any) error {
-	validKeys := make([]string, len(o.PropertiesValue))
-	i := 0
-	for k := range o.PropertiesValue {
-		validKeys[i] = k
-		i++
+	var validKeys []string
+	if index := o.getPropertyIndex(); index != nil {
+		validKeys = append(validKeys, index.ids...)
+	} else {
+		validKeys = make([]string, 0, len(o.PropertiesValue))
+		for k := range o.PropertiesValue {
+			validKeys = append(validKeys, k)
+		}
 	}
 	return &ConstraintError{
 		Message: fmt.Sprintf(
@@ -615,6 +1448,9 @@ func (o *ObjectSchema) invalidKeyError(value any) error {
 			value,
 			strings.Join(validKeys, ", "),
 		),
+		Constraint: ConstraintUnknownField,
+		Expected:   validKeys,
+		Actual:     value,
 	}
 }
 
@@ -630,8 +1466,9 @@ type TypedObject[T any] interface {
 // when unserialized.
 func NewStructMappedObjectSchema[T any](id string, properties map[string]*PropertySchema) *ObjectSchema {
 	validateObjectIsStruct[T]()
+	aliases := buildObjectAliases(id, properties)
 	var defaultValue T
-	return &ObjectSchema{
+	o := &ObjectSchema{
 		IDValue:         id,
 		PropertiesValue: properties,
 
@@ -640,7 +1477,11 @@ func NewStructMappedObjectSchema[T any](id string, properties map[string]*Proper
 		defaultValue:     defaultValue,
 		defaultValueType: reflect.TypeOf(&defaultValue).Elem(),
 		fieldCache:       buildObjectFieldCache[T](properties),
+
+		aliases: aliases,
 	}
+	o.decodeConditions()
+	return o
 }
 
 func NewTypedObject[T any](id string, properties map[string]*PropertySchema) *TypedObjectSchema[T] {
@@ -747,6 +1588,36 @@ func validateObjectIsStruct[T any]() {
 	}
 }
 
+// buildObjectAliases returns the property IDs by their aliases. It panics if a property alias could be confused with
+// another property ID or alias.
+func buildObjectAliases(id string, properties map[string]*PropertySchema) map[string]string {
+	aliasOwners := map[string]string{}
+	for propertyID, property := range properties {
+		for _, alias := range property.AliasesValue {
+			if _, ok := properties[alias]; ok {
+				panic(BadArgumentError{
+					Message: fmt.Sprintf("alias %s of property %s on object %s is also a property ID", alias, propertyID, id),
+					Path:    []string{id, propertyID},
+				})
+			}
+			if owner, ok := aliasOwners[alias]; ok && owner != propertyID {
+				panic(BadArgumentError{
+					Message: fmt.Sprintf(
+						"alias %s on object %s is used by both properties %s and %s",
+						alias,
+						id,
+						owner,
+						propertyID,
+					),
+					Path: []string{id, propertyID},
+				})
+			}
+			aliasOwners[alias] = propertyID
+		}
+	}
+	return aliasOwners
+}
+
 func extractObjectDefaultValues(properties map[string]*PropertySchema) map[string]any {
 	defaultValues := map[string]any{}
 	for propertyID, property := range properties {
@@ -783,6 +1654,12 @@ func jsonUnmarshal(defaultValue string, value any, propertryType TypeID) error {
 	return err
 }
 
+// buildObjectFieldCache maps each property to the struct field that holds its value. The struct field is matched by its
+// JSON tag first and by its name second. Fields of embedded structs are promoted the same way as in Go, including
+// embedded pointers, which are allocated when needed during unserialization. Like in encoding/json, unexported fields
+// are skipped: the property is still validated, but its value is neither stored in nor read from the struct. A field
+// promoted through an unexported embedded pointer cannot be allocated, which results in a BadArgumentError panic when
+// the schema is built instead of an error at runtime.
 func buildObjectFieldCache[T any](properties map[string]*PropertySchema) map[string]reflect.StructField {
 	var defaultValue T
 	fieldCache := make(map[string]reflect.StructField, len(properties))
@@ -816,7 +1693,53 @@ func buildObjectFieldCache[T any](properties map[string]*PropertySchema) map[str
 				})
 			}
 		}
+		if !field.IsExported() {
+			continue
+		}
+		if err := validateObjectField(reflectType, propertyID, field); err != nil {
+			panic(BadArgumentError{
+				Message: err.Error(),
+			})
+		}
 		fieldCache[propertyID] = field
 	}
 	return fieldCache
 }
+
+// validateObjectField checks that the exported field mapped to the property can be both set and read via reflection.
+func validateObjectField(reflectType reflect.Type, propertyID string, field reflect.StructField) error {
+	currentType := reflectType
+	for _, i := range field.Index[:len(field.Index)-1] {
+		embeddedField := currentType.Field(i)
+		currentType = embeddedField.Type
+		if currentType.Kind() != reflect.Pointer {
+			continue
+		}
+		if !embeddedField.IsExported() {
+			return fmt.Errorf(
+				"The field '%s' for '%s' on '%s' is promoted through the unexported embedded pointer '%s', which "+
+					"cannot be allocated. Please embed the struct by value or export it.",
+				field.Name,
+				propertyID,
+				reflectType.Name(),
+				embeddedField.Name,
+			)
+		}
+		currentType = currentType.Elem()
+	}
+	return nil
+}
+
+// fieldByIndexAllocating returns the nested field of the struct value, allocating any nil embedded pointers on the way.
+func fieldByIndexAllocating(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
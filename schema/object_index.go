@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"sort"
+	"sync"
+)
+
+// propertyIndex holds lookups of the properties of an object that are otherwise computed on every call by iterating
+// over all properties.
+type propertyIndex struct {
+	once sync.Once
+	// ids holds the property IDs in ascending order.
+	ids []string
+	// ruled holds the properties that are required, conditionally required or conflict with other properties, in the
+	// order of their IDs. Properties without such rules never fail the checks of validateFieldInterdependencies.
+	ruled []indexedProperty
+	// defaulted holds the IDs of the properties with a default value in ascending order.
+	defaulted []string
+}
+
+type indexedProperty struct {
+	id       string
+	property *PropertySchema
+}
+
+// UsePropertyIndex is a builder-pattern way of precomputing the property lookups of objects with many properties,
+// such as schemas generated from external APIs with hundreds of fields. Without the index, checking the required and
+// conflicting properties iterates over all properties on every unserialization, validation, and serialization. With
+// it, only the properties that have such rules are checked, in the order of their IDs, so the first error reported
+// is always the same. Likewise, only the properties with a default value are visited when filling in defaults, except
+// on struct-mapped objects, which also fill in the defaults of nested objects. The list of valid property IDs in
+// unknown field errors is sorted.
+//
+// The index is built when the object is first used. The properties of the object must not be changed after that.
+func (o *ObjectSchema) UsePropertyIndex() *ObjectSchema {
+	o.checkNotLocked("use a property index")
+	o.compiled.index = &propertyIndex{}
+	return o
+}
+
+// getPropertyIndex returns the property index, building it if this is the first use, or nil if the object doesn't
+// use one.
+func (o *ObjectSchema) getPropertyIndex() *propertyIndex {
+	if o.compiled.index == nil {
+		return nil
+	}
+	o.compiled.index.once.Do(func() {
+		ids := make([]string, 0, len(o.PropertiesValue))
+		for propertyID := range o.PropertiesValue {
+			ids = append(ids, propertyID)
+		}
+		sort.Strings(ids)
+		var ruled []indexedProperty
+		var defaulted []string
+		defaults := o.GetDefaults()
+		for _, propertyID := range ids {
+			if _, ok := defaults[propertyID]; ok {
+				defaulted = append(defaulted, propertyID)
+			}
+			property := o.PropertiesValue[propertyID]
+			if property.Required() || len(property.RequiredIf()) > 0 || len(property.RequiredIfNot()) > 0 ||
+				len(property.RequiredIfConditionsValue) > 0 || len(property.Conflicts()) > 0 {
+				ruled = append(ruled, indexedProperty{propertyID, property})
+			}
+		}
+		o.compiled.index.ids = ids
+		o.compiled.index.ruled = ruled
+		o.compiled.index.defaulted = defaulted
+	})
+	return o.compiled.index
+}
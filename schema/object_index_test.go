@@ -0,0 +1,87 @@
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func newPropertyIndexTestSchema(width int) *schema.ObjectSchema {
+	s := newDecodePlanTestSchema(width)
+	s.PropertiesValue["field1"].RequiredValue = true
+	s.PropertiesValue["field3"].RequiredValue = true
+	s.PropertiesValue["field6"].ConflictsValue = []string{"field8"}
+	return s
+}
+
+func TestObjectPropertyIndex(t *testing.T) {
+	plain := newPropertyIndexTestSchema(100)
+	indexed := newPropertyIndexTestSchema(100).UsePropertyIndex()
+
+	data := newDecodePlanTestData(100)
+	expected := assert.NoErrorR[any](t)(plain.Unserialize(data))
+	result := assert.NoErrorR[any](t)(indexed.Unserialize(data))
+	assert.Equals(t, result, expected)
+	assert.NoError(t, indexed.Validate(result))
+	assert.Equals(
+		t,
+		assert.NoErrorR[any](t)(indexed.Serialize(result)),
+		assert.NoErrorR[any](t)(plain.Serialize(result)),
+	)
+
+	// The required and conflicting properties are still checked, the first one in the order of the IDs.
+	invalid := newDecodePlanTestData(100)
+	delete(invalid, "field1")
+	delete(invalid, "field3")
+	for i := 0; i < 10; i++ {
+		_, err := indexed.Unserialize(invalid)
+		assert.Error(t, err)
+		assert.Equals(t, err.Error(), "Validation failed for 'field1': This field is required")
+	}
+	conflicting := newDecodePlanTestData(100)
+	conflicting["field6"] = true
+	conflicting["field8"] = "test"
+	err := indexed.Validate(conflicting)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Field conflicts 'field8'")
+
+	// Unknown fields list the valid properties in order.
+	_, err = schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"b": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+		"a": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+	}).UsePropertyIndex().Unserialize(map[string]any{"c": "test"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected one of: a, b")
+
+	assert.Equals(t, schema.MeasureType(indexed).PropertyIndexes, 1)
+}
+
+func BenchmarkObjectPropertyIndex(b *testing.B) {
+	for _, useIndex := range []bool{false, true} {
+		s := newPropertyIndexTestSchema(500)
+		if useIndex {
+			s = s.UsePropertyIndex()
+		}
+		data := newDecodePlanTestData(500)
+		unserialized, err := s.Unserialize(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("index=%t/unserialize", useIndex), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Unserialize(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("index=%t/validate", useIndex), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := s.Validate(unserialized); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
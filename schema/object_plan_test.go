@@ -0,0 +1,111 @@
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func newDecodePlanTestSchema(width int) *schema.ObjectSchema {
+	properties := map[string]*schema.PropertySchema{}
+	for i := 0; i < width; i++ {
+		var t schema.Type
+		switch i % 4 {
+		case 0:
+			t = schema.NewStringSchema(nil, nil, nil)
+		case 1:
+			t = schema.NewIntSchema(nil, nil, nil)
+		case 2:
+			t = schema.NewBoolSchema()
+		default:
+			t = schema.NewIntSchema(schema.IntPointer(0), nil, nil)
+		}
+		var defaultValue *string
+		if i%5 == 0 {
+			defaultValue = schema.PointerTo(fmt.Sprintf("%d", i))
+			if i%4 == 0 {
+				defaultValue = schema.PointerTo(fmt.Sprintf(`"%d"`, i))
+			} else if i%4 == 2 {
+				defaultValue = schema.PointerTo("true")
+			}
+		}
+		properties[fmt.Sprintf("field%d", i)] = schema.NewPropertySchema(
+			t,
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			defaultValue,
+			nil,
+		)
+	}
+	return schema.NewObjectSchema("wide", properties)
+}
+
+func newDecodePlanTestData(width int) map[string]any {
+	data := map[string]any{}
+	for i := 1; i < width; i += 2 {
+		switch i % 4 {
+		case 1:
+			data[fmt.Sprintf("field%d", i)] = i
+		default:
+			data[fmt.Sprintf("field%d", i)] = int64(i)
+		}
+	}
+	data["field2"] = false
+	data["field4"] = "test"
+	return data
+}
+
+func TestObjectDecodePlan(t *testing.T) {
+	plain := newDecodePlanTestSchema(100)
+	planned := newDecodePlanTestSchema(100).UseDecodePlan()
+
+	expected := assert.NoErrorR[any](t)(plain.Unserialize(newDecodePlanTestData(100)))
+	result := assert.NoErrorR[any](t)(planned.Unserialize(newDecodePlanTestData(100)))
+	assert.Equals(t, result, expected)
+
+	invalid := newDecodePlanTestData(100)
+	invalid["field3"] = int64(-1)
+	_, expectedErr := plain.Unserialize(invalid)
+	_, err := planned.Unserialize(invalid)
+	assert.Error(t, err)
+	assert.Equals(t, err.Error(), expectedErr.Error())
+
+	// Values that don't match the fast path fall back to the property's own unserialization.
+	converted := newDecodePlanTestData(100)
+	converted["field0"] = 1
+	expected = assert.NoErrorR[any](t)(plain.Unserialize(converted))
+	result = assert.NoErrorR[any](t)(planned.Unserialize(converted))
+	assert.Equals(t, result, expected)
+}
+
+func TestObjectDecodePlanBuiltLazily(t *testing.T) {
+	s := schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+	}).UseDecodePlan()
+	// Properties configured after UseDecodePlan are taken into account.
+	s.Properties()["name"].Transform(schema.Transform{ID: schema.TransformTrim})
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"name": " test "}))
+	assert.Equals(t, unserialized.(map[string]any), map[string]any{"name": "test"})
+}
+
+func BenchmarkObjectDecodePlan(b *testing.B) {
+	for _, usePlan := range []bool{false, true} {
+		s := newDecodePlanTestSchema(500)
+		if usePlan {
+			s = s.UseDecodePlan()
+		}
+		data := newDecodePlanTestData(500)
+		b.Run(fmt.Sprintf("plan=%t", usePlan), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Unserialize(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
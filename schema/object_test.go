@@ -1,10 +1,14 @@
 package schema_test
 
 import (
+	"errors"
+	"fmt"
 	"go.arcalot.io/assert"
 	"go.flow.arcalot.io/pluginsdk/schema/testdata"
+	"regexp"
 	"strconv"
 	"testing"
+	"unsafe"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
 )
@@ -688,3 +692,560 @@ func TestStructWithPrivateFields(t *testing.T) {
 	// The unserialization will only be able to fill in the public fields.
 	assert.Equals(t, inputWithOnlyPublicField, unserializedData.(testdata.TestStructWithPrivateField))
 }
+
+func TestObjectUnserializeInternsPropertyNames(t *testing.T) {
+	s := schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	})
+	var schemaKey string
+	for key := range s.Properties() {
+		schemaKey = key
+	}
+	// Build the input key at runtime so it does not share memory with the schema's key.
+	inputKey := string([]byte("name"))
+	result := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{inputKey: "test"}))
+	for key := range result.(map[string]any) {
+		assert.Equals(t, unsafe.StringData(key), unsafe.StringData(schemaKey))
+	}
+}
+
+func unknownFieldsTestSchema() *schema.ObjectSchema {
+	return schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"extra": schema.NewPropertySchema(
+			schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewAnySchema(), nil, nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	})
+}
+
+func TestObjectUnknownFieldsReject(t *testing.T) {
+	s := unknownFieldsTestSchema()
+	assert.Equals(t, s.UnknownFields(), schema.UnknownFieldsReject)
+	_, err := s.Unserialize(map[string]any{"name": "a", "other": 1})
+	assert.Error(t, err)
+}
+
+func TestObjectUnknownFieldsIgnore(t *testing.T) {
+	s := unknownFieldsTestSchema().IgnoreUnknownFields()
+	assert.Equals(t, s.UnknownFields(), schema.UnknownFieldsIgnore)
+	result := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"name": "a", "other": 1}))
+	assert.Equals(t, result.(map[string]any), map[string]any{"name": "a"})
+}
+
+func TestObjectUnknownFieldsCollect(t *testing.T) {
+	s := unknownFieldsTestSchema().CollectUnknownFields("extra")
+	assert.Equals(t, s.UnknownFields(), schema.UnknownFieldsCollect)
+	input := map[string]any{
+		"name":  "a",
+		"other": 1,
+		"both":  "unknown",
+		"extra": map[string]any{"both": "explicit"},
+	}
+	result := assert.NoErrorR[any](t)(s.Unserialize(input))
+	assert.Equals(t, result.(map[string]any), map[string]any{
+		"name": "a",
+		"extra": map[string]any{
+			"other": int64(1),
+			"both":  "explicit",
+		},
+	})
+	// The input must not be modified.
+	assert.Equals(t, input["extra"].(map[string]any), map[string]any{"both": "explicit"})
+
+	_, err := s.Unserialize(map[string]any{"other": 1, "extra": "not a map"})
+	assert.Error(t, err)
+}
+
+func TestObjectUnknownFieldsCollectSerialize(t *testing.T) {
+	s := unknownFieldsTestSchema().CollectUnknownFields("extra")
+	input := map[string]any{
+		"name":  "a",
+		"other": "b",
+		"extra": map[string]any{"name": "explicit"},
+	}
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(input))
+	serialized := assert.NoErrorR[any](t)(s.Serialize(unserialized))
+	// Keys that would be read as a property stay in the catch-all property.
+	assert.Equals(t, serialized.(map[string]any), map[string]any{
+		"name":  "a",
+		"other": "b",
+		"extra": map[any]any{"name": "explicit"},
+	})
+	assert.Equals(t, assert.NoErrorR[any](t)(s.Unserialize(serialized)), unserialized)
+}
+
+func TestObjectUnknownFieldsSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(unknownFieldsTestSchema().CollectUnknownFields("extra"))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	assert.Equals(t, unserializedScope.RootObject().UnknownFields(), schema.UnknownFieldsCollect)
+	assert.Equals(t, unserializedScope.RootObject().CatchAllProperty(), "extra")
+	data := assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"name": "a", "other": "b"}))
+	assert.Equals(t, data.(map[string]any)["extra"].(map[string]any), map[string]any{"other": "b"})
+
+	// The catch-all property is required when collecting unknown fields.
+	delete(serialized.(map[string]any)["objects"].(map[any]any)["test"].(map[string]any), "catch_all_property")
+	_, err := schema.DescribeScope().Unserialize(serialized)
+	assert.Error(t, err)
+}
+
+func TestObjectSelfSerializeOmitsDefaults(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"list": schema.NewPropertySchema(
+			schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"map": schema.NewPropertySchema(
+			schema.NewMapSchema(schema.NewIntSchema(nil, nil, nil), schema.NewStringSchema(nil, nil, nil), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"source": schema.NewPropertySchema(
+			newOneOfDefaultTestSchema(),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}))
+	scope.Objects()["test"].Properties()["source"].Type().(*schema.OneOfSchema[string]).DefaultTypeValue = nil
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	// Keys holding their default value are left out, so engines that don't know them can read the schema.
+	assertNoSerializedKeys(
+		t,
+		serialized,
+		"unknown_fields",
+		"catch_all_property",
+		"key_matching",
+		"value_property",
+		"capture_pattern",
+		"unique_items",
+		"string_keys",
+		"untagged",
+		"value_field_name",
+		"default_type",
+	)
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	rootObject := unserialized.(*schema.ScopeSchema).Objects()["test"]
+	assert.Equals(t, rootObject.UnknownFields(), schema.UnknownFieldsReject)
+	assert.Equals(t, rootObject.KeyMatching(), schema.KeyMatchingStrict)
+}
+
+// assertNoSerializedKeys fails the test if any of the keys appear anywhere in the serialized data.
+func assertNoSerializedKeys(t *testing.T, serialized any, keys ...string) {
+	t.Helper()
+	switch data := serialized.(type) {
+	case map[string]any:
+		for key, value := range data {
+			for _, forbidden := range keys {
+				if key == forbidden {
+					t.Fatalf("unexpected key %s in the serialized data", key)
+				}
+			}
+			assertNoSerializedKeys(t, value, keys...)
+		}
+	case map[any]any:
+		for _, value := range data {
+			assertNoSerializedKeys(t, value, keys...)
+		}
+	case []any:
+		for _, value := range data {
+			assertNoSerializedKeys(t, value, keys...)
+		}
+	}
+}
+
+func TestObjectUnknownFieldsCollectInvalidProperty(t *testing.T) {
+	assert.Panics(t, func() {
+		unknownFieldsTestSchema().CollectUnknownFields("nonexistent")
+	})
+	assert.Panics(t, func() {
+		unknownFieldsTestSchema().CollectUnknownFields("name")
+	})
+}
+
+type keyMatchingTestStruct struct {
+	UserName string `json:"user_name"`
+}
+
+func newKeyMatchingTestSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[keyMatchingTestStruct]("test", map[string]*schema.PropertySchema{
+		"user_name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	})
+}
+
+func TestObjectKeyMatching(t *testing.T) {
+	strict := newKeyMatchingTestSchema()
+	assert.Equals(t, strict.KeyMatching(), schema.KeyMatchingStrict)
+	_, err := strict.Unserialize(map[string]any{"User_Name": "arca"})
+	assert.Error(t, err)
+
+	caseInsensitive := newKeyMatchingTestSchema().MatchKeys(schema.KeyMatchingCaseInsensitive)
+	result := assert.NoErrorR[any](t)(caseInsensitive.Unserialize(map[string]any{"User_Name": "arca"}))
+	assert.Equals(t, result.(keyMatchingTestStruct).UserName, "arca")
+	_, err = caseInsensitive.Unserialize(map[string]any{"userName": "arca"})
+	assert.Error(t, err)
+
+	namingInsensitive := newKeyMatchingTestSchema().MatchKeys(schema.KeyMatchingNamingInsensitive)
+	for _, key := range []string{"user_name", "userName", "UserName", "user-name"} {
+		result = assert.NoErrorR[any](t)(namingInsensitive.Unserialize(map[string]any{key: "arca"}))
+		assert.Equals(t, result.(keyMatchingTestStruct).UserName, "arca")
+	}
+	_, err = namingInsensitive.Unserialize(map[string]any{"user_name": "arca", "userName": "lot"})
+	assert.Error(t, err)
+	_, err = schema.UnserializeAll(namingInsensitive, map[string]any{"user_name": "arca", "userName": "lot"})
+	assert.Error(t, err)
+}
+
+func TestObjectKeyMatchingSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(newKeyMatchingTestSchema().MatchKeys(schema.KeyMatchingNamingInsensitive))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	assert.Equals(t, unserializedScope.RootObject().KeyMatching(), schema.KeyMatchingNamingInsensitive)
+	result := assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"userName": "arca"}))
+	assert.Equals(t, result.(map[string]any)["user_name"].(string), "arca")
+}
+
+func TestObjectKeyMatchingAmbiguous(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"user_name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+			"userName":  schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+		}).MatchKeys(schema.KeyMatchingNamingInsensitive)
+	})
+}
+
+type validatorTestStruct struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+func newValidatorTestSchema() *schema.ObjectSchema {
+	properties := map[string]*schema.PropertySchema{
+		"start": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"end":   schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	}
+	return schema.WithValidator(
+		schema.NewStructMappedObjectSchema[validatorTestStruct]("test", properties),
+		func(obj validatorTestStruct) error {
+			if obj.End <= obj.Start {
+				return fmt.Errorf("end must be after start")
+			}
+			return nil
+		},
+	)
+}
+
+func TestObjectWithValidator(t *testing.T) {
+	s := newValidatorTestSchema()
+	result := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"start": 1, "end": 2}))
+	assert.Equals(t, result.(validatorTestStruct), validatorTestStruct{Start: 1, End: 2})
+
+	_, err := s.Unserialize(map[string]any{"start": 2, "end": 1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "end must be after start")
+	var c *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &c), true)
+	assert.Equals(t, c.Constraint, schema.ConstraintCustom)
+
+	assert.NoError(t, s.Validate(validatorTestStruct{Start: 1, End: 2}))
+	assert.Error(t, s.Validate(validatorTestStruct{Start: 2, End: 1}))
+}
+
+func TestObjectWithValidatorConstraintError(t *testing.T) {
+	s := schema.WithValidator(
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"a": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+		}),
+		func(obj map[string]any) error {
+			if obj["a"] == int64(0) {
+				return &schema.ConstraintError{Message: "must not be zero", Path: []string{"a"}}
+			}
+			return nil
+		},
+	)
+	_, err := s.Unserialize(map[string]any{"a": 0})
+	assert.Equals(t, err.Error(), "Validation failed for 'a': must not be zero")
+	assert.NoError(t, s.Validate(map[string]any{"a": int64(1)}))
+}
+
+func TestObjectWithValidatorTypeMismatch(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.WithValidator(
+			schema.NewObjectSchema("test", map[string]*schema.PropertySchema{}),
+			func(obj validatorTestStruct) error { return nil },
+		)
+	})
+}
+
+var applyDefaultsTestSchema = schema.NewScopeSchema(
+	schema.NewObjectSchema(
+		"root",
+		map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				schema.PointerTo(`"default-name"`),
+				nil,
+			),
+			"items": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewRefSchema("item", nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"variant": schema.NewPropertySchema(
+				schema.NewOneOfStringSchema[any](
+					map[string]schema.Object{
+						"item": schema.NewRefSchema("item", nil),
+					},
+					"kind",
+					false,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+	schema.NewObjectSchema(
+		"item",
+		map[string]*schema.PropertySchema{
+			"count": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				schema.PointerTo("5"),
+				nil,
+			),
+		},
+	),
+)
+
+func TestObjectApplyDefaults(t *testing.T) {
+	input := map[string]any{
+		"items": []any{
+			map[string]any{},
+			map[string]any{"count": 1},
+		},
+		"variant": map[string]any{"kind": "item"},
+	}
+	result := applyDefaultsTestSchema.ApplyDefaults(input)
+	assert.Equals(t, result.(map[string]any), map[string]any{
+		"name": "default-name",
+		"items": []any{
+			map[string]any{"count": int64(5)},
+			map[string]any{"count": 1},
+		},
+		"variant": map[string]any{"kind": "item", "count": int64(5)},
+	})
+	// The input must not be modified.
+	assert.Equals(t, input["items"].([]any)[0].(map[string]any), map[string]any{})
+	assert.Equals(t, input["variant"].(map[string]any), map[string]any{"kind": "item"})
+
+	// The result is the effective configuration, so it must unserialize like the original.
+	_, err := applyDefaultsTestSchema.Unserialize(result)
+	assert.NoError(t, err)
+
+	// Data that doesn't match the schema is passed through.
+	assert.Equals(t, applyDefaultsTestSchema.ApplyDefaults("invalid").(string), "invalid")
+	// Types without properties don't implement DefaultsApplier.
+	assert.Equals(t, schema.ApplyDefaults(schema.NewStringSchema(nil, nil, nil), "a").(string), "a")
+	withDefaults := schema.ApplyDefaults(applyDefaultsTestSchema, map[string]any{})
+	assert.Equals(t, withDefaults.(map[string]any)["name"], any("default-name"))
+}
+
+type EmbeddedPointerTestStruct struct {
+	Field1 int64 `json:"field1"`
+}
+
+type testStructWithEmbeddedPointer struct {
+	*EmbeddedPointerTestStruct
+	Field2 string `json:"field2"`
+}
+
+type testStructWithUnexportedEmbeddedPointer struct {
+	*embeddedTestStruct
+}
+
+type testStructWithUnexportedField struct {
+	field1 int64
+}
+
+func TestObjectEmbeddedPointer(t *testing.T) {
+	s := schema.NewTypedObject[testStructWithEmbeddedPointer]("test", map[string]*schema.PropertySchema{
+		"field1": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+		"field2": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	})
+
+	unserialized, err := s.UnserializeType(map[string]any{"field1": 42, "field2": "test"})
+	assert.NoError(t, err)
+	assert.Equals(t, unserialized.Field1, int64(42))
+
+	serialized, err := s.Serialize(unserialized)
+	assert.NoError(t, err)
+	assert.Equals(t, serialized.(map[string]any)["field1"].(int64), int64(42))
+
+	// A nil embedded pointer leaves all promoted properties unset.
+	serialized, err = s.Serialize(testStructWithEmbeddedPointer{Field2: "test"})
+	assert.NoError(t, err)
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"field2": "test"})
+	assert.NoError(t, s.Validate(testStructWithEmbeddedPointer{Field2: "test"}))
+}
+
+func TestObjectUnexportedFieldSkipped(t *testing.T) {
+	s := schema.NewTypedObject[testStructWithUnexportedField]("test", map[string]*schema.PropertySchema{
+		"field1": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+	})
+	for _, compile := range []bool{false, true} {
+		if compile {
+			s.Compile()
+		}
+		unserialized := assert.NoErrorR[testStructWithUnexportedField](t)(s.UnserializeType(map[string]any{"field1": 42}))
+		assert.Equals(t, unserialized, testStructWithUnexportedField{})
+		// The value of the property is still validated.
+		_, err := s.Unserialize(map[string]any{"field1": "not a number"})
+		assert.Error(t, err)
+		serialized := assert.NoErrorR[any](t)(s.Serialize(testStructWithUnexportedField{field1: 42}))
+		assert.Equals(t, serialized.(map[string]any), map[string]any{})
+	}
+}
+
+func TestObjectUnmappableFields(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewTypedObject[testStructWithUnexportedEmbeddedPointer]("test", map[string]*schema.PropertySchema{
+			"Field1": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		})
+	})
+}
+
+type capturePatternTestStruct struct {
+	Host string `json:"host"`
+	Port int64  `json:"port"`
+}
+
+func newCapturePatternTestSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[capturePatternTestStruct]("test", map[string]*schema.PropertySchema{
+		"host": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		"port": schema.NewPropertySchema(
+			schema.NewIntSchema(schema.IntPointer(1), schema.IntPointer(65535), nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			schema.PointerTo("80"),
+			nil,
+		),
+	}).WithCapturePattern(regexp.MustCompile(`^(?P<host>[^:]+)(:(?P<port>\d+))?$`))
+}
+
+func TestObjectCapturePattern(t *testing.T) {
+	s := newCapturePatternTestSchema()
+	result := assert.NoErrorR[any](t)(s.Unserialize("localhost:8080"))
+	assert.Equals(t, result.(capturePatternTestStruct), capturePatternTestStruct{Host: "localhost", Port: 8080})
+
+	// Groups that don't participate in the match leave the property unset.
+	result = assert.NoErrorR[any](t)(s.Unserialize("localhost"))
+	assert.Equals(t, result.(capturePatternTestStruct), capturePatternTestStruct{Host: "localhost", Port: 80})
+
+	// Maps are still accepted.
+	result = assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"host": "localhost", "port": 443}))
+	assert.Equals(t, result.(capturePatternTestStruct), capturePatternTestStruct{Host: "localhost", Port: 443})
+
+	_, err := s.Unserialize("localhost:http")
+	assert.Error(t, err)
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintPattern)
+
+	// The captured values are unserialized according to their properties.
+	_, err = s.Unserialize("localhost:99999")
+	assert.Error(t, err)
+	_, err = schema.UnserializeAll(s, "localhost:99999")
+	assert.Error(t, err)
+
+	serialized := assert.NoErrorR[any](t)(s.Serialize(capturePatternTestStruct{Host: "localhost", Port: 8080}))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"host": "localhost", "port": int64(8080)})
+}
+
+func TestObjectCapturePatternInvalid(t *testing.T) {
+	properties := map[string]*schema.PropertySchema{
+		"host": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	}
+	assert.Panics(t, func() {
+		schema.NewObjectSchema("test", properties).WithCapturePattern(regexp.MustCompile(`^([^:]+)$`))
+	})
+	assert.Panics(t, func() {
+		schema.NewObjectSchema("test", properties).WithCapturePattern(regexp.MustCompile(`^(?P<hostname>[^:]+)$`))
+	})
+}
+
+func TestObjectCapturePatternSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(newCapturePatternTestSchema())
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	assert.Equals(t, unserializedScope.RootObject().CapturePattern().String(), `^(?P<host>[^:]+)(:(?P<port>\d+))?$`)
+	result := assert.NoErrorR[any](t)(unserializedScope.Unserialize("localhost:8080"))
+	assert.Equals(t, result.(map[string]any), map[string]any{"host": "localhost", "port": int64(8080)})
+}
@@ -1,10 +1,13 @@
 package schema
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
 )
 
 // OneOf is the root interface for one-of types. It should not be used directly but is provided for convenience.
@@ -21,6 +24,96 @@ type OneOfSchema[KeyType int64 | string] struct {
 	DiscriminatorFieldNameValue string             `json:"discriminator_field_name"`
 	// whether or not the discriminator is inlined in the underlying objects' schema
 	DiscriminatorInlined bool `json:"discriminator_inlined"`
+	// Untagged selects the variant by validating the data against each type instead of reading a discriminator field.
+	Untagged bool `json:"untagged,omitempty"`
+	// ValueFieldNameValue is the field that holds the serialized variant data next to the discriminator. If empty,
+	// the discriminator is embedded in the variant data.
+	ValueFieldNameValue string `json:"value_field_name,omitempty"`
+	// DefaultTypeValue is the discriminator value of the type selected when the data has no discriminator field.
+	DefaultTypeValue *KeyType `json:"default_type,omitempty"`
+
+	// lookup holds the precomputed discriminator to type mapping. It is rebuilt whenever a namespace is applied.
+	lookup *oneOfLookupCache[KeyType]
+}
+
+// oneOfLookupCache stores the lookup of a one-of schema. Copies of the schema share the cache, so the lookup is only
+// built once even though the schema methods have value receivers.
+type oneOfLookupCache[KeyType int64 | string] struct {
+	value atomic.Pointer[oneOfLookup[KeyType]]
+}
+
+func newOneOfLookupCache[KeyType int64 | string](types map[KeyType]Object) *oneOfLookupCache[KeyType] {
+	cache := &oneOfLookupCache[KeyType]{}
+	cache.value.Store(newOneOfLookup(types))
+	return cache
+}
+
+// oneOfLookup is a precomputed lookup table for resolving discriminators to their object types without going
+// through references on every call.
+type oneOfLookup[KeyType int64 | string] struct {
+	types               map[KeyType]Object
+	keys                []KeyType
+	validDiscriminators string
+}
+
+func newOneOfLookup[KeyType int64 | string](types map[KeyType]Object) *oneOfLookup[KeyType] {
+	resolvedTypes := make(map[KeyType]Object, len(types))
+	keys := make([]KeyType, 0, len(types))
+	for key, typeValue := range types {
+		if ref, ok := typeValue.(*RefSchema); ok && ref.ObjectReady() {
+			typeValue = ref.GetObject()
+		}
+		resolvedTypes[key] = typeValue
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	validDiscriminators := make([]string, len(keys))
+	for i, key := range keys {
+		validDiscriminators[i] = fmt.Sprintf("%v", key)
+	}
+	return &oneOfLookup[KeyType]{
+		types:               resolvedTypes,
+		keys:                keys,
+		validDiscriminators: strings.Join(validDiscriminators, ", "),
+	}
+}
+
+// getLookup returns the precomputed lookup. If the schema was created as a struct literal without a constructor, there
+// is nowhere to store the lookup and it is computed on every call.
+func (o OneOfSchema[KeyType]) getLookup() *oneOfLookup[KeyType] {
+	if o.lookup == nil {
+		return newOneOfLookup(o.TypesValue)
+	}
+	if lookup := o.lookup.value.Load(); lookup != nil {
+		return lookup
+	}
+	lookup := newOneOfLookup(o.TypesValue)
+	o.lookup.value.Store(lookup)
+	return lookup
+}
+
+// initLookup creates the lookup cache for schemas that were created without a constructor, for example by
+// unserializing a schema. It is called by the types containing the one-of before they apply a namespace.
+func (o *OneOfSchema[KeyType]) initLookup() {
+	if o.lookup == nil {
+		o.lookup = &oneOfLookupCache[KeyType]{}
+	}
+}
+
+// lookupInitializer is implemented by one-of schemas.
+type lookupInitializer interface {
+	initLookup()
+}
+
+// applyNamespace applies the namespace to a type contained in another type, creating the lookup cache first if the
+// type is a one-of schema.
+func applyNamespace(t Type, objects map[string]*ObjectSchema, namespace string) {
+	if initializer, ok := t.(lookupInitializer); ok {
+		initializer.initLookup()
+	}
+	t.ApplyNamespace(objects, namespace)
 }
 
 func (o OneOfSchema[KeyType]) TypeID() TypeID {
@@ -43,11 +136,71 @@ func (o OneOfSchema[KeyType]) DiscriminatorFieldName() string {
 	return o.DiscriminatorFieldNameValue
 }
 
+// ValueFieldName returns the field holding the variant data if the variants are wrapped, or an empty string if the
+// discriminator is embedded in the variant data.
+func (o OneOfSchema[KeyType]) ValueFieldName() string {
+	return o.ValueFieldNameValue
+}
+
+// DefaultType returns the discriminator value of the type used when the data has no discriminator, or nil if the
+// discriminator is required.
+func (o OneOfSchema[KeyType]) DefaultType() *KeyType {
+	return o.DefaultTypeValue
+}
+
+// DefaultToType is a builder-pattern way of selecting the type with the given discriminator value when the input
+// omits the discriminator field. This is useful when one of the types is by far the most common one. Data is always
+// serialized with the discriminator.
+func (o *OneOfSchema[KeyType]) DefaultToType(discriminator KeyType) *OneOfSchema[KeyType] {
+	if o.Untagged {
+		panic(BadArgumentError{
+			Message: "untagged one-of schemas cannot have a default type",
+		})
+	}
+	if _, ok := o.TypesValue[discriminator]; !ok {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf("invalid default type %v, it is not one of the types of the one-of schema", discriminator),
+		})
+	}
+	o.DefaultTypeValue = &discriminator
+	return o
+}
+
+// WrapVariants is a builder-pattern way of serializing the variant data in a separate field next to the
+// discriminator, for example {"type": "a", "value": {...}}, instead of embedding the discriminator in the variant
+// data. This matches external formats without pre- or post-processing. The unserialized form is not affected.
+func (o *OneOfSchema[KeyType]) WrapVariants(valueFieldName string) *OneOfSchema[KeyType] {
+	if o.DiscriminatorInlined || o.Untagged {
+		panic(BadArgumentError{
+			Message: "only one-of schemas with a separate discriminator field can wrap their variants",
+		})
+	}
+	if valueFieldName == "" || valueFieldName == o.DiscriminatorFieldNameValue {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"invalid value field name %q, it must not be empty or the same as the discriminator field",
+				valueFieldName,
+			),
+		})
+	}
+	o.ValueFieldNameValue = valueFieldName
+	return o
+}
+
 func (o OneOfSchema[KeyType]) ApplyNamespace(objects map[string]*ObjectSchema, namespace string) {
 	for _, t := range o.TypesValue {
 		t.ApplyNamespace(objects, namespace)
 	}
+	if o.lookup != nil {
+		o.lookup.value.Store(newOneOfLookup(o.TypesValue))
+	}
 	// scope must be applied before we can access the subtypes' properties
+	if o.Untagged {
+		if err := o.validateUntaggedVariants(); err != nil {
+			panic(err)
+		}
+		return
+	}
 	err := o.validateSubtypeDiscriminatorInlineFields()
 	if err != nil {
 		panic(err)
@@ -64,6 +217,44 @@ func (o OneOfSchema[KeyType]) ValidateReferences() error {
 	return nil
 }
 
+func (o OneOfSchema[KeyType]) ApplyDefaults(serialized any) any {
+	discriminator, selectedType, variantData, err := o.selectVariant(serialized)
+	if err != nil {
+		return serialized
+	}
+	result, ok := ApplyDefaults(selectedType, variantData).(map[string]any)
+	if !ok {
+		return serialized
+	}
+	return o.serializedVariant(discriminator, selectedType, result)
+}
+
+// serializedVariant builds the serialized form of the one-of from the serialized data of the selected type.
+func (o OneOfSchema[KeyType]) serializedVariant(
+	discriminator any,
+	selectedType Object,
+	serialized map[string]any,
+) map[string]any {
+	switch {
+	case o.Untagged:
+		return serialized
+	case o.ValueFieldNameValue != "":
+		var value any = serialized
+		if _, isValueVariant := valueVariantOf(selectedType); isValueVariant {
+			value = serialized[OneOfValueFieldName]
+		}
+		return map[string]any{
+			o.DiscriminatorFieldNameValue: discriminator,
+			o.ValueFieldNameValue:         value,
+		}
+	default:
+		if _, ok := serialized[o.DiscriminatorFieldNameValue]; !ok {
+			serialized[o.DiscriminatorFieldNameValue] = discriminator
+		}
+		return serialized
+	}
+}
+
 func (o OneOfSchema[KeyType]) ReflectedType() reflect.Type {
 	if o.interfaceType == nil {
 		var defaultValue any
@@ -72,39 +263,189 @@ func (o OneOfSchema[KeyType]) ReflectedType() reflect.Type {
 	return o.interfaceType
 }
 
-//nolint:funlen
 func (o OneOfSchema[KeyType]) UnserializeType(data any) (result any, err error) {
+	if o.Untagged {
+		key, selectedType, _, unserializedData, err := o.unserializeUntagged(data)
+		if err != nil {
+			return result, err
+		}
+		return o.unserializedVariant(key, selectedType, unserializedData)
+	}
+	discriminator, selectedType, cloneData, err := o.selectVariant(data)
+	if err != nil {
+		return result, err
+	}
+	unserializedData, err := selectedType.Unserialize(cloneData)
+	if err != nil {
+		return result, ConstraintErrorAddPathSegment(err, fmt.Sprintf("{oneof[%v]}", discriminator))
+	}
+	return o.unserializedVariant(discriminator, selectedType, unserializedData)
+}
+
+// unserializedVariant builds the unserialized form of the one-of from the unserialized data of the selected type.
+func (o OneOfSchema[KeyType]) unserializedVariant(
+	discriminator any,
+	selectedType Object,
+	unserializedData any,
+) (any, error) {
+	unserializedMap, ok := unserializedData.(map[string]any)
+	if _, isValueVariant := valueVariantOf(selectedType); ok && !o.Untagged && !isValueVariant {
+		unserializedMap[o.DiscriminatorFieldNameValue] = discriminator
+		return unserializedMap, nil
+	}
+	return saveConvertTo(unserializedData, o.ReflectedType())
+}
+
+// variantDataValue checks that the serialized data of the one-of is a map with keys the discriminator can be looked up
+// in.
+func (o OneOfSchema[KeyType]) variantDataValue(data any) (reflect.Value, error) {
 	if data == nil {
-		return nil, fmt.Errorf("bug: data is nil in OneOfSchema UnserializeType")
+		return reflect.Value{}, fmt.Errorf("bug: data is nil in OneOfSchema UnserializeType")
 	}
 	reflectedValue := reflect.ValueOf(data)
 	if reflectedValue.Kind() != reflect.Map {
-		return result, &ConstraintError{
+		return reflect.Value{}, &ConstraintError{
 			Message: fmt.Sprintf(
 				"Invalid type for one-of type: %q. Expected map.",
 				reflect.TypeOf(data).Name(),
 			),
+			Constraint: ConstraintDataType,
+			Expected:   "map",
+			Actual:     fmt.Sprintf("%T", data),
+		}
+	}
+
+	if !reflect.TypeOf(o.DiscriminatorFieldNameValue).AssignableTo(reflectedValue.Type().Key()) {
+		return reflect.Value{}, &ConstraintError{
+			Message: fmt.Sprintf(
+				"Invalid key type for one-of: '%s'",
+				reflectedValue.Type().Key().String(),
+			),
+			Constraint: ConstraintDataType,
+			Expected:   "map",
+			Actual:     fmt.Sprintf("%T", data),
 		}
 	}
+	return reflectedValue, nil
+}
 
+// selectVariant finds the object type the serialized data belongs to based on its discriminator. It returns the raw
+// discriminator, the selected type, and the data that should be passed to the selected type.
+//
+//nolint:funlen
+func (o OneOfSchema[KeyType]) selectVariant(data any) (any, Object, map[string]any, error) {
+	if o.Untagged {
+		key, selectedType, typedData, _, err := o.unserializeUntagged(data)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return key, selectedType, typedData, nil
+	}
+	reflectedValue, err := o.variantDataValue(data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	lookup := o.getLookup()
 	discriminatorValue := reflectedValue.MapIndex(reflect.ValueOf(o.DiscriminatorFieldNameValue))
+	if !discriminatorValue.IsValid() && o.DefaultTypeValue != nil {
+		discriminatorValue = reflect.ValueOf(*o.DefaultTypeValue)
+	}
 	if !discriminatorValue.IsValid() {
-		return result, &ConstraintError{
-			Message: fmt.Sprintf("Missing discriminator field '%s' in '%v'", o.DiscriminatorFieldNameValue, data),
+		return nil, nil, nil, &ConstraintError{
+			Message: fmt.Sprintf(
+				"Missing discriminator field '%s', expected one of: %s",
+				o.DiscriminatorFieldNameValue,
+				lookup.validDiscriminators,
+			),
+			Constraint: ConstraintDiscriminator,
+			Expected:   lookup.validDiscriminators,
 		}
 	}
 	discriminator := discriminatorValue.Interface()
 	typedDiscriminator, err := o.getTypedDiscriminator(discriminator)
 	if err != nil {
-		return result, err
+		return nil, nil, nil, err
+	}
+	selectedType, ok := lookup.types[typedDiscriminator]
+	if !ok {
+		return nil, nil, nil, &ConstraintError{
+			Message: fmt.Sprintf(
+				"Invalid value for %q, expected one of: %s",
+				o.DiscriminatorFieldNameValue,
+				lookup.validDiscriminators,
+			),
+			Constraint: ConstraintDiscriminator,
+			Expected:   lookup.validDiscriminators,
+			Actual:     discriminator,
+		}
+	}
+
+	typedData, err := copyVariantData(reflectedValue)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	if o.ValueFieldNameValue != "" {
+		variantData, err := o.unwrapVariantData(typedData, selectedType)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return discriminator, selectedType, variantData, nil
+	}
+	if _, isSet := typedData[o.DiscriminatorFieldNameValue]; !isSet && o.DiscriminatorInlined {
+		// The default type was selected, but the inlined discriminator is also a property of the type.
+		typedData[o.DiscriminatorFieldNameValue] = discriminator
+	}
+	return discriminator, selectedType, o.deleteDiscriminator(typedData), nil
+}
 
+// unwrapVariantData returns the data held in the value field of a one-of with wrapped variants.
+func (o OneOfSchema[KeyType]) unwrapVariantData(data map[string]any, selectedType Object) (map[string]any, error) {
+	for key := range data {
+		if key != o.DiscriminatorFieldNameValue && key != o.ValueFieldNameValue {
+			return nil, &ConstraintError{
+				Message: fmt.Sprintf(
+					"Invalid field '%s', expected only '%s' and '%s'",
+					key,
+					o.DiscriminatorFieldNameValue,
+					o.ValueFieldNameValue,
+				),
+				Constraint: ConstraintUnknownField,
+				Actual:     key,
+			}
+		}
+	}
+	value, isSet := data[o.ValueFieldNameValue]
+	if !isSet {
+		return map[string]any{}, nil
+	}
+	if _, isValueVariant := valueVariantOf(selectedType); isValueVariant {
+		return map[string]any{OneOfValueFieldName: value}, nil
+	}
+	reflectedValue := reflect.ValueOf(value)
+	if reflectedValue.Kind() != reflect.Map {
+		return nil, &ConstraintError{
+			Message:    fmt.Sprintf("Must be a map, %T given", value),
+			Path:       []string{o.ValueFieldNameValue},
+			Constraint: ConstraintDataType,
+			Expected:   "map",
+			Actual:     fmt.Sprintf("%T", value),
+		}
+	}
+	variantData, err := copyVariantData(reflectedValue)
+	if err != nil {
+		return nil, ConstraintErrorAddPathSegment(err, o.ValueFieldNameValue)
+	}
+	return variantData, nil
+}
+
+// copyVariantData copies the reflected map into a string-keyed map that can be passed to the selected type.
+func copyVariantData(reflectedValue reflect.Value) (map[string]any, error) {
 	typedData := make(map[string]any, reflectedValue.Len())
 	for _, k := range reflectedValue.MapKeys() {
 		v := reflectedValue.MapIndex(k)
 		keyString, ok := k.Interface().(string)
 		if !ok {
-			return result, &ConstraintError{
+			return nil, &ConstraintError{
 				Message: fmt.Sprintf(
 					"Invalid key type for one-of: '%T'",
 					k.Interface(),
@@ -113,50 +454,70 @@ func (o OneOfSchema[KeyType]) UnserializeType(data any) (result any, err error)
 		}
 		typedData[keyString] = v.Interface()
 	}
+	return typedData, nil
+}
 
-	selectedType, ok := o.TypesValue[typedDiscriminator]
-	if !ok {
-		validDiscriminators := make([]string, len(o.TypesValue))
-		i := 0
-		for k := range o.TypesValue {
-			validDiscriminators[i] = fmt.Sprintf("%v", k)
-			i++
+// inferVariant selects the only type of an untagged one-of that accepts the data.
+func (o OneOfSchema[KeyType]) inferVariant(
+	data map[string]any,
+	accepts func(t Object, data map[string]any) error,
+) (KeyType, Object, error) {
+	var nilKey KeyType
+	lookup := o.getLookup()
+	var matches []KeyType
+	var mismatches []string
+	for _, key := range lookup.keys {
+		if err := accepts(lookup.types[key], data); err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%v (%s)", key, err.Error()))
+			continue
 		}
-		return result, &ConstraintError{
+		matches = append(matches, key)
+	}
+	switch len(matches) {
+	case 0:
+		return nilKey, nil, &ConstraintError{
 			Message: fmt.Sprintf(
-				"Invalid value for %q, expected one of: %s",
-				o.DiscriminatorFieldNameValue,
-				strings.Join(validDiscriminators, ", "),
+				"Data does not match any of the one-of types: %s",
+				strings.Join(mismatches, ", "),
 			),
+			Constraint: ConstraintDiscriminator,
+			Expected:   lookup.validDiscriminators,
+		}
+	case 1:
+		return matches[0], lookup.types[matches[0]], nil
+	default:
+		return nilKey, nil, &ConstraintError{
+			Message:    fmt.Sprintf("Data matches more than one of the one-of types: %v", matches),
+			Constraint: ConstraintDiscriminator,
+			Expected:   lookup.validDiscriminators,
+			Actual:     fmt.Sprintf("%v", matches),
 		}
 	}
+}
 
-	cloneData := o.deleteDiscriminator(typedData)
-	unserializedData, err := selectedType.Unserialize(cloneData)
+func (o OneOfSchema[KeyType]) ValidateType(data any) error {
+	discriminatorValue, underlyingType, variantData, err := o.selectUnserializedVariant(data)
 	if err != nil {
-		return result, err
+		return err
 	}
-	unserializedMap, ok := unserializedData.(map[string]any)
-	if ok {
-		unserializedMap[o.DiscriminatorFieldNameValue] = discriminator
-		return unserializedMap, nil
+	if err := underlyingType.Validate(variantData); err != nil {
+		return ConstraintErrorAddPathSegment(err, fmt.Sprintf("{oneof[%v]}", discriminatorValue))
 	}
-	return saveConvertTo(unserializedData, o.ReflectedType())
+	return nil
 }
 
-func (o OneOfSchema[KeyType]) ValidateType(data any) error {
+// selectUnserializedVariant finds the object type the unserialized data belongs to. It returns the discriminator, the
+// selected type, and the data that should be validated by the selected type.
+func (o OneOfSchema[KeyType]) selectUnserializedVariant(data any) (any, Object, any, error) {
 	discriminatorValue, underlyingType, err := o.findUnderlyingType(data)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 	dataMap, ok := data.(map[string]any)
-	if ok {
+	if _, isValueVariant := valueVariantOf(underlyingType); ok && !isValueVariant {
 		data = o.deleteDiscriminator(dataMap)
 	}
-	if err := underlyingType.Validate(data); err != nil {
-		return ConstraintErrorAddPathSegment(err, fmt.Sprintf("{oneof[%v]}", discriminatorValue))
-	}
-	return nil
+	return discriminatorValue, underlyingType, data, nil
 }
 
 func (o OneOfSchema[KeyType]) SerializeType(data any) (any, error) {
@@ -165,18 +526,14 @@ func (o OneOfSchema[KeyType]) SerializeType(data any) (any, error) {
 		return nil, err
 	}
 	dataMap, ok := data.(map[string]any)
-	if ok {
+	if _, isValueVariant := valueVariantOf(underlyingType); ok && !isValueVariant {
 		data = o.deleteDiscriminator(dataMap)
 	}
 	serializedData, err := underlyingType.Serialize(data)
 	if err != nil {
 		return nil, err
 	}
-	mapData := serializedData.(map[string]any)
-	if _, ok := mapData[o.DiscriminatorFieldNameValue]; !ok {
-		mapData[o.DiscriminatorFieldNameValue] = discriminatorValue
-	}
-	return mapData, nil
+	return o.serializedVariant(discriminatorValue, underlyingType, serializedData.(map[string]any)), nil
 }
 
 func (o OneOfSchema[KeyType]) Unserialize(data any) (any, error) {
@@ -191,6 +548,15 @@ func (o OneOfSchema[KeyType]) ValidateCompatibility(typeOrData any) error {
 	// If not, verify it as data.
 	inputAsMap, ok := typeOrData.(map[string]any)
 	if ok {
+		if o.ValueFieldNameValue != "" {
+			// Bring the wrapped data into the unserialized form, which has the discriminator embedded.
+			discriminator, _, variantData, err := o.selectVariant(inputAsMap)
+			if err != nil {
+				return err
+			}
+			inputAsMap = maps.Clone(variantData)
+			inputAsMap[o.DiscriminatorFieldNameValue] = discriminator
+		}
 		_, _, err := o.validateMap(inputAsMap)
 		return err
 	}
@@ -218,6 +584,27 @@ func (o OneOfSchema[KeyType]) ValidateCompatibility(typeOrData any) error {
 func (o OneOfSchema[KeyType]) validateSchema(otherSchema OneOfSchema[KeyType]) error {
 	// Validate that the discriminator fields match, and all other values match.
 
+	if otherSchema.ValueFieldName() != o.ValueFieldName() {
+		return &ConstraintError{
+			Message: fmt.Sprintf(
+				"validation failed for OneOfSchema. Value field name (%s) does not match expected field name (%s)",
+				otherSchema.ValueFieldName(), o.ValueFieldName()),
+		}
+	}
+	if otherSchema.Untagged != o.Untagged {
+		return &ConstraintError{
+			Message: fmt.Sprintf(
+				"validation failed for OneOfSchema. Untagged (%t) does not match expected value (%t)",
+				otherSchema.Untagged, o.Untagged),
+		}
+	}
+	if !reflect.DeepEqual(otherSchema.DefaultType(), o.DefaultType()) {
+		return &ConstraintError{
+			Message: fmt.Sprintf(
+				"validation failed for OneOfSchema. Default type (%s) does not match expected default type (%s)",
+				formatOptionalKey(otherSchema.DefaultType()), formatOptionalKey(o.DefaultType())),
+		}
+	}
 	// Validate the discriminator field name
 	if otherSchema.DiscriminatorFieldName() != o.DiscriminatorFieldName() {
 		return &ConstraintError{
@@ -249,10 +636,22 @@ func (o OneOfSchema[KeyType]) validateSchema(otherSchema OneOfSchema[KeyType]) e
 
 func (o OneOfSchema[KeyType]) validateMap(data map[string]any) (KeyType, Object, error) {
 	var nilKey KeyType
+	if o.Untagged {
+		return o.inferVariant(data, func(t Object, data map[string]any) error {
+			return t.ValidateCompatibility(data)
+		})
+	}
 	// Validate that it has the discriminator field.
 	// If it doesn't, fail
 	// If it does, pass the non-discriminator fields into the ValidateCompatibility method for the object
 	selectedTypeID := data[o.DiscriminatorFieldNameValue]
+	if selectedTypeID == nil && o.DefaultTypeValue != nil {
+		selectedTypeID = *o.DefaultTypeValue
+		if o.DiscriminatorInlined {
+			data = maps.Clone(data)
+			data[o.DiscriminatorFieldNameValue] = selectedTypeID
+		}
+	}
 	if selectedTypeID == nil {
 		return nilKey, nil, &ConstraintError{
 			Message: fmt.Sprintf(
@@ -269,17 +668,21 @@ func (o OneOfSchema[KeyType]) validateMap(data map[string]any) (KeyType, Object,
 		}
 	}
 	// Find the object that's associated with the selected type
-	selectedSchema := o.TypesValue[selectedTypeIDAsserted]
+	lookup := o.getLookup()
+	selectedSchema := lookup.types[selectedTypeIDAsserted]
 	if selectedSchema == nil {
 		return nilKey, nil, &ConstraintError{
 			Message: fmt.Sprintf(
-				"validation failed for OneOfSchema. Discriminator value '%v' is invalid. Expected one of: %v",
-				selectedTypeIDAsserted, o.getTypeValues()),
+				"validation failed for OneOfSchema. Discriminator value '%v' is invalid. Expected one of: %s",
+				selectedTypeIDAsserted, lookup.validDiscriminators),
 		}
 	}
 	cloneData := o.deleteDiscriminator(data)
-	err := selectedSchema.ValidateCompatibility(cloneData)
-	if err != nil {
+	if err := selectedSchema.ValidateCompatibility(cloneData); err != nil {
+		var constraintErr *ConstraintError
+		if errors.As(err, &constraintErr) {
+			return nilKey, nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("{oneof[%v]}", selectedTypeIDAsserted))
+		}
 		return nilKey, nil, &ConstraintError{
 			Message: fmt.Sprintf(
 				"validation failed for OneOfSchema. Failed to validate as selected schema type '%T' from discriminator value '%v' (%s)",
@@ -289,16 +692,6 @@ func (o OneOfSchema[KeyType]) validateMap(data map[string]any) (KeyType, Object,
 	return selectedTypeIDAsserted, selectedSchema, nil
 }
 
-func (o OneOfSchema[KeyType]) getTypeValues() []KeyType {
-	output := make([]KeyType, len(o.TypesValue))
-	i := 0
-	for key := range o.TypesValue {
-		output[i] = key
-		i += 1
-	}
-	return output
-}
-
 func (o OneOfSchema[KeyType]) Validate(data any) error {
 	d, err := saveConvertTo(data, o.ReflectedType())
 	if err != nil {
@@ -316,6 +709,10 @@ func (o OneOfSchema[KeyType]) Serialize(data any) (result any, err error) {
 }
 
 func (o OneOfSchema[KeyType]) getTypedDiscriminator(discriminator any) (KeyType, error) {
+	if typedDiscriminator, ok := discriminator.(KeyType); ok {
+		// Fast path: the discriminator already has the correct type.
+		return typedDiscriminator, nil
+	}
 	var typedDiscriminator KeyType
 	switch any(typedDiscriminator).(type) {
 	case int64:
@@ -353,6 +750,15 @@ func (o OneOfSchema[KeyType]) getTypedDiscriminator(discriminator any) (KeyType,
 func (o OneOfSchema[KeyType]) findUnderlyingType(data any) (KeyType, Object, error) {
 	var nilKey KeyType
 
+	if data == nil {
+		return nilKey, nil, &ConstraintError{
+			Message:    "Invalid type for one-of type: nil, expected struct or map.",
+			Constraint: ConstraintDataType,
+		}
+	}
+	if key, valueVariant, ok := o.findValueVariant(data); ok {
+		return key, valueVariant, nil
+	}
 	reflectedType := reflect.TypeOf(data)
 	if reflectedType.Kind() != reflect.Struct &&
 		reflectedType.Kind() != reflect.Map &&
@@ -366,41 +772,56 @@ func (o OneOfSchema[KeyType]) findUnderlyingType(data any) (KeyType, Object, err
 		}
 	}
 
-	var foundKey *KeyType
 	if reflectedType.Kind() == reflect.Map {
-		myKey, mySchemaObj, err := o.validateMap(data.(map[string]any))
-		if err != nil {
-			return nilKey, nil, err
+		return o.findMapVariant(data)
+	}
+	return o.findStructVariant(reflectedType)
+}
+
+// findMapVariant finds the type of a one-of given as a map, by its discriminator or, if the one-of is untagged, by
+// its properties.
+func (o OneOfSchema[KeyType]) findMapVariant(data any) (KeyType, Object, error) {
+	mapData, ok := data.(map[string]any)
+	if !ok {
+		var nilKey KeyType
+		return nilKey, nil, &ConstraintError{
+			Message:    fmt.Sprintf("Invalid type for one-of type: %T, expected map[string]any.", data),
+			Constraint: ConstraintDataType,
+			Expected:   "map",
+			Actual:     fmt.Sprintf("%T", data),
 		}
-		return myKey, mySchemaObj, nil
 	}
+	if o.Untagged {
+		return o.inferVariant(mapData, func(t Object, data map[string]any) error {
+			return t.Validate(data)
+		})
+	}
+	return o.validateMap(mapData)
+}
+
+// findStructVariant finds the type of a one-of given as a struct by its reflected type.
+func (o OneOfSchema[KeyType]) findStructVariant(reflectedType reflect.Type) (KeyType, Object, error) {
 	for key, ref := range o.TypesValue {
-		underlyingReflectedType := ref.ReflectedType()
-		if underlyingReflectedType == reflectedType {
-			keyValue := key
-			foundKey = &keyValue
-		}
-	}
-	if foundKey == nil {
-		dataType := reflect.TypeOf(data)
-		values := make([]string, len(o.TypesValue))
-		i := 0
-		for _, ref := range o.TypesValue {
-			values[i] = ref.ReflectedType().String()
-			if values[i] == "" {
-				panic(fmt.Errorf("bug: reflected type name is empty"))
-			}
-			i++
+		if ref.ReflectedType() == reflectedType {
+			return key, ref, nil
 		}
-		return nilKey, nil, &ConstraintError{
-			Message: fmt.Sprintf(
-				"Invalid type for one-of schema: '%s' (valid types are: %s)",
-				dataType.String(),
-				strings.Join(values, ", "),
-			),
+	}
+	values := make([]string, 0, len(o.TypesValue))
+	for _, ref := range o.TypesValue {
+		value := ref.ReflectedType().String()
+		if value == "" {
+			panic(fmt.Errorf("bug: reflected type name is empty"))
 		}
+		values = append(values, value)
+	}
+	var nilKey KeyType
+	return nilKey, nil, &ConstraintError{
+		Message: fmt.Sprintf(
+			"Invalid type for one-of schema: '%s' (valid types are: %s)",
+			reflectedType.String(),
+			strings.Join(values, ", "),
+		),
 	}
-	return *foundKey, o.TypesValue[*foundKey], nil
 }
 
 // validateSubtypeDiscriminatorInlineFields checks to see if a subtype's
@@ -430,10 +851,18 @@ func (o OneOfSchema[KeyType]) validateSubtypeDiscriminatorInlineFields() error {
 
 func (o OneOfSchema[KeyType]) deleteDiscriminator(mymap map[string]any) map[string]any {
 	// the discriminator is not a property of the subtype
-	if !o.DiscriminatorInlined {
+	if !o.DiscriminatorInlined && !o.Untagged {
 		cloneData := maps.Clone(mymap)
 		delete(cloneData, o.DiscriminatorFieldNameValue)
 		return cloneData
 	}
 	return mymap
 }
+
+// formatOptionalKey formats an optional discriminator value for error messages.
+func formatOptionalKey[KeyType int64 | string](key *KeyType) string {
+	if key == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%v", *key)
+}
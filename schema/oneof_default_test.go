@@ -0,0 +1,163 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type oneOfDefaultTestFile struct {
+	Path string `json:"path"`
+}
+
+type oneOfDefaultTestURL struct {
+	URL string `json:"url"`
+}
+
+func newOneOfDefaultTestSchema() *schema.OneOfSchema[string] {
+	return schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{
+			"file": schema.NewStructMappedObjectSchema[oneOfDefaultTestFile](
+				"file",
+				map[string]*schema.PropertySchema{
+					"path": schema.NewPropertySchema(
+						schema.NewStringSchema(nil, nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+			"url": schema.NewStructMappedObjectSchema[oneOfDefaultTestURL](
+				"url",
+				map[string]*schema.PropertySchema{
+					"url": schema.NewPropertySchema(
+						schema.NewStringSchema(nil, nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+		},
+		"_type",
+		false,
+	).DefaultToType("file")
+}
+
+func TestOneOfDefaultType(t *testing.T) {
+	s := newOneOfDefaultTestSchema()
+	assert.Equals(t, *s.DefaultType(), "file")
+
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"path": "/tmp/test"}))
+	assert.Equals(t, unserialized.(oneOfDefaultTestFile), oneOfDefaultTestFile{Path: "/tmp/test"})
+	unserialized = assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"_type": "url", "url": "https://example.com"}))
+	assert.Equals(t, unserialized.(oneOfDefaultTestURL), oneOfDefaultTestURL{URL: "https://example.com"})
+
+	// The serialized form always contains the discriminator.
+	serialized := assert.NoErrorR[any](t)(s.Serialize(oneOfDefaultTestFile{Path: "/tmp/test"}))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"_type": "file", "path": "/tmp/test"})
+
+	assert.NoError(t, s.ValidateCompatibility(map[string]any{"path": "/tmp/test"}))
+	assert.Error(t, s.ValidateCompatibility(map[string]any{"url": "https://example.com"}))
+
+	// Without the discriminator, data of other types is validated against the default type.
+	_, err := s.Unserialize(map[string]any{"url": "https://example.com"})
+	assert.Error(t, err)
+}
+
+func TestOneOfDefaultTypeInlined(t *testing.T) {
+	s := schema.NewOneOfIntSchema[any](
+		map[int64]schema.Object{
+			1: schema.NewObjectSchema(
+				"first",
+				map[string]*schema.PropertySchema{
+					"kind": schema.NewPropertySchema(
+						schema.NewIntSchema(nil, nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+			2: schema.NewObjectSchema(
+				"second",
+				map[string]*schema.PropertySchema{
+					"kind": schema.NewPropertySchema(
+						schema.NewIntSchema(nil, nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+		},
+		"kind",
+		true,
+	).DefaultToType(2)
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{}))
+	assert.Equals(t, unserialized.(map[string]any), map[string]any{"kind": int64(2)})
+	assert.NoError(t, s.ValidateCompatibility(map[string]any{}))
+}
+
+func TestOneOfDefaultTypeInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		newOneOfDefaultTestSchema().DefaultToType("nonexistent")
+	})
+	assert.Panics(t, func() {
+		schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{}).DefaultToType("file")
+	})
+}
+
+func TestOneOfDefaultTypeCompatibility(t *testing.T) {
+	withDefault := newOneOfDefaultTestSchema()
+	withoutDefault := newOneOfDefaultTestSchema()
+	withoutDefault.DefaultTypeValue = nil
+	assert.NoError(t, withDefault.ValidateCompatibility(newOneOfDefaultTestSchema()))
+	assert.Error(t, withDefault.ValidateCompatibility(withoutDefault))
+}
+
+func TestOneOfDefaultTypeSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema(
+			"root",
+			map[string]*schema.PropertySchema{
+				"source": schema.NewPropertySchema(
+					newOneOfDefaultTestSchema(),
+					nil,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+		),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	source := unserializedScope.RootObject().Properties()["source"].Type().(*schema.OneOfSchema[string])
+	assert.Equals(t, *source.DefaultType(), "file")
+	data := assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"source": map[string]any{"path": "a"}}))
+	assert.Equals(t, data.(map[string]any)["source"].(map[string]any)["_type"].(string), "file")
+}
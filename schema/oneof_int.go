@@ -17,9 +17,10 @@ func NewOneOfIntSchema[ItemsInterface any](
 ) *OneOfSchema[int64] {
 	var defaultValue ItemsInterface
 	return &OneOfSchema[int64]{
-		reflect.TypeOf(&defaultValue).Elem(),
-		types,
-		discriminatorFieldName,
-		discriminatorInlined,
+		interfaceType:               reflect.TypeOf(&defaultValue).Elem(),
+		TypesValue:                  types,
+		DiscriminatorFieldNameValue: discriminatorFieldName,
+		DiscriminatorInlined:        discriminatorInlined,
+		lookup:                      newOneOfLookupCache(types),
 	}
 }
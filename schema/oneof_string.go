@@ -17,9 +17,10 @@ func NewOneOfStringSchema[ItemsInterface any](
 ) *OneOfSchema[string] {
 	var defaultValue ItemsInterface
 	return &OneOfSchema[string]{
-		reflect.TypeOf(&defaultValue).Elem(),
-		types,
-		discriminatorFieldName,
-		discriminatorInlined,
+		interfaceType:               reflect.TypeOf(&defaultValue).Elem(),
+		TypesValue:                  types,
+		DiscriminatorFieldNameValue: discriminatorFieldName,
+		DiscriminatorInlined:        discriminatorInlined,
+		lookup:                      newOneOfLookupCache(types),
 	}
 }
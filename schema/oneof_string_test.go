@@ -174,6 +174,26 @@ func TestOneOfStringUnserialization(t *testing.T) {
 	assert.Equals(t, unserialized2, unserializedData)
 }
 
+func TestOneOfStringUnserializationDiscriminatorErrors(t *testing.T) {
+	_, err := oneOfStringTestObjectAType.Unserialize(map[string]any{
+		"s": map[string]any{
+			"message": "Hello world!",
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Missing discriminator field '_type'")
+	assert.Contains(t, err.Error(), "expected one of: B, C")
+
+	_, err = oneOfStringTestObjectAType.Unserialize(map[string]any{
+		"s": map[string]any{
+			"_type":   "X",
+			"message": "Hello world!",
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `Invalid value for "_type", expected one of: B, C`)
+}
+
 func TestOneOfStringCompatibilityValidation(t *testing.T) {
 	// The ones with NoError are matching schemas
 	// All others have one thing that differs, so should error.
@@ -553,3 +573,23 @@ func TestOneOf_Error_SubtypeHasDiscriminator(t *testing.T) {
 			}))
 	}, expMsg)
 }
+
+func TestOneOfStringInvalidMapTypes(t *testing.T) {
+	s := oneOfStringTestObjectAType.Objects()["A"].Properties()["s"].Type()
+	for name, data := range map[string]any{
+		"nil":            nil,
+		"int keys":       map[int]any{1: 2},
+		"any keys":       map[any]any{"a": 1},
+		"nil any key":    map[any]any{nil: 1},
+		"non-string key": map[any]any{[2]int{1, 2}: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Unserialize(data)
+			assert.Error(t, err)
+			assert.Error(t, s.Validate(data))
+			assert.Error(t, s.ValidateCompatibility(data))
+			_, err = s.Serialize(data)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -218,3 +218,19 @@ func Test_OneOf_ConstructorBypass(t *testing.T) { //nolint:funlen
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), error_msg)
 }
+
+func TestOneOfValueReceivers(t *testing.T) {
+	// Callers may hold the one-of schema as a value, which must still be usable as a type.
+	var s schema.Type = *schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{
+			"B": oneOfTestBMappedSchema,
+			"C": oneOfTestCMappedSchema,
+		},
+		"_type",
+		false,
+	)
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"_type": "B", "message": "Hello world!"}))
+	assert.Equals(t, unserialized.(oneOfTestObjectB).Message, "Hello world!")
+	serialized := assert.NoErrorR[any](t)(s.Serialize(unserialized))
+	assert.Equals(t, serialized.(map[string]any)["_type"].(string), "B")
+}
@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewUntaggedOneOfStringSchema creates a new OneOf-type without a discriminator field. This is useful for external
+// formats that don't embed a discriminator. The variant is selected by validating the data against each of the types,
+// and the keys of the types map only serve as names for the variants. The types must be structurally distinct: each
+// pair of types needs a required property that the other type doesn't accept, or a required enum property whose
+// values don't overlap. Otherwise, building the schema panics with a BadArgumentError.
+func NewUntaggedOneOfStringSchema[ItemsInterface any](types map[string]Object) *OneOfSchema[string] {
+	var defaultValue ItemsInterface
+	o := &OneOfSchema[string]{
+		interfaceType: reflect.TypeOf(&defaultValue).Elem(),
+		TypesValue:    types,
+		Untagged:      true,
+		lookup:        newOneOfLookupCache(types),
+	}
+	if err := o.validateUntaggedVariants(); err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// unserializeUntagged selects the variant of an untagged one-of by unserializing the data with each type. It returns
+// the only type that accepted the data, the data passed to it, and its unserialized result.
+func (o OneOfSchema[KeyType]) unserializeUntagged(
+	data any,
+) (key KeyType, selectedType Object, typedData map[string]any, unserialized any, err error) {
+	reflectedValue, err := o.variantDataValue(data)
+	if err != nil {
+		return key, nil, nil, nil, err
+	}
+	typedData, err = copyVariantData(reflectedValue)
+	if err != nil {
+		return key, nil, nil, nil, err
+	}
+	// Only one type may accept the data, so the last successful result belongs to the selected type.
+	key, selectedType, err = o.inferVariant(typedData, func(t Object, data map[string]any) error {
+		result, err := t.Unserialize(data)
+		if err == nil {
+			unserialized = result
+		}
+		return err
+	})
+	if err != nil {
+		return key, nil, nil, nil, err
+	}
+	return key, selectedType, typedData, unserialized, nil
+}
+
+// validateUntaggedVariants checks that no data can match more than one type of an untagged one-of. The check is
+// skipped while references are unresolved, and repeated when the scope is applied.
+func (o OneOfSchema[KeyType]) validateUntaggedVariants() error {
+	lookup := o.getLookup()
+	objects := make(map[KeyType]*ObjectSchema, len(lookup.types))
+	for key, typeValue := range lookup.types {
+		if ref, ok := typeValue.(*RefSchema); ok && !ref.ObjectReady() {
+			return nil
+		}
+		objectSchema, ok := objectSchemaOf(typeValue)
+		if !ok {
+			return BadArgumentError{
+				Message: fmt.Sprintf("type %v of the untagged one-of is not an object (%T)", key, typeValue),
+			}
+		}
+		objects[key] = objectSchema
+	}
+	for i, key := range lookup.keys {
+		for _, otherKey := range lookup.keys[i+1:] {
+			if !hasDistinguishingProperty(objects[key], objects[otherKey]) &&
+				!hasDistinguishingProperty(objects[otherKey], objects[key]) {
+				return BadArgumentError{
+					Message: fmt.Sprintf(
+						"types %v and %v of the untagged one-of cannot be told apart; add a required property "+
+							"to one of them that the other doesn't accept, or a required enum property with "+
+							"different values to both",
+						key,
+						otherKey,
+					),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasDistinguishingProperty returns true if the object has a required property that guarantees that its data is
+// rejected by the other object, and that the other object's data is rejected by it.
+func hasDistinguishingProperty(object *ObjectSchema, other *ObjectSchema) bool {
+	for propertyID, property := range object.PropertiesValue {
+		if !property.Required() {
+			continue
+		}
+		otherPropertyID, ok := other.resolveKey(propertyID)
+		if !ok {
+			if other.UnknownFields() == UnknownFieldsReject {
+				return true
+			}
+			continue
+		}
+		if enumValuesDisjoint(property.Type(), other.PropertiesValue[otherPropertyID].Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumValuesDisjoint returns true if both types are enums and no value is valid for both.
+func enumValuesDisjoint(a Type, b Type) bool {
+	aValues, ok := enumValueSet(a)
+	if !ok {
+		return false
+	}
+	bValues, ok := enumValueSet(b)
+	if !ok {
+		return false
+	}
+	for value := range aValues {
+		if _, ok := bValues[value]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// enumValueSet returns the valid values of an enum type in their string form. Values of int and string enums are
+// compared by their string form, since the int schema also accepts numeric strings.
+func enumValueSet(t Type) (map[string]struct{}, bool) {
+	if t.TypeID() != TypeIDStringEnum && t.TypeID() != TypeIDIntEnum {
+		return nil, false
+	}
+	validValues := reflect.ValueOf(t).MethodByName("ValidValues")
+	if !validValues.IsValid() {
+		return nil, false
+	}
+	result := map[string]struct{}{}
+	for iter := validValues.Call(nil)[0].MapRange(); iter.Next(); {
+		result[fmt.Sprintf("%v", iter.Key().Interface())] = struct{}{}
+	}
+	return result, true
+}
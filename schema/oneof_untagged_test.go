@@ -0,0 +1,155 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type untaggedCircle struct {
+	Radius int64 `json:"radius"`
+}
+
+type untaggedSquare struct {
+	Side int64 `json:"side"`
+}
+
+var untaggedShapeSchema = schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{
+	"circle": schema.NewStructMappedObjectSchema[untaggedCircle]("circle", map[string]*schema.PropertySchema{
+		"radius": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	}),
+	"square": schema.NewStructMappedObjectSchema[untaggedSquare]("square", map[string]*schema.PropertySchema{
+		"side": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+	}),
+})
+
+func TestUntaggedOneOf(t *testing.T) {
+	unserialized := assert.NoErrorR[any](t)(untaggedShapeSchema.Unserialize(map[string]any{"radius": 2}))
+	assert.Equals(t, unserialized.(untaggedCircle), untaggedCircle{Radius: 2})
+	unserialized = assert.NoErrorR[any](t)(untaggedShapeSchema.Unserialize(map[string]any{"side": 3}))
+	assert.Equals(t, unserialized.(untaggedSquare), untaggedSquare{Side: 3})
+
+	assert.NoError(t, untaggedShapeSchema.Validate(untaggedSquare{Side: 3}))
+	serialized := assert.NoErrorR[any](t)(untaggedShapeSchema.Serialize(untaggedSquare{Side: 3}))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"side": int64(3)})
+
+	_, err := untaggedShapeSchema.Unserialize(map[string]any{"width": 3})
+	assert.Error(t, err)
+	_, err = untaggedShapeSchema.Unserialize(map[string]any{"radius": 2, "side": 3})
+	assert.Error(t, err)
+}
+
+func TestUntaggedOneOfUnserializesOnce(t *testing.T) {
+	calls := 0
+	circle := schema.WithValidator[untaggedCircle](
+		schema.NewStructMappedObjectSchema[untaggedCircle]("circle", map[string]*schema.PropertySchema{
+			"radius": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		}),
+		func(_ untaggedCircle) error {
+			calls++
+			return nil
+		},
+	)
+	s := schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{
+		"circle": circle,
+		"square": untaggedShapeSchema.Types()["square"],
+	})
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"radius": 2}))
+	assert.Equals(t, unserialized.(untaggedCircle), untaggedCircle{Radius: 2})
+	assert.Equals(t, calls, 1)
+}
+
+func newUntaggedKindObject(id string, kinds ...string) *schema.ObjectSchema {
+	validValues := map[string]*schema.DisplayValue{}
+	for _, kind := range kinds {
+		validValues[kind] = &schema.DisplayValue{NameValue: schema.PointerTo(kind)}
+	}
+	return schema.NewObjectSchema(id, map[string]*schema.PropertySchema{
+		"kind": schema.NewPropertySchema(
+			schema.NewStringEnumSchema(validValues),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"size": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+	})
+}
+
+func TestUntaggedOneOfEnumProperty(t *testing.T) {
+	s := schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{
+		"fruit":     newUntaggedKindObject("fruit", "apple", "banana"),
+		"vegetable": newUntaggedKindObject("vegetable", "carrot"),
+	})
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"kind": "carrot", "size": 1}))
+	assert.Equals(t, unserialized.(map[string]any), map[string]any{"kind": "carrot", "size": int64(1)})
+	assert.NoError(t, s.Validate(unserialized))
+	serialized := assert.NoErrorR[any](t)(s.Serialize(unserialized))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"kind": "carrot", "size": int64(1)})
+}
+
+func TestUntaggedOneOfAmbiguous(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{
+			"fruit":     newUntaggedKindObject("fruit", "apple", "banana"),
+			"vegetable": newUntaggedKindObject("vegetable", "banana"),
+		})
+	})
+	// References are checked when the scope is applied.
+	assert.Panics(t, func() {
+		schema.NewScopeSchema(
+			schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+				"food": schema.NewPropertySchema(
+					schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{
+						"fruit":     schema.NewRefSchema("fruit", nil),
+						"vegetable": schema.NewRefSchema("vegetable", nil),
+					}),
+					nil,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			}),
+			newUntaggedKindObject("fruit", "apple"),
+			newUntaggedKindObject("vegetable", "apple"),
+		)
+	})
+}
+
+func TestUntaggedOneOfSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+			"food": schema.NewPropertySchema(
+				schema.NewUntaggedOneOfStringSchema[any](map[string]schema.Object{
+					"fruit":     schema.NewRefSchema("fruit", nil),
+					"vegetable": schema.NewRefSchema("vegetable", nil),
+				}),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		newUntaggedKindObject("fruit", "apple"),
+		newUntaggedKindObject("vegetable", "carrot"),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	oneOf := unserializedScope.Objects()["root"].Properties()["food"].Type().(*schema.OneOfSchema[string])
+	assert.Equals(t, oneOf.Untagged, true)
+
+	data := map[string]any{"food": map[string]any{"kind": "apple"}}
+	assert.NoErrorR[any](t)(unserializedScope.Unserialize(data))
+}
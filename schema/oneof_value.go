@@ -0,0 +1,63 @@
+package schema
+
+import "reflect"
+
+// OneOfValueFieldName is the name of the field that holds the value of a non-object one-of variant when serialized.
+const OneOfValueFieldName = "value"
+
+// NewOneOfValueVariant wraps a non-object type, such as a string, int, list, or map, so it can be used as a variant
+// of a one-of type. This supports configurations that can be either a shortcut value or a full object. The value is
+// serialized in a wrapper object next to the discriminator, for example {"_type": "name", "value": "example"}, but
+// unserializes to the value itself. Each value variant of a one-of type must have a different reflected type so the
+// variant can be found when serializing, and map values must not contain the discriminator field.
+func NewOneOfValueVariant(id string, valueType Type) *ObjectSchema {
+	o := NewObjectSchema(id, map[string]*PropertySchema{
+		OneOfValueFieldName: NewPropertySchema(
+			valueType,
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	})
+	o.ValuePropertyValue = OneOfValueFieldName
+	return o
+}
+
+// valueVariantOf returns the wrapper object if the one-of variant was created with NewOneOfValueVariant.
+func valueVariantOf(t Object) (*ObjectSchema, bool) {
+	if ref, ok := t.(*RefSchema); ok {
+		if !ref.ObjectReady() {
+			return nil, false
+		}
+		t = ref.GetObject()
+	}
+	objectSchema, ok := t.(*ObjectSchema)
+	if !ok || objectSchema.ValuePropertyValue == "" {
+		return nil, false
+	}
+	return objectSchema, true
+}
+
+// findValueVariant returns the value variant matching the type of the unserialized data, if any. Maps holding the
+// discriminator field are left to the object variants.
+func (o OneOfSchema[KeyType]) findValueVariant(data any) (KeyType, Object, bool) {
+	var nilKey KeyType
+	if mapData, ok := data.(map[string]any); ok {
+		if _, hasDiscriminator := mapData[o.DiscriminatorFieldNameValue]; hasDiscriminator {
+			return nilKey, nil, false
+		}
+	}
+	reflectedType := reflect.TypeOf(data)
+	lookup := o.getLookup()
+	for _, key := range lookup.keys {
+		valueVariant, ok := valueVariantOf(lookup.types[key])
+		if ok && valueVariant.ReflectedType() == reflectedType {
+			return key, valueVariant, true
+		}
+	}
+	return nilKey, nil, false
+}
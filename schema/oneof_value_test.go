@@ -0,0 +1,119 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type oneOfValueTestFile struct {
+	Path string `json:"path"`
+}
+
+var oneOfValueTestSchema = schema.NewOneOfStringSchema[any](
+	map[string]schema.Object{
+		"url": schema.NewOneOfValueVariant("url", schema.NewStringSchema(schema.IntPointer(1), nil, nil)),
+		"urls": schema.NewOneOfValueVariant(
+			"urls",
+			schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil),
+		),
+		"headers": schema.NewOneOfValueVariant(
+			"headers",
+			schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewAnySchema(), nil, nil),
+		),
+		"file": schema.NewStructMappedObjectSchema[oneOfValueTestFile](
+			"file",
+			map[string]*schema.PropertySchema{
+				"path": schema.NewPropertySchema(
+					schema.NewStringSchema(nil, nil, nil),
+					nil,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+		),
+	},
+	"_type",
+	false,
+)
+
+func TestOneOfValueVariants(t *testing.T) {
+	testCases := map[string]struct {
+		serialized   map[string]any
+		unserialized any
+	}{
+		"string": {
+			map[string]any{"_type": "url", "value": "https://example.com"},
+			"https://example.com",
+		},
+		"list": {
+			map[string]any{"_type": "urls", "value": []any{"a", "b"}},
+			[]string{"a", "b"},
+		},
+		"map": {
+			map[string]any{"_type": "headers", "value": map[string]any{"accept": "*/*"}},
+			map[string]any{"accept": "*/*"},
+		},
+		"object": {
+			map[string]any{"_type": "file", "path": "/tmp/test"},
+			oneOfValueTestFile{Path: "/tmp/test"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			unserialized := assert.NoErrorR[any](t)(oneOfValueTestSchema.Unserialize(testCase.serialized))
+			assert.Equals(t, unserialized, testCase.unserialized)
+			assert.NoError(t, oneOfValueTestSchema.Validate(unserialized))
+			serialized := assert.NoErrorR[any](t)(oneOfValueTestSchema.Serialize(unserialized))
+			assert.Equals(t, serialized.(map[string]any)["_type"], testCase.serialized["_type"])
+			unserialized = assert.NoErrorR[any](t)(oneOfValueTestSchema.Unserialize(serialized))
+			assert.Equals(t, unserialized, testCase.unserialized)
+		})
+	}
+}
+
+func TestOneOfValueVariantInvalid(t *testing.T) {
+	_, err := oneOfValueTestSchema.Unserialize(map[string]any{"_type": "url", "value": ""})
+	assert.Error(t, err)
+	_, err = oneOfValueTestSchema.Unserialize(map[string]any{"_type": "url"})
+	assert.Error(t, err)
+	assert.Error(t, oneOfValueTestSchema.Validate(""))
+	_, err = oneOfValueTestSchema.Serialize(int64(1))
+	assert.Error(t, err)
+}
+
+func TestOneOfValueVariantSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+			"source": schema.NewPropertySchema(
+				schema.NewOneOfStringSchema[any](
+					map[string]schema.Object{"url": schema.NewRefSchema("url", nil)},
+					"_type",
+					false,
+				),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		schema.NewOneOfValueVariant("url", schema.NewStringSchema(nil, nil, nil)),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	assert.Equals(t, unserializedScope.Objects()["url"].ValuePropertyValue, schema.OneOfValueFieldName)
+
+	data := map[string]any{"source": map[string]any{"_type": "url", "value": "https://example.com"}}
+	result := assert.NoErrorR[any](t)(unserializedScope.Unserialize(data))
+	assert.Equals(t, result.(map[string]any)["source"], any("https://example.com"))
+}
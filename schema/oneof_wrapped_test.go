@@ -0,0 +1,91 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type oneOfWrappedTestDisk struct {
+	Size int64 `json:"size"`
+}
+
+func newOneOfWrappedTestSchema() *schema.OneOfSchema[string] {
+	return schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{
+			"disk": schema.NewStructMappedObjectSchema[oneOfWrappedTestDisk](
+				"disk",
+				map[string]*schema.PropertySchema{
+					"size": schema.NewPropertySchema(
+						schema.NewIntSchema(nil, nil, nil),
+						nil,
+						false,
+						nil,
+						nil,
+						nil,
+						schema.PointerTo("10"),
+						nil,
+					),
+				},
+			),
+			"path": schema.NewOneOfValueVariant("path", schema.NewStringSchema(nil, nil, nil)),
+		},
+		"type",
+		false,
+	).WrapVariants("value")
+}
+
+func TestOneOfWrapped(t *testing.T) {
+	s := newOneOfWrappedTestSchema()
+	assert.Equals(t, s.ValueFieldName(), "value")
+
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{
+		"type":  "disk",
+		"value": map[string]any{"size": 5},
+	}))
+	assert.Equals(t, unserialized.(oneOfWrappedTestDisk), oneOfWrappedTestDisk{Size: 5})
+	serialized := assert.NoErrorR[any](t)(s.Serialize(unserialized))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{
+		"type":  "disk",
+		"value": map[string]any{"size": int64(5)},
+	})
+
+	unserialized = assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"type": "path", "value": "/dev/sda"}))
+	assert.Equals(t, unserialized.(string), "/dev/sda")
+	serialized = assert.NoErrorR[any](t)(s.Serialize(unserialized))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"type": "path", "value": "/dev/sda"})
+
+	assert.NoError(t, s.ValidateCompatibility(map[string]any{"type": "disk", "value": map[string]any{"size": 5}}))
+	assert.Equals(t, s.ApplyDefaults(map[string]any{"type": "disk"}).(map[string]any), map[string]any{
+		"type":  "disk",
+		"value": map[string]any{"size": int64(10)},
+	})
+}
+
+func TestOneOfWrappedInvalid(t *testing.T) {
+	s := newOneOfWrappedTestSchema()
+	_, err := s.Unserialize(map[string]any{"type": "disk", "size": 5})
+	assert.Error(t, err)
+	_, err = s.Unserialize(map[string]any{"type": "disk", "value": 5})
+	assert.Error(t, err)
+	_, err = s.Unserialize(map[string]any{"value": map[string]any{}})
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		newOneOfWrappedTestSchema().WrapVariants("type")
+	})
+	assert.Panics(t, func() {
+		schema.NewOneOfStringSchema[any](map[string]schema.Object{}, "type", true).WrapVariants("value")
+	})
+}
+
+func TestOneOfWrappedSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+		"storage": schema.NewPropertySchema(newOneOfWrappedTestSchema(), nil, true, nil, nil, nil, nil, nil),
+	}))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	storage := unserialized.(*schema.ScopeSchema).Objects()["root"].Properties()["storage"]
+	assert.Equals(t, storage.Type().(*schema.OneOfSchema[string]).ValueFieldName(), "value")
+}
@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// OrderedMap is a map that keeps its keys in insertion order. Map schemas created with NewOrderedMapSchema or
+// NewTypedOrderedMapSchema unserialize into it, and it marshals to JSON, YAML and CBOR in insertion order, so plugins
+// generating files where the key order matters don't lose it. The zero value is an empty map ready to use.
+type OrderedMap[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+}
+
+// NewOrderedMap creates an empty ordered map.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{}
+}
+
+// Set sets the value of the key. New keys are added at the end, existing keys keep their position.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.values == nil {
+		m.values = map[K]V{}
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of the key. The second return value is false if the key is not set.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Delete removes the key from the map.
+func (m *OrderedMap[K, V]) Delete(key K) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Keys returns a copy of the keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	return append([]K(nil), m.keys...)
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+// MarshalJSON writes the map as a JSON object in insertion order. The keys are formatted as strings.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(fmt.Sprintf("%v", key))
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the value of %v (%w)", key, err)
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML writes the map as a YAML mapping in insertion order.
+func (m *OrderedMap[K, V]) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range m.keys {
+		keyNode := &yaml.Node{}
+		if err := keyNode.Encode(key); err != nil {
+			return nil, err
+		}
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(m.values[key]); err != nil {
+			return nil, fmt.Errorf("failed to marshal the value of %v (%w)", key, err)
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML reads a YAML mapping, keeping the order of its keys.
+func (m *OrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	*m = OrderedMap[K, V]{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var key K
+		if err := node.Content[i].Decode(&key); err != nil {
+			return err
+		}
+		var value V
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+	return nil
+}
+
+// MarshalCBOR writes the map as a CBOR map in insertion order.
+func (m *OrderedMap[K, V]) MarshalCBOR() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	// Map header with the major type 5 and the number of pairs as the argument.
+	length := uint64(len(m.keys))
+	switch {
+	case length < 24:
+		buf.WriteByte(0xa0 | byte(length))
+	case length <= 0xff:
+		buf.WriteByte(0xb8)
+		buf.WriteByte(byte(length))
+	case length <= 0xffff:
+		buf.WriteByte(0xb9)
+		_ = binary.Write(buf, binary.BigEndian, uint16(length))
+	case length <= 0xffffffff:
+		buf.WriteByte(0xba)
+		_ = binary.Write(buf, binary.BigEndian, uint32(length))
+	default:
+		buf.WriteByte(0xbb)
+		_ = binary.Write(buf, binary.BigEndian, length)
+	}
+	for _, key := range m.keys {
+		encodedKey, err := cbor.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := cbor.Marshal(m.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the value of %v (%w)", key, err)
+		}
+		buf.Write(encodedKey)
+		buf.Write(encodedValue)
+	}
+	return buf.Bytes(), nil
+}
+
+// orderedMap is satisfied by every OrderedMap regardless of its type parameters, so map schemas can fill and read
+// them through reflection.
+type orderedMap interface {
+	Len() int
+	entries() []mapEntry
+	set(key any, value any)
+}
+
+func (m *OrderedMap[K, V]) entries() []mapEntry {
+	if m == nil {
+		return nil
+	}
+	result := make([]mapEntry, len(m.keys))
+	for i, key := range m.keys {
+		value := m.values[key]
+		// Taking the element of the pointer keeps nil interface values valid.
+		result[i] = mapEntry{reflect.ValueOf(&key).Elem(), reflect.ValueOf(&value).Elem()}
+	}
+	return result
+}
+
+func (m *OrderedMap[K, V]) set(key any, value any) {
+	var typedValue V
+	if value != nil {
+		typedValue = value.(V)
+	}
+	m.Set(key.(K), typedValue)
+}
+
+// mapEntriesOf returns the entries of a Go map or an OrderedMap. The entries of ordered maps are returned in order.
+// The second return value is false if the data is neither.
+func mapEntriesOf(data any) ([]mapEntry, bool) {
+	if ordered, ok := data.(orderedMap); ok {
+		return ordered.entries(), true
+	}
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Map {
+		return nil, false
+	}
+	entries := make([]mapEntry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		entries = append(entries, mapEntry{iter.Key(), iter.Value()})
+	}
+	return entries, true
+}
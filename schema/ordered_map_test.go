@@ -0,0 +1,88 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := schema.NewOrderedMap[string, int64]()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 3)
+	m.Set("b", 4)
+	assert.Equals(t, m.Keys(), []string{"b", "a", "c"})
+	value, ok := m.Get("b")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, value, int64(4))
+	m.Delete("a")
+	assert.Equals(t, m.Keys(), []string{"b", "c"})
+	assert.Equals(t, m.Len(), 2)
+
+	encodedJSON := assert.NoErrorR[[]byte](t)(json.Marshal(m))
+	assert.Equals(t, string(encodedJSON), `{"b":4,"c":3}`)
+	encodedYAML := assert.NoErrorR[[]byte](t)(yaml.Marshal(m))
+	assert.Equals(t, string(encodedYAML), "b: 4\nc: 3\n")
+
+	decoded := schema.NewOrderedMap[string, int64]()
+	assert.NoError(t, yaml.Unmarshal([]byte("z: 1\ny: 2\nx: 3\n"), decoded))
+	assert.Equals(t, decoded.Keys(), []string{"z", "y", "x"})
+}
+
+func TestOrderedMap_CBOR(t *testing.T) {
+	m := schema.NewOrderedMap[any, any]()
+	for i := 30; i > 0; i-- {
+		m.Set(int64(i), "value")
+	}
+	encoded := assert.NoErrorR[[]byte](t)(cbor.Marshal(m))
+	var decoded map[int64]string
+	assert.NoError(t, cbor.Unmarshal(encoded, &decoded))
+	assert.Equals(t, len(decoded), 30)
+	assert.Equals(t, decoded[30], "value")
+}
+
+func TestOrderedMapSchema(t *testing.T) {
+	mapType := schema.NewTypedOrderedMapSchema[string, int64](
+		schema.NewStringSchema(nil, nil, nil),
+		schema.NewIntSchema(nil, schema.IntPointer(10), nil),
+		nil,
+		schema.IntPointer(3),
+	)
+	input := schema.NewOrderedMap[any, any]()
+	input.Set("second", 2)
+	input.Set("first", 1)
+	unserialized := assert.NoErrorR[*schema.OrderedMap[string, int64]](t)(mapType.UnserializeType(input))
+	assert.Equals(t, unserialized.Keys(), []string{"second", "first"})
+	assert.NoError(t, mapType.ValidateType(unserialized))
+	serialized := assert.NoErrorR[any](t)(mapType.SerializeType(unserialized))
+	assert.Equals(t, serialized.(*schema.OrderedMap[any, any]).Keys(), []any{"second", "first"})
+
+	// The keys of Go maps are sorted.
+	unserialized = assert.NoErrorR[*schema.OrderedMap[string, int64]](t)(mapType.UnserializeType(
+		map[string]any{"c": 1, "a": 2, "b": 3},
+	))
+	assert.Equals(t, unserialized.Keys(), []string{"a", "b", "c"})
+
+	input.Set("third", 11)
+	assert.ErrorR(t)(mapType.UnserializeType(input))
+	unserialized.Set("d", 4)
+	assert.Error(t, mapType.ValidateType(unserialized))
+	_, err := schema.UnserializeAll(mapType, input)
+	assert.Error(t, err)
+}
+
+func TestOrderedMapSchema_Untyped(t *testing.T) {
+	mapType := schema.NewOrderedMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewAnySchema(), nil, nil)
+	input := schema.NewOrderedMap[any, any]()
+	input.Set("b", "x")
+	input.Set("a", int64(1))
+	unserialized := assert.NoErrorR[any](t)(schema.UnserializeAll(mapType, input))
+	assert.Equals(t, unserialized.(*schema.OrderedMap[any, any]).Keys(), []any{"b", "a"})
+	redacted := schema.Redact(mapType, unserialized)
+	assert.Equals(t, redacted.(*schema.OrderedMap[any, any]).Keys(), []any{"b", "a"})
+}
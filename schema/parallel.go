@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelMinItems is the number of items below which lists and maps configured with Parallel are still unserialized
+// sequentially, since starting the workers costs more than it saves.
+const ParallelMinItems = 256
+
+// parallelChunkSize is the number of items a worker takes at once, so workers don't contend on every item.
+const parallelChunkSize = 64
+
+// Parallel is a builder-pattern way of unserializing the items of large lists on a pool of workers. Zero or fewer
+// workers means one per CPU. The items keep their order, and if several items are invalid, a *ConstraintErrors with
+// an error for each of them is returned, in the order of the items. Lists with fewer than ParallelMinItems items are
+// unserialized sequentially. The setting is not part of the serialized schema.
+func (l *ListSchema) Parallel(workers int) *ListSchema {
+	l.parallelWorkers = parallelWorkerCount(workers)
+	return l
+}
+
+// Parallel is a builder-pattern way of unserializing the items of large lists on a pool of workers, see
+// ListSchema.Parallel.
+func (t *TypedListSchema[UnserializedType, ItemType]) Parallel(
+	workers int,
+) *TypedListSchema[UnserializedType, ItemType] {
+	t.parallelWorkers = parallelWorkerCount(workers)
+	return t
+}
+
+// Parallel is a builder-pattern way of unserializing the entries of large maps on a pool of workers, see
+// ListSchema.Parallel. Ordered maps keep the order of their entries.
+func (m *MapSchema[K, V]) Parallel(workers int) *MapSchema[K, V] {
+	m.parallelWorkers = parallelWorkerCount(workers)
+	return m
+}
+
+// Parallel is a builder-pattern way of unserializing the entries of large maps on a pool of workers, see
+// ListSchema.Parallel.
+func (m *TypedMapSchema[KeyType, ValueType]) Parallel(workers int) *TypedMapSchema[KeyType, ValueType] {
+	m.MapSchema.Parallel(workers)
+	return m
+}
+
+func parallelWorkerCount(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// unserializeParallel calls unserialize for each index below count on the given number of workers and returns the
+// results by index. The function is expected to label its errors with itemError. If there are several errors, they are
+// returned as a *ConstraintErrors in the order of the indexes.
+func unserializeParallel(count int, workers int, unserialize func(i int) (any, error)) ([]any, error) {
+	results := make([]any, count)
+	errs := make([]error, count)
+	var next atomic.Int64
+	wg := &sync.WaitGroup{}
+	for w := 0; w < min(workers, (count+parallelChunkSize-1)/parallelChunkSize); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				start := int(next.Add(parallelChunkSize)) - parallelChunkSize
+				if start >= count {
+					return
+				}
+				for i := start; i < min(start+parallelChunkSize, count); i++ {
+					results[i], errs[i] = unserialize(i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results, collectItemErrors(errs)
+}
+
+// itemError adds the path segment of an item to the error. Errors that are not constraint errors are wrapped into one,
+// so the path of the item is not lost when they are collected.
+func itemError(err error, pathSegment string) error {
+	var c *ConstraintError
+	if _, ok := err.(*ConstraintErrors); !ok && !errors.As(err, &c) {
+		err = &ConstraintError{
+			Message: err.Error(),
+			Cause:   err,
+		}
+	}
+	return ConstraintErrorAddPathSegment(err, pathSegment)
+}
+
+// collectItemErrors returns the single error, or a *ConstraintErrors holding all errors in order.
+func collectItemErrors(errs []error) error {
+	var first error
+	var all []*ConstraintError
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		if multiple, ok := err.(*ConstraintErrors); ok {
+			all = append(all, multiple.Errors...)
+		} else {
+			all = append(all, asConstraintError(err))
+		}
+	}
+	if len(all) <= 1 {
+		return first
+	}
+	return &ConstraintErrors{Errors: all}
+}
@@ -0,0 +1,98 @@
+package schema_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func newParallelTestList(count int) []any {
+	data := make([]any, count)
+	for i := range data {
+		data[i] = fmt.Sprintf("%d", i)
+	}
+	return data
+}
+
+func TestListParallel(t *testing.T) {
+	s := schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil).Parallel(4)
+	data := newParallelTestList(1000)
+	result := assert.NoErrorR[any](t)(s.Unserialize(data))
+	items := result.([]int64)
+	assert.Equals(t, len(items), 1000)
+	for i, item := range items {
+		assert.Equals(t, item, int64(i))
+	}
+}
+
+func TestListParallelErrors(t *testing.T) {
+	s := schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil).Parallel(4)
+	data := newParallelTestList(1000)
+	data[700] = "invalid"
+	data[3] = "invalid"
+
+	_, err := s.Unserialize(data)
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 2)
+	assert.Equals(t, errs.Errors[0].Path, []string{"[3]"})
+	assert.Equals(t, errs.Errors[1].Path, []string{"[700]"})
+
+	// Nested lists keep the full path of each error.
+	object := schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"items": schema.NewPropertySchema(s, nil, false, nil, nil, nil, nil, nil),
+	})
+	_, err = object.Unserialize(map[string]any{"items": data})
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, errs.Errors[0].Path, []string{"items", "[3]"})
+	assert.Equals(t, errs.Errors[1].Path, []string{"items", "[700]"})
+
+	// A single error is returned as is.
+	data[700] = "700"
+	_, err = s.Unserialize(data)
+	var single *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &single), true)
+	assert.Equals(t, single.Path, []string{"[3]"})
+}
+
+func TestMapParallel(t *testing.T) {
+	s := schema.NewMapSchema(schema.NewIntSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil).Parallel(0)
+	data := map[any]any{}
+	for i := 0; i < 1000; i++ {
+		data[fmt.Sprintf("%d", i)] = fmt.Sprintf("%d", i*2)
+	}
+	result := assert.NoErrorR[any](t)(s.Unserialize(data))
+	entries := result.(map[int64]int64)
+	assert.Equals(t, len(entries), 1000)
+	assert.Equals(t, entries[500], int64(1000))
+
+	data["500"] = "invalid"
+	_, err := s.Unserialize(data)
+	var single *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &single), true)
+	assert.Equals(t, single.Path, []string{"[500]"})
+}
+
+func BenchmarkListParallel(b *testing.B) {
+	item := schema.NewStructMappedObjectSchema[compileTestStruct]("test", newCompileTestSchema().PropertiesValue)
+	data := make([]any, 10000)
+	for i := range data {
+		data[i] = newCompileTestData()
+	}
+	for _, workers := range []int{0, 1, 4} {
+		s := schema.NewListSchema(item, nil, nil)
+		if workers > 0 {
+			s = s.Parallel(workers)
+		}
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Unserialize(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TypeAtPath returns the type at the path within the root type. The path is either a JSON pointer (RFC 6901), such as
+// /servers/0/host, or a dotted path, such as servers[0].host or servers.0.host. Property IDs containing dots or
+// brackets can only be addressed with a JSON pointer. An empty path returns the root type.
+//
+// Scopes and refs are resolved to the objects they point to. Since there is no data to read the discriminator from,
+// the variant of a one-of type is selected by a segment holding its discriminator value, such as /payload/text/body.
+// List items are addressed with any index, map values with any valid key.
+func TypeAtPath(root Type, path string) (Type, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := root
+	for i, segment := range segments {
+		current, err = typeAtSegment(current, segment)
+		if err != nil {
+			return nil, resolvePathError(path, segments[:i], err)
+		}
+	}
+	return current, nil
+}
+
+// ValueAtPath returns the value at the path within a value of the root type. The path has the same format as for
+// TypeAtPath, except that the variant of a one-of type is read from the value and not addressed by a segment. The value
+// may be serialized or unserialized. The fields of structs are read directly, while values in other forms, such as
+// tuples mapped to structs, are serialized first, so the result may be in serialized form. Unset optional properties
+// have a nil value.
+func ValueAtPath(root Type, value any, path string) (any, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	currentType := root
+	currentValue := value
+	for i, segment := range segments {
+		currentType, currentValue, err = resolveVariant(currentType, currentValue)
+		if err == nil {
+			currentType, currentValue, err = valueAtSegment(currentType, currentValue, segment)
+		}
+		if err != nil {
+			return nil, resolvePathError(path, segments[:i], err)
+		}
+	}
+	return currentValue, nil
+}
+
+// parsePath splits a JSON pointer or dotted path into its segments.
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(path, "/") {
+		segments := strings.Split(path[1:], "/")
+		for i, segment := range segments {
+			segments[i] = jsonPointerUnescaper.Replace(segment)
+		}
+		return segments, nil
+	}
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		name, indexes, hasIndexes := strings.Cut(part, "[")
+		if name != "" || !hasIndexes {
+			segments = append(segments, name)
+		}
+		if !hasIndexes {
+			continue
+		}
+		if !strings.HasSuffix(indexes, "]") {
+			return nil, BadArgumentError{Message: fmt.Sprintf("unclosed bracket in path %s", path)}
+		}
+		for _, index := range strings.Split(indexes[:len(indexes)-1], "][") {
+			segments = append(segments, index)
+		}
+	}
+	return segments, nil
+}
+
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func resolvePathError(path string, resolved []string, err error) error {
+	return fmt.Errorf("cannot resolve %s at /%s (%w)", path, strings.Join(resolved, "/"), err)
+}
+
+// resolveType returns the object a scope or ref points to, or the type itself.
+func resolveType(t Type) (Type, error) {
+	switch typed := t.(type) {
+	case Scope:
+		return typed.RootObject(), nil
+	case *RefSchema:
+		if !typed.ObjectReady() {
+			return nil, fmt.Errorf("reference to %s is not linked to its object", typed.ID())
+		}
+		return resolveType(typed.GetObject())
+	default:
+		return t, nil
+	}
+}
+
+func typeAtSegment(t Type, segment string) (Type, error) {
+	t, err := resolveType(t)
+	if err != nil {
+		return nil, err
+	}
+	switch typed := t.(type) {
+	case *ObjectSchema:
+		return propertyType(typed, segment)
+	case untypedListSchema:
+		if _, err := parseIndex(segment, -1); err != nil {
+			return nil, err
+		}
+		return typed.itemType(), nil
+	case untypedMapSchema:
+		if _, err := typed.keyType().Unserialize(mapKeyFromSegment(typed.keyType(), segment)); err != nil {
+			return nil, fmt.Errorf("invalid map key %s (%w)", segment, err)
+		}
+		return typed.valueType(), nil
+	case *TupleSchema:
+		index, err := parseIndex(segment, len(typed.ItemsValue))
+		if err != nil {
+			return nil, err
+		}
+		return typed.ItemsValue[index], nil
+	case *OneOfSchema[string]:
+		return variantType(typed, segment)
+	case *OneOfSchema[int64]:
+		return variantType(typed, segment)
+	default:
+		return nil, fmt.Errorf("%s types contain no other types", t.TypeID())
+	}
+}
+
+func propertyType(o *ObjectSchema, segment string) (Type, error) {
+	propertyID, ok := o.resolveKey(segment)
+	if !ok {
+		return nil, fmt.Errorf("object %s has no property %s", o.ID(), segment)
+	}
+	return o.PropertiesValue[propertyID].Type(), nil
+}
+
+func variantType[KeyType int64 | string](o *OneOfSchema[KeyType], segment string) (Type, error) {
+	for key, variant := range o.Types() {
+		if fmt.Sprintf("%v", key) != segment {
+			continue
+		}
+		if wrapper, isValueVariant := valueVariantOf(variant); isValueVariant {
+			return wrapper.PropertiesValue[OneOfValueFieldName].Type(), nil
+		}
+		return variant, nil
+	}
+	return nil, fmt.Errorf("one-of type has no variant %s", segment)
+}
+
+// resolveVariant resolves scopes and refs, and selects the variant of one-of types based on the value.
+func resolveVariant(t Type, value any) (Type, any, error) {
+	t, err := resolveType(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	o, ok := t.(variantSelector)
+	if !ok || value == nil {
+		return t, value, nil
+	}
+	var selectedType Object
+	if mapValue, isMap := value.(map[string]any); isMap {
+		var variantData map[string]any
+		_, selectedType, variantData, err = o.selectVariant(mapValue)
+		value = variantData
+		if _, isValueVariant := valueVariantOf(selectedType); err == nil && isValueVariant {
+			value = variantData[OneOfValueFieldName]
+		}
+	} else {
+		_, selectedType, value, err = o.selectUnserializedVariant(value)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if wrapper, isValueVariant := valueVariantOf(selectedType); isValueVariant {
+		return wrapper.PropertiesValue[OneOfValueFieldName].Type(), value, nil
+	}
+	return resolveVariant(selectedType, value)
+}
+
+func valueAtSegment(t Type, value any, segment string) (Type, any, error) {
+	if value == nil {
+		return nil, nil, errors.New("no value")
+	}
+	switch typed := t.(type) {
+	case *ObjectSchema:
+		return propertyValue(typed, value, segment)
+	case untypedListSchema:
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, nil, fmt.Errorf("expected a list, got %T", value)
+		}
+		index, err := parseIndex(segment, v.Len())
+		if err != nil {
+			return nil, nil, err
+		}
+		return typed.itemType(), v.Index(index).Interface(), nil
+	case untypedMapSchema:
+		entries, ok := mapEntriesOf(value)
+		if !ok {
+			return nil, nil, fmt.Errorf("expected a map, got %T", value)
+		}
+		for _, entry := range entries {
+			if fmt.Sprintf("%v", entry.key.Interface()) == segment {
+				return typed.valueType(), entry.value.Interface(), nil
+			}
+		}
+		return nil, nil, fmt.Errorf("map has no key %s", segment)
+	case *TupleSchema:
+		return tupleValue(typed, value, segment)
+	default:
+		return nil, nil, fmt.Errorf("%s values contain no other values", t.TypeID())
+	}
+}
+
+func propertyValue(o *ObjectSchema, value any, segment string) (Type, any, error) {
+	propertyID, ok := o.resolveKey(segment)
+	if !ok {
+		return nil, nil, fmt.Errorf("object %s has no property %s", o.ID(), segment)
+	}
+	property := o.PropertiesValue[propertyID]
+	v := reflect.ValueOf(value)
+	if o.fieldCache != nil && reflect.Indirect(v).Kind() == reflect.Struct {
+		if field := o.getFieldReflection(propertyID, v, property); field != nil {
+			return property.Type(), field.Interface(), nil
+		}
+		return property.Type(), nil, nil
+	}
+	if v.Kind() != reflect.Map {
+		serialized, err := o.Serialize(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		v = reflect.ValueOf(serialized)
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, nil, fmt.Errorf("expected a map for object %s, got %T", o.ID(), value)
+	}
+	field := v.MapIndex(reflect.ValueOf(propertyID).Convert(v.Type().Key()))
+	if !field.IsValid() {
+		return property.Type(), nil, nil
+	}
+	return property.Type(), field.Interface(), nil
+}
+
+func tupleValue(t *TupleSchema, value any, segment string) (Type, any, error) {
+	index, err := parseIndex(segment, len(t.ItemsValue))
+	if err != nil {
+		return nil, nil, err
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		serialized, err := t.Serialize(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		v = reflect.ValueOf(serialized)
+	}
+	if v.Len() <= index {
+		return nil, nil, fmt.Errorf("tuple has %d items, not %d", v.Len(), index+1)
+	}
+	return t.ItemsValue[index], v.Index(index).Interface(), nil
+}
+
+// parseIndex parses a list or tuple index. If length is not negative, the index must be below it.
+func parseIndex(segment string, length int) (int, error) {
+	index, err := strconv.Atoi(segment)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid index %s", segment)
+	}
+	if length >= 0 && index >= length {
+		return 0, fmt.Errorf("index %d out of range for %d items", index, length)
+	}
+	return index, nil
+}
+
+// mapKeyFromSegment converts the segment to the serialized form of an integer key if the key type needs one.
+func mapKeyFromSegment(keyType Type, segment string) any {
+	if keyType.TypeID() != TypeIDInt && keyType.TypeID() != TypeIDIntEnum {
+		return segment
+	}
+	if key, err := strconv.ParseInt(segment, 10, 64); err == nil {
+		return key
+	}
+	return segment
+}
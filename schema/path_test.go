@@ -0,0 +1,115 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type pathTestServer struct {
+	Host string `json:"host"`
+	Port *int64 `json:"port"`
+}
+
+func pathTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewObjectSchema("config", map[string]*schema.PropertySchema{
+			"servers": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewRefSchema("server", nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"limits": schema.NewPropertySchema(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"target": schema.NewPropertySchema(
+				schema.NewOneOfStringSchema[any](map[string]schema.Object{
+					"server": schema.NewRefSchema("server", nil),
+					"name":   schema.NewOneOfValueVariant("name", schema.NewStringSchema(nil, nil, nil)),
+				}, "kind", false),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		schema.NewStructMappedObjectSchema[*pathTestServer]("server", map[string]*schema.PropertySchema{
+			"host": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"port": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+		}),
+	)
+}
+
+func TestTypeAtPath(t *testing.T) {
+	scope := pathTestScope()
+	for path, expected := range map[string]schema.TypeID{
+		"":                   schema.TypeIDScope,
+		"servers":            schema.TypeIDList,
+		"servers[0].host":    schema.TypeIDString,
+		"servers.0.port":     schema.TypeIDInt,
+		"/servers/0/host":    schema.TypeIDString,
+		"limits.cpu":         schema.TypeIDInt,
+		"target.server.host": schema.TypeIDString,
+		"target.name":        schema.TypeIDString,
+	} {
+		t.Run(path, func(t *testing.T) {
+			result := assert.NoErrorR[schema.Type](t)(schema.TypeAtPath(scope, path))
+			assert.Equals(t, result.TypeID(), expected)
+		})
+	}
+	for _, path := range []string{"missing", "servers.first", "servers[0", "target.other", "servers.0.host.x"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := schema.TypeAtPath(scope, path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValueAtPath(t *testing.T) {
+	scope := pathTestScope()
+	serialized := map[string]any{
+		"servers": []any{
+			map[string]any{"host": "a.example.com", "port": int64(80)},
+			map[string]any{"host": "b.example.com"},
+		},
+		"limits": map[string]any{"cpu": int64(2)},
+		"target": map[string]any{"kind": "server", "host": "c.example.com"},
+	}
+	unserialized := assert.NoErrorR[any](t)(scope.Unserialize(serialized))
+
+	for _, value := range []any{serialized, unserialized} {
+		assert.Equals(t, assert.NoErrorR[any](t)(schema.ValueAtPath(scope, value, "servers[0].host")), any("a.example.com"))
+		assert.Equals(t, assert.NoErrorR[any](t)(schema.ValueAtPath(scope, value, "/servers/0/port")), any(int64(80)))
+		assert.Nil(t, assert.NoErrorR[any](t)(schema.ValueAtPath(scope, value, "servers.1.port")))
+		assert.Equals(t, assert.NoErrorR[any](t)(schema.ValueAtPath(scope, value, "limits.cpu")), any(int64(2)))
+		assert.Equals(t, assert.NoErrorR[any](t)(schema.ValueAtPath(scope, value, "target.host")), any("c.example.com"))
+		_, err := schema.ValueAtPath(scope, value, "servers.2.host")
+		assert.Error(t, err)
+		_, err = schema.ValueAtPath(scope, value, "limits.memory")
+		assert.Error(t, err)
+	}
+
+	nameTarget := map[string]any{"target": map[string]any{"kind": "name", "value": "primary"}}
+	assert.Equals(t, assert.NoErrorR[any](t)(schema.ValueAtPath(scope, nameTarget, "target")), any(map[string]any{
+		"kind":  "name",
+		"value": "primary",
+	}))
+	_, err := schema.ValueAtPath(scope, nameTarget, "target.value")
+	assert.Error(t, err)
+}
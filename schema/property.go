@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -33,20 +35,27 @@ func NewPropertySchema(
 	examples []string,
 ) *PropertySchema {
 	return &PropertySchema{
-		t,
-		displayValue,
-		required,
-		requiredIf,
-		requiredIfNot,
-		conflicts,
-		defaultValue,
-		examples,
-		false,
-		false,
-		nil,
+		TypeValue:          t,
+		DisplayValue:       displayValue,
+		RequiredValue:      required,
+		RequiredIfValue:    requiredIf,
+		RequiredIfNotValue: requiredIfNot,
+		ConflictsValue:     conflicts,
+		DefaultValue:       defaultValue,
+		ExamplesValue:      examples,
 	}
 }
 
+// PropertyCondition makes a property required depending on the value of another property in the same object.
+type PropertyCondition struct {
+	// PropertyID is the ID of the property whose value is checked.
+	PropertyID string `json:"property_id"`
+	// Values holds the JSON-encoded serialized values that trigger the condition.
+	Values []string `json:"values"`
+	// Negate triggers the condition when the property is not set to any of the values, including when it is unset.
+	Negate bool `json:"negate"`
+}
+
 type PropertySchema struct {
 	TypeValue          Type     `json:"type"`
 	DisplayValue       Display  `json:"display"`
@@ -63,6 +72,22 @@ type PropertySchema struct {
 	Disabled bool `json:"disabled"`
 	// DisabledReason explains why the property is disabled. Default nil
 	DisabledReason *string `json:"disabled_reason"`
+	// SensitiveValue marks the value as a secret. Use Redact to remove sensitive values before logging data.
+	SensitiveValue bool `json:"sensitive"`
+	// DeprecatedValue holds the deprecation information if the property should no longer be used.
+	DeprecatedValue *Deprecated `json:"deprecated"`
+	// RequiredIfConditionsValue holds the value-based conditions that make this property required.
+	RequiredIfConditionsValue []PropertyCondition `json:"required_if_conditions"`
+	// AliasesValue holds alternate names the property is accepted under when unserializing, for example after a rename.
+	AliasesValue []string `json:"aliases"`
+	// TransformsValue holds the transformations applied to the serialized value before it is unserialized.
+	TransformsValue []Transform `json:"transforms"`
+	// TransformOnSerialize also applies the transformations to the value before it is serialized.
+	TransformOnSerialize bool `json:"transform_on_serialize"`
+	// EngineRequirementsValue holds what the engine has to support to set the property, if anything.
+	EngineRequirementsValue *EngineRequirements `json:"engine_requirements"`
+
+	locked bool
 }
 
 // TreatEmptyAsDefaultValue triggers the property to treat an empty value (e.g. "", or 0) as the default value for
@@ -71,17 +96,57 @@ type PropertySchema struct {
 // This is useful in case of third party structs where the property may not have a pointer despite being optional.
 // However, to avoid ambiguity and better performance, this option should be used only when needed.
 func (p *PropertySchema) TreatEmptyAsDefaultValue() *PropertySchema {
+	p.checkNotLocked("treat empty values as the default")
 	p.emptyIsDefault = true
 	return p
 }
 
 // Disable is a builder-pattern way of disabling the property.
 func (p *PropertySchema) Disable(reason string) *PropertySchema {
+	p.checkNotLocked("disable the property")
 	p.Disabled = true
 	p.DisabledReason = &reason
 	return p
 }
 
+// MarkSensitive is a builder-pattern way of marking the property as holding a secret. Sensitive values are replaced
+// by Redact, and are left out of error messages.
+func (p *PropertySchema) MarkSensitive() *PropertySchema {
+	p.checkNotLocked("mark the property sensitive")
+	p.SensitiveValue = true
+	return p
+}
+
+// Sensitive indicates that the value holds a secret, such as a password, and must not be logged or shown.
+func (p *PropertySchema) Sensitive() bool {
+	return p.SensitiveValue
+}
+
+// Deprecate is a builder-pattern way of marking the property as deprecated.
+func (p *PropertySchema) Deprecate(deprecated Deprecated) *PropertySchema {
+	p.checkNotLocked("deprecate the property")
+	p.DeprecatedValue = &deprecated
+	return p
+}
+
+// Deprecated returns the deprecation information if the property should no longer be used, nil otherwise.
+func (p *PropertySchema) Deprecated() *Deprecated {
+	return p.DeprecatedValue
+}
+
+// RequireEngine is a builder-pattern way of restricting the property to engines that meet the requirements. Engines
+// that don't are not offered the property, so it should be optional.
+func (p *PropertySchema) RequireEngine(requirements EngineRequirements) *PropertySchema {
+	p.checkNotLocked("set engine requirements")
+	p.EngineRequirementsValue = &requirements
+	return p
+}
+
+// EngineRequirements returns what the engine has to support to set the property, nil if anything goes.
+func (p *PropertySchema) EngineRequirements() *EngineRequirements {
+	return p.EngineRequirementsValue
+}
+
 func (p *PropertySchema) Default() *string {
 	return p.DefaultValue
 }
@@ -114,6 +179,45 @@ func (p *PropertySchema) RequiredIfNot() []string {
 	return p.RequiredIfNotValue
 }
 
+// RequiredIfConditions returns the conditions on the values of other properties that make this property required.
+func (p *PropertySchema) RequiredIfConditions() []PropertyCondition {
+	return p.RequiredIfConditionsValue
+}
+
+// RequireIfValue is a builder-pattern way of making the property required if the property with the given ID is set to
+// one of the given serialized values. For example, RequireIfValue("mode", "tls") can make a certificate path required
+// only when TLS is used.
+func (p *PropertySchema) RequireIfValue(propertyID string, values ...any) *PropertySchema {
+	return p.addRequiredIfCondition(propertyID, values, false)
+}
+
+// RequireIfNotValue is a builder-pattern way of making the property required if the property with the given ID is not
+// set to any of the given serialized values, including when it is not set at all.
+func (p *PropertySchema) RequireIfNotValue(propertyID string, values ...any) *PropertySchema {
+	return p.addRequiredIfCondition(propertyID, values, true)
+}
+
+func (p *PropertySchema) addRequiredIfCondition(propertyID string, values []any, negate bool) *PropertySchema {
+	p.checkNotLocked("add a required-if condition")
+	encodedValues := make([]string, len(values))
+	for i, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf("Cannot encode the condition value for '%s'", propertyID),
+				Cause:   err,
+			})
+		}
+		encodedValues[i] = string(encoded)
+	}
+	p.RequiredIfConditionsValue = append(p.RequiredIfConditionsValue, PropertyCondition{
+		PropertyID: propertyID,
+		Values:     encodedValues,
+		Negate:     negate,
+	})
+	return p
+}
+
 func (p *PropertySchema) Conflicts() []string {
 	return p.ConflictsValue
 }
@@ -122,17 +226,64 @@ func (p *PropertySchema) Examples() []string {
 	return p.ExamplesValue
 }
 
+// Alias is a builder-pattern way of accepting the property under alternate names when unserializing. This allows
+// renaming a property without breaking existing workflow files. The property is always serialized under its ID.
+func (p *PropertySchema) Alias(aliases ...string) *PropertySchema {
+	p.checkNotLocked("add aliases")
+	p.AliasesValue = append(p.AliasesValue, aliases...)
+	return p
+}
+
+// Aliases returns the alternate names the property is accepted under when unserializing.
+func (p *PropertySchema) Aliases() []string {
+	return p.AliasesValue
+}
+
+// Transform is a builder-pattern way of adding transformations, such as trimming whitespace, that are applied in
+// order to the value before it is unserialized. It panics if a transformation is invalid.
+func (p *PropertySchema) Transform(transforms ...Transform) *PropertySchema {
+	p.checkNotLocked("add transforms")
+	for _, transform := range transforms {
+		if err := transform.validate(); err != nil {
+			panic(BadArgumentError{
+				Message: "invalid property transformation",
+				Cause:   err,
+			})
+		}
+	}
+	p.TransformsValue = append(p.TransformsValue, transforms...)
+	return p
+}
+
+// TransformSerialized is a builder-pattern way of also applying the transformations to the value before it is
+// serialized.
+func (p *PropertySchema) TransformSerialized() *PropertySchema {
+	p.checkNotLocked("transform serialized values")
+	p.TransformOnSerialize = true
+	return p
+}
+
+// Transforms returns the transformations applied to the value before it is validated.
+func (p *PropertySchema) Transforms() []Transform {
+	return p.TransformsValue
+}
+
 func (p *PropertySchema) ApplyNamespace(objects map[string]*ObjectSchema, namespace string) {
-	p.TypeValue.ApplyNamespace(objects, namespace)
+	applyNamespace(p.TypeValue, objects, namespace)
 }
 
 func (p *PropertySchema) ValidateReferences() error {
 	return p.TypeValue.ValidateReferences()
 }
 
+func (p *PropertySchema) ApplyDefaults(serialized any) any {
+	return ApplyDefaults(p.TypeValue, serialized)
+}
+
 func (p *PropertySchema) Unserialize(data any) (any, error) {
 	if !p.Disabled {
-		return p.TypeValue.Unserialize(data)
+		result, err := p.TypeValue.Unserialize(applyTransforms(p.TransformsValue, data))
+		return result, p.redactError(err)
 	} else {
 		// Note, this is last, so that actual validation errors are returned before the disabled err
 		if p.DisabledReason == nil {
@@ -153,6 +304,11 @@ func (p *PropertySchema) ValidateCompatibility(typeOrData any) error {
 		return p.TypeValue.ValidateCompatibility(schemaType.TypeValue)
 	}
 	err := p.TypeValue.ValidateCompatibility(typeOrData)
+	if _, isType := typeOrData.(Type); err != nil && !isType {
+		// Errors of the data already point to the problematic field, so they are kept as they are for the callers to
+		// add the path of the property.
+		return err
+	}
 	if err != nil {
 		if p.DisplayValue != nil && p.Display().Name() != nil {
 			return &ConstraintError{
@@ -184,8 +340,36 @@ func (p *PropertySchema) ValidateCompatibility(typeOrData any) error {
 }
 
 func (p *PropertySchema) Validate(data any) error {
-	return p.TypeValue.Validate(data)
+	return p.redactError(p.TypeValue.Validate(data))
 }
 func (p *PropertySchema) Serialize(data any) (any, error) {
-	return p.TypeValue.Serialize(data)
+	if p.TransformOnSerialize {
+		data = applyTransforms(p.TransformsValue, data)
+	}
+	result, err := p.TypeValue.Serialize(data)
+	return result, p.redactError(err)
+}
+
+// redactError removes the parts of the error that may contain the value if the property is sensitive. The path and
+// the violated constraint are kept so the user can still find the problem.
+func (p *PropertySchema) redactError(err error) error {
+	if err == nil || !p.SensitiveValue {
+		return err
+	}
+	var c *ConstraintError
+	if !errors.As(err, &c) {
+		return &ConstraintError{
+			Message: "Invalid sensitive value",
+		}
+	}
+	message := "Invalid sensitive value"
+	if c.Constraint != "" {
+		message = fmt.Sprintf("Sensitive value violates the %s constraint", c.Constraint)
+	}
+	return &ConstraintError{
+		Message:    message,
+		Path:       c.Path,
+		Constraint: c.Constraint,
+		Expected:   c.Expected,
+	}
 }
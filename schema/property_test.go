@@ -125,3 +125,176 @@ func TestPropertyEmptyAsDefault(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+type requiredIfValueTestStruct struct {
+	Mode     string  `json:"mode"`
+	CertPath *string `json:"cert_path"`
+	Port     *int64  `json:"port"`
+}
+
+func newRequiredIfValueTestProperties() map[string]*schema.PropertySchema {
+	return map[string]*schema.PropertySchema{
+		"mode": schema.NewPropertySchema(
+			schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+				"plain": {NameValue: schema.PointerTo("Plain")},
+				"tls":   {NameValue: schema.PointerTo("TLS")},
+			}),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"cert_path": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		).RequireIfValue("mode", "tls"),
+		"port": schema.NewPropertySchema(
+			schema.NewIntSchema(nil, nil, nil),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		).RequireIfNotValue("mode", "plain"),
+	}
+}
+
+func TestPropertyRequireIfValue(t *testing.T) {
+	s := schema.NewObjectSchema("test", newRequiredIfValueTestProperties())
+
+	_, err := s.Unserialize(map[string]any{"mode": "plain"})
+	assert.NoError(t, err)
+	_, err = s.Unserialize(map[string]any{"mode": "tls", "port": 443})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'mode' is set to \"tls\"")
+	_, err = s.Unserialize(map[string]any{"mode": "tls", "cert_path": "/cert.pem"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'mode' is not set to \"plain\"")
+	_, err = s.Unserialize(map[string]any{"mode": "tls", "cert_path": "/cert.pem", "port": 443})
+	assert.NoError(t, err)
+
+	assert.Error(t, s.Validate(map[string]any{"mode": "tls", "port": int64(443)}))
+	_, err = s.Serialize(map[string]any{"mode": "tls", "port": int64(443)})
+	assert.Error(t, err)
+}
+
+func TestPropertyRequireIfValueStruct(t *testing.T) {
+	s := schema.NewTypedObject[requiredIfValueTestStruct]("test", newRequiredIfValueTestProperties())
+
+	assert.NoError(t, s.Validate(requiredIfValueTestStruct{Mode: "plain"}))
+	assert.Error(t, s.Validate(requiredIfValueTestStruct{Mode: "tls", Port: schema.PointerTo(int64(443))}))
+	_, err := s.Serialize(requiredIfValueTestStruct{Mode: "tls", Port: schema.PointerTo(int64(443))})
+	assert.Error(t, err)
+	_, err = s.UnserializeType(map[string]any{"mode": "tls", "cert_path": "/cert.pem", "port": 443})
+	assert.NoError(t, err)
+}
+
+func TestPropertyRequireIfValueSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("test", newRequiredIfValueTestProperties()))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	property := unserialized.(*schema.ScopeSchema).Objects()["test"].Properties()["cert_path"]
+	assert.Equals(t, property.RequiredIfConditions(), []schema.PropertyCondition{
+		{PropertyID: "mode", Values: []string{"\"tls\""}},
+	})
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	_, err := unserializedScope.Unserialize(map[string]any{"mode": "tls", "port": 443})
+	assert.Error(t, err)
+}
+
+func TestPropertyRequireIfValueNonexistentProperty(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"port": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			).RequireIfValue("mode", "tls"),
+		})
+	})
+}
+
+type aliasTestStruct struct {
+	Hostname string `json:"hostname"`
+}
+
+func newAliasTestSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[aliasTestStruct]("test", map[string]*schema.PropertySchema{
+		"hostname": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		).Alias("host", "server"),
+	})
+}
+
+func TestPropertyAliases(t *testing.T) {
+	s := newAliasTestSchema()
+	for _, key := range []string{"hostname", "host", "server"} {
+		unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{key: "localhost"}))
+		assert.Equals(t, unserialized.(aliasTestStruct).Hostname, "localhost")
+	}
+	serialized := assert.NoErrorR[any](t)(s.Serialize(aliasTestStruct{Hostname: "localhost"}))
+	assert.Equals(t, serialized.(map[string]any), map[string]any{"hostname": "localhost"})
+
+	_, err := s.Unserialize(map[string]any{"hostname": "localhost", "host": "example.com"})
+	assert.Error(t, err)
+
+	unserialized := assert.NoErrorR[any](t)(
+		newAliasTestSchema().MatchKeys(schema.KeyMatchingCaseInsensitive).Unserialize(map[string]any{"Host": "localhost"}),
+	)
+	assert.Equals(t, unserialized.(aliasTestStruct).Hostname, "localhost")
+}
+
+func TestPropertyAliasesSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(newAliasTestSchema())
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	result := assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"server": "localhost"}))
+	assert.Equals(t, result.(map[string]any)["hostname"].(string), "localhost")
+}
+
+func TestPropertyAliasesConflict(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"a": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+			"b": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			).Alias("a"),
+		})
+	})
+	assert.Panics(t, func() {
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"a": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			).Alias("c"),
+			"b": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			).Alias("c"),
+		})
+	})
+}
@@ -0,0 +1,182 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ProvenanceKind identifies how a value was changed while it was unserialized.
+type ProvenanceKind string
+
+const (
+	// ProvenanceDefault indicates that the property was not set, so its default value was used.
+	ProvenanceDefault ProvenanceKind = "default"
+	// ProvenanceTransform indicates that a transformation of the property changed the value. The detail holds the
+	// ID of the transformation.
+	ProvenanceTransform ProvenanceKind = "transform"
+	// ProvenanceUnits indicates that a string with units, such as "5m", was converted to a number in the base unit.
+	// The detail holds the name of the base unit.
+	ProvenanceUnits ProvenanceKind = "units"
+)
+
+// ProvenanceStep is a single change of a value.
+type ProvenanceStep struct {
+	Kind   ProvenanceKind `json:"kind"`
+	Detail string         `json:"detail,omitempty"`
+	// Value is the value after the change.
+	Value any `json:"value"`
+}
+
+// ProvenanceEntry records how the value at a path came to differ from the serialized input.
+type ProvenanceEntry struct {
+	// Path is the JSON pointer of the value in the serialized data, such as "/items/0/name".
+	Path string `json:"path"`
+	// Original is the value as it was given, or nil if it was not set.
+	Original any `json:"original"`
+	// Steps lists the changes in the order they were applied.
+	Steps []ProvenanceStep `json:"steps"`
+}
+
+// String describes the entry for diagnostics, for example `/name: " Arca " -> transform trim -> "Arca"`.
+func (e ProvenanceEntry) String() string {
+	builder := &strings.Builder{}
+	builder.WriteString(e.Path)
+	builder.WriteString(": ")
+	if e.Original == nil {
+		builder.WriteString("unset")
+	} else {
+		builder.WriteString(describeProvenanceValue(e.Original))
+	}
+	for _, step := range e.Steps {
+		_, _ = fmt.Fprintf(builder, " -> %s", step.Kind)
+		if step.Detail != "" {
+			_, _ = fmt.Fprintf(builder, " %s", step.Detail)
+		}
+		_, _ = fmt.Fprintf(builder, " -> %s", describeProvenanceValue(step.Value))
+	}
+	return builder.String()
+}
+
+func describeProvenanceValue(value any) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Provenance lists the values that were changed while unserializing data, ordered by path. Values that were used as
+// given are not listed.
+type Provenance []ProvenanceEntry
+
+// Get returns the entry for the JSON pointer of a value, if the value was changed.
+func (p Provenance) Get(path string) (ProvenanceEntry, bool) {
+	i := sort.Search(len(p), func(i int) bool { return p[i].Path >= path })
+	if i < len(p) && p[i].Path == path {
+		return p[i], true
+	}
+	return ProvenanceEntry{}, false
+}
+
+// UnserializeWithProvenance unserializes the data like UnserializeAll and records how the SDK changed the values on
+// the way: defaults filled in, transformations applied, and units converted. It helps users debug why a plugin saw a
+// value different from what they typed. The values of sensitive properties are recorded as RedactedPlaceholder.
+func UnserializeWithProvenance(t Type, data any) (any, Provenance, error) {
+	recorder := &provenanceRecorder{entries: map[string]*ProvenanceEntry{}}
+	result, errs := errorCollector{provenance: recorder}.collect(t, data)
+	if len(errs) > 0 {
+		return nil, nil, &ConstraintErrors{Errors: errs}
+	}
+	return result, recorder.provenance(), nil
+}
+
+// provenanceRecorder collects the provenance entries by path while the errorCollector walks the data.
+type provenanceRecorder struct {
+	entries map[string]*ProvenanceEntry
+}
+
+func (r *provenanceRecorder) record(path []string, sensitive bool, original any, step ProvenanceStep) {
+	if sensitive {
+		if original != nil {
+			original = RedactedPlaceholder
+		}
+		step.Value = RedactedPlaceholder
+	}
+	pointer := provenancePointer(path)
+	entry, ok := r.entries[pointer]
+	if !ok {
+		entry = &ProvenanceEntry{Path: pointer, Original: original}
+		r.entries[pointer] = entry
+	}
+	entry.Steps = append(entry.Steps, step)
+}
+
+func (r *provenanceRecorder) provenance() Provenance {
+	result := make(Provenance, 0, len(r.entries))
+	for _, entry := range r.entries {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+func provenancePointer(path []string) string {
+	builder := &strings.Builder{}
+	for _, segment := range path {
+		builder.WriteString("/")
+		builder.WriteString(jsonPointerEscaper.Replace(segment))
+	}
+	return builder.String()
+}
+
+// at returns the collector for the value at the path segment below the current value. The path is only tracked when
+// recording provenance.
+func (c errorCollector) at(segment string) errorCollector {
+	if c.provenance != nil {
+		c.path = appendPath(c.path, segment)
+	}
+	return c
+}
+
+// recordDefaults records the properties fillDefaults set, which were not set before.
+func (c errorCollector) recordDefaults(o *ObjectSchema, setBefore map[string]bool, rawData map[string]any) {
+	for propertyID, value := range rawData {
+		if !setBefore[propertyID] {
+			property := o.PropertiesValue[propertyID]
+			c.at(propertyID).record(property.SensitiveValue, nil, ProvenanceDefault, "", value)
+		}
+	}
+}
+
+// applyTransforms applies the transformations of the property one by one, recording the ones that changed the value.
+func (c errorCollector) applyTransforms(property *PropertySchema, data any) any {
+	if c.provenance == nil {
+		return applyTransforms(property.TransformsValue, data)
+	}
+	original := data
+	for _, transform := range property.TransformsValue {
+		transformed := transform.Apply(data)
+		if !reflect.DeepEqual(transformed, data) {
+			c.record(property.SensitiveValue, original, ProvenanceTransform, string(transform.ID), transformed)
+		}
+		data = transformed
+	}
+	return data
+}
+
+// recordUnits records the conversion of a string with units to a number.
+func (c errorCollector) recordUnits(t Type, data any, result any) {
+	if c.provenance == nil {
+		return
+	}
+	withUnits, ok := t.(interface{ Units() *UnitsDefinition })
+	if _, isString := data.(string); !ok || !isString || withUnits.Units() == nil {
+		return
+	}
+	c.record(false, data, ProvenanceUnits, withUnits.Units().BaseUnit().NameLongPluralValue, result)
+}
+
+func (c errorCollector) record(sensitive bool, original any, kind ProvenanceKind, detail string, value any) {
+	c.provenance.record(c.path, sensitive || c.sensitive, original, ProvenanceStep{kind, detail, value})
+}
@@ -0,0 +1,81 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestUnserializeWithProvenance(t *testing.T) {
+	item := schema.NewObjectSchema("item", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil).
+			Transform(schema.TrimTransform(), schema.LowercaseTransform(), schema.TrimTransform()),
+		"timeout": schema.NewPropertySchema(
+			schema.NewIntSchema(nil, nil, schema.UnitDurationNanoseconds),
+			nil,
+			false,
+			nil,
+			nil,
+			nil,
+			schema.PointerTo(`"1s"`),
+			nil,
+		),
+		"token": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil).
+			Transform(schema.TrimTransform()).
+			MarkSensitive(),
+	})
+	root := schema.NewScopeSchema(schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+		"items": schema.NewPropertySchema(
+			schema.NewListSchema(schema.NewRefSchema("item", nil), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}), item)
+
+	result, provenance, err := schema.UnserializeWithProvenance(root, map[string]any{
+		"items": []any{
+			map[string]any{"name": "plain"},
+			map[string]any{"name": " Arca ", "timeout": "2m", "token": " secret "},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equals(t, result.(map[string]any)["items"].([]map[string]any)[1]["name"], any("arca"))
+	assert.Equals(t, provenance, schema.Provenance{
+		{Path: "/items/0/timeout", Original: nil, Steps: []schema.ProvenanceStep{
+			{Kind: schema.ProvenanceDefault, Value: "1s"},
+			{Kind: schema.ProvenanceUnits, Detail: "nanoseconds", Value: int64(1000000000)},
+		}},
+		{Path: "/items/1/name", Original: " Arca ", Steps: []schema.ProvenanceStep{
+			{Kind: schema.ProvenanceTransform, Detail: "trim", Value: "Arca"},
+			{Kind: schema.ProvenanceTransform, Detail: "lowercase", Value: "arca"},
+		}},
+		{Path: "/items/1/timeout", Original: "2m", Steps: []schema.ProvenanceStep{
+			{Kind: schema.ProvenanceUnits, Detail: "nanoseconds", Value: int64(120000000000)},
+		}},
+		{Path: "/items/1/token", Original: schema.RedactedPlaceholder, Steps: []schema.ProvenanceStep{
+			{Kind: schema.ProvenanceTransform, Detail: "trim", Value: schema.RedactedPlaceholder},
+		}},
+	})
+
+	entry, ok := provenance.Get("/items/1/name")
+	assert.Equals(t, ok, true)
+	assert.Equals(
+		t,
+		entry.String(),
+		`/items/1/name: " Arca " -> transform trim -> "Arca" -> transform lowercase -> "arca"`,
+	)
+	_, ok = provenance.Get("/items/0/name")
+	assert.Equals(t, ok, false)
+}
+
+func TestUnserializeWithProvenanceErrors(t *testing.T) {
+	_, provenance, err := schema.UnserializeWithProvenance(schema.NewIntSchema(nil, nil, nil), "not a number")
+	assert.Error(t, err)
+	assert.Nil(t, provenance)
+}
@@ -0,0 +1,249 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/clock"
+)
+
+// RateLimiterConfig configures a token-bucket RateLimiter. Steps calling a rate-limited service can add a property
+// created with NewRateLimiterConfigProperty to their input. The SDK then finds the configuration in the step input and
+// provides the rate limiter to the handler through RateLimiterFromContext. Runs of the same step with the same
+// configuration share a rate limiter.
+type RateLimiterConfig struct {
+	// Rate is the average number of operations allowed per second.
+	Rate float64 `json:"rate"`
+	// Burst is the number of operations allowed at once. Values below 1 are treated as 1.
+	Burst int64 `json:"burst"`
+}
+
+var rateLimiterConfigObject = NewStructMappedObjectSchema[*RateLimiterConfig](
+	"RateLimiterConfig",
+	map[string]*PropertySchema{
+		"rate": NewPropertySchema(
+			NewFloatSchema(PointerTo(0.0), nil, nil),
+			NewDisplayValue(
+				PointerTo("Rate"),
+				PointerTo("Average number of operations allowed per second."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"0.5"},
+		),
+		"burst": NewPropertySchema(
+			NewIntSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Burst"),
+				PointerTo("Number of operations allowed at once."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			PointerTo("1"),
+			nil,
+		),
+	},
+)
+
+// NewRateLimiterConfigProperty creates an optional input property holding a RateLimiterConfig. Map it to a
+// *RateLimiterConfig field of the input struct.
+func NewRateLimiterConfigProperty() *PropertySchema {
+	return NewPropertySchema(
+		rateLimiterConfigObject,
+		NewDisplayValue(
+			PointerTo("Rate limit"),
+			PointerTo("Limits how often the step calls the services it depends on."),
+			nil,
+		),
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// NewRateLimiter creates a token-bucket rate limiter. The bucket starts full.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	burst := float64(config.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   config.Rate,
+		burst:  burst,
+		tokens: burst,
+		clock:  clock.Real(),
+	}
+}
+
+// WithClock is a builder-pattern way of making the rate limiter refill and wait according to the clock, such as a
+// clock.Fake in tests.
+func (r *RateLimiter) WithClock(c clock.Clock) *RateLimiter {
+	r.clock = c
+	return r
+}
+
+// RateLimiter is a token-bucket rate limiter that is safe for concurrent use.
+type RateLimiter struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	clock  clock.Clock
+}
+
+// Allow takes a token if one is available and returns whether it did.
+func (r *RateLimiter) Allow() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.refill()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// Wait blocks until a token is available and takes it. It returns the error of the context if the context is done
+// first.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay, err := r.reserve()
+		if err != nil || delay == 0 {
+			return err
+		}
+		timer := r.clock.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C():
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long it takes until one is.
+func (r *RateLimiter) reserve() (time.Duration, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.refill()
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0, nil
+	}
+	if r.rate <= 0 {
+		return 0, fmt.Errorf("rate limiter with a rate of %v never allows another operation", r.rate)
+	}
+	return time.Duration(math.Ceil((1 - r.tokens) / r.rate * float64(time.Second))), nil
+}
+
+func (r *RateLimiter) refill() {
+	now := r.clock.Now()
+	if !r.last.IsZero() {
+		r.tokens = math.Min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.rate)
+	}
+	r.last = now
+}
+
+type rateLimiterContextKey struct{}
+
+// ContextWithRateLimiter returns a copy of the context carrying the rate limiter.
+func ContextWithRateLimiter(ctx context.Context, limiter *RateLimiter) context.Context {
+	return context.WithValue(ctx, rateLimiterContextKey{}, limiter)
+}
+
+// RateLimiterFromContext returns the rate limiter of the step the context belongs to, or nil if it has none.
+func RateLimiterFromContext(ctx context.Context) *RateLimiter {
+	limiter, _ := ctx.Value(rateLimiterContextKey{}).(*RateLimiter)
+	return limiter
+}
+
+// WaitRateLimit waits for the rate limiter of the context like RateLimiter.Wait. It returns immediately if the context
+// has no rate limiter, so handlers can call it before each outbound request whether rate limiting is configured or
+// not.
+func WaitRateLimit(ctx context.Context) error {
+	limiter := RateLimiterFromContext(ctx)
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// rateLimiters holds the rate limiters of a step, one for each configuration its inputs used.
+type rateLimiters struct {
+	lock     sync.Mutex
+	limiters map[RateLimiterConfig]*RateLimiter
+}
+
+// contextWithInputRateLimiter adds the rate limiter for the configuration in the input to the context, if the input
+// has one.
+func (r *rateLimiters) contextWithInputRateLimiter(ctx context.Context, input any) context.Context {
+	config := findRateLimiterConfig(input)
+	if config == nil {
+		return ctx
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.limiters == nil {
+		r.limiters = map[RateLimiterConfig]*RateLimiter{}
+	}
+	limiter, ok := r.limiters[*config]
+	if !ok {
+		limiter = NewRateLimiter(*config).WithClock(clock.FromContext(ctx))
+		r.limiters[*config] = limiter
+	}
+	return ContextWithRateLimiter(ctx, limiter)
+}
+
+// findRateLimiterConfig returns a rate limiter configuration among the fields of a struct input or the values
+// of a map input.
+func findRateLimiterConfig(input any) *RateLimiterConfig {
+	v := reflect.ValueOf(input)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if config := asRateLimiterConfig(v.Field(i).Interface()); config != nil {
+				return config
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if config := asRateLimiterConfig(iter.Value().Interface()); config != nil {
+				return config
+			}
+		}
+	}
+	return nil
+}
+
+func asRateLimiterConfig(value any) *RateLimiterConfig {
+	switch config := value.(type) {
+	case *RateLimiterConfig:
+		return config
+	case RateLimiterConfig:
+		return &config
+	default:
+		return nil
+	}
+}
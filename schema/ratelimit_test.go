@@ -0,0 +1,113 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/clock"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := schema.NewRateLimiter(schema.RateLimiterConfig{Rate: 1, Burst: 2})
+	assert.Equals(t, limiter.Allow(), true)
+	assert.Equals(t, limiter.Allow(), true)
+	assert.Equals(t, limiter.Allow(), false)
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := schema.NewRateLimiter(schema.RateLimiterConfig{Rate: 100})
+	assert.NoError(t, limiter.Wait(context.Background()))
+	start := time.Now()
+	assert.NoError(t, limiter.Wait(context.Background()))
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Fatalf("the second token was available after %v", elapsed)
+	}
+
+	slowLimiter := schema.NewRateLimiter(schema.RateLimiterConfig{Rate: 0.01})
+	assert.Equals(t, slowLimiter.Allow(), true)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, slowLimiter.Wait(ctx))
+}
+
+func TestWaitRateLimitWithoutLimiter(t *testing.T) {
+	assert.Nil(t, schema.RateLimiterFromContext(context.Background()))
+	assert.NoError(t, schema.WaitRateLimit(context.Background()))
+}
+
+type rateLimitedInput struct {
+	URL       string                    `json:"url"`
+	RateLimit *schema.RateLimiterConfig `json:"rate_limit"`
+}
+
+func TestStepRateLimiterFromInput(t *testing.T) {
+	var limiters []*schema.RateLimiter
+	step := schema.NewCallableStep[*rateLimitedInput](
+		"fetch",
+		schema.NewScopeSchema(
+			schema.NewStructMappedObjectSchema[*rateLimitedInput](
+				"input",
+				map[string]*schema.PropertySchema{
+					"url": schema.NewPropertySchema(
+						schema.NewStringSchema(nil, nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+					"rate_limit": schema.NewRateLimiterConfigProperty(),
+				},
+			),
+		),
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+				nil,
+				false,
+			),
+		},
+		nil,
+		func(ctx context.Context, _ *rateLimitedInput) (string, any) {
+			limiters = append(limiters, schema.RateLimiterFromContext(ctx))
+			return "success", map[string]any{}
+		},
+	)
+
+	for i, runID := range []string{"run-1", "run-2", "run-3"} {
+		serialized := map[string]any{"url": "https://example.com"}
+		if i < 2 {
+			serialized["rate_limit"] = map[string]any{"rate": 2.0}
+		}
+		input := assert.NoErrorR[any](t)(step.Input().Unserialize(serialized))
+		_, _, err := step.Call(context.Background(), runID, input)
+		assert.NoError(t, err)
+	}
+
+	assert.NotNil(t, limiters[0])
+	// Runs with the same configuration share the rate limiter.
+	assert.Equals(t, limiters[0] == limiters[1], true)
+	assert.Nil(t, limiters[2])
+}
+
+func TestRateLimiterWithFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	limiter := schema.NewRateLimiter(schema.RateLimiterConfig{Rate: 1}).WithClock(fake)
+	assert.Equals(t, limiter.Allow(), true)
+	assert.Equals(t, limiter.Allow(), false)
+	fake.Advance(time.Second)
+	assert.Equals(t, limiter.Allow(), true)
+
+	waited := make(chan error)
+	go func() {
+		waited <- limiter.Wait(context.Background())
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	assert.NoError(t, <-waited)
+}
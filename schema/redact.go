@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"reflect"
+)
+
+// RedactedPlaceholder replaces sensitive values in the output of Redact.
+const RedactedPlaceholder = "<redacted>"
+
+// Redact returns a copy of the value in its serialized form with the values of all properties marked as sensitive
+// replaced by RedactedPlaceholder. Use it before logging or displaying step inputs and outputs. The value may be
+// serialized or unserialized; unserialized values are serialized first. If a value cannot be serialized, it is
+// replaced entirely so no secret can leak.
+func Redact(t Type, value any) any {
+	if value == nil {
+		return nil
+	}
+	if objectSchema, ok := objectSchemaOf(t); ok {
+		return redactObject(objectSchema, value)
+	}
+	if listSchema, ok := t.(untypedListSchema); ok {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice {
+			return value
+		}
+		result := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result[i] = Redact(listSchema.itemType(), v.Index(i).Interface())
+		}
+		return result
+	}
+	if mapSchema, ok := t.(untypedMapSchema); ok {
+		entries, ok := mapEntriesOf(value)
+		if !ok {
+			return value
+		}
+		if _, ordered := value.(orderedMap); ordered {
+			result := NewOrderedMap[any, any]()
+			for _, entry := range entries {
+				result.Set(entry.key.Interface(), Redact(mapSchema.valueType(), entry.value.Interface()))
+			}
+			return result
+		}
+		result := make(map[any]any, len(entries))
+		for _, entry := range entries {
+			result[entry.key.Interface()] = Redact(mapSchema.valueType(), entry.value.Interface())
+		}
+		return result
+	}
+	if tupleSchema, ok := t.(*TupleSchema); ok {
+		return redactTuple(tupleSchema, value)
+	}
+	if oneOfSchema, ok := t.(variantSelector); ok {
+		return redactOneOf(t, oneOfSchema, value)
+	}
+	return value
+}
+
+func redactTuple(t *TupleSchema, value any) any {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		serialized, err := t.Serialize(value)
+		if err != nil {
+			return RedactedPlaceholder
+		}
+		v = reflect.ValueOf(serialized)
+	}
+	if v.Len() != len(t.ItemsValue) {
+		return RedactedPlaceholder
+	}
+	result := make([]any, v.Len())
+	for i, item := range t.ItemsValue {
+		result[i] = Redact(item, v.Index(i).Interface())
+	}
+	return result
+}
+
+func redactObject(o *ObjectSchema, value any) any {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map {
+		serialized, err := o.Serialize(value)
+		if err != nil {
+			return RedactedPlaceholder
+		}
+		v = reflect.ValueOf(serialized)
+		if v.Kind() != reflect.Map {
+			return RedactedPlaceholder
+		}
+	}
+	result := make(map[string]any, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		key, ok := iter.Key().Interface().(string)
+		if !ok {
+			continue
+		}
+		property, ok := o.PropertiesValue[key]
+		switch {
+		case !ok:
+			result[key] = iter.Value().Interface()
+		case property.Sensitive():
+			result[key] = RedactedPlaceholder
+		default:
+			result[key] = Redact(property.Type(), iter.Value().Interface())
+		}
+	}
+	return result
+}
+
+func redactOneOf(t Type, o variantSelector, value any) any {
+	serialized := value
+	if reflect.ValueOf(value).Kind() != reflect.Map {
+		var err error
+		serialized, err = t.Serialize(value)
+		if err != nil {
+			return RedactedPlaceholder
+		}
+	}
+	discriminator, selectedType, variantData, err := o.selectVariant(serialized)
+	if err != nil {
+		return RedactedPlaceholder
+	}
+	result, ok := Redact(selectedType, variantData).(map[string]any)
+	if !ok {
+		return RedactedPlaceholder
+	}
+	return o.serializedVariant(discriminator, selectedType, result)
+}
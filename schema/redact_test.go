@@ -0,0 +1,88 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type redactTestCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+var redactTestSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[redactTestCredentials](
+		"credentials",
+		map[string]*schema.PropertySchema{
+			"username": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"password": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(8), nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			).MarkSensitive(),
+		},
+	),
+)
+
+func TestRedact(t *testing.T) {
+	expected := map[string]any{
+		"username": "admin",
+		"password": schema.RedactedPlaceholder,
+	}
+	// Serialized data
+	assert.Equals(
+		t,
+		schema.Redact(redactTestSchema, map[string]any{"username": "admin", "password": "secret123"}),
+		any(expected),
+	)
+	// Unserialized data
+	assert.Equals(
+		t,
+		schema.Redact(redactTestSchema, redactTestCredentials{Username: "admin", Password: "secret123"}),
+		any(expected),
+	)
+	// Nested in a list
+	list := schema.NewListSchema(redactTestSchema, nil, nil)
+	assert.Equals(
+		t,
+		schema.Redact(list, []any{map[string]any{"username": "admin", "password": "secret123"}}),
+		any([]any{expected}),
+	)
+	// Data that cannot be serialized is redacted entirely.
+	assert.Equals(t, schema.Redact(redactTestSchema, 42), any(schema.RedactedPlaceholder))
+}
+
+func TestSensitivePropertyErrorRedaction(t *testing.T) {
+	_, err := redactTestSchema.Unserialize(map[string]any{"username": "admin", "password": "short"})
+	assert.Error(t, err)
+	assert.Equals(t, err.Error(), "Validation failed for 'password': Sensitive value violates the min constraint")
+	var c *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &c), true)
+	assert.Nil(t, c.Actual)
+}
+
+func TestSensitivePropertySelfSerialization(t *testing.T) {
+	serialized := assert.NoErrorR[any](t)(redactTestSchema.SelfSerialize())
+	objects := serialized.(map[string]any)["objects"].(map[any]any)
+	properties := objects["credentials"].(map[string]any)["properties"].(map[any]any)
+	assert.Equals(t, properties["password"].(map[string]any)["sensitive"], any(true))
+	_, hasSensitive := properties["username"].(map[string]any)["sensitive"]
+	assert.Equals(t, hasSensitive, false)
+}
@@ -26,10 +26,9 @@ func NewRefSchema(id string, display Display) *RefSchema {
 // NewNamespacedRefSchema creates a new reference to an object in a wrapping Scope by ID and namespace.
 func NewNamespacedRefSchema(id string, namespace string, display Display) *RefSchema {
 	return &RefSchema{
-		id,
-		display,
-		namespace,
-		nil,
+		IDValue:         id,
+		DisplayValue:    display,
+		ObjectNamespace: namespace,
 	}
 }
 
@@ -39,6 +38,7 @@ type RefSchema struct {
 	ObjectNamespace string  `json:"namespace"`
 
 	referencedObjectCache Object
+	locked                bool
 }
 
 func (r *RefSchema) Properties() map[string]*PropertySchema {
@@ -121,9 +121,25 @@ func (r *RefSchema) ApplyNamespace(objects map[string]*ObjectSchema, namespace s
 			Message: fmt.Sprintf("Referenced object '%s' not found in scope with namespace %q; available:\n%s", r.IDValue, namespace, availableObjects),
 		})
 	}
+	if r.locked {
+		if referencedObject != r.referencedObjectCache {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf(
+					"cannot link reference to object %s in namespace %q to a different object, it is part of a locked scope",
+					r.IDValue,
+					namespace,
+				),
+			})
+		}
+		return
+	}
 	r.referencedObjectCache = referencedObject
 }
 
+func (r *RefSchema) ApplyDefaults(serialized any) any {
+	return ApplyDefaults(r.GetObject(), serialized)
+}
+
 func (r *RefSchema) ValidateReferences() error {
 	if r.referencedObjectCache != nil {
 		return nil // Success
@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Rewrite builds a derived schema, such as one without sensitive properties for a UI or an external export, and leaves
+// the original untouched. It copies the type through its serialized form and calls the rewriter for the types of the
+// copy with the same paths as Walk. The rewriter returns the type to use in place of the given one:
+//
+//   - the given type, which the rewriter may have modified, to continue with the types it contains,
+//   - another type to use it as is, without calling the rewriter for the types it contains,
+//   - nil to remove an object property.
+//
+// If the rewriter returns SkipChildren, the returned type is used without calling the rewriter for the types it
+// contains. Any other error stops the rewrite and is returned. Objects are rewritten once, with the path they are
+// first reached by. Objects that replace an object of a scope must keep its ID.
+//
+// Since the copy is built from the serialized form, its objects unserialize to maps and not to the structs of the
+// original.
+func Rewrite(root Type, rewriter func(path []string, t Type) (Type, error)) (Type, error) {
+	serialized, err := valueType.Serialize(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize %s schema for rewriting (%w)", root.TypeID(), err)
+	}
+	copied, err := valueType.Unserialize(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy %s schema for rewriting (%w)", root.TypeID(), err)
+	}
+	r := &schemaRewriter{
+		rewriter: rewriter,
+		visited:  map[*ObjectSchema]bool{},
+	}
+	result, err := r.rewrite(nil, copied.(Type))
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, BadArgumentError{Message: "the rewriter removed the root type"}
+	}
+	return result, nil
+}
+
+type schemaRewriter struct {
+	rewriter func(path []string, t Type) (Type, error)
+	// visited holds the objects of the copy that have been rewritten.
+	visited map[*ObjectSchema]bool
+	// scopes holds the scopes the rewritten type is in, innermost last.
+	scopes []*ScopeSchema
+}
+
+func (r *schemaRewriter) rewrite(path []string, t Type) (Type, error) {
+	object, isObject := t.(*ObjectSchema)
+	if isObject {
+		if r.visited[object] {
+			return t, nil
+		}
+		r.visited[object] = true
+	}
+	replacement, err := r.rewriter(path, t)
+	if err != nil {
+		if errors.Is(err, SkipChildren) {
+			return replacement, nil
+		}
+		return nil, err
+	}
+	if replacement != t {
+		return replacement, nil
+	}
+	switch typed := t.(type) {
+	case *ScopeSchema:
+		return typed, r.rewriteScope(path, typed)
+	case *RefSchema:
+		return typed, r.rewriteRef(path, typed)
+	case *ObjectSchema:
+		return typed, r.rewriteProperties(path, typed)
+	case *ListSchema:
+		typed.ItemsValue, err = r.rewriteRequired(appendPath(path, "items"), typed.ItemsValue)
+	case *MapSchema[Type, Type]:
+		typed.KeysValue, err = r.rewriteRequired(appendPath(path, "keys"), typed.KeysValue)
+		if err == nil {
+			typed.ValuesValue, err = r.rewriteRequired(appendPath(path, "values"), typed.ValuesValue)
+		}
+	case *TupleSchema:
+		for i, item := range typed.ItemsValue {
+			if typed.ItemsValue[i], err = r.rewriteRequired(appendPath(path, fmt.Sprintf("[%d]", i)), item); err != nil {
+				return nil, err
+			}
+		}
+	case *OneOfSchema[string]:
+		err = rewriteOneOf(r, path, typed)
+	case *OneOfSchema[int64]:
+		err = rewriteOneOf(r, path, typed)
+	}
+	return t, err
+}
+
+// rewriteRequired rewrites a type that cannot be removed.
+func (r *schemaRewriter) rewriteRequired(path []string, t Type) (Type, error) {
+	replacement, err := r.rewrite(path, t)
+	if err == nil && replacement == nil {
+		return nil, BadArgumentError{
+			Message: fmt.Sprintf("the rewriter removed the %s type at %v, which is not a property", t.TypeID(), path),
+		}
+	}
+	return replacement, err
+}
+
+// rewriteScopeObject rewrites an object of the scope and stores its replacement in the scope.
+func (r *schemaRewriter) rewriteScopeObject(path []string, scope *ScopeSchema, object *ObjectSchema) error {
+	replacement, err := r.rewriteRequired(path, object)
+	if err != nil {
+		return err
+	}
+	replacementObject, ok := replacement.(*ObjectSchema)
+	if !ok || replacementObject.ID() != object.ID() {
+		return BadArgumentError{
+			Message: fmt.Sprintf(
+				"the rewriter replaced the scope object %s at %v with something other than an object with the same ID",
+				object.ID(),
+				path,
+			),
+		}
+	}
+	scope.ObjectsValue[object.ID()] = replacementObject
+	return nil
+}
+
+func (r *schemaRewriter) rewriteScope(path []string, scope *ScopeSchema) error {
+	r.scopes = append(r.scopes, scope)
+	err := r.rewriteScopeObject(path, scope, scope.RootObject())
+	r.scopes = r.scopes[:len(r.scopes)-1]
+	if err != nil {
+		return err
+	}
+	// Links the references to the rewritten objects.
+	scope.ApplySelf()
+	return nil
+}
+
+// rewriteRef rewrites the object the reference points to if it is in the innermost scope.
+func (r *schemaRewriter) rewriteRef(path []string, ref *RefSchema) error {
+	if len(r.scopes) == 0 {
+		return nil
+	}
+	scope := r.scopes[len(r.scopes)-1]
+	object, ok := scope.ObjectsValue[ref.IDValue]
+	if !ok || r.visited[object] {
+		return nil
+	}
+	return r.rewriteScopeObject(path, scope, object)
+}
+
+func (r *schemaRewriter) rewriteProperties(path []string, object *ObjectSchema) error {
+	// The rewriter may have renamed properties, so the lookups are rebuilt when the namespace is applied.
+	object.aliases = nil
+	object.normalizedKeys = nil
+	propertyIDs := make([]string, 0, len(object.PropertiesValue))
+	for propertyID := range object.PropertiesValue {
+		propertyIDs = append(propertyIDs, propertyID)
+	}
+	sort.Strings(propertyIDs)
+	for _, propertyID := range propertyIDs {
+		property := object.PropertiesValue[propertyID]
+		replacement, err := r.rewrite(appendPath(path, propertyID), property.TypeValue)
+		if err != nil {
+			return err
+		}
+		if replacement == nil {
+			delete(object.PropertiesValue, propertyID)
+		} else {
+			property.TypeValue = replacement
+		}
+	}
+	return nil
+}
+
+func rewriteOneOf[KeyType int64 | string](r *schemaRewriter, path []string, oneOf *OneOfSchema[KeyType]) error {
+	keys := make([]KeyType, 0, len(oneOf.TypesValue))
+	for key := range oneOf.TypesValue {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		replacement, err := r.rewriteRequired(appendPath(path, fmt.Sprintf("%v", key)), oneOf.TypesValue[key])
+		if err != nil {
+			return err
+		}
+		replacementObject, ok := replacement.(Object)
+		if !ok {
+			return BadArgumentError{
+				Message: fmt.Sprintf("the rewriter replaced the one-of type %v at %v with a non-object", key, path),
+			}
+		}
+		oneOf.TypesValue[key] = replacementObject
+	}
+	return nil
+}
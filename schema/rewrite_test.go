@@ -0,0 +1,131 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func rewriteTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewObjectSchema("config", map[string]*schema.PropertySchema{
+			"user": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"password": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			).MarkSensitive(),
+			"servers": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewRefSchema("server", nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		schema.NewObjectSchema("server", map[string]*schema.PropertySchema{
+			"host": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"port": schema.NewPropertySchema(schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil),
+		}),
+	)
+}
+
+func TestRewrite(t *testing.T) {
+	original := rewriteTestScope()
+	originalHash := assert.NoErrorR[string](t)(schema.Hash(original))
+
+	var paths []string
+	rewriter := func(path []string, t schema.Type) (schema.Type, error) {
+		paths = append(paths, strings.Join(path, "."))
+		object, ok := t.(*schema.ObjectSchema)
+		if !ok {
+			return t, nil
+		}
+		for propertyID, property := range object.PropertiesValue {
+			// Strip the sensitive properties.
+			if property.SensitiveValue {
+				delete(object.PropertiesValue, propertyID)
+			}
+			// Inject defaults.
+			if propertyID == "port" {
+				property.DefaultValue = schema.PointerTo("443")
+			}
+		}
+		// Rename fields.
+		if host, ok := object.PropertiesValue["host"]; ok {
+			delete(object.PropertiesValue, "host")
+			object.PropertiesValue["hostname"] = host
+		}
+		return t, nil
+	}
+	rewritten := assert.NoErrorR[schema.Type](t)(schema.Rewrite(original, rewriter))
+
+	// The original is untouched.
+	assert.Equals(t, assert.NoErrorR[string](t)(schema.Hash(original)), originalHash)
+	assert.Equals(t, len(original.Objects()["config"].Properties()), 3)
+
+	scope := rewritten.(*schema.ScopeSchema)
+	assert.Equals(t, len(scope.Objects()["config"].Properties()), 2)
+	assert.Equals(t, *scope.Objects()["server"].Properties()["port"].Default(), "443")
+	data := assert.NoErrorR[any](t)(scope.Unserialize(map[string]any{
+		"user":    "admin",
+		"servers": []any{map[string]any{"hostname": "example.com"}},
+	}))
+	assert.Equals(t, data.(map[string]any)["servers"].([]map[string]any)[0]["port"], any(int64(443)))
+	assert.Equals(t, paths, []string{
+		"",
+		"",
+		"servers",
+		"servers.items",
+		"servers.items",
+		"servers.items.hostname",
+		"servers.items.port",
+		"user",
+	})
+}
+
+func TestRewriteReplaceAndRemove(t *testing.T) {
+	rewritten := assert.NoErrorR[schema.Type](t)(schema.Rewrite(
+		rewriteTestScope(),
+		func(path []string, t schema.Type) (schema.Type, error) {
+			switch {
+			case len(path) == 1 && path[0] == "user":
+				return schema.NewIntSchema(nil, nil, nil), nil
+			case len(path) == 1 && path[0] == "servers":
+				return nil, nil
+			}
+			return t, nil
+		},
+	))
+	properties := rewritten.(*schema.ScopeSchema).Properties()
+	assert.Equals(t, properties["user"].Type().TypeID(), schema.TypeIDInt)
+	_, ok := properties["servers"]
+	assert.Equals(t, ok, false)
+}
+
+func TestRewriteErrors(t *testing.T) {
+	testErr := errors.New("test")
+	_, err := schema.Rewrite(rewriteTestScope(), func(_ []string, _ schema.Type) (schema.Type, error) {
+		return nil, testErr
+	})
+	assert.Equals(t, errors.Is(err, testErr), true)
+
+	_, err = schema.Rewrite(rewriteTestScope(), func(path []string, t schema.Type) (schema.Type, error) {
+		if len(path) == 2 {
+			return nil, nil
+		}
+		return t, nil
+	})
+	assert.Error(t, err)
+}
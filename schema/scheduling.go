@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scheduling holds hints on when and how often a step should be executed, such as the quota of an upstream API the
+// step wraps. The SDK does not enforce them, engines may honor them when scheduling the step.
+type Scheduling struct {
+	// RateLimits limit how often the step should be executed. All limits apply.
+	RateLimits []RateLimit `json:"rate_limits"`
+	// ExecutionWindows hold the times in which the step should be executed. If there are none, the step can be
+	// executed at any time, otherwise it should be executed in any of the windows.
+	ExecutionWindows []ExecutionWindow `json:"execution_windows"`
+}
+
+// RateLimit limits the number of executions of a step in a period of time.
+type RateLimit struct {
+	// MaxExecutions is the number of executions allowed in each period.
+	MaxExecutions int64 `json:"max_executions"`
+	// Period is the length of the period.
+	Period time.Duration `json:"period"`
+}
+
+// ExecutionWindow is a recurring time of day, on some days of the week, in a time zone.
+type ExecutionWindow struct {
+	// TimeZone is the IANA name of the time zone the window is in, such as "Europe/Berlin".
+	TimeZone string `json:"time_zone"`
+	// Weekdays holds the lowercase English names of the days the window is open on, such as "monday". If it is empty,
+	// the window is open every day.
+	Weekdays []string `json:"weekdays"`
+	// Start is the time the window opens, in the HH:MM format.
+	Start string `json:"start"`
+	// End is the time the window closes, in the HH:MM format. If it is not after Start, the window closes on the next
+	// day.
+	End string `json:"end"`
+}
+
+// Contains returns true if the window is open at the given time. It returns an error if the time zone is unknown or
+// a time is not in the HH:MM format.
+func (w ExecutionWindow) Contains(t time.Time) (bool, error) {
+	location, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("invalid execution window time zone %s (%w)", w.TimeZone, err)
+	}
+	start, err := parseMinuteOfDay(w.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseMinuteOfDay(w.End)
+	if err != nil {
+		return false, err
+	}
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+	if end <= start {
+		if minute >= end && minute < start {
+			return false, nil
+		}
+		if minute < end {
+			// The window opened on the previous day.
+			day = (day + 6) % 7
+		}
+	} else if minute < start || minute >= end {
+		return false, nil
+	}
+	return w.openOn(day), nil
+}
+
+func (w ExecutionWindow) openOn(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range w.Weekdays {
+		if strings.EqualFold(weekday, day.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if a step with these scheduling hints can be executed at the given time according to its
+// execution windows. Rate limits depend on the previous executions, so they are left to the engine.
+func (s Scheduling) Allows(t time.Time) (bool, error) {
+	if len(s.ExecutionWindows) == 0 {
+		return true, nil
+	}
+	for _, window := range s.ExecutionWindows {
+		open, err := window.Contains(t)
+		if err != nil || open {
+			return open, err
+		}
+	}
+	return false, nil
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid execution window time %s, expected HH:MM (%w)", value, err)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
@@ -0,0 +1,115 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestSchedulingSelfSerialization(t *testing.T) {
+	callableSchema := schema.NewCallableSchema(
+		schema.NewCallableStep[map[string]any](
+			"fetch",
+			schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{})),
+			map[string]*schema.StepOutputSchema{
+				"success": schema.NewStepOutputSchema(
+					schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+					nil,
+					false,
+				),
+			},
+			nil,
+			func(_ context.Context, _ map[string]any) (string, any) {
+				return "success", map[string]any{}
+			},
+		).(*schema.CallableStepSchema[any, map[string]any]).Schedule(schema.Scheduling{
+			RateLimits: []schema.RateLimit{
+				{MaxExecutions: 1, Period: time.Minute},
+			},
+			ExecutionWindows: []schema.ExecutionWindow{
+				{TimeZone: "UTC", Weekdays: []string{"monday", "friday"}, Start: "09:00", End: "17:00"},
+			},
+		}),
+	)
+
+	serialized := assert.NoErrorR[any](t)(callableSchema.SelfSerialize())
+	unserialized := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(serialized))
+
+	scheduling := unserialized.StepsValue["fetch"].Scheduling()
+	assert.Equals(t, scheduling.RateLimits, []schema.RateLimit{{MaxExecutions: 1, Period: time.Minute}})
+	assert.Equals(t, scheduling.ExecutionWindows[0].TimeZone, "UTC")
+	assert.Equals(t, scheduling.ExecutionWindows[0].Weekdays, []string{"monday", "friday"})
+	assert.Equals(t, scheduling.ExecutionWindows[0].Start, "09:00")
+	assert.Equals(t, scheduling.ExecutionWindows[0].End, "17:00")
+}
+
+func TestSchedulingInvalidTime(t *testing.T) {
+	serialized := map[string]any{
+		"execution_windows": []any{
+			map[string]any{"time_zone": "UTC", "start": "9:00", "end": "17:00"},
+		},
+	}
+	_, err := schema.DescribeScope().Objects()["Scheduling"].Unserialize(serialized)
+	assert.Error(t, err)
+}
+
+func TestExecutionWindowContains(t *testing.T) {
+	businessHours := schema.ExecutionWindow{
+		TimeZone: "UTC",
+		Weekdays: []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+		Start:    "09:00",
+		End:      "17:00",
+	}
+	// 2024-01-01 is a Monday.
+	assert.Equals(t, assert.NoErrorR[bool](t)(businessHours.Contains(
+		time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+	)), true)
+	assert.Equals(t, assert.NoErrorR[bool](t)(businessHours.Contains(
+		time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),
+	)), false)
+	assert.Equals(t, assert.NoErrorR[bool](t)(businessHours.Contains(
+		time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC),
+	)), false)
+	// The time is converted to the time zone of the window.
+	assert.Equals(t, assert.NoErrorR[bool](t)(businessHours.Contains(
+		time.Date(2024, 1, 1, 10, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+	)), false)
+
+	// The night from Friday to Saturday.
+	overnight := schema.ExecutionWindow{
+		TimeZone: "UTC",
+		Weekdays: []string{"friday"},
+		Start:    "22:00",
+		End:      "06:00",
+	}
+	assert.Equals(t, assert.NoErrorR[bool](t)(overnight.Contains(
+		time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC),
+	)), true)
+	assert.Equals(t, assert.NoErrorR[bool](t)(overnight.Contains(
+		time.Date(2024, 1, 6, 5, 59, 0, 0, time.UTC),
+	)), true)
+	assert.Equals(t, assert.NoErrorR[bool](t)(overnight.Contains(
+		time.Date(2024, 1, 5, 5, 0, 0, 0, time.UTC),
+	)), false)
+	assert.Equals(t, assert.NoErrorR[bool](t)(overnight.Contains(
+		time.Date(2024, 1, 6, 22, 0, 0, 0, time.UTC),
+	)), false)
+
+	_, err := schema.ExecutionWindow{TimeZone: "Nowhere/Invalid", Start: "09:00", End: "17:00"}.Contains(time.Now())
+	assert.Error(t, err)
+}
+
+func TestSchedulingAllows(t *testing.T) {
+	assert.Equals(t, assert.NoErrorR[bool](t)(schema.Scheduling{}.Allows(time.Now())), true)
+	scheduling := schema.Scheduling{
+		ExecutionWindows: []schema.ExecutionWindow{
+			{TimeZone: "UTC", Start: "01:00", End: "02:00"},
+			{TimeZone: "UTC", Start: "13:00", End: "14:00"},
+		},
+	}
+	assert.Equals(t, assert.NoErrorR[bool](t)(scheduling.Allows(time.Date(2024, 1, 1, 13, 30, 0, 0, time.UTC))), true)
+	assert.Equals(t, assert.NoErrorR[bool](t)(scheduling.Allows(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))), false)
+}
@@ -3,6 +3,8 @@ package schema
 import (
 	"context"
 	"fmt"
+
+	"go.flow.arcalot.io/pluginsdk/clock"
 )
 
 // Schema is a collection of steps supported by a plugin.
@@ -55,6 +57,21 @@ func (s SchemaSchema) applyNamespace() {
 	}
 }
 
+// resolveUnits replaces the units in the schema with the equal ones in the registry.
+func (s SchemaSchema) resolveUnits(registry *UnitsRegistry) {
+	for _, step := range s.StepsValue {
+		registry.Resolve(step.InputValue)
+		for _, output := range step.OutputsValue {
+			registry.Resolve(output.Schema())
+		}
+		for _, signals := range []map[string]*SignalSchema{step.SignalHandlersValue, step.SignalEmittersValue} {
+			for _, signal := range signals {
+				registry.Resolve(signal.DataSchemaValue)
+			}
+		}
+	}
+}
+
 func NewCallableSchema(
 	steps ...CallableStep,
 ) *CallableSchema {
@@ -64,12 +81,48 @@ func NewCallableSchema(
 	}
 
 	return &CallableSchema{
-		stepMap,
+		StepsValue: stepMap,
 	}
 }
 
 type CallableSchema struct {
-	StepsValue map[string]CallableStep `json:"steps"`
+	StepsValue        map[string]CallableStep `json:"steps"`
+	unserializeLimits *UnserializeLimits
+	yieldPolicy       *YieldPolicy
+}
+
+// WithUnserializeLimits is a builder-pattern way of replacing the DefaultUnserializeLimits the step inputs and signal
+// data are checked against before they are unserialized.
+func (s *CallableSchema) WithUnserializeLimits(limits UnserializeLimits) *CallableSchema {
+	s.unserializeLimits = &limits
+	return s
+}
+
+func (s CallableSchema) limits() UnserializeLimits {
+	if s.unserializeLimits == nil {
+		return DefaultUnserializeLimits()
+	}
+	return *s.unserializeLimits
+}
+
+// WithYieldPolicy is a builder-pattern way of making the unserialization of step inputs and signal data yield to other
+// goroutines according to the policy, and stop when the context of the call is cancelled. Inputs are then unserialized
+// like UnserializeAll, so all violations are reported at once.
+func (s *CallableSchema) WithYieldPolicy(policy YieldPolicy) *CallableSchema {
+	s.yieldPolicy = &policy
+	return s
+}
+
+// unserialize checks the serialized data against the limits and unserializes it with the type, yielding according to
+// the yield policy if there is one.
+func (s CallableSchema) unserialize(ctx context.Context, t Type, data any) (any, error) {
+	if s.yieldPolicy == nil {
+		return UnserializeWithLimits(t, data, s.limits())
+	}
+	if err := s.limits().check(data, newYielder(ctx, *s.yieldPolicy)); err != nil {
+		return nil, err
+	}
+	return UnserializeAllContext(ctx, t, data, *s.yieldPolicy)
 }
 
 func (s CallableSchema) CallStep(
@@ -88,19 +141,37 @@ func (s CallableSchema) CallStep(
 			Message: fmt.Sprintf("Invalid step called: %s", stepID),
 		}
 	}
-	unserializedInputData, err := step.Input().Unserialize(serializedInputData)
+	metrics := stepMetricsFromContext(ctx)
+	clk := clock.FromContext(ctx)
+	start := clk.Now()
+	unserializedInputData, err := s.unserialize(ctx, step.Input(), serializedInputData)
+	if metrics != nil {
+		metrics.UnserializeNanoseconds = clock.Since(clk, start).Nanoseconds()
+		metrics.InputBytes = encodedSize(serializedInputData)
+	}
 	if err != nil {
 		return "", nil, InvalidInputError{err}
 	}
+	start = clk.Now()
 	outputID, unserializedOutput, err := step.Call(ctx, runID, unserializedInputData)
+	if metrics != nil {
+		metrics.HandlerNanoseconds = clock.Since(clk, start).Nanoseconds()
+	}
 	if err != nil {
 		return outputID, nil, err
 	}
 	output := step.Outputs()[outputID]
+	start = clk.Now()
 	serializedData, err := output.Schema().Serialize(unserializedOutput)
+	if metrics != nil {
+		metrics.SerializeNanoseconds = clock.Since(clk, start).Nanoseconds()
+	}
 	if err != nil {
 		return "", nil, InvalidOutputError{err}
 	}
+	if metrics != nil {
+		metrics.OutputBytes = encodedSize(serializedData)
+	}
 	return outputID, serializedData, nil
 }
 
@@ -120,7 +191,7 @@ func (s CallableSchema) CallSignal(
 			Message: fmt.Sprintf("Invalid step called: %s", stepID),
 		}
 	}
-	unserializedInputData, err := step.SignalHandlers()[signalID].DataSchema().Unserialize(serializedInputData)
+	unserializedInputData, err := s.unserialize(ctx, step.SignalHandlers()[signalID].DataSchema(), serializedInputData)
 	if err != nil {
 		return InvalidInputError{err}
 	}
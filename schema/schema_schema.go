@@ -32,6 +32,22 @@ var idType = NewStringSchema(
 )
 var mapKeyType = NewOneOfStringSchema[any](
 	map[string]Object{
+		"enum_integer": NewRefSchema(
+			"IntEnum",
+			NewDisplayValue(
+				PointerTo("Integer enum"),
+				nil,
+				nil,
+			),
+		),
+		"enum_string": NewRefSchema(
+			"StringEnum",
+			NewDisplayValue(
+				PointerTo("String enum"),
+				nil,
+				nil,
+			),
+		),
 		"integer": NewRefSchema(
 			"Int",
 			NewDisplayValue(
@@ -72,6 +88,41 @@ var displayProperty = NewPropertySchema(
 	nil,
 	nil,
 )
+var timeOfDayPattern = regexp.MustCompile("^([01][0-9]|2[0-3]):[0-5][0-9]$")
+var deprecatedProperty = NewPropertySchema(
+	NewRefSchema(
+		"Deprecated",
+		nil,
+	),
+	NewDisplayValue(
+		PointerTo("Deprecated"),
+		PointerTo("Deprecation information, if this item should no longer be used."),
+		nil,
+	),
+	false,
+	nil,
+	nil,
+	nil,
+	nil,
+	nil,
+)
+var engineRequirementsProperty = NewPropertySchema(
+	NewRefSchema(
+		"EngineRequirements",
+		nil,
+	),
+	NewDisplayValue(
+		PointerTo("Engine requirements"),
+		PointerTo("What the engine has to support to use this item. Engines that don't are not offered it."),
+		nil,
+	),
+	false,
+	nil,
+	nil,
+	nil,
+	nil,
+	nil,
+)
 var valueType = NewOneOfStringSchema[any](
 	map[string]Object{
 		"any": NewRefSchema(
@@ -193,10 +244,21 @@ var valueType = NewOneOfStringSchema[any](
 				nil,
 			),
 		),
+		"tuple": NewRefSchema(
+			"Tuple",
+			NewDisplayValue(
+				PointerTo("Tuple"),
+				nil,
+				nil,
+			),
+		),
 	},
 	"type_id",
 	false,
 )
+
+// tupleItemType is valueType for a list of item types, which has to unserialize into a []Type.
+var tupleItemType = NewOneOfStringSchema[Type](valueType.TypesValue, "type_id", false)
 var scopeObject = NewStructMappedObjectSchema[*ScopeSchema](
 	"Scope",
 	map[string]*PropertySchema{
@@ -285,6 +347,243 @@ var basicObjects = []*ObjectSchema{
 			nil,
 			[]string{"\"<svg ...></svg>\""},
 		),
+		"version": versionProperty,
+	}).IgnoreUnknownFields(),
+	NewStructMappedObjectSchema[*Deprecated]("Deprecated", map[string]*PropertySchema{
+		"since": NewPropertySchema(
+			NewStringSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Since"),
+				PointerTo("Version in which the item was deprecated."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"1.2.0\""},
+		),
+		"message": NewPropertySchema(
+			NewStringSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Message"),
+				PointerTo("Explanation of the deprecation and what to do instead."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"Use the 'fruits' field instead.\""},
+		),
+		"replaced_by": NewPropertySchema(
+			NewStringSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Replaced by"),
+				PointerTo("ID of the item that replaces the deprecated item."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"fruits\""},
+		),
+	}),
+	NewStructMappedObjectSchema[*EngineRequirements]("EngineRequirements", map[string]*PropertySchema{
+		"min_version": NewPropertySchema(
+			NewStringSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Minimum version"),
+				PointerTo("Earliest engine version that supports the item."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"1.4.0\""},
+		),
+		"features": NewPropertySchema(
+			NewListSchema(NewStringSchema(IntPointer(1), nil, nil), nil, nil),
+			NewDisplayValue(
+				PointerTo("Features"),
+				PointerTo("Engine features the item needs. All of them have to be supported."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"[\"expressions-v2\"]"},
+		),
+	}),
+	NewStructMappedObjectSchema[*Scheduling]("Scheduling", map[string]*PropertySchema{
+		"rate_limits": NewPropertySchema(
+			NewListSchema(NewRefSchema("RateLimit", nil), nil, nil),
+			NewDisplayValue(
+				PointerTo("Rate limits"),
+				PointerTo("Limits on how often the step should be executed. All limits apply."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"execution_windows": NewPropertySchema(
+			NewListSchema(NewRefSchema("ExecutionWindow", nil), nil, nil),
+			NewDisplayValue(
+				PointerTo("Execution windows"),
+				PointerTo("Times in which the step should be executed. If empty, the step can be executed at any "+
+					"time."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}),
+	NewStructMappedObjectSchema[RateLimit]("RateLimit", map[string]*PropertySchema{
+		"max_executions": NewPropertySchema(
+			NewIntSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Maximum executions"),
+				PointerTo("Number of executions allowed in each period."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"1"},
+		),
+		"period": NewPropertySchema(
+			NewIntSchema(IntPointer(1), nil, UnitDurationNanoseconds),
+			NewDisplayValue(
+				PointerTo("Period"),
+				PointerTo("Length of the period the executions are counted in."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"60000000000"},
+		),
+	}),
+	NewStructMappedObjectSchema[*TruncationPolicy]("TruncationPolicy", map[string]*PropertySchema{
+		"max_string_length": NewPropertySchema(
+			NewIntSchema(IntPointer(0), nil, UnitBytes),
+			NewDisplayValue(
+				PointerTo("Maximum string length"),
+				PointerTo("Number of bytes strings are truncated to. If not set, strings are not truncated."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"65536"},
+		),
+		"max_list_items": NewPropertySchema(
+			NewIntSchema(IntPointer(0), nil, nil),
+			NewDisplayValue(
+				PointerTo("Maximum list items"),
+				PointerTo("Number of items lists are truncated to. If not set, lists are not truncated."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"1000"},
+		),
+	}),
+	NewStructMappedObjectSchema[ExecutionWindow]("ExecutionWindow", map[string]*PropertySchema{
+		"time_zone": NewPropertySchema(
+			NewStringSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Time zone"),
+				PointerTo("IANA name of the time zone the window is in."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"Europe/Berlin\""},
+		),
+		"weekdays": NewPropertySchema(
+			NewListSchema(
+				NewStringEnumSchema(map[string]*DisplayValue{
+					"monday":    {NameValue: PointerTo("Monday")},
+					"tuesday":   {NameValue: PointerTo("Tuesday")},
+					"wednesday": {NameValue: PointerTo("Wednesday")},
+					"thursday":  {NameValue: PointerTo("Thursday")},
+					"friday":    {NameValue: PointerTo("Friday")},
+					"saturday":  {NameValue: PointerTo("Saturday")},
+					"sunday":    {NameValue: PointerTo("Sunday")},
+				}),
+				nil,
+				nil,
+			),
+			NewDisplayValue(
+				PointerTo("Weekdays"),
+				PointerTo("Days the window is open on. If empty, the window is open every day."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"[\"monday\", \"friday\"]"},
+		),
+		"start": NewPropertySchema(
+			NewStringSchema(nil, nil, timeOfDayPattern),
+			NewDisplayValue(
+				PointerTo("Start"),
+				PointerTo("Time the window opens, in the HH:MM format."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"09:00\""},
+		),
+		"end": NewPropertySchema(
+			NewStringSchema(nil, nil, timeOfDayPattern),
+			NewDisplayValue(
+				PointerTo("End"),
+				PointerTo("Time the window closes, in the HH:MM format. If it is not after the start, the window "+
+					"closes on the next day."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"17:00\""},
+		),
 	}),
 	NewStructMappedObjectSchema[*FloatSchema]("Float", map[string]*PropertySchema{
 		"min": NewPropertySchema(
@@ -340,6 +639,47 @@ var basicObjects = []*ObjectSchema{
 			nil,
 			[]string{"{\"1024\": {\"name\": \"kB\"}, \"1048576\": {\"name\": \"MB\"}}"},
 		),
+		"value_aliases": NewPropertySchema(
+			NewMapSchema(
+				NewIntSchema(nil, nil, nil),
+				NewListSchema(NewStringSchema(IntPointer(1), nil, nil), IntPointer(1), nil),
+				nil,
+				nil,
+			),
+			NewDisplayValue(
+				PointerTo("Value aliases"),
+				PointerTo("Alternative spellings of the values that are accepted and replaced with the value."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"deprecated_values": NewPropertySchema(
+			NewMapSchema(
+				NewIntSchema(nil, nil, nil),
+				NewRefSchema(
+					"Deprecated",
+					nil,
+				),
+				nil,
+				nil,
+			),
+			NewDisplayValue(
+				PointerTo("Deprecated values"),
+				PointerTo("Deprecation information for the values that should no longer be used."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
 		"units": unitsProperty,
 	}),
 	NewStructMappedObjectSchema[*IntSchema](
@@ -374,6 +714,21 @@ var basicObjects = []*ObjectSchema{
 				[]string{"16"},
 			),
 			"units": unitsProperty,
+			"serialize_units": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("Serialize with units"),
+					PointerTo("If true, values are serialized as strings with units, such as \"1m30s\", instead of "+
+						"numbers in the base unit."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				PointerTo("false"),
+				nil,
+			).TreatEmptyAsDefaultValue(),
 		},
 	),
 	NewStructMappedObjectSchema[*ListSchema](
@@ -421,6 +776,35 @@ var basicObjects = []*ObjectSchema{
 				nil,
 				[]string{"16"},
 			),
+			"unique_items": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("Unique items"),
+					PointerTo("If true, lists with duplicate items are rejected."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				PointerTo("false"),
+				nil,
+			).TreatEmptyAsDefaultValue(),
+			"unique_key": NewPropertySchema(
+				NewListSchema(NewStringSchema(IntPointer(1), nil, nil), IntPointer(1), nil),
+				NewDisplayValue(
+					PointerTo("Unique key"),
+					PointerTo("Property path of the item value compared for uniqueness. If not set, whole items "+
+						"are compared."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"[\"name\"]"},
+			).TreatEmptyAsDefaultValue(),
 		},
 	),
 	NewStructMappedObjectSchema[*MapSchema[Type, Type]](
@@ -482,6 +866,21 @@ var basicObjects = []*ObjectSchema{
 				nil,
 				[]string{"16"},
 			),
+			"string_keys": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("String keys"),
+					PointerTo("If true, the integer keys are serialized as strings. Both integers and strings are "+
+						"accepted when unserializing."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				PointerTo("false"),
+				nil,
+			).TreatEmptyAsDefaultValue(),
 		},
 	),
 	NewStructMappedObjectSchema[*ObjectSchema](
@@ -542,11 +941,123 @@ var basicObjects = []*ObjectSchema{
 				PointerTo("false"),
 				nil,
 			),
+			"unknown_fields": NewPropertySchema(
+				NewStringEnumSchema(map[string]*DisplayValue{
+					string(UnknownFieldsReject):  {NameValue: PointerTo("Reject")},
+					string(UnknownFieldsIgnore):  {NameValue: PointerTo("Ignore")},
+					string(UnknownFieldsCollect): {NameValue: PointerTo("Collect")},
+				}),
+				NewDisplayValue(
+					PointerTo("Unknown fields"),
+					PointerTo("How keys that don't belong to any property are handled. If not set, they are rejected."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"ignore\""},
+			).TreatEmptyAsDefaultValue(),
+			"catch_all_property": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(
+					PointerTo("Catch-all property"),
+					PointerTo("Map property receiving the keys that don't belong to any property if unknown fields "+
+						"are collected."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"extra\""},
+			).TreatEmptyAsDefaultValue().RequireIfValue("unknown_fields", string(UnknownFieldsCollect)),
+			"key_matching": NewPropertySchema(
+				NewStringEnumSchema(map[string]*DisplayValue{
+					string(KeyMatchingStrict):            {NameValue: PointerTo("Strict")},
+					string(KeyMatchingCaseInsensitive):   {NameValue: PointerTo("Case insensitive")},
+					string(KeyMatchingNamingInsensitive): {NameValue: PointerTo("Naming insensitive")},
+				}),
+				NewDisplayValue(
+					PointerTo("Key matching"),
+					PointerTo("How input keys are matched to property IDs. If not set, keys must match exactly."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"case_insensitive\""},
+			).TreatEmptyAsDefaultValue(),
+			"value_property": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(
+					PointerTo("Value property"),
+					PointerTo("Property holding the value of a non-object one-of variant. If set, the object "+
+						"unserializes to the value of this property instead of a map."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"value\""},
+			).TreatEmptyAsDefaultValue(),
+			"capture_pattern": NewPropertySchema(
+				NewPatternSchema(),
+				NewDisplayValue(
+					PointerTo("Capture pattern"),
+					PointerTo("Pattern string inputs are parsed with. Each named group sets the property of "+
+						"the same name."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{`"^(?P<host>[^:]+):(?P<port>\\d+)$"`},
+			),
 		},
 	),
 	NewStructMappedObjectSchema[*OneOfSchema[int64]](
 		"OneOfIntSchema",
 		map[string]*PropertySchema{
+			"default_type": NewPropertySchema(
+				NewIntSchema(nil, nil, nil),
+				NewDisplayValue(
+					PointerTo("Default type"),
+					PointerTo("Discriminator value of the type used when the discriminator field is missing."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"value_field_name": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(
+					PointerTo("Value field name"),
+					PointerTo(
+						"Name of the field holding the variant data next to the discriminator. If not set, the "+
+							"discriminator is embedded in the variant data.",
+					),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"value\""},
+			).TreatEmptyAsDefaultValue(),
 			"discriminator_inlined": NewPropertySchema(
 				NewBoolSchema(),
 				NewDisplayValue(
@@ -608,6 +1119,54 @@ var basicObjects = []*ObjectSchema{
 	NewStructMappedObjectSchema[*OneOfSchema[string]](
 		"OneOfStringSchema",
 		map[string]*PropertySchema{
+			"default_type": NewPropertySchema(
+				NewStringSchema(nil, nil, nil),
+				NewDisplayValue(
+					PointerTo("Default type"),
+					PointerTo("Discriminator value of the type used when the discriminator field is missing."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"untagged": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("Untagged"),
+					PointerTo(
+						"Select the type by validating the data against each of the types instead of using a "+
+							"discriminator field.",
+					),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				PointerTo("false"),
+				nil,
+			).TreatEmptyAsDefaultValue(),
+			"value_field_name": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(
+					PointerTo("Value field name"),
+					PointerTo(
+						"Name of the field holding the variant data next to the discriminator. If not set, the "+
+							"discriminator is embedded in the variant data.",
+					),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"value\""},
+			).TreatEmptyAsDefaultValue(),
 			"discriminator_inlined": NewPropertySchema(
 				NewBoolSchema(),
 				NewDisplayValue(
@@ -664,12 +1223,63 @@ var basicObjects = []*ObjectSchema{
 				nil,
 				nil,
 			),
-		},
-	),
-	NewStructMappedObjectSchema[*PatternSchema](
-		"Pattern",
-		map[string]*PropertySchema{},
-	),
+		},
+	),
+	NewStructMappedObjectSchema[*PatternSchema](
+		"Pattern",
+		map[string]*PropertySchema{},
+	),
+	NewStructMappedObjectSchema[PropertyCondition]("PropertyCondition", map[string]*PropertySchema{
+		"property_id": NewPropertySchema(
+			NewStringSchema(IntPointer(1), nil, nil),
+			NewDisplayValue(
+				PointerTo("Property ID"),
+				PointerTo("ID of the property whose value is checked."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"\"mode\""},
+		),
+		"values": NewPropertySchema(
+			NewListSchema(
+				NewStringSchema(nil, nil, nil),
+				nil,
+				nil,
+			),
+			NewDisplayValue(
+				PointerTo("Values"),
+				PointerTo("Values in JSON encoding that trigger the condition."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			[]string{"[\"\\\"tls\\\"\"]"},
+		),
+		"negate": NewPropertySchema(
+			NewBoolSchema(),
+			NewDisplayValue(
+				PointerTo("Negate"),
+				PointerTo(
+					"Trigger the condition if the property is not set to any of the values, including when it "+
+						"is not set at all.",
+				),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			PointerTo("false"),
+			nil,
+		),
+	}),
 	NewStructMappedObjectSchema[*PropertySchema](
 		"Property",
 		map[string]*PropertySchema{
@@ -747,6 +1357,80 @@ var basicObjects = []*ObjectSchema{
 				nil,
 				nil,
 			),
+			"aliases": NewPropertySchema(
+				NewListSchema(
+					NewStringSchema(IntPointer(1), nil, nil),
+					nil,
+					nil,
+				),
+				NewDisplayValue(
+					PointerTo("Aliases"),
+					PointerTo(
+						"Alternate names the property is accepted under when unserializing. The property is "+
+							"always serialized under its ID.",
+					),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"[\"old_name\"]"},
+			).TreatEmptyAsDefaultValue(),
+			"transforms": NewPropertySchema(
+				NewListSchema(
+					NewRefSchema("Transform", nil),
+					nil,
+					nil,
+				),
+				NewDisplayValue(
+					PointerTo("Transforms"),
+					PointerTo("Transformations applied in order to the value before it is validated."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			).TreatEmptyAsDefaultValue(),
+			"transform_on_serialize": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("Transform on serialize"),
+					PointerTo("Also apply the transformations to the value before it is serialized."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				PointerTo("false"),
+				nil,
+			).TreatEmptyAsDefaultValue(),
+			"required_if_conditions": NewPropertySchema(
+				NewListSchema(
+					NewRefSchema("PropertyCondition", nil),
+					nil,
+					nil,
+				),
+				NewDisplayValue(
+					PointerTo("Required if conditions"),
+					PointerTo(
+						"Sets the current property to required if any of the conditions on the values of other "+
+							"properties apply.",
+					),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			).TreatEmptyAsDefaultValue(),
 			"conflicts": NewPropertySchema(
 				NewListSchema(
 					NewStringSchema(nil, nil, nil),
@@ -828,6 +1512,22 @@ var basicObjects = []*ObjectSchema{
 				nil,
 				nil,
 			),
+			"deprecated":          deprecatedProperty,
+			"engine_requirements": engineRequirementsProperty,
+			"sensitive": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("Sensitive"),
+					PointerTo("Whether the value is a secret that must not be logged or displayed."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			).TreatEmptyAsDefaultValue(),
 		},
 	),
 	NewStructMappedObjectSchema[*RefSchema](
@@ -867,6 +1567,20 @@ var basicObjects = []*ObjectSchema{
 	NewStructMappedObjectSchema[*StringEnumSchema](
 		"StringEnum",
 		map[string]*PropertySchema{
+			"catalog": NewPropertySchema(
+				NewStringSchema(IntPointer(1), nil, nil),
+				NewDisplayValue(
+					PointerTo("Catalog"),
+					PointerTo("URI of the centrally published enum catalog the values were taken from."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"urn:arcalot:catalog:severity:v1\""},
+			),
 			"values": NewPropertySchema(
 				NewMapSchema(
 					NewStringSchema(nil, nil, nil),
@@ -897,6 +1611,47 @@ var basicObjects = []*ObjectSchema{
 					"  }\n" +
 					"}"},
 			),
+			"value_aliases": NewPropertySchema(
+				NewMapSchema(
+					NewStringSchema(nil, nil, nil),
+					NewListSchema(NewStringSchema(IntPointer(1), nil, nil), IntPointer(1), nil),
+					nil,
+					nil,
+				),
+				NewDisplayValue(
+					PointerTo("Value aliases"),
+					PointerTo("Alternative spellings of the values that are accepted and replaced with the value."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"deprecated_values": NewPropertySchema(
+				NewMapSchema(
+					NewStringSchema(nil, nil, nil),
+					NewRefSchema(
+						"Deprecated",
+						nil,
+					),
+					nil,
+					nil,
+				),
+				NewDisplayValue(
+					PointerTo("Deprecated values"),
+					PointerTo("Deprecation information for the values that should no longer be used."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
 		},
 	),
 	NewStructMappedObjectSchema[*StringSchema](
@@ -946,6 +1701,79 @@ var basicObjects = []*ObjectSchema{
 			),
 		},
 	),
+	WithValidator(
+		NewStructMappedObjectSchema[Transform]("Transform", map[string]*PropertySchema{
+			"id": NewPropertySchema(
+				NewStringEnumSchema(map[string]*DisplayValue{
+					string(TransformTrim):      {NameValue: PointerTo("Trim whitespace")},
+					string(TransformLowercase): {NameValue: PointerTo("Lower case")},
+					string(TransformUppercase): {NameValue: PointerTo("Upper case")},
+					string(TransformClamp):     {NameValue: PointerTo("Clamp")},
+				}),
+				NewDisplayValue(
+					PointerTo("ID"),
+					PointerTo("The transformation to apply."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"\"trim\""},
+			),
+			"min": NewPropertySchema(
+				NewFloatSchema(nil, nil, nil),
+				NewDisplayValue(
+					PointerTo("Minimum"),
+					PointerTo("Lower bound for the clamp transformation (inclusive)."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"0"},
+			),
+			"max": NewPropertySchema(
+				NewFloatSchema(nil, nil, nil),
+				NewDisplayValue(
+					PointerTo("Maximum"),
+					PointerTo("Upper bound for the clamp transformation (inclusive)."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				[]string{"100"},
+			),
+		}),
+		func(t Transform) error {
+			return t.validate()
+		},
+	),
+	NewStructMappedObjectSchema[*TupleSchema](
+		"Tuple",
+		map[string]*PropertySchema{
+			"items": NewPropertySchema(
+				NewListSchema(tupleItemType, IntPointer(1), nil),
+				NewDisplayValue(
+					PointerTo("Items"),
+					PointerTo("Type definitions for the items of this tuple, in order."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
 	NewStructMappedObjectSchema[*UnitDefinition](
 		"Unit",
 		map[string]*PropertySchema{
@@ -1006,7 +1834,7 @@ var basicObjects = []*ObjectSchema{
 				[]string{"\"B\",\"char\""},
 			),
 		},
-	),
+	).IgnoreUnknownFields(),
 	NewStructMappedObjectSchema[*UnitsDefinition](
 		"Units",
 		map[string]*PropertySchema{
@@ -1068,9 +1896,27 @@ var basicObjects = []*ObjectSchema{
 						"}",
 				},
 			),
+			"version": versionProperty,
 		},
-	),
+	).IgnoreUnknownFields(),
 }
+
+// versionProperty is the version marker of the serialized forms that newer SDKs may extend, such as displays and
+// units. These objects ignore unknown fields, so older readers can read the forms of newer SDKs.
+var versionProperty = NewPropertySchema(
+	NewIntSchema(IntPointer(1), nil, nil),
+	NewDisplayValue(
+		PointerTo("Version"),
+		PointerTo("Version of the serialized form. Absent means version 1."),
+		nil,
+	),
+	false,
+	nil,
+	nil,
+	nil,
+	nil,
+	[]string{"2"},
+)
 var schemaObject = NewStructMappedObjectSchema[*SchemaSchema](
 	"Schema",
 	map[string]*PropertySchema{
@@ -1137,6 +1983,21 @@ var stepOutputSchemaObject = NewStructMappedObjectSchema[*StepOutputSchema](
 			nil,
 			nil,
 		),
+		"truncation": NewPropertySchema(
+			NewRefSchema("TruncationPolicy", nil),
+			NewDisplayValue(
+				PointerTo("Truncation"),
+				PointerTo("Limits the strings and lists of this output are truncated to. Truncated values are "+
+					"reported along with the output."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
 	},
 )
 var signalSchemaObject = NewStructMappedObjectSchema[*SignalSchema](
@@ -1179,7 +2040,26 @@ var signalSchemaObject = NewStructMappedObjectSchema[*SignalSchema](
 var stepSchemaObject = NewStructMappedObjectSchema[*StepSchema](
 	"Step",
 	map[string]*PropertySchema{
-		"display": displayProperty,
+		"display":             displayProperty,
+		"deprecated":          deprecatedProperty,
+		"engine_requirements": engineRequirementsProperty,
+		"scheduling": NewPropertySchema(
+			NewRefSchema(
+				"Scheduling",
+				nil,
+			),
+			NewDisplayValue(
+				PointerTo("Scheduling"),
+				PointerTo("Hints on when and how often the step should be executed."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
 		"id": NewPropertySchema(
 			idType,
 			NewDisplayValue(
@@ -1296,6 +2176,70 @@ var schemaSchema = NewScopeSchema(
 		signalSchemaObject,
 	)...,
 )
+var stepScopeSchema = NewScopeSchema(
+	stepSchemaObject,
+	append(
+		basicObjects,
+		scopeObject,
+		stepOutputSchemaObject,
+		signalSchemaObject,
+	)...,
+)
+var functionSchemaObject = NewStructMappedObjectSchema[*FunctionSchema](
+	"Function",
+	map[string]*PropertySchema{
+		"id": NewPropertySchema(
+			idType,
+			NewDisplayValue(
+				PointerTo("ID"),
+				PointerTo("Machine identifier for this function."),
+				nil,
+			),
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"inputs": NewPropertySchema(
+			NewListSchema(tupleItemType, nil, nil),
+			NewDisplayValue(
+				PointerTo("Inputs"),
+				PointerTo("Type definitions for the parameters of this function, in order."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"output": NewPropertySchema(
+			tupleItemType,
+			NewDisplayValue(
+				PointerTo("Output"),
+				PointerTo("Type definition for the return value of this function, if any."),
+				nil,
+			),
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+		"display": displayProperty,
+	},
+)
+var functionScopeSchema = NewScopeSchema(
+	functionSchemaObject,
+	append(
+		basicObjects,
+		scopeObject,
+	)...,
+)
 
 // DescribeScope returns a scope that describes the ScopeSchema itself.
 func DescribeScope() *ScopeSchema {
@@ -1329,5 +2273,6 @@ func UnserializeSchema(data any) (*SchemaSchema, error) {
 	}
 	result := s.(*SchemaSchema)
 	result.applyNamespace()
+	result.resolveUnits(DefaultUnitsRegistry)
 	return result, nil
 }
@@ -85,3 +85,115 @@ func TestStepOutputSchema(t *testing.T) {
 		t.Fatalf("Incorrect unserialized output: %s", unserializedStepOutputOutput.(map[any]any)["foo"])
 	}
 }
+
+func TestSchemaUnserializationNewerDisplayAndUnits(t *testing.T) {
+	step := schema.NewStepSchema(
+		"upload",
+		schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+			"size": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, schema.NewUnits(
+					schema.NewUnit("B", "B", "block", "blocks"),
+					map[int64]*schema.UnitDefinition{8: schema.NewUnit("sb", "sb", "superblock", "superblocks")},
+				)),
+				schema.NewDisplayValue(schema.PointerTo("Size"), nil, nil),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		})),
+		map[string]*schema.StepOutputSchema{},
+		nil,
+		nil,
+		nil,
+	)
+	selfSerialized := assert.NoErrorR[any](t)(
+		schema.NewSchema(map[string]*schema.StepSchema{"upload": step}).SelfSerialize(),
+	)
+	serialized := map[string]any{}
+	assert.NoError(t, yaml.Unmarshal(assert.NoErrorR[[]byte](t)(yaml.Marshal(selfSerialized)), &serialized))
+	property := serialized
+	for _, key := range []string{"steps", "upload", "input", "objects", "input", "properties", "size"} {
+		property = property[key].(map[string]any)
+	}
+	display := property["display"].(map[string]any)
+	units := property["type"].(map[string]any)["units"].(map[string]any)
+
+	// Version 1 is not serialized, so readers rejecting unknown fields can read it.
+	_, hasVersion := display["version"]
+	assert.Equals(t, hasVersion, false)
+	_, hasVersion = units["version"]
+	assert.Equals(t, hasVersion, false)
+
+	// A newer SDK may add a version marker and fields this version doesn't know.
+	display["version"] = 2
+	display["color"] = "blue"
+	units["version"] = 2
+	units["aliases"] = map[string]any{"bytes": []any{"octets"}}
+	units["base_unit"].(map[string]any)["symbol"] = "B"
+
+	unserialized := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(serialized))
+	size := unserialized.StepsValue["upload"].Input().Objects()["input"].Properties()["size"]
+	assert.Equals(t, *size.Display().Name(), "Size")
+	assert.Equals(t, size.Display().(*schema.DisplayValue).Version(), int64(2))
+	sizeUnits := size.Type().(*schema.IntSchema).Units()
+	assert.Equals(t, sizeUnits.Version(), int64(2))
+	assert.Equals(t, sizeUnits.BaseUnit().NameShortSingular(), "B")
+	assert.Equals(t, schema.UnitBytes.Version(), schema.UnitsFormatVersion)
+}
+
+func TestSchemaUnserializationOldPropertySet(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil),
+			schema.NewDisplayValue(schema.PointerTo("Name"), nil, nil),
+			false,
+			nil,
+			nil,
+			nil,
+			schema.PointerTo(`"test"`),
+			nil,
+		),
+		"tags": schema.NewPropertySchema(
+			schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		),
+	}))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+
+	// The property keys known before aliases, transforms, sensitive values and conditions were added. Properties that
+	// use none of these must be readable by engines that reject any other key.
+	propertySchema := schema.DescribeScope().Objects()["Property"]
+	oldProperties := map[string]*schema.PropertySchema{}
+	for _, propertyID := range []string{
+		"type",
+		"display",
+		"required",
+		"required_if_not",
+		"required_if",
+		"conflicts",
+		"default",
+		"examples",
+		"disabled",
+		"disabled_reason",
+	} {
+		oldProperties[propertyID] = propertySchema.Properties()[propertyID]
+	}
+	oldPropertySchema := schema.NewObjectSchema("Property", oldProperties)
+
+	properties := serialized.(map[string]any)["objects"].(map[any]any)["input"].(map[string]any)["properties"]
+	for propertyID, property := range properties.(map[any]any) {
+		_, err := oldPropertySchema.Unserialize(property)
+		if err != nil {
+			t.Fatalf("property %s cannot be read with the old property set: %v", propertyID, err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Package schemaarchive exports the complete schema of a plugin to a single compressed archive and imports it again, so
+// registries and air-gapped environments can review plugins without pulling their images.
+//
+// An archive is a gzip-compressed tar file holding a manifest.json, the schema.json, an examples/<step>.json with a
+// minimal input for each step, and documentation files under docs/. The manifest records the SHA-256 digest of every
+// other file and the hash of the schema, and Import rejects archives that don't match it. Exporting the same schema
+// twice produces the same bytes, and the digest of the manifest identifies the content of the archive.
+package schemaarchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// FormatVersion is the version of the archive format this package writes. Import rejects archives of newer versions.
+const FormatVersion = 1
+
+// MaxFileSize is the largest file Import reads from an archive.
+const MaxFileSize = 64 << 20
+
+const (
+	manifestFile   = "manifest.json"
+	schemaFile     = "schema.json"
+	examplesPrefix = "examples/"
+	docsPrefix     = "docs/"
+)
+
+// Metadata describes the plugin of an archive.
+type Metadata struct {
+	// Name is the name of the plugin.
+	Name string `json:"name"`
+	// Version is the version of the plugin, if known.
+	Version string `json:"version,omitempty"`
+	// Description is a short description of the plugin.
+	Description string `json:"description,omitempty"`
+	// Image is the container image the schema was taken from, if any.
+	Image string `json:"image,omitempty"`
+	// Labels holds additional information, such as the source repository or the license.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Archive is the content of an imported archive.
+type Archive struct {
+	Metadata Metadata
+	Schema   *schema.SchemaSchema
+	// Examples holds a minimal serialized input for each step ID, as generated by schema.GenerateExample.
+	Examples map[string]any
+	// Docs holds the documentation files by their name, such as README.md.
+	Docs map[string][]byte
+	// Digest is the SHA-256 digest of the manifest, which identifies the content of the archive.
+	Digest string
+}
+
+type manifest struct {
+	FormatVersion int      `json:"format_version"`
+	Metadata      Metadata `json:"metadata"`
+	// SchemaHash is the schema.HashSchema of the schema.
+	SchemaHash string `json:"schema_hash"`
+	// Files holds the SHA-256 digest of every file of the archive except the manifest.
+	Files map[string]string `json:"files"`
+}
+
+// Export writes the plugin schema, an example input for each step, and the documentation files to the writer as an
+// archive, and returns the digest of the archive. The names of the documentation files are relative paths, such as
+// README.md or steps/hello.md.
+func Export(
+	w io.Writer,
+	plugin interface{ SelfSerialize() (any, error) },
+	metadata Metadata,
+	docs map[string][]byte,
+) (string, error) {
+	serialized, err := plugin.SelfSerialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize plugin schema (%w)", err)
+	}
+	unserialized, err := schema.UnserializeSchema(serialized)
+	if err != nil {
+		return "", fmt.Errorf("failed to read back plugin schema (%w)", err)
+	}
+	schemaHash, err := schema.HashSchema(unserialized)
+	if err != nil {
+		return "", err
+	}
+	files := map[string][]byte{}
+	if files[schemaFile], err = schema.MarshalCanonical(serialized); err != nil {
+		return "", fmt.Errorf("failed to encode plugin schema (%w)", err)
+	}
+	for id, step := range unserialized.StepsValue {
+		example, err := schema.MarshalCanonical(schema.GenerateExample(step.Input()))
+		if err != nil {
+			return "", fmt.Errorf("failed to encode example input of step %s (%w)", id, err)
+		}
+		files[examplesPrefix+id+".json"] = example
+	}
+	for name, content := range docs {
+		if err := validateDocName(name); err != nil {
+			return "", err
+		}
+		files[docsPrefix+name] = content
+	}
+	m := manifest{
+		FormatVersion: FormatVersion,
+		Metadata:      metadata,
+		SchemaHash:    schemaHash,
+		Files:         make(map[string]string, len(files)),
+	}
+	for name, content := range files {
+		m.Files[name] = digest(content)
+	}
+	manifestData, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode archive manifest (%w)", err)
+	}
+	files[manifestFile] = manifestData
+	if err := writeArchive(w, files); err != nil {
+		return "", err
+	}
+	return digest(manifestData), nil
+}
+
+// Import reads an archive written by Export and verifies the digests of its files and the hash of its schema.
+func Import(r io.Reader) (*Archive, error) {
+	files, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	manifestData, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("archive has no %s", manifestFile)
+	}
+	m := manifest{}
+	if err := json.Unmarshal(manifestData, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode archive manifest (%w)", err)
+	}
+	if m.FormatVersion < 1 || m.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("unsupported archive format version %d", m.FormatVersion)
+	}
+	if err := verifyFiles(m, files); err != nil {
+		return nil, err
+	}
+	result := &Archive{
+		Metadata: m.Metadata,
+		Examples: map[string]any{},
+		Docs:     map[string][]byte{},
+		Digest:   digest(manifestData),
+	}
+	if result.Schema, err = decodeSchema(files[schemaFile], m.SchemaHash); err != nil {
+		return nil, err
+	}
+	for name, content := range files {
+		switch {
+		case strings.HasPrefix(name, examplesPrefix):
+			stepID := strings.TrimSuffix(strings.TrimPrefix(name, examplesPrefix), ".json")
+			if result.Examples[stepID], err = decodeJSON(content); err != nil {
+				return nil, fmt.Errorf("failed to decode example input of step %s (%w)", stepID, err)
+			}
+		case strings.HasPrefix(name, docsPrefix):
+			result.Docs[strings.TrimPrefix(name, docsPrefix)] = content
+		}
+	}
+	return result, nil
+}
+
+// verifyFiles checks that the archive holds exactly the files of the manifest with their digests.
+func verifyFiles(m manifest, files map[string][]byte) error {
+	if _, ok := m.Files[schemaFile]; !ok {
+		return fmt.Errorf("archive manifest does not list %s", schemaFile)
+	}
+	for name, expected := range m.Files {
+		content, ok := files[name]
+		if !ok {
+			return fmt.Errorf("archive file %s is missing", name)
+		}
+		if digest(content) != expected {
+			return fmt.Errorf("archive file %s does not match its digest", name)
+		}
+	}
+	for name := range files {
+		if _, ok := m.Files[name]; !ok && name != manifestFile {
+			return fmt.Errorf("archive file %s is not listed in the manifest", name)
+		}
+	}
+	return nil
+}
+
+func decodeSchema(data []byte, expectedHash string) (*schema.SchemaSchema, error) {
+	serialized, err := decodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode plugin schema (%w)", err)
+	}
+	result, err := schema.UnserializeSchema(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin schema (%w)", err)
+	}
+	schemaHash, err := schema.HashSchema(result)
+	if err != nil {
+		return nil, err
+	}
+	if schemaHash != expectedHash {
+		return nil, fmt.Errorf("plugin schema does not match its hash %s", expectedHash)
+	}
+	return result, nil
+}
+
+// decodeJSON decodes the JSON data, keeping numbers as json.Number so large integers are not rounded.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func validateDocName(name string) error {
+	if name == "" || path.IsAbs(name) || path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") {
+		return schema.BadArgumentError{Message: fmt.Sprintf("invalid documentation file name: %s", name)}
+	}
+	return nil
+}
+
+// writeArchive writes the files in the order of their names, with fixed modification times, so the same files always
+// produce the same archive.
+func writeArchive(w io.Writer, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range names {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(files[name])),
+			ModTime:  time.Unix(0, 0),
+			Format:   tar.FormatPAX,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write archive file %s (%w)", name, err)
+		}
+		if _, err := tarWriter.Write(files[name]); err != nil {
+			return fmt.Errorf("failed to write archive file %s (%w)", name, err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive (%w)", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive (%w)", err)
+	}
+	return nil
+}
+
+func readArchive(r io.Reader) (map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive (%w)", err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive (%w)", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("archive entry %s is not a regular file", header.Name)
+		}
+		if header.Size > MaxFileSize {
+			return nil, fmt.Errorf("archive file %s is larger than %d bytes", header.Name, MaxFileSize)
+		}
+		if _, ok := files[header.Name]; ok {
+			return nil, fmt.Errorf("archive file %s appears more than once", header.Name)
+		}
+		content, err := io.ReadAll(io.LimitReader(tarReader, MaxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive file %s (%w)", header.Name, err)
+		}
+		files[header.Name] = content
+	}
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,146 @@
+package schemaarchive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/schema/schemaarchive"
+)
+
+type helloInput struct {
+	Name    string `json:"name"`
+	Timeout int64  `json:"timeout"`
+}
+
+func helloPlugin() *schema.CallableSchema {
+	return schema.NewCallableSchema(schema.NewCallableStep[helloInput](
+		"hello",
+		schema.NewScopeSchema(schema.NewStructMappedObjectSchema[helloInput](
+			"input",
+			map[string]*schema.PropertySchema{
+				"name": schema.NewPropertySchema(
+					schema.NewStringSchema(schema.IntPointer(1), nil, nil), nil, true, nil, nil, nil, nil, nil,
+				),
+				"timeout": schema.NewPropertySchema(
+					schema.NewIntSchema(nil, schema.PointerTo(int64(1)<<60+1), schema.UnitDurationNanoseconds),
+					nil,
+					false,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+		)),
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+				nil,
+				false,
+			),
+		},
+		nil,
+		func(_ context.Context, _ helloInput) (string, any) {
+			return "success", map[string]any{}
+		},
+	))
+}
+
+func TestExportImport(t *testing.T) {
+	plugin := helloPlugin()
+	metadata := schemaarchive.Metadata{Name: "hello", Version: "1.0.0", Labels: map[string]string{"license": "Apache-2.0"}}
+	docs := map[string][]byte{"README.md": []byte("# Hello\n"), "steps/hello.md": []byte("Greets.\n")}
+	buf := &bytes.Buffer{}
+	digest := assert.NoErrorR[string](t)(schemaarchive.Export(buf, plugin, metadata, docs))
+
+	// Exports are reproducible.
+	again := &bytes.Buffer{}
+	assert.Equals(t, assert.NoErrorR[string](t)(schemaarchive.Export(again, plugin, metadata, docs)), digest)
+	assert.Equals(t, again.Bytes(), buf.Bytes())
+
+	archive := assert.NoErrorR[*schemaarchive.Archive](t)(schemaarchive.Import(buf))
+	assert.Equals(t, archive.Digest, digest)
+	assert.Equals(t, archive.Metadata, metadata)
+	assert.Equals(t, archive.Docs, docs)
+	assert.Equals(t, archive.Examples["hello"], any(map[string]any{"name": "a"}))
+	assert.Equals(
+		t,
+		assert.NoErrorR[string](t)(schema.HashSchema(archive.Schema)),
+		assert.NoErrorR[string](t)(schema.HashSchema(plugin)),
+	)
+	timeout := archive.Schema.StepsValue["hello"].Input().Properties()["timeout"].Type().(*schema.IntSchema)
+	assert.Equals(t, *timeout.Max(), int64(1)<<60+1)
+	assert.Equals(t, timeout.Units() == schema.UnitDurationNanoseconds, true)
+}
+
+func TestExportInvalidDocName(t *testing.T) {
+	for _, name := range []string{"", "/etc/passwd", "../README.md", "docs/../../x", "./README.md"} {
+		_, err := schemaarchive.Export(io.Discard, helloPlugin(), schemaarchive.Metadata{}, map[string][]byte{name: nil})
+		assert.Error(t, err)
+	}
+}
+
+// rewriteArchive rewrites the archive, changing its files with the function.
+func rewriteArchive(t *testing.T, archive []byte, change func(files map[string][]byte)) []byte {
+	gzipReader := assert.NoErrorR[*gzip.Reader](t)(gzip.NewReader(bytes.NewReader(archive)))
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		files[header.Name] = assert.NoErrorR[[]byte](t)(io.ReadAll(tarReader))
+	}
+	change(files)
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tarWriter.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func TestImportRejectsTampering(t *testing.T) {
+	buf := &bytes.Buffer{}
+	_, err := schemaarchive.Export(buf, helloPlugin(), schemaarchive.Metadata{Name: "hello"}, nil)
+	assert.NoError(t, err)
+	original := buf.Bytes()
+
+	for name, change := range map[string]func(files map[string][]byte){
+		"changed schema": func(files map[string][]byte) {
+			files["schema.json"] = bytes.Replace(files["schema.json"], []byte(`"hello"`), []byte(`"howdy"`), 1)
+		},
+		"missing example": func(files map[string][]byte) {
+			delete(files, "examples/hello.json")
+		},
+		"extra file": func(files map[string][]byte) {
+			files["docs/extra.md"] = []byte("extra")
+		},
+		"missing manifest": func(files map[string][]byte) {
+			delete(files, "manifest.json")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := schemaarchive.Import(bytes.NewReader(rewriteArchive(t, original, change)))
+			assert.Error(t, err)
+		})
+	}
+	_, err = schemaarchive.Import(bytes.NewReader(rewriteArchive(t, original, func(map[string][]byte) {})))
+	assert.NoError(t, err)
+	_, err = schemaarchive.Import(bytes.NewReader([]byte("not an archive")))
+	assert.Error(t, err)
+}
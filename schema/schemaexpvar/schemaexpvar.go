@@ -0,0 +1,18 @@
+// Package schemaexpvar publishes the memory statistics of schemas with expvar. It is separate from the schema package
+// because importing expvar registers the /debug/vars handler on http.DefaultServeMux.
+package schemaexpvar
+
+import (
+	"expvar"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Publish publishes the schema.SchemaStats of the steps returned by the function as an expvar variable. The function
+// is called each time the variable is read, so it can return the steps the service currently holds. Like
+// expvar.Publish, it panics if a variable with the same name already exists.
+func Publish[S schema.Step](name string, steps func() map[string]S) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return schema.MeasureSteps(steps())
+	}))
+}
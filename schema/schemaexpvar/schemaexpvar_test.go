@@ -0,0 +1,33 @@
+package schemaexpvar_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/schema/schemaexpvar"
+)
+
+func TestPublish(t *testing.T) {
+	steps := map[string]*schema.StepSchema{}
+	schemaexpvar.Publish("schemaexpvar_test", func() map[string]*schema.StepSchema {
+		return steps
+	})
+	var stats schema.SchemaStats
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("schemaexpvar_test").String()), &stats))
+	assert.Equals(t, stats.Steps, 0)
+
+	steps["test"] = schema.NewStepSchema(
+		"test",
+		schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{})),
+		map[string]*schema.StepOutputSchema{},
+		nil,
+		nil,
+		nil,
+	)
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("schemaexpvar_test").String()), &stats))
+	assert.Equals(t, stats.Steps, 1)
+	assert.Equals(t, stats.Objects, 1)
+}
@@ -29,8 +29,8 @@ func NewScopeSchema(rootObject *ObjectSchema, objects ...*ObjectSchema) *ScopeSc
 	}
 
 	schema := &ScopeSchema{
-		objectMap,
-		root,
+		ObjectsValue: objectMap,
+		RootValue:    root,
 	}
 
 	schema.ApplySelf()
@@ -41,14 +41,25 @@ func NewScopeSchema(rootObject *ObjectSchema, objects ...*ObjectSchema) *ScopeSc
 // NewScopeSchemaFromScope returns a new scope.
 func NewScopeSchemaFromScope(scope Scope) *ScopeSchema {
 	return &ScopeSchema{
-		scope.Objects(),
-		scope.Root(),
+		ObjectsValue: scope.Objects(),
+		RootValue:    scope.Root(),
 	}
 }
 
 type ScopeSchema struct {
 	ObjectsValue map[string]*ObjectSchema `json:"objects"`
 	RootValue    string                   `json:"root"`
+
+	coercionPolicy *CoercionPolicy
+	locked         bool
+}
+
+// WithCoercionPolicy is a builder-pattern way of restricting the conversions between data types when unserializing
+// data with the scope. The policy is not part of the serialized schema.
+func (s *ScopeSchema) WithCoercionPolicy(policy CoercionPolicy) *ScopeSchema {
+	s.checkNotLocked("set a coercion policy")
+	s.coercionPolicy = &policy
+	return s
 }
 
 func (s *ScopeSchema) SelfSerialize() (any, error) {
@@ -76,6 +87,9 @@ func (s *ScopeSchema) ReflectedType() reflect.Type {
 }
 
 func (s *ScopeSchema) Unserialize(data any) (any, error) {
+	if s.coercionPolicy != nil {
+		return UnserializeWithCoercion(s, data, *s.coercionPolicy)
+	}
 	return s.RootObject().Unserialize(data)
 }
 
@@ -88,6 +102,20 @@ func (s *ScopeSchema) ValidateCompatibility(typeOrData any) error {
 	return s.RootObject().ValidateCompatibility(typeOrData)
 }
 
+// ValidateCompatibilityParallel works like ValidateCompatibility, but when given a schema, the objects of the scope are
+// checked concurrently by up to the given number of workers. If there is more than one incompatibility, the one with
+// the lowest path is returned, so the result does not depend on scheduling. Data is always validated sequentially.
+func (s *ScopeSchema) ValidateCompatibilityParallel(typeOrData any, workers int) error {
+	if scope, ok := typeOrData.(*ScopeSchema); ok {
+		typeOrData = scope.RootObject()
+	}
+	schemaType, ok := ConvertToObjectSchema(typeOrData)
+	if !ok {
+		return s.RootObject().ValidateCompatibility(typeOrData)
+	}
+	return s.RootObject().validateSchemaCompatibilityParallel(schemaType, workers)
+}
+
 func (s *ScopeSchema) Validate(data any) error {
 	return s.RootObject().Validate(data)
 }
@@ -102,6 +130,7 @@ func (s *ScopeSchema) ApplySelf() {
 }
 
 func (s *ScopeSchema) ApplyNamespace(externalObjects map[string]*ObjectSchema, namespace string) {
+	s.checkNotLocked("apply a namespace")
 	// When the namespace is the default namespace, each scope should pass itself down.
 	var objectsToApply map[string]*ObjectSchema
 	if namespace == SelfNamespace {
@@ -109,8 +138,13 @@ func (s *ScopeSchema) ApplyNamespace(externalObjects map[string]*ObjectSchema, n
 	} else {
 		objectsToApply = externalObjects
 	}
+	cache := newScopeTypeCache(s.ObjectsValue)
 	for _, v := range s.ObjectsValue {
 		v.ApplyNamespace(objectsToApply, namespace)
+		if !v.locked {
+			// Locked objects shared with this scope keep the cache of the scope they were locked in.
+			v.compiled.scope = cache
+		}
 	}
 }
 
@@ -124,6 +158,10 @@ func (s *ScopeSchema) ValidateReferences() error {
 	return nil
 }
 
+func (s *ScopeSchema) ApplyDefaults(serialized any) any {
+	return s.RootObject().ApplyDefaults(serialized)
+}
+
 func (s *ScopeSchema) TypeID() TypeID {
 	return TypeIDScope
 }
@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// scopeTypeCache stores the reflection work for the properties of the objects of a scope, so unserializing the same
+// object types again and again doesn't resolve the same references and reflected types on every value. All objects of
+// the scope share the cache. It is built on first use and replaced whenever a namespace is applied to the scope, since
+// that changes what the references point to.
+type scopeTypeCache struct {
+	objects map[string]*ObjectSchema
+	value   atomic.Pointer[scopeTypes]
+}
+
+// scopeTypes holds the resolved information for each property of the objects of a scope. It is not modified after it
+// is built, so it can be read concurrently.
+type scopeTypes struct {
+	properties map[*PropertySchema]propertyTypeInfo
+}
+
+// propertyTypeInfo is the resolved information of a property that the object conversions need for every value.
+type propertyTypeInfo struct {
+	reflectedType reflect.Type
+	// subObject is the object whose defaults are applied if the property is not set, or nil if the property is not
+	// an object or unserializes to a pointer.
+	subObject Object
+}
+
+func newScopeTypeCache(objects map[string]*ObjectSchema) *scopeTypeCache {
+	return &scopeTypeCache{objects: objects}
+}
+
+// property returns the information of the property, building the cache if this is the first use. Properties that are
+// not part of the scope are resolved on every call.
+func (c *scopeTypeCache) property(property *PropertySchema) propertyTypeInfo {
+	types := c.value.Load()
+	if types == nil {
+		// Concurrent first uses may both build the cache, which is harmless since the results are the same.
+		types = newScopeTypes(c.objects)
+		c.value.Store(types)
+	}
+	if info, ok := types.properties[property]; ok {
+		return info
+	}
+	return newPropertyTypeInfo(property)
+}
+
+func newScopeTypes(objects map[string]*ObjectSchema) *scopeTypes {
+	types := &scopeTypes{properties: map[*PropertySchema]propertyTypeInfo{}}
+	for _, object := range objects {
+		for _, property := range object.PropertiesValue {
+			if ref, ok := property.TypeValue.(*RefSchema); ok && !ref.ObjectReady() {
+				// Unlinked references fail when they are used, which is left to the uncached conversion.
+				continue
+			}
+			types.properties[property] = newPropertyTypeInfo(property)
+		}
+	}
+	return types
+}
+
+func newPropertyTypeInfo(property *PropertySchema) propertyTypeInfo {
+	info := propertyTypeInfo{reflectedType: property.ReflectedType()}
+	if info.reflectedType.Kind() == reflect.Pointer {
+		return info
+	}
+	switch property.TypeID() {
+	case TypeIDRef:
+		info.subObject = property.Type().(Ref).GetObject()
+	case TypeIDObject:
+		info.subObject = property.Type().(Object)
+	}
+	return info
+}
+
+// propertyTypeInfo returns the resolved information of one of the properties of the object, from the cache of its
+// scope if it has one.
+func (o *ObjectSchema) propertyTypeInfo(property *PropertySchema) propertyTypeInfo {
+	if o.compiled.scope != nil {
+		return o.compiled.scope.property(property)
+	}
+	return newPropertyTypeInfo(property)
+}
@@ -0,0 +1,96 @@
+package schema_test
+
+import (
+	"sync"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type scopeCacheTestRoot struct {
+	Name    string               `json:"name"`
+	Labels  map[string]string    `json:"labels"`
+	Tags    []string             `json:"tags"`
+	Options scopeCacheTestOption `json:"options"`
+}
+
+type scopeCacheTestOption struct {
+	Retries int64 `json:"retries"`
+}
+
+func newScopeCacheTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewStructMappedObjectSchema[scopeCacheTestRoot]("root", map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"labels": schema.NewPropertySchema(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewStringSchema(nil, nil, nil), nil, nil),
+				nil, false, nil, nil, nil, nil, nil,
+			),
+			"tags": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil),
+				nil, false, nil, nil, nil, nil, nil,
+			),
+			"options": schema.NewPropertySchema(
+				schema.NewRefSchema("options", nil), nil, false, nil, nil, nil, nil, nil,
+			),
+		}),
+		schema.NewStructMappedObjectSchema[scopeCacheTestOption]("options", map[string]*schema.PropertySchema{
+			"retries": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, schema.PointerTo("3"), nil,
+			),
+		}),
+	)
+}
+
+func TestScopeCacheConcurrentUnserialize(t *testing.T) {
+	scope := newScopeCacheTestScope()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				result, err := scope.Unserialize(map[string]any{"name": "test"})
+				if err != nil {
+					t.Errorf("unserialization failed (%v)", err)
+					return
+				}
+				// The defaults of the referenced non-pointer object are filled in.
+				if retries := result.(scopeCacheTestRoot).Options.Retries; retries != 3 {
+					t.Errorf("expected 3 retries, got %d", retries)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestScopeCacheReapplied(t *testing.T) {
+	scope := newScopeCacheTestScope()
+	assert.NoErrorR[any](t)(scope.Unserialize(map[string]any{"name": "test"}))
+
+	// Applying the scope again after replacing the referenced object resets the cache.
+	scope.ObjectsValue["options"] = schema.NewStructMappedObjectSchema[scopeCacheTestOption](
+		"options",
+		map[string]*schema.PropertySchema{
+			"retries": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil), nil, false, nil, nil, nil, schema.PointerTo("5"), nil,
+			),
+		},
+	)
+	scope.ApplySelf()
+	result := assert.NoErrorR[any](t)(scope.Unserialize(map[string]any{"name": "test"}))
+	assert.Equals(t, result.(scopeCacheTestRoot).Options.Retries, int64(5))
+}
+
+func BenchmarkScopeCache(b *testing.B) {
+	scope := newScopeCacheTestScope()
+	data := map[string]any{"name": "test"}
+	for i := 0; i < b.N; i++ {
+		if _, err := scope.Unserialize(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
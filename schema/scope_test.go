@@ -2,7 +2,10 @@ package schema_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"go.arcalot.io/assert"
+	"strings"
 	"testing"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
@@ -572,3 +575,76 @@ func TestMismatchedRootID(t *testing.T) {
 		brokenSchema.RootObject()
 	}, "root object's ID \"a\" doesn't match its map key \"wrong\"")
 }
+
+func TestCompatibilityValidationParallel(t *testing.T) {
+	assert.NoError(t, scopeTestObjectAType.ValidateCompatibilityParallel(scopeTestObjectAType, 4))
+	assert.Error(t, scopeTestObjectAType.ValidateCompatibilityParallel(scopeTestObjectEmptySchema, 4))
+	assert.Error(t, scopeTestObjectEmptySchema.ValidateCompatibilityParallel(scopeTestObjectAType, 4))
+	assert.Error(t, scopeTestObjectEmptySchema.ValidateCompatibilityParallel(scopeTestObjectEmptySchemaRenamed, 4))
+	assert.Error(t, scopeTestObjectCStrSchema.ValidateCompatibilityParallel(scopeTestObjectCIntSchema, 4))
+	// Data is validated sequentially.
+	assert.NoError(t, scopeTestObjectEmptySchema.ValidateCompatibilityParallel(map[string]any{}, 4))
+}
+
+func TestCompatibilityValidationParallelErrorOrder(t *testing.T) {
+	newScope := func(typeFactory func() schema.Type) *schema.ScopeSchema {
+		properties := map[string]*schema.PropertySchema{}
+		for i := 0; i < 50; i++ {
+			properties[fmt.Sprintf("field%02d", i)] = schema.NewPropertySchema(
+				typeFactory(),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+		}
+		return schema.NewScopeSchema(schema.NewObjectSchema("test", properties))
+	}
+	stringScope := newScope(func() schema.Type { return schema.NewStringSchema(nil, nil, nil) })
+	intScope := newScope(func() schema.Type { return schema.NewIntSchema(nil, nil, nil) })
+
+	assert.NoError(t, stringScope.ValidateCompatibilityParallel(stringScope, 8))
+	expected := stringScope.ValidateCompatibilityParallel(intScope, 1)
+	assert.Error(t, expected)
+	assert.Contains(t, expected.Error(), "field00")
+	// Only the first incompatibility is returned, like ValidateCompatibility does.
+	assert.Equals(t, strings.Contains(expected.Error(), "field01"), false)
+	for i := 0; i < 10; i++ {
+		err := stringScope.ValidateCompatibilityParallel(intScope, 8)
+		assert.Equals(t, err.Error(), expected.Error())
+	}
+}
+
+func TestCompatibilityValidationParallelObjects(t *testing.T) {
+	newScope := func(innerType schema.Type) *schema.ScopeSchema {
+		return schema.NewScopeSchema(
+			schema.NewObjectSchema("outer", map[string]*schema.PropertySchema{
+				"inner": schema.NewPropertySchema(
+					schema.NewRefSchema("inner", nil),
+					nil,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			}),
+			schema.NewObjectSchema("inner", map[string]*schema.PropertySchema{
+				"value": schema.NewPropertySchema(innerType, nil, true, nil, nil, nil, nil, nil),
+			}),
+		)
+	}
+	stringScope := newScope(schema.NewStringSchema(nil, nil, nil))
+	intScope := newScope(schema.NewIntSchema(nil, nil, nil))
+
+	assert.NoError(t, stringScope.ValidateCompatibilityParallel(newScope(schema.NewStringSchema(nil, nil, nil)), 4))
+	err := stringScope.ValidateCompatibilityParallel(intScope, 4)
+	assert.Error(t, err)
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path, []string{"inner", "value"})
+}
@@ -0,0 +1,155 @@
+package schema
+
+// SerializeOptions selects what SelfSerializeWithOptions leaves out of a plugin schema: the documentation, which
+// engines that only validate data against the schema don't need, and for large plugins makes up most of the serialized
+// schema, and the items the engine can't use. Documentation tools should use the full schema from SelfSerialize.
+type SerializeOptions struct {
+	// OmitDisplay leaves out the display values of steps, outputs, signals and properties, which hold their names,
+	// descriptions and icons. The display values of enum values are emptied, since the values need one.
+	OmitDisplay bool
+	// OmitExamples leaves out the examples of properties.
+	OmitExamples bool
+	// Engine leaves out the steps and properties whose EngineRequirements the engine doesn't meet, so it is only
+	// offered what it can run. A property left out can't be referenced by the value conditions of other properties.
+	// Conditions that required a property unless the left out property had certain values make it required, the
+	// others are dropped.
+	Engine *EngineCapabilities
+}
+
+// CompactSerializeOptions returns the options that leave out all documentation, for schema exchanges between
+// machines.
+func CompactSerializeOptions() SerializeOptions {
+	return SerializeOptions{
+		OmitDisplay:  true,
+		OmitExamples: true,
+	}
+}
+
+// SelfSerializeWithOptions serializes the schema like SelfSerialize, leaving out the parts the options select.
+func (s CallableSchema) SelfSerializeWithOptions(options SerializeOptions) (any, error) {
+	serialized, err := s.SelfSerialize()
+	if err != nil {
+		return nil, err
+	}
+	return stripSerializedSchema(serialized, options)
+}
+
+// SelfSerializeWithOptions serializes the schema like SelfSerialize, leaving out the parts the options select.
+func (s SchemaSchema) SelfSerializeWithOptions(options SerializeOptions) (any, error) {
+	serialized, err := s.SelfSerialize()
+	if err != nil {
+		return nil, err
+	}
+	return stripSerializedSchema(serialized, options)
+}
+
+// stripSerializedSchema removes the parts the options select from a copy of the serialized schema. The copy is made by
+// unserializing it, so the schema it was serialized from is left as is.
+func stripSerializedSchema(serialized any, options SerializeOptions) (any, error) {
+	if !options.OmitDisplay && !options.OmitExamples && options.Engine == nil {
+		return serialized, nil
+	}
+	unserialized, err := schemaSchema.Unserialize(serialized)
+	if err != nil {
+		return nil, err
+	}
+	steps := unserialized.(*SchemaSchema).StepsValue
+	for stepID, step := range steps {
+		if options.Engine != nil && options.Engine.Supports(step.EngineRequirementsValue) != nil {
+			delete(steps, stepID)
+			continue
+		}
+		if options.OmitDisplay {
+			step.DisplayValue = nil
+		}
+		stripScope(step.InputValue, options)
+		for _, output := range step.OutputsValue {
+			if options.OmitDisplay {
+				output.DisplayValue = nil
+			}
+			stripScope(output.SchemaValue, options)
+		}
+		for _, signals := range []map[string]*SignalSchema{step.SignalHandlersValue, step.SignalEmittersValue} {
+			for _, signal := range signals {
+				if options.OmitDisplay {
+					signal.DisplayValue = nil
+				}
+				stripScope(signal.DataSchemaValue, options)
+			}
+		}
+	}
+	return schemaSchema.Serialize(unserialized)
+}
+
+func stripScope(scope Scope, options SerializeOptions) {
+	for _, object := range scope.Objects() {
+		if options.Engine != nil {
+			stripUnsupportedProperties(object, *options.Engine)
+		}
+		for _, property := range object.PropertiesValue {
+			if options.OmitDisplay {
+				property.DisplayValue = nil
+			}
+			if options.OmitExamples {
+				property.ExamplesValue = nil
+			}
+			stripType(property.TypeValue, options)
+		}
+	}
+}
+
+// stripUnsupportedProperties removes the properties whose requirements the engine doesn't meet, and the value
+// conditions of the remaining properties that reference them.
+func stripUnsupportedProperties(object *ObjectSchema, engine EngineCapabilities) {
+	removed := map[string]bool{}
+	for propertyID, property := range object.PropertiesValue {
+		if engine.Supports(property.EngineRequirementsValue) != nil {
+			removed[propertyID] = true
+			delete(object.PropertiesValue, propertyID)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	for _, property := range object.PropertiesValue {
+		conditions := property.RequiredIfConditionsValue[:0]
+		for _, condition := range property.RequiredIfConditionsValue {
+			switch {
+			case !removed[condition.PropertyID]:
+				conditions = append(conditions, condition)
+			case condition.Negate:
+				// The removed property is never set, so it never has one of the values.
+				property.RequiredValue = true
+			}
+		}
+		property.RequiredIfConditionsValue = conditions
+	}
+}
+
+// stripType strips the enums and nested scopes in the type. Objects are stripped through the scope defining them.
+func stripType(t Type, options SerializeOptions) {
+	_ = Walk(t, func(_ []string, t Type) error {
+		switch typed := t.(type) {
+		case Scope:
+			stripScope(typed, options)
+			return SkipChildren
+		case *ObjectSchema, *RefSchema:
+			return SkipChildren
+		case *StringEnumSchema:
+			if options.OmitDisplay {
+				stripEnumDisplay(typed.ValidValuesMap)
+			}
+		case *IntEnumSchema:
+			if options.OmitDisplay {
+				stripEnumDisplay(typed.ValidValuesMap)
+			}
+		}
+		return nil
+	})
+}
+
+func stripEnumDisplay[T int64 | string](values map[T]*DisplayValue) {
+	for value := range values {
+		values[value] = &DisplayValue{}
+	}
+}
@@ -0,0 +1,83 @@
+package schema_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type serializeOptionsTestInput struct {
+	Mode string `json:"mode"`
+}
+
+func serializeOptionsTestSchema() *schema.CallableSchema {
+	display := schema.NewDisplayValue(schema.PointerTo("Name"), schema.PointerTo("A long description."), nil)
+	return schema.NewCallableSchema(schema.NewCallableStep[serializeOptionsTestInput](
+		"step",
+		schema.NewScopeSchema(schema.NewStructMappedObjectSchema[serializeOptionsTestInput](
+			"input",
+			map[string]*schema.PropertySchema{
+				"mode": schema.NewPropertySchema(
+					schema.NewStringEnumSchema(map[string]*schema.DisplayValue{"fast": display, "safe": display}),
+					display,
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					[]string{`"fast"`},
+				),
+			},
+		)),
+		map[string]*schema.StepOutputSchema{
+			"success": schema.NewStepOutputSchema(
+				schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+				display,
+				false,
+			),
+		},
+		display,
+		func(_ context.Context, _ serializeOptionsTestInput) (string, any) {
+			return "success", map[string]any{}
+		},
+	))
+}
+
+func TestSelfSerializeWithOptions(t *testing.T) {
+	callable := serializeOptionsTestSchema()
+	full := assert.NoErrorR[any](t)(callable.SelfSerialize())
+	compact := assert.NoErrorR[any](t)(callable.SelfSerializeWithOptions(schema.CompactSerializeOptions()))
+
+	encodedFull := assert.NoErrorR[[]byte](t)(json.Marshal(full))
+	encodedCompact := assert.NoErrorR[[]byte](t)(json.Marshal(compact))
+	assert.Equals(t, len(encodedCompact) < len(encodedFull), true)
+	assert.Contains(t, string(encodedFull), "A long description.")
+	for _, removed := range []string{"A long description.", `\"fast\"`, `"display"`} {
+		assert.Equals(t, strings.Contains(string(encodedCompact), removed), false)
+	}
+
+	// The compact schema is still a valid schema that validates the same data.
+	unserialized := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(compact))
+	input := unserialized.StepsValue["step"].InputValue
+	assert.NoErrorR[any](t)(input.Unserialize(map[string]any{"mode": "safe"}))
+	_, err := input.Unserialize(map[string]any{"mode": "slow"})
+	assert.Error(t, err)
+
+	// The original schema is left as is.
+	assert.Equals(t, assert.NoErrorR[any](t)(callable.SelfSerialize()), full)
+}
+
+func TestSelfSerializeWithOptionsExamplesOnly(t *testing.T) {
+	callable := serializeOptionsTestSchema()
+	serialized := assert.NoErrorR[any](t)(callable.SelfSerializeWithOptions(schema.SerializeOptions{OmitExamples: true}))
+	encoded := string(assert.NoErrorR[[]byte](t)(json.Marshal(serialized)))
+	assert.Equals(t, strings.Contains(encoded, `\"fast\"`), false)
+	assert.Equals(t, strings.Contains(encoded, "A long description."), true)
+
+	none := assert.NoErrorR[any](t)(callable.SelfSerializeWithOptions(schema.SerializeOptions{}))
+	assert.Equals(t, none, assert.NoErrorR[any](t)(callable.SelfSerialize()))
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"context"
+	"fmt"
 )
 
 // Signal holds the definition for a single signal. This is universal for emitted or received.
@@ -111,3 +112,22 @@ func (s CallableSignalSchema[StepData, InputType]) Call(ctx context.Context, ste
 	s.handler(ctx, stepData.(StepData), input.(InputType))
 	return nil
 }
+
+type signalEmitterContextKey struct{}
+
+// ContextWithSignalEmitter returns a copy of the context that lets the step it is passed to emit signals declared in
+// its signal emitters. The emitter is called with the signal ID and the unserialized signal data. The ATP server uses
+// it to send the signals to the client.
+func ContextWithSignalEmitter(ctx context.Context, emitter func(signalID string, data any) error) context.Context {
+	return context.WithValue(ctx, signalEmitterContextKey{}, emitter)
+}
+
+// EmitSignal emits a signal from the step the context belongs to. It returns an error if the context has no signal
+// emitter, for example because the step is not called through an ATP server.
+func EmitSignal(ctx context.Context, signalID string, data any) error {
+	emitter, ok := ctx.Value(signalEmitterContextKey{}).(func(signalID string, data any) error)
+	if !ok {
+		return fmt.Errorf("cannot emit signal %s, the step context has no signal emitter", signalID)
+	}
+	return emitter(signalID, data)
+}
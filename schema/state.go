@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"fmt"
+	"sync"
+)
+
+// StateBackend stores the serialized values of a StateStore. Implementations must be safe for concurrent use, which
+// schematest.TestStateBackendCompliance checks along with the rest of the expected behavior.
+type StateBackend interface {
+	// LoadState returns the serialized value of the key. The second return value is false if the key is not set.
+	LoadState(key string) (serialized any, found bool, err error)
+	// StoreState sets the serialized value of the key.
+	StoreState(key string, serialized any) error
+	// DeleteState removes the key. Removing a key that is not set is not an error.
+	DeleteState(key string) error
+}
+
+// NewStateStore creates a state store with a schema for each key it accepts. If the backend is nil, the values are
+// kept in memory for the lifetime of the plugin.
+func NewStateStore(keys map[string]Type, backend StateBackend) *StateStore {
+	if backend == nil {
+		backend = NewMemoryStateBackend()
+	}
+	return &StateStore{
+		keys:    keys,
+		backend: backend,
+	}
+}
+
+// StateStore is a key-value store the steps of a plugin can use to pass state that is not part of their outputs, such
+// as handles or tokens, to each other. Each key has a declared schema: values are validated and serialized when they
+// are set and unserialized when they are read, so they can be persisted by the backend.
+type StateStore struct {
+	keys    map[string]Type
+	backend StateBackend
+}
+
+// Keys returns the declared keys and their schemas.
+func (s *StateStore) Keys() map[string]Type {
+	return s.keys
+}
+
+// Get returns the unserialized value of the key. The second return value is false if the key is not set.
+func (s *StateStore) Get(key string) (any, bool, error) {
+	keyType, err := s.keyType(key)
+	if err != nil {
+		return nil, false, err
+	}
+	serialized, found, err := s.backend.LoadState(key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	value, err := keyType.Unserialize(serialized)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid stored value for state key %s (%w)", key, err)
+	}
+	return value, true, nil
+}
+
+// Set validates and serializes the value with the schema of the key and stores it.
+func (s *StateStore) Set(key string, value any) error {
+	keyType, err := s.keyType(key)
+	if err != nil {
+		return err
+	}
+	serialized, err := keyType.Serialize(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for state key %s (%w)", key, err)
+	}
+	return s.backend.StoreState(key, serialized)
+}
+
+// Delete removes the value of the key.
+func (s *StateStore) Delete(key string) error {
+	if _, err := s.keyType(key); err != nil {
+		return err
+	}
+	return s.backend.DeleteState(key)
+}
+
+func (s *StateStore) keyType(key string) (Type, error) {
+	keyType, ok := s.keys[key]
+	if !ok {
+		return nil, BadArgumentError{
+			Message: fmt.Sprintf("undeclared state key: %s", key),
+		}
+	}
+	return keyType, nil
+}
+
+// GetState returns the value of the key like StateStore.Get, typed to the type its schema unserializes to.
+func GetState[T any](s *StateStore, key string) (T, bool, error) {
+	var result T
+	value, found, err := s.Get(key)
+	if err != nil || !found {
+		return result, false, err
+	}
+	result, ok := value.(T)
+	if !ok {
+		return result, false, BadArgumentError{
+			Message: fmt.Sprintf("state key %s unserializes to %T, not %T", key, value, result),
+		}
+	}
+	return result, true, nil
+}
+
+// NewMemoryStateBackend creates a state backend that keeps the values in memory.
+func NewMemoryStateBackend() StateBackend {
+	return &memoryStateBackend{
+		values: map[string]any{},
+	}
+}
+
+type memoryStateBackend struct {
+	lock   sync.Mutex
+	values map[string]any
+}
+
+func (m *memoryStateBackend) LoadState(key string) (any, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	value, found := m.values[key]
+	return value, found, nil
+}
+
+func (m *memoryStateBackend) StoreState(key string, serialized any) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.values[key] = serialized
+	return nil
+}
+
+func (m *memoryStateBackend) DeleteState(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.values, key)
+	return nil
+}
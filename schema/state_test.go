@@ -0,0 +1,69 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type stateTestSession struct {
+	Token string `json:"token"`
+}
+
+var stateTestKeys = map[string]schema.Type{
+	"session": schema.NewStructMappedObjectSchema[stateTestSession](
+		"session",
+		map[string]*schema.PropertySchema{
+			"token": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+	"counter": schema.NewIntSchema(nil, nil, nil),
+}
+
+func TestStateStore(t *testing.T) {
+	store := schema.NewStateStore(stateTestKeys, nil)
+
+	_, found, err := store.Get("session")
+	assert.NoError(t, err)
+	assert.Equals(t, found, false)
+
+	assert.NoError(t, store.Set("session", stateTestSession{Token: "abc"}))
+	session, found, err := schema.GetState[stateTestSession](store, "session")
+	assert.NoError(t, err)
+	assert.Equals(t, found, true)
+	assert.Equals(t, session.Token, "abc")
+
+	assert.NoError(t, store.Set("counter", int64(3)))
+	counter, found, err := schema.GetState[int64](store, "counter")
+	assert.NoError(t, err)
+	assert.Equals(t, found, true)
+	assert.Equals(t, counter, int64(3))
+
+	assert.NoError(t, store.Delete("session"))
+	_, found, err = store.Get("session")
+	assert.NoError(t, err)
+	assert.Equals(t, found, false)
+}
+
+func TestStateStoreInvalid(t *testing.T) {
+	store := schema.NewStateStore(stateTestKeys, nil)
+	assert.Error(t, store.Set("session", stateTestSession{Token: ""}))
+	assert.Error(t, store.Set("undeclared", "x"))
+	_, _, err := store.Get("undeclared")
+	assert.Error(t, err)
+	assert.Error(t, store.Delete("undeclared"))
+
+	assert.NoError(t, store.Set("counter", int64(3)))
+	_, _, err = schema.GetState[string](store, "counter")
+	assert.Error(t, err)
+}
@@ -0,0 +1,320 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Object IDs of the statistics objects. Scopes that include them with NewStatisticsObjects or NewHistogramObjects
+// must not use these IDs for their own objects.
+const (
+	StatisticsObjectID      = "Statistics"
+	PercentileObjectID      = "Percentile"
+	HistogramObjectID       = "Histogram"
+	HistogramBucketObjectID = "HistogramBucket"
+)
+
+// Statistics summarizes a set of samples, such as the results of a benchmark, in a format downstream steps can
+// analyze without knowing the plugin that produced it. ComputeStatistics creates it from the samples.
+type Statistics struct {
+	// Count is the number of samples.
+	Count int64   `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	// StdDev is the population standard deviation of the samples.
+	StdDev float64 `json:"stddev"`
+	// Percentiles holds the requested percentiles in the order they were requested.
+	Percentiles []Percentile `json:"percentiles"`
+}
+
+// Percentile is the value below which the given percentage of the samples fall.
+type Percentile struct {
+	// Percentile is the percentage between 0 and 100.
+	Percentile float64 `json:"percentile"`
+	Value      float64 `json:"value"`
+}
+
+// ComputeStatistics returns the statistics of the samples with the given percentiles (0-100). Percentiles are
+// interpolated linearly between the closest samples. All statistics are 0 if there are no samples. It panics with a
+// BadArgumentError if a percentile is out of range.
+func ComputeStatistics(samples []float64, percentiles ...float64) Statistics {
+	for _, percentile := range percentiles {
+		checkPercentile(percentile)
+	}
+	result := Statistics{
+		Count:       int64(len(samples)),
+		Percentiles: make([]Percentile, len(percentiles)),
+	}
+	for i, percentile := range percentiles {
+		result.Percentiles[i].Percentile = percentile
+	}
+	if len(samples) == 0 {
+		return result
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	result.Min = sorted[0]
+	result.Max = sorted[len(sorted)-1]
+	var sum float64
+	for _, sample := range sorted {
+		sum += sample
+	}
+	result.Mean = sum / float64(len(sorted))
+	var squares float64
+	for _, sample := range sorted {
+		squares += (sample - result.Mean) * (sample - result.Mean)
+	}
+	result.StdDev = math.Sqrt(squares / float64(len(sorted)))
+	for i, percentile := range percentiles {
+		rank := percentile / 100 * float64(len(sorted)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		result.Percentiles[i].Value = sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	}
+	return result
+}
+
+// Histogram counts samples in buckets with fixed upper bounds, for distributions with too many samples to report
+// individually. NewHistogram creates it, and LinearBuckets and ExponentialBuckets create commonly used bounds. It is
+// not safe for concurrent use.
+type Histogram struct {
+	// Buckets holds the buckets in ascending order of their upper bounds, including the empty ones.
+	Buckets []HistogramBucket `json:"buckets"`
+	// Overflow is the number of samples higher than the upper bound of the last bucket.
+	Overflow int64 `json:"overflow"`
+	// Count is the number of samples.
+	Count int64 `json:"count"`
+	// Sum is the sum of all samples.
+	Sum float64 `json:"sum"`
+	// Min is the lowest sample, or 0 if there are no samples.
+	Min float64 `json:"min"`
+	// Max is the highest sample, or 0 if there are no samples.
+	Max float64 `json:"max"`
+}
+
+// HistogramBucket is a single bucket of a Histogram.
+type HistogramBucket struct {
+	// UpperBound is the inclusive upper bound of the samples counted in the bucket. The lower bound is the upper bound
+	// of the previous bucket.
+	UpperBound float64 `json:"upper_bound"`
+	// Count is the number of samples in the bucket.
+	Count int64 `json:"count"`
+}
+
+// NewHistogram creates an empty histogram with the given upper bounds. It panics with a BadArgumentError if the
+// bounds are not in strictly ascending order.
+func NewHistogram(upperBounds ...float64) *Histogram {
+	if len(upperBounds) == 0 {
+		panic(BadArgumentError{Message: "a histogram needs at least one bucket"})
+	}
+	buckets := make([]HistogramBucket, len(upperBounds))
+	for i, bound := range upperBounds {
+		if math.IsNaN(bound) || math.IsInf(bound, 0) || (i > 0 && bound <= upperBounds[i-1]) {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf("invalid histogram bounds %v, they must be finite and strictly ascending", upperBounds),
+			})
+		}
+		buckets[i].UpperBound = bound
+	}
+	return &Histogram{Buckets: buckets}
+}
+
+// LinearBuckets returns count upper bounds, starting at start and each width apart.
+func LinearBuckets(start float64, width float64, count int) []float64 {
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start + float64(i)*width
+	}
+	return bounds
+}
+
+// ExponentialBuckets returns count upper bounds, starting at start and each factor times the previous one.
+func ExponentialBuckets(start float64, factor float64, count int) []float64 {
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start * math.Pow(factor, float64(i))
+	}
+	return bounds
+}
+
+// Record adds the samples to the histogram.
+func (h *Histogram) Record(samples ...float64) {
+	for _, sample := range samples {
+		index := sort.Search(len(h.Buckets), func(i int) bool {
+			return sample <= h.Buckets[i].UpperBound
+		})
+		if index == len(h.Buckets) {
+			h.Overflow++
+		} else {
+			h.Buckets[index].Count++
+		}
+		if h.Count == 0 || sample < h.Min {
+			h.Min = sample
+		}
+		if h.Count == 0 || sample > h.Max {
+			h.Max = sample
+		}
+		h.Count++
+		h.Sum += sample
+	}
+}
+
+// Mean returns the average of the samples, or 0 if there are no samples.
+func (h *Histogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / float64(h.Count)
+}
+
+// Percentile returns an estimate of the value below which the given percentage (0-100) of the samples fall. The
+// estimate is interpolated linearly within the bucket containing the percentile, and limited to the lowest and highest
+// sample. It panics with a BadArgumentError if the percentile is out of range.
+func (h *Histogram) Percentile(percentile float64) float64 {
+	checkPercentile(percentile)
+	if h.Count == 0 {
+		return 0
+	}
+	rank := percentile / 100 * float64(h.Count)
+	var seen int64
+	for i, bucket := range h.Buckets {
+		if bucket.Count == 0 || float64(seen+bucket.Count) < rank {
+			seen += bucket.Count
+			continue
+		}
+		lower := h.Min
+		if i > 0 {
+			lower = max(h.Buckets[i-1].UpperBound, h.Min)
+		}
+		upper := min(bucket.UpperBound, h.Max)
+		return lower + (upper-lower)*(rank-float64(seen))/float64(bucket.Count)
+	}
+	return h.Max
+}
+
+func checkPercentile(percentile float64) {
+	if !(percentile >= 0 && percentile <= 100) {
+		panic(BadArgumentError{Message: fmt.Sprintf("invalid percentile %v, it must be between 0 and 100", percentile)})
+	}
+}
+
+// NewStatisticsObjects returns new objects describing Statistics, so plugins can include them in the scopes of their
+// outputs and refer to them with NewRefSchema(StatisticsObjectID, nil):
+//
+//	schema.NewScopeSchema(outputObject, schema.NewStatisticsObjects()...)
+func NewStatisticsObjects() []*ObjectSchema {
+	return []*ObjectSchema{
+		NewStructMappedObjectSchema[Statistics](
+			StatisticsObjectID,
+			map[string]*PropertySchema{
+				"count": newStatisticsProperty(
+					NewIntSchema(IntPointer(0), nil, nil), "Count", "Number of samples.",
+				),
+				"min": newStatisticsProperty(NewFloatSchema(nil, nil, nil), "Minimum", "Lowest sample."),
+				"max": newStatisticsProperty(NewFloatSchema(nil, nil, nil), "Maximum", "Highest sample."),
+				"mean": newStatisticsProperty(
+					NewFloatSchema(nil, nil, nil), "Mean", "Average of the samples.",
+				),
+				"stddev": newStatisticsProperty(
+					NewFloatSchema(PointerTo(0.0), nil, nil),
+					"Standard deviation",
+					"Population standard deviation of the samples.",
+				),
+				"percentiles": newStatisticsProperty(
+					NewListSchema(NewRefSchema(PercentileObjectID, nil), nil, nil),
+					"Percentiles",
+					"Values below which the given percentages of the samples fall.",
+				),
+			},
+		),
+		NewStructMappedObjectSchema[Percentile](
+			PercentileObjectID,
+			map[string]*PropertySchema{
+				"percentile": newStatisticsProperty(
+					NewFloatSchema(PointerTo(0.0), PointerTo(100.0), nil),
+					"Percentile",
+					"Percentage of the samples between 0 and 100.",
+				),
+				"value": newStatisticsProperty(
+					NewFloatSchema(nil, nil, nil), "Value", "Value below which the percentage of the samples fall.",
+				),
+			},
+		),
+	}
+}
+
+// NewHistogramObjects returns new objects describing a Histogram, so plugins can include them in the scopes of their
+// outputs and refer to them with NewRefSchema(HistogramObjectID, nil), like NewStatisticsObjects.
+func NewHistogramObjects() []*ObjectSchema {
+	return []*ObjectSchema{
+		NewStructMappedObjectSchema[*Histogram](
+			HistogramObjectID,
+			map[string]*PropertySchema{
+				"buckets": newStatisticsProperty(
+					NewListSchema(NewRefSchema(HistogramBucketObjectID, nil), IntPointer(1), nil),
+					"Buckets",
+					"Buckets in ascending order of their upper bounds.",
+				),
+				"overflow": newStatisticsProperty(
+					NewIntSchema(IntPointer(0), nil, nil),
+					"Overflow",
+					"Number of samples higher than the upper bound of the last bucket.",
+				),
+				"count": newStatisticsProperty(NewIntSchema(IntPointer(0), nil, nil), "Count", "Number of samples."),
+				"sum":   newStatisticsProperty(NewFloatSchema(nil, nil, nil), "Sum", "Sum of all samples."),
+				"min":   newStatisticsProperty(NewFloatSchema(nil, nil, nil), "Minimum", "Lowest sample."),
+				"max":   newStatisticsProperty(NewFloatSchema(nil, nil, nil), "Maximum", "Highest sample."),
+			},
+		),
+		NewStructMappedObjectSchema[HistogramBucket](
+			HistogramBucketObjectID,
+			map[string]*PropertySchema{
+				"upper_bound": newStatisticsProperty(
+					NewFloatSchema(nil, nil, nil),
+					"Upper bound",
+					"Inclusive upper bound of the samples counted in the bucket.",
+				),
+				"count": newStatisticsProperty(
+					NewIntSchema(IntPointer(0), nil, nil), "Count", "Number of samples in the bucket.",
+				),
+			},
+		),
+	}
+}
+
+func newStatisticsProperty(t Type, name string, description string) *PropertySchema {
+	return NewPropertySchema(
+		t,
+		NewDisplayValue(PointerTo(name), PointerTo(description), nil),
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+var statisticsSchema = func() *ScopeSchema {
+	objects := NewStatisticsObjects()
+	return NewScopeSchema(objects[0], objects[1:]...)
+}()
+
+var histogramSchema = func() *ScopeSchema {
+	objects := NewHistogramObjects()
+	return NewScopeSchema(objects[0], objects[1:]...)
+}()
+
+// DescribeStatistics returns a scope that describes Statistics, so downstream steps can validate and unserialize the
+// statistics they analyze.
+func DescribeStatistics() *ScopeSchema {
+	return statisticsSchema
+}
+
+// DescribeHistogram returns a scope that describes a Histogram, so downstream steps can validate and unserialize the
+// histograms they analyze.
+func DescribeHistogram() *ScopeSchema {
+	return histogramSchema
+}
@@ -0,0 +1,82 @@
+package schema_test
+
+import (
+	"math"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestComputeStatistics(t *testing.T) {
+	statistics := schema.ComputeStatistics([]float64{4, 1, 3, 2, 5}, 50, 90, 100)
+	assert.Equals(t, statistics.Count, int64(5))
+	assert.Equals(t, statistics.Min, 1.0)
+	assert.Equals(t, statistics.Max, 5.0)
+	assert.Equals(t, statistics.Mean, 3.0)
+	assert.Equals(t, math.Abs(statistics.StdDev-math.Sqrt2) < 1e-9, true)
+	assert.Equals(t, statistics.Percentiles, []schema.Percentile{
+		{Percentile: 50, Value: 3},
+		{Percentile: 90, Value: 4.6},
+		{Percentile: 100, Value: 5},
+	})
+
+	empty := schema.ComputeStatistics(nil, 50)
+	assert.Equals(t, empty, schema.Statistics{Percentiles: []schema.Percentile{{Percentile: 50}}})
+
+	assert.PanicsContains(t, func() {
+		schema.ComputeStatistics(nil, 101)
+	}, "invalid percentile 101")
+}
+
+func TestHistogram(t *testing.T) {
+	assert.Equals(t, schema.LinearBuckets(10, 10, 3), []float64{10, 20, 30})
+	assert.Equals(t, schema.ExponentialBuckets(1, 2, 4), []float64{1, 2, 4, 8})
+
+	histogram := schema.NewHistogram(schema.LinearBuckets(10, 10, 3)...)
+	histogram.Record(5, 10, 15, 25, 100)
+	assert.Equals(t, histogram.Buckets, []schema.HistogramBucket{
+		{UpperBound: 10, Count: 2},
+		{UpperBound: 20, Count: 1},
+		{UpperBound: 30, Count: 1},
+	})
+	assert.Equals(t, histogram.Overflow, int64(1))
+	assert.Equals(t, histogram.Count, int64(5))
+	assert.Equals(t, histogram.Mean(), 31.0)
+	assert.Equals(t, histogram.Percentile(0), 5.0)
+	assert.Equals(t, histogram.Percentile(40), 10.0)
+	assert.Equals(t, histogram.Percentile(50), 15.0)
+	assert.Equals(t, histogram.Percentile(100), 100.0)
+
+	assert.PanicsContains(t, func() {
+		schema.NewHistogram(2, 1)
+	}, "strictly ascending")
+}
+
+func TestStatisticsSchema(t *testing.T) {
+	statistics := schema.ComputeStatistics([]float64{1, 2}, 50)
+	serialized := assert.NoErrorR[any](t)(schema.DescribeStatistics().Serialize(statistics))
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeStatistics().Unserialize(serialized))
+	assert.Equals(t, unserialized.(schema.Statistics), statistics)
+
+	histogram := schema.NewHistogram(1, 2)
+	histogram.Record(1.5)
+	serialized = assert.NoErrorR[any](t)(schema.DescribeHistogram().Serialize(histogram))
+	unserialized = assert.NoErrorR[any](t)(schema.DescribeHistogram().Unserialize(serialized))
+	assert.Equals(t, unserialized.(*schema.Histogram), histogram)
+
+	// The objects can be included in the scopes of plugin outputs.
+	type benchmarkOutput struct {
+		Latency schema.Statistics `json:"latency"`
+	}
+	output := schema.NewScopeSchema(
+		schema.NewStructMappedObjectSchema[benchmarkOutput]("output", map[string]*schema.PropertySchema{
+			"latency": schema.NewPropertySchema(
+				schema.NewRefSchema(schema.StatisticsObjectID, nil), nil, true, nil, nil, nil, nil, nil,
+			),
+		}),
+		schema.NewStatisticsObjects()...,
+	)
+	assert.NoError(t, output.ValidateReferences())
+	assert.NoErrorR[any](t)(output.Serialize(benchmarkOutput{statistics}))
+}
@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"reflect"
+)
+
+// SchemaStats describes the size of schemas in memory, to help diagnose memory growth in long-lived services holding
+// many plugin schemas. Objects and scopes that are shared, for example by a SchemaPool, are counted once.
+type SchemaStats struct {
+	// Steps is the number of steps.
+	Steps int `json:"steps"`
+	// Scopes is the number of distinct scopes.
+	Scopes int `json:"scopes"`
+	// Objects is the number of distinct object schemas.
+	Objects int `json:"objects"`
+	// Properties is the number of properties of the distinct objects.
+	Properties int `json:"properties"`
+	// Types is the number of types in the schemas, counting the types of shared objects once.
+	Types int `json:"types"`
+	// StructMappedObjects is the number of objects that unserialize to structs and cache their reflected fields.
+	StructMappedObjects int `json:"struct_mapped_objects"`
+	// FieldCacheEntries is the number of struct fields cached by the struct-mapped objects.
+	FieldCacheEntries int `json:"field_cache_entries"`
+	// DecodePlans is the number of objects using a decode plan.
+	DecodePlans int `json:"decode_plans"`
+	// PropertyIndexes is the number of objects using a property index.
+	PropertyIndexes int `json:"property_indexes"`
+	// CompiledObjects is the number of objects whose conversions were precompiled with Compile.
+	CompiledObjects int `json:"compiled_objects"`
+	// UnitRegexes is the number of unit definitions that compiled their parsing regex.
+	UnitRegexes int `json:"unit_regexes"`
+	// EstimatedBytes is an estimate of the memory the schemas hold, including caches. Memory shared between the
+	// schemas is counted once. Reflected Go types and handler functions are not counted.
+	EstimatedBytes int64 `json:"estimated_bytes"`
+}
+
+// MeasureSteps returns the memory statistics of the steps, such as the StepsValue of a CallableSchema or the steps of
+// a FrozenSchema. Measuring walks all types of the steps, so it should not be called on hot paths.
+func MeasureSteps[S Step](steps map[string]S) SchemaStats {
+	m := newSchemaMeasurer()
+	for _, step := range steps {
+		m.stats.Steps++
+		m.measureScope(step.Input())
+		for _, output := range step.Outputs() {
+			m.measureScope(output.Schema())
+		}
+		for _, signal := range step.SignalHandlers() {
+			m.measureScope(signal.DataSchema())
+		}
+		for _, signal := range step.SignalEmitters() {
+			m.measureScope(signal.DataSchema())
+		}
+	}
+	return m.stats
+}
+
+// MeasureType returns the memory statistics of a single type, such as a scope.
+func MeasureType(t Type) SchemaStats {
+	m := newSchemaMeasurer()
+	m.measureScope(t)
+	return m.stats
+}
+
+type schemaMeasurer struct {
+	stats   SchemaStats
+	objects map[*ObjectSchema]bool
+	units   map[*UnitsDefinition]bool
+	sizer   *memorySizer
+}
+
+func newSchemaMeasurer() *schemaMeasurer {
+	return &schemaMeasurer{
+		objects: map[*ObjectSchema]bool{},
+		units:   map[*UnitsDefinition]bool{},
+		sizer:   &memorySizer{seen: map[memoryKey]bool{}},
+	}
+}
+
+func (m *schemaMeasurer) measureScope(t Type) {
+	if t == nil {
+		return
+	}
+	value := reflect.ValueOf(t)
+	if value.Kind() == reflect.Pointer && m.sizer.seen[memoryKey{value.Pointer(), value.Type()}] {
+		return
+	}
+	if t.TypeID() == TypeIDScope {
+		m.stats.Scopes++
+	}
+	m.stats.EstimatedBytes += m.sizer.size(reflect.ValueOf(&t).Elem())
+	_ = Walk(t, func(_ []string, t Type) error {
+		m.stats.Types++
+		if withUnits, ok := t.(interface{ Units() *UnitsDefinition }); ok {
+			m.measureUnits(withUnits.Units())
+		}
+		object, ok := t.(*ObjectSchema)
+		if !ok {
+			return nil
+		}
+		if m.objects[object] {
+			return SkipChildren
+		}
+		m.objects[object] = true
+		m.measureObject(object)
+		return nil
+	})
+}
+
+func (m *schemaMeasurer) measureObject(object *ObjectSchema) {
+	m.stats.Objects++
+	m.stats.Properties += len(object.PropertiesValue)
+	if object.fieldCache != nil {
+		m.stats.StructMappedObjects++
+		m.stats.FieldCacheEntries += len(object.fieldCache)
+	}
+	if object.compiled.plan != nil {
+		m.stats.DecodePlans++
+	}
+	if object.compiled.index != nil {
+		m.stats.PropertyIndexes++
+	}
+	if object.compiled.fields != nil {
+		m.stats.CompiledObjects++
+	}
+}
+
+func (m *schemaMeasurer) measureUnits(units *UnitsDefinition) {
+	if units == nil || m.units[units] {
+		return
+	}
+	m.units[units] = true
+	if cache := units.cache.Load(); cache != nil {
+		m.stats.UnitRegexes++
+		// The sizer does not follow the atomic pointer of the cache.
+		m.stats.EstimatedBytes += m.sizer.size(reflect.ValueOf(cache))
+	}
+}
+
+// memorySizer estimates the memory held by values, counting the memory behind each pointer, slice and map once.
+type memorySizer struct {
+	seen map[memoryKey]bool
+}
+
+// memoryKey identifies counted memory. The type is part of the key because a struct and its first field have the same
+// address.
+type memoryKey struct {
+	address uintptr
+	typ     reflect.Type
+}
+
+var reflectTypeType = reflect.TypeOf((*reflect.Type)(nil)).Elem()
+
+// size returns the size of the value including the memory it points to.
+func (s *memorySizer) size(v reflect.Value) int64 {
+	return int64(v.Type().Size()) + s.referenced(v)
+}
+
+// referenced returns the size of the memory the value points to, excluding the value itself.
+func (s *memorySizer) referenced(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || s.visit(v) {
+			return 0
+		}
+		return s.size(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() || v.Type() == reflectTypeType {
+			return 0
+		}
+		elem := v.Elem()
+		if elem.Kind() == reflect.Pointer {
+			return s.referenced(elem)
+		}
+		return s.size(elem)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Struct:
+		var total int64
+		for i := 0; i < v.NumField(); i++ {
+			total += s.referenced(v.Field(i))
+		}
+		return total
+	case reflect.Array:
+		var total int64
+		for i := 0; i < v.Len(); i++ {
+			total += s.referenced(v.Index(i))
+		}
+		return total
+	case reflect.Slice:
+		if v.IsNil() || s.visit(v) {
+			return 0
+		}
+		total := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			total += s.referenced(v.Index(i))
+		}
+		return total
+	case reflect.Map:
+		if v.IsNil() || s.visit(v) {
+			return 0
+		}
+		total := int64(v.Len()) * int64(v.Type().Key().Size()+v.Type().Elem().Size())
+		iterator := v.MapRange()
+		for iterator.Next() {
+			total += s.referenced(iterator.Key()) + s.referenced(iterator.Value())
+		}
+		return total
+	default:
+		return 0
+	}
+}
+
+// visit marks the memory the value points to as counted and returns whether it was counted before.
+func (s *memorySizer) visit(v reflect.Value) bool {
+	key := memoryKey{v.Pointer(), v.Type()}
+	if s.seen[key] {
+		return true
+	}
+	s.seen[key] = true
+	return false
+}
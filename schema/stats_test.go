@@ -0,0 +1,54 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestMeasureSteps(t *testing.T) {
+	callable := engineTestSchema()
+	stats := schema.MeasureSteps(callable.StepsValue)
+	assert.Equals(t, stats.Steps, 2)
+	// The steps share the input scope, but each has its own output scope.
+	assert.Equals(t, stats.Scopes, 3)
+	assert.Equals(t, stats.Objects, 3)
+	assert.Equals(t, stats.StructMappedObjects, 1)
+	assert.Equals(t, stats.FieldCacheEntries, 3)
+	assert.Equals(t, stats.Properties, 3)
+	assert.Equals(t, stats.EstimatedBytes > 0, true)
+
+	// Freezing shares the identical scopes.
+	frozen := assert.NoErrorR[*schema.FrozenSchema](t)(schema.Freeze(callable))
+	frozenStats := schema.MeasureSteps(frozen.Steps())
+	assert.Equals(t, frozenStats.Steps, 2)
+	assert.Equals(t, frozenStats.Scopes, 2)
+	assert.Equals(t, frozenStats.Objects, 2)
+	assert.Equals(t, frozenStats.StructMappedObjects, 0)
+	assert.Equals(t, frozenStats.EstimatedBytes < stats.EstimatedBytes, true)
+}
+
+func TestMeasureType(t *testing.T) {
+	units := schema.NewUnits(
+		schema.NewUnit("s", "s", "second", "seconds"),
+		map[int64]*schema.UnitDefinition{60: schema.NewUnit("m", "m", "minute", "minutes")},
+	)
+	durationType := schema.NewIntSchema(nil, nil, units)
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+		"a": schema.NewPropertySchema(durationType, nil, true, nil, nil, nil, nil, nil),
+		"b": schema.NewPropertySchema(durationType, nil, true, nil, nil, nil, nil, nil),
+	}))
+	stats := schema.MeasureType(scope)
+	assert.Equals(t, stats.Scopes, 1)
+	assert.Equals(t, stats.Objects, 1)
+	assert.Equals(t, stats.Types, 4)
+	before := stats.EstimatedBytes
+
+	// Parsing a unit string compiles the parsing regex of the units.
+	_, err := scope.Unserialize(map[string]any{"a": "5s", "b": "1m"})
+	assert.NoError(t, err)
+	stats = schema.MeasureType(scope)
+	assert.Equals(t, stats.UnitRegexes, 1)
+	assert.Equals(t, stats.EstimatedBytes > before, true)
+}
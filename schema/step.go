@@ -34,12 +34,12 @@ func NewStepSchema(
 	display Display,
 ) *StepSchema {
 	return &StepSchema{
-		id,
-		input,
-		outputs,
-		signalHandlers,
-		signalEmitters,
-		display,
+		IDValue:             id,
+		InputValue:          input,
+		OutputsValue:        outputs,
+		SignalHandlersValue: signalHandlers,
+		SignalEmittersValue: signalEmitters,
+		DisplayValue:        display,
 	}
 }
 
@@ -52,6 +52,10 @@ type StepSchema struct {
 	SignalHandlersValue map[string]*SignalSchema     `json:"signal_handlers"`
 	SignalEmittersValue map[string]*SignalSchema     `json:"signal_emitters"`
 	DisplayValue        Display                      `json:"display"`
+	DeprecatedValue     *Deprecated                  `json:"deprecated"`
+	SchedulingValue     *Scheduling                  `json:"scheduling"`
+	// EngineRequirementsValue holds what the engine has to support to run the step, if anything.
+	EngineRequirementsValue *EngineRequirements `json:"engine_requirements"`
 }
 
 func (s StepSchema) ID() string {
@@ -78,6 +82,36 @@ func (s StepSchema) Display() Display {
 	return s.DisplayValue
 }
 
+func (s StepSchema) Deprecated() *Deprecated {
+	return s.DeprecatedValue
+}
+
+// Deprecate is a builder-pattern way of marking the step as deprecated.
+func (s *StepSchema) Deprecate(deprecated Deprecated) *StepSchema {
+	s.DeprecatedValue = &deprecated
+	return s
+}
+
+func (s StepSchema) Scheduling() *Scheduling {
+	return s.SchedulingValue
+}
+
+// Schedule is a builder-pattern way of adding scheduling hints to the step.
+func (s *StepSchema) Schedule(scheduling Scheduling) *StepSchema {
+	s.SchedulingValue = &scheduling
+	return s
+}
+
+func (s StepSchema) EngineRequirements() *EngineRequirements {
+	return s.EngineRequirementsValue
+}
+
+// RequireEngine is a builder-pattern way of restricting the step to engines that meet the requirements.
+func (s *StepSchema) RequireEngine(requirements EngineRequirements) *StepSchema {
+	s.EngineRequirementsValue = &requirements
+	return s
+}
+
 // NewCallableStep creates a callable step definition.
 func NewCallableStep[StepInputType any](
 	id string,
@@ -146,10 +180,15 @@ type CallableStepSchema[StepData any, InputType any] struct {
 	SignalEmittersValue map[string]*SignalSchema     `json:"signal_emitters"`
 	OutputsValue        map[string]*StepOutputSchema `json:"outputs"`
 	DisplayValue        Display                      `json:"display"`
-	initializer         func() StepData
-	initializerMutex    sync.Mutex
-	stepData            map[string]*runningStepData[StepData] // Maps run ID to step data
-	handler             func(context.Context, StepData, InputType) (string, any)
+	DeprecatedValue     *Deprecated                  `json:"deprecated"`
+	SchedulingValue     *Scheduling                  `json:"scheduling"`
+	// EngineRequirementsValue holds what the engine has to support to run the step, if anything.
+	EngineRequirementsValue *EngineRequirements `json:"engine_requirements"`
+	initializer             func() StepData
+	initializerMutex        sync.Mutex
+	stepData                map[string]*runningStepData[StepData] // Maps run ID to step data
+	handler                 func(context.Context, StepData, InputType) (string, any)
+	rateLimiters            rateLimiters
 }
 
 func (s *CallableStepSchema[StepData, InputType]) SignalHandlers() map[string]*SignalSchema {
@@ -180,6 +219,42 @@ func (s *CallableStepSchema[StepData, InputType]) Display() Display {
 	return s.DisplayValue
 }
 
+func (s *CallableStepSchema[StepData, InputType]) Deprecated() *Deprecated {
+	return s.DeprecatedValue
+}
+
+// Deprecate is a builder-pattern way of marking the step as deprecated.
+func (s *CallableStepSchema[StepData, InputType]) Deprecate(
+	deprecated Deprecated,
+) *CallableStepSchema[StepData, InputType] {
+	s.DeprecatedValue = &deprecated
+	return s
+}
+
+func (s *CallableStepSchema[StepData, InputType]) Scheduling() *Scheduling {
+	return s.SchedulingValue
+}
+
+// Schedule is a builder-pattern way of adding scheduling hints to the step.
+func (s *CallableStepSchema[StepData, InputType]) Schedule(
+	scheduling Scheduling,
+) *CallableStepSchema[StepData, InputType] {
+	s.SchedulingValue = &scheduling
+	return s
+}
+
+func (s *CallableStepSchema[StepData, InputType]) EngineRequirements() *EngineRequirements {
+	return s.EngineRequirementsValue
+}
+
+// RequireEngine is a builder-pattern way of restricting the step to engines that meet the requirements.
+func (s *CallableStepSchema[StepData, InputType]) RequireEngine(
+	requirements EngineRequirements,
+) *CallableStepSchema[StepData, InputType] {
+	s.EngineRequirementsValue = &requirements
+	return s
+}
+
 func (s *CallableStepSchema[StepData, InputType]) ToStepSchema() *StepSchema {
 	signalHandlers := make(map[string]*SignalSchema, len(s.SignalHandlersValue))
 	for k, v := range s.SignalHandlersValue {
@@ -192,6 +267,10 @@ func (s *CallableStepSchema[StepData, InputType]) ToStepSchema() *StepSchema {
 		SignalHandlersValue: signalHandlers,
 		SignalEmittersValue: s.SignalEmittersValue,
 		DisplayValue:        s.DisplayValue,
+		DeprecatedValue:     s.DeprecatedValue,
+		SchedulingValue:     s.SchedulingValue,
+
+		EngineRequirementsValue: s.EngineRequirementsValue,
 	}
 }
 
@@ -228,6 +307,10 @@ func (s *CallableStepSchema[StepData, InputType]) Call(ctx context.Context, runI
 	}
 
 	runningStepData := s.setupStepData(runID)
+	ctx = s.rateLimiters.contextWithInputRateLimiter(ctx, input)
+	ctx, cleanups := contextWithCleanups(ctx)
+	// Deferred, so the cleanup functions also run if the handler panics.
+	defer cleanups.run(ctx)
 	outputID, outputData := s.handler(ctx, runningStepData.initializedData, input.(InputType))
 	output, ok := s.OutputsValue[outputID]
 	if !ok {
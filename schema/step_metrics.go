@@ -0,0 +1,99 @@
+package schema
+
+import (
+	"context"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// StepMetrics holds the measurements of a single step execution. CallableSchema.CallStep collects them into contexts
+// created with ContextWithStepMetrics, and the ATP server sends them to the engine along with the step output, so
+// plugins are measured without writing any instrumentation. DescribeStepMetrics returns its schema.
+type StepMetrics struct {
+	// UnserializeNanoseconds is the time it took to unserialize and validate the step input.
+	UnserializeNanoseconds int64 `json:"unserialize_ns"`
+	// HandlerNanoseconds is the time the step handler ran, including the validation of its output.
+	HandlerNanoseconds int64 `json:"handler_ns"`
+	// SerializeNanoseconds is the time it took to serialize the step output.
+	SerializeNanoseconds int64 `json:"serialize_ns"`
+	// InputBytes is the size of the serialized step input encoded as CBOR.
+	InputBytes int64 `json:"input_bytes"`
+	// OutputBytes is the size of the serialized step output encoded as CBOR. It is 0 if the step failed without an
+	// output.
+	OutputBytes int64 `json:"output_bytes"`
+}
+
+type stepMetricsContextKey struct{}
+
+// ContextWithStepMetrics returns a copy of the context that makes CallableSchema.CallStep record the measurements of
+// the step execution into the metrics. The metrics must not be read before CallStep returns.
+func ContextWithStepMetrics(ctx context.Context, metrics *StepMetrics) context.Context {
+	return context.WithValue(ctx, stepMetricsContextKey{}, metrics)
+}
+
+// stepMetricsFromContext returns the metrics of the context, or nil if it has none.
+func stepMetricsFromContext(ctx context.Context) *StepMetrics {
+	metrics, _ := ctx.Value(stepMetricsContextKey{}).(*StepMetrics)
+	return metrics
+}
+
+// encodedSize returns the size of the serialized data encoded as CBOR, or 0 if it cannot be encoded.
+func encodedSize(serialized any) int64 {
+	encoded, err := cbor.Marshal(serialized)
+	if err != nil {
+		return 0
+	}
+	return int64(len(encoded))
+}
+
+var stepMetricsSchema = NewScopeSchema(
+	NewStructMappedObjectSchema[StepMetrics](
+		"StepMetrics",
+		map[string]*PropertySchema{
+			"unserialize_ns": newStepMetricsProperty(
+				UnitDurationNanoseconds,
+				"Unserialize time",
+				"Time it took to unserialize and validate the step input.",
+			),
+			"handler_ns": newStepMetricsProperty(
+				UnitDurationNanoseconds,
+				"Handler time",
+				"Time the step handler ran, including the validation of its output.",
+			),
+			"serialize_ns": newStepMetricsProperty(
+				UnitDurationNanoseconds,
+				"Serialize time",
+				"Time it took to serialize the step output.",
+			),
+			"input_bytes": newStepMetricsProperty(
+				UnitBytes,
+				"Input size",
+				"Size of the serialized step input encoded as CBOR.",
+			),
+			"output_bytes": newStepMetricsProperty(
+				UnitBytes,
+				"Output size",
+				"Size of the serialized step output encoded as CBOR.",
+			),
+		},
+	),
+)
+
+func newStepMetricsProperty(units *UnitsDefinition, name string, description string) *PropertySchema {
+	return NewPropertySchema(
+		NewIntSchema(IntPointer(0), nil, units),
+		NewDisplayValue(PointerTo(name), PointerTo(description), nil),
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// DescribeStepMetrics returns a scope that describes StepMetrics, so engines can validate and unserialize the metrics
+// they aggregate.
+func DescribeStepMetrics() *ScopeSchema {
+	return stepMetricsSchema
+}
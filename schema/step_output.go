@@ -23,16 +23,17 @@ func NewStepOutputSchema(
 	error bool,
 ) *StepOutputSchema {
 	return &StepOutputSchema{
-		schema,
-		display,
-		error,
+		SchemaValue:  schema,
+		DisplayValue: display,
+		ErrorValue:   error,
 	}
 }
 
 type StepOutputSchema struct {
-	SchemaValue  Scope         `json:"schema"`
-	DisplayValue *DisplayValue `json:"display"`
-	ErrorValue   bool          `json:"error"`
+	SchemaValue     Scope             `json:"schema"`
+	DisplayValue    *DisplayValue     `json:"display"`
+	ErrorValue      bool              `json:"error"`
+	TruncationValue *TruncationPolicy `json:"truncation"`
 }
 
 func (s StepOutputSchema) ReflectedType() reflect.Type {
@@ -63,6 +64,10 @@ func (s StepOutputSchema) ValidateReferences() error {
 	return s.SchemaValue.ValidateReferences()
 }
 
+func (s StepOutputSchema) ApplyDefaults(serialized any) any {
+	return ApplyDefaults(s.SchemaValue, serialized)
+}
+
 func (s StepOutputSchema) Schema() Scope {
 	return s.SchemaValue
 }
@@ -74,3 +79,15 @@ func (s StepOutputSchema) Display() *DisplayValue {
 func (s StepOutputSchema) Error() bool {
 	return s.ErrorValue
 }
+
+// Truncation returns the policy the output is truncated with when it is sent to the engine, or nil if it is sent as
+// is.
+func (s StepOutputSchema) Truncation() *TruncationPolicy {
+	return s.TruncationValue
+}
+
+// Truncate is a builder-pattern way of setting the policy the output is truncated with when it is sent to the engine.
+func (s *StepOutputSchema) Truncate(policy TruncationPolicy) *StepOutputSchema {
+	s.TruncationValue = &policy
+	return s
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"go.arcalot.io/assert"
 	"testing"
+	"time"
 
+	"go.flow.arcalot.io/pluginsdk/clock"
 	"go.flow.arcalot.io/pluginsdk/schema"
 )
 
@@ -101,3 +103,30 @@ func TestStepExecution(t *testing.T) {
 	assert.Equals(t, outputID, "success")
 	assert.Equals(t, outputData.(stepTestSuccessOutput).Message, "Hello, Arca Lot!")
 }
+
+func TestStepMetrics(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	callable := schema.NewCallableSchema(schema.NewCallableStep(
+		"hello",
+		testStepSchema.Input().(*schema.ScopeSchema),
+		testStepSchema.Outputs(),
+		nil,
+		func(ctx context.Context, input stepTestInputData) (string, any) {
+			fake.Advance(time.Second)
+			return stepTestHandler(ctx, input)
+		},
+	))
+	metrics := &schema.StepMetrics{}
+	ctx := schema.ContextWithStepMetrics(clock.NewContext(context.Background(), fake), metrics)
+	outputID, _, err := callable.CallStep(ctx, t.Name(), "hello", map[string]any{"name": "Arca Lot"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, "success")
+	assert.Equals(t, metrics.UnserializeNanoseconds, int64(0))
+	assert.Equals(t, metrics.HandlerNanoseconds, time.Second.Nanoseconds())
+	assert.Equals(t, metrics.SerializeNanoseconds, int64(0))
+	assert.Equals(t, metrics.InputBytes > 0, true)
+	assert.Equals(t, metrics.OutputBytes > 0, true)
+
+	serialized := assert.NoErrorR[any](t)(schema.DescribeStepMetrics().Serialize(*metrics))
+	assert.Equals(t, serialized.(map[string]any)["handler_ns"], any(time.Second.Nanoseconds()))
+}
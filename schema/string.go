@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -59,6 +60,10 @@ func (s StringSchema) Pattern() *regexp.Regexp {
 }
 
 func (s StringSchema) Unserialize(data any) (any, error) {
+	if str, ok := data.(string); ok {
+		// Returning the data as is avoids boxing the string into a new interface value.
+		return data, s.ValidateType(str)
+	}
 	return s.UnserializeType(data)
 }
 
@@ -114,50 +119,57 @@ func (s StringSchema) ValidateCompatibility(typeOrData any) error {
 }
 
 func (s StringSchema) Validate(d any) error {
-	_, err := s.Serialize(d)
-	return err
+	data, err := asString(d)
+	if err != nil {
+		return err
+	}
+	return s.ValidateType(data)
 }
 
 func (s StringSchema) ValidateType(data string) error {
 	if s.MinValue != nil && int64(len(data)) < *s.MinValue {
 		return &ConstraintError{
-			Message: fmt.Sprintf("String must be at least %d characters, %d given", *s.MinValue, int64(len(data))),
+			Message:    fmt.Sprintf("String must be at least %d characters, %d given", *s.MinValue, int64(len(data))),
+			Constraint: ConstraintMin,
+			Expected:   *s.MinValue,
+			Actual:     int64(len(data)),
 		}
 	}
 	if s.MaxValue != nil && int64(len(data)) > *s.MaxValue {
 		return &ConstraintError{
-			Message: fmt.Sprintf("String must be at most %d characters, %d given", *s.MaxValue, int64(len(data))),
+			Message:    fmt.Sprintf("String must be at most %d characters, %d given", *s.MaxValue, int64(len(data))),
+			Constraint: ConstraintMax,
+			Expected:   *s.MaxValue,
+			Actual:     int64(len(data)),
 		}
 	}
 	if s.PatternValue != nil && !(*s.PatternValue).MatchString(data) {
 		return &ConstraintError{
-			Message: fmt.Sprintf("String '%s' must match the pattern '%s'", data, (*s.PatternValue).String()),
+			Message:    fmt.Sprintf("String '%s' must match the pattern '%s'", data, (*s.PatternValue).String()),
+			Constraint: ConstraintPattern,
+			Expected:   (*s.PatternValue).String(),
+			Actual:     describeValue(data),
 		}
 	}
 	return nil
 }
 
 func (s StringSchema) Serialize(d any) (any, error) {
+	if data, ok := d.(string); ok {
+		// Returning the data as is avoids boxing the string into a new interface value.
+		return d, s.ValidateType(data)
+	}
 	data, err := asString(d)
 	if err != nil {
 		return data, err
 	}
-	if s.MinValue != nil && int64(len(data)) < *s.MinValue {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("String must be at least %d characters, %d given", *s.MinValue, int64(len(data))),
-		}
-	}
-	if s.MaxValue != nil && int64(len(data)) > *s.MaxValue {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("String must be at most %d characters, %d given", *s.MaxValue, int64(len(data))),
-		}
-	}
-	if s.PatternValue != nil && !(*s.PatternValue).MatchString(data) {
-		return data, &ConstraintError{
-			Message: fmt.Sprintf("String '%s' must match the pattern '%s'", data, (*s.PatternValue).String()),
-		}
-	}
-	return data, nil
+	return data, s.ValidateType(data)
+}
+
+// SerializeString serializes a string without converting it to and from an interface value, which avoids the
+// allocations of Serialize for plugins serializing many values.
+func (s StringSchema) SerializeString(data string) (string, error) {
+	return data, s.ValidateType(data)
 }
 
 func asString(d any) (string, error) {
@@ -166,9 +178,12 @@ func asString(d any) (string, error) {
 		var i string
 		stringType := reflect.TypeOf(i)
 		dValue := reflect.ValueOf(d)
-		if !dValue.CanConvert(stringType) {
+		if !dValue.IsValid() || !dValue.CanConvert(stringType) {
 			return "", &ConstraintError{
-				Message: fmt.Sprintf("%T is not a valid data type for a string schema.", d),
+				Message:    fmt.Sprintf("%T is not a valid data type for a string schema.", d),
+				Constraint: ConstraintDataType,
+				Expected:   "string",
+				Actual:     fmt.Sprintf("%T", d),
 			}
 		}
 		data = dValue.Convert(stringType).String()
@@ -184,6 +199,8 @@ func stringInputMapper(data any) (string, error) {
 	switch v := data.(type) {
 	case string:
 		return v, nil
+	case json.Number:
+		return v.String(), nil
 	case int:
 		return fmt.Sprintf("%d", v), nil
 	case uint:
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go.arcalot.io/assert"
 	"regexp"
+	"strings"
 	"testing"
 
 	"go.flow.arcalot.io/pluginsdk/schema"
@@ -203,3 +204,24 @@ func TestVerifyCompatibility(t *testing.T) {
 	// Int enum invalid
 	assert.Error(t, s1.ValidateCompatibility(schema.NewIntEnumSchema(map[int64]*schema.DisplayValue{}, nil)))
 }
+
+func TestStringNilValue(t *testing.T) {
+	assert.Error(t, schema.NewStringSchema(nil, nil, nil).Validate(nil))
+	_, err := schema.NewStringSchema(nil, nil, nil).Serialize(nil)
+	assert.Error(t, err)
+}
+
+func TestStringAllocations(t *testing.T) {
+	s := schema.NewStringSchema(schema.IntPointer(1), schema.IntPointer(64), nil)
+	var data any = strings.Repeat("a", 32)
+	assert.Equals(t, testing.AllocsPerRun(100, func() {
+		_, _ = s.Serialize(data)
+		_, _ = s.Unserialize(data)
+		_ = s.Validate(data)
+		_, _ = s.SerializeString("metric")
+	}), float64(0))
+
+	assert.Equals(t, assert.NoErrorR[string](t)(s.SerializeString("metric")), "metric")
+	_, err := s.SerializeString("")
+	assert.Error(t, err)
+}
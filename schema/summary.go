@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// SummaryOptions configures the summaries of the large lists in a step output. Engines request summaries, for example
+// in the ATP start message, so they can show a preview of an output without reading all of it.
+type SummaryOptions struct {
+	// MinItems is the number of items from which a list is summarized. Smaller lists are not summarized.
+	MinItems int64 `json:"min_items"`
+	// FirstItems is the number of items at the start of the list included in the summary.
+	FirstItems int64 `json:"first_items"`
+}
+
+// DefaultSummaryOptions returns the options engines use unless they need different limits.
+func DefaultSummaryOptions() SummaryOptions {
+	return SummaryOptions{
+		MinItems:   100,
+		FirstItems: 5,
+	}
+}
+
+// ListSummary describes a large list of a serialized output. DescribeListSummary returns its schema.
+type ListSummary struct {
+	// Path is the JSON pointer of the list in the output, as accepted by ValueAtPath.
+	Path string `json:"path"`
+	// Count is the number of items in the list.
+	Count int64 `json:"count"`
+	// First holds the first items of the list.
+	First []any `json:"first"`
+	// Numeric holds the statistics of the numeric values in the list, keyed by their JSON pointer relative to the
+	// item. The key is empty for lists of numbers, and the property ID such as "/size" for lists of objects.
+	Numeric map[string]NumericSummary `json:"numeric"`
+}
+
+// NumericSummary holds the statistics of the numeric values at the same place in the items of a list.
+type NumericSummary struct {
+	// Count is the number of items that have a numeric value at the place.
+	Count int64   `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+}
+
+// Summarize returns a summary of each list in the serialized data that has at least MinItems items, sorted by path.
+// The items of a summarized list are not searched for further lists.
+func (o SummaryOptions) Summarize(serialized any) []ListSummary {
+	var summaries []ListSummary
+	o.summarize(reflect.ValueOf(serialized), "", &summaries)
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Path < summaries[j].Path
+	})
+	return summaries
+}
+
+func (o SummaryOptions) summarize(value reflect.Value, path string, summaries *[]ListSummary) {
+	if !value.IsValid() {
+		return
+	}
+	switch value.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if !value.IsNil() {
+			o.summarize(value.Elem(), path, summaries)
+		}
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are binary data, not lists.
+			return
+		}
+		if int64(value.Len()) >= o.MinItems {
+			*summaries = append(*summaries, o.summarizeList(value, path))
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			o.summarize(value.Index(i), fmt.Sprintf("%s/%d", path, i), summaries)
+		}
+	case reflect.Map:
+		iterator := value.MapRange()
+		for iterator.Next() {
+			itemPath := path + "/" + jsonPointerEscaper.Replace(fmt.Sprintf("%v", iterator.Key().Interface()))
+			o.summarize(iterator.Value(), itemPath, summaries)
+		}
+	}
+}
+
+func (o SummaryOptions) summarizeList(value reflect.Value, path string) ListSummary {
+	first := min(int64(value.Len()), max(o.FirstItems, 0))
+	summary := ListSummary{
+		Path:    path,
+		Count:   int64(value.Len()),
+		First:   make([]any, first),
+		Numeric: map[string]NumericSummary{},
+	}
+	for i := 0; i < int(first); i++ {
+		summary.First[i] = value.Index(i).Interface()
+	}
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		for item.Kind() == reflect.Interface && !item.IsNil() {
+			item = item.Elem()
+		}
+		if number, ok := numericValue(item); ok {
+			summary.Numeric[""] = summary.Numeric[""].add(number)
+			continue
+		}
+		if item.Kind() != reflect.Map {
+			continue
+		}
+		iterator := item.MapRange()
+		for iterator.Next() {
+			if number, ok := numericValue(iterator.Value()); ok {
+				key := "/" + jsonPointerEscaper.Replace(fmt.Sprintf("%v", iterator.Key().Interface()))
+				summary.Numeric[key] = summary.Numeric[key].add(number)
+			}
+		}
+	}
+	return summary
+}
+
+// add returns the statistics with the value included.
+func (s NumericSummary) add(value float64) NumericSummary {
+	if s.Count == 0 {
+		return NumericSummary{1, value, value, value}
+	}
+	s.Count++
+	s.Min = math.Min(s.Min, value)
+	s.Max = math.Max(s.Max, value)
+	s.Mean += (value - s.Mean) / float64(s.Count)
+	return s
+}
+
+// numericValue returns the value as a float if it is a number.
+func numericValue(value reflect.Value) (float64, bool) {
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+var listSummarySchema = NewScopeSchema(
+	NewStructMappedObjectSchema[ListSummary](
+		"ListSummary",
+		map[string]*PropertySchema{
+			"path": NewPropertySchema(
+				NewStringSchema(nil, nil, nil),
+				NewDisplayValue(PointerTo("Path"), PointerTo("JSON pointer of the list in the output."), nil),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"count": NewPropertySchema(
+				NewIntSchema(IntPointer(0), nil, nil),
+				NewDisplayValue(PointerTo("Count"), PointerTo("Number of items in the list."), nil),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"first": NewPropertySchema(
+				NewListSchema(NewAnySchema(), nil, nil),
+				NewDisplayValue(PointerTo("First items"), PointerTo("The first items of the list."), nil),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"numeric": NewPropertySchema(
+				NewMapSchema(NewStringSchema(nil, nil, nil), NewRefSchema("NumericSummary", nil), nil, nil),
+				NewDisplayValue(
+					PointerTo("Numeric statistics"),
+					PointerTo("Statistics of the numeric values in the list, keyed by their JSON pointer in the item."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+	NewStructMappedObjectSchema[NumericSummary](
+		"NumericSummary",
+		map[string]*PropertySchema{
+			"count": NewPropertySchema(NewIntSchema(IntPointer(0), nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"min":   NewPropertySchema(NewFloatSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"max":   NewPropertySchema(NewFloatSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"mean":  NewPropertySchema(NewFloatSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		},
+	),
+)
+
+// DescribeListSummary returns a scope that describes a ListSummary, so consumers of summaries can validate and
+// unserialize them.
+func DescribeListSummary() *ScopeSchema {
+	return listSummarySchema
+}
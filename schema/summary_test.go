@@ -0,0 +1,56 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestSummaryOptionsSummarize(t *testing.T) {
+	items := make([]any, 0, 4)
+	for i := int64(1); i <= 4; i++ {
+		items = append(items, map[string]any{"name": "item", "size": i * 10, "ratio": float64(i) / 2})
+	}
+	serialized := map[string]any{
+		"items":   items,
+		"numbers": []any{int64(3), int64(1), int64(2)},
+		"short":   []any{"a"},
+		"nested":  []any{map[string]any{"values": []any{"a", "b", "c"}}},
+	}
+	summaries := schema.SummaryOptions{MinItems: 3, FirstItems: 2}.Summarize(serialized)
+	assert.Equals(t, summaries, []schema.ListSummary{
+		{
+			Path:  "/items",
+			Count: 4,
+			First: items[:2],
+			Numeric: map[string]schema.NumericSummary{
+				"/size":  {Count: 4, Min: 10, Max: 40, Mean: 25},
+				"/ratio": {Count: 4, Min: 0.5, Max: 2, Mean: 1.25},
+			},
+		},
+		// Lists below the limit are searched for larger lists.
+		{
+			Path:    "/nested/0/values",
+			Count:   3,
+			First:   []any{"a", "b"},
+			Numeric: map[string]schema.NumericSummary{},
+		},
+		{
+			Path:    "/numbers",
+			Count:   3,
+			First:   []any{int64(3), int64(1)},
+			Numeric: map[string]schema.NumericSummary{"": {Count: 3, Min: 1, Max: 3, Mean: 2}},
+		},
+	})
+
+	assert.Equals(t, len(schema.DefaultSummaryOptions().Summarize(serialized)), 0)
+}
+
+func TestDescribeListSummary(t *testing.T) {
+	summaries := schema.SummaryOptions{MinItems: 2, FirstItems: 1}.Summarize([]any{int64(1), int64(2)})
+	assert.Equals(t, len(summaries), 1)
+	serialized := assert.NoErrorR[any](t)(schema.DescribeListSummary().Serialize(summaries[0]))
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeListSummary().Unserialize(serialized))
+	assert.Equals(t, unserialized.(schema.ListSummary), summaries[0])
+}
@@ -0,0 +1,186 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NewTemplateStringSchema creates a new string schema for templates, such as the messages of notification or reporting
+// plugins, whose placeholders refer to the variables described by the given type, usually an object or a scope.
+func NewTemplateStringSchema(variables Type) *TemplateStringSchema {
+	if variables == nil {
+		panic(BadArgumentError{Message: "template strings need a schema of their variables"})
+	}
+	return &TemplateStringSchema{
+		VariablesValue: variables,
+	}
+}
+
+// TemplateStringSchema is a string holding a template with {{ variable }} placeholders. A placeholder holds the path
+// of a variable in the format of TypeAtPath, such as {{ name }} or {{ server.host }}, which must lead to a scalar
+// value, such as a string, number, bool or enum. A literal {{ is written as \{{. The placeholders are checked when the
+// string is unserialized, so invalid templates are rejected before the step runs, and Render fills them in.
+//
+// The variables are not part of the serialized schema, which describes a plain string.
+type TemplateStringSchema struct {
+	StringSchema `json:",inline"`
+
+	VariablesValue Type `json:"-"`
+}
+
+// Variables returns the type describing the variables of the template.
+func (s TemplateStringSchema) Variables() Type {
+	return s.VariablesValue
+}
+
+func (s TemplateStringSchema) Unserialize(data any) (any, error) {
+	return s.UnserializeType(data)
+}
+
+func (s TemplateStringSchema) UnserializeType(data any) (string, error) {
+	unserialized, err := stringInputMapper(data)
+	if err != nil {
+		return "", err
+	}
+	return unserialized, s.ValidateType(unserialized)
+}
+
+func (s TemplateStringSchema) ValidateCompatibility(typeOrData any) error {
+	if data, ok := typeOrData.(string); ok {
+		return s.ValidateType(data)
+	}
+	return s.StringSchema.ValidateCompatibility(typeOrData)
+}
+
+func (s TemplateStringSchema) Validate(d any) error {
+	data, err := asString(d)
+	if err != nil {
+		return err
+	}
+	return s.ValidateType(data)
+}
+
+func (s TemplateStringSchema) ValidateType(data string) error {
+	if err := s.StringSchema.ValidateType(data); err != nil {
+		return err
+	}
+	_, err := s.parse(data)
+	return err
+}
+
+func (s TemplateStringSchema) Serialize(d any) (any, error) {
+	data, err := asString(d)
+	if err != nil {
+		return data, err
+	}
+	return data, s.ValidateType(data)
+}
+
+func (s TemplateStringSchema) SerializeString(data string) (string, error) {
+	return data, s.ValidateType(data)
+}
+
+func (s TemplateStringSchema) SerializeType(data string) (any, error) {
+	return data, s.ValidateType(data)
+}
+
+// Render validates the template and replaces its placeholders with the values of the variables, which may be
+// serialized or unserialized. The values are formatted like fmt.Sprint does. Rendering fails if a variable is not set,
+// such as an unset optional property, since a silently empty placeholder is rarely intended. The values are inserted
+// as they are and never interpreted as a template themselves.
+func (s TemplateStringSchema) Render(template string, variables any) (string, error) {
+	if err := s.StringSchema.ValidateType(template); err != nil {
+		return "", err
+	}
+	parts, err := s.parse(template)
+	if err != nil {
+		return "", err
+	}
+	result := strings.Builder{}
+	for _, part := range parts {
+		if part.variable == "" {
+			result.WriteString(part.literal)
+			continue
+		}
+		value, err := ValueAtPath(s.VariablesValue, variables, part.variable)
+		if err != nil {
+			return "", fmt.Errorf("failed to render template variable %s (%w)", part.variable, err)
+		}
+		if isAbsentValue(value) {
+			return "", fmt.Errorf("failed to render template variable %s, it is not set", part.variable)
+		}
+		result.WriteString(fmt.Sprint(value))
+	}
+	return result.String(), nil
+}
+
+// templatePart is either a literal text or a placeholder of a template string.
+type templatePart struct {
+	literal  string
+	variable string
+}
+
+// parse splits the template into its parts and checks that each placeholder refers to a scalar variable.
+func (s TemplateStringSchema) parse(template string) ([]templatePart, error) {
+	var parts []templatePart
+	literal := strings.Builder{}
+	for offset := 0; offset < len(template); {
+		rest := template[offset:]
+		switch {
+		case strings.HasPrefix(rest, `\{{`):
+			literal.WriteString("{{")
+			offset += 3
+		case strings.HasPrefix(rest, "{{"):
+			end := strings.Index(rest, "}}")
+			if end < 0 {
+				return nil, templateError(fmt.Sprintf("unterminated placeholder at offset %d", offset), nil)
+			}
+			variable := strings.TrimSpace(rest[2:end])
+			if variable == "" || strings.Contains(variable, "{{") {
+				return nil, templateError(fmt.Sprintf("invalid placeholder at offset %d", offset), nil)
+			}
+			if err := s.checkVariable(variable); err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				parts = append(parts, templatePart{literal: literal.String()})
+				literal.Reset()
+			}
+			parts = append(parts, templatePart{variable: variable})
+			offset += end + 2
+		default:
+			literal.WriteByte(template[offset])
+			offset++
+		}
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, templatePart{literal: literal.String()})
+	}
+	return parts, nil
+}
+
+func (s TemplateStringSchema) checkVariable(variable string) error {
+	variableType, err := TypeAtPath(s.VariablesValue, variable)
+	if err == nil {
+		variableType, err = resolveType(variableType)
+	}
+	if err != nil {
+		return templateError(fmt.Sprintf("unknown template variable %s", variable), err)
+	}
+	switch variableType.TypeID() {
+	case TypeIDString, TypeIDStringEnum, TypeIDInt, TypeIDIntEnum, TypeIDFloat, TypeIDBool:
+		return nil
+	default:
+		return templateError(fmt.Sprintf(
+			"template variable %s is of type %s, only scalar variables can be used", variable, variableType.TypeID(),
+		), nil)
+	}
+}
+
+func templateError(message string, cause error) *ConstraintError {
+	return &ConstraintError{
+		Message:    message,
+		Cause:      cause,
+		Constraint: ConstraintTemplate,
+	}
+}
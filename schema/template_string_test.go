@@ -0,0 +1,125 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type templateServer struct {
+	Host string `json:"host"`
+	Port int64  `json:"port"`
+}
+
+type templateVariables struct {
+	Name   string         `json:"name"`
+	Server templateServer `json:"server"`
+	Tags   []string       `json:"tags"`
+	Note   *string        `json:"note,omitempty"`
+}
+
+var templateVariablesSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[templateVariables](
+		"variables",
+		map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+			),
+			"server": schema.NewPropertySchema(
+				schema.NewRefSchema("server", nil), nil, true, nil, nil, nil, nil, nil,
+			),
+			"tags": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), nil, nil), nil, true, nil, nil, nil, nil, nil,
+			),
+			"note": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, false, nil, nil, nil, nil, nil,
+			),
+		},
+	),
+	schema.NewStructMappedObjectSchema[templateServer](
+		"server",
+		map[string]*schema.PropertySchema{
+			"host": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+			),
+			"port": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+			),
+		},
+	),
+)
+
+func TestTemplateStringUnserialize(t *testing.T) {
+	templateSchema := schema.NewTemplateStringSchema(templateVariablesSchema)
+	assert.Equals(t, templateSchema.TypeID(), schema.TypeIDString)
+
+	valid := []string{
+		"",
+		"no placeholders",
+		"Hello {{name}}!",
+		"{{ server.host }}:{{ server.port }}",
+		"{{/server/host}}",
+		`literal \{{name}}`,
+	}
+	for _, template := range valid {
+		unserialized := assert.NoErrorR[any](t)(templateSchema.Unserialize(template))
+		assert.Equals(t, unserialized.(string), template)
+	}
+
+	invalid := map[string]string{
+		"Hello {{name":        "unterminated placeholder at offset 6",
+		"Hello {{ }}":         "invalid placeholder at offset 6",
+		"Hello {{ {{name}}":   "invalid placeholder at offset 6",
+		"Hello {{nmae}}":      "unknown template variable nmae",
+		"{{server.hostname}}": "unknown template variable server.hostname",
+		"{{server}}":          "template variable server is of type object",
+		"{{tags}}":            "template variable tags is of type list",
+	}
+	for template, expected := range invalid {
+		_, err := templateSchema.Unserialize(template)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), expected)
+		var constraintErr *schema.ConstraintError
+		assert.Equals(t, errors.As(err, &constraintErr), true)
+		assert.Equals(t, constraintErr.Constraint, schema.ConstraintTemplate)
+	}
+
+	// The template is checked wherever the string is used, for example in the input of a step.
+	type notification struct {
+		Message string `json:"message"`
+	}
+	input := schema.NewStructMappedObjectSchema[notification]("notification", map[string]*schema.PropertySchema{
+		"message": schema.NewPropertySchema(templateSchema, nil, true, nil, nil, nil, nil, nil),
+	})
+	_, err := input.Unserialize(map[string]any{"message": "{{nmae}}"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'message'")
+	assert.Error(t, input.Validate(notification{"{{nmae}}"}))
+	assert.NoError(t, input.Validate(notification{"{{name}}"}))
+}
+
+func TestTemplateStringRender(t *testing.T) {
+	templateSchema := schema.NewTemplateStringSchema(templateVariablesSchema)
+	variables := templateVariables{
+		Name:   "{{name}}",
+		Server: templateServer{Host: "example.com", Port: 8080},
+	}
+	rendered := assert.NoErrorR[string](t)(templateSchema.Render(
+		`Hello {{ name }}, \{{ is served by {{server.host}}:{{server.port}}`, variables,
+	))
+	// Values are inserted as they are, even if they look like placeholders.
+	assert.Equals(t, rendered, "Hello {{name}}, {{ is served by example.com:8080")
+
+	serialized := map[string]any{"name": "world", "server": map[string]any{"host": "h", "port": 1}, "tags": []any{}}
+	rendered = assert.NoErrorR[string](t)(templateSchema.Render("Hello {{name}}", serialized))
+	assert.Equals(t, rendered, "Hello world")
+
+	_, err := templateSchema.Render("{{note}}", variables)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not set")
+
+	_, err = templateSchema.Render("{{nmae}}", variables)
+	assert.Error(t, err)
+}
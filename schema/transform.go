@@ -0,0 +1,132 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// TransformID identifies a declarative value transformation.
+type TransformID string
+
+const (
+	// TransformTrim removes leading and trailing whitespace from strings.
+	TransformTrim TransformID = "trim"
+	// TransformLowercase converts strings to lower case.
+	TransformLowercase TransformID = "lowercase"
+	// TransformUppercase converts strings to upper case.
+	TransformUppercase TransformID = "uppercase"
+	// TransformClamp limits numbers to the range between Min and Max (inclusive).
+	TransformClamp TransformID = "clamp"
+)
+
+// Transform is a named transformation that is applied to a property value before it is validated. Transformations
+// are part of the schema, so UIs can apply them as well. Values the transformation does not apply to, such as
+// numbers for a string transformation, are left unchanged.
+type Transform struct {
+	ID TransformID `json:"id"`
+	// Min is the lower bound for the clamp transformation.
+	Min *float64 `json:"min"`
+	// Max is the upper bound for the clamp transformation.
+	Max *float64 `json:"max"`
+}
+
+// TrimTransform creates a transformation that removes leading and trailing whitespace.
+func TrimTransform() Transform {
+	return Transform{ID: TransformTrim}
+}
+
+// LowercaseTransform creates a transformation that converts strings to lower case.
+func LowercaseTransform() Transform {
+	return Transform{ID: TransformLowercase}
+}
+
+// UppercaseTransform creates a transformation that converts strings to upper case.
+func UppercaseTransform() Transform {
+	return Transform{ID: TransformUppercase}
+}
+
+// ClampTransform creates a transformation that limits numbers to the given range. Either bound may be nil.
+func ClampTransform(minValue *float64, maxValue *float64) Transform {
+	return Transform{ID: TransformClamp, Min: minValue, Max: maxValue}
+}
+
+func (t Transform) validate() error {
+	switch t.ID {
+	case TransformTrim, TransformLowercase, TransformUppercase:
+		return nil
+	case TransformClamp:
+		if t.Min == nil && t.Max == nil {
+			return fmt.Errorf("the clamp transformation needs a minimum, a maximum, or both")
+		}
+		if t.Min != nil && t.Max != nil && *t.Min > *t.Max {
+			return fmt.Errorf("the clamp minimum %f is larger than the maximum %f", *t.Min, *t.Max)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown transformation: %s", t.ID)
+	}
+}
+
+// Apply returns the transformed value. The result has the same type as the input.
+func (t Transform) Apply(value any) any {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return value
+	}
+	var result reflect.Value
+	switch {
+	case v.Kind() == reflect.String:
+		result = reflect.ValueOf(t.applyString(v.String()))
+	case v.CanInt() && t.ID == TransformClamp:
+		result = reflect.ValueOf(t.clampInt(v.Int()))
+	case v.CanUint() && t.ID == TransformClamp:
+		result = reflect.ValueOf(uint64(math.Max(0, t.clampFloat(float64(v.Uint())))))
+	case v.CanFloat() && t.ID == TransformClamp:
+		result = reflect.ValueOf(t.clampFloat(v.Float()))
+	default:
+		return value
+	}
+	return result.Convert(v.Type()).Interface()
+}
+
+func (t Transform) applyString(value string) string {
+	switch t.ID {
+	case TransformTrim:
+		return strings.TrimSpace(value)
+	case TransformLowercase:
+		return strings.ToLower(value)
+	case TransformUppercase:
+		return strings.ToUpper(value)
+	default:
+		return value
+	}
+}
+
+func (t Transform) clampFloat(value float64) float64 {
+	if t.Min != nil && value < *t.Min {
+		return *t.Min
+	}
+	if t.Max != nil && value > *t.Max {
+		return *t.Max
+	}
+	return value
+}
+
+func (t Transform) clampInt(value int64) int64 {
+	if t.Min != nil && float64(value) < *t.Min {
+		return int64(math.Ceil(*t.Min))
+	}
+	if t.Max != nil && float64(value) > *t.Max {
+		return int64(math.Floor(*t.Max))
+	}
+	return value
+}
+
+func applyTransforms(transforms []Transform, value any) any {
+	for _, transform := range transforms {
+		value = transform.Apply(value)
+	}
+	return value
+}
@@ -0,0 +1,117 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type transformTestString string
+
+func TestTransformApply(t *testing.T) {
+	testCases := map[string]struct {
+		transform schema.Transform
+		input     any
+		expected  any
+	}{
+		"trim":               {schema.TrimTransform(), " a b ", "a b"},
+		"lowercase":          {schema.LowercaseTransform(), "AbC", "abc"},
+		"uppercase":          {schema.UppercaseTransform(), "AbC", "ABC"},
+		"custom-string":      {schema.LowercaseTransform(), transformTestString("A"), transformTestString("a")},
+		"non-string":         {schema.TrimTransform(), int64(1), int64(1)},
+		"nil":                {schema.TrimTransform(), nil, nil},
+		"clamp-int-min":      {schema.ClampTransform(schema.PointerTo(1.5), nil), int64(0), int64(2)},
+		"clamp-int-max":      {schema.ClampTransform(nil, schema.PointerTo(10.0)), 11, 10},
+		"clamp-uint":         {schema.ClampTransform(nil, schema.PointerTo(10.0)), uint8(11), uint8(10)},
+		"clamp-float":        {schema.ClampTransform(schema.PointerTo(0.5), schema.PointerTo(1.0)), 0.1, 0.5},
+		"clamp-within-range": {schema.ClampTransform(schema.PointerTo(0.0), schema.PointerTo(1.0)), 0.1, 0.1},
+		"clamp-string":       {schema.ClampTransform(schema.PointerTo(0.0), nil), "-1", "-1"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equals(t, testCase.transform.Apply(testCase.input), testCase.expected)
+		})
+	}
+}
+
+func newTransformTestProperty() *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+			"tls": {NameValue: schema.PointerTo("TLS")},
+		}),
+		nil,
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	).Transform(schema.TrimTransform(), schema.LowercaseTransform())
+}
+
+func TestPropertyTransform(t *testing.T) {
+	property := newTransformTestProperty()
+	unserialized := assert.NoErrorR[any](t)(property.Unserialize(" TLS\n"))
+	assert.Equals(t, unserialized.(string), "tls")
+
+	clamped := schema.NewPropertySchema(
+		schema.NewIntSchema(schema.IntPointer(1), schema.IntPointer(10), nil),
+		nil,
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	).Transform(schema.ClampTransform(schema.PointerTo(1.0), schema.PointerTo(10.0)))
+	unserialized = assert.NoErrorR[any](t)(clamped.Unserialize(100))
+	assert.Equals(t, unserialized.(int64), int64(10))
+	// Validation checks the value as-is.
+	assert.Error(t, clamped.Validate(int64(100)))
+	_, err := clamped.Serialize(int64(100))
+	assert.Error(t, err)
+	serialized := assert.NoErrorR[any](t)(clamped.TransformSerialized().Serialize(int64(100)))
+	assert.Equals(t, serialized.(int64), int64(10))
+}
+
+func TestPropertyTransformInObject(t *testing.T) {
+	s := schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"mode": newTransformTestProperty(),
+	}).UseDecodePlan()
+	unserialized := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{"mode": " Tls"}))
+	assert.Equals(t, unserialized.(map[string]any), map[string]any{"mode": "tls"})
+	unserialized = assert.NoErrorR[any](t)(schema.UnserializeAll(s, map[string]any{"mode": " Tls"}))
+	assert.Equals(t, unserialized.(map[string]any), map[string]any{"mode": "tls"})
+}
+
+func TestPropertyTransformInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		newTransformTestProperty().Transform(schema.Transform{ID: "reverse"})
+	})
+	assert.Panics(t, func() {
+		newTransformTestProperty().Transform(schema.ClampTransform(nil, nil))
+	})
+	assert.Panics(t, func() {
+		newTransformTestProperty().Transform(schema.ClampTransform(schema.PointerTo(2.0), schema.PointerTo(1.0)))
+	})
+}
+
+func TestPropertyTransformSelfSerialization(t *testing.T) {
+	scope := schema.NewScopeSchema(schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+		"mode": newTransformTestProperty(),
+	}))
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	property := unserialized.(*schema.ScopeSchema).Objects()["test"].Properties()["mode"]
+	assert.Equals(t, property.Transforms(), []schema.Transform{schema.TrimTransform(), schema.LowercaseTransform()})
+}
+
+func TestPropertyTransformSelfSchemaInvalid(t *testing.T) {
+	transformSchema := schema.DescribeScope().Objects()["Transform"]
+	assert.NoErrorR[any](t)(transformSchema.Unserialize(map[string]any{"id": "clamp", "min": 1.0, "max": 2.0}))
+	_, err := transformSchema.Unserialize(map[string]any{"id": "clamp", "min": 2.0, "max": 1.0})
+	assert.Error(t, err)
+	_, err = transformSchema.Unserialize(map[string]any{"id": "clamp"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// TruncationPolicy limits the size of the strings and lists in a step output, so a step producing more output than
+// expected does not flood the engine. The ATP server truncates the serialized output according to the policy of the
+// output and reports each truncated value to the client. Truncated outputs may no longer satisfy the minimum length
+// constraints of their schema.
+type TruncationPolicy struct {
+	// MaxStringLength is the number of bytes strings are truncated to. Strings are cut at a character boundary, so
+	// they may end up slightly shorter. If nil, strings are not truncated.
+	MaxStringLength *int64 `json:"max_string_length"`
+	// MaxListItems is the number of items lists are truncated to. If nil, lists are not truncated.
+	MaxListItems *int64 `json:"max_list_items"`
+}
+
+// Truncation describes a value that was truncated according to a TruncationPolicy.
+type Truncation struct {
+	// Path is the JSON pointer of the value in the output, as accepted by ValueAtPath.
+	Path string `json:"path"`
+	// Truncated is always true. It marks the value as truncated for consumers that only look at this field.
+	Truncated bool `json:"truncated"`
+	// OriginalSize is the length of the value before it was truncated, in bytes for strings and items for lists.
+	OriginalSize int64 `json:"original_size"`
+}
+
+// Apply returns a copy of the serialized data with the strings and lists that exceed the limits truncated, and the
+// truncations sorted by path. The data is returned as is if nothing was truncated.
+func (p TruncationPolicy) Apply(serialized any) (any, []Truncation) {
+	var truncations []Truncation
+	result := p.truncate(reflect.ValueOf(serialized), "", &truncations)
+	if len(truncations) == 0 {
+		return serialized, nil
+	}
+	sort.Slice(truncations, func(i, j int) bool {
+		return truncations[i].Path < truncations[j].Path
+	})
+	return result.Interface(), truncations
+}
+
+func (p TruncationPolicy) truncate(value reflect.Value, path string, truncations *[]Truncation) reflect.Value {
+	if !value.IsValid() {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		return p.truncate(value.Elem(), path, truncations)
+	case reflect.String:
+		data := value.String()
+		if p.MaxStringLength == nil || int64(len(data)) <= *p.MaxStringLength {
+			return value
+		}
+		*truncations = append(*truncations, Truncation{path, true, int64(len(data))})
+		cut := int(*p.MaxStringLength)
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		return reflect.ValueOf(data[:cut]).Convert(value.Type())
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are binary data, not lists.
+			return value
+		}
+		length := value.Len()
+		if p.MaxListItems != nil && int64(length) > *p.MaxListItems {
+			*truncations = append(*truncations, Truncation{path, true, int64(length)})
+			length = int(*p.MaxListItems)
+		}
+		result := reflect.MakeSlice(value.Type(), length, length)
+		for i := 0; i < length; i++ {
+			result.Index(i).Set(p.truncate(value.Index(i), fmt.Sprintf("%s/%d", path, i), truncations))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			itemPath := path + "/" + jsonPointerEscaper.Replace(fmt.Sprintf("%v", iterator.Key().Interface()))
+			item := p.truncate(iterator.Value(), itemPath, truncations)
+			if !item.IsValid() {
+				item = reflect.Zero(value.Type().Elem())
+			}
+			result.SetMapIndex(iterator.Key(), item)
+		}
+		return result
+	default:
+		return value
+	}
+}
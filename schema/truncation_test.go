@@ -0,0 +1,68 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestTruncationPolicyApply(t *testing.T) {
+	policy := schema.TruncationPolicy{
+		MaxStringLength: schema.PointerTo(int64(4)),
+		MaxListItems:    schema.PointerTo(int64(2)),
+	}
+	data := map[string]any{
+		"short": "abc",
+		"long":  "abcdefgh",
+		"utf8":  "aéé",
+		"items": []any{"x", "yyyyyy", "z"},
+		"nested": map[any]any{
+			"a/b": []any{int64(1)},
+		},
+		"empty": nil,
+	}
+	truncated, truncations := policy.Apply(data)
+	assert.Equals(t, truncated, any(map[string]any{
+		"short": "abc",
+		"long":  "abcd",
+		"utf8":  "aé",
+		"items": []any{"x", "yyyy"},
+		"nested": map[any]any{
+			"a/b": []any{int64(1)},
+		},
+		"empty": nil,
+	}))
+	assert.Equals(t, truncations, []schema.Truncation{
+		{Path: "/items", Truncated: true, OriginalSize: 3},
+		{Path: "/items/1", Truncated: true, OriginalSize: 6},
+		{Path: "/long", Truncated: true, OriginalSize: 8},
+		{Path: "/utf8", Truncated: true, OriginalSize: 5},
+	})
+	// The original data is left as is.
+	assert.Equals(t, data["long"], any("abcdefgh"))
+}
+
+func TestTruncationPolicyApplyUnchanged(t *testing.T) {
+	policy := schema.TruncationPolicy{MaxStringLength: schema.PointerTo(int64(10))}
+	data := map[string]any{"items": []any{"a", "b", "c"}}
+	truncated, truncations := policy.Apply(data)
+	assert.Equals(t, truncated, any(data))
+	assert.Equals(t, len(truncations), 0)
+}
+
+func TestStepOutputTruncationSelfSerialize(t *testing.T) {
+	output := schema.NewStepOutputSchema(
+		schema.NewScopeSchema(schema.NewObjectSchema("output", map[string]*schema.PropertySchema{})),
+		nil,
+		false,
+	).Truncate(schema.TruncationPolicy{MaxListItems: schema.PointerTo(int64(100))})
+	serialized := assert.NoErrorR[any](t)(schema.DescribeStepOutput().Serialize(output))
+	assert.Equals(
+		t,
+		serialized.(map[string]any)["truncation"],
+		any(map[string]any{"max_list_items": int64(100)}),
+	)
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeStepOutput().Unserialize(serialized))
+	assert.Equals(t, *unserialized.(*schema.StepOutputSchema).Truncation().MaxListItems, int64(100))
+}
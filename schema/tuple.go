@@ -0,0 +1,313 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewTupleSchema creates a schema for a fixed-length list whose items each have their own type, such as a
+// [string, int] coordinate pair. The data is unserialized into a []any with one element per item type.
+func NewTupleSchema(items ...Type) *TupleSchema {
+	return &TupleSchema{
+		ItemsValue: items,
+	}
+}
+
+// NewStructMappedTupleSchema creates a tuple schema like NewTupleSchema that unserializes into T instead. T must be
+// either a struct with one exported field per item type, in order, or an array with one element per item type. The
+// unserialized items must be assignable to the fields or elements.
+func NewStructMappedTupleSchema[T any](items ...Type) *TupleSchema {
+	var defaultValue T
+	reflectedType := reflect.TypeOf(&defaultValue).Elem()
+	t := &TupleSchema{
+		ItemsValue:    items,
+		reflectedType: reflectedType,
+	}
+	switch reflectedType.Kind() {
+	case reflect.Struct:
+		t.fields = mapTupleStructFields(reflectedType, items)
+	case reflect.Array:
+		checkTupleArrayItems(reflectedType, items)
+	default:
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"tuples can only be mapped to structs or arrays, %s given",
+				reflectedType.String(),
+			),
+		})
+	}
+	return t
+}
+
+// mapTupleStructFields returns the indexes of the exported fields of reflectedType the tuple items map to, in order.
+func mapTupleStructFields(reflectedType reflect.Type, items []Type) []int {
+	var fields []int
+	for i := 0; i < reflectedType.NumField(); i++ {
+		if reflectedType.Field(i).IsExported() {
+			fields = append(fields, i)
+		}
+	}
+	if len(fields) != len(items) {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"%s has %d exported fields, but the tuple has %d items",
+				reflectedType.String(),
+				len(fields),
+				len(items),
+			),
+		})
+	}
+	for i, item := range items {
+		field := reflectedType.Field(fields[i])
+		if !item.ReflectedType().AssignableTo(field.Type) {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf(
+					"item %d of the tuple unserializes to %s, which cannot be assigned to field %s of type %s",
+					i,
+					item.ReflectedType().String(),
+					field.Name,
+					field.Type.String(),
+				),
+			})
+		}
+	}
+	return fields
+}
+
+// checkTupleArrayItems checks that the tuple items can be assigned to the elements of the array type reflectedType.
+func checkTupleArrayItems(reflectedType reflect.Type, items []Type) {
+	if reflectedType.Len() != len(items) {
+		panic(BadArgumentError{
+			Message: fmt.Sprintf(
+				"%s has %d elements, but the tuple has %d items",
+				reflectedType.String(),
+				reflectedType.Len(),
+				len(items),
+			),
+		})
+	}
+	for i, item := range items {
+		if !item.ReflectedType().AssignableTo(reflectedType.Elem()) {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf(
+					"item %d of the tuple unserializes to %s, which cannot be assigned to %s",
+					i,
+					item.ReflectedType().String(),
+					reflectedType.Elem().String(),
+				),
+			})
+		}
+	}
+}
+
+// TupleSchema describes a fixed-length list whose items each have their own type. Each position is validated against
+// its own item type.
+type TupleSchema struct {
+	ItemsValue []Type `json:"items"`
+
+	// reflectedType is the struct or array the tuple is mapped to, or nil for a []any.
+	reflectedType reflect.Type
+	// fields holds the indexes of the exported struct fields in item order if the tuple is mapped to a struct.
+	fields []int
+}
+
+func (t *TupleSchema) TypeID() TypeID {
+	return TypeIDTuple
+}
+
+// Items returns the item types of the tuple in order.
+func (t *TupleSchema) Items() []Type {
+	return t.ItemsValue
+}
+
+func (t *TupleSchema) ReflectedType() reflect.Type {
+	if t.reflectedType == nil {
+		return reflect.TypeOf([]any{})
+	}
+	return t.reflectedType
+}
+
+func (t *TupleSchema) ApplyNamespace(objects map[string]*ObjectSchema, namespace string) {
+	for _, item := range t.ItemsValue {
+		applyNamespace(item, objects, namespace)
+	}
+}
+
+func (t *TupleSchema) ValidateReferences() error {
+	for i, item := range t.ItemsValue {
+		if err := item.ValidateReferences(); err != nil {
+			return fmt.Errorf("invalid reference in tuple item %d (%w)", i, err)
+		}
+	}
+	return nil
+}
+
+func (t *TupleSchema) ApplyDefaults(serialized any) any {
+	v := reflect.ValueOf(serialized)
+	if v.Kind() != reflect.Slice || v.Len() != len(t.ItemsValue) {
+		return serialized
+	}
+	result := make([]any, v.Len())
+	for i, item := range t.ItemsValue {
+		result[i] = ApplyDefaults(item, v.Index(i).Interface())
+	}
+	return result
+}
+
+func (t *TupleSchema) Unserialize(data any) (any, error) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, &ConstraintError{
+			Message:    fmt.Sprintf("Must be a slice, %T given", data),
+			Constraint: ConstraintDataType,
+			Expected:   "list",
+			Actual:     fmt.Sprintf("%T", data),
+		}
+	}
+	if err := t.validateLength(v.Len()); err != nil {
+		return nil, err
+	}
+	items := make([]any, len(t.ItemsValue))
+	for i, item := range t.ItemsValue {
+		unserialized, err := item.Unserialize(v.Index(i).Interface())
+		if err != nil {
+			return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+		}
+		items[i] = unserialized
+	}
+	return t.build(items), nil
+}
+
+func (t *TupleSchema) Validate(data any) error {
+	items, err := t.elements(data)
+	if err != nil {
+		return err
+	}
+	for i, item := range t.ItemsValue {
+		if err := item.Validate(items[i]); err != nil {
+			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return nil
+}
+
+func (t *TupleSchema) ValidateCompatibility(typeOrData any) error {
+	if schemaType, ok := typeOrData.(*TupleSchema); ok {
+		if len(schemaType.ItemsValue) != len(t.ItemsValue) {
+			return &ConstraintError{
+				Message: fmt.Sprintf(
+					"tuple with %d items is not compatible with a tuple with %d items",
+					len(schemaType.ItemsValue),
+					len(t.ItemsValue),
+				),
+			}
+		}
+		for i, item := range t.ItemsValue {
+			if err := item.ValidateCompatibility(schemaType.ItemsValue[i]); err != nil {
+				return ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+			}
+		}
+		return nil
+	}
+	v := reflect.ValueOf(typeOrData)
+	if v.Kind() != reflect.Slice {
+		return &ConstraintError{
+			Message: fmt.Sprintf("unsupported data type for 'tuple' type: %T. Is not list or tuple schema",
+				typeOrData),
+		}
+	}
+	if err := t.validateLength(v.Len()); err != nil {
+		return err
+	}
+	for i, item := range t.ItemsValue {
+		if err := item.ValidateCompatibility(v.Index(i).Interface()); err != nil {
+			return ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return nil
+}
+
+func (t *TupleSchema) Serialize(data any) (any, error) {
+	items, err := t.elements(data)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, len(t.ItemsValue))
+	for i, item := range t.ItemsValue {
+		serialized, err := item.Serialize(items[i])
+		if err != nil {
+			return nil, ConstraintErrorAddPathSegment(err, fmt.Sprintf("[%d]", i))
+		}
+		result[i] = serialized
+	}
+	return result, nil
+}
+
+func (t *TupleSchema) validateLength(length int) error {
+	switch {
+	case length < len(t.ItemsValue):
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must have exactly %d items, %d given", len(t.ItemsValue), length),
+			Constraint: ConstraintMin,
+			Expected:   int64(len(t.ItemsValue)),
+			Actual:     int64(length),
+		}
+	case length > len(t.ItemsValue):
+		return &ConstraintError{
+			Message:    fmt.Sprintf("Must have exactly %d items, %d given", len(t.ItemsValue), length),
+			Constraint: ConstraintMax,
+			Expected:   int64(len(t.ItemsValue)),
+			Actual:     int64(length),
+		}
+	}
+	return nil
+}
+
+// build creates the unserialized tuple from the unserialized items.
+func (t *TupleSchema) build(items []any) any {
+	if t.reflectedType == nil {
+		return items
+	}
+	result := reflect.New(t.reflectedType).Elem()
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		if t.fields != nil {
+			result.Field(t.fields[i]).Set(reflect.ValueOf(item))
+		} else {
+			result.Index(i).Set(reflect.ValueOf(item))
+		}
+	}
+	return result.Interface()
+}
+
+// elements returns the items of unserialized tuple data, which is either a slice or array, or the struct the tuple
+// is mapped to.
+func (t *TupleSchema) elements(data any) ([]any, error) {
+	v := reflect.ValueOf(data)
+	switch {
+	case t.fields != nil && v.Kind() == reflect.Struct && v.Type() == t.reflectedType:
+		items := make([]any, len(t.fields))
+		for i, field := range t.fields {
+			items[i] = v.Field(field).Interface()
+		}
+		return items, nil
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		if err := t.validateLength(v.Len()); err != nil {
+			return nil, err
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+		return items, nil
+	default:
+		return nil, &ConstraintError{
+			Message:    fmt.Sprintf("%T is not a valid data type for a tuple schema.", data),
+			Constraint: ConstraintDataType,
+			Expected:   "tuple",
+			Actual:     fmt.Sprintf("%T", data),
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type coordinate struct {
+	Label string
+	Value int64
+}
+
+func TestTupleUnserialize(t *testing.T) {
+	tupleType := schema.NewTupleSchema(
+		schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+		schema.NewIntSchema(nil, schema.IntPointer(10), nil),
+	)
+	assert.Equals(t, tupleType.TypeID(), schema.TypeIDTuple)
+	assert.Equals(t, len(tupleType.Items()), 2)
+
+	unserialized := assert.NoErrorR[any](t)(tupleType.Unserialize([]any{"x", 5}))
+	assert.Equals(t, unserialized.([]any), []any{"x", int64(5)})
+	assert.NoError(t, tupleType.Validate(unserialized))
+	serialized := assert.NoErrorR[any](t)(tupleType.Serialize(unserialized))
+	assert.Equals(t, serialized.([]any), []any{"x", int64(5)})
+
+	_, err := tupleType.Unserialize([]any{"x"})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintMin)
+	_, err = tupleType.Unserialize([]any{"x", 5, 6})
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintMax)
+	_, err = tupleType.Unserialize([]any{"x", 11})
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path, []string{"[1]"})
+	assert.ErrorR(t)(tupleType.Unserialize("x"))
+	assert.Error(t, tupleType.Validate([]any{"", int64(5)}))
+}
+
+func TestTupleStructMapped(t *testing.T) {
+	tupleType := schema.NewStructMappedTupleSchema[coordinate](
+		schema.NewStringSchema(nil, nil, nil),
+		schema.NewIntSchema(nil, nil, nil),
+	)
+	unserialized := assert.NoErrorR[any](t)(tupleType.Unserialize([]any{"x", 5}))
+	assert.Equals(t, unserialized.(coordinate), coordinate{"x", 5})
+	assert.NoError(t, tupleType.Validate(coordinate{"y", 6}))
+	serialized := assert.NoErrorR[any](t)(tupleType.Serialize(coordinate{"y", 6}))
+	assert.Equals(t, serialized.([]any), []any{"y", int64(6)})
+	assert.Error(t, tupleType.Validate(struct{ A string }{"x"}))
+}
+
+func TestTupleArrayMapped(t *testing.T) {
+	tupleType := schema.NewStructMappedTupleSchema[[2]int64](
+		schema.NewIntSchema(nil, nil, nil),
+		schema.NewIntSchema(schema.IntPointer(0), nil, nil),
+	)
+	unserialized := assert.NoErrorR[any](t)(tupleType.Unserialize([]any{-1, 1}))
+	assert.Equals(t, unserialized.([2]int64), [2]int64{-1, 1})
+	assert.Error(t, tupleType.Validate([2]int64{-1, -1}))
+	serialized := assert.NoErrorR[any](t)(tupleType.Serialize([2]int64{3, 4}))
+	assert.Equals(t, serialized.([]any), []any{int64(3), int64(4)})
+}
+
+func TestTupleStructMappedInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewStructMappedTupleSchema[coordinate](schema.NewStringSchema(nil, nil, nil))
+	})
+	assert.Panics(t, func() {
+		schema.NewStructMappedTupleSchema[coordinate](
+			schema.NewIntSchema(nil, nil, nil),
+			schema.NewIntSchema(nil, nil, nil),
+		)
+	})
+	assert.Panics(t, func() {
+		schema.NewStructMappedTupleSchema[[3]int64](schema.NewIntSchema(nil, nil, nil))
+	})
+	assert.Panics(t, func() {
+		schema.NewStructMappedTupleSchema[string](schema.NewStringSchema(nil, nil, nil))
+	})
+}
+
+func TestTupleUnserializeAll(t *testing.T) {
+	tupleType := schema.NewTupleSchema(
+		schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+		schema.NewIntSchema(nil, schema.IntPointer(10), nil),
+	)
+	_, err := schema.UnserializeAll(tupleType, []any{"", 11})
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 2)
+	assert.Equals(t, errs.Errors[0].Path, []string{"[0]"})
+	assert.Equals(t, errs.Errors[1].Path, []string{"[1]"})
+}
+
+func TestTupleSelfSerialize(t *testing.T) {
+	scope := schema.NewScopeSchema(
+		schema.NewObjectSchema("test", map[string]*schema.PropertySchema{
+			"point": schema.NewPropertySchema(
+				schema.NewTupleSchema(
+					schema.NewStringSchema(nil, nil, nil),
+					schema.NewIntSchema(nil, schema.IntPointer(10), nil),
+				),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+	)
+	serialized := assert.NoErrorR[any](t)(scope.SelfSerialize())
+	unserialized := assert.NoErrorR[any](t)(schema.DescribeScope().Unserialize(serialized))
+	unserializedScope := unserialized.(*schema.ScopeSchema)
+	unserializedScope.ApplySelf()
+	assert.NoErrorR[any](t)(unserializedScope.Unserialize(map[string]any{"point": []any{"x", 5}}))
+	_, err := unserializedScope.Unserialize(map[string]any{"point": []any{"x", 11}})
+	assert.Error(t, err)
+}
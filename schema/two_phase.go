@@ -0,0 +1,259 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	// TwoPhasePlanSignalID is the ID of the signal a two-phase step emits with its plan after the prepare phase.
+	TwoPhasePlanSignalID = "plan"
+	// TwoPhaseConfirmSignalID is the ID of the signal the engine sends to a two-phase step to approve or reject its
+	// plan.
+	TwoPhaseConfirmSignalID = "confirm"
+	// TwoPhaseRejectedOutputID is the ID of the output of a two-phase step whose plan was rejected, or which was
+	// cancelled before the plan was confirmed.
+	TwoPhaseRejectedOutputID = "rejected"
+	// TwoPhasePrepareFailedOutputID is the ID of the output of a two-phase step whose prepare phase failed.
+	TwoPhasePrepareFailedOutputID = "prepare_failed"
+)
+
+// TwoPhaseConfirmation is the data of the confirm signal of a two-phase step.
+type TwoPhaseConfirmation struct {
+	Approve bool   `json:"approve"`
+	Reason  string `json:"reason"`
+}
+
+// TwoPhaseRejected is the output of a two-phase step whose plan was not approved.
+type TwoPhaseRejected struct {
+	Reason string `json:"reason"`
+}
+
+// TwoPhasePrepareFailed is the output of a two-phase step whose prepare phase failed.
+type TwoPhasePrepareFailed struct {
+	Error string `json:"error"`
+}
+
+var twoPhaseConfirmationSchema = NewScopeSchema(
+	NewStructMappedObjectSchema[TwoPhaseConfirmation](
+		"TwoPhaseConfirmation",
+		map[string]*PropertySchema{
+			"approve": NewPropertySchema(
+				NewBoolSchema(),
+				NewDisplayValue(
+					PointerTo("Approve"),
+					PointerTo("Whether the step may commit the plan it emitted."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"reason": NewPropertySchema(
+				NewStringSchema(nil, nil, nil),
+				NewDisplayValue(
+					PointerTo("Reason"),
+					PointerTo("Why the plan was approved or rejected."),
+					nil,
+				),
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+)
+
+var twoPhaseRejectedOutput = NewStepOutputSchema(
+	NewScopeSchema(
+		NewStructMappedObjectSchema[TwoPhaseRejected](
+			"TwoPhaseRejected",
+			map[string]*PropertySchema{
+				"reason": NewPropertySchema(
+					NewStringSchema(nil, nil, nil),
+					NewDisplayValue(PointerTo("Reason"), PointerTo("Why the plan was not committed."), nil),
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+		),
+	),
+	NewDisplayValue(PointerTo("Rejected"), PointerTo("The plan of the step was not approved."), nil),
+	true,
+)
+
+var twoPhasePrepareFailedOutput = NewStepOutputSchema(
+	NewScopeSchema(
+		NewStructMappedObjectSchema[TwoPhasePrepareFailed](
+			"TwoPhasePrepareFailed",
+			map[string]*PropertySchema{
+				"error": NewPropertySchema(
+					NewStringSchema(nil, nil, nil),
+					NewDisplayValue(PointerTo("Error"), PointerTo("The error of the prepare phase."), nil),
+					true,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+				),
+			},
+		),
+	),
+	NewDisplayValue(PointerTo("Prepare failed"), PointerTo("The step failed to create its plan."), nil),
+	true,
+)
+
+// twoPhaseRun is the state shared between the handler and the confirm signal of a single two-phase step run.
+type twoPhaseRun struct {
+	lock          sync.Mutex
+	planEmitted   bool
+	confirmations chan TwoPhaseConfirmation
+}
+
+// NewTwoPhaseStep creates a step that runs in two phases, so the engine can gate destructive operations, such as
+// chaos steps, on an approval. First, prepare creates a plan of what the step is going to do, which is emitted in the
+// plan signal. The step then waits for the confirm signal. If the plan is approved, commit runs with the plan and
+// returns the output of the step. Otherwise, the step finishes with the rejected output, which it also does if it is
+// cancelled while waiting. If prepare fails or returns a plan that doesn't match the plan schema, the step finishes
+// with the prepare_failed output. Confirmations received before the plan is emitted are ignored.
+//
+// The rejected and prepare_failed outputs are added to the outputs, which must not use these IDs themselves. The step
+// must be called through an ATP server, or with a context from ContextWithSignalEmitter, to emit its plan.
+func NewTwoPhaseStep[InputType any, PlanType any](
+	id string,
+	input *ScopeSchema,
+	plan *ScopeSchema,
+	outputs map[string]*StepOutputSchema,
+	display Display,
+	prepare func(context.Context, InputType) (PlanType, error),
+	commit func(context.Context, InputType, PlanType) (string, any),
+) CallableStep {
+	return NewCallableStepWithSignals[*twoPhaseRun, InputType](
+		id,
+		input,
+		twoPhaseOutputs(outputs),
+		map[string]CallableSignal{
+			TwoPhaseConfirmSignalID: newTwoPhaseConfirmSignal(),
+		},
+		map[string]*SignalSchema{
+			TwoPhasePlanSignalID: newTwoPhasePlanSignal(plan),
+		},
+		display,
+		func() *twoPhaseRun {
+			return &twoPhaseRun{
+				confirmations: make(chan TwoPhaseConfirmation, 1),
+			}
+		},
+		func(ctx context.Context, run *twoPhaseRun, input InputType) (string, any) {
+			return runTwoPhase(ctx, run, plan, input, prepare, commit)
+		},
+	)
+}
+
+// twoPhaseOutputs adds the rejected and prepare_failed outputs to the outputs of a two-phase step.
+func twoPhaseOutputs(outputs map[string]*StepOutputSchema) map[string]*StepOutputSchema {
+	allOutputs := make(map[string]*StepOutputSchema, len(outputs)+2)
+	for outputID, output := range outputs {
+		if outputID == TwoPhaseRejectedOutputID || outputID == TwoPhasePrepareFailedOutputID {
+			panic(BadArgumentError{
+				Message: fmt.Sprintf("the output ID %s is reserved for two-phase steps", outputID),
+			})
+		}
+		allOutputs[outputID] = output
+	}
+	allOutputs[TwoPhaseRejectedOutputID] = twoPhaseRejectedOutput
+	allOutputs[TwoPhasePrepareFailedOutputID] = twoPhasePrepareFailedOutput
+	return allOutputs
+}
+
+// newTwoPhaseConfirmSignal creates the confirm signal, which passes the first confirmation after the plan was
+// emitted on to the waiting step.
+func newTwoPhaseConfirmSignal() CallableSignal {
+	return NewCallableSignal(
+		TwoPhaseConfirmSignalID,
+		twoPhaseConfirmationSchema,
+		NewDisplayValue(
+			PointerTo("Confirm"),
+			PointerTo("Approves or rejects the plan of the step."),
+			nil,
+		),
+		func(_ context.Context, run *twoPhaseRun, confirmation TwoPhaseConfirmation) {
+			run.lock.Lock()
+			defer run.lock.Unlock()
+			if !run.planEmitted {
+				return
+			}
+			// Only the first confirmation counts.
+			select {
+			case run.confirmations <- confirmation:
+			default:
+			}
+		},
+	)
+}
+
+// newTwoPhasePlanSignal creates the schema of the plan signal a two-phase step emits.
+func newTwoPhasePlanSignal(plan *ScopeSchema) *SignalSchema {
+	return NewSignalSchema(
+		TwoPhasePlanSignalID,
+		plan,
+		NewDisplayValue(
+			PointerTo("Plan"),
+			PointerTo("What the step is going to do once the plan is approved."),
+			nil,
+		),
+	)
+}
+
+// runTwoPhase prepares and emits the plan, then waits for the confirmation before committing it.
+func runTwoPhase[InputType any, PlanType any](
+	ctx context.Context,
+	run *twoPhaseRun,
+	plan *ScopeSchema,
+	input InputType,
+	prepare func(context.Context, InputType) (PlanType, error),
+	commit func(context.Context, InputType, PlanType) (string, any),
+) (string, any) {
+	stepPlan, err := prepare(ctx, input)
+	if err != nil {
+		return TwoPhasePrepareFailedOutputID, TwoPhasePrepareFailed{Error: err.Error()}
+	}
+	if err := plan.Validate(stepPlan); err != nil {
+		return TwoPhasePrepareFailedOutputID, TwoPhasePrepareFailed{
+			Error: fmt.Sprintf("invalid plan (%v)", err),
+		}
+	}
+	run.lock.Lock()
+	run.planEmitted = true
+	run.lock.Unlock()
+	if err := EmitSignal(ctx, TwoPhasePlanSignalID, stepPlan); err != nil {
+		return TwoPhasePrepareFailedOutputID, TwoPhasePrepareFailed{
+			Error: fmt.Sprintf("failed to emit plan (%v)", err),
+		}
+	}
+	DebugLogf(ctx, "Plan emitted, waiting for confirmation...")
+	select {
+	case confirmation := <-run.confirmations:
+		if !confirmation.Approve {
+			return TwoPhaseRejectedOutputID, TwoPhaseRejected{Reason: confirmation.Reason}
+		}
+		DebugLogf(ctx, "Plan approved, committing...")
+	case <-ctx.Done():
+		return TwoPhaseRejectedOutputID, TwoPhaseRejected{
+			Reason: "the step was cancelled before the plan was confirmed",
+		}
+	}
+	return commit(ctx, input, stepPlan)
+}
@@ -0,0 +1,132 @@
+package schema_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+type twoPhaseTestPlan struct {
+	Targets []string `json:"targets"`
+}
+
+var twoPhaseTestPlanSchema = schema.NewScopeSchema(
+	schema.NewStructMappedObjectSchema[twoPhaseTestPlan](
+		"plan",
+		map[string]*schema.PropertySchema{
+			"targets": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewStringSchema(nil, nil, nil), schema.IntPointer(1), nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+)
+
+func twoPhaseTestStep(prepareErr error) schema.CallableStep {
+	return schema.NewTwoPhaseStep(
+		"delete",
+		testStepSchema.Input().(*schema.ScopeSchema),
+		twoPhaseTestPlanSchema,
+		testStepSchema.Outputs(),
+		nil,
+		func(_ context.Context, input stepTestInputData) (twoPhaseTestPlan, error) {
+			return twoPhaseTestPlan{Targets: []string{input.Name}}, prepareErr
+		},
+		func(_ context.Context, input stepTestInputData, plan twoPhaseTestPlan) (string, any) {
+			return "success", stepTestSuccessOutput{Message: fmt.Sprintf("Deleted %s", plan.Targets[0])}
+		},
+	)
+}
+
+// twoPhaseTestContext returns a context that answers the plan of the step with the confirmation.
+func twoPhaseTestContext(
+	t *testing.T,
+	step schema.CallableStep,
+	confirmation schema.TwoPhaseConfirmation,
+) context.Context {
+	return schema.ContextWithSignalEmitter(context.Background(), func(signalID string, data any) error {
+		assert.Equals(t, signalID, schema.TwoPhasePlanSignalID)
+		assert.Equals(t, data.(twoPhaseTestPlan), twoPhaseTestPlan{Targets: []string{"Arca Lot"}})
+		return step.CallSignal(context.Background(), t.Name(), schema.TwoPhaseConfirmSignalID, confirmation)
+	})
+}
+
+func TestTwoPhaseStepApproved(t *testing.T) {
+	step := twoPhaseTestStep(nil)
+	ctx := twoPhaseTestContext(t, step, schema.TwoPhaseConfirmation{Approve: true})
+	outputID, outputData, err := step.Call(ctx, t.Name(), stepTestInputData{Name: "Arca Lot"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, "success")
+	assert.Equals(t, outputData.(stepTestSuccessOutput).Message, "Deleted Arca Lot")
+}
+
+func TestTwoPhaseStepRejected(t *testing.T) {
+	step := twoPhaseTestStep(nil)
+	ctx := twoPhaseTestContext(t, step, schema.TwoPhaseConfirmation{Approve: false, Reason: "not today"})
+	outputID, outputData, err := step.Call(ctx, t.Name(), stepTestInputData{Name: "Arca Lot"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, schema.TwoPhaseRejectedOutputID)
+	assert.Equals(t, outputData.(schema.TwoPhaseRejected).Reason, "not today")
+}
+
+func TestTwoPhaseStepPrepareFailed(t *testing.T) {
+	step := twoPhaseTestStep(fmt.Errorf("target not found"))
+	ctx := twoPhaseTestContext(t, step, schema.TwoPhaseConfirmation{Approve: true})
+	outputID, outputData, err := step.Call(ctx, t.Name(), stepTestInputData{Name: "Arca Lot"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, schema.TwoPhasePrepareFailedOutputID)
+	assert.Equals(t, outputData.(schema.TwoPhasePrepareFailed).Error, "target not found")
+
+	// Without a signal emitter the plan cannot be confirmed.
+	outputID, _, err = twoPhaseTestStep(nil).Call(context.Background(), t.Name(), stepTestInputData{Name: "Arca Lot"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, schema.TwoPhasePrepareFailedOutputID)
+}
+
+func TestTwoPhaseStepCancelled(t *testing.T) {
+	step := twoPhaseTestStep(nil)
+	// Confirmations sent before the plan are ignored.
+	assert.NoError(t, step.CallSignal(
+		context.Background(),
+		t.Name(),
+		schema.TwoPhaseConfirmSignalID,
+		schema.TwoPhaseConfirmation{Approve: true},
+	))
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = schema.ContextWithSignalEmitter(ctx, func(signalID string, data any) error {
+		cancel()
+		return nil
+	})
+	outputID, _, err := step.Call(ctx, t.Name(), stepTestInputData{Name: "Arca Lot"})
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, schema.TwoPhaseRejectedOutputID)
+}
+
+func TestTwoPhaseStepReservedOutput(t *testing.T) {
+	assert.Panics(t, func() {
+		schema.NewTwoPhaseStep(
+			"delete",
+			testStepSchema.Input().(*schema.ScopeSchema),
+			twoPhaseTestPlanSchema,
+			map[string]*schema.StepOutputSchema{
+				schema.TwoPhaseRejectedOutputID: testStepSchema.Outputs()["error"],
+			},
+			nil,
+			func(_ context.Context, input stepTestInputData) (twoPhaseTestPlan, error) {
+				return twoPhaseTestPlan{}, nil
+			},
+			func(_ context.Context, input stepTestInputData, plan twoPhaseTestPlan) (string, any) {
+				return "success", nil
+			},
+		)
+	})
+}
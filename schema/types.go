@@ -27,6 +27,8 @@ const (
 	TypeIDList TypeID = "list"
 	// TypeIDMap is a type that satisfies the Map.
 	TypeIDMap TypeID = "map"
+	// TypeIDTuple is a type that satisfies the TupleSchema.
+	TypeIDTuple TypeID = "tuple"
 	// TypeIDScope is a type that satisfies the Scope.
 	TypeIDScope TypeID = "scope"
 	// TypeIDObject is a type that satisfies the Object.
@@ -71,7 +73,27 @@ type Serializable interface {
 	ValidateReferences() error
 }
 
-// Type adds the type ID to Serializable as part of the Schema tree.
+// DefaultsApplier is implemented by types that can contain properties with defaults. It is optional, so types
+// implemented outside this package don't need to implement it.
+type DefaultsApplier interface {
+	// ApplyDefaults returns a copy of the serialized data with the defaults of all unset properties filled in
+	// recursively. Data that does not match the schema is returned without changes, so the result must still be
+	// unserialized to be validated.
+	ApplyDefaults(serialized any) any
+}
+
+// ApplyDefaults returns a copy of the serialized data with the defaults of all unset properties filled in recursively
+// if the type implements DefaultsApplier. Otherwise, the data is returned without changes.
+func ApplyDefaults(t Type, serialized any) any {
+	if applier, ok := t.(DefaultsApplier); ok {
+		return applier.ApplyDefaults(serialized)
+	}
+	return serialized
+}
+
+// Type adds the type ID to Serializable as part of the Schema tree. Once a schema is built and its scope is applied,
+// its methods are safe for concurrent use. Types implemented outside the SDK can check that they behave like the
+// built-in ones with schematest.TestTypeCompliance.
 type Type interface {
 	Serializable
 
@@ -102,6 +124,40 @@ func (s *ScalarType) ValidateReferences() error {
 	return nil // Scalar types have no references, so no work to do.
 }
 
+// copySerializedValue creates a deep copy of the maps and slices in serialized data, so the result can be modified
+// without affecting the original.
+func copySerializedValue(value any) any {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		result := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key := iter.Key()
+			item := iter.Value()
+			copied := reflect.ValueOf(copySerializedValue(item.Interface()))
+			if !copied.IsValid() {
+				copied = reflect.Zero(v.Type().Elem())
+			}
+			result.SetMapIndex(key, copied)
+		}
+		return result.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return value
+		}
+		result := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied := reflect.ValueOf(copySerializedValue(v.Index(i).Interface()))
+			if copied.IsValid() {
+				result.Index(i).Set(copied)
+			}
+		}
+		return result.Interface()
+	default:
+		return value
+	}
+}
+
 // MapKeyType are types that can be used as map keys.
 type MapKeyType interface {
 	int64 | string
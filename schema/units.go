@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -74,20 +75,20 @@ func (u *UnitDefinition) FormatLongFloat(amount float64, displayZero bool) strin
 }
 
 func formatNumberUnitShort[T NumberType](amount T, unit *UnitDefinition, displayZero bool) string {
-	var formatString string
-	switch any(amount).(type) {
+	var formatted string
+	switch number := any(amount).(type) {
 	case int64:
-		formatString = "%d"
+		formatted = fmt.Sprintf("%d", number)
 	case float64:
-		formatString = "%f"
+		formatted = formatDecimal(number, 6)
 	}
 	switch {
 	case amount == 1 || amount == -1:
-		return strings.TrimRight(fmt.Sprintf(formatString, amount), "0.") + unit.NameShortSingular()
+		return formatted + unit.NameShortSingular()
 	case amount != 0:
-		return strings.TrimRight(fmt.Sprintf(formatString, amount), "0.") + unit.NameShortPlural()
+		return formatted + unit.NameShortPlural()
 	case displayZero:
-		return strings.TrimRight(fmt.Sprintf(formatString, amount), "0.") + unit.NameShortPlural()
+		return formatted + unit.NameShortPlural()
 	default:
 		return ""
 	}
@@ -136,11 +137,22 @@ func NewUnits(baseUnit *UnitDefinition, multipliers map[int64]*UnitDefinition) *
 }
 
 type UnitsDefinition struct {
-	BaseUnitValue          *UnitDefinition           `json:"base_unit"`
-	MultipliersValue       map[int64]*UnitDefinition `json:"multipliers"`
-	sortedMultipliersCache []int64
-	reCache                *regexp.Regexp
-	reSubExpNames          map[string]int
+	BaseUnitValue    *UnitDefinition           `json:"base_unit"`
+	MultipliersValue map[int64]*UnitDefinition `json:"multipliers"`
+	// VersionValue is the version of the serialized form. Nil means version 1.
+	VersionValue *int64 `json:"version,omitempty"`
+
+	// cache holds the values derived from the multipliers. It is built on first use, so units can be used from
+	// several goroutines at once.
+	cache atomic.Pointer[unitsCache]
+}
+
+// unitsCache holds the values derived from the units that parsing and formatting need on every call. It is not
+// modified after it is built.
+type unitsCache struct {
+	sortedMultipliers []int64
+	re                *regexp.Regexp
+	reSubExpNames     map[string]int
 }
 
 func (u *UnitsDefinition) BaseUnit() *UnitDefinition {
@@ -151,6 +163,19 @@ func (u *UnitsDefinition) Multipliers() map[int64]*UnitDefinition {
 	return u.MultipliersValue
 }
 
+// UnitsFormatVersion is the version of the serialized form of units this SDK understands. Like DisplayFormatVersion,
+// it is only serialized if it is above 1, and readers ignore the fields they don't know.
+const UnitsFormatVersion int64 = 1
+
+// Version returns the version of the serialized form of the units. A version above UnitsFormatVersion means the units
+// were written by a newer SDK and may have held fields that were ignored.
+func (u *UnitsDefinition) Version() int64 {
+	if u.VersionValue == nil {
+		return 1
+	}
+	return *u.VersionValue
+}
+
 // FormatShortInt formats the passed int according to the UnitDefinition multipliers.
 func (u *UnitsDefinition) FormatShortInt(data int64) string {
 	if data == 0 {
@@ -216,17 +241,18 @@ func (u *UnitsDefinition) FormatLongFloat(data float64) string {
 }
 
 func (u *UnitsDefinition) getSortedMultipliersCache() []int64 {
-	if u.sortedMultipliersCache == nil {
-		var multipliers []int64
-		for multiplier := range u.MultipliersValue {
-			multipliers = append(multipliers, multiplier)
-		}
-		sort.SliceStable(multipliers, func(i, j int) bool {
-			return multipliers[i] > multipliers[j]
-		})
-		u.sortedMultipliersCache = multipliers
+	return u.getCache().sortedMultipliers
+}
+
+// getCache returns the derived values of the units, building them if this is the first use.
+func (u *UnitsDefinition) getCache() *unitsCache {
+	if cache := u.cache.Load(); cache != nil {
+		return cache
 	}
-	return u.sortedMultipliersCache
+	// Concurrent first uses may both build the cache, which is harmless since the results are the same.
+	cache := u.newCache()
+	u.cache.Store(cache)
+	return cache
 }
 
 func (u *UnitsDefinition) parse(data string) (any, error) {
@@ -236,10 +262,8 @@ func (u *UnitsDefinition) parse(data string) (any, error) {
 			Message: "Empty string cannot be parsed as " + u.BaseUnitValue.NameLongPlural(),
 		}
 	}
-	if u.reCache == nil {
-		u.updateReCache()
-	}
-	match := u.reCache.FindStringSubmatch(data)
+	cache := u.getCache()
+	match := cache.re.FindStringSubmatch(data)
 	if match == nil {
 		return u.buildUnitParseError(data)
 	}
@@ -248,8 +272,8 @@ func (u *UnitsDefinition) parse(data string) (any, error) {
 	var floatNumber float64
 	var intNumber int64
 	var err error
-	for _, multiplier := range u.getSortedMultipliersCache() {
-		matchGroupID := u.reSubExpNames[fmt.Sprintf("g%d", multiplier)]
+	for _, multiplier := range cache.sortedMultipliers {
+		matchGroupID := cache.reSubExpNames[fmt.Sprintf("g%d", multiplier)]
 		result := match[matchGroupID]
 
 		intNumber, floatNumber, isFloat, err = u.handleParseMultiplier(
@@ -263,7 +287,7 @@ func (u *UnitsDefinition) parse(data string) (any, error) {
 			return 0, err
 		}
 	}
-	baseMatchGroup := match[u.reSubExpNames["g1"]]
+	baseMatchGroup := match[cache.reSubExpNames["g1"]]
 	intNumber, floatNumber, isFloat, err = u.handleParseMultiplier(
 		baseMatchGroup,
 		1,
@@ -314,10 +338,17 @@ func (u *UnitsDefinition) handleParseMultiplier(
 	return intNumber, floatNumber, isFloat, nil
 }
 
-func (u *UnitsDefinition) updateReCache() {
+func (u *UnitsDefinition) newCache() *unitsCache {
+	var multipliers []int64
+	for multiplier := range u.MultipliersValue {
+		multipliers = append(multipliers, multiplier)
+	}
+	sort.SliceStable(multipliers, func(i, j int) bool {
+		return multipliers[i] > multipliers[j]
+	})
 	var parts []string
 	if u.MultipliersValue != nil {
-		for _, multiplier := range u.getSortedMultipliersCache() {
+		for _, multiplier := range multipliers {
 			unit := u.MultipliersValue[multiplier]
 			parts = append(parts, fmt.Sprintf(
 				"(?:|(?P<g%s>[0-9]+)\\s*(%s|%s|%s|%s))",
@@ -330,18 +361,22 @@ func (u *UnitsDefinition) updateReCache() {
 		}
 	}
 	parts = append(parts, fmt.Sprintf(
-		"(?:|(?P<g1>[0-9]+(|.[0-9]+))\\s*(|%s|%s|%s|%s))",
+		"(?:|(?P<g1>[0-9]+(|\\.[0-9]+))\\s*(|%s|%s|%s|%s))",
 		regexp.QuoteMeta(u.BaseUnitValue.NameShortSingular()),
 		regexp.QuoteMeta(u.BaseUnitValue.NameShortPlural()),
 		regexp.QuoteMeta(u.BaseUnitValue.NameLongSingular()),
 		regexp.QuoteMeta(u.BaseUnitValue.NameLongPlural()),
 	))
 	regex := "^\\s*" + strings.Join(parts, "\\s*") + "\\s*$"
-	u.reCache = regexp.MustCompile(regex)
-	u.reSubExpNames = map[string]int{}
-	for i, subExpName := range u.reCache.SubexpNames() {
-		u.reSubExpNames[subExpName] = i
+	cache := &unitsCache{
+		sortedMultipliers: multipliers,
+		re:                regexp.MustCompile(regex),
+		reSubExpNames:     map[string]int{},
+	}
+	for i, subExpName := range cache.re.SubexpNames() {
+		cache.reSubExpNames[subExpName] = i
 	}
+	return cache
 }
 
 func (u *UnitsDefinition) buildUnitParseError(data string) (any, error) {
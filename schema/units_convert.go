@@ -0,0 +1,211 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnitMultiplier returns how many of the base unit the unit with the given name is, for example 60 for "m" in
+// UnitDurationSeconds. Any of the four names of a unit can be used.
+func (u *UnitsDefinition) UnitMultiplier(name string) (int64, error) {
+	name = strings.TrimSpace(name)
+	if unitHasName(u.BaseUnitValue, name) {
+		return 1, nil
+	}
+	for multiplier, unit := range u.MultipliersValue {
+		if unitHasName(unit, name) {
+			return multiplier, nil
+		}
+	}
+	return 0, UnitParseError{
+		Message: fmt.Sprintf("Unknown unit '%s' for '%s'", name, u.BaseUnitValue.NameLongPlural()),
+	}
+}
+
+func unitHasName(unit *UnitDefinition, name string) bool {
+	return name == unit.NameShortSingular() ||
+		name == unit.NameShortPlural() ||
+		name == unit.NameLongSingular() ||
+		name == unit.NameLongPlural()
+}
+
+// Convert converts an amount from one unit to another, for example 1.5 "GB" to 1536 "MB".
+func (u *UnitsDefinition) Convert(amount float64, from string, to string) (float64, error) {
+	fromMultiplier, err := u.UnitMultiplier(from)
+	if err != nil {
+		return 0, err
+	}
+	toMultiplier, err := u.UnitMultiplier(to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * float64(fromMultiplier) / float64(toMultiplier), nil
+}
+
+// FormatShortScaled formats the amount of the base unit in the largest unit it is at least one of, rounded to the
+// given number of decimal places, for example 1.5kB for 1536 bytes. Unlike FormatShortFloat, it uses a single unit,
+// which is easier to read in reports.
+func (u *UnitsDefinition) FormatShortScaled(data float64, precision int) string {
+	amount, unit := u.scale(data)
+	formatted := formatDecimal(amount, precision)
+	if formatted == "1" || formatted == "-1" {
+		return formatted + unit.NameShortSingular()
+	}
+	return formatted + unit.NameShortPlural()
+}
+
+// FormatLongScaled formats the amount like FormatShortScaled, using the long names of the units.
+func (u *UnitsDefinition) FormatLongScaled(data float64, precision int) string {
+	amount, unit := u.scale(data)
+	formatted := formatDecimal(amount, precision)
+	if formatted == "1" || formatted == "-1" {
+		return formatted + unit.NameLongSingular()
+	}
+	return formatted + unit.NameLongPlural()
+}
+
+// scale returns the amount in the largest unit it is at least one of.
+func (u *UnitsDefinition) scale(data float64) (float64, *UnitDefinition) {
+	for _, multiplier := range u.getSortedMultipliersCache() {
+		if math.Abs(data) >= float64(multiplier) {
+			return data / float64(multiplier), u.MultipliersValue[multiplier]
+		}
+	}
+	return data, u.BaseUnitValue
+}
+
+// formatDecimal formats the number with at most the given number of decimal places, without trailing zeros.
+func formatDecimal(number float64, precision int) string {
+	formatted := strconv.FormatFloat(number, 'f', precision, 64)
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimSuffix(strings.TrimRight(formatted, "0"), ".")
+	}
+	if formatted == "-0" {
+		return "0"
+	}
+	return formatted
+}
+
+// RateUnits describes an amount of one quantity per unit of another, such as bytes per second. Rates are written as
+// two unit expressions separated by a slash, for example 5MB/s, 1.5 GB/minute or 100MB/2s. Parsed values are in base
+// units of the numerator per base unit of the denominator.
+type RateUnits struct {
+	numerator   *UnitsDefinition
+	denominator *UnitsDefinition
+}
+
+// NewRateUnits creates a rate of the numerator units per denominator unit, such as UnitBytes per UnitDurationSeconds.
+func NewRateUnits(numerator *UnitsDefinition, denominator *UnitsDefinition) *RateUnits {
+	return &RateUnits{
+		numerator:   numerator,
+		denominator: denominator,
+	}
+}
+
+// Numerator returns the units of the quantity being measured.
+func (r *RateUnits) Numerator() *UnitsDefinition {
+	return r.numerator
+}
+
+// Denominator returns the units the quantity is measured per.
+func (r *RateUnits) Denominator() *UnitsDefinition {
+	return r.denominator
+}
+
+// Parse parses a rate such as 5MB/s into the amount of numerator base units per denominator base unit.
+func (r *RateUnits) Parse(data string) (float64, error) {
+	amount, per, err := r.split(data)
+	if err != nil {
+		return 0, err
+	}
+	numerator, err := r.numerator.ParseFloat(amount)
+	if err != nil {
+		return 0, err
+	}
+	denominator, err := r.parseDenominator(per)
+	if err != nil {
+		return 0, err
+	}
+	return numerator / denominator, nil
+}
+
+// UnitMultiplier returns how many base rate units the compound unit, such as MB/s, is.
+func (r *RateUnits) UnitMultiplier(expression string) (float64, error) {
+	numeratorUnit, denominatorUnit, err := r.split(expression)
+	if err != nil {
+		return 0, err
+	}
+	numerator, err := r.numerator.UnitMultiplier(numeratorUnit)
+	if err != nil {
+		return 0, err
+	}
+	denominator, err := r.denominator.UnitMultiplier(denominatorUnit)
+	if err != nil {
+		return 0, err
+	}
+	return float64(numerator) / float64(denominator), nil
+}
+
+// Convert converts an amount from one compound unit to another, for example 1 "MB/s" to 60 "MB/m".
+func (r *RateUnits) Convert(amount float64, from string, to string) (float64, error) {
+	fromMultiplier, err := r.UnitMultiplier(from)
+	if err != nil {
+		return 0, err
+	}
+	toMultiplier, err := r.UnitMultiplier(to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * fromMultiplier / toMultiplier, nil
+}
+
+// FormatShortScaled formats a rate in base units per the named denominator unit, scaling the numerator like
+// UnitsDefinition.FormatShortScaled, for example 1.5MB/s.
+func (r *RateUnits) FormatShortScaled(data float64, per string, precision int) (string, error) {
+	denominator, err := r.denominator.UnitMultiplier(per)
+	if err != nil {
+		return "", err
+	}
+	return r.numerator.FormatShortScaled(data*float64(denominator), precision) + "/" + per, nil
+}
+
+func (r *RateUnits) split(data string) (string, string, error) {
+	numerator, denominator, found := strings.Cut(data, "/")
+	if !found || strings.Contains(denominator, "/") {
+		return "", "", UnitParseError{
+			Message: fmt.Sprintf(
+				"Cannot parse '%s' as '%s' per '%s': expected exactly one '/'",
+				data,
+				r.numerator.BaseUnitValue.NameLongPlural(),
+				r.denominator.BaseUnitValue.NameLongSingular(),
+			),
+		}
+	}
+	return strings.TrimSpace(numerator), strings.TrimSpace(denominator), nil
+}
+
+// parseDenominator parses the denominator of a rate, which is either a unit, such as s, or an amount, such as 2s.
+func (r *RateUnits) parseDenominator(data string) (float64, error) {
+	var denominator float64
+	if data != "" && (data[0] >= '0' && data[0] <= '9' || data[0] == '.') {
+		parsed, err := r.denominator.ParseFloat(data)
+		if err != nil {
+			return 0, err
+		}
+		denominator = parsed
+	} else {
+		multiplier, err := r.denominator.UnitMultiplier(data)
+		if err != nil {
+			return 0, err
+		}
+		denominator = float64(multiplier)
+	}
+	if denominator == 0 {
+		return 0, UnitParseError{
+			Message: fmt.Sprintf("Cannot divide by zero %s", r.denominator.BaseUnitValue.NameLongPlural()),
+		}
+	}
+	return denominator, nil
+}
@@ -0,0 +1,56 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestUnitsConvert(t *testing.T) {
+	assert.Equals(t, assert.NoErrorR[int64](t)(schema.UnitDurationSeconds.UnitMultiplier("minutes")), int64(60))
+	assert.Equals(t, assert.NoErrorR[int64](t)(schema.UnitDurationSeconds.UnitMultiplier("s")), int64(1))
+	_, err := schema.UnitDurationSeconds.UnitMultiplier("parsecs")
+	assert.Error(t, err)
+
+	assert.Equals(t, assert.NoErrorR[float64](t)(schema.UnitBytes.Convert(1.5, "GB", "MB")), 1536.0)
+	assert.Equals(t, assert.NoErrorR[float64](t)(schema.UnitDurationSeconds.Convert(90, "s", "m")), 1.5)
+	_, err = schema.UnitBytes.Convert(1, "GB", "s")
+	assert.Error(t, err)
+}
+
+func TestUnitsFormatScaled(t *testing.T) {
+	assert.Equals(t, schema.UnitBytes.FormatShortScaled(1536, 2), "1.5kB")
+	assert.Equals(t, schema.UnitBytes.FormatShortScaled(1073741824, 2), "1GB")
+	assert.Equals(t, schema.UnitBytes.FormatShortScaled(500, 2), "500B")
+	assert.Equals(t, schema.UnitBytes.FormatShortScaled(0, 2), "0B")
+	assert.Equals(t, schema.UnitDurationSeconds.FormatShortScaled(-5400, 1), "-1.5H")
+	assert.Equals(t, schema.UnitDurationSeconds.FormatLongScaled(60, 1), "1minute")
+	assert.Equals(t, schema.UnitDurationSeconds.FormatLongScaled(100, 2), "1.67minutes")
+}
+
+func TestRateUnits(t *testing.T) {
+	rate := schema.NewRateUnits(schema.UnitBytes, schema.UnitDurationSeconds)
+	for input, expected := range map[string]float64{
+		"5MB/s":         5242880,
+		"512 B / s":     512,
+		"60kB/minute":   1024,
+		"100MB/2s":      52428800,
+		"1kB512B/1m30s": 1536.0 / 90,
+	} {
+		t.Run(input, func(t *testing.T) {
+			assert.Equals(t, assert.NoErrorR[float64](t)(rate.Parse(input)), expected)
+		})
+	}
+	for _, input := range []string{"5MB", "5MB/s/s", "5MB/parsec", "5MB/0s", "5 parsecs/s"} {
+		_, err := rate.Parse(input)
+		assert.Error(t, err)
+	}
+
+	assert.Equals(t, assert.NoErrorR[float64](t)(rate.UnitMultiplier("kB/m")), 1024.0/60)
+	assert.Equals(t, assert.NoErrorR[float64](t)(rate.Convert(1, "MB/s", "MB/m")), 60.0)
+	assert.Equals(t, assert.NoErrorR[string](t)(rate.FormatShortScaled(5242880, "s", 1)), "5MB/s")
+	assert.Equals(t, assert.NoErrorR[string](t)(rate.FormatShortScaled(5242880, "m", 1)), "300MB/m")
+	_, err := rate.FormatShortScaled(1, "parsec", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RegisteredUnits is a units definition registered in a UnitsRegistry under an ID.
+type RegisteredUnits struct {
+	ID      string
+	Units   *UnitsDefinition
+	Display Display
+}
+
+// UnitsRegistry holds named units definitions, such as IOPS or cores, next to the built-in byte and time units. Units
+// are serialized in full with the schema, so a registry is not needed to exchange them over ATP. Instead, it lets
+// plugins and engines refer to units by ID, list them for documentation, and map the units of unserialized schemas
+// back to the registered definitions with Resolve.
+//
+// A registry may have a parent, such as the DefaultUnitsRegistry, which it falls back to. A registry is safe for
+// concurrent use.
+type UnitsRegistry struct {
+	lock    sync.RWMutex
+	parent  *UnitsRegistry
+	entries map[string]RegisteredUnits
+}
+
+// NewUnitsRegistry creates an empty registry that falls back to the parent registry, if any.
+func NewUnitsRegistry(parent *UnitsRegistry) *UnitsRegistry {
+	return &UnitsRegistry{
+		parent:  parent,
+		entries: map[string]RegisteredUnits{},
+	}
+}
+
+// DefaultUnitsRegistry is the process-wide registry. It holds the built-in units, and UnserializeSchema resolves the
+// units of the schemas it returns against it.
+var DefaultUnitsRegistry = newDefaultUnitsRegistry()
+
+func newDefaultUnitsRegistry() *UnitsRegistry {
+	registry := NewUnitsRegistry(nil)
+	for id, units := range map[string]*UnitsDefinition{
+		"bytes":                UnitBytes,
+		"duration_nanoseconds": UnitDurationNanoseconds,
+		"duration_seconds":     UnitDurationSeconds,
+		"characters":           UnitCharacters,
+		"percentage":           UnitPercentage,
+	} {
+		if err := registry.Register(id, units, nil); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// RegisterUnits registers the units in the DefaultUnitsRegistry.
+func RegisterUnits(id string, units *UnitsDefinition, display Display) error {
+	return DefaultUnitsRegistry.Register(id, units, display)
+}
+
+// Register adds the units under the ID. It fails if the ID is already taken in the registry or its parents, or if
+// the same units are already registered under another ID.
+func (r *UnitsRegistry) Register(id string, units *UnitsDefinition, display Display) error {
+	if id == "" || units == nil || units.BaseUnitValue == nil {
+		return BadArgumentError{Message: "units must be registered with an ID and a base unit"}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, taken := r.entries[id]
+	if !taken && r.parent != nil {
+		_, taken = r.parent.Get(id)
+	}
+	if taken {
+		return BadArgumentError{Message: fmt.Sprintf("units %s are already registered", id)}
+	}
+	existing, ok := r.findLocal(units)
+	if !ok && r.parent != nil {
+		existing, ok = r.parent.Find(units)
+	}
+	if ok {
+		return BadArgumentError{
+			Message: fmt.Sprintf("the units of %s are already registered as %s", id, existing.ID),
+		}
+	}
+	r.entries[id] = RegisteredUnits{id, units, display}
+	return nil
+}
+
+// Get returns the units registered under the ID in the registry or its parents.
+func (r *UnitsRegistry) Get(id string) (RegisteredUnits, bool) {
+	r.lock.RLock()
+	entry, ok := r.entries[id]
+	r.lock.RUnlock()
+	if !ok && r.parent != nil {
+		return r.parent.Get(id)
+	}
+	return entry, ok
+}
+
+// Find returns the registered units with the same unit names and multipliers as the given units, which may come from
+// an unserialized schema.
+func (r *UnitsRegistry) Find(units *UnitsDefinition) (RegisteredUnits, bool) {
+	if units == nil {
+		return RegisteredUnits{}, false
+	}
+	r.lock.RLock()
+	entry, ok := r.findLocal(units)
+	r.lock.RUnlock()
+	if !ok && r.parent != nil {
+		return r.parent.Find(units)
+	}
+	return entry, ok
+}
+
+// findLocal looks the units up in this registry only. The caller must hold the lock.
+func (r *UnitsRegistry) findLocal(units *UnitsDefinition) (RegisteredUnits, bool) {
+	for _, entry := range r.entries {
+		if entry.Units == units || unitsEqual(entry.Units, units) {
+			return entry, true
+		}
+	}
+	return RegisteredUnits{}, false
+}
+
+// List returns the units of the registry and its parents ordered by ID, for example to document them.
+func (r *UnitsRegistry) List() []RegisteredUnits {
+	entries := map[string]RegisteredUnits{}
+	for registry := r; registry != nil; registry = registry.parent {
+		registry.lock.RLock()
+		for id, entry := range registry.entries {
+			if _, ok := entries[id]; !ok {
+				entries[id] = entry
+			}
+		}
+		registry.lock.RUnlock()
+	}
+	result := make([]RegisteredUnits, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Resolve replaces the units of the numbers in the type with the registered definitions that have the same content,
+// so they can be compared by identity, such as units == UnitBytes, and share their parsing caches.
+func (r *UnitsRegistry) Resolve(t Type) {
+	_ = Walk(t, func(_ []string, t Type) error {
+		switch typed := t.(type) {
+		case *IntSchema:
+			typed.UnitsValue = r.resolve(typed.UnitsValue)
+		case *FloatSchema:
+			typed.UnitsValue = r.resolve(typed.UnitsValue)
+		case *IntEnumSchema:
+			typed.IntUnits = r.resolve(typed.IntUnits)
+		}
+		return nil
+	})
+}
+
+func (r *UnitsRegistry) resolve(units *UnitsDefinition) *UnitsDefinition {
+	if entry, ok := r.Find(units); ok {
+		return entry.Units
+	}
+	return units
+}
+
+func unitsEqual(a *UnitsDefinition, b *UnitsDefinition) bool {
+	if !unitEqual(a.BaseUnitValue, b.BaseUnitValue) || len(a.MultipliersValue) != len(b.MultipliersValue) {
+		return false
+	}
+	for multiplier, unit := range a.MultipliersValue {
+		if !unitEqual(unit, b.MultipliersValue[multiplier]) {
+			return false
+		}
+	}
+	return true
+}
+
+func unitEqual(a *UnitDefinition, b *UnitDefinition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
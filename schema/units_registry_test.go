@@ -0,0 +1,106 @@
+package schema_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func newIOPSUnits() *schema.UnitsDefinition {
+	return schema.NewUnits(
+		schema.NewUnit("IOPS", "IOPS", "operation per second", "operations per second"),
+		map[int64]*schema.UnitDefinition{1000: schema.NewUnit("kIOPS", "kIOPS", "thousand IOPS", "thousand IOPS")},
+	)
+}
+
+func TestUnitsRegistry(t *testing.T) {
+	registry := schema.NewUnitsRegistry(schema.DefaultUnitsRegistry)
+	iops := newIOPSUnits()
+	assert.NoError(t, registry.Register("iops", iops, schema.NewDisplayValue(schema.PointerTo("IOPS"), nil, nil)))
+
+	entry, ok := registry.Get("iops")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, entry.Units == iops, true)
+	entry, ok = registry.Get("bytes")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, entry.Units == schema.UnitBytes, true)
+	_, ok = schema.DefaultUnitsRegistry.Get("iops")
+	assert.Equals(t, ok, false)
+
+	// An equal definition, for example from an unserialized schema, is found by its content.
+	entry, ok = registry.Find(newIOPSUnits())
+	assert.Equals(t, ok, true)
+	assert.Equals(t, entry.ID, "iops")
+
+	assert.Error(t, registry.Register("iops", newIOPSUnits(), nil))
+	assert.Error(t, registry.Register("operations", newIOPSUnits(), nil))
+	assert.Error(t, registry.Register("bytes", newIOPSUnits(), nil))
+	assert.Error(t, registry.Register("", newIOPSUnits(), nil))
+
+	ids := []string{}
+	for _, entry := range registry.List() {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equals(t, ids, []string{
+		"bytes",
+		"characters",
+		"duration_nanoseconds",
+		"duration_seconds",
+		"iops",
+		"percentage",
+	})
+}
+
+func TestUnitsRegistryResolve(t *testing.T) {
+	registry := schema.NewUnitsRegistry(schema.DefaultUnitsRegistry)
+	iops := newIOPSUnits()
+	assert.NoError(t, registry.Register("iops", iops, nil))
+
+	step := schema.NewStepSchema(
+		"test",
+		schema.NewScopeSchema(schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+			"iops": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, newIOPSUnits()),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"size": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, schema.UnitBytes),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		})),
+		map[string]*schema.StepOutputSchema{},
+		nil,
+		nil,
+		nil,
+	)
+	serialized := assert.NoErrorR[any](t)(
+		schema.NewSchema(map[string]*schema.StepSchema{"test": step}).SelfSerialize(),
+	)
+	unserialized := assert.NoErrorR[*schema.SchemaSchema](t)(schema.UnserializeSchema(serialized))
+	properties := unserialized.StepsValue["test"].Input().Properties()
+
+	// The built-in units are resolved when unserializing the schema.
+	assert.Equals(t, properties["size"].Type().(*schema.IntSchema).Units() == schema.UnitBytes, true)
+	assert.Equals(t, properties["iops"].Type().(*schema.IntSchema).Units() == iops, false)
+
+	registry.Resolve(unserialized.StepsValue["test"].Input())
+	assert.Equals(t, properties["iops"].Type().(*schema.IntSchema).Units() == iops, true)
+	result := assert.NoErrorR[any](t)(unserialized.StepsValue["test"].Input().Unserialize(map[string]any{
+		"iops": "2kIOPS",
+		"size": "1kB",
+	}))
+	assert.Equals(t, result.(map[string]any)["iops"], any(int64(2000)))
+}
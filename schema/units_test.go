@@ -17,6 +17,11 @@ func TestUnitsParseInt(t *testing.T) {
 			schema.UnitDurationNanoseconds,
 			305000000000,
 		},
+		"1m30s": {
+			"1m30s",
+			schema.UnitDurationNanoseconds,
+			90000000000,
+		},
 		"1%": {
 			"1%",
 			schema.UnitPercentage,
@@ -67,6 +72,11 @@ func TestUnitsParseFloat(t *testing.T) {
 			schema.UnitDurationSeconds,
 			305.1,
 		},
+		"10.5s": {
+			"10.5s",
+			schema.UnitDurationSeconds,
+			10.5,
+		},
 		"1.1%": {
 			"1.1%",
 			schema.UnitPercentage,
@@ -0,0 +1,426 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// UnserializeAll unserializes the data like Unserialize, but instead of stopping at the first constraint violation it
+// walks the entire input and returns a *ConstraintErrors containing every violation with its field path. This lets
+// users fix all problems in their input in one pass.
+func UnserializeAll(t Type, data any) (any, error) {
+	result, errs := errorCollector{}.collect(t, data)
+	if len(errs) > 0 {
+		return nil, &ConstraintErrors{Errors: errs}
+	}
+	return result, nil
+}
+
+// ValidateAll validates the unserialized data like Validate, but returns a *ConstraintErrors containing every
+// violation with its field path instead of stopping at the first one.
+func ValidateAll(t Type, data any) error {
+	if _, errs := (errorCollector{validate: true}).collect(t, data); len(errs) > 0 {
+		return &ConstraintErrors{Errors: errs}
+	}
+	return nil
+}
+
+// constraintSink receives the constraint violations found by the checks shared between the unserialize paths and the
+// errorCollector. A nil sink stops the check at the first violation, which it returns, while a non-nil sink collects
+// every violation and lets the check carry on, so checks reporting to a non-nil sink always return nil.
+type constraintSink struct {
+	errs []*ConstraintError
+}
+
+// report returns the error if the sink is nil, and records it otherwise.
+func (s *constraintSink) report(err error) error {
+	if s == nil {
+		return err
+	}
+	s.add(err)
+	return nil
+}
+
+// add records the error, if any.
+func (s *constraintSink) add(err error) {
+	if err != nil {
+		s.errs = append(s.errs, asConstraintError(err))
+	}
+}
+
+// errorCollector walks the data once and gathers all constraint violations. Composite types are descended into, while
+// all other types are unserialized or validated as a whole. If no violations are found, the result of unserializing
+// the data is returned as well, so valid subtrees are never processed twice.
+type errorCollector struct {
+	// validate switches from unserializing serialized data to validating unserialized data.
+	validate bool
+	// provenance records the changes made to the values while unserializing, if set. The path of the current value
+	// is only tracked for it, and sensitive is set below sensitive properties.
+	provenance *provenanceRecorder
+	path       []string
+	sensitive  bool
+	// coercion restricts the conversions between data types when unserializing, if set.
+	coercion *CoercionPolicy
+	// yield lets other goroutines run while walking large data, if set.
+	yield *yielder
+}
+
+func (c errorCollector) collect(t Type, data any) (any, []*ConstraintError) {
+	if err := c.yield.point(); err != nil {
+		return nil, []*ConstraintError{err}
+	}
+	if objectSchema, ok := objectSchemaOf(t); ok {
+		return c.collectObject(objectSchema, data)
+	} else if listSchema, ok := t.(untypedListSchema); ok {
+		return c.collectList(listSchema, data)
+	} else if mapSchema, ok := t.(untypedMapSchema); ok {
+		return c.collectMap(mapSchema, data)
+	} else if tupleSchema, ok := t.(*TupleSchema); ok {
+		return c.collectTuple(tupleSchema, data)
+	} else if oneOfSchema, ok := t.(variantSelector); ok {
+		return c.collectOneOf(oneOfSchema, data)
+	}
+	return c.collectValue(t, data)
+}
+
+// collectValue unserializes or validates the data as a whole.
+func (c errorCollector) collectValue(t Type, data any) (any, []*ConstraintError) {
+	if c.validate {
+		if err := t.Validate(data); err != nil {
+			return nil, []*ConstraintError{asConstraintError(err)}
+		}
+		return data, nil
+	}
+	if c.coercion != nil {
+		if err := c.coercion.check(t, data); err != nil {
+			return nil, []*ConstraintError{err}
+		}
+	}
+	result, err := t.Unserialize(data)
+	if err != nil {
+		return nil, []*ConstraintError{asConstraintError(err)}
+	}
+	c.recordUnits(t, data, result)
+	return result, nil
+}
+
+func (c errorCollector) collectObject(o *ObjectSchema, data any) (any, []*ConstraintError) {
+	if !c.validate && reflect.ValueOf(data).Kind() != reflect.Map {
+		// Objects with a single property may be inlined, which Unserialize handles.
+		return c.collectValue(o, data)
+	}
+	sink := &constraintSink{}
+	rawData, setBefore, err := c.objectRawData(o, data, sink)
+	if err != nil {
+		return nil, []*ConstraintError{asConstraintError(err)}
+	}
+	for _, propertyID := range sortedKeys(o.PropertiesValue) {
+		c.collectObjectProperty(o, propertyID, rawData, setBefore, sink)
+	}
+	if len(sink.errs) > 0 {
+		return nil, sink.errs
+	}
+	if c.validate {
+		if err := o.runValidators(data); err != nil {
+			return nil, []*ConstraintError{asConstraintError(err)}
+		}
+		return data, nil
+	}
+	unserialized, err := o.buildResult(rawData)
+	if err != nil {
+		return nil, []*ConstraintError{asConstraintError(err)}
+	}
+	return unserialized, nil
+}
+
+// objectRawData returns the property values of the object, with the defaults filled in when unserializing, and the
+// properties set in the data if defaults need to be told apart from them. The invalid keys are reported to the sink.
+func (c errorCollector) objectRawData(
+	o *ObjectSchema,
+	data any,
+	sink *constraintSink,
+) (map[string]any, map[string]bool, error) {
+	if c.validate {
+		rawData, err := o.validatedRawData(data)
+		return rawData, nil, err
+	}
+	rawData, _ := o.copyRawData(reflect.ValueOf(data), sink)
+	var setBefore map[string]bool
+	if c.provenance != nil || c.coercion != nil {
+		setBefore = make(map[string]bool, len(rawData))
+		for propertyID := range rawData {
+			setBefore[propertyID] = true
+		}
+	}
+	o.fillDefaults(rawData)
+	if c.provenance != nil {
+		c.recordDefaults(o, setBefore, rawData)
+	}
+	return rawData, setBefore, nil
+}
+
+// collectObjectProperty checks the interdependencies of the property and descends into its value, if set, replacing
+// it in the raw data with the unserialized value.
+func (c errorCollector) collectObjectProperty(
+	o *ObjectSchema,
+	propertyID string,
+	rawData map[string]any,
+	setBefore map[string]bool,
+	sink *constraintSink,
+) {
+	property := o.PropertiesValue[propertyID]
+	sink.add(o.validatePropertyInterdependencies(rawData, propertyID, property))
+	value, isSet := rawData[propertyID]
+	if !isSet {
+		return
+	}
+	propertyCollector := c.at(propertyID)
+	if setBefore != nil && !setBefore[propertyID] {
+		// Default values are set by the plugin, so the coercion policy doesn't apply to them.
+		propertyCollector.coercion = nil
+	}
+	unserializedValue, errs := propertyCollector.collectProperty(property, value)
+	if len(errs) > 0 {
+		sink.errs = append(sink.errs, prefixConstraintErrors(errs, propertyID)...)
+		return
+	}
+	rawData[propertyID] = unserializedValue
+}
+
+// validatedRawData returns the property values of the unserialized object, the same way Validate reads them.
+func (o *ObjectSchema) validatedRawData(data any) (map[string]any, error) {
+	if o.ValuePropertyValue != "" {
+		return map[string]any{o.ValuePropertyValue: data}, nil
+	}
+	if o.fieldCache == nil {
+		d, ok := data.(map[string]any)
+		if !ok {
+			return nil, &ConstraintError{
+				Message: fmt.Sprintf("%T is not a valid data type for an object schema", data),
+			}
+		}
+		for key := range d {
+			if _, ok := o.PropertiesValue[key]; !ok {
+				return nil, o.invalidKeyError(key)
+			}
+		}
+		return maps.Clone(d), nil
+	}
+	return o.structRawData(data)
+}
+
+// collectProperty descends into the property value, applying the transformations and redacting the errors of
+// sensitive properties the same way PropertySchema.Unserialize does.
+func (c errorCollector) collectProperty(property *PropertySchema, data any) (any, []*ConstraintError) {
+	if property.Disabled {
+		return c.collectValue(property, data)
+	}
+	if !c.validate {
+		data = c.applyTransforms(property, data)
+	}
+	c.sensitive = c.sensitive || property.SensitiveValue
+	result, errs := c.collect(property.TypeValue, data)
+	for i, err := range errs {
+		errs[i] = asConstraintError(property.redactError(err))
+	}
+	return result, errs
+}
+
+func (c errorCollector) collectList(l untypedListSchema, data any) (any, []*ConstraintError) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return c.collectValue(l, data)
+	}
+	sink := &constraintSink{}
+	_ = checkItemCount(l.Min(), l.Max(), v.Len(), sink)
+	var unserialized reflect.Value
+	if !c.validate {
+		unserialized = reflect.MakeSlice(l.ReflectedType(), v.Len(), v.Len())
+	}
+	for i := 0; i < v.Len(); i++ {
+		item, errs := c.at(strconv.Itoa(i)).collect(l.itemType(), v.Index(i).Interface())
+		if len(errs) > 0 {
+			sink.errs = append(sink.errs, prefixConstraintErrors(errs, fmt.Sprintf("[%d]", i))...)
+			continue
+		}
+		if !c.validate && len(sink.errs) == 0 {
+			unserialized.Index(i).Set(reflect.ValueOf(item))
+		}
+	}
+	if len(sink.errs) > 0 {
+		return nil, sink.errs
+	}
+	if c.validate {
+		unserialized = v
+	}
+	_ = l.duplicateItems(unserialized, sink)
+	if len(sink.errs) > 0 {
+		return nil, sink.errs
+	}
+	if c.validate {
+		return data, nil
+	}
+	return unserialized.Interface(), nil
+}
+
+func (c errorCollector) collectMap(m untypedMapSchema, data any) (any, []*ConstraintError) {
+	entries, ok := mapEntriesOf(data)
+	if !ok {
+		return c.collectValue(m, data)
+	}
+	if _, ordered := data.(orderedMap); !ordered {
+		sortMapEntries(entries)
+	}
+	sink := &constraintSink{}
+	_ = checkItemCount(m.Min(), m.Max(), len(entries), sink)
+	var unserialized reflect.Value
+	if !c.validate {
+		unserialized = m.newUnserializedMap(len(entries))
+	}
+	for _, entry := range entries {
+		// Keys are not values of their own, so changes to them are not recorded.
+		key, keyErrors := errorCollector{validate: c.validate}.collect(m.keyType(), entry.key.Interface())
+		sink.errs = append(sink.errs, prefixConstraintErrors(keyErrors, fmt.Sprintf("{%v}", entry.key.Interface()))...)
+		value, valueErrors := c.at(fmt.Sprintf("%v", entry.key.Interface())).collect(m.valueType(), entry.value.Interface())
+		sink.errs = append(sink.errs, prefixConstraintErrors(valueErrors, fmt.Sprintf("[%v]", entry.key.Interface()))...)
+		if !c.validate && len(sink.errs) == 0 {
+			setUnserializedEntry(unserialized, key, value)
+		}
+	}
+	if len(sink.errs) > 0 {
+		return nil, sink.errs
+	}
+	if c.validate {
+		return data, nil
+	}
+	return unserialized.Interface(), nil
+}
+
+func (c errorCollector) collectTuple(t *TupleSchema, data any) (any, []*ConstraintError) {
+	var items []any
+	if c.validate {
+		var err error
+		if items, err = t.elements(data); err != nil {
+			return nil, []*ConstraintError{asConstraintError(err)}
+		}
+	} else {
+		v := reflect.ValueOf(data)
+		if v.Kind() != reflect.Slice || v.Len() != len(t.ItemsValue) {
+			return c.collectValue(t, data)
+		}
+		items = make([]any, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+	var result []*ConstraintError
+	for i, item := range t.ItemsValue {
+		unserializedItem, errs := c.at(strconv.Itoa(i)).collect(item, items[i])
+		if len(errs) > 0 {
+			result = append(result, prefixConstraintErrors(errs, fmt.Sprintf("[%d]", i))...)
+			continue
+		}
+		items[i] = unserializedItem
+	}
+	if len(result) > 0 {
+		return nil, result
+	}
+	if c.validate {
+		return data, nil
+	}
+	return t.build(items), nil
+}
+
+func (c errorCollector) collectOneOf(o variantSelector, data any) (any, []*ConstraintError) {
+	if c.validate {
+		discriminator, selectedType, variantData, err := o.selectUnserializedVariant(data)
+		if err != nil {
+			return nil, []*ConstraintError{asConstraintError(err)}
+		}
+		if _, errs := c.collect(selectedType, variantData); len(errs) > 0 {
+			return nil, prefixConstraintErrors(errs, fmt.Sprintf("{oneof[%v]}", discriminator))
+		}
+		return data, nil
+	}
+	discriminator, selectedType, variantData, err := o.selectVariant(data)
+	if err != nil {
+		return nil, []*ConstraintError{asConstraintError(err)}
+	}
+	unserializedData, errs := c.collect(selectedType, variantData)
+	if len(errs) > 0 {
+		return nil, prefixConstraintErrors(errs, fmt.Sprintf("{oneof[%v]}", discriminator))
+	}
+	result, err := o.unserializedVariant(discriminator, selectedType, unserializedData)
+	if err != nil {
+		return nil, []*ConstraintError{asConstraintError(err)}
+	}
+	return result, nil
+}
+
+// variantSelector is implemented by one-of schemas to find the type matching the serialized data.
+type variantSelector interface {
+	selectVariant(data any) (any, Object, map[string]any, error)
+	selectUnserializedVariant(data any) (any, Object, any, error)
+	serializedVariant(discriminator any, selectedType Object, serialized map[string]any) map[string]any
+	unserializedVariant(discriminator any, selectedType Object, unserializedData any) (any, error)
+	DiscriminatorFieldName() string
+}
+
+// objectSchemaOf returns the object schema that unserializes data for the given type, if any.
+func objectSchemaOf(t Type) (*ObjectSchema, bool) {
+	if scope, ok := t.(Scope); ok {
+		return scope.RootObject(), true
+	}
+	object, ok := ConvertToObjectSchema(t)
+	if !ok {
+		return nil, false
+	}
+	if ref, isRef := object.(*RefSchema); isRef {
+		object = ref.GetObject()
+	}
+	objectSchema, ok := object.(*ObjectSchema)
+	return objectSchema, ok
+}
+
+func asConstraintError(err error) *ConstraintError {
+	var c *ConstraintError
+	if errors.As(err, &c) {
+		return c
+	}
+	return &ConstraintError{
+		Message: err.Error(),
+	}
+}
+
+func prefixConstraintErrors(errs []*ConstraintError, pathSegment string) []*ConstraintError {
+	for _, err := range errs {
+		err.Path = append([]string{pathSegment}, err.Path...)
+	}
+	return errs
+}
+
+type mapEntry struct {
+	key   reflect.Value
+	value reflect.Value
+}
+
+// sortedMapEntries returns the entries of the reflected map in a stable order so errors are reported deterministically.
+// The values are read while iterating, since keys such as NaN cannot be looked up again.
+func sortedMapEntries(v reflect.Value) []mapEntry {
+	entries := make([]mapEntry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		entries = append(entries, mapEntry{iter.Key(), iter.Value()})
+	}
+	sortMapEntries(entries)
+	return entries
+}
+
+func sortMapEntries(entries []mapEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return fmt.Sprintf("%v", entries[i].key.Interface()) < fmt.Sprintf("%v", entries[j].key.Interface())
+	})
+}
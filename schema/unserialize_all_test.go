@@ -0,0 +1,287 @@
+package schema_test
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+var unserializeAllTestSchema = schema.NewScopeSchema(
+	schema.NewObjectSchema(
+		"root",
+		map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(3), nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"items": schema.NewPropertySchema(
+				schema.NewListSchema(
+					schema.NewRefSchema("item", nil),
+					nil,
+					nil,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"labels": schema.NewPropertySchema(
+				schema.NewMapSchema(
+					schema.NewStringSchema(nil, nil, nil),
+					schema.NewIntSchema(schema.IntPointer(0), nil, nil),
+					nil,
+					nil,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+	schema.NewObjectSchema(
+		"item",
+		map[string]*schema.PropertySchema{
+			"count": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, schema.IntPointer(10), nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	),
+)
+
+func TestUnserializeAllValid(t *testing.T) {
+	data := map[string]any{
+		"name":   "test",
+		"items":  []any{map[string]any{"count": 1}},
+		"labels": map[string]any{"a": 1},
+	}
+	result, err := schema.UnserializeAll(unserializeAllTestSchema, data)
+	assert.NoError(t, err)
+	assert.Equals(t, result.(map[string]any)["name"].(string), "test")
+}
+
+func TestUnserializeAllCollectsEveryError(t *testing.T) {
+	data := map[string]any{
+		"name": "a",
+		"items": []any{
+			map[string]any{"count": 11},
+			map[string]any{"count": 5},
+			map[string]any{},
+		},
+		"labels":  map[string]any{"a": -1, "b": 1},
+		"unknown": true,
+	}
+	_, err := schema.UnserializeAll(unserializeAllTestSchema, data)
+	assert.Error(t, err)
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+
+	paths := make([]string, len(errs.Errors))
+	for i, e := range errs.Errors {
+		paths[i] = ""
+		for _, segment := range e.Path {
+			paths[i] += "/" + segment
+		}
+	}
+	assert.Equals(t, paths, []string{
+		"",
+		"/items/[0]/count",
+		"/items/[2]/count",
+		"/labels/[a]",
+		"/name",
+	})
+
+	// Each individual error can still be found with errors.As.
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+}
+
+func TestUnserializeAllSensitive(t *testing.T) {
+	s := schema.NewObjectSchema("root", map[string]*schema.PropertySchema{
+		"password": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, regexp.MustCompile("^[a-z]+$")),
+			nil,
+			true,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		).MarkSensitive(),
+	})
+	_, err := schema.UnserializeAll(s, map[string]any{"password": "s3cr3t"})
+	assert.Error(t, err)
+	assert.Equals(t, strings.Contains(err.Error(), "s3cr3t"), false)
+	assert.Contains(t, err.Error(), "Sensitive value violates the pattern constraint")
+}
+
+func TestUnserializeAllSingleError(t *testing.T) {
+	_, err := schema.UnserializeAll(schema.NewIntSchema(nil, schema.IntPointer(1), nil), 2)
+	assert.Error(t, err)
+	assert.Equals(t, err.Error(), "Validation failed: Must be at most 1")
+}
+
+func TestUnserializeAllNaNKey(t *testing.T) {
+	_, err := schema.UnserializeAll(
+		schema.NewMapSchema(schema.NewIntSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil),
+		map[any]any{math.NaN(): 1, "a": nil},
+	)
+	assert.Error(t, err)
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 3)
+}
+
+type unserializeAllTestInner struct {
+	Count int64 `json:"count"`
+}
+
+type unserializeAllTestOuter struct {
+	Name  string                  `json:"name"`
+	Inner unserializeAllTestInner `json:"inner"`
+}
+
+func newUnserializeAllStructSchema(validatorCalls *int) *schema.ObjectSchema {
+	inner := schema.NewStructMappedObjectSchema[unserializeAllTestInner](
+		"inner",
+		map[string]*schema.PropertySchema{
+			"count": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, schema.IntPointer(10), nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		},
+	)
+	schema.WithValidator(inner, func(obj unserializeAllTestInner) error {
+		*validatorCalls++
+		return nil
+	})
+	return schema.NewStructMappedObjectSchema[unserializeAllTestOuter](
+		"outer",
+		map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(3), nil, nil),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"inner": schema.NewPropertySchema(inner, nil, true, nil, nil, nil, nil, nil),
+		},
+	)
+}
+
+func TestUnserializeAllStruct(t *testing.T) {
+	validatorCalls := 0
+	s := newUnserializeAllStructSchema(&validatorCalls)
+	result, err := schema.UnserializeAll(s, map[string]any{"name": "test", "inner": map[string]any{"count": 5}})
+	assert.NoError(t, err)
+	assert.Equals(t, result.(unserializeAllTestOuter), unserializeAllTestOuter{
+		Name:  "test",
+		Inner: unserializeAllTestInner{Count: 5},
+	})
+	assert.Equals(t, validatorCalls, 1)
+
+	// Valid subtrees of invalid data are only unserialized once.
+	validatorCalls = 0
+	_, err = schema.UnserializeAll(s, map[string]any{"name": "a", "inner": map[string]any{"count": 5}})
+	assert.Error(t, err)
+	assert.Equals(t, validatorCalls, 1)
+}
+
+func TestValidateAll(t *testing.T) {
+	validatorCalls := 0
+	s := newUnserializeAllStructSchema(&validatorCalls)
+	assert.NoError(t, schema.ValidateAll(s, unserializeAllTestOuter{
+		Name:  "test",
+		Inner: unserializeAllTestInner{Count: 5},
+	}))
+	assert.Equals(t, validatorCalls, 1)
+
+	err := schema.ValidateAll(s, unserializeAllTestOuter{
+		Name:  "a",
+		Inner: unserializeAllTestInner{Count: 11},
+	})
+	assert.Error(t, err)
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 2)
+	assert.Equals(t, errs.Errors[0].Path, []string{"inner", "count"})
+	assert.Equals(t, errs.Errors[1].Path, []string{"name"})
+
+	err = schema.ValidateAll(unserializeAllTestSchema, map[string]any{
+		"name":   "a",
+		"labels": map[string]int64{"a": -1},
+	})
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, len(errs.Errors), 2)
+}
+
+func TestUnserializeAllOneOfPath(t *testing.T) {
+	s := schema.NewOneOfStringSchema[any](
+		map[string]schema.Object{
+			"a": schema.NewObjectSchema(
+				"a",
+				map[string]*schema.PropertySchema{
+					"count": schema.NewPropertySchema(
+						schema.NewIntSchema(schema.IntPointer(0), nil, nil),
+						nil,
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+		},
+		"type",
+		false,
+	)
+	expectedPath := []string{"{oneof[a]}", "count"}
+	var errs *schema.ConstraintErrors
+	assert.Equals(t, errors.As(schema.ValidateAll(s, map[string]any{"type": "a", "count": int64(-1)}), &errs), true)
+	assert.Equals(t, errs.Errors[0].Path, expectedPath)
+
+	_, err := schema.UnserializeAll(s, map[string]any{"type": "a", "count": -1})
+	assert.Equals(t, errors.As(err, &errs), true)
+	assert.Equals(t, errs.Errors[0].Path, expectedPath)
+
+	_, err = s.Unserialize(map[string]any{"type": "a", "count": -1})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Path, expectedPath)
+}
@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueChange is a single difference between two values of a type.
+type ValueChange struct {
+	// Kind is ChangeAdded for values that are only set in the new value, such as an optional property, a list item or
+	// a map entry, ChangeRemoved for values that are only set in the old value, and ChangeValue for all others.
+	Kind ChangeKind
+	// Path is the JSON pointer of the value, as accepted by ValueAtPath. It is empty for the value as a whole.
+	Path string
+	// Old is the serialized old value, or nil for added values.
+	Old any
+	// New is the serialized new value, or nil for removed values.
+	New any
+}
+
+func (c ValueChange) String() string {
+	path := c.Path
+	if path == "" {
+		path = "value"
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s added: %v", path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s removed: %v", path, c.Old)
+	default:
+		return fmt.Sprintf("%s changed from %v to %v", path, c.Old, c.New)
+	}
+}
+
+// DiffValues compares two unserialized values of the type and returns their differences, ordered by path, for
+// example to detect drift between a desired and an actual state, or to explain why two values are not equal in a
+// test. The schema guides the comparison: properties, list items, map entries and tuple items are compared one by one,
+// so each difference is labeled with the path of the smallest value that changed. Values of different one-of variants
+// and values that don't match the schema are reported as a change of the value as a whole. The old and new values of
+// sensitive properties are replaced with RedactedPlaceholder.
+func DiffValues(t Type, a, b any) []ValueChange {
+	var changes []ValueChange
+	diffValues(t, "", a, b, false, &changes)
+	return changes
+}
+
+func diffValues(t Type, path string, a, b any, sensitive bool, changes *[]ValueChange) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	switch {
+	case isAbsentValue(a) && isAbsentValue(b):
+		return
+	case isAbsentValue(a):
+		*changes = append(*changes, ValueChange{ChangeAdded, path, nil, serializeForValueDiff(t, b, sensitive)})
+		return
+	case isAbsentValue(b):
+		*changes = append(*changes, ValueChange{ChangeRemoved, path, serializeForValueDiff(t, a, sensitive), nil})
+		return
+	}
+	typeA, valueA, errA := resolveVariant(t, a)
+	typeB, valueB, errB := resolveVariant(t, b)
+	if errA == nil && errB == nil && sameType(typeA, typeB) &&
+		diffContainedValues(typeA, path, valueA, valueB, sensitive, changes) {
+		return
+	}
+	*changes = append(*changes, ValueChange{
+		ChangeValue,
+		path,
+		serializeForValueDiff(t, a, sensitive),
+		serializeForValueDiff(t, b, sensitive),
+	})
+}
+
+// diffContainedValues compares the values contained in two values of the type. It returns false if the type contains
+// no other values or the values don't match it.
+func diffContainedValues(t Type, path string, a, b any, sensitive bool, changes *[]ValueChange) bool {
+	switch typed := t.(type) {
+	case *ObjectSchema:
+		return diffObjectValues(typed, path, a, b, sensitive, changes)
+	case untypedListSchema:
+		return diffListValues(typed, path, a, b, sensitive, changes)
+	case untypedMapSchema:
+		return diffMapValues(typed, path, a, b, sensitive, changes)
+	case *TupleSchema:
+		return diffTupleValues(typed, path, a, b, sensitive, changes)
+	default:
+		return false
+	}
+}
+
+// diffObjectValues compares the properties of two objects. The changes are only added if all properties could be
+// read, so objects that don't match the type are compared as a whole.
+func diffObjectValues(o *ObjectSchema, path string, a, b any, sensitive bool, changes *[]ValueChange) bool {
+	var objectChanges []ValueChange
+	for _, propertyID := range sortedKeys(o.PropertiesValue) {
+		propertyType, propertyA, errA := propertyValue(o, a, propertyID)
+		_, propertyB, errB := propertyValue(o, b, propertyID)
+		if errA != nil || errB != nil {
+			return false
+		}
+		diffValues(
+			propertyType,
+			path+"/"+jsonPointerEscaper.Replace(propertyID),
+			propertyA,
+			propertyB,
+			sensitive || o.PropertiesValue[propertyID].Sensitive(),
+			&objectChanges,
+		)
+	}
+	*changes = append(*changes, objectChanges...)
+	return true
+}
+
+// diffListValues compares the items of two lists by index. Items only present in one of the lists are added or
+// removed.
+func diffListValues(l untypedListSchema, path string, a, b any, sensitive bool, changes *[]ValueChange) bool {
+	listA, listB := reflect.ValueOf(a), reflect.ValueOf(b)
+	if listA.Kind() != reflect.Slice || listB.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < max(listA.Len(), listB.Len()); i++ {
+		var itemA, itemB any
+		if i < listA.Len() {
+			itemA = listA.Index(i).Interface()
+		}
+		if i < listB.Len() {
+			itemB = listB.Index(i).Interface()
+		}
+		diffValues(l.itemType(), fmt.Sprintf("%s/%d", path, i), itemA, itemB, sensitive, changes)
+	}
+	return true
+}
+
+// diffMapValues compares the values of two maps by key, in key order.
+func diffMapValues(m untypedMapSchema, path string, a, b any, sensitive bool, changes *[]ValueChange) bool {
+	entriesA, okA := mapEntriesOf(a)
+	entriesB, okB := mapEntriesOf(b)
+	if !okA || !okB {
+		return false
+	}
+	valuesA, valuesB := mapEntriesByKey(entriesA), mapEntriesByKey(entriesB)
+	for _, key := range sortedDiffKeys(valuesA, valuesB) {
+		diffValues(
+			m.valueType(),
+			path+"/"+jsonPointerEscaper.Replace(key),
+			valuesA[key],
+			valuesB[key],
+			sensitive,
+			changes,
+		)
+	}
+	return true
+}
+
+// diffTupleValues compares the items of two tuples. Like with objects, the changes are only added if all items could
+// be read.
+func diffTupleValues(t *TupleSchema, path string, a, b any, sensitive bool, changes *[]ValueChange) bool {
+	var tupleChanges []ValueChange
+	for i := range t.ItemsValue {
+		segment := fmt.Sprintf("%d", i)
+		itemType, itemA, errA := tupleValue(t, a, segment)
+		_, itemB, errB := tupleValue(t, b, segment)
+		if errA != nil || errB != nil {
+			return false
+		}
+		diffValues(itemType, path+"/"+segment, itemA, itemB, sensitive, &tupleChanges)
+	}
+	*changes = append(*changes, tupleChanges...)
+	return true
+}
+
+// isAbsentValue returns true for unset values, such as optional properties that are nil.
+func isAbsentValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// sameType returns true if both types are the same type, such as the same one-of variant.
+func sameType(a, b Type) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// mapEntriesByKey returns the values of the map entries keyed by the string form of their keys, which is how they
+// appear in paths.
+func mapEntriesByKey(entries []mapEntry) map[string]any {
+	result := make(map[string]any, len(entries))
+	for _, entry := range entries {
+		result[fmt.Sprintf("%v", entry.key.Interface())] = entry.value.Interface()
+	}
+	return result
+}
+
+// serializeForValueDiff returns the serialized form of the value for a change, or the value itself if it doesn't
+// match the type.
+func serializeForValueDiff(t Type, value any, sensitive bool) any {
+	if sensitive {
+		return RedactedPlaceholder
+	}
+	serialized, err := t.Serialize(value)
+	if err != nil {
+		return value
+	}
+	return serialized
+}
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SkipChildren can be returned by the visitor passed to Walk to skip the types contained in the visited type. Walk
+// continues with the next sibling and does not return it as an error.
+var SkipChildren = errors.New("skip the children of this type") //nolint:revive // Named like fs.SkipDir.
+
+// Walk traverses the type and all types it contains depth-first and calls the visitor for each of them, parents before
+// their children. The path holds one segment per level:
+//
+//   - the property ID for the properties of objects,
+//   - "items" for the items of lists,
+//   - "keys" and "values" for the keys and values of maps,
+//   - "[index]" for the items of tuples,
+//   - the discriminator value for the variants of one-of types.
+//
+// Scopes and refs add no segment: the object they point to is visited with the same path right after them. Objects that
+// are already being walked further up the path are skipped, so recursive schemas don't loop forever. Unlinked refs
+// are visited, but not followed.
+//
+// If the visitor returns an error, the walk stops and returns it, unless the error is SkipChildren.
+func Walk(root Type, visitor func(path []string, t Type) error) error {
+	w := &walker{
+		visitor: visitor,
+		active:  map[any]bool{},
+	}
+	return w.walk(nil, root)
+}
+
+type walker struct {
+	visitor func(path []string, t Type) error
+	// active holds the objects that are being walked.
+	active map[any]bool
+}
+
+func (w *walker) walk(path []string, t Type) error {
+	if t.TypeID() == TypeIDObject && w.active[objectKey(t.(Object))] {
+		return nil
+	}
+	if err := w.visitor(path, t); err != nil {
+		if errors.Is(err, SkipChildren) {
+			return nil
+		}
+		return err
+	}
+	switch t.TypeID() {
+	case TypeIDScope:
+		return w.walk(path, t.(Scope).RootObject())
+	case TypeIDRef:
+		ref, ok := t.(*RefSchema)
+		if ok && !ref.ObjectReady() {
+			return nil
+		}
+		return w.walk(path, t.(Ref).GetObject())
+	case TypeIDObject:
+		return w.walkProperties(path, t.(Object))
+	case TypeIDList:
+		if list, ok := t.(untypedListSchema); ok {
+			return w.walk(appendPath(path, "items"), list.itemType())
+		}
+	case TypeIDMap:
+		if m, ok := t.(untypedMapSchema); ok {
+			if err := w.walk(appendPath(path, "keys"), m.keyType()); err != nil {
+				return err
+			}
+			return w.walk(appendPath(path, "values"), m.valueType())
+		}
+	case TypeIDTuple:
+		for i, item := range t.(*TupleSchema).Items() {
+			if err := w.walk(appendPath(path, fmt.Sprintf("[%d]", i)), item); err != nil {
+				return err
+			}
+		}
+	case TypeIDOneOfString:
+		if oneOf, ok := t.(*OneOfSchema[string]); ok {
+			return walkOneOf(w, path, oneOf)
+		}
+	case TypeIDOneOfInt:
+		if oneOf, ok := t.(*OneOfSchema[int64]); ok {
+			return walkOneOf(w, path, oneOf)
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkProperties(path []string, object Object) error {
+	key := objectKey(object)
+	w.active[key] = true
+	defer delete(w.active, key)
+
+	properties := object.Properties()
+	propertyIDs := make([]string, 0, len(properties))
+	for propertyID := range properties {
+		propertyIDs = append(propertyIDs, propertyID)
+	}
+	sort.Strings(propertyIDs)
+	for _, propertyID := range propertyIDs {
+		if err := w.walk(appendPath(path, propertyID), properties[propertyID].Type()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectKey identifies an object for the cycle detection. Objects that aren't an *ObjectSchema are identified by
+// their ID.
+func objectKey(object Object) any {
+	if objectSchema, ok := object.(*ObjectSchema); ok {
+		return objectSchema
+	}
+	return "id:" + object.ID()
+}
+
+func walkOneOf[KeyType int64 | string](w *walker, path []string, oneOf *OneOfSchema[KeyType]) error {
+	types := oneOf.Types()
+	keys := make([]KeyType, 0, len(types))
+	for key := range types {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		if err := w.walk(appendPath(path, fmt.Sprintf("%v", key)), types[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func walkTestScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewObjectSchema("node", map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+			"children": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewRefSchema("node", nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"labels": schema.NewPropertySchema(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"position": schema.NewPropertySchema(
+				schema.NewTupleSchema(schema.NewIntSchema(nil, nil, nil), schema.NewIntSchema(nil, nil, nil)),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"payload": schema.NewPropertySchema(
+				schema.NewOneOfStringSchema[any](map[string]schema.Object{
+					"text": schema.NewRefSchema("text", nil),
+				}, "kind", false),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		schema.NewObjectSchema("text", map[string]*schema.PropertySchema{
+			"body": schema.NewPropertySchema(schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil),
+		}),
+	)
+}
+
+func TestWalk(t *testing.T) {
+	var visited []string
+	assert.NoError(t, schema.Walk(walkTestScope(), func(path []string, t schema.Type) error {
+		visited = append(visited, strings.Join(path, ".")+"="+string(t.TypeID()))
+		return nil
+	}))
+	assert.Equals(t, visited, []string{
+		"=scope",
+		"=object",
+		"children=list",
+		"children.items=ref",
+		"labels=map",
+		"labels.keys=string",
+		"labels.values=integer",
+		"name=string",
+		"payload=one_of_string",
+		"payload.text=ref",
+		"payload.text=object",
+		"payload.text.body=string",
+		"position=tuple",
+		"position.[0]=integer",
+		"position.[1]=integer",
+	})
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	var visited []string
+	assert.NoError(t, schema.Walk(walkTestScope(), func(path []string, t schema.Type) error {
+		visited = append(visited, strings.Join(path, "."))
+		if t.TypeID() == schema.TypeIDMap || t.TypeID() == schema.TypeIDTuple || t.TypeID() == schema.TypeIDOneOfString {
+			return schema.SkipChildren
+		}
+		return nil
+	}))
+	assert.Equals(t, visited, []string{"", "", "children", "children.items", "labels", "name", "payload", "position"})
+}
+
+func TestWalkError(t *testing.T) {
+	expected := errors.New("sensitive data found")
+	err := schema.Walk(walkTestScope(), func(path []string, t schema.Type) error {
+		if strings.Join(path, ".") == "name" {
+			return expected
+		}
+		return nil
+	})
+	assert.Equals(t, err, expected)
+}
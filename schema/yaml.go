@@ -0,0 +1,248 @@
+package schema
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnserializeYAML unserializes a YAML node, such as a parsed workflow input, into the type. The node is converted with
+// NormalizeYAML first, so YAML-specific values unserialize like their JSON counterparts.
+func UnserializeYAML(t Type, node *yaml.Node) (any, error) {
+	data, err := NormalizeYAML(node)
+	if err != nil {
+		return nil, err
+	}
+	return t.Unserialize(data)
+}
+
+// UnserializeYAMLWithOptions unserializes a YAML node like UnserializeYAML, handling keys that appear more than once in
+// a mapping as the options say.
+func UnserializeYAMLWithOptions(t Type, node *yaml.Node, options DecodeOptions) (any, error) {
+	data, err := NormalizeYAMLWithOptions(node, options)
+	if err != nil {
+		return nil, err
+	}
+	return t.Unserialize(data)
+}
+
+// NormalizeYAML converts a YAML node into the plain data the schemas unserialize. Decoding YAML into any produces
+// values that don't fit the schemas, so the node is converted as follows:
+//
+//   - Mappings become map[string]any. Keys that are not strings, such as 1 or true, are kept as written in the
+//     document, which the key types of map schemas accept.
+//   - Merge keys (<<) and aliases are resolved. Documents expanding aliases into much more data than they contain
+//     are rejected with the limits yaml.v3 applies when decoding.
+//   - Timestamps are kept as written, since the schemas have no time type.
+//   - Binary values are decoded from base64 into strings.
+//   - Other scalars become int, float64, bool, string or nil.
+//
+// Keys that appear more than once in a mapping keep their last value. Use NormalizeYAMLWithOptions to detect them.
+func NormalizeYAML(node *yaml.Node) (any, error) {
+	return NormalizeYAMLWithOptions(node, DecodeOptions{DuplicateKeys: DuplicateKeysLastWins})
+}
+
+// NormalizeYAMLWithOptions converts a YAML node like NormalizeYAML, handling keys that appear more than once in a
+// mapping as the options say. Keys set explicitly next to a merge key are not duplicates, they override the merged
+// entries.
+func NormalizeYAMLWithOptions(node *yaml.Node, options DecodeOptions) (any, error) {
+	if node == nil {
+		return nil, nil
+	}
+	return (&yamlNormalizer{options: options}).node(node, []string{})
+}
+
+// The limits of alias expansion, which are the ones yaml.v3 applies when decoding. Below yamlAliasRatioRangeLow
+// decoded nodes, up to 99% of them may come from aliases. The allowed ratio then falls linearly to 10% at
+// yamlAliasRatioRangeHigh nodes, so a small document can't expand into a huge amount of data (a "billion laughs").
+const (
+	yamlAliasRatioRangeLow  = 400000
+	yamlAliasRatioRangeHigh = 4000000
+	yamlAliasRatioRange     = float64(yamlAliasRatioRangeHigh - yamlAliasRatioRangeLow)
+)
+
+// yamlNormalizer converts YAML nodes, counting the nodes it decodes to reject documents that expand aliases
+// excessively.
+type yamlNormalizer struct {
+	options DecodeOptions
+	// decodeCount is the number of nodes decoded, and aliasCount the number of those decoded through an alias.
+	decodeCount int
+	aliasCount  int
+	// aliasDepth is the number of aliases the current node is decoded through.
+	aliasDepth int
+}
+
+// allowedAliasRatio returns the share of the decoded nodes that may come from aliases.
+func allowedAliasRatio(decodeCount int) float64 {
+	switch {
+	case decodeCount <= yamlAliasRatioRangeLow:
+		return 0.99
+	case decodeCount >= yamlAliasRatioRangeHigh:
+		return 0.10
+	default:
+		return 0.99 - 0.89*(float64(decodeCount-yamlAliasRatioRangeLow)/yamlAliasRatioRange)
+	}
+}
+
+// count counts the node as decoded and returns an error if too many of the decoded nodes come from aliases.
+func (n *yamlNormalizer) count(node *yaml.Node, path []string) error {
+	n.decodeCount++
+	if n.aliasDepth > 0 {
+		n.aliasCount++
+	}
+	if n.aliasCount > 100 && n.decodeCount > 1000 &&
+		float64(n.aliasCount)/float64(n.decodeCount) > allowedAliasRatio(n.decodeCount) {
+		return yamlNodeError(node, path, "document contains excessive aliasing")
+	}
+	return nil
+}
+
+func (n *yamlNormalizer) node(node *yaml.Node, path []string) (any, error) {
+	if err := n.count(node, path); err != nil {
+		return nil, err
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return n.node(node.Content[0], path)
+	case yaml.AliasNode:
+		n.aliasDepth++
+		defer func() { n.aliasDepth-- }()
+		return n.node(node.Alias, path)
+	case yaml.SequenceNode:
+		result := make([]any, len(node.Content))
+		for i, item := range node.Content {
+			value, err := n.node(item, append(path, fmt.Sprintf("%d", i)))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = value
+		}
+		return result, nil
+	case yaml.MappingNode:
+		result := map[string]any{}
+		if err := n.mapping(node, path, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case yaml.ScalarNode:
+		return normalizeYAMLScalar(node, path)
+	default:
+		return nil, yamlNodeError(node, path, fmt.Sprintf("unsupported YAML node kind %d", node.Kind))
+	}
+}
+
+// mapping adds the entries of the mapping to the result. Entries of merged mappings don't override the entries set
+// explicitly, wherever the merge key is.
+func (n *yamlNormalizer) mapping(node *yaml.Node, path []string, result map[string]any) error {
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+			merged = append(merged, valueNode)
+			continue
+		}
+		if keyNode.Kind == yaml.AliasNode {
+			keyNode = keyNode.Alias
+		}
+		if keyNode.Kind != yaml.ScalarNode {
+			return yamlNodeError(keyNode, path, "mapping keys must be scalars")
+		}
+		if _, ok := result[keyNode.Value]; ok {
+			if err := n.options.duplicateKey(duplicateYAMLKeyError(keyNode, path)); err != nil {
+				return err
+			}
+		}
+		value, err := n.node(valueNode, append(path, keyNode.Value))
+		if err != nil {
+			return err
+		}
+		result[keyNode.Value] = value
+	}
+	for _, mergedNode := range merged {
+		if err := n.merge(mergedNode, path, result, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge adds the entries of the merged mapping, or of each mapping of a merged sequence, to the result without
+// overriding the entries already set.
+func (n *yamlNormalizer) merge(node *yaml.Node, path []string, result map[string]any, allowSequence bool) error {
+	if node.Kind == yaml.AliasNode {
+		// Merged aliases count towards the limit of alias expansion like any other alias.
+		n.aliasDepth++
+		defer func() { n.aliasDepth-- }()
+		node = node.Alias
+	}
+	if err := n.count(node, path); err != nil {
+		return err
+	}
+	if node.Kind == yaml.SequenceNode && allowSequence {
+		for _, source := range node.Content {
+			if err := n.merge(source, path, result, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return yamlNodeError(node, path, "merge keys must refer to mappings")
+	}
+	entries := map[string]any{}
+	if err := n.mapping(node, path, entries); err != nil {
+		return err
+	}
+	for key, value := range entries {
+		if _, ok := result[key]; !ok {
+			result[key] = value
+		}
+	}
+	return nil
+}
+
+func normalizeYAMLScalar(node *yaml.Node, path []string) (any, error) {
+	switch node.ShortTag() {
+	case "!!str", "!!timestamp":
+		return node.Value, nil
+	case "!!binary":
+		decoded, err := base64.StdEncoding.DecodeString(node.Value)
+		if err != nil {
+			return nil, yamlNodeError(node, path, fmt.Sprintf("invalid binary value (%v)", err))
+		}
+		return string(decoded), nil
+	default:
+		var result any
+		if err := node.Decode(&result); err != nil {
+			return nil, yamlNodeError(node, path, err.Error())
+		}
+		return result, nil
+	}
+}
+
+// yamlNodeError returns an error for a node that can't be decoded. The path is copied, since the callers append to it
+// for the other entries.
+func yamlNodeError(node *yaml.Node, path []string, message string) *UnserializationError {
+	return &UnserializationError{
+		Message: describeYAMLNode(node, message),
+		Path:    slices.Clone(path),
+	}
+}
+
+func duplicateYAMLKeyError(keyNode *yaml.Node, path []string) *ConstraintError {
+	return &ConstraintError{
+		Message:    describeYAMLNode(keyNode, fmt.Sprintf("Duplicate key '%s' in YAML mapping", keyNode.Value)),
+		Path:       append(slices.Clone(path), keyNode.Value),
+		Constraint: ConstraintDuplicateKey,
+		Actual:     keyNode.Value,
+	}
+}
+
+func describeYAMLNode(node *yaml.Node, message string) string {
+	return fmt.Sprintf("%s (line %d, column %d)", message, node.Line, node.Column)
+}
@@ -0,0 +1,140 @@
+package schema_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func parseYAML(t *testing.T, data string) *yaml.Node {
+	node := &yaml.Node{}
+	assert.NoError(t, yaml.Unmarshal([]byte(data), node))
+	return node
+}
+
+func TestUnserializeYAML(t *testing.T) {
+	input := schema.NewObjectSchema("input", map[string]*schema.PropertySchema{
+		"name": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+		),
+		"started": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+		),
+		"key": schema.NewPropertySchema(
+			schema.NewStringSchema(nil, nil, nil), nil, true, nil, nil, nil, nil, nil,
+		),
+		"ports": schema.NewPropertySchema(
+			schema.NewMapSchema(schema.NewIntSchema(nil, nil, nil), schema.NewStringSchema(nil, nil, nil), nil, nil),
+			nil, true, nil, nil, nil, nil, nil,
+		),
+	})
+
+	result := assert.NoErrorR[any](t)(schema.UnserializeYAML(input, parseYAML(t, `
+defaults: &defaults
+  name: default
+  started: 2024-01-02T03:04:05Z
+input:
+  <<: *defaults
+  name: test
+  key: !!binary aGVsbG8=
+  ports:
+    80: http
+    443: https
+`).Content[0].Content[3]))
+	assert.Equals(t, result.(map[string]any), map[string]any{
+		"name":    "test",
+		"started": "2024-01-02T03:04:05Z",
+		"key":     "hello",
+		"ports":   map[int64]string{80: "http", 443: "https"},
+	})
+}
+
+func TestNormalizeYAML(t *testing.T) {
+	result := assert.NoErrorR[any](t)(schema.NormalizeYAML(parseYAML(t, `
+list: [1, 1.5, true, null, text]
+1: one
+`)))
+	assert.Equals(t, result, any(map[string]any{
+		"list": []any{1, 1.5, true, nil, "text"},
+		"1":    "one",
+	}))
+
+	empty := assert.NoErrorR[any](t)(schema.NormalizeYAML(nil))
+	assert.Nil(t, empty)
+
+	_, err := schema.NormalizeYAML(parseYAML(t, "? [a, b]\n: c\n"))
+	assert.Error(t, err)
+	_, err = schema.NormalizeYAML(parseYAML(t, "key: !!binary '%%%'\n"))
+	assert.Error(t, err)
+}
+
+func TestNormalizeYAMLDuplicateKeys(t *testing.T) {
+	node := parseYAML(t, `
+base: &base
+  a: 1
+items:
+  - <<: *base
+    a: 2
+  - b: 1
+    b: 2
+`)
+	// Overriding a merged key is not a duplicate.
+	_, err := schema.NormalizeYAMLWithOptions(node, schema.DecodeOptions{})
+	var constraintErr *schema.ConstraintError
+	assert.Equals(t, errors.As(err, &constraintErr), true)
+	assert.Equals(t, constraintErr.Constraint, schema.ConstraintDuplicateKey)
+	assert.Equals(t, constraintErr.Path, []string{"items", "1", "b"})
+	assert.Contains(t, constraintErr.Message, "line 8")
+
+	var warnings []*schema.ConstraintError
+	result := assert.NoErrorR[any](t)(schema.NormalizeYAMLWithOptions(node, schema.DecodeOptions{
+		DuplicateKeys: schema.DuplicateKeysLastWins,
+		Warn: func(err *schema.ConstraintError) {
+			warnings = append(warnings, err)
+		},
+	}))
+	assert.Equals(t, result.(map[string]any)["items"], any([]any{
+		map[string]any{"a": 2},
+		map[string]any{"b": 2},
+	}))
+	assert.Equals(t, len(warnings), 1)
+	assert.Equals(t, warnings[0].Path, []string{"items", "1", "b"})
+
+	// NormalizeYAML keeps the last value without reporting it.
+	result = assert.NoErrorR[any](t)(schema.NormalizeYAML(node))
+	assert.Equals(t, result.(map[string]any)["items"].([]any)[1], any(map[string]any{"b": 2}))
+}
+
+// yamlAliasBomb returns a document in which every level refers to the previous one nine times, so it expands into 9^9
+// values. The format is a line with %[1]d for the level and %[2]d for the previous level.
+func yamlAliasBomb(level0 string, format string) string {
+	lines := []string{level0}
+	for i := 1; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf(format, i, i-1))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestNormalizeYAMLExcessiveAliasing(t *testing.T) {
+	for name, data := range map[string]string{
+		"sequence": yamlAliasBomb(
+			"l0: &l0 [x, x, x, x, x, x, x, x, x]",
+			"l%[1]d: &l%[1]d [*l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d]",
+		),
+		"merge": yamlAliasBomb(
+			"l0: &l0 {a: x, b: x, c: x, d: x, e: x, f: x, g: x, h: x, i: x}",
+			"l%[1]d: &l%[1]d {<<: [*l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d, *l%[2]d]}",
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := schema.NormalizeYAML(parseYAML(t, data))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "excessive aliasing")
+		})
+	}
+}
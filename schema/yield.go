@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// YieldPolicy makes the processing of large data cooperate with other goroutines. Validating or unserializing a
+// giant payload can keep a goroutine busy for a long time, and a plugin server handling concurrent requests is more
+// responsive if such work regularly lets others run and stops early when its request is cancelled.
+type YieldPolicy struct {
+	// Interval is the number of values processed between yield points. At each yield point, runtime.Gosched is
+	// called and the context is checked. Zero disables yielding.
+	Interval int
+}
+
+// DefaultYieldPolicy returns a policy that yields every 10000 values, which keeps the overhead negligible.
+func DefaultYieldPolicy() YieldPolicy {
+	return YieldPolicy{
+		Interval: 10_000,
+	}
+}
+
+// UnserializeAllContext unserializes the data like UnserializeAll, yielding according to the policy. If the context
+// is done at a yield point, it stops and returns an error wrapping the error of the context.
+func UnserializeAllContext(ctx context.Context, t Type, data any, policy YieldPolicy) (any, error) {
+	y := newYielder(ctx, policy)
+	result, errs := errorCollector{yield: y}.collect(t, data)
+	if y != nil && y.err != nil {
+		return nil, y.err
+	}
+	if len(errs) > 0 {
+		return nil, &ConstraintErrors{Errors: errs}
+	}
+	return result, nil
+}
+
+// ValidateAllContext validates the unserialized data like ValidateAll, yielding according to the policy. If the
+// context is done at a yield point, it stops and returns an error wrapping the error of the context.
+func ValidateAllContext(ctx context.Context, t Type, data any, policy YieldPolicy) error {
+	y := newYielder(ctx, policy)
+	_, errs := errorCollector{validate: true, yield: y}.collect(t, data)
+	if y != nil && y.err != nil {
+		return y.err
+	}
+	if len(errs) > 0 {
+		return &ConstraintErrors{Errors: errs}
+	}
+	return nil
+}
+
+// yielder counts the values processed by an operation and periodically yields the processor and checks the context.
+// A nil yielder never yields.
+type yielder struct {
+	ctx      context.Context
+	interval int
+	count    int
+	err      error
+}
+
+func newYielder(ctx context.Context, policy YieldPolicy) *yielder {
+	if policy.Interval <= 0 {
+		return nil
+	}
+	return &yielder{ctx: ctx, interval: policy.Interval}
+}
+
+// point counts a processed value. Once the context is done, it returns a ConstraintError for every value, so the
+// operation stops quickly, and the error of the context is kept in err.
+func (y *yielder) point() *ConstraintError {
+	if y == nil {
+		return nil
+	}
+	if y.err == nil {
+		y.count++
+		if y.count < y.interval {
+			return nil
+		}
+		y.count = 0
+		runtime.Gosched()
+		if err := y.ctx.Err(); err != nil {
+			y.err = fmt.Errorf("processing was interrupted (%w)", err)
+		}
+	}
+	if y.err != nil {
+		return &ConstraintError{Message: y.err.Error()}
+	}
+	return nil
+}
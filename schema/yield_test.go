@@ -0,0 +1,52 @@
+package schema_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+func TestUnserializeAllContext(t *testing.T) {
+	listType := schema.NewListSchema(schema.NewIntSchema(nil, nil, nil), nil, nil)
+	data := make([]any, 1000)
+	for i := range data {
+		data[i] = i
+	}
+	policy := schema.YieldPolicy{Interval: 10}
+
+	result := assert.NoErrorR[any](t)(schema.UnserializeAllContext(context.Background(), listType, data, policy))
+	assert.Equals(t, len(result.([]int64)), 1000)
+	assert.NoError(t, schema.ValidateAllContext(context.Background(), listType, result, policy))
+
+	_, err := schema.UnserializeAllContext(context.Background(), listType, []any{"a"}, policy)
+	var constraintErrors *schema.ConstraintErrors
+	assert.Equals(t, errors.As(err, &constraintErrors), true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = schema.UnserializeAllContext(ctx, listType, data, policy)
+	assert.Equals(t, errors.Is(err, context.Canceled), true)
+	err = schema.ValidateAllContext(ctx, listType, result, policy)
+	assert.Equals(t, errors.Is(err, context.Canceled), true)
+
+	// Without yielding, the context is never checked.
+	_, err = schema.UnserializeAllContext(ctx, listType, data, schema.YieldPolicy{})
+	assert.NoError(t, err)
+}
+
+func TestCallableSchemaWithYieldPolicy(t *testing.T) {
+	callable := schema.NewCallableSchema(testStepSchema).WithYieldPolicy(schema.YieldPolicy{Interval: 1})
+	input := map[string]any{"name": "Arca Lot"}
+
+	outputID, _, err := callable.CallStep(context.Background(), t.Name(), "hello", input)
+	assert.NoError(t, err)
+	assert.Equals(t, outputID, "success")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = callable.CallStep(ctx, t.Name(), "hello", input)
+	assert.Equals(t, errors.Is(err, context.Canceled), true)
+}
@@ -0,0 +1,239 @@
+package schematest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// TypeCases holds the serialized values TestTypeCompliance checks a type with.
+type TypeCases struct {
+	// Valid holds serialized values the type must accept.
+	Valid []any
+	// Invalid holds serialized values the type must reject.
+	Invalid []any
+}
+
+// unexpectedValue is a Go type no schema type unserializes to.
+type unexpectedValue struct{}
+
+// TestTypeCompliance checks that a type, typically one implemented outside the SDK, behaves like the built-in types,
+// so engines and other plugins can rely on it. Each of the following is checked in a subtest:
+//
+//   - Valid values unserialize to a value of the ReflectedType, which Validate accepts. Serialize turns it back into
+//     data that ValidateCompatibility accepts and that survives another round trip, as checked by CheckRoundTrip.
+//   - Invalid values are rejected by Unserialize with an error, without panicking.
+//   - Go types the type doesn't unserialize to are rejected by Validate and Serialize with an error, without panicking.
+//   - The type is compatible with itself and its references are linked.
+//   - The valid and invalid values give the same results when they are unserialized from several goroutines at once.
+//     Run the tests with -race to also catch unsynchronized caches.
+func TestTypeCompliance(t *testing.T, typ schema.Type, cases TypeCases) {
+	t.Helper()
+	if len(cases.Valid) == 0 {
+		t.Fatalf("at least one valid value is needed to check the type")
+	}
+	t.Run("valid", func(t *testing.T) {
+		for i, value := range cases.Valid {
+			if err := checkValidValue(typ, value); err != nil {
+				t.Errorf("valid value %d: %v\nvalue: %#v", i, err, value)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for i, value := range cases.Invalid {
+			if err := checkInvalidValue(typ, value); err != nil {
+				t.Errorf("invalid value %d: %v\nvalue: %#v", i, err, value)
+			}
+		}
+	})
+	t.Run("unexpected", func(t *testing.T) {
+		if err := noPanic(func() error { return typ.Validate(unexpectedValue{}) }); err == nil {
+			t.Errorf("Validate accepted a %T", unexpectedValue{})
+		}
+		if err := noPanic(func() error {
+			_, err := typ.Serialize(unexpectedValue{})
+			return err
+		}); err == nil {
+			t.Errorf("Serialize accepted a %T", unexpectedValue{})
+		}
+	})
+	t.Run("schema", func(t *testing.T) {
+		if typ.TypeID() == "" {
+			t.Errorf("TypeID returned an empty type ID")
+		}
+		if err := noPanic(typ.ValidateReferences); err != nil {
+			t.Errorf("ValidateReferences failed (%v)", err)
+		}
+		if err := noPanic(func() error { return typ.ValidateCompatibility(typ) }); err != nil {
+			t.Errorf("the type is not compatible with itself (%v)", err)
+		}
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		wg := &sync.WaitGroup{}
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i, value := range cases.Valid {
+					if err := checkValidValue(typ, value); err != nil {
+						t.Errorf("valid value %d from goroutine %d: %v", i, g, err)
+					}
+				}
+				for i, value := range cases.Invalid {
+					if err := checkInvalidValue(typ, value); err != nil {
+						t.Errorf("invalid value %d from goroutine %d: %v", i, g, err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func checkValidValue(typ schema.Type, value any) error {
+	var unserialized any
+	if err := noPanic(func() (err error) {
+		unserialized, err = typ.Unserialize(value)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to unserialize (%w)", err)
+	}
+	if unserialized != nil && !reflect.TypeOf(unserialized).AssignableTo(typ.ReflectedType()) {
+		return fmt.Errorf(
+			"unserialized to %T, which is not assignable to the reflected type %s",
+			unserialized,
+			typ.ReflectedType(),
+		)
+	}
+	if err := noPanic(func() error { return typ.Validate(unserialized) }); err != nil {
+		return fmt.Errorf("the unserialized value failed validation (%w)", err)
+	}
+	var serialized any
+	if err := noPanic(func() (err error) {
+		serialized, err = typ.Serialize(unserialized)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to serialize (%w)", err)
+	}
+	if err := noPanic(func() error { return typ.ValidateCompatibility(serialized) }); err != nil {
+		return fmt.Errorf("the serialized value is not compatible (%w)", err)
+	}
+	if err := roundTrip(typ, serialized); err != nil {
+		return fmt.Errorf("the serialized value does not round trip (%w)", err)
+	}
+	return nil
+}
+
+func checkInvalidValue(typ schema.Type, value any) error {
+	err := noPanic(func() error {
+		_, err := typ.Unserialize(value)
+		return err
+	})
+	if err == nil {
+		return fmt.Errorf("the value was unserialized without an error")
+	}
+	var panicked panicError
+	if errors.As(err, &panicked) {
+		return err
+	}
+	return nil
+}
+
+// panicError is returned by noPanic if the function panicked.
+type panicError struct {
+	value any
+}
+
+func (p panicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+// noPanic calls the function, returning the panic as an error if it panics.
+func noPanic(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError{r}
+		}
+	}()
+	return f()
+}
+
+// TestStateBackendCompliance checks that a schema.StateBackend behaves like the memory backend, so state stores can
+// use it. The backend must be empty. Values are stored in the form schemas serialize to, such as maps with string keys,
+// lists and strings, and must be loaded back unchanged.
+func TestStateBackendCompliance(t *testing.T, backend schema.StateBackend) {
+	t.Helper()
+	t.Run("missing", func(t *testing.T) {
+		value, found, err := backend.LoadState("missing")
+		if err != nil || found || value != nil {
+			t.Errorf("loading a key that is not set returned %v, %t, %v", value, found, err)
+		}
+		if err := backend.DeleteState("missing"); err != nil {
+			t.Errorf("deleting a key that is not set failed (%v)", err)
+		}
+	})
+	t.Run("store", func(t *testing.T) {
+		for _, value := range []any{
+			"value",
+			[]any{"a", "b"},
+			map[string]any{"token": "secret", "handles": []any{"1", "2"}},
+		} {
+			if err := backend.StoreState("key", value); err != nil {
+				t.Fatalf("failed to store %v (%v)", value, err)
+			}
+			loaded, found, err := backend.LoadState("key")
+			if err != nil || !found {
+				t.Fatalf("failed to load the stored value (found: %t, error: %v)", found, err)
+			}
+			if !reflect.DeepEqual(loaded, value) {
+				t.Errorf("loaded %#v instead of %#v", loaded, value)
+			}
+		}
+	})
+	t.Run("delete", func(t *testing.T) {
+		if err := backend.StoreState("deleted", "value"); err != nil {
+			t.Fatalf("failed to store value (%v)", err)
+		}
+		if err := backend.DeleteState("deleted"); err != nil {
+			t.Fatalf("failed to delete value (%v)", err)
+		}
+		if _, found, err := backend.LoadState("deleted"); err != nil || found {
+			t.Errorf("the deleted key was loaded (found: %t, error: %v)", found, err)
+		}
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		testConcurrentStateAccess(t, backend)
+	})
+}
+
+// testConcurrentStateAccess stores, loads and deletes separate keys from several goroutines at once.
+func testConcurrentStateAccess(t *testing.T, backend schema.StateBackend) {
+	t.Helper()
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("concurrent-%d", g)
+			for i := 0; i < 20; i++ {
+				value := fmt.Sprintf("%d", i)
+				if err := backend.StoreState(key, value); err != nil {
+					t.Errorf("failed to store %s (%v)", key, err)
+					return
+				}
+				if loaded, _, err := backend.LoadState(key); err != nil || loaded != value {
+					t.Errorf("loaded %v instead of %s for %s (%v)", loaded, value, key, err)
+					return
+				}
+			}
+			if err := backend.DeleteState(key); err != nil {
+				t.Errorf("failed to delete %s (%v)", key, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,106 @@
+package schematest_test
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/schematest"
+)
+
+func TestTypeComplianceBuiltins(t *testing.T) {
+	for name, testCase := range map[string]struct {
+		typ   schema.Type
+		cases schematest.TypeCases
+	}{
+		"string": {
+			schema.NewStringSchema(schema.IntPointer(1), nil, regexp.MustCompile(`^[a-z]+$`)),
+			schematest.TypeCases{Valid: []any{"a", "abc"}, Invalid: []any{"", "ABC", []any{}}},
+		},
+		"int": {
+			schema.NewIntSchema(schema.IntPointer(0), nil, schema.UnitBytes),
+			schematest.TypeCases{Valid: []any{0, int64(5), "1kB"}, Invalid: []any{-1, "five", map[string]any{}}},
+		},
+		"list": {
+			schema.NewListSchema(schema.NewBoolSchema(), nil, schema.IntPointer(2)),
+			schematest.TypeCases{Valid: []any{[]any{}, []any{true, false}}, Invalid: []any{[]any{true, true, true}, "x"}},
+		},
+		"scope": {
+			testScope(),
+			schematest.TypeCases{
+				Valid: []any{map[string]any{
+					"name":    "job",
+					"image":   "quay.io/arcalot",
+					"timeout": "5s",
+				}},
+				Invalid: []any{map[string]any{"name": "job"}, "job"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			schematest.TestTypeCompliance(t, testCase.typ, testCase.cases)
+		})
+	}
+}
+
+// addressSchema is a type implemented outside the SDK. It unserializes strings to IP addresses.
+type addressSchema struct {
+	schema.ScalarType
+}
+
+func (a *addressSchema) TypeID() schema.TypeID {
+	return schema.TypeIDString
+}
+
+func (a *addressSchema) ReflectedType() reflect.Type {
+	return reflect.TypeOf(netip.Addr{})
+}
+
+func (a *addressSchema) Unserialize(data any) (any, error) {
+	text, ok := data.(string)
+	if !ok {
+		return nil, &schema.ConstraintError{Message: fmt.Sprintf("%T is not a valid address", data)}
+	}
+	address, err := netip.ParseAddr(text)
+	if err != nil {
+		return nil, &schema.ConstraintError{Message: fmt.Sprintf("invalid address %q", text), Cause: err}
+	}
+	return address, nil
+}
+
+func (a *addressSchema) Validate(data any) error {
+	if _, ok := data.(netip.Addr); !ok {
+		return &schema.ConstraintError{Message: fmt.Sprintf("%T is not an address", data)}
+	}
+	return nil
+}
+
+func (a *addressSchema) ValidateCompatibility(typeOrData any) error {
+	switch typeOrData.(type) {
+	case *addressSchema, string:
+		return nil
+	default:
+		return &schema.ConstraintError{Message: fmt.Sprintf("%T is not compatible with an address", typeOrData)}
+	}
+}
+
+func (a *addressSchema) Serialize(data any) (any, error) {
+	if err := a.Validate(data); err != nil {
+		return nil, err
+	}
+	return data.(netip.Addr).String(), nil
+}
+
+func TestTypeComplianceCustom(t *testing.T) {
+	schematest.TestTypeCompliance(t, &addressSchema{}, schematest.TypeCases{
+		Valid:   []any{"127.0.0.1", "::1"},
+		Invalid: []any{"localhost", 1},
+	})
+}
+
+func TestStateBackendCompliance(t *testing.T) {
+	schematest.TestStateBackendCompliance(t, schema.NewMemoryStateBackend())
+}
@@ -0,0 +1,108 @@
+// Package schematest provides helpers for property-based testing of schemas and the types they map to, and compliance
+// checks for custom implementations of the extension points of the SDK, such as schema types and state backends.
+package schematest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Arbitrary returns a random valid serialized value of the type. Values honor the constraints of the type, such as
+// string patterns, length and number limits, enums and one-of variants, and favor the edge cases: numbers at the
+// limits, multiples of units and non-ASCII strings. The same random source returns the same values, so failing cases
+// can be reproduced from the seed.
+func Arbitrary(t schema.Type, rng *rand.Rand) any {
+	return schema.GenerateRandomExample(t, rng)
+}
+
+// CheckRoundTrip unserializes and serializes the given number of arbitrary values of the type and fails the test if
+// a value is rejected or the round trip loses any of its data. Serializing may add values, such as defaults, but may
+// not drop or change the ones that were set. The seed is logged on failure.
+func CheckRoundTrip(t testing.TB, typ schema.Type, seed int64, iterations int) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // Test data does not need a secure source.
+	for i := 0; i < iterations; i++ {
+		value := Arbitrary(typ, rng)
+		if err := roundTrip(typ, value); err != nil {
+			t.Fatalf("round trip of value %d with seed %d failed: %v\nvalue: %#v", i, seed, err, value)
+		}
+	}
+}
+
+func roundTrip(typ schema.Type, value any) error {
+	unserialized, err := typ.Unserialize(value)
+	if err != nil {
+		return fmt.Errorf("failed to unserialize (%w)", err)
+	}
+	serialized, err := typ.Serialize(unserialized)
+	if err != nil {
+		return fmt.Errorf("failed to serialize (%w)", err)
+	}
+	if path, ok := contains(reflect.ValueOf(serialized), reflect.ValueOf(value), "$"); !ok {
+		return fmt.Errorf("the value at %s was lost or changed\nserialized: %#v", path, serialized)
+	}
+	return nil
+}
+
+// contains checks that actual holds everything expected holds. Maps may have additional keys. It returns the path of
+// the first difference.
+func contains(actual reflect.Value, expected reflect.Value, path string) (string, bool) {
+	actual = unwrapInterface(actual)
+	expected = unwrapInterface(expected)
+	if !actual.IsValid() || !expected.IsValid() {
+		return path, actual.IsValid() == expected.IsValid()
+	}
+	switch expected.Kind() {
+	case reflect.Map:
+		if actual.Kind() != reflect.Map {
+			return path, false
+		}
+		for _, key := range expected.MapKeys() {
+			actualValue := mapIndex(actual, key)
+			if !actualValue.IsValid() {
+				return fmt.Sprintf("%s[%v]", path, key.Interface()), false
+			}
+			if diffPath, ok := contains(actualValue, expected.MapIndex(key), fmt.Sprintf("%s[%v]", path, key)); !ok {
+				return diffPath, false
+			}
+		}
+		return "", true
+	case reflect.Slice:
+		if actual.Kind() != reflect.Slice || actual.Len() != expected.Len() {
+			return path, false
+		}
+		for i := 0; i < expected.Len(); i++ {
+			if diffPath, ok := contains(actual.Index(i), expected.Index(i), fmt.Sprintf("%s[%d]", path, i)); !ok {
+				return diffPath, false
+			}
+		}
+		return "", true
+	default:
+		return path, reflect.DeepEqual(actual.Interface(), expected.Interface())
+	}
+}
+
+// mapIndex looks the key up in the map, converting it to the key type of the map if the types differ.
+func mapIndex(m reflect.Value, key reflect.Value) reflect.Value {
+	key = unwrapInterface(key)
+	keyType := m.Type().Key()
+	switch {
+	case key.Type().AssignableTo(keyType):
+	case key.Type().ConvertibleTo(keyType):
+		key = key.Convert(keyType)
+	default:
+		return reflect.Value{}
+	}
+	return m.MapIndex(key)
+}
+
+func unwrapInterface(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v
+}
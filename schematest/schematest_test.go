@@ -0,0 +1,161 @@
+package schematest_test
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/schematest"
+)
+
+type testJob struct {
+	Name    string          `json:"name"`
+	Image   string          `json:"image"`
+	Timeout time.Duration   `json:"timeout"`
+	Ratio   *float64        `json:"ratio"`
+	Tags    []string        `json:"tags"`
+	Env     map[string]any  `json:"env"`
+	Target  any             `json:"target"`
+	Retries map[int64]int64 `json:"retries"`
+}
+
+func testScope() *schema.ScopeSchema {
+	return schema.NewScopeSchema(
+		schema.NewStructMappedObjectSchema[*testJob]("job", map[string]*schema.PropertySchema{
+			"name": schema.NewPropertySchema(
+				schema.NewStringSchema(schema.IntPointer(1), schema.IntPointer(16), regexp.MustCompile(`^[a-z][a-z0-9-]*$`)),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"image": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, regexp.MustCompile(`^(quay|docker)\.io/\w+(:\d{1,3})?$`)),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"timeout": schema.NewPropertySchema(
+				schema.NewIntSchema(schema.IntPointer(0), schema.PointerTo(int64(time.Hour)), schema.UnitDurationNanoseconds),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"ratio": schema.NewPropertySchema(
+				schema.NewFloatSchema(schema.PointerTo(-1.0), schema.PointerTo(1.0), nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"tags": schema.NewPropertySchema(
+				schema.NewListSchema(schema.NewStringSchema(schema.IntPointer(2), schema.IntPointer(5), nil), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"env": schema.NewPropertySchema(
+				schema.NewMapSchema(schema.NewStringSchema(nil, nil, nil), schema.NewAnySchema(), nil, nil),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"target": schema.NewPropertySchema(
+				schema.NewOneOfStringSchema[any](map[string]schema.Object{
+					"host": schema.NewRefSchema("host", nil),
+					"name": schema.NewOneOfValueVariant("name", schema.NewStringSchema(nil, nil, nil)),
+				}, "kind", false),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"retries": schema.NewPropertySchema(
+				schema.NewMapSchema(
+					schema.NewIntSchema(schema.IntPointer(400), schema.IntPointer(599), nil),
+					schema.NewIntSchema(schema.IntPointer(0), schema.IntPointer(5), nil),
+					nil,
+					nil,
+				),
+				nil,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+		schema.NewObjectSchema("host", map[string]*schema.PropertySchema{
+			"address": schema.NewPropertySchema(
+				schema.NewStringSchema(nil, nil, regexp.MustCompile(`^\d{1,3}(\.\d{1,3}){3}$`)),
+				nil,
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+		}),
+	)
+}
+
+func TestArbitrary(t *testing.T) {
+	scope := testScope()
+	rng := rand.New(rand.NewSource(1)) //nolint:gosec // Test data does not need a secure source.
+	for i := 0; i < 200; i++ {
+		assert.NoErrorR[any](t)(scope.Unserialize(schematest.Arbitrary(scope, rng)))
+	}
+}
+
+func TestArbitraryPatterns(t *testing.T) {
+	rng := rand.New(rand.NewSource(1)) //nolint:gosec // Test data does not need a secure source.
+	for _, pattern := range []string{
+		`^[A-Z]{2,4}-\d+$`,
+		`^(?i)yes|no$`,
+		`^[^/\s]+(/[^/\s]+)*$`,
+		`^\S+@\S+\.[a-z]{2,}$`,
+		`^.?x{3}$`,
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			s := schema.NewStringSchema(nil, nil, regexp.MustCompile(pattern))
+			for i := 0; i < 50; i++ {
+				assert.NoErrorR[any](t)(s.Unserialize(schematest.Arbitrary(s, rng)))
+			}
+			assert.NoErrorR[any](t)(s.Unserialize(schema.GenerateExample(s)))
+		})
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	schematest.CheckRoundTrip(t, testScope(), 1, 200)
+}
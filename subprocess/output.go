@@ -0,0 +1,100 @@
+package subprocess
+
+import (
+	"bytes"
+	"context"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Stream identifies the output stream a line was written to.
+type Stream string
+
+const (
+	// StreamStdout is the standard output.
+	StreamStdout Stream = "stdout"
+	// StreamStderr is the standard error.
+	StreamStderr Stream = "stderr"
+)
+
+// maxLineBytes limits the length of the lines emitted as signals. Longer lines are split.
+const maxLineBytes = 64 * 1024
+
+// OutputLine is the data of the signal emitted for each output line.
+type OutputLine struct {
+	// Stream is the output stream the line was written to.
+	Stream Stream `json:"stream"`
+	// Line holds the line without the trailing newline.
+	Line string `json:"line"`
+}
+
+// outputWriter captures the output of a stream up to a size limit and emits its lines as signals.
+type outputWriter struct {
+	ctx       context.Context
+	stream    Stream
+	signalID  string
+	limit     int64
+	captured  bytes.Buffer
+	truncated bool
+	line      []byte
+}
+
+func newOutputWriter(ctx context.Context, stream Stream, limit int64, signalID string) *outputWriter {
+	return &outputWriter{
+		ctx:      ctx,
+		stream:   stream,
+		signalID: signalID,
+		limit:    limit,
+	}
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	remaining := w.limit - int64(w.captured.Len())
+	switch {
+	case int64(len(p)) <= remaining:
+		w.captured.Write(p)
+	default:
+		w.captured.Write(p[:remaining])
+		w.truncated = true
+	}
+	if w.signalID == "" {
+		return len(p), nil
+	}
+	data := p
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			w.line = append(w.line, data...)
+			if len(w.line) >= maxLineBytes {
+				w.flush()
+			}
+			break
+		}
+		w.line = append(w.line, data[:i]...)
+		w.emit()
+		data = data[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits the last line if it did not end with a newline.
+func (w *outputWriter) flush() {
+	if len(w.line) > 0 {
+		w.emit()
+	}
+}
+
+func (w *outputWriter) emit() {
+	line := string(bytes.TrimSuffix(w.line, []byte("\r")))
+	w.line = w.line[:0]
+	if w.signalID == "" {
+		return
+	}
+	if err := schema.EmitSignal(w.ctx, w.signalID, &OutputLine{Stream: w.stream, Line: line}); err != nil {
+		// The command keeps running, the output is still captured.
+		schema.DebugLogf(
+			w.ctx, "Failed to emit %s line as signal %s, no longer streaming the output (%v)", w.stream, w.signalID, err,
+		)
+		w.signalID = ""
+	}
+}
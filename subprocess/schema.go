@@ -0,0 +1,134 @@
+package subprocess
+
+import (
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// NewResultSchema creates the object schema of Result. Steps can use it in their output schema to return the result
+// of Run as is. It serializes a *Result.
+func NewResultSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[*Result](
+		"CommandResult",
+		map[string]*schema.PropertySchema{
+			"exit_code": schema.NewPropertySchema(
+				schema.NewIntSchema(nil, nil, nil),
+				schema.NewDisplayValue(
+					schema.PointerTo("Exit code"),
+					schema.PointerTo("Exit code of the command, or -1 if it was killed by a signal."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"stdout": outputProperty("Standard output", "Captured standard output of the command."),
+			"stdout_truncated": flagProperty(
+				"Standard output truncated",
+				"Whether the standard output exceeded the size limit and was cut off.",
+			),
+			"stderr": outputProperty("Standard error", "Captured standard error of the command."),
+			"stderr_truncated": flagProperty(
+				"Standard error truncated",
+				"Whether the standard error exceeded the size limit and was cut off.",
+			),
+			"duration": schema.NewPropertySchema(
+				schema.NewIntSchema(schema.IntPointer(0), nil, schema.UnitDurationNanoseconds),
+				schema.NewDisplayValue(
+					schema.PointerTo("Duration"),
+					schema.PointerTo("Time the command ran."),
+					nil,
+				),
+				true,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			),
+			"timed_out": flagProperty(
+				"Timed out",
+				"Whether the command was killed because it exceeded the timeout.",
+			),
+		},
+	)
+}
+
+// NewOutputSignalSchema creates the schema of the signal Run emits for each output line. Add it to the signal
+// emitters of the step and pass the same ID in Options.OutputSignalID.
+func NewOutputSignalSchema(id string) *schema.SignalSchema {
+	return schema.NewSignalSchema(
+		id,
+		schema.NewScopeSchema(
+			schema.NewStructMappedObjectSchema[*OutputLine](
+				"CommandOutputLine",
+				map[string]*schema.PropertySchema{
+					"stream": schema.NewPropertySchema(
+						schema.NewStringEnumSchema(map[string]*schema.DisplayValue{
+							string(StreamStdout): {NameValue: schema.PointerTo("Standard output")},
+							string(StreamStderr): {NameValue: schema.PointerTo("Standard error")},
+						}),
+						schema.NewDisplayValue(
+							schema.PointerTo("Stream"),
+							schema.PointerTo("Output stream the line was written to."),
+							nil,
+						),
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+					"line": schema.NewPropertySchema(
+						schema.NewStringSchema(nil, nil, nil),
+						schema.NewDisplayValue(
+							schema.PointerTo("Line"),
+							schema.PointerTo("Output line without the trailing newline."),
+							nil,
+						),
+						true,
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+					),
+				},
+			),
+		),
+		schema.NewDisplayValue(
+			schema.PointerTo("Command output"),
+			schema.PointerTo("A line written by the command the step runs."),
+			nil,
+		),
+	)
+}
+
+func outputProperty(name string, description string) *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewStringSchema(nil, nil, nil),
+		schema.NewDisplayValue(&name, &description, nil),
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func flagProperty(name string, description string) *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewBoolSchema(),
+		schema.NewDisplayValue(&name, &description, nil),
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
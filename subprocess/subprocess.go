@@ -0,0 +1,109 @@
+// Package subprocess runs commands for plugins wrapping CLI tools. Run captures the output, exit code and duration of
+// a command into a Result, which steps can return as their output by using the schema from NewResultSchema. The
+// output lines can also be streamed as signals while the command runs.
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"go.flow.arcalot.io/pluginsdk/clock"
+)
+
+// DefaultMaxOutputBytes is the number of bytes captured of each output stream if Options.MaxOutputBytes is zero.
+const DefaultMaxOutputBytes = 1024 * 1024
+
+// Options configures how Run runs a command.
+type Options struct {
+	// Timeout limits the time the command may run. The command is killed when it expires. Zero means no limit.
+	Timeout time.Duration
+	// MaxOutputBytes is the number of bytes captured of stdout and stderr each. The rest is discarded. Zero means
+	// DefaultMaxOutputBytes.
+	MaxOutputBytes int64
+	// Dir is the working directory of the command. If empty, the working directory of the plugin is used.
+	Dir string
+	// Env holds the environment variables of the command in the form key=value. If nil, the environment of the plugin
+	// is used.
+	Env []string
+	// Stdin is read as the standard input of the command. If nil, the command reads from the null device.
+	Stdin io.Reader
+	// OutputSignalID is the ID of the signal each output line is emitted as while the command runs. The step must
+	// declare it as a signal emitter with NewOutputSignalSchema. If empty, no signals are emitted.
+	OutputSignalID string
+}
+
+// Result holds the outcome of a command.
+type Result struct {
+	// ExitCode is the exit code of the command, or -1 if it was killed by a signal.
+	ExitCode int64 `json:"exit_code"`
+	// Stdout holds the captured standard output.
+	Stdout string `json:"stdout"`
+	// StdoutTruncated indicates that the standard output exceeded the size limit and was cut off.
+	StdoutTruncated bool `json:"stdout_truncated"`
+	// Stderr holds the captured standard error.
+	Stderr string `json:"stderr"`
+	// StderrTruncated indicates that the standard error exceeded the size limit and was cut off.
+	StderrTruncated bool `json:"stderr_truncated"`
+	// Duration is the time the command ran, measured with the clock of the context.
+	Duration time.Duration `json:"duration"`
+	// TimedOut indicates that the command was killed because it exceeded the timeout.
+	TimedOut bool `json:"timed_out"`
+}
+
+// Run runs the command and waits for it to finish. The command is killed if the context is cancelled or the timeout
+// expires. A non-zero exit code or a timeout is not an error, the result describes it. Run only returns an error if
+// the command cannot be started or is cancelled through the context.
+func Run(ctx context.Context, options Options, name string, args ...string) (*Result, error) {
+	runCtx := ctx
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	maxOutputBytes := options.MaxOutputBytes
+	if maxOutputBytes <= 0 {
+		maxOutputBytes = DefaultMaxOutputBytes
+	}
+	stdout := newOutputWriter(ctx, StreamStdout, maxOutputBytes, options.OutputSignalID)
+	stderr := newOutputWriter(ctx, StreamStderr, maxOutputBytes, options.OutputSignalID)
+
+	cmd := exec.CommandContext(runCtx, name, args...)
+	cmd.Dir = options.Dir
+	cmd.Env = options.Env
+	cmd.Stdin = options.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Child processes of the command may keep the output open after it was killed.
+	cmd.WaitDelay = time.Second
+
+	clk := clock.FromContext(ctx)
+	start := clk.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s (%w)", name, err)
+	}
+	err := cmd.Wait()
+	duration := clock.Since(clk, start)
+	stdout.flush()
+	stderr.flush()
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !errors.Is(err, exec.ErrWaitDelay) {
+		return nil, fmt.Errorf("failed to run %s (%w)", name, err)
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%s was cancelled (%w)", name, ctx.Err())
+	}
+	return &Result{
+		ExitCode:        int64(cmd.ProcessState.ExitCode()),
+		Stdout:          stdout.captured.String(),
+		StdoutTruncated: stdout.truncated,
+		Stderr:          stderr.captured.String(),
+		StderrTruncated: stderr.truncated,
+		Duration:        duration,
+		TimedOut:        errors.Is(runCtx.Err(), context.DeadlineExceeded),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package subprocess_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/subprocess"
+)
+
+func TestRun(t *testing.T) {
+	result := assert.NoErrorR[*subprocess.Result](t)(subprocess.Run(
+		context.Background(),
+		subprocess.Options{Stdin: strings.NewReader("input")},
+		"sh", "-c", "cat; echo; echo error >&2; exit 3",
+	))
+	assert.Equals(t, result.ExitCode, int64(3))
+	assert.Equals(t, result.Stdout, "input\n")
+	assert.Equals(t, result.Stderr, "error\n")
+	assert.Equals(t, result.StdoutTruncated, false)
+	assert.Equals(t, result.TimedOut, false)
+
+	serialized := assert.NoErrorR[any](t)(subprocess.NewResultSchema().Serialize(result))
+	assert.Equals(t, serialized.(map[string]any)["exit_code"], any(int64(3)))
+}
+
+func TestRunTruncated(t *testing.T) {
+	result := assert.NoErrorR[*subprocess.Result](t)(subprocess.Run(
+		context.Background(),
+		subprocess.Options{MaxOutputBytes: 4},
+		"sh", "-c", "echo 0123456789",
+	))
+	assert.Equals(t, result.ExitCode, int64(0))
+	assert.Equals(t, result.Stdout, "0123")
+	assert.Equals(t, result.StdoutTruncated, true)
+}
+
+func TestRunTimeout(t *testing.T) {
+	result := assert.NoErrorR[*subprocess.Result](t)(subprocess.Run(
+		context.Background(),
+		subprocess.Options{Timeout: 100 * time.Millisecond},
+		"sleep", "10",
+	))
+	assert.Equals(t, result.TimedOut, true)
+	assert.Equals(t, result.ExitCode, int64(-1))
+	assert.Equals(t, result.Duration < 5*time.Second, true)
+}
+
+func TestRunErrors(t *testing.T) {
+	_, err := subprocess.Run(context.Background(), subprocess.Options{}, "/nonexistent/command")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = subprocess.Run(ctx, subprocess.Options{}, "sleep", "10")
+	assert.Error(t, err)
+}
+
+func TestRunOutputSignals(t *testing.T) {
+	signal := subprocess.NewOutputSignalSchema("output")
+	lock := &sync.Mutex{}
+	var lines []any
+	ctx := schema.ContextWithSignalEmitter(context.Background(), func(signalID string, data any) error {
+		assert.Equals(t, signalID, "output")
+		serialized, err := signal.DataSchema().Serialize(data)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		lines = append(lines, serialized)
+		return nil
+	})
+	assert.NoErrorR[*subprocess.Result](t)(subprocess.Run(
+		ctx,
+		subprocess.Options{OutputSignalID: "output"},
+		"sh", "-c", "echo first; echo second; printf last",
+	))
+	assert.Equals(t, lines, []any{
+		map[string]any{"stream": "stdout", "line": "first"},
+		map[string]any{"stream": "stdout", "line": "second"},
+		map[string]any{"stream": "stdout", "line": "last"},
+	})
+}
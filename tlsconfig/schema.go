@@ -0,0 +1,135 @@
+package tlsconfig
+
+import (
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// NewSchema creates the object schema of Config. It unserializes to a *Config. The client certificate and key must
+// both be given inline or both as files.
+func NewSchema() *schema.ObjectSchema {
+	return schema.NewStructMappedObjectSchema[*Config](
+		"TLSConfig",
+		map[string]*schema.PropertySchema{
+			"ca_cert": pemProperty(
+				"CA certificate",
+				"PEM-encoded CA certificates the server certificates are verified with instead of the system CA "+
+					"certificates.",
+				nil,
+				[]string{"ca_cert_file"},
+			),
+			"ca_cert_file": fileProperty(
+				"CA certificate file",
+				"Path of a file holding the PEM-encoded CA certificates.",
+				nil,
+				[]string{"ca_cert"},
+			),
+			"client_cert": pemProperty(
+				"Client certificate",
+				"PEM-encoded certificate the client authenticates with.",
+				[]string{"client_key"},
+				[]string{"client_cert_file"},
+			),
+			"client_cert_file": fileProperty(
+				"Client certificate file",
+				"Path of a file holding the PEM-encoded client certificate.",
+				[]string{"client_key_file"},
+				[]string{"client_cert"},
+			),
+			"client_key": pemProperty(
+				"Client key",
+				"PEM-encoded private key of the client certificate.",
+				[]string{"client_cert"},
+				[]string{"client_key_file"},
+			).MarkSensitive(),
+			"client_key_file": fileProperty(
+				"Client key file",
+				"Path of a file holding the PEM-encoded private key of the client certificate.",
+				[]string{"client_cert_file"},
+				[]string{"client_key"},
+			),
+			"server_name":          serverNameProperty(),
+			"insecure_skip_verify": insecureSkipVerifyProperty(),
+		},
+	)
+}
+
+// NewProperty creates an optional input property holding the TLS configuration. Map it to a *Config field of the
+// input struct.
+func NewProperty() *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		NewSchema(),
+		schema.NewDisplayValue(
+			schema.PointerTo("TLS"),
+			schema.PointerTo("TLS settings. If not set, the system CA certificates are used."),
+			nil,
+		),
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func pemProperty(name string, description string, requiredIf []string, conflicts []string) *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+		schema.NewDisplayValue(&name, &description, nil),
+		false,
+		requiredIf,
+		nil,
+		conflicts,
+		nil,
+		nil,
+	)
+}
+
+func fileProperty(name string, description string, requiredIf []string, conflicts []string) *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+		schema.NewDisplayValue(&name, &description, nil),
+		false,
+		requiredIf,
+		nil,
+		conflicts,
+		nil,
+		nil,
+	)
+}
+
+func serverNameProperty() *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewStringSchema(schema.IntPointer(1), nil, nil),
+		schema.NewDisplayValue(
+			schema.PointerTo("Server name"),
+			schema.PointerTo("Host name the server certificate is verified against, if it differs from "+
+				"the host connected to."),
+			nil,
+		),
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
+		[]string{"\"api.example.com\""},
+	)
+}
+
+func insecureSkipVerifyProperty() *schema.PropertySchema {
+	return schema.NewPropertySchema(
+		schema.NewBoolSchema(),
+		schema.NewDisplayValue(
+			schema.PointerTo("Insecure skip verify"),
+			schema.PointerTo("Disables the verification of the server certificates. Warning: this "+
+				"makes the connections vulnerable to interception and should only be used for testing."),
+			nil,
+		),
+		false,
+		nil,
+		nil,
+		nil,
+		schema.PointerTo("false"),
+		nil,
+	)
+}
@@ -0,0 +1,100 @@
+// Package tlsconfig provides a TLS client configuration described by a schema, so networking plugins accept the same
+// TLS settings in their step input and handle them the same way. Certificates and keys can be given inline or as the
+// path of a file.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.flow.arcalot.io/pluginsdk/schema"
+)
+
+// Config configures TLS client connections. The zero value verifies the server certificates with the system CA
+// certificates.
+type Config struct {
+	// CACert holds the PEM-encoded CA certificates the server certificates are verified with instead of the system
+	// CA certificates.
+	CACert *string `json:"ca_cert"`
+	// CACertFile is the path of a file holding the CA certificates, as an alternative to CACert.
+	CACertFile *string `json:"ca_cert_file"`
+	// ClientCert holds the PEM-encoded certificate the client authenticates with.
+	ClientCert *string `json:"client_cert"`
+	// ClientCertFile is the path of a file holding the client certificate, as an alternative to ClientCert.
+	ClientCertFile *string `json:"client_cert_file"`
+	// ClientKey holds the PEM-encoded private key of the client certificate.
+	ClientKey *string `json:"client_key"`
+	// ClientKeyFile is the path of a file holding the private key, as an alternative to ClientKey.
+	ClientKeyFile *string `json:"client_key_file"`
+	// ServerName overrides the host name the server certificate is verified against.
+	ServerName *string `json:"server_name"`
+	// InsecureSkipVerify disables the verification of the server certificates. Build writes a warning to the debug
+	// log of the step if it is set.
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+}
+
+// Build creates the TLS configuration, reading the files it refers to.
+func (c Config) Build(ctx context.Context) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // Explicitly requested by the configuration.
+	}
+	if c.InsecureSkipVerify {
+		schema.DebugLogf(ctx, "Warning: TLS server certificate verification is disabled, connections are insecure.")
+	}
+	if c.ServerName != nil {
+		tlsConfig.ServerName = *c.ServerName
+	}
+	caCert, err := inlineOrFile("CA certificate", c.CACert, c.CACertFile)
+	if err != nil {
+		return nil, err
+	}
+	if caCert != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("the CA certificate does not contain any PEM-encoded certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	clientCert, err := inlineOrFile("client certificate", c.ClientCert, c.ClientCertFile)
+	if err != nil {
+		return nil, err
+	}
+	clientKey, err := inlineOrFile("client key", c.ClientKey, c.ClientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if (clientCert == nil) != (clientKey == nil) {
+		return nil, errors.New("the client certificate and key must be set together")
+	}
+	if clientCert != nil {
+		certificate, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate (%w)", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// inlineOrFile returns the inline value or the contents of the file, or nil if neither is set.
+func inlineOrFile(name string, inline *string, file *string) ([]byte, error) {
+	switch {
+	case inline != nil && file != nil:
+		return nil, fmt.Errorf("the %s must be given either inline or as a file, not both", name)
+	case inline != nil:
+		return []byte(*inline), nil
+	case file != nil:
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s file %s (%w)", name, *file, err)
+		}
+		return data, nil
+	default:
+		return nil, nil
+	}
+}
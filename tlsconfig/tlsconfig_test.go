@@ -0,0 +1,122 @@
+package tlsconfig_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.flow.arcalot.io/pluginsdk/schema"
+	"go.flow.arcalot.io/pluginsdk/tlsconfig"
+)
+
+// newTestCertificate returns a self-signed PEM-encoded certificate and its private key.
+func newTestCertificate(t *testing.T) (string, string) {
+	key := assert.NoErrorR[*ecdsa.PrivateKey](t)(ecdsa.GenerateKey(elliptic.P256(), rand.Reader))
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der := assert.NoErrorR[[]byte](t)(x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key))
+	keyDER := assert.NoErrorR[[]byte](t)(x509.MarshalECPrivateKey(key))
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestBuildInline(t *testing.T) {
+	cert, key := newTestCertificate(t)
+	tlsConfig := assert.NoErrorR[*tls.Config](t)(tlsconfig.Config{
+		CACert:     &cert,
+		ClientCert: &cert,
+		ClientKey:  &key,
+		ServerName: schema.PointerTo("api.example.com"),
+	}.Build(context.Background()))
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Equals(t, len(tlsConfig.Certificates), 1)
+	assert.Equals(t, tlsConfig.ServerName, "api.example.com")
+	assert.Equals(t, tlsConfig.InsecureSkipVerify, false)
+	assert.Equals(t, tlsConfig.MinVersion, uint16(tls.VersionTLS12))
+}
+
+func TestBuildFiles(t *testing.T) {
+	cert, key := newTestCertificate(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.NoError(t, os.WriteFile(certFile, []byte(cert), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, []byte(key), 0o600))
+
+	tlsConfig := assert.NoErrorR[*tls.Config](t)(tlsconfig.Config{
+		CACertFile:     &certFile,
+		ClientCertFile: &certFile,
+		ClientKeyFile:  &keyFile,
+	}.Build(context.Background()))
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Equals(t, len(tlsConfig.Certificates), 1)
+
+	_, err := tlsconfig.Config{CACertFile: schema.PointerTo(filepath.Join(dir, "missing.pem"))}.
+		Build(context.Background())
+	assert.Error(t, err)
+}
+
+func TestBuildInvalid(t *testing.T) {
+	cert, key := newTestCertificate(t)
+	for name, config := range map[string]tlsconfig.Config{
+		"invalid-ca":      {CACert: schema.PointerTo("not a certificate")},
+		"inline-and-file": {CACert: &cert, CACertFile: schema.PointerTo("ca.pem")},
+		"cert-only":       {ClientCert: &cert},
+		"key-only":        {ClientKey: &key},
+		"mismatched":      {ClientCert: &cert, ClientKey: schema.PointerTo("not a key")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.Build(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuildInsecureWarning(t *testing.T) {
+	log := &bytes.Buffer{}
+	ctx := schema.ContextWithDebugLog(context.Background(), log)
+	tlsConfig := assert.NoErrorR[*tls.Config](t)(tlsconfig.Config{InsecureSkipVerify: true}.Build(ctx))
+	assert.Equals(t, tlsConfig.InsecureSkipVerify, true)
+	assert.Contains(t, log.String(), "verification is disabled")
+}
+
+func TestSchema(t *testing.T) {
+	s := tlsconfig.NewSchema()
+	config := assert.NoErrorR[any](t)(s.Unserialize(map[string]any{
+		"ca_cert_file": "/etc/ssl/certs/ca.pem",
+	}))
+	assert.Equals(t, *config.(*tlsconfig.Config).CACertFile, "/etc/ssl/certs/ca.pem")
+
+	for name, data := range map[string]map[string]any{
+		"inline-and-file":  {"ca_cert": "cert", "ca_cert_file": "/ca.pem"},
+		"cert-without-key": {"client_cert_file": "/cert.pem"},
+		"mixed-forms":      {"client_cert_file": "/cert.pem", "client_key": "key"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Unserialize(data)
+			assert.Error(t, err)
+		})
+	}
+	assert.NoErrorR[any](t)(s.Unserialize(map[string]any{
+		"client_cert_file": "/cert.pem",
+		"client_key_file":  "/key.pem",
+	}))
+}